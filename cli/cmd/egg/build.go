@@ -3,9 +3,9 @@
 // Overview:
 //   - Responsibility: Build foundation images, backend services, and frontend applications
 //   - Key Types: Build commands for foundation, backend, frontend, and all
-//   - Concurrency Model: Sequential builds with parallel image builds (future)
+//   - Concurrency Model: Sequential builds; build all runs up to --parallel builds at once
 //   - Error Semantics: Build errors with detailed context
-//   - Performance Notes: Docker build caching, multi-stage builds
+//   - Performance Notes: Docker build caching, multi-stage builds; unchanged backends are skipped
 //
 // Usage:
 //
@@ -16,15 +16,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/buildcache"
 	"go.eggybyte.com/egg/cli/internal/ui"
 	"gopkg.in/yaml.v3"
 )
@@ -40,10 +44,13 @@ const (
 )
 
 var (
-	buildPush     bool // For individual backend/frontend commands
-	buildLocal    bool // For build all command
-	buildPlatform string
-	buildTag      string
+	buildPush             bool // For individual backend/frontend commands
+	buildLocal            bool // For build all command
+	buildPlatform         string
+	buildTag              string
+	buildParallel         int  // Concurrent builds for build all
+	buildParallelFrontend bool // Build frontends concurrently too
+	buildForce            bool // Rebuild backends even if unchanged
 )
 
 // buildCmd represents the build command.
@@ -88,16 +95,25 @@ Build Process:
 
 All compilation is done inside Docker containers for consistency.
 
+Incremental builds:
+  A content hash of the service directory, backend/go.work, gen/go,
+  the Dockerfile and the build settings is recorded in
+  .egg/build-cache.json after each successful build. The build is
+  skipped when the hash is unchanged and the image still exists
+  (in the registry when pushing, locally otherwise).
+
 Flags:
   --push: Push image to registry after building
   --platform: Target platforms (default: linux/amd64,linux/arm64)
   --tag: Custom tag (overrides egg.yaml version)
+  --force: Rebuild even if nothing changed
 
 Example:
   egg build backend user              # Build specific service
   egg build backend                   # Build all backend services
   egg build backend order --push --tag v1.0.0
-  egg build backend user --platform linux/amd64`,
+  egg build backend user --platform linux/amd64
+  egg build backend user --force`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runBuildBackend,
 }
@@ -133,7 +149,8 @@ var buildAllCmd = &cobra.Command{
 	Long: `Build all backend and frontend services in the project.
 
 This command discovers all services in backend/ and frontend/ directories
-and builds each one sequentially.
+and builds each one, sequentially unless --parallel is given. A failed
+build does not stop the others; all failures are reported at the end.
 
 Default behavior:
   - Multi-platform build (linux/amd64,linux/arm64) with push enabled
@@ -142,11 +159,20 @@ Default behavior:
 Flags:
   --local: Build for local platform only (no push)
   --platform: Target platform (default: linux/amd64,linux/arm64)
+  --parallel: Build up to N backend services concurrently (default: 1)
+  --parallel-frontend: Build frontend services concurrently too
+  --force: Rebuild backend services even if nothing changed
+
+Unchanged backend services are skipped (see 'egg build backend --help').
+With --parallel, each output line is prefixed with its service name.
+Frontend builds stay serial by default since Flutter builds contend on
+the shared SDK.
 
 Example:
   egg build all                    # Multi-platform build and push (default)
   egg build all --local            # Build for local platform only
-  egg build all --platform linux/amd64`,
+  egg build all --platform linux/amd64
+  egg build all --local --parallel 4`,
 	RunE: runBuildAll,
 }
 
@@ -161,6 +187,7 @@ func init() {
 	buildBackendCmd.Flags().BoolVar(&buildLocal, "local", false, "Build for local platform only (no push)")
 	buildBackendCmd.Flags().StringVar(&buildPlatform, "platform", "linux/amd64,linux/arm64", "Target platforms (comma-separated)")
 	buildBackendCmd.Flags().StringVar(&buildTag, "tag", "", "Custom tag (overrides egg.yaml version)")
+	buildBackendCmd.Flags().BoolVar(&buildForce, "force", false, "Rebuild even if nothing changed")
 
 	// Frontend flags
 	buildFrontendCmd.Flags().BoolVar(&buildPush, "push", false, "Push image to registry")
@@ -170,6 +197,9 @@ func init() {
 	// All flags
 	buildAllCmd.Flags().BoolVar(&buildLocal, "local", false, "Build for local platform only (no push)")
 	buildAllCmd.Flags().StringVar(&buildPlatform, "platform", "linux/amd64,linux/arm64", "Target platforms for backend (comma-separated)")
+	buildAllCmd.Flags().IntVar(&buildParallel, "parallel", 1, "Maximum number of services built concurrently")
+	buildAllCmd.Flags().BoolVar(&buildParallelFrontend, "parallel-frontend", false, "Build frontend services concurrently too (default: serial)")
+	buildAllCmd.Flags().BoolVar(&buildForce, "force", false, "Rebuild backend services even if nothing changed")
 }
 
 // Note: runBuildFoundation has been removed.
@@ -209,8 +239,10 @@ func runBuildBackend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build each service
+	settings := resolveBuildSettings()
+	settings.cache = loadBuildCache()
 	for _, serviceName := range servicesToBuild {
-		if err := buildBackendService(ctx, serviceName, config); err != nil {
+		if err := buildBackendService(ctx, serviceName, config, settings, stdOutput); err != nil {
 			return fmt.Errorf("failed to build backend service %s: %w", serviceName, err)
 		}
 	}
@@ -220,8 +252,8 @@ func runBuildBackend(cmd *cobra.Command, args []string) error {
 }
 
 // buildBackendService builds a single backend service image.
-func buildBackendService(ctx context.Context, serviceName string, config *ProjectConfig) error {
-	ui.Info("Building backend service: %s", serviceName)
+func buildBackendService(ctx context.Context, serviceName string, config *ProjectConfig, settings buildSettings, out buildOutput) error {
+	out.info("Building backend service: %s", serviceName)
 
 	// Prepare image metadata
 	imageTag := buildTag
@@ -230,26 +262,6 @@ func buildBackendService(ctx context.Context, serviceName string, config *Projec
 	}
 	imageName := fmt.Sprintf("%s/%s-%s:%s", config.DockerRegistry, config.ProjectName, serviceName, imageTag)
 
-	// Determine if multi-platform build
-	isMultiPlatform := strings.Contains(buildPlatform, ",")
-
-	// Handle --local flag
-	if buildLocal {
-		localPlatform := detectLocalPlatform()
-		buildPlatform = localPlatform
-		isMultiPlatform = false
-		buildPush = false
-		ui.Info("--local specified: building for local platform (%s) only", localPlatform)
-	}
-
-	// Multi-platform builds MUST use --push (buildx limitation)
-	if isMultiPlatform && !buildPush {
-		ui.Warning("Multi-platform builds require --push flag (buildx limitation)")
-		ui.Info("Switching to single platform: linux/amd64")
-		buildPlatform = "linux/amd64"
-		isMultiPlatform = false
-	}
-
 	// Prepare build arguments
 	buildArgs := []string{
 		fmt.Sprintf("SERVICE_NAME=%s", serviceName),
@@ -264,34 +276,120 @@ func buildBackendService(ctx context.Context, serviceName string, config *Projec
 	// Note: This would require loading full config schema, for now use defaults
 	// buildArgs = append(buildArgs, fmt.Sprintf("HTTP_PORT=%d", httpPort))
 
-	if isMultiPlatform {
-		// Multi-platform build with push
-		ui.Info("Building multi-platform Docker image for %s...", buildPlatform)
-		if err := buildMultiPlatformImage(ctx, "docker/Dockerfile.backend", imageName, buildPlatform, buildArgs); err != nil {
-			return fmt.Errorf("failed to build and push multi-platform image: %w", err)
-		}
-		ui.Success("Multi-platform image built and pushed: %s (platforms: %s)", imageName, buildPlatform)
-	} else {
-		// Single platform build
-		ui.Info("Building Docker image for %s...", buildPlatform)
-		if err := buildDockerImageWithArgs(ctx, "docker/Dockerfile.backend", imageName, ".", buildPlatform, buildArgs); err != nil {
-			return fmt.Errorf("failed to build Docker image: %w", err)
-		}
-		ui.Success("Image built: %s (platform: %s)", imageName, buildPlatform)
+	dockerfile := "docker/Dockerfile.backend"
+	cacheKey := "backend/" + serviceName
 
-		// Push if requested (single platform)
-		if buildPush {
-			ui.Info("Pushing image to registry...")
-			if err := pushDockerImage(ctx, imageName); err != nil {
-				return fmt.Errorf("failed to push image: %w", err)
-			}
-			ui.Success("Image pushed successfully")
+	// Skip the build if the inputs are unchanged and the image still exists
+	var hash string
+	if settings.cache != nil {
+		var err error
+		hash, err = backendBuildHash(serviceName, dockerfile, imageName, buildArgs, settings)
+		if err != nil {
+			out.debug("Build cache disabled for %s: %v", serviceName, err)
+		} else if !buildForce && backendUpToDate(ctx, settings, cacheKey, hash, imageName) {
+			out.success("Up to date, skipping build: %s (use --force to rebuild)", imageName)
+			return nil
 		}
 	}
 
+	if err := buildServiceImage(ctx, dockerfile, imageName, buildArgs, settings, out); err != nil {
+		return err
+	}
+
+	if hash != "" {
+		settings.cache.Put(cacheKey, buildcache.Entry{
+			Hash:     hash,
+			Image:    imageName,
+			Platform: settings.platform,
+			Pushed:   settings.push,
+		})
+		if err := settings.cache.Save(); err != nil {
+			out.debug("Failed to save build cache: %v", err)
+		}
+	}
 	return nil
 }
 
+// backendBuildHash hashes the inputs of a backend image build: the service
+// directory, the backend workspace files, generated code, the Dockerfile
+// and the image settings.
+//
+// Parameters:
+//   - serviceName: Backend service name
+//   - dockerfile: Dockerfile path
+//   - imageName: Full image reference
+//   - buildArgs: Docker build arguments
+//   - settings: Resolved build settings
+//
+// Returns:
+//   - string: Content hash
+//   - error: Error if an input cannot be read
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Reads every input file once
+func backendBuildHash(serviceName, dockerfile, imageName string, buildArgs []string, settings buildSettings) (string, error) {
+	paths := []string{
+		filepath.Join("backend", serviceName),
+		filepath.Join("backend", "go.work"),
+		filepath.Join("backend", "go.work.sum"),
+		filepath.Join("gen", "go"),
+		dockerfile,
+	}
+	extra := append([]string{
+		"image=" + imageName,
+		"platform=" + settings.platform,
+		fmt.Sprintf("push=%t", settings.push),
+	}, buildArgs...)
+	return buildcache.Hash(paths, extra...)
+}
+
+// backendUpToDate reports whether the cached build of key matches hash and
+// its image still exists: in the registry when pushing, locally otherwise.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - settings: Resolved build settings
+//   - key: Cache key
+//   - hash: Current content hash
+//   - imageName: Full image reference
+//
+// Returns:
+//   - bool: True if the build can be skipped
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - One docker call (a registry round trip when pushing)
+func backendUpToDate(ctx context.Context, settings buildSettings, key, hash, imageName string) bool {
+	entry, ok := settings.cache.Get(key)
+	if !ok || entry.Hash != hash {
+		return false
+	}
+	if settings.push {
+		return remoteImageExists(ctx, imageName)
+	}
+	return exec.CommandContext(ctx, "docker", "image", "inspect", imageName).Run() == nil
+}
+
+// remoteImageExists reports whether a tag exists in its registry.
+func remoteImageExists(ctx context.Context, imageName string) bool {
+	return exec.CommandContext(ctx, "docker", "buildx", "imagetools", "inspect", imageName).Run() == nil
+}
+
+// loadBuildCache loads the project build cache. An unreadable cache is
+// reported and replaced by an empty one.
+func loadBuildCache() *buildcache.Cache {
+	cache, err := buildcache.Load(buildcache.DefaultPath)
+	if err != nil {
+		ui.Warning("Ignoring build cache: %v", err)
+	}
+	return cache
+}
+
 // runBuildFrontend builds frontend service image(s).
 func runBuildFrontend(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -326,8 +424,9 @@ func runBuildFrontend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Build each service
+	settings := resolveBuildSettings()
 	for _, serviceName := range servicesToBuild {
-		if err := buildFrontendService(ctx, serviceName, config); err != nil {
+		if err := buildFrontendService(ctx, serviceName, config, settings, stdOutput); err != nil {
 			return fmt.Errorf("failed to build frontend service %s: %w", serviceName, err)
 		}
 	}
@@ -337,18 +436,18 @@ func runBuildFrontend(cmd *cobra.Command, args []string) error {
 }
 
 // buildFrontendService builds a single frontend service image.
-func buildFrontendService(ctx context.Context, serviceName string, config *ProjectConfig) error {
-	ui.Info("Building frontend service: %s", serviceName)
+func buildFrontendService(ctx context.Context, serviceName string, config *ProjectConfig, settings buildSettings, out buildOutput) error {
+	out.info("Building frontend service: %s", serviceName)
 
 	// Step 1: Build Flutter web assets
-	ui.Info("Building Flutter web assets...")
+	out.info("Building Flutter web assets...")
 	serviceDir := filepath.Join("frontend", serviceName)
 	flutterBuildDir := filepath.Join(serviceDir, "build", "web")
 
-	if err := buildFlutterWeb(ctx, serviceName, serviceDir); err != nil {
+	if err := buildFlutterWeb(ctx, serviceName, serviceDir, out); err != nil {
 		return fmt.Errorf("failed to build Flutter web: %w", err)
 	}
-	ui.Success("Flutter web built: %s", flutterBuildDir)
+	out.success("Flutter web built: %s", flutterBuildDir)
 
 	// Step 2: Build Docker image with multi-platform support
 	imageTag := buildTag
@@ -368,48 +467,36 @@ func buildFrontendService(ctx context.Context, serviceName string, config *Proje
 	}
 
 	// Frontend images are platform-agnostic (static files), but we support multi-platform for consistency
-	isMultiPlatform := strings.Contains(buildPlatform, ",")
-
-	// Handle --local flag
-	if buildLocal {
-		localPlatform := detectLocalPlatform()
-		buildPlatform = localPlatform
-		isMultiPlatform = false
-		buildPush = false
-		ui.Info("--local specified: building for local platform (%s) only", localPlatform)
-	}
-
-	// Multi-platform builds MUST use --push (buildx limitation)
-	if isMultiPlatform && !buildPush {
-		ui.Warning("Multi-platform builds require --push flag (buildx limitation)")
-		ui.Info("Building for single platform instead: linux/amd64")
-		buildPlatform = "linux/amd64"
-		isMultiPlatform = false
-	}
+	return buildServiceImage(ctx, "docker/Dockerfile.frontend", imageName, buildArgs, settings, out)
+}
 
-	if isMultiPlatform {
+// buildServiceImage builds a service image for the resolved platforms and
+// pushes it when requested. Multi-platform images are pushed by buildx.
+func buildServiceImage(ctx context.Context, dockerfile, imageName string, buildArgs []string, settings buildSettings, out buildOutput) error {
+	if settings.multiPlatform() {
 		// Multi-platform build with push
-		ui.Info("Building multi-platform Docker image for %s...", buildPlatform)
-		if err := buildMultiPlatformImage(ctx, "docker/Dockerfile.frontend", imageName, buildPlatform, buildArgs); err != nil {
+		out.info("Building multi-platform Docker image for %s...", settings.platform)
+		if err := buildMultiPlatformImage(ctx, dockerfile, imageName, settings.platform, buildArgs, out); err != nil {
 			return fmt.Errorf("failed to build and push multi-platform image: %w", err)
 		}
-		ui.Success("Multi-platform image built and pushed: %s (platforms: %s)", imageName, buildPlatform)
-	} else {
-		// Single platform build
-		ui.Info("Building Docker image for %s...", buildPlatform)
-		if err := buildDockerImageWithArgs(ctx, "docker/Dockerfile.frontend", imageName, ".", buildPlatform, buildArgs); err != nil {
-			return fmt.Errorf("failed to build Docker image: %w", err)
-		}
-		ui.Success("Image built: %s", imageName)
+		out.success("Multi-platform image built and pushed: %s (platforms: %s)", imageName, settings.platform)
+		return nil
+	}
 
-		// Push if requested (single platform)
-		if buildPush {
-			ui.Info("Pushing image to registry...")
-			if err := pushDockerImage(ctx, imageName); err != nil {
-				return fmt.Errorf("failed to push image: %w", err)
-			}
-			ui.Success("Image pushed successfully")
+	// Single platform build
+	out.info("Building Docker image for %s...", settings.platform)
+	if err := buildDockerImageWithArgs(ctx, dockerfile, imageName, ".", settings.platform, buildArgs, out); err != nil {
+		return fmt.Errorf("failed to build Docker image: %w", err)
+	}
+	out.success("Image built: %s (platform: %s)", imageName, settings.platform)
+
+	// Push if requested (single platform)
+	if settings.push {
+		out.info("Pushing image to registry...")
+		if err := pushDockerImage(ctx, imageName, out); err != nil {
+			return fmt.Errorf("failed to push image: %w", err)
 		}
+		out.success("Image pushed successfully")
 	}
 
 	return nil
@@ -420,6 +507,10 @@ func runBuildAll(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	ui.Info("Building all services...")
 
+	if buildParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1, got %d", buildParallel)
+	}
+
 	// Load project configuration
 	config, err := loadProjectConfig()
 	if err != nil {
@@ -427,23 +518,16 @@ func runBuildAll(cmd *cobra.Command, args []string) error {
 	}
 
 	// Handle --local flag: default is push for multi-platform, --local disables push
-	isMultiPlatform := strings.Contains(buildPlatform, ",")
-	var shouldPush bool
-
+	settings := buildSettings{platform: buildPlatform}
 	if buildLocal {
 		// Detect local platform and use single platform
-		localPlatform := detectLocalPlatform()
-		buildPlatform = localPlatform
-		isMultiPlatform = false
-		shouldPush = false
-		ui.Info("--local specified: building for local platform (%s) only", localPlatform)
-	} else if isMultiPlatform {
+		settings.platform = detectLocalPlatform()
+		ui.Info("--local specified: building for local platform (%s) only", settings.platform)
+	} else if settings.multiPlatform() {
 		ui.Info("Multi-platform build: images will be pushed to registry")
-		shouldPush = true
-	} else {
-		// Single platform, no push by default
-		shouldPush = false
+		settings.push = true
 	}
+	settings.cache = loadBuildCache()
 
 	// Discover backend services
 	backendServices, err := discoverServices("backend")
@@ -458,38 +542,242 @@ func runBuildAll(cmd *cobra.Command, args []string) error {
 	}
 
 	ui.Info("Found %d backend services, %d frontend services", len(backendServices), len(frontendServices))
-
-	// Store original push setting for individual commands
-	originalPush := buildPush
-	originalPlatform := buildPlatform
+	if buildParallel > 1 {
+		ui.Info("Building up to %d services in parallel", buildParallel)
+	}
 
 	// Build backend services
-	for _, service := range backendServices {
-		ui.Info("Building backend service: %s", service)
-		// Set push and platform for this build
-		buildPush = shouldPush
-		buildPlatform = originalPlatform
-		if err := buildBackendService(ctx, service, config); err != nil {
-			return fmt.Errorf("failed to build backend service %s: %w", service, err)
+	failures := runServiceBuilds(ctx, "backend", backendServices, buildParallel,
+		func(ctx context.Context, service string, out buildOutput) error {
+			return buildBackendService(ctx, service, config, settings, out)
+		})
+
+	// Build frontend services (serial unless --parallel-frontend, since
+	// Flutter builds contend on the shared SDK)
+	frontendParallel := 1
+	if buildParallelFrontend {
+		frontendParallel = buildParallel
+	}
+	failures = append(failures, runServiceBuilds(ctx, "frontend", frontendServices, frontendParallel,
+		func(ctx context.Context, service string, out buildOutput) error {
+			return buildFrontendService(ctx, service, config, settings, out)
+		})...)
+
+	if len(failures) > 0 {
+		ui.Error("Failed builds:")
+		for _, failure := range failures {
+			ui.Error("  %s: %v", failure.service, failure.err)
 		}
+		return fmt.Errorf("%d of %d service builds failed", len(failures), len(backendServices)+len(frontendServices))
 	}
 
-	// Build frontend services
-	for _, service := range frontendServices {
-		ui.Info("Building frontend service: %s", service)
-		buildPush = shouldPush
-		buildPlatform = originalPlatform
-		if err := buildFrontendService(ctx, service, config); err != nil {
-			return fmt.Errorf("failed to build frontend service %s: %w", service, err)
+	ui.Success("All services built successfully")
+	return nil
+}
+
+// buildFailure records a service whose build failed.
+type buildFailure struct {
+	service string // Service path (e.g., backend/user)
+	err     error
+}
+
+// runServiceBuilds builds services with up to parallel builds at a time.
+// A failed build does not stop the others; failures are returned in
+// service order once all builds finished.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - kind: Service kind (backend or frontend)
+//   - services: Service names
+//   - parallel: Maximum concurrent builds (1 builds sequentially)
+//   - build: Builds one service, writing its output to out
+//
+// Returns:
+//   - []buildFailure: Failed builds
+//
+// Concurrency:
+//   - Runs up to parallel builds concurrently; with more than one, each
+//     service's output is line-prefixed with its name
+//
+// Performance:
+//   - Wall time bounded by the slowest builds per worker
+func runServiceBuilds(ctx context.Context, kind string, services []string, parallel int, build func(ctx context.Context, service string, out buildOutput) error) []buildFailure {
+	errs := make([]error, len(services))
+
+	if parallel <= 1 || len(services) <= 1 {
+		for i, service := range services {
+			errs[i] = build(ctx, service, stdOutput)
+			if errs[i] != nil {
+				ui.Error("Failed to build %s service %s: %v", kind, service, errs[i])
+			}
+		}
+	} else {
+		width := 0
+		for _, service := range services {
+			width = max(width, len(service))
 		}
+
+		var (
+			outputMu sync.Mutex
+			wg       sync.WaitGroup
+		)
+		sem := make(chan struct{}, parallel)
+		for i, service := range services {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				out := newPrefixedOutput(&outputMu, fmt.Sprintf("[%-*s] ", width, service))
+				errs[i] = build(ctx, service, out)
+				out.flush()
+				if errs[i] != nil {
+					out.error("Build failed: %v", errs[i])
+				} else {
+					out.success("Build finished")
+				}
+			}()
+		}
+		wg.Wait()
 	}
 
-	// Restore original settings
-	buildPush = originalPush
-	buildPlatform = originalPlatform
+	var failures []buildFailure
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, buildFailure{service: kind + "/" + services[i], err: err})
+		}
+	}
+	return failures
+}
 
-	ui.Success("All services built successfully")
-	return nil
+// buildSettings are the resolved platform and push settings of a build.
+type buildSettings struct {
+	platform string            // Target platforms (comma-separated)
+	push     bool              // Push images after building
+	cache    *buildcache.Cache // Backend build cache (nil disables skipping)
+}
+
+// multiPlatform reports whether more than one platform is targeted.
+func (s buildSettings) multiPlatform() bool {
+	return strings.Contains(s.platform, ",")
+}
+
+// resolveBuildSettings applies --local and the buildx requirement that
+// multi-platform builds push to the --platform and --push flags.
+func resolveBuildSettings() buildSettings {
+	settings := buildSettings{platform: buildPlatform, push: buildPush}
+
+	// Handle --local flag
+	if buildLocal {
+		settings.platform = detectLocalPlatform()
+		settings.push = false
+		ui.Info("--local specified: building for local platform (%s) only", settings.platform)
+	}
+
+	// Multi-platform builds MUST use --push (buildx limitation)
+	if settings.multiPlatform() && !settings.push {
+		ui.Warning("Multi-platform builds require --push flag (buildx limitation)")
+		ui.Info("Switching to single platform: linux/amd64")
+		settings.platform = "linux/amd64"
+	}
+
+	return settings
+}
+
+// buildOutput directs the output of a service build. Sequential builds
+// write straight to the terminal; parallel builds get line-prefixed
+// writers so interleaved output stays attributable.
+type buildOutput struct {
+	prefix string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// stdOutput writes build output to the terminal unprefixed.
+var stdOutput = buildOutput{stdout: os.Stdout, stderr: os.Stderr}
+
+// newPrefixedOutput returns an output prefixing every line with prefix.
+// Lines are written while holding mu, shared by all concurrent builds.
+func newPrefixedOutput(mu *sync.Mutex, prefix string) buildOutput {
+	return buildOutput{
+		prefix: prefix,
+		stdout: &prefixWriter{mu: mu, w: os.Stdout, prefix: prefix},
+		stderr: &prefixWriter{mu: mu, w: os.Stderr, prefix: prefix},
+	}
+}
+
+// command creates a command writing to the build output.
+func (o buildOutput) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = o.stdout
+	cmd.Stderr = o.stderr
+	return cmd
+}
+
+// flush writes any unterminated output line.
+func (o buildOutput) flush() {
+	for _, w := range []io.Writer{o.stdout, o.stderr} {
+		if pw, ok := w.(*prefixWriter); ok {
+			pw.Flush()
+		}
+	}
+}
+
+func (o buildOutput) debug(format string, args ...interface{}) {
+	ui.Debug(o.prefix+format, args...)
+}
+
+func (o buildOutput) info(format string, args ...interface{}) {
+	ui.Info(o.prefix+format, args...)
+}
+
+func (o buildOutput) success(format string, args ...interface{}) {
+	ui.Success(o.prefix+format, args...)
+}
+
+func (o buildOutput) error(format string, args ...interface{}) {
+	ui.Error(o.prefix+format, args...)
+}
+
+// prefixWriter prefixes each complete line written to it. A trailing
+// partial line is buffered until the next newline or Flush.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write buffers p and writes out its complete lines.
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := p.writeLine(p.buf[:i+1]); err != nil {
+			return len(b), err
+		}
+		p.buf = p.buf[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes the buffered partial line, if any.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		_ = p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
 }
 
 // Helper functions
@@ -597,7 +885,7 @@ func compileBinaryInContainer(ctx context.Context, serviceName, binaryPath strin
 // Note:
 //   - Flutter build outputs to <serviceDir>/build/web/
 //   - This function does NOT copy files elsewhere - Dockerfile copies directly from build/web
-func buildFlutterWeb(ctx context.Context, serviceName, serviceDir string) error {
+func buildFlutterWeb(ctx context.Context, serviceName, serviceDir string, out buildOutput) error {
 	// Check if flutter is available
 	if _, err := exec.LookPath("flutter"); err != nil {
 		return fmt.Errorf("flutter not found in PATH - please install Flutter SDK")
@@ -609,12 +897,10 @@ func buildFlutterWeb(ctx context.Context, serviceName, serviceDir string) error
 	}
 
 	// Run flutter build web (outputs to build/web by default)
-	ui.Debug("Building Flutter web in %s", serviceDir)
+	out.debug("Building Flutter web in %s", serviceDir)
 
-	cmd := exec.CommandContext(ctx, "flutter", "build", "web", "--release")
+	cmd := out.command(ctx, "flutter", "build", "web", "--release")
 	cmd.Dir = serviceDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("flutter build failed: %w", err)
@@ -674,7 +960,7 @@ func buildDockerImage(ctx context.Context, dockerfile, tag, contextPath, platfor
 // Note:
 //   - For single platform builds, uses --load to load image into local Docker daemon
 //   - Platform parameter ensures correct base image architecture is pulled
-func buildDockerImageWithArgs(ctx context.Context, dockerfile, tag, contextPath, platform string, buildArgs []string) error {
+func buildDockerImageWithArgs(ctx context.Context, dockerfile, tag, contextPath, platform string, buildArgs []string, out buildOutput) error {
 	// Use docker buildx for consistent multi-arch support
 	args := []string{"buildx", "build", "-f", dockerfile, "-t", tag}
 
@@ -699,11 +985,9 @@ func buildDockerImageWithArgs(ctx context.Context, dockerfile, tag, contextPath,
 
 	args = append(args, contextPath)
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := out.command(ctx, "docker", args...)
 
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
+	out.debug("Running: docker %s", strings.Join(args, " "))
 
 	return cmd.Run()
 }
@@ -720,7 +1004,7 @@ func buildDockerImageWithArgs(ctx context.Context, dockerfile, tag, contextPath,
 //
 // Returns:
 //   - error: Build error if any
-func buildMultiPlatformImage(ctx context.Context, dockerfile, tag, platforms string, buildArgs []string) error {
+func buildMultiPlatformImage(ctx context.Context, dockerfile, tag, platforms string, buildArgs []string, out buildOutput) error {
 	// Verify buildx is available
 	if err := exec.CommandContext(ctx, "docker", "buildx", "version").Run(); err != nil {
 		return fmt.Errorf("docker buildx not available - please ensure Docker Buildx is installed")
@@ -743,11 +1027,9 @@ func buildMultiPlatformImage(ctx context.Context, dockerfile, tag, platforms str
 
 	args = append(args, ".") // context
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := out.command(ctx, "docker", args...)
 
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
+	out.debug("Running: docker %s", strings.Join(args, " "))
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("buildx multi-platform build failed: %w", err)
@@ -757,12 +1039,10 @@ func buildMultiPlatformImage(ctx context.Context, dockerfile, tag, platforms str
 }
 
 // pushDockerImage pushes a Docker image to registry.
-func pushDockerImage(ctx context.Context, tag string) error {
-	cmd := exec.CommandContext(ctx, "docker", "push", tag)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+func pushDockerImage(ctx context.Context, tag string, out buildOutput) error {
+	cmd := out.command(ctx, "docker", "push", tag)
 
-	ui.Debug("Running: docker push %s", tag)
+	out.debug("Running: docker push %s", tag)
 
 	return cmd.Run()
 }
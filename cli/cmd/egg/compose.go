@@ -9,16 +9,22 @@
 //
 // Usage:
 //
-//	egg compose up [--detached]
+//	egg compose up [service...] [--profile <name>] [--wait [--wait-timeout <duration>]]
 //	egg compose down
 //	egg compose logs [--service <name>]
+//	egg compose proxy-status
 package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.eggybyte.com/egg/cli/internal/configschema"
@@ -51,9 +57,9 @@ Examples:
 
 // composeUpCmd represents the compose up command.
 var composeUpCmd = &cobra.Command{
-	Use:   "up",
+	Use:   "up [service...]",
 	Short: "Start services",
-	Long: `Start all services with Docker Compose.
+	Long: `Start services with Docker Compose.
 
 This command:
 - Renders compose.yaml from egg configuration to deploy/compose/compose.yaml
@@ -61,8 +67,25 @@ This command:
 - Attaches MySQL database if enabled
 - Sets up service dependencies and network
 
+When services or profiles are given, only those services are rendered and
+started, together with everything they depend on: services listed in
+depends_on, services referenced by ${svc:...} expressions in their
+environment, and MySQL if a selected backend service is started.
+
+With --wait, the command polls the health endpoint of each started backend
+service (inside its container, on the health port from egg.yaml) and
+returns once all of them are healthy, or fails after --wait-timeout.
+
+Flags:
+  --profile: Start the services in a profile (repeatable)
+  --wait: Wait until backend services are healthy
+  --wait-timeout: Maximum time to wait (default: 2m)
+
 Example:
-  egg compose up`,
+  egg compose up
+  egg compose up user
+  egg compose up --profile core
+  egg compose up --wait && egg test user`,
 	RunE: runComposeUp,
 }
 
@@ -156,18 +179,39 @@ var composeProxyStopCmd = &cobra.Command{
 	Long: `Stop all running port proxy containers for the project.
 
 This command:
-- Stops all socat proxy containers
-- Cleans up port mappings
+- Stops all socat proxy containers, recorded or found running in Docker
+- Cleans up port mappings and the proxy state file
 
 Example:
   egg compose proxy-stop`,
 	RunE: runComposeProxyStop,
 }
 
+// composeProxyStatusCmd represents the compose proxy-status command.
+var composeProxyStatusCmd = &cobra.Command{
+	Use:   "proxy-status",
+	Short: "Show port proxies",
+	Long: `Show the port proxies of the project.
+
+Proxies are read from the state file (.egg/proxies.json) written by proxy
+and proxy-all, and reconciled against the running proxy containers:
+- running:   recorded and running
+- stale:     recorded, but the container is gone (removed by proxy-stop)
+- untracked: running, but not recorded (e.g. the CLI crashed)
+
+Example:
+  egg compose proxy-status
+  egg compose proxy-status --json`,
+	RunE: runComposeProxyStatus,
+}
+
 var (
-	serviceFilter string
-	followLogs    bool
-	localPort     int
+	serviceFilter   string
+	followLogs      bool
+	localPort       int
+	composeProfiles []string
+	composeWait     bool
+	composeWaitTime time.Duration
 )
 
 func init() {
@@ -179,7 +223,11 @@ func init() {
 	composeCmd.AddCommand(composeProxyCmd)
 	composeCmd.AddCommand(composeProxyAllCmd)
 	composeCmd.AddCommand(composeProxyStopCmd)
+	composeCmd.AddCommand(composeProxyStatusCmd)
 
+	composeUpCmd.Flags().StringSliceVar(&composeProfiles, "profile", nil, "Start the services in a profile (repeatable)")
+	composeUpCmd.Flags().BoolVar(&composeWait, "wait", false, "Wait until backend services are healthy")
+	composeUpCmd.Flags().DurationVar(&composeWaitTime, "wait-timeout", 2*time.Minute, "Maximum time to wait for services to become healthy")
 	composeLogsCmd.Flags().StringVar(&serviceFilter, "service", "", "Filter logs by service name")
 	composeLogsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Follow log output")
 	composeProxyCmd.Flags().IntVar(&localPort, "local-port", 0, "Local port to map to (0 to auto-find)")
@@ -210,7 +258,7 @@ func getComposeNetworkName(projectName string) string {
 //
 // Parameters:
 //   - cmd: Cobra command
-//   - args: Command arguments
+//   - args: Command arguments (services to start, all if empty)
 //
 // Returns:
 //   - error: Execution error if any
@@ -251,8 +299,18 @@ func runComposeUp(cmd *cobra.Command, args []string) error {
 	// Create Compose renderer
 	composeRenderer := compose.NewRenderer(fs, refParser)
 
+	// Resolve the services to start (nil renders all)
+	var services []string
+	if len(args) > 0 || len(composeProfiles) > 0 {
+		services, err = composeRenderer.SelectServices(config, args, composeProfiles)
+		if err != nil {
+			return fmt.Errorf("failed to select services: %w", err)
+		}
+		ui.Info("Selected services: %s", strings.Join(services, ", "))
+	}
+
 	// Render Compose configuration
-	if err := composeRenderer.Render(config); err != nil {
+	if err := composeRenderer.RenderServices(config, services); err != nil {
 		return fmt.Errorf("failed to render Compose configuration: %w", err)
 	}
 
@@ -267,6 +325,12 @@ func runComposeUp(cmd *cobra.Command, args []string) error {
 
 	ui.Success("Services started successfully!")
 
+	if composeWait {
+		if err := waitForComposeServices(ctx, config, services, composeWaitTime); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -409,6 +473,95 @@ func startComposeServices(ctx context.Context, runner *toolrunner.Runner, projec
 	return nil
 }
 
+// waitForComposeServices polls the health endpoint of each started backend
+// service until all are healthy or the timeout elapses. Readiness is
+// reported per service as it happens.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - config: Project configuration
+//   - services: Started services (nil for all)
+//   - timeout: Maximum time to wait
+//
+// Returns:
+//   - error: Error naming the services that did not become healthy
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - One docker compose exec per pending service every poll interval
+func waitForComposeServices(ctx context.Context, config *configschema.Config, services []string, timeout time.Duration) error {
+	const pollInterval = 2 * time.Second
+
+	healthPorts := make(map[string]int)
+	for name, service := range config.Backend {
+		ports := service.Ports
+		if ports == nil {
+			ports = &config.BackendDefaults.Ports
+		}
+		healthPorts[name] = ports.Health
+	}
+
+	var pending []string
+	if services == nil {
+		for name := range healthPorts {
+			pending = append(pending, name)
+		}
+	} else {
+		for _, name := range services {
+			if _, ok := healthPorts[name]; ok {
+				pending = append(pending, name)
+			}
+		}
+	}
+	sort.Strings(pending)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ui.Info("Waiting for %d backend service(s) to become healthy (timeout: %s)...", len(pending), timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+
+	for {
+		var remaining []string
+		for _, name := range pending {
+			if composeServiceHealthy(ctx, config.ProjectName, name, healthPorts[name]) {
+				ui.Success("  %s is healthy (%s)", name, time.Since(start).Round(100*time.Millisecond))
+			} else {
+				remaining = append(remaining, name)
+			}
+		}
+		pending = remaining
+		if len(pending) == 0 {
+			ui.Success("All services are healthy!")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, name := range pending {
+				ui.Error("  %s is not healthy", name)
+			}
+			return fmt.Errorf("services not healthy after %s: %s (check 'egg compose logs --service <name>')",
+				timeout, strings.Join(pending, ", "))
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// composeServiceHealthy reports whether a service's health endpoint
+// responds inside its container. Services are not published on the host,
+// so the check runs through docker compose exec.
+func composeServiceHealthy(ctx context.Context, projectName, service string, healthPort int) bool {
+	healthURL := fmt.Sprintf("http://localhost:%d/health", healthPort)
+	return exec.CommandContext(ctx, "docker", "compose", "-f", "deploy/compose/compose.yaml", "-p", projectName,
+		"exec", "-T", service, "wget", "--spider", "-q", healthURL).Run() == nil
+}
+
 // stopComposeServices stops Docker Compose services.
 //
 // Parameters:
@@ -433,7 +586,8 @@ func stopComposeServices(ctx context.Context, runner *toolrunner.Runner, project
 	}
 
 	// Execute docker compose command
-	args := []string{"-f", composeFile, "-p", projectName, "down"}
+	// Remove orphans so services left over from a wider selection stop too
+	args := []string{"-f", composeFile, "-p", projectName, "down", "--remove-orphans"}
 	result, err := runner.DockerCompose(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("failed to stop services: %w", err)
@@ -830,3 +984,79 @@ func runComposeProxyStop(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runComposeProxyStatus executes the compose proxy-status command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - One state file read and one docker ps
+func runComposeProxyStatus(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Load configuration
+	config, diags, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if diags.HasErrors() {
+		ui.Error("Configuration validation failed:")
+		for _, diag := range diags.Items() {
+			if diag.Severity == configschema.SeverityError {
+				ui.Error("  %s: %s", diag.Path, diag.Message)
+			}
+		}
+		return fmt.Errorf("configuration validation failed")
+	}
+
+	// Create tool runner
+	runner := toolrunner.NewRunner(".")
+
+	// Create port proxy manager
+	manager := portproxy.NewManager(runner, config.ProjectName, getComposeNetworkName(config.ProjectName))
+
+	statuses, err := manager.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get proxy status: %w", err)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		ui.Info("No port proxies")
+		return nil
+	}
+
+	ui.Info("%-40s %-20s %-8s %-16s %s", "PROXY", "SERVICE", "PORT", "LOCAL", "STATE")
+	stale := 0
+	for _, status := range statuses {
+		state := "running"
+		switch {
+		case !status.Running:
+			state = "stale"
+			stale++
+		case !status.Recorded:
+			state = "untracked"
+		}
+		ui.Info("%-40s %-20s %-8d %-16s %s", status.ProxyName, status.ServiceName, status.ServicePort,
+			fmt.Sprintf("localhost:%d", status.LocalPort), state)
+	}
+	if stale > 0 {
+		ui.Warning("%d stale proxy record(s); run 'egg compose proxy-stop' to clean up", stale)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package main provides the egg CLI command implementations.
+//
+// Overview:
+//   - Responsibility: CLI command execution and orchestration
+//   - Key Types: Command handlers, argument parsers, option processors
+//   - Concurrency Model: Sequential command execution with context support
+//   - Error Semantics: User-friendly error messages with suggestions
+//   - Performance Notes: Fast command resolution, minimal initialization
+//
+// Usage:
+//
+//	egg config print <service> [--env docker|kubernetes]
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+	"go.eggybyte.com/egg/cli/internal/render/compose"
+	"go.eggybyte.com/egg/cli/internal/render/manifests"
+	"go.eggybyte.com/egg/cli/internal/ui"
+)
+
+// configCmd represents the config command.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the effective project configuration",
+	Long: `Inspect the effective project configuration.
+
+This command provides:
+- print: Effective environment of a service
+
+Examples:
+  egg config print user`,
+}
+
+// configPrintCmd represents the config print command.
+var configPrintCmd = &cobra.Command{
+	Use:   "print <service>",
+	Short: "Print the effective environment of a service",
+	Long: `Print the environment a backend or frontend service gets when deployed.
+
+Layers are merged in precedence order, later layers overriding earlier ones:
+- env.global
+- env.backend (backend) or env.frontend (frontend)
+- backend.<service>.env.common
+- backend.<service>.env.docker or backend.<service>.env.kubernetes
+
+Service identity and ports come from egg.yaml and cannot be overridden.
+${...} expressions are resolved as the Compose or Kubernetes renderer
+resolves them. Kubernetes variables injected from a ConfigMap or Secret
+are printed as <configmap:name/key> or <secret:name/key>.
+
+Flags:
+  --env: Target environment: docker (default) or kubernetes
+
+Example:
+  egg config print user
+  egg config print user --env kubernetes
+  egg config print user --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigPrint,
+}
+
+var configPrintEnv string
+
+// envEntry is a printed environment variable.
+type envEntry struct {
+	Name  string
+	Value string
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPrintCmd)
+
+	configPrintCmd.Flags().StringVar(&configPrintEnv, "env", "docker", "Target environment (docker, kubernetes)")
+}
+
+// runConfigPrint executes the config print command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments (service)
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Configuration loading and expression resolution
+func runConfigPrint(cmd *cobra.Command, args []string) error {
+	serviceName := args[0]
+
+	config, diags, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if diags.HasErrors() {
+		ui.Error("Configuration validation failed:")
+		for _, diag := range diags.Items() {
+			if diag.Severity == configschema.SeverityError {
+				ui.Error("  %s: %s", diag.Path, diag.Message)
+			}
+		}
+		return fmt.Errorf("configuration validation failed")
+	}
+
+	_, isBackend := config.Backend[serviceName]
+	if _, isFrontend := config.Frontend[serviceName]; !isBackend && !isFrontend {
+		return fmt.Errorf("service %s not found in egg.yaml", serviceName)
+	}
+
+	var env []envEntry
+	switch configPrintEnv {
+	case "docker":
+		env, err = composeEnv(config, serviceName, isBackend)
+	case "kubernetes":
+		env, err = kubernetesEnv(config, serviceName, isBackend)
+	default:
+		return fmt.Errorf("unsupported environment: %s (use docker or kubernetes)", configPrintEnv)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s environment of %s: %w", configPrintEnv, serviceName, err)
+	}
+
+	if jsonOutput {
+		values := make(map[string]string, len(env))
+		for _, v := range env {
+			values[v.Name] = v.Value
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(values)
+	}
+
+	for _, v := range env {
+		fmt.Printf("%s=%s\n", v.Name, v.Value)
+	}
+	return nil
+}
+
+// composeEnv returns the Docker Compose environment of a service.
+func composeEnv(config *configschema.Config, name string, backend bool) ([]envEntry, error) {
+	renderer := compose.NewRenderer(nil, ref.NewParser())
+	var vars []compose.EnvVar
+	var err error
+	if backend {
+		vars, err = renderer.BackendEnv(config, name)
+	} else {
+		vars, err = renderer.FrontendEnv(config, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]envEntry, 0, len(vars))
+	for _, v := range vars {
+		env = append(env, envEntry{Name: v.Name, Value: v.Value})
+	}
+	return env, nil
+}
+
+// kubernetesEnv returns the Kubernetes container environment of a service,
+// with injected values shown as their source.
+func kubernetesEnv(config *configschema.Config, name string, backend bool) ([]envEntry, error) {
+	renderer := manifests.NewRenderer(ref.NewParser())
+	var vars []manifests.EnvVar
+	var err error
+	if backend {
+		vars, err = renderer.BackendEnv(config, name)
+	} else {
+		vars, err = renderer.FrontendEnv(config, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := make([]envEntry, 0, len(vars))
+	for _, v := range vars {
+		value := v.Value
+		if v.ValueFrom != nil {
+			if selector := v.ValueFrom.SecretKeyRef; selector != nil {
+				value = fmt.Sprintf("<secret:%s/%s>", selector.Name, selector.Key)
+			} else if selector := v.ValueFrom.ConfigMapKeyRef; selector != nil {
+				value = fmt.Sprintf("<configmap:%s/%s>", selector.Name, selector.Key)
+			}
+		}
+		env = append(env, envEntry{Name: v.Name, Value: value})
+	}
+	return env, nil
+}
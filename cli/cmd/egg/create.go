@@ -61,8 +61,20 @@ This command creates:
 - Configuration management
 - Kubernetes deployment templates
 
+With --with-db the service is wired to the database: a model and
+repository are generated and main.go auto-migrates the model, requires
+the database (MustDB) and registers its constructors with
+servicex.RegisterServices. Without it a minimal service without database
+is generated. The crud proto template always uses the database.
+
+Flags:
+  --proto: Proto template: echo (default), crud, or none
+  --with-db: Wire the database (default: true for crud, false otherwise)
+
 Examples:
   egg create backend user-service
+  egg create backend user-service --proto crud
+  egg create backend user-service --with-db
   egg create backend user-service --local-modules`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCreateBackend,
@@ -97,6 +109,7 @@ var (
 	healthPort        int
 	metricsPort       int
 	protoTemplate     string
+	createWithDB      bool
 )
 
 func init() {
@@ -105,6 +118,7 @@ func init() {
 	createCmd.AddCommand(createFrontendCmd)
 
 	createBackendCmd.Flags().StringVar(&protoTemplate, "proto", "echo", "Proto template: echo, crud, or none")
+	createBackendCmd.Flags().BoolVar(&createWithDB, "with-db", false, "Wire the database: model, repository, and auto-migration (default: true for crud)")
 	createBackendCmd.Flags().BoolVar(&useLocalModules, "local-modules", false, "Use local egg modules for development")
 	createBackendCmd.Flags().IntVar(&httpPort, "http-port", 0, "HTTP port (default: 8080)")
 	createBackendCmd.Flags().IntVar(&healthPort, "health-port", 0, "Health check port (default: 8081)")
@@ -142,7 +156,16 @@ func runCreateBackend(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("proto template validation failed")
 	}
 
-	ui.Info("Creating backend service: %s (proto: %s)", serviceName, protoTemplate)
+	// The crud template is database-backed; default --with-db accordingly
+	withDatabase := createWithDB
+	if !cmd.Flags().Changed("with-db") {
+		withDatabase = protoTemplate == "crud"
+	} else if protoTemplate == "crud" && !withDatabase {
+		ui.Error("The crud proto template requires a database; omit --with-db=false")
+		return fmt.Errorf("proto template validation failed")
+	}
+
+	ui.Info("Creating backend service: %s (proto: %s, database: %t)", serviceName, protoTemplate, withDatabase)
 
 	// Load configuration
 	config, diags, err := loadConfig()
@@ -160,6 +183,10 @@ func runCreateBackend(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration validation failed")
 	}
 
+	if withDatabase && !config.Database.Enabled {
+		ui.Warning("database.enabled is false in egg.yaml: %s will need DB_DSN to start", serviceName)
+	}
+
 	// Check if service name conflicts with existing services (backend or frontend)
 	if _, exists := config.Backend[serviceName]; exists {
 		return fmt.Errorf("backend service '%s' already exists - duplicate service names are not allowed", serviceName)
@@ -194,7 +221,7 @@ func runCreateBackend(cmd *cobra.Command, args []string) error {
 	backendGen := generators.NewBackendGenerator(fs, runner)
 
 	// Generate service
-	if err := backendGen.Create(ctx, serviceName, config, protoTemplate, useLocalModules); err != nil {
+	if err := backendGen.Create(ctx, serviceName, config, protoTemplate, useLocalModules, withDatabase); err != nil {
 		return fmt.Errorf("failed to create backend service: %w", err)
 	}
 
@@ -0,0 +1,205 @@
+// Package egg provides the egg CLI command implementations.
+//
+// Overview:
+//   - Responsibility: CLI command execution and orchestration
+//   - Key Types: Command handlers, argument parsers, option processors
+//   - Concurrency Model: Sequential command execution with context support
+//   - Error Semantics: User-friendly error messages with suggestions
+//   - Performance Notes: Fast command resolution, minimal initialization
+//
+// Usage:
+//
+//	egg deploy render [-n <namespace>] [-o <file>]
+//	egg deploy apply [-n <namespace>] [--dry-run]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+	"go.eggybyte.com/egg/cli/internal/render/manifests"
+	"go.eggybyte.com/egg/cli/internal/toolrunner"
+	"go.eggybyte.com/egg/cli/internal/ui"
+)
+
+// deployCmd represents the deploy command.
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Render and apply Kubernetes manifests",
+	Long: `Render and apply plain Kubernetes manifests without Helm.
+
+Manifests are generated from egg.yaml:
+- A Deployment per backend and frontend service
+- clusterIP and headless Services per backend (kubernetes.service)
+- ConfigMaps and Secrets from kubernetes.resources
+
+Environment variables are merged from env.global, env.backend and the
+service's env.common and env.kubernetes, in increasing precedence.
+${cfgv:name:key} and ${sec:name:key} values are injected from the
+ConfigMap or Secret; ${cfg:name} and ${svc:name@clusterip|headless}
+expand to the resource names.
+
+Examples:
+  egg deploy render
+  egg deploy render -n production -o deploy/manifests.yaml
+  egg deploy apply -n production
+  egg deploy apply -n production --dry-run`,
+}
+
+// deployRenderCmd represents the deploy render command.
+var deployRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render Kubernetes manifests",
+	Long: `Render Kubernetes manifests from egg configuration.
+
+Manifests are written to stdout unless --output is given.
+
+Example:
+  egg deploy render -n production
+  egg deploy render -o deploy/manifests.yaml`,
+	RunE: runDeployRender,
+}
+
+// deployApplyCmd represents the deploy apply command.
+var deployApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply Kubernetes manifests",
+	Long: `Render Kubernetes manifests and pipe them to kubectl apply.
+
+Example:
+  egg deploy apply -n production
+  egg deploy apply -n production --dry-run`,
+	RunE: runDeployApply,
+}
+
+var (
+	deployNamespace string
+	deployOutput    string
+	deployDryRun    bool
+)
+
+func init() {
+	rootCmd.AddCommand(deployCmd)
+	deployCmd.AddCommand(deployRenderCmd)
+	deployCmd.AddCommand(deployApplyCmd)
+
+	deployCmd.PersistentFlags().StringVarP(&deployNamespace, "namespace", "n", "", "Kubernetes namespace (default: kubectl context namespace)")
+	deployRenderCmd.Flags().StringVarP(&deployOutput, "output", "o", "", "Write manifests to file instead of stdout")
+	deployApplyCmd.Flags().BoolVar(&deployDryRun, "dry-run", false, "Validate manifests with kubectl without changing the cluster")
+}
+
+// runDeployRender executes the deploy render command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - In-memory rendering, single file write
+func runDeployRender(cmd *cobra.Command, args []string) error {
+	output, err := renderDeployManifests()
+	if err != nil {
+		return err
+	}
+
+	if deployOutput == "" {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+
+	if err := os.WriteFile(deployOutput, output, 0644); err != nil {
+		return fmt.Errorf("failed to write manifests: %w", err)
+	}
+	ui.Success("Kubernetes manifests rendered: %s", deployOutput)
+	return nil
+}
+
+// runDeployApply executes the deploy apply command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Apply time depends on cluster response
+func runDeployApply(cmd *cobra.Command, args []string) error {
+	output, err := renderDeployManifests()
+	if err != nil {
+		return err
+	}
+
+	if deployNamespace != "" {
+		ui.Info("Applying Kubernetes manifests to namespace: %s", deployNamespace)
+	} else {
+		ui.Info("Applying Kubernetes manifests...")
+	}
+
+	runner := toolrunner.NewRunner(".")
+	runner.SetVerbose(true)
+
+	if err := runner.KubectlApplyManifests(context.Background(), output, deployNamespace, deployDryRun); err != nil {
+		return err
+	}
+
+	if deployDryRun {
+		ui.Success("Kubernetes manifests validated (dry run)")
+	} else {
+		ui.Success("Kubernetes manifests applied successfully!")
+	}
+	return nil
+}
+
+// renderDeployManifests loads and validates egg.yaml and renders its
+// Kubernetes manifests.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - []byte: Multi-document YAML manifests
+//   - error: Configuration or rendering error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Configuration parsing and in-memory rendering
+func renderDeployManifests() ([]byte, error) {
+	config, diags, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if diags.HasErrors() {
+		ui.Error("Configuration validation failed:")
+		for _, diag := range diags.Items() {
+			if diag.Severity == configschema.SeverityError {
+				ui.Error("  %s: %s", diag.Path, diag.Message)
+			}
+		}
+		return nil, fmt.Errorf("configuration validation failed")
+	}
+
+	renderer := manifests.NewRenderer(ref.NewParser())
+	output, err := renderer.Render(config, deployNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Kubernetes manifests: %w", err)
+	}
+	return output, nil
+}
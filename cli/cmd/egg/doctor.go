@@ -14,11 +14,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/configschema"
 	"go.eggybyte.com/egg/cli/internal/toolrunner"
 	"go.eggybyte.com/egg/cli/internal/ui"
 	"go.eggybyte.com/egg/cli/internal/version"
@@ -31,12 +37,17 @@ var doctorCmd = &cobra.Command{
 	Long: `Perform comprehensive diagnostics of your EGG development environment.
 
 This command verifies:
-  • Core toolchain (Go, Docker)
-  • Development tools (buf, kubectl, helm)
+  • Core toolchain (Go ` + minGoVersion + `+, Docker with buildx and compose)
+  • Development tools (buf, flutter, kubectl, helm)
   • Code generation (Local protoc plugins)
+  • Project (egg.yaml validity, backend/go.work), when run in a project
   • Network connectivity (Docker Hub, Go Proxy)
   • File system permissions
 
+Failed checks print a suggested fix. The command exits non-zero when a
+hard requirement is missing, so CI can gate on it. Flutter is required
+only when egg.yaml declares frontend services.
+
 Note: EGG uses local protoc plugins for offline-first development.
 To install missing plugins, use: egg doctor --install
 
@@ -51,6 +62,60 @@ func init() {
 	rootCmd.AddCommand(doctorCmd)
 }
 
+// minGoVersion is the oldest Go toolchain that can build generated
+// projects (their go.mod and go.work files require it).
+const minGoVersion = "1.25"
+
+// doctorIssue is a failed check with a suggested fix.
+type doctorIssue struct {
+	message string
+	fix     string
+}
+
+// Error returns the issue message.
+func (i *doctorIssue) Error() string {
+	return i.message
+}
+
+// newDoctorIssue creates a failed check result with a suggested fix.
+//
+// Parameters:
+//   - fix: Actionable suggestion shown below the failure
+//   - format: Message format string
+//   - args: Message arguments
+//
+// Returns:
+//   - error: Issue error
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single allocation
+func newDoctorIssue(fix, format string, args ...interface{}) error {
+	return &doctorIssue{message: fmt.Sprintf(format, args...), fix: fix}
+}
+
+// printFix prints the suggested fix of a failed check, if it has one.
+//
+// Parameters:
+//   - err: Check error
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Error inspection only
+func printFix(err error) {
+	var issue *doctorIssue
+	if errors.As(err, &issue) && issue.fix != "" {
+		ui.Info("      Fix: %s", issue.fix)
+	}
+}
+
 // runDoctor executes the doctor command.
 //
 // Parameters:
@@ -87,6 +152,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	hasErrors := false
 	hasWarnings := false
 
+	// Load project configuration when run inside a project
+	var config *configschema.Config
+	var diags *configschema.Diagnostics
+	if _, err := os.Stat("egg.yaml"); err == nil {
+		config, diags = configschema.Load("egg.yaml")
+	}
+
 	// Check version information
 	checkVersionInfo()
 
@@ -98,6 +170,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	ui.Info("  Checking Go installation...")
 	if err := checkGoInstallation(ctx, runner); err != nil {
 		ui.Error("  Go: %v", err)
+		printFix(err)
 		hasErrors = true
 	} else {
 		ui.Success("  Go")
@@ -111,6 +184,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	ui.Info("  Checking Docker installation...")
 	if err := checkDockerInstallation(ctx, runner); err != nil {
 		ui.Error("  Docker: %v", err)
+		printFix(err)
 		hasErrors = true
 	} else {
 		ui.Success("  Docker")
@@ -132,7 +206,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Check required tools
 	ui.Info("Development Tools")
-	if err := checkRequiredTools(ctx, runner); err != nil {
+	requireFlutter := config != nil && len(config.Frontend) > 0
+	if err := checkRequiredTools(ctx, runner, requireFlutter); err != nil {
 		ui.Error("  %v", err)
 		hasErrors = true
 	}
@@ -152,6 +227,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	}
 	ui.Info("")
 
+	// Check project configuration
+	ui.Info("Project")
+	projectErrors, projectWarnings := checkProject(config, diags)
+	hasErrors = hasErrors || projectErrors
+	hasWarnings = hasWarnings || projectWarnings
+	ui.Info("")
+
 	// Check network connectivity
 	ui.Info("Network Connectivity")
 	if err := checkNetworkConnectivity(ctx, runner); err != nil {
@@ -243,30 +325,97 @@ func checkSystemInfo() {
 // Performance:
 //   - Go version check
 func checkGoInstallation(ctx context.Context, runner *toolrunner.Runner) error {
+	installFix := "Install Go " + minGoVersion + " or newer from https://go.dev/dl/"
+
 	// Check if go is available
 	if available, _ := toolrunner.CheckToolAvailability("go"); !available {
-		return fmt.Errorf("not found in PATH")
+		return newDoctorIssue(installFix, "not found in PATH")
 	}
 
 	// Get Go version
 	goVersion, err := toolrunner.GetGoVersion(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get version: %w", err)
+		return newDoctorIssue(installFix, "failed to get version: %v", err)
 	}
 
 	// Check Go modules support
 	if _, err := runner.Go(ctx, "env", "GOMOD"); err != nil {
-		return fmt.Errorf("modules not supported")
+		return newDoctorIssue(installFix, "modules not supported")
 	}
 
-	// Parse version to check minimum requirement (1.21+)
-	if !strings.Contains(goVersion, "go1.") {
-		return fmt.Errorf("invalid version format: %s", goVersion)
+	// Parse version to check minimum requirement
+	ok, err := goVersionAtLeast(goVersion, minGoVersion)
+	if err != nil {
+		return newDoctorIssue(installFix, "%v", err)
+	}
+	if !ok {
+		return newDoctorIssue(installFix, "version %s is older than the required %s",
+			strings.TrimSpace(goVersion), minGoVersion)
 	}
 
 	return nil
 }
 
+// goVersionAtLeast reports whether the output of `go version` names a
+// toolchain of at least the given version.
+//
+// Parameters:
+//   - goVersion: Output of `go version` (e.g., "go version go1.25.1 linux/amd64")
+//   - minimum: Minimum version as major.minor (e.g., "1.25")
+//
+// Returns:
+//   - bool: True if the toolchain is at least minimum
+//   - error: Error if the version cannot be parsed
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - String parsing only
+func goVersionAtLeast(goVersion, minimum string) (bool, error) {
+	var current string
+	for _, field := range strings.Fields(goVersion) {
+		if strings.HasPrefix(field, "go1.") {
+			current = strings.TrimPrefix(field, "go")
+			break
+		}
+	}
+	if current == "" {
+		return false, fmt.Errorf("invalid version format: %s", strings.TrimSpace(goVersion))
+	}
+
+	parse := func(v string) (int, int, error) {
+		parts := strings.SplitN(v, ".", 3)
+		if len(parts) < 2 {
+			return 0, 0, fmt.Errorf("invalid version: %s", v)
+		}
+		// Strip pre-release suffixes such as "25rc1"
+		minor := parts[1]
+		if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+			minor = minor[:i]
+		}
+		major, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid version: %s", v)
+		}
+		minorNum, err := strconv.Atoi(minor)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid version: %s", v)
+		}
+		return major, minorNum, nil
+	}
+
+	major, minor, err := parse(current)
+	if err != nil {
+		return false, err
+	}
+	minMajor, minMinor, err := parse(minimum)
+	if err != nil {
+		return false, err
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor), nil
+}
+
 // checkDockerInstallation checks Docker installation and availability.
 //
 // Parameters:
@@ -284,23 +433,27 @@ func checkGoInstallation(ctx context.Context, runner *toolrunner.Runner) error {
 func checkDockerInstallation(ctx context.Context, runner *toolrunner.Runner) error {
 	// Check if docker is available
 	if available, _ := toolrunner.CheckToolAvailability("docker"); !available {
-		return fmt.Errorf("not found in PATH")
+		return newDoctorIssue("Install Docker Desktop or Docker Engine: https://docs.docker.com/get-docker/",
+			"not found in PATH")
 	}
 
 	// Check Docker daemon
 	_, err := runner.Docker(ctx, "version", "--format", "{{.Server.Version}}")
 	if err != nil {
-		return fmt.Errorf("daemon not running")
+		return newDoctorIssue("Start Docker Desktop, or run: sudo systemctl start docker",
+			"daemon not running")
 	}
 
 	// Check Docker buildx (required)
 	if _, err := runner.Docker(ctx, "buildx", "version"); err != nil {
-		return fmt.Errorf("buildx not available (required for multi-platform builds)")
+		return newDoctorIssue("Install the buildx plugin: https://docs.docker.com/build/install-buildx/",
+			"buildx not available (required for multi-platform builds)")
 	}
 
 	// Check Docker Compose (required)
 	if _, err := runner.DockerCompose(ctx, "version"); err != nil {
-		return fmt.Errorf("compose not available (required for local development)")
+		return newDoctorIssue("Install the Compose plugin: https://docs.docker.com/compose/install/",
+			"compose not available (required for local development)")
 	}
 
 	return nil
@@ -311,6 +464,7 @@ func checkDockerInstallation(ctx context.Context, runner *toolrunner.Runner) err
 // Parameters:
 //   - ctx: Context for cancellation
 //   - runner: Tool runner
+//   - requireFlutter: Whether Flutter is required (the project has frontends)
 //
 // Returns:
 //   - error: Check error if any
@@ -320,15 +474,17 @@ func checkDockerInstallation(ctx context.Context, runner *toolrunner.Runner) err
 //
 // Performance:
 //   - Tool availability checks
-func checkRequiredTools(ctx context.Context, runner *toolrunner.Runner) error {
+func checkRequiredTools(ctx context.Context, runner *toolrunner.Runner, requireFlutter bool) error {
 	tools := []struct {
 		name        string
 		required    bool
 		description string
+		fix         string
 	}{
-		{"buf", true, "Protocol buffer compiler"},
-		{"kubectl", false, "Kubernetes CLI"},
-		{"helm", false, "Kubernetes package manager"},
+		{"buf", true, "Protocol buffer compiler", "Run: egg doctor --install (or see https://buf.build/docs/installation)"},
+		{"flutter", requireFlutter, "Flutter SDK for frontend services", "Install Flutter: https://docs.flutter.dev/get-started/install"},
+		{"kubectl", false, "Kubernetes CLI", "Install kubectl: https://kubernetes.io/docs/tasks/tools/"},
+		{"helm", false, "Kubernetes package manager", "Install Helm: https://helm.sh/docs/intro/install/"},
 	}
 
 	var missingRequired []string
@@ -345,6 +501,11 @@ func checkRequiredTools(ctx context.Context, runner *toolrunner.Runner) error {
 				if result, err := runner.Buf(ctx, "--version"); err == nil && result.ExitCode == 0 {
 					toolVersion = strings.TrimSpace(result.Stdout)
 				}
+			case "flutter":
+				// flutter --version prints "Flutter 3.x.y • channel stable • ..." first
+				if result, err := runner.Flutter(ctx, "--version"); err == nil && result.ExitCode == 0 {
+					toolVersion = strings.TrimSpace(strings.SplitN(result.Stdout, "\n", 2)[0])
+				}
 			case "kubectl":
 				// kubectl version --client outputs version info, parse from output
 				if result, err := runner.Exec(ctx, "kubectl", "version", "--client"); err == nil && result.ExitCode == 0 {
@@ -374,6 +535,7 @@ func checkRequiredTools(ctx context.Context, runner *toolrunner.Runner) error {
 			} else {
 				ui.Warning("  %s (optional - %s)", tool.name, tool.description)
 			}
+			ui.Info("      Fix: %s", tool.fix)
 		}
 	}
 
@@ -562,6 +724,138 @@ func installProtocPlugins(ctx context.Context) error {
 	return nil
 }
 
+// checkProject checks egg.yaml validity and the backend workspace when
+// run inside a project.
+//
+// Parameters:
+//   - config: Loaded configuration (nil when there is no egg.yaml)
+//   - diags: Diagnostics from loading egg.yaml
+//
+// Returns:
+//   - bool: True if a hard requirement failed
+//   - bool: True if warnings were reported
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Reads backend/go.work once
+func checkProject(config *configschema.Config, diags *configschema.Diagnostics) (bool, bool) {
+	if config == nil && diags == nil {
+		ui.Info("  No egg.yaml in current directory, skipping project checks")
+		return false, false
+	}
+
+	hasErrors, hasWarnings := false, false
+
+	// Configuration validity
+	ui.Info("  Checking egg.yaml...")
+	if config == nil || diags.HasErrors() {
+		ui.Error("  egg.yaml: invalid configuration")
+		hasErrors = true
+	} else if diags.HasWarnings() {
+		ui.Warning("  egg.yaml: valid with warnings")
+		hasWarnings = true
+	} else {
+		ui.Success("  egg.yaml")
+	}
+	for _, diag := range diags.Items() {
+		switch diag.Severity {
+		case configschema.SeverityError:
+			ui.Error("      %s: %s", diag.Path, diag.Message)
+		case configschema.SeverityWarning:
+			ui.Warning("      %s: %s", diag.Path, diag.Message)
+		default:
+			continue
+		}
+		if diag.Suggestion != "" {
+			ui.Info("      Fix: %s", diag.Suggestion)
+		}
+	}
+	if config == nil || len(config.Backend) == 0 {
+		return hasErrors, hasWarnings
+	}
+
+	// Backend workspace
+	ui.Info("  Checking backend/go.work...")
+	var services []string
+	for name := range config.Backend {
+		if _, err := os.Stat(filepath.Join("backend", name)); err == nil {
+			services = append(services, name) // Not created yet otherwise; egg check reports this
+		}
+	}
+	sort.Strings(services)
+
+	workPath := filepath.Join("backend", "go.work")
+	data, err := os.ReadFile(workPath)
+	if err != nil {
+		ui.Error("  backend/go.work: not found")
+		if len(services) > 0 {
+			ui.Info("      Fix: Run: cd backend && go work init ./%s", strings.Join(services, " ./"))
+		} else {
+			ui.Info("      Fix: Create a backend service: egg create backend <name>")
+		}
+		return true, hasWarnings
+	}
+
+	var missing []string
+	for _, name := range services {
+		if !workspaceUses(string(data), name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		ui.Warning("  backend/go.work: missing services: %s", strings.Join(missing, ", "))
+		ui.Info("      Fix: Run: cd backend && go work use ./%s", strings.Join(missing, " ./"))
+		return hasErrors, true
+	}
+	ui.Success("  backend/go.work")
+
+	return hasErrors, hasWarnings
+}
+
+// workspaceUses reports whether a go.work file uses the backend service
+// directory name, in either the single-line or block use form.
+//
+// Parameters:
+//   - goWork: go.work file content
+//   - name: Service directory name
+//
+// Returns:
+//   - bool: True if the directory is used
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single pass over the file
+func workspaceUses(goWork, name string) bool {
+	inUse := false
+	for _, line := range strings.Split(goWork, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "use (":
+			inUse = true
+			continue
+		case inUse && line == ")":
+			inUse = false
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use "))
+		case !inUse:
+			continue
+		}
+		dir := strings.TrimSuffix(filepath.ToSlash(filepath.Clean(strings.Trim(line, `"`))), "/")
+		if dir == name {
+			return true
+		}
+	}
+	return false
+}
+
 // checkNetworkConnectivity checks network connectivity to required services.
 //
 // Parameters:
@@ -0,0 +1,143 @@
+// Package main provides the egg CLI command implementations.
+//
+// Overview:
+//   - Responsibility: CLI command execution and orchestration
+//   - Key Types: Command handlers, argument parsers, option processors
+//   - Concurrency Model: Sequential command execution with context support
+//   - Error Semantics: User-friendly error messages with suggestions
+//   - Performance Notes: Fast command resolution, minimal initialization
+//
+// Usage:
+//
+//	egg gen client <service> <target> [--proto <file>] [--service <name>] [--force]
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/generators"
+	"go.eggybyte.com/egg/cli/internal/projectfs"
+	"go.eggybyte.com/egg/cli/internal/toolrunner"
+	"go.eggybyte.com/egg/cli/internal/ui"
+)
+
+// genCmd represents the gen command.
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate code for existing services",
+	Long: `Generate code for existing services.
+
+This command provides:
+- client: Typed clientx wrappers for calling other services
+
+Examples:
+  egg gen client user greet`,
+}
+
+// genClientCmd represents the gen client command.
+var genClientCmd = &cobra.Command{
+	Use:   "client <service> <target>",
+	Short: "Generate a typed client for another service",
+	Long: `Generate a typed clientx client that <service> uses to call <target>.
+
+The client is generated from the target's proto service (default:
+api/<target>/v1/<target>.proto) into backend/<service>/internal/client:
+- New<Name>Client with timeout, retry, circuit breaker and internal token
+- One typed method per unary RPC (streaming RPCs via Client())
+- Provide<Name>Client registering the client in the DI container, with the
+  base URL read from <NAME>_SERVICE_URL (default: http://<target>:<port>)
+
+The Provide call is added to registerServices in cmd/server/main.go, so
+constructors can depend on *client.<Name>Client. Run 'egg api generate'
+first so the target's Connect code exists in gen/go.
+
+Flags:
+  --proto: Proto file path (default: api/<target>/v1/<target>.proto)
+  --service: Proto service to wrap if the file defines several
+  --force: Overwrite an existing client
+
+Example:
+  egg gen client user greet
+  egg gen client order payment --service PaymentService
+  egg gen client order inventory --proto api/inventory/v1/stock.proto --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runGenClient,
+}
+
+var (
+	genClientProto   string
+	genClientService string
+	genClientForce   bool
+)
+
+func init() {
+	rootCmd.AddCommand(genCmd)
+	genCmd.AddCommand(genClientCmd)
+
+	genClientCmd.Flags().StringVar(&genClientProto, "proto", "", "Proto file path (default: api/<target>/v1/<target>.proto)")
+	genClientCmd.Flags().StringVar(&genClientService, "service", "", "Proto service to wrap (required if the file defines several)")
+	genClientCmd.Flags().BoolVar(&genClientForce, "force", false, "Overwrite an existing client")
+}
+
+// runGenClient executes the gen client command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments (service, target)
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Proto parsing, code generation and one go get
+func runGenClient(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	serviceName, target := args[0], args[1]
+
+	config, diags, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if diags.HasErrors() {
+		ui.Error("Configuration validation failed:")
+		for _, diag := range diags.Items() {
+			if diag.Severity == configschema.SeverityError {
+				ui.Error("  %s: %s", diag.Path, diag.Message)
+			}
+		}
+		return fmt.Errorf("configuration validation failed")
+	}
+
+	if serviceName == target {
+		return fmt.Errorf("service %s cannot generate a client for itself", serviceName)
+	}
+
+	ui.Info("Generating %s client for backend service: %s", target, serviceName)
+
+	// Create project file system
+	fs := projectfs.NewProjectFS(".")
+	fs.SetVerbose(true)
+
+	// Create tool runner
+	runner := toolrunner.NewRunner(".")
+	runner.SetVerbose(true)
+
+	clientGen := generators.NewClientGenerator(fs, runner)
+	if err := clientGen.Generate(ctx, serviceName, target, config, generators.ClientOptions{
+		ProtoFile:    genClientProto,
+		ProtoService: genClientService,
+		Force:        genClientForce,
+	}); err != nil {
+		return fmt.Errorf("failed to generate client: %w", err)
+	}
+
+	ui.Success("Client for %s generated successfully!", target)
+	return nil
+}
@@ -138,7 +138,7 @@ func runStandaloneBuild(cmd *cobra.Command, args []string) error {
 		// Push if requested (single platform)
 		if standaloneBuildPush {
 			ui.Info("Pushing image to registry...")
-			if err := pushDockerImage(ctx, imageTag); err != nil {
+			if err := pushDockerImage(ctx, imageTag, stdOutput); err != nil {
 				return fmt.Errorf("failed to push image: %w", err)
 			}
 			ui.Success("Image pushed successfully")
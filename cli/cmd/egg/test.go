@@ -0,0 +1,277 @@
+// Package egg provides the egg CLI command implementations.
+//
+// Overview:
+//   - Responsibility: CLI command execution and orchestration
+//   - Key Types: Command handlers, argument parsers, option processors
+//   - Concurrency Model: Sequential command execution with context support
+//   - Error Semantics: User-friendly error messages with suggestions
+//   - Performance Notes: Fast command resolution, minimal initialization
+//
+// Usage:
+//
+//	egg test <service> [--suite <suite>] [--host <host>]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/connecttest"
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ui"
+)
+
+var (
+	testHost       string
+	testBaseURL    string
+	testHealthURL  string
+	testMetricsURL string
+	testSuites     []string
+	testTimeout    time.Duration
+	testToken      string
+)
+
+// testCmd represents the test command.
+var testCmd = &cobra.Command{
+	Use:   "test <service>",
+	Short: "Run integration checks against a running service",
+	Long: `Run integration checks against a running backend service.
+
+Endpoints are derived from the service's ports in egg.yaml, so the same
+checks work for Docker Compose port proxies and deployed services.
+
+Suites:
+- health:  health endpoint responds with 200
+- greet:   greet.v1.GreeterService unary and streaming calls
+- crud:    user.v1.UserService create, read, update, delete and list
+- admin:   user.v1.UserService admin calls (deletes all users; needs --internal-token)
+- metrics: Prometheus endpoint, target_info and RPC request counts
+
+Without --suite, health and metrics are checked. RPC suites run before
+metrics so the request counts include their calls.
+
+Examples:
+  egg test user --suite crud,metrics
+  egg test greet --suite health,greet,metrics --host 10.0.0.12
+  egg test user --url http://user.example.com --metrics-url http://user.example.com:9091/metrics
+  egg test user --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTest,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+
+	testCmd.Flags().StringVar(&testHost, "host", "localhost", "Host the service ports are reachable on")
+	testCmd.Flags().StringVar(&testBaseURL, "url", "", "Connect base URL (overrides --host and the HTTP port)")
+	testCmd.Flags().StringVar(&testHealthURL, "health-url", "", "Health check URL (overrides --host and the health port)")
+	testCmd.Flags().StringVar(&testMetricsURL, "metrics-url", "", "Metrics URL (overrides --host and the metrics port)")
+	testCmd.Flags().StringSliceVar(&testSuites, "suite", nil, "Suites to run (comma-separated: health, greet, crud, admin, metrics)")
+	testCmd.Flags().DurationVar(&testTimeout, "timeout", 10*time.Second, "Timeout per request")
+	testCmd.Flags().StringVar(&testToken, "internal-token", os.Getenv("INTERNAL_TOKEN"), "Internal token for the admin suite (default: $INTERNAL_TOKEN)")
+}
+
+// runTest executes the test command.
+//
+// Parameters:
+//   - cmd: Cobra command
+//   - args: Command arguments (service name)
+//
+// Returns:
+//   - error: Execution error, or a failure summary if any check failed
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Dominated by service response times
+func runTest(cmd *cobra.Command, args []string) error {
+	service := args[0]
+
+	endpoints, err := resolveTestEndpoints(service)
+	if err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		ui.Info("Testing service: %s", service)
+		ui.Info("  Connect: %s", endpoints.BaseURL)
+		ui.Info("  Health:  %s", endpoints.HealthURL)
+		ui.Info("  Metrics: %s", endpoints.MetricsURL)
+	}
+
+	opts := connecttest.Options{
+		Service:   service,
+		Endpoints: endpoints,
+		Suites:    testSuites,
+		Timeout:   testTimeout,
+
+		InternalToken: testToken,
+	}
+	if !jsonOutput {
+		opts.OnResult = displayTestResult
+	}
+
+	report, err := connecttest.Run(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		displayTestSummary(report)
+	}
+
+	if !report.Success() {
+		return fmt.Errorf("%d of %d checks failed", report.Failed, report.Total-report.Skipped)
+	}
+	return nil
+}
+
+// resolveTestEndpoints derives the service endpoints from egg.yaml and
+// applies URL overrides. With all three URLs overridden, egg.yaml is not
+// needed.
+//
+// Parameters:
+//   - service: Backend service name
+//
+// Returns:
+//   - connecttest.Endpoints: Service endpoints
+//   - error: Error if the configuration cannot be loaded or lacks the service
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Configuration parsing when URLs are not all overridden
+func resolveTestEndpoints(service string) (connecttest.Endpoints, error) {
+	var endpoints connecttest.Endpoints
+	if testBaseURL == "" || testHealthURL == "" || testMetricsURL == "" {
+		config, _, err := loadConfig()
+		if err != nil {
+			return endpoints, fmt.Errorf("failed to load configuration: %w", err)
+		}
+		endpoints, err = endpointsFromConfig(config, service, testHost)
+		if err != nil {
+			return endpoints, err
+		}
+	}
+
+	if testBaseURL != "" {
+		endpoints.BaseURL = strings.TrimRight(testBaseURL, "/")
+	}
+	if testHealthURL != "" {
+		endpoints.HealthURL = testHealthURL
+	}
+	if testMetricsURL != "" {
+		endpoints.MetricsURL = testMetricsURL
+	}
+	return endpoints, nil
+}
+
+// endpointsFromConfig derives the endpoints of a backend service from its
+// configured ports.
+//
+// Parameters:
+//   - config: Loaded project configuration (defaults applied)
+//   - service: Backend service name as declared in egg.yaml
+//   - host: Host the service ports are reachable on (e.g., localhost)
+//
+// Returns:
+//   - connecttest.Endpoints: Service endpoints
+//   - error: Error if the service is not declared
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - O(n) in the number of backend services on error
+func endpointsFromConfig(config *configschema.Config, service, host string) (connecttest.Endpoints, error) {
+	backend, ok := config.Backend[service]
+	if !ok {
+		names := make([]string, 0, len(config.Backend))
+		for name := range config.Backend {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return connecttest.Endpoints{}, fmt.Errorf("backend service %q not found in egg.yaml (available: %s)",
+			service, strings.Join(names, ", "))
+	}
+
+	ports := backend.Ports
+	if ports == nil {
+		ports = &config.BackendDefaults.Ports
+	}
+	return connecttest.EndpointsAt(host, ports.HTTP, ports.Health, ports.Metrics), nil
+}
+
+// displayTestResult prints a single check result as it completes.
+//
+// Parameters:
+//   - result: Check result
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - One line per result
+func displayTestResult(result connecttest.Result) {
+	name := result.Suite + "/" + result.Name
+	switch {
+	case result.Skipped:
+		ui.Warning("SKIP %s: %s", name, result.Details)
+	case result.Passed:
+		if result.Details != "" {
+			ui.Success("PASS %s (%dms) %s", name, result.DurationMS, result.Details)
+		} else {
+			ui.Success("PASS %s (%dms)", name, result.DurationMS)
+		}
+	default:
+		ui.Error("FAIL %s (%dms): %s", name, result.DurationMS, result.Error)
+	}
+}
+
+// displayTestSummary prints the totals of a test run and lists failures.
+//
+// Parameters:
+//   - report: Test report
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - O(n) in the number of results
+func displayTestSummary(report *connecttest.Report) {
+	ui.Info("")
+	ui.Info("Test summary: %d passed, %d failed, %d skipped (%dms)",
+		report.Passed, report.Failed, report.Skipped, report.DurationMS)
+
+	if report.Success() {
+		ui.Success("All checks passed for %s", report.Service)
+		return
+	}
+
+	ui.Error("Failed checks:")
+	for _, result := range report.Results {
+		if !result.Passed && !result.Skipped {
+			ui.Info("  %s/%s: %s", result.Suite, result.Name, result.Error)
+		}
+	}
+}
@@ -1,22 +1,28 @@
 // Package main provides the egg CLI version command.
 //
 // Overview:
-//   - Responsibility: Display CLI version information
+//   - Responsibility: Display CLI and project module version information
 //   - Key Types: Version command handler
 //   - Concurrency Model: Single-threaded CLI execution
-//   - Error Semantics: No errors (version display only)
-//   - Performance Notes: Fast version lookup
+//   - Error Semantics: Unparseable project go.mod/go.work files are reported as errors
+//   - Performance Notes: Fast version lookup, reads project module files once
 //
 // Usage:
 //
 //	egg --version
 //	egg -v
+//	egg version [--json]
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"go.eggybyte.com/egg/cli/internal/ui"
 	"go.eggybyte.com/egg/cli/internal/version"
 )
 
@@ -29,8 +35,35 @@ var versionCmd = &cobra.Command{
 This command shows:
   • CLI version, git commit hash, and build timestamp
   • Egg framework version
-  • Go runtime version`,
-	Run: runVersion,
+  • Go runtime version
+  • CLI build info embedded by the Go toolchain (module, VCS revision)
+  • Egg modules required by each project module, when run in a project
+
+Project modules are read from go.work or backend/go.work, or from go.mod
+for standalone services. Framework modules (core, logx, connectx, obsx,
+servicex, ...) are released together; modules pinned to a version other
+than the one the project mostly uses are reported as mismatches.
+Replaced (local) modules are not checked.
+
+Examples:
+  egg version
+  egg version --json`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+// versionReport is the JSON output of the version command.
+type versionReport struct {
+	Version          string                  `json:"version"`
+	Commit           string                  `json:"commit"`
+	BuildTime        string                  `json:"build_time"`
+	FrameworkVersion string                  `json:"framework_version"`
+	GoVersion        string                  `json:"go_version"`
+	Platform         string                  `json:"platform"`
+	Build            *version.BuildInfo      `json:"build,omitempty"`
+	Modules          []version.ProjectModule `json:"modules,omitempty"`
+	ProjectVersion   string                  `json:"project_framework_version,omitempty"`
+	Mismatches       []version.Mismatch      `json:"mismatches,omitempty"`
 }
 
 func init() {
@@ -49,14 +82,97 @@ func init() {
 //   - args: Command arguments
 //
 // Returns:
-//   - None (writes to stdout)
+//   - error: Error if a project go.mod or go.work cannot be parsed
 //
 // Concurrency:
 //   - Single-threaded
 //
 // Performance:
-//   - Fast version string formatting
-func runVersion(cmd *cobra.Command, args []string) {
+//   - Reads project module files once
+func runVersion(cmd *cobra.Command, args []string) error {
+	report := versionReport{
+		Version:          version.Version,
+		Commit:           version.Commit,
+		BuildTime:        version.BuildTime,
+		FrameworkVersion: version.FrameworkVersion,
+		GoVersion:        runtime.Version(),
+		Platform:         runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if build, ok := version.ReadBuildInfo(); ok {
+		report.Build = &build
+	}
+
+	// Project modules are optional: outside a project only the CLI is reported
+	if _, err := os.Stat("go.mod"); err == nil || hasWorkspace() {
+		modules, err := version.ScanProject(".")
+		if err != nil {
+			return fmt.Errorf("failed to read project modules: %w", err)
+		}
+		report.Modules = modules
+		report.ProjectVersion, report.Mismatches = version.FindMismatches(modules)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
 	fmt.Println(version.GetFullVersionInfo())
+	if build := report.Build; build != nil {
+		line := fmt.Sprintf("built from %s %s with %s", build.Path, build.Version, build.GoVersion)
+		if build.Revision != "" {
+			line += fmt.Sprintf(" (revision %s", build.Revision)
+			if build.Modified {
+				line += ", modified"
+			}
+			line += ")"
+		}
+		fmt.Println(line)
+	}
+
+	if len(report.Modules) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("Project modules:")
+	for _, module := range report.Modules {
+		fmt.Printf("  %s (%s)\n", module.Dir, module.Path)
+		if len(module.Requires) == 0 {
+			fmt.Println("    no egg modules")
+		}
+		for _, req := range module.Requires {
+			line := fmt.Sprintf("    %-10s %s", req.Module, req.Version)
+			if req.Indirect {
+				line += " (indirect)"
+			}
+			if req.Replace != "" {
+				line += " => " + req.Replace
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if len(report.Mismatches) > 0 {
+		fmt.Println()
+		ui.Warning("Framework modules should share one version (%s):", report.ProjectVersion)
+		for _, mismatch := range report.Mismatches {
+			ui.Warning("  %s %s in %s", mismatch.Module, mismatch.Version, strings.Join(mismatch.Users, ", "))
+		}
+		ui.Info("Fix: go get %s<module>@%s in the listed modules", version.ModulePrefix, report.ProjectVersion)
+	}
+	return nil
+}
+
+// hasWorkspace reports whether the current directory has a Go workspace
+// of its own or under backend/.
+func hasWorkspace() bool {
+	for _, path := range []string{"go.work", "backend/go.work"} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
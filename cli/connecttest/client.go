@@ -0,0 +1,273 @@
+package connecttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Envelope flags of the Connect streaming protocol.
+const (
+	flagCompressed = 0x01
+	flagEndStream  = 0x02
+)
+
+// RPCError is an error returned by a Connect service.
+type RPCError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (e *RPCError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Message
+}
+
+// CodeOf returns the Connect code of err, or "" if err is not an RPCError.
+//
+// Parameters:
+//   - err: Error returned by a Client call
+//
+// Returns:
+//   - string: Connect error code (e.g., "not_found")
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1) error unwrapping
+func CodeOf(err error) string {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return ""
+}
+
+// Client calls Connect procedures using the JSON codec, so services can be
+// tested without their generated stubs.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// NewClient creates a Connect JSON client for the service at baseURL.
+//
+// Parameters:
+//   - baseURL: Service base URL (e.g., http://localhost:8080)
+//   - httpClient: HTTP client used for calls
+//
+// Returns:
+//   - *Client: Connect client
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - No connection setup until the first call
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// WithHeader returns a copy of the client that sends key: value with every
+// call, e.g. an internal token.
+//
+// Parameters:
+//   - key: Header name
+//   - value: Header value
+//
+// Returns:
+//   - *Client: Client sending the header
+//
+// Concurrency:
+//   - Safe for concurrent use; the original client is not modified
+//
+// Performance:
+//   - Copies the client's headers
+func (c *Client) WithHeader(key, value string) *Client {
+	clone := *c
+	clone.header = c.header.Clone()
+	if clone.header == nil {
+		clone.header = http.Header{}
+	}
+	clone.header.Set(key, value)
+	return &clone
+}
+
+// Unary calls a unary procedure, decoding the response into resp.
+//
+// Parameters:
+//   - ctx: Call context
+//   - procedure: Full procedure name (e.g., "greet.v1.GreeterService/SayHello")
+//   - req: Request message, encoded as JSON
+//   - resp: Response message to decode into (may be nil)
+//
+// Returns:
+//   - error: *RPCError for service errors, or a transport error
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - One HTTP round trip
+func (c *Client) Unary(ctx context.Context, procedure string, req, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := c.newRequest(ctx, procedure, "application/json", body)
+	if err != nil {
+		return err
+	}
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", procedure, err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return decodeError(httpResp.StatusCode, data)
+	}
+	if resp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// ServerStream calls a server-streaming procedure, passing each response
+// message to onMessage.
+//
+// Parameters:
+//   - ctx: Call context
+//   - procedure: Full procedure name
+//   - req: Request message, encoded as JSON
+//   - onMessage: Called with each raw JSON response message
+//
+// Returns:
+//   - error: *RPCError from the end of the stream, or a transport error
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Messages are decoded as they arrive
+func (c *Client) ServerStream(ctx context.Context, procedure string, req any, onMessage func(json.RawMessage) error) error {
+	msg, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := c.newRequest(ctx, procedure, "application/connect+json", envelope(0, msg))
+	if err != nil {
+		return err
+	}
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", procedure, err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(httpResp.Body)
+		return decodeError(httpResp.StatusCode, data)
+	}
+
+	for {
+		flags, data, err := readEnvelope(httpResp.Body)
+		if err == io.EOF {
+			return fmt.Errorf("stream ended without end-of-stream message")
+		}
+		if err != nil {
+			return err
+		}
+		if flags&flagCompressed != 0 {
+			return fmt.Errorf("compressed stream messages are not supported")
+		}
+		if flags&flagEndStream != 0 {
+			var end struct {
+				Error *RPCError `json:"error"`
+			}
+			if err := json.Unmarshal(data, &end); err != nil {
+				return fmt.Errorf("failed to decode end of stream: %w", err)
+			}
+			if end.Error != nil {
+				return end.Error
+			}
+			return nil
+		}
+		if err := onMessage(json.RawMessage(data)); err != nil {
+			return err
+		}
+	}
+}
+
+// newRequest builds a Connect POST request for procedure.
+func (c *Client) newRequest(ctx context.Context, procedure, contentType string, body []byte) (*http.Request, error) {
+	url := c.baseURL + "/" + strings.TrimPrefix(procedure, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Connect-Protocol-Version", "1")
+	return req, nil
+}
+
+// decodeError converts a non-200 Connect response into an RPCError.
+func decodeError(status int, body []byte) error {
+	var rpcErr RPCError
+	if err := json.Unmarshal(body, &rpcErr); err != nil || rpcErr.Code == "" {
+		return fmt.Errorf("unexpected HTTP status %d: %s", status, strings.TrimSpace(string(body)))
+	}
+	return &rpcErr
+}
+
+// envelope frames msg for the Connect streaming protocol.
+func envelope(flags byte, msg []byte) []byte {
+	buf := make([]byte, 5+len(msg))
+	buf[0] = flags
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(msg)))
+	copy(buf[5:], msg)
+	return buf
+}
+
+// readEnvelope reads one enveloped message, returning io.EOF at a clean
+// end of the body.
+func readEnvelope(r io.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return 0, nil, io.EOF
+		}
+		return 0, nil, fmt.Errorf("failed to read stream message: %w", err)
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, fmt.Errorf("failed to read stream message: %w", err)
+	}
+	return header[0], data, nil
+}
@@ -0,0 +1,284 @@
+// Package connecttest provides integration checks for deployed egg services.
+// It backs `egg test` and the examples' connect-tester.
+//
+// Overview:
+//   - Responsibility: Run health, RPC and metrics checks against a running service
+//   - Key Types: Options, Endpoints, Result, Report, Client
+//   - Concurrency Model: Checks run sequentially; a Report is built by one run
+//   - Error Semantics: Check failures are recorded in the Report, not returned
+//   - Performance Notes: One HTTP request per check, bounded by a per-request timeout
+//
+// Usage:
+//
+//	endpoints := connecttest.EndpointsAt("localhost", 8082, 8083, 9092)
+//	report, err := connecttest.Run(ctx, connecttest.Options{
+//		Service:   "user",
+//		Endpoints: endpoints,
+//		Suites:    []string{connecttest.SuiteHealth, connecttest.SuiteCRUD, connecttest.SuiteMetrics},
+//	})
+package connecttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Suite names accepted in Options.Suites.
+const (
+	SuiteHealth  = "health"  // Health endpoint responds with 200
+	SuiteGreet   = "greet"   // greet.v1.GreeterService unary and streaming calls
+	SuiteCRUD    = "crud"    // user.v1.UserService create, read, update, delete and list
+	SuiteAdmin   = "admin"   // user.v1.UserService admin calls; deletes all users
+	SuiteMetrics = "metrics" // Prometheus endpoint exposes service and RPC metrics
+)
+
+// suiteOrder is the order suites run in, so metrics see the RPC suites'
+// traffic.
+var suiteOrder = []string{SuiteHealth, SuiteGreet, SuiteCRUD, SuiteAdmin, SuiteMetrics}
+
+// DefaultSuites are run when Options.Suites is empty.
+var DefaultSuites = []string{SuiteHealth, SuiteMetrics}
+
+// defaultTimeout bounds each request unless Options.Timeout is set.
+const defaultTimeout = 10 * time.Second
+
+// Endpoints are the URLs a service is tested at.
+type Endpoints struct {
+	BaseURL    string `json:"base_url"`    // Connect endpoint (e.g., http://localhost:8080)
+	HealthURL  string `json:"health_url"`  // Health check (e.g., http://localhost:8081/health)
+	MetricsURL string `json:"metrics_url"` // Prometheus metrics (e.g., http://localhost:9091/metrics)
+}
+
+// Options configures a test run.
+type Options struct {
+	Service   string        // Service name, expected as service_name in target_info
+	Endpoints Endpoints     // Where the service is reachable
+	Suites    []string      // Suites to run (default: DefaultSuites)
+	Timeout   time.Duration // Per-request timeout (default: 10s)
+
+	// InternalToken is sent as X-Internal-Token by the admin suite's
+	// authorized calls. Without it those checks fail.
+	InternalToken string
+
+	// OnResult, when set, is called after each check, e.g. to print
+	// progress.
+	OnResult func(Result)
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Suite      string        `json:"suite"`
+	Name       string        `json:"name"`
+	Passed     bool          `json:"passed"`
+	Skipped    bool          `json:"skipped,omitempty"`
+	Duration   time.Duration `json:"-"`
+	DurationMS int64         `json:"duration_ms"`
+	Error      string        `json:"error,omitempty"`
+	Details    string        `json:"details,omitempty"`
+}
+
+// Report aggregates the results of a test run.
+type Report struct {
+	Service    string    `json:"service"`
+	Endpoints  Endpoints `json:"endpoints"`
+	Suites     []string  `json:"suites"`
+	Results    []Result  `json:"results"`
+	Total      int       `json:"total"`
+	Passed     int       `json:"passed"`
+	Failed     int       `json:"failed"`
+	Skipped    int       `json:"skipped"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// Success reports whether no check failed.
+func (r *Report) Success() bool {
+	return r.Failed == 0
+}
+
+// EndpointsAt returns the endpoints of a service serving its HTTP, health
+// and metrics ports on host, as egg services do.
+//
+// Parameters:
+//   - host: Host the service ports are reachable on (e.g., localhost)
+//   - httpPort: Connect port
+//   - healthPort: Health check port, serving /health
+//   - metricsPort: Prometheus port, serving /metrics
+//
+// Returns:
+//   - Endpoints: Service endpoints
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1)
+func EndpointsAt(host string, httpPort, healthPort, metricsPort int) Endpoints {
+	return Endpoints{
+		BaseURL:    hostURL(host, httpPort, ""),
+		HealthURL:  hostURL(host, healthPort, "/health"),
+		MetricsURL: hostURL(host, metricsPort, "/metrics"),
+	}
+}
+
+// hostURL builds an http URL for host, port and path.
+func hostURL(host string, port int, path string) string {
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+	return "http://" + host + ":" + strconv.Itoa(port) + path
+}
+
+// Run runs the configured suites and returns their report.
+//
+// Parameters:
+//   - ctx: Context bounding the whole run
+//   - opts: Run options
+//
+// Returns:
+//   - *Report: Results of all checks
+//   - error: Error if the options are invalid; failed checks are not errors
+//
+// Concurrency:
+//   - Checks run sequentially
+//
+// Performance:
+//   - Dominated by service response times
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	suites, err := normalizeSuites(opts.Suites)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	httpClient := &http.Client{Timeout: opts.Timeout}
+	r := &runner{
+		ctx:    ctx,
+		opts:   opts,
+		http:   httpClient,
+		client: NewClient(opts.Endpoints.BaseURL, httpClient),
+		report: &Report{
+			Service:   opts.Service,
+			Endpoints: opts.Endpoints,
+			Suites:    suites,
+			Results:   []Result{},
+		},
+	}
+
+	start := time.Now()
+	for _, suite := range suites {
+		r.suite = suite
+		switch suite {
+		case SuiteHealth:
+			r.runHealth()
+		case SuiteGreet:
+			r.runGreet()
+		case SuiteCRUD:
+			r.runCRUD()
+		case SuiteAdmin:
+			r.runAdmin()
+		case SuiteMetrics:
+			r.runMetrics()
+		}
+	}
+	r.report.DurationMS = time.Since(start).Milliseconds()
+	return r.report, nil
+}
+
+// normalizeSuites validates suite names, removes duplicates and sorts them
+// into run order.
+func normalizeSuites(names []string) ([]string, error) {
+	if len(names) == 0 {
+		names = DefaultSuites
+	}
+
+	requested := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, suite := range suiteOrder {
+			if name == suite {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown test suite %q (available: %s)", name, strings.Join(suiteOrder, ", "))
+		}
+		requested[name] = true
+	}
+
+	var suites []string
+	for _, suite := range suiteOrder {
+		if requested[suite] {
+			suites = append(suites, suite)
+		}
+	}
+	return suites, nil
+}
+
+// runner holds the state of one Run.
+type runner struct {
+	ctx    context.Context
+	opts   Options
+	http   *http.Client
+	client *Client
+	report *Report
+	suite  string // Suite currently running
+	calls  int    // RPCs made so far, expected in the metrics
+}
+
+// record adds the result of a check that started at start.
+func (r *runner) record(name string, start time.Time, err error, details string) {
+	duration := time.Since(start)
+	result := Result{
+		Suite:      r.suite,
+		Name:       name,
+		Passed:     err == nil,
+		Duration:   duration,
+		DurationMS: duration.Milliseconds(),
+		Details:    details,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	r.add(result)
+}
+
+// skip adds a check that did not apply, e.g. an optional metric that is
+// not enabled.
+func (r *runner) skip(name, reason string) {
+	r.add(Result{Suite: r.suite, Name: name, Skipped: true, Details: reason})
+}
+
+// add appends result to the report and updates its counts.
+func (r *runner) add(result Result) {
+	r.report.Results = append(r.report.Results, result)
+	r.report.Total++
+	switch {
+	case result.Skipped:
+		r.report.Skipped++
+	case result.Passed:
+		r.report.Passed++
+	default:
+		r.report.Failed++
+	}
+	if r.opts.OnResult != nil {
+		r.opts.OnResult(result)
+	}
+}
+
+// runHealth checks that the health endpoint responds with 200.
+func (r *runner) runHealth() {
+	start := time.Now()
+	status, _, err := r.get(r.opts.Endpoints.HealthURL)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("expected status 200, got %d", status)
+	}
+	r.record("Health", start, err, r.opts.Endpoints.HealthURL)
+}
@@ -0,0 +1,382 @@
+package connecttest
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testToken is the internal token the fake service accepts.
+const testToken = "test-internal-token"
+
+// fakeService serves the greet and user procedures over the Connect JSON
+// protocol, plus health and metrics endpoints.
+type fakeService struct {
+	mu       sync.Mutex
+	users    map[string]user
+	nextID   int
+	requests int
+}
+
+func (s *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/health":
+		w.WriteHeader(http.StatusOK)
+		return
+	case "/metrics":
+		s.mu.Lock()
+		requests := s.requests
+		s.mu.Unlock()
+		fmt.Fprintf(w, "# HELP target_info Target metadata\n# TYPE target_info gauge\n")
+		fmt.Fprintf(w, "target_info{service_name=\"user\",service_version=\"1.0.0\"} 1\n")
+		fmt.Fprintf(w, "rpc_requests_total{rpc_method=\"GetUser\"} %d\n", requests)
+		for _, series := range []string{"rpc_request_duration_seconds_bucket", "rpc_request_size_bytes_bucket", "rpc_response_size_bytes_bucket"} {
+			fmt.Fprintf(w, "%s{le=\"+Inf\"} %d\n", series, requests)
+		}
+		fmt.Fprintf(w, "process_uptime_seconds 12.5\n")
+		return
+	}
+
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	if r.URL.Path == "/"+procSayHelloStream {
+		s.serveStream(w, r)
+		return
+	}
+
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeConnectError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	resp, code := s.handle(r.URL.Path[1:], req, r.Header.Get(internalTokenHeader) == testToken)
+	if code != "" {
+		writeConnectError(w, http.StatusNotFound, code, "request failed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handle runs a unary procedure, returning the response or an error code.
+func (s *fakeService) handle(procedure string, req map[string]any, authorized bool) (any, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	str := func(key string) string { v, _ := req[key].(string); return v }
+	switch procedure {
+	case procSayHello:
+		return map[string]any{"message": "Hello, " + str("name")}, ""
+	case procCreateUser:
+		if !strings.Contains(str("email"), "@") || str("name") == "" {
+			return nil, "invalid_argument"
+		}
+		s.nextID++
+		u := user{ID: fmt.Sprintf("u-%d", s.nextID), Email: str("email"), Name: str("name")}
+		s.users[u.ID] = u
+		return map[string]any{"user": u}, ""
+	case procGetUser:
+		u, ok := s.users[str("id")]
+		if !ok {
+			return nil, "not_found"
+		}
+		return map[string]any{"user": u}, ""
+	case procUpdateUser:
+		if _, ok := s.users[str("id")]; !ok {
+			return nil, "not_found"
+		}
+		for _, other := range s.users {
+			if other.ID != str("id") && other.Email == str("email") {
+				return nil, "already_exists"
+			}
+		}
+		u := user{ID: str("id"), Email: str("email"), Name: str("name")}
+		s.users[u.ID] = u
+		return map[string]any{"user": u}, ""
+	case procDeleteUser:
+		if _, ok := s.users[str("id")]; !ok {
+			return nil, "not_found"
+		}
+		delete(s.users, str("id"))
+		return map[string]any{"success": true}, ""
+	case procListUsers:
+		page, _ := req["page"].(float64)
+		size, _ := req["pageSize"].(float64)
+		if page < 1 {
+			page = 1
+		}
+		if size < 1 {
+			size = 10
+		}
+		if size > 100 {
+			size = 100
+		}
+		users := []user{}
+		for _, u := range s.users {
+			users = append(users, u)
+		}
+		return map[string]any{"users": users, "total": len(users), "page": page, "pageSize": size}, ""
+	case procResetAllUsers:
+		if !authorized {
+			return nil, "unauthenticated"
+		}
+		if confirm, _ := req["confirm"].(bool); !confirm {
+			return nil, "invalid_argument"
+		}
+		deleted := len(s.users)
+		s.users = map[string]user{}
+		return map[string]any{"deletedCount": deleted, "success": true}, ""
+	case procValidateToken:
+		return map[string]any{"valid": true, "message": "Hello, internal"}, ""
+	default:
+		return nil, "unimplemented"
+	}
+}
+
+// serveStream answers SayHelloStream with count messages (5 for zero).
+func (s *fakeService) serveStream(w http.ResponseWriter, r *http.Request) {
+	var header [5]byte
+	io.ReadFull(r.Body, header[:])
+	body := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	io.ReadFull(r.Body, body)
+	var req struct {
+		Count int `json:"count"`
+	}
+	json.Unmarshal(body, &req)
+	if req.Count == 0 {
+		req.Count = 5
+	}
+
+	w.Header().Set("Content-Type", "application/connect+json")
+	for i := 1; i <= req.Count; i++ {
+		msg, _ := json.Marshal(map[string]any{"message": "Hello", "sequence": i})
+		w.Write(envelope(0, msg))
+	}
+	w.Write(envelope(flagEndStream, []byte("{}")))
+}
+
+func writeConnectError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(RPCError{Code: code, Message: message})
+}
+
+// newFakeServer starts a fake service and returns its endpoints.
+func newFakeServer(t *testing.T) Endpoints {
+	t.Helper()
+	srv := httptest.NewServer(&fakeService{users: map[string]user{}})
+	t.Cleanup(srv.Close)
+	return Endpoints{
+		BaseURL:    srv.URL,
+		HealthURL:  srv.URL + "/health",
+		MetricsURL: srv.URL + "/metrics",
+	}
+}
+
+func TestRun_AllSuites(t *testing.T) {
+	endpoints := newFakeServer(t)
+
+	var streamed []string
+	report, err := Run(context.Background(), Options{
+		Service:       "user",
+		Endpoints:     endpoints,
+		Suites:        []string{"metrics", "admin", "crud", "greet", "health"},
+		InternalToken: testToken,
+		OnResult:      func(r Result) { streamed = append(streamed, r.Name) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, r := range report.Results {
+		if !r.Passed && !r.Skipped {
+			t.Errorf("%s/%s failed: %s", r.Suite, r.Name, r.Error)
+		}
+	}
+	if !report.Success() {
+		t.Fatalf("Expected success, got %d failures", report.Failed)
+	}
+	if want := []string{SuiteHealth, SuiteGreet, SuiteCRUD, SuiteAdmin, SuiteMetrics}; strings.Join(report.Suites, ",") != strings.Join(want, ",") {
+		t.Errorf("Suites = %v, want %v", report.Suites, want)
+	}
+	if len(streamed) != report.Total {
+		t.Errorf("OnResult called %d times, want %d", len(streamed), report.Total)
+	}
+	if report.Skipped != 2 {
+		t.Errorf("Expected runtime and database metrics to be skipped, got %d skipped", report.Skipped)
+	}
+}
+
+func TestRun_AdminWithoutToken(t *testing.T) {
+	report, err := Run(context.Background(), Options{
+		Endpoints: newFakeServer(t),
+		Suites:    []string{SuiteAdmin},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	results := map[string]Result{}
+	for _, r := range report.Results {
+		results[r.Name] = r
+	}
+	if !results["AdminResetAllUsers_NoToken"].Passed {
+		t.Errorf("Unauthorized reset should be rejected: %+v", results["AdminResetAllUsers_NoToken"])
+	}
+	if r := results["AdminResetAllUsers_WithToken"]; r.Passed || !strings.Contains(r.Error, "internal token not set") {
+		t.Errorf("Expected failure without internal token, got %+v", r)
+	}
+	if _, ok := results["AdminResetAllUsers_NoConfirm"]; ok {
+		t.Error("Confirm check needs the internal token and should not run without it")
+	}
+}
+
+func TestClient_WithHeader(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(internalTokenHeader))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{}")
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, srv.Client())
+	authorized := client.WithHeader(internalTokenHeader, testToken)
+	for _, c := range []*Client{authorized, client} {
+		if err := c.Unary(context.Background(), procGetUser, map[string]any{}, nil); err != nil {
+			t.Fatalf("Unary() error = %v", err)
+		}
+	}
+	if strings.Join(got, ",") != testToken+"," {
+		t.Errorf("Headers = %q, want the token only on the derived client", got)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			fmt.Fprintln(w, "# TYPE target_info gauge")
+			fmt.Fprintln(w, `target_info{service_name="other"} 1`)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	report, err := Run(context.Background(), Options{
+		Service: "user",
+		Endpoints: Endpoints{
+			BaseURL:    srv.URL,
+			HealthURL:  srv.URL + "/health",
+			MetricsURL: srv.URL + "/metrics",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Success() {
+		t.Fatal("Expected failures")
+	}
+
+	failed := map[string]string{}
+	for _, r := range report.Results {
+		if !r.Passed && !r.Skipped {
+			failed[r.Name] = r.Error
+		}
+	}
+	if !strings.Contains(failed["Health"], "503") {
+		t.Errorf("Expected Health failure with status, got %q", failed["Health"])
+	}
+	if !strings.Contains(failed["Metrics_TargetInfo"], `service_name="user"`) {
+		t.Errorf("Expected target_info failure, got %q", failed["Metrics_TargetInfo"])
+	}
+	if _, ok := failed["Metrics_RPC_rpc_requests_total"]; ok {
+		t.Error("Missing RPC metrics should be skipped without RPC suites")
+	}
+}
+
+func TestRun_UnknownSuite(t *testing.T) {
+	_, err := Run(context.Background(), Options{Suites: []string{"load"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown test suite "load"`) {
+		t.Errorf("Expected unknown suite error, got %v", err)
+	}
+}
+
+func TestClient_ServerStreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/connect+json")
+		w.Write(envelope(0, []byte(`{"message":"one"}`)))
+		w.Write(envelope(flagEndStream, []byte(`{"error":{"code":"resource_exhausted","message":"slow down"}}`)))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, srv.Client())
+	var messages int
+	err := client.ServerStream(context.Background(), procSayHelloStream, map[string]any{}, func(json.RawMessage) error {
+		messages++
+		return nil
+	})
+	if CodeOf(err) != "resource_exhausted" {
+		t.Errorf("Expected resource_exhausted, got %v", err)
+	}
+	if messages != 1 {
+		t.Errorf("Expected 1 message before the error, got %d", messages)
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	body := `# HELP rpc_requests_total Requests
+# TYPE rpc_requests_total counter
+rpc_requests_total{rpc_method="Get",rpc_code="ok"} 3
+rpc_requests_total{rpc_method="List",rpc_code="ok"} 2 1700000000000
+rpc_requests_total{rpc_method="Get",rpc_code="not_found"} 1
+target_info{service_name="user",description="a, \"quoted\" value"} 1
+process_uptime_seconds 12.5
+malformed
+`
+	metrics := ParseMetrics(body)
+
+	if total, ok := SumMetric(metrics, "rpc_requests_total", nil); !ok || total != 6 {
+		t.Errorf("SumMetric(all) = %v, %v; want 6, true", total, ok)
+	}
+	if total, _ := SumMetric(metrics, "rpc_requests_total", map[string]string{"rpc_method": "Get"}); total != 4 {
+		t.Errorf("SumMetric(Get) = %v, want 4", total)
+	}
+	if _, ok := SumMetric(metrics, "rpc_requests_total", map[string]string{"rpc_method": "Delete"}); ok {
+		t.Error("SumMetric should report no match for unknown labels")
+	}
+	if got := metrics["target_info"][0].Labels["description"]; got != `a, "quoted" value` {
+		t.Errorf("Quoted label = %q", got)
+	}
+	if got := metrics["process_uptime_seconds"][0].Value; got != 12.5 {
+		t.Errorf("Unlabeled value = %v, want 12.5", got)
+	}
+	if _, ok := metrics["malformed"]; ok {
+		t.Error("Malformed lines should be skipped")
+	}
+}
+
+func TestEndpointsAt(t *testing.T) {
+	endpoints := EndpointsAt("10.0.0.12", 8082, 8083, 9092)
+	want := Endpoints{
+		BaseURL:    "http://10.0.0.12:8082",
+		HealthURL:  "http://10.0.0.12:8083/health",
+		MetricsURL: "http://10.0.0.12:9092/metrics",
+	}
+	if endpoints != want {
+		t.Errorf("Endpoints = %+v, want %+v", endpoints, want)
+	}
+
+	if endpoints := EndpointsAt("::1", 8080, 8081, 9091); endpoints.BaseURL != "http://[::1]:8080" {
+		t.Errorf("Expected bracketed IPv6 host, got %s", endpoints.BaseURL)
+	}
+}
@@ -0,0 +1,295 @@
+package connecttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricSample is a single sample of a Prometheus text exposition.
+type MetricSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// rpcMetrics are the series obsx exports for every RPC, keyed by display
+// name. Histograms are checked by their _bucket series.
+var rpcMetrics = []struct {
+	name   string
+	series string
+}{
+	{"rpc_requests_total", "rpc_requests_total"},
+	{"rpc_request_duration_seconds", "rpc_request_duration_seconds_bucket"},
+	{"rpc_request_size_bytes", "rpc_request_size_bytes_bucket"},
+	{"rpc_response_size_bytes", "rpc_response_size_bytes_bucket"},
+}
+
+// Optional metric groups, reported as skipped when the service does not
+// enable them.
+var (
+	runtimeMetrics = []string{
+		"process_runtime_go_goroutines",
+		"process_runtime_go_gc_count_total",
+		"process_runtime_go_memory_heap_bytes",
+		"process_runtime_go_memory_stack_bytes",
+	}
+	processMetrics = []string{
+		"process_start_time_seconds",
+		"process_uptime_seconds",
+		"process_memory_rss_bytes",
+		"process_cpu_seconds_total",
+	}
+	databaseMetrics = []string{
+		"db_pool_open_connections",
+		"db_pool_in_use",
+		"db_pool_idle",
+		"db_pool_max_open",
+		"db_pool_wait_count_total",
+	}
+)
+
+// ParseMetrics parses the Prometheus text format into samples grouped by
+// series name. Comments and malformed lines are skipped.
+//
+// Parameters:
+//   - body: Prometheus text format response body
+//
+// Returns:
+//   - map[string][]MetricSample: Samples grouped by series name
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single pass over the body
+func ParseMetrics(body string) map[string][]MetricSample {
+	metrics := make(map[string][]MetricSample)
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// name{label="value",...} value [timestamp]
+		series, rest := line, ""
+		if idx := strings.LastIndex(line, "}"); idx > 0 && strings.Contains(line[:idx], "{") {
+			series, rest = line[:idx+1], line[idx+1:]
+		} else if fields := strings.Fields(line); len(fields) >= 2 {
+			series, rest = fields[0], strings.Join(fields[1:], " ")
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+
+		sample := MetricSample{Name: series, Labels: map[string]string{}, Value: value}
+		if idx := strings.Index(series, "{"); idx > 0 {
+			sample.Name = series[:idx]
+			sample.Labels = parseLabels(series[idx+1 : len(series)-1])
+		}
+		metrics[sample.Name] = append(metrics[sample.Name], sample)
+	}
+	return metrics
+}
+
+// parseLabels parses the comma-separated label pairs of a series.
+func parseLabels(s string) map[string]string {
+	labels := make(map[string]string)
+	for s != "" {
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(s[:eq], ","))
+		s = strings.TrimSpace(s[eq+1:])
+		if !strings.HasPrefix(s, `"`) {
+			break
+		}
+
+		// Find the closing quote, skipping escaped characters
+		var value strings.Builder
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				if s[i] == 'n' {
+					value.WriteByte('\n')
+					continue
+				}
+			}
+			value.WriteByte(s[i])
+		}
+		labels[key] = value.String()
+		if i >= len(s) {
+			break
+		}
+		s = strings.TrimSpace(s[i+1:])
+	}
+	return labels
+}
+
+// SumMetric sums the samples of name whose labels include all of filters.
+//
+// Parameters:
+//   - metrics: Parsed metrics
+//   - name: Series name
+//   - filters: Labels that must match (may be nil)
+//
+// Returns:
+//   - float64: Sum of matching samples
+//   - bool: True if at least one sample matched
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of samples of name
+func SumMetric(metrics map[string][]MetricSample, name string, filters map[string]string) (float64, bool) {
+	var total float64
+	found := false
+	for _, sample := range metrics[name] {
+		matches := true
+		for key, want := range filters {
+			if sample.Labels[key] != want {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			total += sample.Value
+			found = true
+		}
+	}
+	return total, found
+}
+
+// get fetches url, returning the status and body.
+func (r *runner) get(url string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// runMetrics checks the Prometheus endpoint: its format, the service's
+// target_info, the RPC metrics and the request count of the RPC suites.
+func (r *runner) runMetrics() {
+	start := time.Now()
+	status, body, err := r.get(r.opts.Endpoints.MetricsURL)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("expected status 200, got %d", status)
+	}
+	text := string(body)
+	if err == nil && !strings.Contains(text, "# HELP") && !strings.Contains(text, "# TYPE") {
+		err = fmt.Errorf("response is not in Prometheus text format")
+	}
+	if err != nil {
+		r.record("Metrics_Endpoint", start, err, r.opts.Endpoints.MetricsURL)
+		return
+	}
+
+	metrics := ParseMetrics(text)
+	r.record("Metrics_Endpoint", start, nil, fmt.Sprintf("%d samples", countSamples(metrics)))
+
+	r.checkTargetInfo(metrics)
+	r.checkRPCMetrics(metrics)
+	r.checkOptional("Metrics_Runtime", metrics, runtimeMetrics)
+	r.checkOptional("Metrics_Process", metrics, processMetrics)
+	r.checkOptional("Metrics_Database", metrics, databaseMetrics)
+}
+
+// checkTargetInfo verifies target_info names the service.
+func (r *runner) checkTargetInfo(metrics map[string][]MetricSample) {
+	start := time.Now()
+	if _, ok := metrics["target_info"]; !ok {
+		r.record("Metrics_TargetInfo", start, fmt.Errorf("target_info metric not found"), "")
+		return
+	}
+	if r.opts.Service == "" {
+		r.record("Metrics_TargetInfo", start, nil, "")
+		return
+	}
+	if _, ok := SumMetric(metrics, "target_info", map[string]string{"service_name": r.opts.Service}); !ok {
+		r.record("Metrics_TargetInfo", start,
+			fmt.Errorf("target_info does not contain service_name=%q", r.opts.Service), "")
+		return
+	}
+	r.record("Metrics_TargetInfo", start, nil, "service_name="+r.opts.Service)
+}
+
+// checkRPCMetrics verifies the RPC series exist and count at least the
+// calls made by the RPC suites. Without RPC suites, missing series are
+// skipped since the service may not have served any RPC yet.
+func (r *runner) checkRPCMetrics(metrics map[string][]MetricSample) {
+	for _, m := range rpcMetrics {
+		start := time.Now()
+		name := "Metrics_RPC_" + m.name
+		_, ok := metrics[m.series]
+		switch {
+		case ok:
+			r.record(name, start, nil, "metric exists")
+		case r.calls == 0:
+			r.skip(name, "no RPCs served yet")
+		default:
+			r.record(name, start, fmt.Errorf("metric %s not found", m.name), "")
+		}
+	}
+
+	if r.calls == 0 {
+		return
+	}
+	start := time.Now()
+	total, _ := SumMetric(metrics, "rpc_requests_total", nil)
+	var err error
+	if int(total) < r.calls {
+		err = fmt.Errorf("expected at least %d requests, got %d", r.calls, int(total))
+	}
+	r.record("Metrics_RPC_Count", start, err, fmt.Sprintf("requests=%d (expected>=%d)", int(total), r.calls))
+}
+
+// checkOptional records which of an optional metric group are exported,
+// skipping the group when none are.
+func (r *runner) checkOptional(name string, metrics map[string][]MetricSample, series []string) {
+	start := time.Now()
+	var found []string
+	for _, s := range series {
+		if _, ok := metrics[s]; ok {
+			found = append(found, s)
+		}
+	}
+	if len(found) == 0 {
+		r.skip(name, "not enabled")
+		return
+	}
+	sort.Strings(found)
+	r.record(name, start, nil, fmt.Sprintf("%d/%d metrics: %s", len(found), len(series), strings.Join(found, ", ")))
+}
+
+// countSamples returns the number of samples across all series.
+func countSamples(metrics map[string][]MetricSample) int {
+	n := 0
+	for _, samples := range metrics {
+		n += len(samples)
+	}
+	return n
+}
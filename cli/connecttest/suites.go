@@ -0,0 +1,320 @@
+package connecttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Procedures of the example services the RPC suites exercise.
+const (
+	procSayHello       = "greet.v1.GreeterService/SayHello"
+	procSayHelloStream = "greet.v1.GreeterService/SayHelloStream"
+	procCreateUser     = "user.v1.UserService/CreateUser"
+	procGetUser        = "user.v1.UserService/GetUser"
+	procUpdateUser     = "user.v1.UserService/UpdateUser"
+	procDeleteUser     = "user.v1.UserService/DeleteUser"
+	procListUsers      = "user.v1.UserService/ListUsers"
+	procResetAllUsers  = "user.v1.UserService/AdminResetAllUsers"
+	procValidateToken  = "user.v1.UserService/ValidateInternalToken"
+)
+
+// internalTokenHeader carries Options.InternalToken, as clientx sends it.
+const internalTokenHeader = "X-Internal-Token"
+
+// user is the JSON form of user.v1.User.
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// userResponse is the JSON form of the Create, Get and Update responses.
+type userResponse struct {
+	User *user `json:"user"`
+}
+
+// listUsersResponse is the JSON form of user.v1.ListUsersResponse.
+type listUsersResponse struct {
+	Users    []user `json:"users"`
+	Total    int32  `json:"total"`
+	Page     int32  `json:"page"`
+	PageSize int32  `json:"pageSize"`
+}
+
+// unary calls a unary procedure, counting it for the metrics suite.
+func (r *runner) unary(procedure string, req, resp any) error {
+	return r.unaryWith(r.client, procedure, req, resp)
+}
+
+// unaryWith calls a unary procedure through client, counting it for the
+// metrics suite.
+func (r *runner) unaryWith(client *Client, procedure string, req, resp any) error {
+	r.calls++
+	return client.Unary(r.ctx, procedure, req, resp)
+}
+
+// runGreet exercises greet.v1.GreeterService.
+func (r *runner) runGreet() {
+	for _, lang := range []string{"en", "es", "fr", "de", "zh"} {
+		r.sayHello("SayHello_"+lang, "World", lang)
+	}
+	r.sayHello("SayHello_EmptyName", "", "en")
+
+	for _, count := range []int32{1, 3, 5, 10} {
+		r.sayHelloStream(fmt.Sprintf("SayHelloStream_%d", count), count)
+	}
+	r.sayHelloStream("SayHelloStream_ZeroCount", 0)
+}
+
+// sayHello records a SayHello call.
+func (r *runner) sayHello(name, who, lang string) {
+	start := time.Now()
+	var resp struct {
+		Message string `json:"message"`
+	}
+	err := r.unary(procSayHello, map[string]any{"name": who, "language": lang}, &resp)
+	if err == nil && resp.Message == "" {
+		err = fmt.Errorf("empty greeting")
+	}
+	r.record(name, start, err, resp.Message)
+}
+
+// sayHelloStream records a SayHelloStream call, requiring count messages
+// when count is positive and at least one otherwise.
+func (r *runner) sayHelloStream(name string, count int32) {
+	start := time.Now()
+	r.calls++
+	received := 0
+	err := r.client.ServerStream(r.ctx, procSayHelloStream,
+		map[string]any{"name": "Tester", "count": count},
+		func(json.RawMessage) error {
+			received++
+			return nil
+		})
+	if err == nil {
+		switch {
+		case count > 0 && received != int(count):
+			err = fmt.Errorf("expected %d messages, got %d", count, received)
+		case received == 0:
+			err = fmt.Errorf("expected at least one message")
+		}
+	}
+	r.record(name, start, err, fmt.Sprintf("%d messages", received))
+}
+
+// runCRUD exercises user.v1.UserService and deletes the users it creates.
+func (r *runner) runCRUD() {
+	var created []string
+	for i := 1; i <= 3; i++ {
+		if id := r.createUser(fmt.Sprintf("CreateUser_%d", i), i); id != "" {
+			created = append(created, id)
+		}
+	}
+
+	if len(created) > 0 {
+		id := created[0]
+		r.getUser("GetUser", id)
+		r.updateUser("UpdateUser", id)
+	}
+	if len(created) > 1 {
+		r.updateDuplicateEmail("UpdateUser_DuplicateEmail", created[0], created[1])
+	}
+
+	r.listUsers("ListUsers_Page1_Size10", 1, 10, nil)
+	r.listUsers("ListUsers_Page1_Size5", 1, 5, nil)
+	r.listUsers("ListUsers_InvalidPage", 0, 10, func(resp listUsersResponse) error {
+		if resp.Page != 1 {
+			return fmt.Errorf("expected page to normalize to 1, got %d", resp.Page)
+		}
+		return nil
+	})
+	r.listUsers("ListUsers_InvalidPageSize", 1, 0, func(resp listUsersResponse) error {
+		if resp.PageSize < 1 || resp.PageSize > 100 {
+			return fmt.Errorf("expected page size to normalize into 1..100, got %d", resp.PageSize)
+		}
+		return nil
+	})
+	r.listUsers("ListUsers_LargePageSize", 1, 200, func(resp listUsersResponse) error {
+		if resp.PageSize > 100 {
+			return fmt.Errorf("expected page size to be capped at 100, got %d", resp.PageSize)
+		}
+		return nil
+	})
+
+	if len(created) > 0 {
+		r.deleteUser("DeleteUser", created[0])
+		r.expectError("GetUser_Deleted", procGetUser, map[string]any{"id": created[0]})
+	}
+
+	r.expectError("GetUser_NonExistent", procGetUser, map[string]any{"id": "non-existent-id"})
+	r.expectError("GetUser_EmptyID", procGetUser, map[string]any{"id": ""})
+	r.expectError("CreateUser_EmptyEmail", procCreateUser, map[string]any{"email": "", "name": "Test User"})
+	r.expectError("CreateUser_EmptyName", procCreateUser, map[string]any{"email": "empty-name@example.com", "name": ""})
+	r.expectError("CreateUser_InvalidEmail", procCreateUser, map[string]any{"email": "not-an-email", "name": "Test User"})
+	r.expectError("UpdateUser_NonExistent", procUpdateUser, map[string]any{
+		"id": "non-existent-update-id", "email": "update@example.com", "name": "Update Test",
+	})
+	r.expectError("DeleteUser_NonExistent", procDeleteUser, map[string]any{"id": "non-existent-delete-id"})
+
+	// Leave a deployed service as it was found
+	for _, id := range created[min(1, len(created)):] {
+		_ = r.unary(procDeleteUser, map[string]any{"id": id}, nil)
+	}
+}
+
+// createUser records a CreateUser call and returns the new user's ID.
+func (r *runner) createUser(name string, i int) string {
+	start := time.Now()
+	var resp userResponse
+	err := r.unary(procCreateUser, map[string]any{
+		"email": fmt.Sprintf("egg-test-%d-%d@example.com", time.Now().UnixNano(), i),
+		"name":  fmt.Sprintf("Test User %d", i),
+	}, &resp)
+	if err == nil && (resp.User == nil || resp.User.ID == "") {
+		err = fmt.Errorf("response has no user ID")
+	}
+	if err != nil {
+		r.record(name, start, err, "")
+		return ""
+	}
+	r.record(name, start, nil, resp.User.ID)
+	return resp.User.ID
+}
+
+// getUser records a GetUser call for id.
+func (r *runner) getUser(name, id string) {
+	start := time.Now()
+	var resp userResponse
+	err := r.unary(procGetUser, map[string]any{"id": id}, &resp)
+	if err == nil && (resp.User == nil || resp.User.ID != id) {
+		err = fmt.Errorf("expected user %s in response", id)
+	}
+	details := ""
+	if err == nil {
+		details = resp.User.Email
+	}
+	r.record(name, start, err, details)
+}
+
+// updateUser records an UpdateUser call for id.
+func (r *runner) updateUser(name, id string) {
+	start := time.Now()
+	email := fmt.Sprintf("egg-test-updated-%d@example.com", time.Now().UnixNano())
+	var resp userResponse
+	err := r.unary(procUpdateUser, map[string]any{"id": id, "email": email, "name": "Updated Test User"}, &resp)
+	if err == nil && (resp.User == nil || resp.User.Email != email) {
+		err = fmt.Errorf("expected updated email %s in response", email)
+	}
+	r.record(name, start, err, email)
+}
+
+// updateDuplicateEmail records an UpdateUser call giving id the email of
+// other, which must fail.
+func (r *runner) updateDuplicateEmail(name, id, other string) {
+	start := time.Now()
+	var resp userResponse
+	err := r.unary(procGetUser, map[string]any{"id": other}, &resp)
+	if err == nil && resp.User == nil {
+		err = fmt.Errorf("response has no user")
+	}
+	if err != nil {
+		r.record(name, start, fmt.Errorf("failed to get user %s: %w", other, err), "")
+		return
+	}
+	r.expectErrorSince(name, start, r.client, procUpdateUser, map[string]any{
+		"id": id, "email": resp.User.Email, "name": "Update Test",
+	})
+}
+
+// deleteUser records a DeleteUser call for id.
+func (r *runner) deleteUser(name, id string) {
+	start := time.Now()
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	err := r.unary(procDeleteUser, map[string]any{"id": id}, &resp)
+	if err == nil && !resp.Success {
+		err = fmt.Errorf("expected success=true")
+	}
+	r.record(name, start, err, id)
+}
+
+// listUsers records a ListUsers call, applying check to the response.
+func (r *runner) listUsers(name string, page, pageSize int32, check func(listUsersResponse) error) {
+	start := time.Now()
+	var resp listUsersResponse
+	err := r.unary(procListUsers, map[string]any{"page": page, "pageSize": pageSize}, &resp)
+	if err == nil && check != nil {
+		err = check(resp)
+	}
+	r.record(name, start, err, fmt.Sprintf("%d users, total=%d", len(resp.Users), resp.Total))
+}
+
+// expectError records a call that must fail with a Connect error.
+func (r *runner) expectError(name, procedure string, req any) {
+	r.expectErrorSince(name, time.Now(), r.client, procedure, req)
+}
+
+// expectErrorSince records a call through client that must fail with a
+// Connect error, timed from start.
+func (r *runner) expectErrorSince(name string, start time.Time, client *Client, procedure string, req any) {
+	err := r.unaryWith(client, procedure, req, nil)
+	code := CodeOf(err)
+	switch {
+	case err == nil:
+		r.record(name, start, fmt.Errorf("expected an error, got success"), "")
+	case code == "":
+		r.record(name, start, err, "")
+	default:
+		r.record(name, start, nil, "returned "+code)
+	}
+}
+
+// runAdmin exercises the admin and internal-token calls of
+// user.v1.UserService. AdminResetAllUsers deletes every user, so the suite
+// only runs when requested.
+func (r *runner) runAdmin() {
+	r.expectError("AdminResetAllUsers_NoToken", procResetAllUsers, map[string]any{"confirm": true})
+
+	if r.opts.InternalToken == "" {
+		r.record("AdminResetAllUsers_WithToken", time.Now(), fmt.Errorf("internal token not set"), "")
+	} else {
+		authorized := r.client.WithHeader(internalTokenHeader, r.opts.InternalToken)
+		r.expectErrorSince("AdminResetAllUsers_NoConfirm", time.Now(), authorized, procResetAllUsers,
+			map[string]any{"confirm": false})
+		r.resetAllUsers("AdminResetAllUsers_WithToken", authorized)
+	}
+
+	r.validateInternalToken("ValidateInternalToken")
+}
+
+// resetAllUsers records an authorized AdminResetAllUsers call.
+func (r *runner) resetAllUsers(name string, client *Client) {
+	start := time.Now()
+	var resp struct {
+		DeletedCount int32 `json:"deletedCount"`
+		Success      bool  `json:"success"`
+	}
+	err := r.unaryWith(client, procResetAllUsers, map[string]any{"confirm": true}, &resp)
+	if err == nil && !resp.Success {
+		err = fmt.Errorf("expected success=true")
+	}
+	r.record(name, start, err, fmt.Sprintf("deleted=%d", resp.DeletedCount))
+}
+
+// validateInternalToken records a ValidateInternalToken call, which makes
+// the service call the greet service with its own internal token.
+func (r *runner) validateInternalToken(name string) {
+	start := time.Now()
+	var resp struct {
+		Valid        bool   `json:"valid"`
+		Message      string `json:"message"`
+		ErrorMessage string `json:"errorMessage"`
+	}
+	err := r.unary(procValidateToken, map[string]any{"token": ""}, &resp)
+	if err == nil && !resp.Valid {
+		err = fmt.Errorf("service client check failed: %s", resp.ErrorMessage)
+	}
+	r.record(name, start, err, resp.Message)
+}
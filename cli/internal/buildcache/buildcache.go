@@ -0,0 +1,327 @@
+// Package buildcache provides content-hash based build skipping for egg projects.
+//
+// Overview:
+//   - Responsibility: Hash build inputs and remember the hash of the last successful build
+//   - Key Types: Cache, Entry
+//   - Concurrency Model: Cache is safe for concurrent use; Save writes atomically
+//   - Error Semantics: A missing or corrupt cache file yields an empty cache
+//   - Performance Notes: Hashing reads every input file once
+//
+// Usage:
+//
+//	cache, err := buildcache.Load(buildcache.DefaultPath)
+//	hash, err := buildcache.Hash([]string{"backend/user", "gen/go"}, imageName, platform)
+//	if entry, ok := cache.Get("backend/user"); ok && entry.Hash == hash {
+//		// Up to date
+//	}
+//	cache.Put("backend/user", buildcache.Entry{Hash: hash, Image: imageName})
+//	err = cache.Save()
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPath is the cache file location relative to the project root.
+const DefaultPath = ".egg/build-cache.json"
+
+// skippedDirs are directories excluded from hashing, matching the
+// project's .dockerignore, since they never reach the build context.
+var skippedDirs = map[string]bool{
+	"bin":          true,
+	"dist":         true,
+	"tmp":          true,
+	"temp":         true,
+	"logs":         true,
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// skippedSuffixes are file suffixes excluded from hashing. go.sum files
+// are regenerated inside the builder, so they do not affect the image.
+var skippedSuffixes = []string{"go.sum", ".log", ".test", ".out", ".tmp", ".swp", "~"}
+
+// Entry records a successful build.
+type Entry struct {
+	Hash     string    `json:"hash"`               // Content hash of the build inputs
+	Image    string    `json:"image"`              // Image reference that was built
+	Platform string    `json:"platform,omitempty"` // Target platforms
+	Pushed   bool      `json:"pushed,omitempty"`   // Whether the image was pushed
+	BuiltAt  time.Time `json:"built_at"`           // Build completion time
+}
+
+// Cache maps build keys (e.g., backend/user) to their last successful
+// build.
+//
+// Parameters:
+//   - None (use Load)
+//
+// Returns:
+//   - None (data structure)
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - In-memory map, persisted by Save
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// cacheFile is the on-disk form of a Cache.
+type cacheFile struct {
+	Version int              `json:"version"`
+	Entries map[string]Entry `json:"entries"`
+}
+
+// cacheVersion is bumped when the hash inputs change, invalidating old
+// entries.
+const cacheVersion = 1
+
+// Load reads a cache file. A missing, unreadable or outdated file yields
+// an empty cache, so a bad cache only costs a rebuild.
+//
+// Parameters:
+//   - path: Cache file path
+//
+// Returns:
+//   - *Cache: Loaded cache
+//   - error: Error if the file exists but cannot be read
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single file read
+func Load(path string) (*Cache, error) {
+	cache := &Cache{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, fmt.Errorf("failed to read build cache: %w", err)
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != cacheVersion {
+		return cache, nil
+	}
+	for key, entry := range file.Entries {
+		cache.entries[key] = entry
+	}
+	return cache, nil
+}
+
+// Get returns the entry for key.
+//
+// Parameters:
+//   - key: Build key
+//
+// Returns:
+//   - Entry: Recorded build
+//   - bool: True if an entry exists
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1) lookup
+func (c *Cache) Get(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// Put records a successful build for key.
+//
+// Parameters:
+//   - key: Build key
+//   - entry: Build record (BuiltAt defaults to now)
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1) insertion
+func (c *Cache) Put(key string, entry Entry) {
+	if entry.BuiltAt.IsZero() {
+		entry.BuiltAt = time.Now().UTC()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// Delete removes the entry for key.
+//
+// Parameters:
+//   - key: Build key
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1) deletion
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Save writes the cache file, replacing it atomically.
+//
+// Parameters:
+//   - None
+//
+// Returns:
+//   - error: Write error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - One temporary file write and rename
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cacheFile{Version: cacheVersion, Entries: c.entries}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode build cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create build cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".build-cache-*")
+	if err != nil {
+		return fmt.Errorf("failed to write build cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write build cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write build cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to write build cache: %w", err)
+	}
+	return nil
+}
+
+// Hash computes a content hash over files and directory trees plus extra
+// strings such as the image name and build arguments. Paths that do not
+// exist are recorded as absent, so creating them changes the hash.
+// Hidden files and directories and build artifacts are skipped.
+//
+// Parameters:
+//   - paths: Files or directories to hash
+//   - extra: Additional inputs (e.g., image name, platform, build args)
+//
+// Returns:
+//   - string: Hex-encoded SHA-256 hash
+//   - error: Error if a file cannot be read
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Reads every file under paths once
+func Hash(paths []string, extra ...string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "egg-build-cache/%d\n", cacheVersion)
+	for _, s := range extra {
+		fmt.Fprintf(h, "extra %q\n", s)
+	}
+
+	for _, root := range paths {
+		if _, err := os.Lstat(root); os.IsNotExist(err) {
+			fmt.Fprintf(h, "absent %q\n", filepath.ToSlash(root))
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			name := d.Name()
+			if path != root && strings.HasPrefix(name, ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && skippedDirs[name] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			for _, suffix := range skippedSuffixes {
+				if strings.HasSuffix(name, suffix) {
+					return nil
+				}
+			}
+			return hashFile(h, path, d)
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", root, err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile writes a file's path, mode and content to h. Symlinks are
+// hashed by target rather than followed.
+func hashFile(h io.Writer, path string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "file %q %o\n", filepath.ToSlash(path), info.Mode().Perm())
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "link %q\n", target)
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(h, "size %d\n", info.Size())
+	_, err = io.Copy(h, f)
+	return err
+}
@@ -0,0 +1,108 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	dir := t.TempDir()
+	service := filepath.Join(dir, "backend", "user")
+	writeFile(t, filepath.Join(service, "go.mod"), "module example.com/user\n")
+	writeFile(t, filepath.Join(service, "cmd", "server", "main.go"), "package main\n")
+
+	hash := func(extra ...string) string {
+		t.Helper()
+		h, err := Hash([]string{service, filepath.Join(dir, "missing")}, extra...)
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		return h
+	}
+
+	base := hash("image:1.0")
+	if again := hash("image:1.0"); again != base {
+		t.Error("Hash should be stable")
+	}
+	if hash("image:1.1") == base {
+		t.Error("Hash should change with extra inputs")
+	}
+
+	// Artifacts and hidden files are ignored.
+	writeFile(t, filepath.Join(service, "bin", "server"), "binary")
+	writeFile(t, filepath.Join(service, ".env"), "SECRET=1")
+	writeFile(t, filepath.Join(service, "go.sum"), "sum")
+	writeFile(t, filepath.Join(service, "server.log"), "log")
+	if hash("image:1.0") != base {
+		t.Error("Hash should ignore artifacts and hidden files")
+	}
+
+	writeFile(t, filepath.Join(service, "cmd", "server", "main.go"), "package main\n\nfunc main() {}\n")
+	changed := hash("image:1.0")
+	if changed == base {
+		t.Error("Hash should change with source content")
+	}
+
+	writeFile(t, filepath.Join(dir, "missing"), "")
+	if hash("image:1.0") == changed {
+		t.Error("Hash should change when a missing path appears")
+	}
+}
+
+func TestCache_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".egg", "build-cache.json")
+
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := cache.Get("backend/user"); ok {
+		t.Error("Expected empty cache for missing file")
+	}
+
+	cache.Put("backend/user", Entry{Hash: "abc", Image: "shop-user:1.0", Pushed: true})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	entry, ok := loaded.Get("backend/user")
+	if !ok || entry.Hash != "abc" || entry.Image != "shop-user:1.0" || !entry.Pushed {
+		t.Errorf("Get() = %+v, %v", entry, ok)
+	}
+	if entry.BuiltAt.IsZero() {
+		t.Error("Expected BuiltAt to default to the current time")
+	}
+
+	loaded.Delete("backend/user")
+	if _, ok := loaded.Get("backend/user"); ok {
+		t.Error("Expected entry to be deleted")
+	}
+}
+
+func TestLoad_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "build-cache.json")
+	writeFile(t, path, "{not json")
+
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, ok := cache.Get("backend/user"); ok {
+		t.Error("Expected empty cache for corrupt file")
+	}
+}
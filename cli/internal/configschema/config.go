@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -88,6 +89,25 @@ type PortConfig struct {
 	Metrics int `yaml:"metrics"`
 }
 
+// Port offsets from the HTTP port used when health or metrics ports are
+// not set (8080 -> 8081 and 9091).
+const (
+	HealthPortOffset  = 1
+	MetricsPortOffset = 1011
+)
+
+// Effective returns the ports with unset health and metrics ports derived
+// from the HTTP port.
+func (p PortConfig) Effective() PortConfig {
+	if p.Health == 0 && p.HTTP != 0 {
+		p.Health = p.HTTP + HealthPortOffset
+	}
+	if p.Metrics == 0 && p.HTTP != 0 {
+		p.Metrics = p.HTTP + MetricsPortOffset
+	}
+	return p
+}
+
 // KubernetesConfig defines Kubernetes resources.
 type KubernetesConfig struct {
 	Resources KubernetesResourcesConfig `yaml:"resources"`
@@ -104,6 +124,8 @@ type BackendService struct {
 	Ports      *PortConfig             `yaml:"ports,omitempty"`
 	Kubernetes BackendKubernetesConfig `yaml:"kubernetes"`
 	Env        BackendEnvConfig        `yaml:"env"`
+	DependsOn  []string                `yaml:"depends_on,omitempty"` // Services started before this one
+	Profiles   []string                `yaml:"profiles,omitempty"`   // Compose profiles selecting this service
 }
 
 // BackendKubernetesConfig defines Kubernetes settings for backend services.
@@ -133,6 +155,8 @@ type BackendEnvConfig struct {
 // FrontendService defines a frontend service configuration.
 type FrontendService struct {
 	Platforms []string `yaml:"platforms"`
+	DependsOn []string `yaml:"depends_on,omitempty"` // Services started before this one
+	Profiles  []string `yaml:"profiles,omitempty"`   // Compose profiles selecting this service
 }
 
 // DatabaseConfig defines database settings.
@@ -543,8 +567,11 @@ func applyDefaults(config *Config) {
 	for name, service := range config.Backend {
 		if service.Ports == nil {
 			service.Ports = &config.BackendDefaults.Ports
-			config.Backend[name] = service
+		} else {
+			ports := service.Ports.Effective()
+			service.Ports = &ports
 		}
+		config.Backend[name] = service
 	}
 }
 
@@ -583,16 +610,25 @@ func validateConfig(config *Config, diags *Diagnostics) {
 	}
 
 	// Validate backend services
-	usedPorts := make(map[int]string)
 	for name, service := range config.Backend {
-		validateBackendService(name, service, config, diags, usedPorts)
+		validateBackendService(name, service, config, diags)
 	}
+	validatePortConflicts(config, diags)
 
 	// Validate frontend services
 	for name, service := range config.Frontend {
 		validateFrontendService(name, service, diags)
 	}
 
+	// Validate service dependencies
+	for name, service := range config.Backend {
+		validateDependsOn("backend."+name, name, service.DependsOn, config, diags)
+	}
+	for name, service := range config.Frontend {
+		validateDependsOn("frontend."+name, name, service.DependsOn, config, diags)
+	}
+	validateDependencyCycles(config, diags)
+
 	// Validate database configuration
 	validateDatabaseConfig(config.Database, diags)
 
@@ -607,7 +643,6 @@ func validateConfig(config *Config, diags *Diagnostics) {
 //   - service: Service configuration
 //   - config: Full configuration for context
 //   - diags: Diagnostics collection
-//   - usedPorts: Map of used ports to service names
 //
 // Returns:
 //   - None (populates diagnostics)
@@ -617,7 +652,7 @@ func validateConfig(config *Config, diags *Diagnostics) {
 //
 // Performance:
 //   - O(1) per service validation
-func validateBackendService(name string, service BackendService, config *Config, diags *Diagnostics, usedPorts map[int]string) {
+func validateBackendService(name string, service BackendService, config *Config, diags *Diagnostics) {
 	// Validate service name
 	if !isValidServiceName(name) {
 		diags.AddError("Invalid service name", fmt.Sprintf("backend.%s", name), "Use lowercase letters, numbers, hyphens, and underscores only")
@@ -628,24 +663,11 @@ func validateBackendService(name string, service BackendService, config *Config,
 		diags.AddWarning("Service naming convention violation", fmt.Sprintf("backend.%s", name), err.Error())
 	}
 
-	// Validate ports
+	// Validate ports (conflicts are checked across services in validatePortConflicts)
 	if service.Ports != nil {
-		ports := []struct {
-			port int
-			name string
-		}{
-			{service.Ports.HTTP, "http"},
-			{service.Ports.Health, "health"},
-			{service.Ports.Metrics, "metrics"},
-		}
-
-		for _, p := range ports {
+		for _, p := range namedPorts(service.Ports.Effective()) {
 			if p.port <= 0 || p.port > 65535 {
 				diags.AddError("Invalid port number", fmt.Sprintf("backend.%s.ports.%s", name, p.name), "Use port numbers between 1 and 65535")
-			} else if existing, exists := usedPorts[p.port]; exists {
-				diags.AddError("Port conflict", fmt.Sprintf("backend.%s.ports.%s", name, p.name), fmt.Sprintf("Port %d is already used by %s", p.port, existing))
-			} else {
-				usedPorts[p.port] = fmt.Sprintf("backend.%s", name)
 			}
 		}
 	}
@@ -660,6 +682,71 @@ func validateBackendService(name string, service BackendService, config *Config,
 	}
 }
 
+// namedPort is a port together with its role (http, health or metrics).
+type namedPort struct {
+	port int
+	name string
+}
+
+// namedPorts returns the http, health and metrics ports in that order.
+func namedPorts(ports PortConfig) []namedPort {
+	return []namedPort{
+		{ports.HTTP, "http"},
+		{ports.Health, "health"},
+		{ports.Metrics, "metrics"},
+	}
+}
+
+// validatePortConflicts checks that the effective ports of all backend
+// services, including health and metrics ports derived from the HTTP port,
+// are distinct. Each conflict is reported once, on the later port in
+// service name order, and names both offending ports.
+//
+// Parameters:
+//   - config: Configuration to validate
+//   - diags: Diagnostics collection
+//
+// Returns:
+//   - None (populates diagnostics)
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - O(n log n) where n is number of backend services
+func validatePortConflicts(config *Config, diags *Diagnostics) {
+	names := make([]string, 0, len(config.Backend))
+	for name := range config.Backend {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	usedPorts := make(map[int]string)
+	for _, name := range names {
+		service := config.Backend[name]
+		if service.Ports == nil {
+			continue
+		}
+
+		for _, p := range namedPorts(service.Ports.Effective()) {
+			if p.port <= 0 || p.port > 65535 {
+				continue
+			}
+
+			path := fmt.Sprintf("backend.%s.ports.%s", name, p.name)
+			if existing, exists := usedPorts[p.port]; exists {
+				diags.AddError(
+					fmt.Sprintf("Port conflict: %d is used by %s and %s", p.port, existing, path),
+					path,
+					fmt.Sprintf("Choose a different %s port (unset health and metrics ports default to http+%d and http+%d)", p.name, HealthPortOffset, MetricsPortOffset),
+				)
+				continue
+			}
+			usedPorts[p.port] = path
+		}
+	}
+}
+
 // validateFrontendService validates a single frontend service configuration.
 //
 // Parameters:
@@ -692,6 +779,105 @@ func validateFrontendService(name string, service FrontendService, diags *Diagno
 	}
 }
 
+// validateDependsOn validates that a service's depends_on entries name
+// other backend or frontend services.
+//
+// Parameters:
+//   - path: Service path (e.g., backend.user)
+//   - name: Service name
+//   - dependsOn: Declared dependencies
+//   - config: Full configuration for context
+//   - diags: Diagnostics collection
+//
+// Returns:
+//   - None (populates diagnostics)
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - O(n) in the number of dependencies
+func validateDependsOn(path, name string, dependsOn []string, config *Config, diags *Diagnostics) {
+	for _, dep := range dependsOn {
+		_, isBackend := config.Backend[dep]
+		_, isFrontend := config.Frontend[dep]
+		switch {
+		case dep == name:
+			diags.AddError("Service depends on itself", path+".depends_on", fmt.Sprintf("Remove %s from depends_on", dep))
+		case !isBackend && !isFrontend:
+			diags.AddError(fmt.Sprintf("Unknown dependency: %s", dep), path+".depends_on", "List backend or frontend services defined in egg.yaml")
+		}
+	}
+}
+
+// validateDependencyCycles reports depends_on cycles, which Docker Compose
+// rejects.
+//
+// Parameters:
+//   - config: Full configuration
+//   - diags: Diagnostics collection
+//
+// Returns:
+//   - None (populates diagnostics)
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - O(V+E) depth-first search
+func validateDependencyCycles(config *Config, diags *Diagnostics) {
+	graph := make(map[string][]string)
+	for name, service := range config.Backend {
+		graph[name] = service.DependsOn
+	}
+	for name, service := range config.Frontend {
+		graph[name] = service.DependsOn
+	}
+
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			cycle := append([]string{}, stack...)
+			for len(cycle) > 0 && cycle[0] != name {
+				cycle = cycle[1:]
+			}
+			cycle = append(cycle, name)
+			diags.AddError("Dependency cycle: "+strings.Join(cycle, " -> "), "depends_on", "Remove one of the depends_on entries in the cycle")
+			return true
+		case done:
+			return false
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range graph[name] {
+			if _, ok := graph[dep]; ok && visit(dep) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return false
+	}
+	for _, name := range names {
+		if visit(name) {
+			return
+		}
+	}
+}
+
 // validateDatabaseConfig validates database configuration.
 //
 // Parameters:
@@ -122,6 +122,56 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "valid depends_on",
+			config: &Config{
+				ProjectName:  "test-project",
+				ModulePrefix: "github.com/test/test-project",
+				Backend: map[string]BackendService{
+					"user":  {DependsOn: []string{"greet"}},
+					"greet": {},
+				},
+				Frontend: map[string]FrontendService{
+					"admin": {Platforms: []string{"web"}, DependsOn: []string{"user"}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown depends_on",
+			config: &Config{
+				ProjectName:  "test-project",
+				ModulePrefix: "github.com/test/test-project",
+				Backend: map[string]BackendService{
+					"user": {DependsOn: []string{"missing"}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "depends_on cycle",
+			config: &Config{
+				ProjectName:  "test-project",
+				ModulePrefix: "github.com/test/test-project",
+				Backend: map[string]BackendService{
+					"user":  {DependsOn: []string{"greet"}},
+					"greet": {DependsOn: []string{"order"}},
+					"order": {DependsOn: []string{"user"}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "self depends_on",
+			config: &Config{
+				ProjectName:  "test-project",
+				ModulePrefix: "github.com/test/test-project",
+				Frontend: map[string]FrontendService{
+					"admin": {Platforms: []string{"web"}, DependsOn: []string{"admin"}},
+				},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -136,3 +186,76 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePortConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  map[string]BackendService
+		wantPath string
+		wantMsg  string
+	}{
+		{
+			name: "derived metrics port overlaps http port",
+			backend: map[string]BackendService{
+				"greet": {Ports: &PortConfig{HTTP: 8080}},
+				"user":  {Ports: &PortConfig{HTTP: 9091, Health: 9092, Metrics: 9093}},
+			},
+			wantPath: "backend.user.ports.http",
+			wantMsg:  "Port conflict: 9091 is used by backend.greet.ports.metrics and backend.user.ports.http",
+		},
+		{
+			name: "derived health ports overlap",
+			backend: map[string]BackendService{
+				"greet": {Ports: &PortConfig{HTTP: 8080, Health: 8090, Metrics: 9091}},
+				"user":  {Ports: &PortConfig{HTTP: 8089, Metrics: 9100}},
+			},
+			wantPath: "backend.user.ports.health",
+			wantMsg:  "Port conflict: 8090 is used by backend.greet.ports.health and backend.user.ports.health",
+		},
+		{
+			name: "distinct derived ports",
+			backend: map[string]BackendService{
+				"greet": {Ports: &PortConfig{HTTP: 8080}},
+				"user":  {Ports: &PortConfig{HTTP: 8090}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := NewDiagnostics()
+			validatePortConflicts(&Config{Backend: tt.backend}, diags)
+
+			var errs []Diagnostic
+			for _, diag := range diags.Items() {
+				if diag.Severity == SeverityError {
+					errs = append(errs, diag)
+				}
+			}
+			if tt.wantPath == "" {
+				if len(errs) != 0 {
+					t.Fatalf("Expected no conflicts, got %+v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Path != tt.wantPath || errs[0].Message != tt.wantMsg {
+				t.Fatalf("Expected %q at %s, got %+v", tt.wantMsg, tt.wantPath, errs)
+			}
+		})
+	}
+}
+
+func TestApplyDefaultsDerivesPorts(t *testing.T) {
+	config := &Config{
+		Backend: map[string]BackendService{
+			"user": {Ports: &PortConfig{HTTP: 8090}},
+		},
+	}
+	applyDefaults(config)
+
+	got := *config.Backend["user"].Ports
+	want := PortConfig{HTTP: 8090, Health: 8091, Metrics: 9101}
+	if got != want {
+		t.Errorf("Expected derived ports %+v, got %+v", want, got)
+	}
+}
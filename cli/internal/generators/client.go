@@ -0,0 +1,603 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/projectfs"
+	"go.eggybyte.com/egg/cli/internal/templates"
+	"go.eggybyte.com/egg/cli/internal/toolrunner"
+	"go.eggybyte.com/egg/cli/internal/ui"
+)
+
+// ClientGenerator generates typed clientx wrappers for proto services.
+//
+// Parameters:
+//   - fs: Project file system
+//   - runner: Tool runner for external commands
+//   - loader: Template loader
+//
+// Returns:
+//   - None (data structure)
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Template-based generation
+type ClientGenerator struct {
+	fs     *projectfs.ProjectFS
+	runner *toolrunner.Runner
+	loader *templates.Loader
+}
+
+// ClientOptions configures client generation.
+type ClientOptions struct {
+	ProtoFile    string // Proto file path (default: api/<target>/v1/<target>.proto)
+	ProtoService string // Proto service to wrap (required if the file defines several)
+	Force        bool   // Overwrite an existing client file
+}
+
+// ProtoService is a service parsed from a proto file.
+type ProtoService struct {
+	Name string     // Service name (e.g., "GreeterService")
+	RPCs []ProtoRPC // Service methods in declaration order
+}
+
+// ProtoRPC is a method of a proto service.
+type ProtoRPC struct {
+	Name            string // Method name (e.g., "SayHello")
+	Request         string // Request message type as written
+	Response        string // Response message type as written
+	ClientStreaming bool   // Request is a stream
+	ServerStreaming bool   // Response is a stream
+}
+
+// ProtoFile is the subset of a proto file needed to generate clients.
+type ProtoFile struct {
+	Package   string         // Proto package (e.g., "org.project.greet.v1")
+	GoPackage string         // go_package option value
+	Services  []ProtoService // Services in declaration order
+}
+
+// clientMethod is a unary RPC exposed as a typed client method.
+type clientMethod struct {
+	Name     string
+	Request  string
+	Response string
+}
+
+// NewClientGenerator creates a new client generator.
+//
+// Parameters:
+//   - fs: Project file system
+//   - runner: Tool runner for external commands
+//
+// Returns:
+//   - *ClientGenerator: Client generator instance
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Minimal initialization overhead
+func NewClientGenerator(fs *projectfs.ProjectFS, runner *toolrunner.Runner) *ClientGenerator {
+	return &ClientGenerator{
+		fs:     fs,
+		runner: runner,
+		loader: templates.NewLoader(),
+	}
+}
+
+// Generate writes a typed client for the target's proto service into
+// backend/<service>/internal/client and registers it in the service's
+// registerServices function.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - service: Backend service that calls the target
+//   - target: Backend service providing the proto service
+//   - config: Project configuration
+//   - opts: Generation options
+//
+// Returns:
+//   - error: Generation error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Proto parsing, template rendering and file I/O
+func (g *ClientGenerator) Generate(ctx context.Context, service, target string, config *configschema.Config, opts ClientOptions) error {
+	if !isValidServiceName(service) {
+		return fmt.Errorf("invalid service name: %s", service)
+	}
+	if !isValidServiceName(target) {
+		return fmt.Errorf("invalid target service name: %s", target)
+	}
+
+	serviceDir := filepath.Join("backend", service)
+	if exists, _ := g.fs.DirectoryExists(serviceDir); !exists {
+		return fmt.Errorf("service not found: %s", serviceDir)
+	}
+
+	protoPath := opts.ProtoFile
+	if protoPath == "" {
+		protoPath = filepath.Join("api", target, "v1", target+".proto")
+	}
+	content, err := g.fs.ReadFile(protoPath)
+	if err != nil {
+		return fmt.Errorf("failed to read proto file: %w", err)
+	}
+	proto, err := ParseProtoFile(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", protoPath, err)
+	}
+	protoService, err := selectProtoService(proto, opts.ProtoService)
+	if err != nil {
+		return err
+	}
+
+	data, err := clientTemplateData(service, target, filepath.ToSlash(protoPath), proto, protoService, config)
+	if err != nil {
+		return err
+	}
+	clientName := data["ClientName"].(string)
+
+	clientFile := filepath.Join(serviceDir, "internal", "client", toSnakeCase(clientName)+"_client.go")
+	if exists, _ := g.fs.FileExists(clientFile); exists && !opts.Force {
+		return fmt.Errorf("client already exists: %s (use --force to regenerate)", clientFile)
+	}
+
+	rendered, err := g.loader.LoadAndRender("backend/client.go.tmpl", data)
+	if err != nil {
+		return fmt.Errorf("failed to load and render client.go template: %w", err)
+	}
+	source, err := format.Source([]byte(rendered))
+	if err != nil {
+		return fmt.Errorf("failed to format generated client: %w", err)
+	}
+	if err := g.fs.WriteFile(clientFile, string(source), 0644); err != nil {
+		return fmt.Errorf("failed to write client: %w", err)
+	}
+	ui.Success("Client generated: %s", clientFile)
+
+	for _, rpc := range protoService.RPCs {
+		if rpc.ClientStreaming || rpc.ServerStreaming {
+			ui.Info("Streaming RPC %s is available via %sClient.Client()", rpc.Name, clientName)
+		}
+	}
+
+	// Generated code lives in gen/go; remind to generate it if missing
+	connectImport := data["ConnectImport"].(string)
+	if rel, ok := strings.CutPrefix(connectImport, config.ModulePrefix+"/"); ok {
+		if exists, _ := g.fs.DirectoryExists(filepath.FromSlash(rel)); !exists {
+			ui.Warning("Generated package %s not found; run 'egg api generate'", rel)
+		}
+	}
+
+	g.addClientxDependency(ctx, serviceDir)
+
+	mainFile := filepath.Join(serviceDir, "cmd", "server", "main.go")
+	serviceModulePath := config.ModulePrefix + "/backend/" + service
+	wired, err := g.registerClient(mainFile, serviceModulePath, clientName)
+	if err != nil {
+		ui.Warning("Failed to register client in %s: %v", mainFile, err)
+	}
+	if wired {
+		ui.Success("Registered %sClient in %s", clientName, mainFile)
+	} else {
+		ui.Info("Register the client in registerServices:")
+		ui.Info("  if err := client.Provide%sClient(app); err != nil {", clientName)
+		ui.Info("      return err")
+		ui.Info("  }")
+	}
+
+	return nil
+}
+
+// clientTemplateData builds the client.go.tmpl data for a proto service.
+//
+// Parameters:
+//   - service: Calling backend service
+//   - target: Target backend service
+//   - protoPath: Proto file path (slash-separated)
+//   - proto: Parsed proto file
+//   - protoService: Service to wrap
+//   - config: Project configuration
+//
+// Returns:
+//   - map[string]interface{}: Template data
+//   - error: Error if the go_package option is missing
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of RPCs
+func clientTemplateData(service, target, protoPath string, proto *ProtoFile, protoService *ProtoService, config *configschema.Config) (map[string]interface{}, error) {
+	if proto.GoPackage == "" {
+		return nil, fmt.Errorf("proto file %s has no go_package option", protoPath)
+	}
+	messageImport, messagePackage := splitGoPackage(proto.GoPackage)
+	connectPackage := messagePackage + "connect"
+
+	clientName := strings.TrimSuffix(protoService.Name, "Service")
+	if clientName == "" {
+		clientName = protoService.Name
+	}
+
+	var methods []clientMethod
+	for _, rpc := range protoService.RPCs {
+		if rpc.ClientStreaming || rpc.ServerStreaming {
+			continue
+		}
+		request, reqOK := localMessage(rpc.Request, proto.Package)
+		response, respOK := localMessage(rpc.Response, proto.Package)
+		if !reqOK || !respOK {
+			ui.Info("RPC %s uses messages from another package; use %sClient.Client()", rpc.Name, clientName)
+			continue
+		}
+		methods = append(methods, clientMethod{Name: rpc.Name, Request: request, Response: response})
+	}
+
+	port := config.BackendDefaults.Ports.HTTP
+	if targetConfig, ok := config.Backend[target]; ok && targetConfig.Ports != nil && targetConfig.Ports.HTTP > 0 {
+		port = targetConfig.Ports.HTTP
+	}
+	if port == 0 {
+		port = 8080
+	}
+
+	return map[string]interface{}{
+		"ServiceName":    service,
+		"Target":         target,
+		"ProtoFile":      protoPath,
+		"ProtoService":   protoService.Name,
+		"ClientName":     clientName,
+		"ClientVar":      lowerFirst(clientName),
+		"MessageImport":  messageImport,
+		"MessagePackage": messagePackage,
+		"ConnectImport":  messageImport + "/" + connectPackage,
+		"ConnectPackage": connectPackage,
+		"URLEnv":         strings.ToUpper(toSnakeCase(clientName)) + "_SERVICE_URL",
+		"DefaultURL":     fmt.Sprintf("http://%s:%d", target, port),
+		"Methods":        methods,
+	}, nil
+}
+
+// addClientxDependency adds clientx to the service go.mod at the version
+// of its servicex requirement. Failures are reported as warnings.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - serviceDir: Service directory
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - One go get invocation when clientx is missing
+func (g *ClientGenerator) addClientxDependency(ctx context.Context, serviceDir string) {
+	goMod, err := g.fs.ReadFile(filepath.Join(serviceDir, "go.mod"))
+	if err != nil {
+		ui.Warning("Failed to read go.mod: %v", err)
+		return
+	}
+	if strings.Contains(goMod, "go.eggybyte.com/egg/clientx ") {
+		return
+	}
+
+	version := getFrameworkVersion()
+	if match := regexp.MustCompile(`go\.eggybyte\.com/egg/servicex (\S+)`).FindStringSubmatch(goMod); match != nil {
+		version = match[1]
+	}
+
+	env := map[string]string{"GOPROXY": "https://goproxy.cn,direct"}
+	if version == "v0.0.0-dev" {
+		// Resolve from the local workspace, as egg create backend --local-modules does
+		env = map[string]string{"GOPROXY": "direct", "GOSUMDB": "off"}
+	}
+
+	dep := "go.eggybyte.com/egg/clientx@" + version
+	serviceRunner := toolrunner.NewRunner(filepath.Join(g.fs.GetRootDir(), serviceDir))
+	serviceRunner.SetVerbose(g.runner.GetVerbose())
+	if _, err := serviceRunner.GoWithEnv(ctx, env, "get", dep); err != nil {
+		ui.Warning("Failed to add dependency %s: %v", dep, err)
+	}
+}
+
+// registerClient inserts a Provide<Name>Client call at the start of
+// registerServices in the service's main.go, adding the client and fmt
+// imports as needed.
+//
+// Parameters:
+//   - mainFile: Path to cmd/server/main.go
+//   - serviceModulePath: Go module path of the service
+//   - clientName: Client name (e.g., "Greet")
+//
+// Returns:
+//   - bool: True if the client is registered (including previously)
+//   - error: Error if main.go cannot be read, parsed or written
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Single parse and write of main.go
+func (g *ClientGenerator) registerClient(mainFile, serviceModulePath, clientName string) (bool, error) {
+	content, err := g.fs.ReadFile(mainFile)
+	if err != nil {
+		return false, err
+	}
+
+	call := fmt.Sprintf("client.Provide%sClient(app)", clientName)
+	if strings.Contains(content, call) {
+		return true, nil
+	}
+
+	updated, ok, err := insertClientRegistration(content, serviceModulePath+"/internal/client", clientName)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := g.fs.WriteFile(mainFile, updated, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// insertClientRegistration adds the Provide<Name>Client call to the
+// registerServices function of a main.go source.
+//
+// Parameters:
+//   - source: main.go content
+//   - clientImport: Import path of the client package
+//   - clientName: Client name (e.g., "Greet")
+//
+// Returns:
+//   - string: Updated and formatted source
+//   - bool: False if there is no registerServices(app) function or parenthesized import block
+//   - error: Parse or format error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single parse and format
+func insertClientRegistration(source, clientImport, clientName string) (string, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, parser.ParseComments)
+	if err != nil {
+		return "", false, err
+	}
+
+	var body *ast.BlockStmt
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv == nil && fn.Name.Name == "registerServices" && fn.Body != nil &&
+			len(fn.Type.Params.List) == 1 && len(fn.Type.Params.List[0].Names) == 1 &&
+			fn.Type.Params.List[0].Names[0].Name == "app" {
+			body = fn.Body
+		}
+	}
+	var imports *ast.GenDecl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			imports = gen
+			break
+		}
+	}
+	if body == nil || imports == nil {
+		return "", false, nil
+	}
+
+	var missing []string
+	for _, path := range []string{"fmt", clientImport} {
+		found := false
+		for _, spec := range file.Imports {
+			if value, _ := strconv.Unquote(spec.Path.Value); value == path && spec.Name == nil {
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, path)
+		}
+	}
+
+	// Insert from the end of the file so earlier offsets stay valid
+	bodyOffset := fset.Position(body.Lbrace).Offset + 1
+	registration := fmt.Sprintf("\n\t// Register generated service clients\n"+
+		"\tif err := client.Provide%[1]sClient(app); err != nil {\n"+
+		"\t\treturn fmt.Errorf(\"failed to provide %[1]s client: %%w\", err)\n"+
+		"\t}\n", clientName)
+	source = source[:bodyOffset] + registration + source[bodyOffset:]
+
+	// Standard library imports join the first group; others join the
+	// first non-standard group, or end the block if there is none
+	stdOffset := fset.Position(imports.Lparen).Offset + 1
+	otherOffset := fset.Position(imports.Rparen).Offset
+	for _, spec := range imports.Specs {
+		if value, _ := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value); strings.Contains(strings.Split(value, "/")[0], ".") {
+			otherOffset = fset.Position(spec.Pos()).Offset
+			break
+		}
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		offset, line := otherOffset, fmt.Sprintf("%q\n\t", missing[i])
+		if !strings.Contains(strings.Split(missing[i], "/")[0], ".") {
+			offset, line = stdOffset, fmt.Sprintf("\n\t%q", missing[i])
+		}
+		source = source[:offset] + line + source[offset:]
+	}
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return "", false, err
+	}
+	return string(formatted), true, nil
+}
+
+var (
+	protoBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	protoLineComment  = regexp.MustCompile(`//[^\n]*`)
+	protoPackageRe    = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)\s*;`)
+	protoGoPackageRe  = regexp.MustCompile(`option\s+go_package\s*=\s*"([^"]+)"\s*;`)
+	protoServiceRe    = regexp.MustCompile(`\bservice\s+(\w+)\s*\{`)
+	protoRPCRe        = regexp.MustCompile(`\brpc\s+(\w+)\s*\(\s*(stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(stream\s+)?([\w.]+)\s*\)`)
+)
+
+// ParseProtoFile extracts the package, go_package option and services of
+// a proto file. It understands the subset of proto3 syntax needed for
+// client generation; message definitions are not parsed.
+//
+// Parameters:
+//   - content: Proto file content
+//
+// Returns:
+//   - *ProtoFile: Parsed proto file
+//   - error: Error if the file defines no services or has unbalanced braces
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) regular expression scans
+func ParseProtoFile(content string) (*ProtoFile, error) {
+	// Strip comments, keeping line breaks; string literals containing
+	// comment markers are not expected in service definitions
+	content = protoBlockComment.ReplaceAllStringFunc(content, func(s string) string {
+		return strings.Repeat("\n", strings.Count(s, "\n"))
+	})
+	content = protoLineComment.ReplaceAllString(content, "")
+
+	proto := &ProtoFile{}
+	if match := protoPackageRe.FindStringSubmatch(content); match != nil {
+		proto.Package = match[1]
+	}
+	if match := protoGoPackageRe.FindStringSubmatch(content); match != nil {
+		proto.GoPackage = match[1]
+	}
+
+	for _, loc := range protoServiceRe.FindAllStringSubmatchIndex(content, -1) {
+		name := content[loc[2]:loc[3]]
+		end := matchingBrace(content, loc[1]-1)
+		if end < 0 {
+			return nil, fmt.Errorf("service %s: unbalanced braces", name)
+		}
+
+		service := ProtoService{Name: name}
+		for _, rpc := range protoRPCRe.FindAllStringSubmatch(content[loc[1]:end], -1) {
+			service.RPCs = append(service.RPCs, ProtoRPC{
+				Name:            rpc[1],
+				Request:         rpc[3],
+				Response:        rpc[5],
+				ClientStreaming: rpc[2] != "",
+				ServerStreaming: rpc[4] != "",
+			})
+		}
+		proto.Services = append(proto.Services, service)
+	}
+
+	if len(proto.Services) == 0 {
+		return nil, fmt.Errorf("no service definitions found")
+	}
+	return proto, nil
+}
+
+// matchingBrace returns the index of the brace closing the one at open,
+// or -1 if it is unbalanced.
+func matchingBrace(content string, open int) int {
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// selectProtoService picks the named service, or the only one if name is
+// empty.
+func selectProtoService(proto *ProtoFile, name string) (*ProtoService, error) {
+	names := make([]string, 0, len(proto.Services))
+	for i := range proto.Services {
+		if proto.Services[i].Name == name {
+			return &proto.Services[i], nil
+		}
+		names = append(names, proto.Services[i].Name)
+	}
+	if name == "" && len(proto.Services) == 1 {
+		return &proto.Services[0], nil
+	}
+	if name == "" {
+		return nil, fmt.Errorf("proto file defines several services (%s); choose one with --service", strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("proto service %s not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+// splitGoPackage splits a go_package option ("path;name" or "path") into
+// the import path and package name.
+func splitGoPackage(goPackage string) (importPath, name string) {
+	if i := strings.Index(goPackage, ";"); i >= 0 {
+		return goPackage[:i], goPackage[i+1:]
+	}
+	return goPackage, path.Base(goPackage)
+}
+
+// localMessage resolves a message type declared in the proto package,
+// stripping a redundant package qualifier. It reports false for types
+// from other packages.
+func localMessage(typeName, protoPackage string) (string, bool) {
+	typeName = strings.TrimPrefix(typeName, ".")
+	if protoPackage != "" {
+		typeName = strings.TrimPrefix(typeName, protoPackage+".")
+	}
+	return typeName, !strings.Contains(typeName, ".")
+}
+
+// toSnakeCase converts a CamelCase name to snake_case (e.g., "UserProfile"
+// to "user_profile").
+func toSnakeCase(name string) string {
+	var result strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				result.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// lowerFirst lowercases the first letter of a name.
+func lowerFirst(name string) string {
+	if name == "" {
+		return ""
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
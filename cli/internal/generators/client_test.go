@@ -0,0 +1,162 @@
+package generators
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/templates"
+)
+
+const greetProto = `syntax = "proto3";
+
+package acme.shop.greet.v1;
+
+option go_package = "github.com/acme/shop/gen/go/greet/v1;greetv1";
+
+import "google/protobuf/empty.proto";
+
+/* GreeterService { is mentioned in a comment */
+service GreeterService {
+  // SayHello greets. rpc Fake(A) returns (B);
+  rpc SayHello(SayHelloRequest) returns (acme.shop.greet.v1.SayHelloResponse);
+  rpc Watch(WatchRequest) returns (stream WatchEvent) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+  rpc Reset(google.protobuf.Empty) returns (google.protobuf.Empty);
+}
+
+message SayHelloRequest { string name = 1; }
+`
+
+func TestParseProtoFile(t *testing.T) {
+	proto, err := ParseProtoFile(greetProto)
+	if err != nil {
+		t.Fatalf("ParseProtoFile() error = %v", err)
+	}
+
+	if proto.Package != "acme.shop.greet.v1" {
+		t.Errorf("Package = %q", proto.Package)
+	}
+	if proto.GoPackage != "github.com/acme/shop/gen/go/greet/v1;greetv1" {
+		t.Errorf("GoPackage = %q", proto.GoPackage)
+	}
+	if len(proto.Services) != 1 || proto.Services[0].Name != "GreeterService" {
+		t.Fatalf("Services = %+v", proto.Services)
+	}
+
+	rpcs := proto.Services[0].RPCs
+	if len(rpcs) != 3 {
+		t.Fatalf("Expected 3 RPCs, got %+v", rpcs)
+	}
+	if rpcs[0].Name != "SayHello" || rpcs[0].Response != "acme.shop.greet.v1.SayHelloResponse" {
+		t.Errorf("RPC 0 = %+v", rpcs[0])
+	}
+	if !rpcs[1].ServerStreaming || rpcs[1].ClientStreaming {
+		t.Errorf("Watch should be server streaming: %+v", rpcs[1])
+	}
+
+	if _, err := ParseProtoFile("syntax = \"proto3\";\nmessage A {}\n"); err == nil {
+		t.Error("Expected error for proto without services")
+	}
+}
+
+func TestClientTemplate(t *testing.T) {
+	proto, err := ParseProtoFile(greetProto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &configschema.Config{
+		ModulePrefix: "github.com/acme/shop",
+		Backend: map[string]configschema.BackendService{
+			"greet": {Ports: &configschema.PortConfig{HTTP: 9000}},
+		},
+	}
+
+	data, err := clientTemplateData("user", "greet", "api/greet/v1/greet.proto", proto, &proto.Services[0], config)
+	if err != nil {
+		t.Fatalf("clientTemplateData() error = %v", err)
+	}
+	rendered, err := templates.NewLoader().LoadAndRender("backend/client.go.tmpl", data)
+	if err != nil {
+		t.Fatalf("LoadAndRender() error = %v", err)
+	}
+	source, err := format.Source([]byte(rendered))
+	if err != nil {
+		t.Fatalf("Generated client does not parse: %v\n%s", err, rendered)
+	}
+
+	for _, want := range []string{
+		`greetv1connect "github.com/acme/shop/gen/go/greet/v1/greetv1connect"`,
+		"type GreeterClient struct",
+		"func NewGreeterClient(baseURL, internalToken string) (*GreeterClient, error)",
+		"func ProvideGreeterClient(app *servicex.App) error",
+		`GreeterURLEnv = "GREETER_SERVICE_URL"`,
+		`DefaultGreeterURL = "http://greet:9000"`,
+		"func (c *GreeterClient) SayHello(ctx context.Context, req *greetv1.SayHelloRequest) (*greetv1.SayHelloResponse, error)",
+		"clientx.WithRetry(3)",
+		"clientx.WithCircuitBreaker(true)",
+		"clientx.WithInternalToken(internalToken)",
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("Generated client missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"func (c *GreeterClient) Watch", "func (c *GreeterClient) Reset"} {
+		if strings.Contains(string(source), unwanted) {
+			t.Errorf("Generated client should not wrap %q", unwanted)
+		}
+	}
+}
+
+func TestInsertClientRegistration(t *testing.T) {
+	main := `package main
+
+import (
+	"context"
+
+	"go.eggybyte.com/egg/servicex"
+)
+
+func main() {
+	_ = servicex.Run(context.Background())
+}
+
+func registerServices(app *servicex.App) error {
+	app.Logger().Info("starting")
+	return nil
+}
+`
+
+	updated, ok, err := insertClientRegistration(main, "github.com/acme/shop/backend/user/internal/client", "Greeter")
+	if err != nil || !ok {
+		t.Fatalf("insertClientRegistration() = %v, %v", ok, err)
+	}
+	for _, want := range []string{
+		"\"context\"\n\t\"fmt\"\n",
+		"\"github.com/acme/shop/backend/user/internal/client\"\n\t\"go.eggybyte.com/egg/servicex\"\n",
+		"func registerServices(app *servicex.App) error {\n\t// Register generated service clients\n\tif err := client.ProvideGreeterClient(app); err != nil {",
+	} {
+		if !strings.Contains(updated, want) {
+			t.Errorf("Updated main.go missing %q:\n%s", want, updated)
+		}
+	}
+
+	_, ok, err = insertClientRegistration("package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n", "x/client", "Greeter")
+	if err != nil || ok {
+		t.Errorf("Expected no registration without registerServices, got %v, %v", ok, err)
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"Greet":       "greet",
+		"UserProfile": "user_profile",
+		"HTTPProxy":   "http_proxy",
+	} {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
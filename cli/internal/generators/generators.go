@@ -466,6 +466,7 @@ func (g *APIGenerator) updateBackendServicesWithGenGo(ctx context.Context) error
 //   - config: Project configuration
 //   - protoTemplate: Proto template type (echo, crud, or none)
 //   - useLocalModules: Whether to use local egg modules for development
+//   - withDatabase: Whether to wire the database (model, repository, auto-migrate); required for crud
 //
 // Returns:
 //   - error: Creation error if any
@@ -475,7 +476,11 @@ func (g *APIGenerator) updateBackendServicesWithGenGo(ctx context.Context) error
 //
 // Performance:
 //   - Service scaffolding and module initialization
-func (g *BackendGenerator) Create(ctx context.Context, name string, config *configschema.Config, protoTemplate string, useLocalModules bool) error {
+func (g *BackendGenerator) Create(ctx context.Context, name string, config *configschema.Config, protoTemplate string, useLocalModules, withDatabase bool) error {
+	if protoTemplate == "crud" && !withDatabase {
+		return fmt.Errorf("the crud template requires a database")
+	}
+
 	ui.Info("Creating backend service: %s", name)
 
 	// Validate service name
@@ -617,7 +622,7 @@ func (g *BackendGenerator) Create(ctx context.Context, name string, config *conf
 	}
 
 	// Prepare template data
-	templateData := g.prepareTemplateData(name, serviceConfig, config, protoTemplate)
+	templateData := g.prepareTemplateData(name, serviceConfig, config, protoTemplate, withDatabase)
 
 	// Generate proto file if requested
 	if protoTemplate != "none" {
@@ -743,7 +748,8 @@ func (g *BackendGenerator) generateBackendFiles(name string, serviceConfig confi
 		"HealthPort":        templateData.HealthPort,
 		"MetricsPort":       templateData.MetricsPort,
 		"ProtoTemplate":     templateData.ProtoTemplate,
-		"HasDatabase":       templateData.ProtoTemplate == "crud",
+		"HasDatabase":       templateData.HasDatabase,
+		"IsCRUD":            templateData.IsCRUD,
 	}
 
 	// Generate main.go from template
@@ -773,8 +779,8 @@ func (g *BackendGenerator) generateBackendFiles(name string, serviceConfig confi
 		return fmt.Errorf("failed to write handler.go: %w", err)
 	}
 
-	// Generate model, repository, service, and errors only for CRUD templates
-	if templateData.ProtoTemplate == "crud" {
+	// Generate the service layer only for CRUD templates
+	if templateData.IsCRUD {
 		// Generate service placeholder from template
 		serviceGo, err := g.loader.LoadAndRender("backend/service.go.tmpl", data)
 		if err != nil {
@@ -783,7 +789,10 @@ func (g *BackendGenerator) generateBackendFiles(name string, serviceConfig confi
 		if err := g.fs.WriteFile(filepath.Join("backend", name, "internal", "service", "service.go"), serviceGo, 0644); err != nil {
 			return fmt.Errorf("failed to write service.go: %w", err)
 		}
+	}
 
+	// Generate model, repository, and errors for database-backed services
+	if templateData.HasDatabase {
 		// Generate model from template
 		modelGo, err := g.loader.LoadAndRender("backend/model.go.tmpl", data)
 		if err != nil {
@@ -1264,6 +1273,8 @@ func serviceNameToVar(name string) string {
 //   - name: Service name
 //   - serviceConfig: Service configuration
 //   - config: Project configuration
+//   - protoTemplate: Proto template type (echo, crud, or none)
+//   - withDatabase: Whether the service is wired to a database
 //
 // Returns:
 //   - *TemplateData: Prepared template data
@@ -1273,7 +1284,7 @@ func serviceNameToVar(name string) string {
 //
 // Performance:
 //   - O(1) data preparation
-func (g *BackendGenerator) prepareTemplateData(name string, serviceConfig configschema.BackendService, config *configschema.Config, protoTemplate string) *TemplateData {
+func (g *BackendGenerator) prepareTemplateData(name string, serviceConfig configschema.BackendService, config *configschema.Config, protoTemplate string, withDatabase bool) *TemplateData {
 	// Get ports with defaults
 	httpPort := 8080
 	healthPort := 8081
@@ -1311,7 +1322,8 @@ func (g *BackendGenerator) prepareTemplateData(name string, serviceConfig config
 		HealthPort:        healthPort,
 		MetricsPort:       metricsPort,
 		ProtoTemplate:     protoTemplate,
-		HasDatabase:       protoTemplate == "crud", // Database required for CRUD services
+		HasDatabase:       withDatabase,
+		IsCRUD:            protoTemplate == "crud",
 	}
 }
 
@@ -0,0 +1,87 @@
+package generators
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/cli/internal/templates"
+)
+
+func TestBackendMainTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		database bool
+		crud     bool
+		wanted   []string
+		unwanted []string
+	}{
+		{
+			name:     "minimal",
+			wanted:   []string{"handler.NewOrderHandler(app.Logger())", "servicex.WithMetricsConfig(true, true, false, false)"},
+			unwanted: []string{"gorm", "MustDB", "WithAutoMigrate", "RegisterServices", "internal/repository"},
+		},
+		{
+			name:     "database",
+			database: true,
+			wanted: []string{
+				"app.MustDB()",
+				"servicex.WithAutoMigrate(&model.Order{})",
+				"servicex.RegisterServices(app, map[string]any{",
+				"func(db *gorm.DB) repository.OrderRepository",
+				"func(logger log.Logger) *handler.OrderHandler",
+				"app.Resolve(&orderHandler)",
+			},
+			unwanted: []string{"internal/service", "service.OrderService"},
+		},
+		{
+			name:     "crud",
+			database: true,
+			crud:     true,
+			wanted: []string{
+				"servicex.WithAutoMigrate(&model.Order{})",
+				`"github.com/acme/shop/backend/order/internal/service"`,
+				"func(repo repository.OrderRepository, logger log.Logger) service.OrderService",
+				"func(svc service.OrderService, logger log.Logger) *handler.OrderHandler",
+				"app.Resolve(&orderHandler)",
+			},
+		},
+	}
+
+	loader := templates.NewLoader()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]any{
+				"ServiceName":       "order",
+				"ServiceNameVar":    "order",
+				"ServiceNameCamel":  "Order",
+				"ModulePrefix":      "github.com/acme/shop",
+				"ServiceModulePath": "github.com/acme/shop/backend/order",
+				"HasDatabase":       tt.database,
+				"IsCRUD":            tt.crud,
+			}
+
+			for _, name := range []string{"backend/main.go.tmpl", "backend/handler.go.tmpl"} {
+				rendered, err := loader.LoadAndRender(name, data)
+				if err != nil {
+					t.Fatalf("LoadAndRender(%s) error = %v", name, err)
+				}
+				if _, err := format.Source([]byte(rendered)); err != nil {
+					t.Fatalf("Generated %s does not parse: %v\n%s", name, err, rendered)
+				}
+			}
+
+			rendered, _ := loader.LoadAndRender("backend/main.go.tmpl", data)
+			for _, want := range tt.wanted {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("Generated main.go missing %q", want)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(rendered, unwanted) {
+					t.Errorf("Generated main.go should not contain %q", unwanted)
+				}
+			}
+		})
+	}
+}
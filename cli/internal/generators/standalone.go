@@ -328,6 +328,7 @@ func (g *StandaloneGenerator) prepareTemplateData(name, modulePath string, servi
 		MetricsPort:       serviceConfig.Ports.Metrics,
 		ProtoTemplate:     protoTemplate,
 		HasDatabase:       hasDatabase,
+		IsCRUD:            hasDatabase,
 	}
 }
 
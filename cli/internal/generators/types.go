@@ -59,5 +59,6 @@ type TemplateData struct {
 	HealthPort        int    // Health port (default: 8081)
 	MetricsPort       int    // Metrics port (default: 9091)
 	ProtoTemplate     string // Proto template type: "echo", "crud", or "none"
-	HasDatabase       bool   // Whether the service requires database (true for crud, false for echo)
+	HasDatabase       bool   // Whether the service is wired to a database (model, repository, auto-migrate)
+	IsCRUD            bool   // Whether the service implements the CRUD proto (handler backed by the service layer)
 }
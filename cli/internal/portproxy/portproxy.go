@@ -10,7 +10,13 @@
 // Usage:
 //
 //	manager := NewManager(runner, projectName, networkName)
-//	err := manager.CreateProxy(ctx, serviceName, servicePort, localPort)
+//	proxy, err := manager.CreateProxy(ctx, serviceName, servicePort, localPort)
+//	statuses, err := manager.Status(ctx)
+//	err = manager.StopAllProxies(ctx)
+//
+// Created proxies are recorded in a state file (default: .egg/proxies.json)
+// and reconciled against the running containers, so listing and stopping
+// work across invocations.
 package portproxy
 
 import (
@@ -29,14 +35,15 @@ type Manager struct {
 	runner      *toolrunner.Runner
 	projectName string
 	networkName string
+	statePath   string
 }
 
 // ProxyInfo represents a port proxy configuration.
 type ProxyInfo struct {
-	ServiceName string
-	ServicePort int
-	LocalPort   int
-	ProxyName   string
+	ServiceName string `json:"service_name"`
+	ServicePort int    `json:"service_port"`
+	LocalPort   int    `json:"local_port"`
+	ProxyName   string `json:"proxy_name"`
 }
 
 // NewManager creates a new port proxy manager.
@@ -59,9 +66,27 @@ func NewManager(runner *toolrunner.Runner, projectName, networkName string) *Man
 		runner:      runner,
 		projectName: projectName,
 		networkName: networkName,
+		statePath:   DefaultStatePath,
 	}
 }
 
+// SetStatePath sets the proxy state file location.
+//
+// Parameters:
+//   - path: State file path
+//
+// Returns:
+//   - None
+//
+// Concurrency:
+//   - Not safe for concurrent use with other methods
+//
+// Performance:
+//   - O(1)
+func (m *Manager) SetStatePath(path string) {
+	m.statePath = path
+}
+
 // CheckPortAvailable checks if a local port is available.
 //
 // Parameters:
@@ -196,12 +221,19 @@ func (m *Manager) CreateProxy(ctx context.Context, serviceName string, servicePo
 		return nil, fmt.Errorf("docker command failed: %s", result.Stderr)
 	}
 
-	return &ProxyInfo{
+	proxy := ProxyInfo{
 		ServiceName: serviceName,
 		ServicePort: servicePort,
 		LocalPort:   finalLocalPort,
 		ProxyName:   proxyName,
-	}, nil
+	}
+	if err := m.updateState(func(proxies []ProxyInfo) []ProxyInfo {
+		return append(withoutProxies(proxies, proxyName), proxy)
+	}); err != nil {
+		return &proxy, fmt.Errorf("proxy %s created but not recorded: %w", proxyName, err)
+	}
+
+	return &proxy, nil
 }
 
 // StopProxy stops a port proxy container.
@@ -232,10 +264,14 @@ func (m *Manager) StopProxy(ctx context.Context, proxyName string) error {
 		}
 	}
 
-	return nil
+	return m.updateState(func(proxies []ProxyInfo) []ProxyInfo {
+		return withoutProxies(proxies, proxyName)
+	})
 }
 
-// StopAllProxies stops all port proxy containers for the project.
+// StopAllProxies stops all port proxy containers for the project, both
+// those recorded in the state file and those found running in Docker.
+// Proxies that fail to stop stay recorded.
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -249,39 +285,30 @@ func (m *Manager) StopProxy(ctx context.Context, proxyName string) error {
 // Performance:
 //   - Docker container listing and stopping
 func (m *Manager) StopAllProxies(ctx context.Context) error {
-	// Find all proxy containers
-	args := []string{"ps", "--filter", fmt.Sprintf("name=%s-proxy-", m.projectName), "--format", "{{.Names}}"}
-	result, err := m.runner.Docker(ctx, args...)
+	statuses, err := m.Status(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list proxies: %w", err)
-	}
-
-	if result.ExitCode != 0 {
-		return fmt.Errorf("docker ps failed: %s", result.Stderr)
-	}
-
-	// Parse container names
-	containerNames := strings.TrimSpace(result.Stdout)
-	if containerNames == "" {
-		return nil // No proxies running
+		return err
 	}
 
-	names := strings.Split(containerNames, "\n")
-	for _, name := range names {
-		name = strings.TrimSpace(name)
-		if name == "" {
+	var failed []ProxyInfo
+	for _, status := range statuses {
+		if !status.Running {
 			continue
 		}
-		if err := m.StopProxy(ctx, name); err != nil {
+		if err := m.StopProxy(ctx, status.ProxyName); err != nil {
 			// Continue stopping other proxies even if one fails
-			fmt.Printf("Warning: failed to stop proxy %s: %v\n", name, err)
+			fmt.Printf("Warning: failed to stop proxy %s: %v\n", status.ProxyName, err)
+			failed = append(failed, status.ProxyInfo)
 		}
 	}
 
-	return nil
+	// Stale entries of proxies that no longer run are dropped as well
+	return m.updateState(func([]ProxyInfo) []ProxyInfo { return failed })
 }
 
 // ListProxies lists all running port proxy containers for the project.
+// Recorded proxies whose containers no longer run are removed from the
+// state file.
 //
 // Parameters:
 //   - ctx: Context for cancellation
@@ -296,8 +323,57 @@ func (m *Manager) StopAllProxies(ctx context.Context) error {
 // Performance:
 //   - Docker container inspection
 func (m *Manager) ListProxies(ctx context.Context) ([]ProxyInfo, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies := make([]ProxyInfo, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Running {
+			proxies = append(proxies, status.ProxyInfo)
+		}
+	}
+
+	if err := m.updateState(func([]ProxyInfo) []ProxyInfo { return proxies }); err != nil {
+		return nil, err
+	}
+	return proxies, nil
+}
+
+// Status returns every proxy known from the state file or running in
+// Docker, with where it was found. The state file is not modified.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - []ProxyStatus: Proxies sorted by proxy name
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - One state file read and one docker ps
+func (m *Manager) Status(ctx context.Context) ([]ProxyStatus, error) {
+	recorded, err := loadState(m.statePath)
+	if err != nil {
+		return nil, err
+	}
+	running, err := m.runningProxies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reconcile(recorded, running), nil
+}
+
+// runningProxies returns the running proxy containers of the project keyed
+// by container name.
+func (m *Manager) runningProxies(ctx context.Context) (map[string]ProxyInfo, error) {
 	// Find all proxy containers with port information
-	args := []string{"ps", "--filter", fmt.Sprintf("name=%s-proxy-", m.projectName), "--format", "{{.Names}}|{{.Ports}}"}
+	prefix := m.projectName + "-proxy-"
+	args := []string{"ps", "--filter", "name=" + prefix, "--format", "{{.Names}}|{{.Ports}}"}
 	result, err := m.runner.Docker(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list proxies: %w", err)
@@ -307,70 +383,73 @@ func (m *Manager) ListProxies(ctx context.Context) ([]ProxyInfo, error) {
 		return nil, fmt.Errorf("docker ps failed: %s", result.Stderr)
 	}
 
-	// Parse container names and ports
-	lines := strings.TrimSpace(result.Stdout)
-	if lines == "" {
-		return []ProxyInfo{}, nil // No proxies running
+	proxies := make(map[string]ProxyInfo)
+	for _, line := range strings.Split(strings.TrimSpace(result.Stdout), "\n") {
+		if proxy, ok := parseProxyLine(prefix, line); ok {
+			proxies[proxy.ProxyName] = proxy
+		}
 	}
+	return proxies, nil
+}
 
-	proxyLines := strings.Split(lines, "\n")
-	proxies := make([]ProxyInfo, 0, len(proxyLines))
+// parseProxyLine parses a "name|ports" line of docker ps output. Proxy
+// names have the form <project>-proxy-<service>-<port>.
+func parseProxyLine(prefix, line string) (ProxyInfo, bool) {
+	name, portsStr, ok := strings.Cut(strings.TrimSpace(line), "|")
+	if !ok {
+		return ProxyInfo{}, false
+	}
+	name = strings.TrimSpace(name)
+	portsStr = strings.TrimSpace(portsStr)
 
-	for _, line := range proxyLines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+	// docker ps name filters match substrings
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return ProxyInfo{}, false
+	}
+	idx := strings.LastIndex(rest, "-")
+	if idx <= 0 {
+		return ProxyInfo{}, false
+	}
+	servicePort, err := strconv.Atoi(rest[idx+1:])
+	if err != nil {
+		return ProxyInfo{}, false
+	}
 
-		// Parse format: "name|ports"
-		parts := strings.SplitN(line, "|", 2)
-		if len(parts) < 2 {
-			continue
+	// Extract local port from ports string (format: "0.0.0.0:XXXXX->YYYYY/tcp")
+	localPort := servicePort // Default to service port
+	if hostPart, _, found := strings.Cut(portsStr, "->"); found {
+		if colon := strings.LastIndex(hostPart, ":"); colon >= 0 {
+			if port, err := strconv.Atoi(hostPart[colon+1:]); err == nil {
+				localPort = port
+			}
 		}
+	}
 
-		name := strings.TrimSpace(parts[0])
-		portsStr := strings.TrimSpace(parts[1])
-
-		// Extract service name and port from proxy name
-		// Format: project-proxy-service-port
-		nameParts := strings.Split(name, "-")
-		if len(nameParts) < 3 {
-			continue
-		}
+	return ProxyInfo{
+		ProxyName:   name,
+		ServiceName: rest[:idx],
+		ServicePort: servicePort,
+		LocalPort:   localPort,
+	}, true
+}
 
-		// Try to parse port from name (last part)
-		portStr := nameParts[len(nameParts)-1]
-		servicePort, err := strconv.Atoi(portStr)
-		if err != nil {
-			continue
-		}
+// updateState applies update to the recorded proxies and saves the result.
+func (m *Manager) updateState(update func([]ProxyInfo) []ProxyInfo) error {
+	proxies, err := loadState(m.statePath)
+	if err != nil {
+		return err
+	}
+	return saveState(m.statePath, update(proxies))
+}
 
-		// Reconstruct service name (everything between "proxy" and port)
-		serviceName := strings.Join(nameParts[2:len(nameParts)-1], "-")
-
-		// Extract local port from ports string (format: "0.0.0.0:XXXXX->YYYYY/tcp")
-		localPort := servicePort // Default to service port
-		if portsStr != "" {
-			// Parse port mapping: "0.0.0.0:XXXXX->YYYYY/tcp"
-			portParts := strings.Split(portsStr, "->")
-			if len(portParts) >= 1 {
-				hostPortPart := strings.Split(portParts[0], ":")
-				if len(hostPortPart) >= 2 {
-					hostPortStr := strings.Split(hostPortPart[1], "/")[0]
-					if port, err := strconv.Atoi(hostPortStr); err == nil {
-						localPort = port
-					}
-				}
-			}
+// withoutProxies returns proxies without the one named name.
+func withoutProxies(proxies []ProxyInfo, name string) []ProxyInfo {
+	result := make([]ProxyInfo, 0, len(proxies))
+	for _, proxy := range proxies {
+		if proxy.ProxyName != name {
+			result = append(result, proxy)
 		}
-
-		proxies = append(proxies, ProxyInfo{
-			ProxyName:   name,
-			ServiceName: serviceName,
-			ServicePort: servicePort,
-			LocalPort:   localPort,
-		})
 	}
-
-	return proxies, nil
+	return result
 }
@@ -0,0 +1,96 @@
+package portproxy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".egg", "proxies.json")
+
+	proxies, err := loadState(path)
+	if err != nil || proxies != nil {
+		t.Fatalf("loadState() on missing file = %v, %v", proxies, err)
+	}
+
+	want := []ProxyInfo{
+		{ProxyName: "shop-proxy-user-8080", ServiceName: "user", ServicePort: 8080, LocalPort: 8080},
+		{ProxyName: "shop-proxy-greet-8080", ServiceName: "greet", ServicePort: 8080, LocalPort: 8081},
+	}
+	if err := saveState(path, want); err != nil {
+		t.Fatalf("saveState() error = %v", err)
+	}
+	got, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []ProxyInfo{want[1], want[0]}) {
+		t.Errorf("loadState() = %+v", got)
+	}
+
+	if err := saveState(path, nil); err != nil {
+		t.Fatalf("saveState(nil) error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected empty state to remove the file, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if proxies, err := loadState(path); err != nil || proxies != nil {
+		t.Errorf("loadState() on corrupt file = %v, %v", proxies, err)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	recorded := []ProxyInfo{
+		{ProxyName: "my-shop-proxy-user-api-8080", ServiceName: "user_api", ServicePort: 8080, LocalPort: 8080},
+		{ProxyName: "my-shop-proxy-greet-8080", ServiceName: "greet", ServicePort: 8080, LocalPort: 8081},
+	}
+	running := map[string]ProxyInfo{
+		"my-shop-proxy-user-api-8080": {ProxyName: "my-shop-proxy-user-api-8080", ServiceName: "user-api", ServicePort: 8080, LocalPort: 18080},
+		"my-shop-proxy-admin-3000":    {ProxyName: "my-shop-proxy-admin-3000", ServiceName: "admin", ServicePort: 3000, LocalPort: 3000},
+	}
+
+	got := reconcile(recorded, running)
+	want := []ProxyStatus{
+		{ProxyInfo: running["my-shop-proxy-admin-3000"], Running: true},
+		{ProxyInfo: recorded[1], Recorded: true},
+		{ProxyInfo: ProxyInfo{ProxyName: "my-shop-proxy-user-api-8080", ServiceName: "user_api", ServicePort: 8080, LocalPort: 18080}, Recorded: true, Running: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reconcile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseProxyLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want ProxyInfo
+		ok   bool
+	}{
+		{
+			line: "my-shop-proxy-user-api-8080|0.0.0.0:18080->18080/tcp, [::]:18080->18080/tcp",
+			want: ProxyInfo{ProxyName: "my-shop-proxy-user-api-8080", ServiceName: "user-api", ServicePort: 8080, LocalPort: 18080},
+			ok:   true,
+		},
+		{
+			line: "my-shop-proxy-greet-9091|",
+			want: ProxyInfo{ProxyName: "my-shop-proxy-greet-9091", ServiceName: "greet", ServicePort: 9091, LocalPort: 9091},
+			ok:   true,
+		},
+		{line: "other-my-shop-proxy-greet-9091|", ok: false},
+		{line: "my-shop-proxy-greet|", ok: false},
+		{line: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseProxyLine("my-shop-proxy-", tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseProxyLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
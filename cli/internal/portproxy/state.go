@@ -0,0 +1,115 @@
+package portproxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DefaultStatePath is the proxy state file location relative to the
+// project root.
+const DefaultStatePath = ".egg/proxies.json"
+
+// stateVersion is bumped when the state file format changes.
+const stateVersion = 1
+
+// stateFile is the on-disk proxy state.
+type stateFile struct {
+	Version int         `json:"version"`
+	Proxies []ProxyInfo `json:"proxies"`
+}
+
+// ProxyStatus is a proxy as known from the state file and Docker.
+type ProxyStatus struct {
+	ProxyInfo
+	Recorded bool `json:"recorded"` // Listed in the state file
+	Running  bool `json:"running"`  // Container is running
+}
+
+// loadState reads the proxies recorded in the state file. A missing or
+// corrupt file, or a file of another version, yields no proxies.
+func loadState(path string) ([]ProxyInfo, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy state: %w", err)
+	}
+
+	// A corrupt file is treated like a missing one; running proxies are
+	// still found through Docker
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil || state.Version != stateVersion {
+		return nil, nil
+	}
+	return state.Proxies, nil
+}
+
+// saveState atomically writes the proxies to the state file, sorted by
+// proxy name. An empty list removes the file.
+func saveState(path string, proxies []ProxyInfo) error {
+	if len(proxies) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove proxy state: %w", err)
+		}
+		return nil
+	}
+
+	sorted := append([]ProxyInfo(nil), proxies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ProxyName < sorted[j].ProxyName })
+	data, err := json.MarshalIndent(stateFile{Version: stateVersion, Proxies: sorted}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create proxy state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".proxies-*")
+	if err != nil {
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write proxy state: %w", err)
+	}
+	return nil
+}
+
+// reconcile merges the recorded proxies with the running containers.
+// Recorded details win over those parsed from container names, since
+// names cannot be split unambiguously; the local port is taken from
+// Docker when the container runs. The result is sorted by proxy name.
+func reconcile(recorded []ProxyInfo, running map[string]ProxyInfo) []ProxyStatus {
+	byName := make(map[string]*ProxyStatus, len(recorded)+len(running))
+	for _, proxy := range recorded {
+		byName[proxy.ProxyName] = &ProxyStatus{ProxyInfo: proxy, Recorded: true}
+	}
+	for name, proxy := range running {
+		if status, ok := byName[name]; ok {
+			status.Running = true
+			status.LocalPort = proxy.LocalPort
+			continue
+		}
+		byName[name] = &ProxyStatus{ProxyInfo: proxy, Running: true}
+	}
+
+	statuses := make([]ProxyStatus, 0, len(byName))
+	for _, status := range byName {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].ProxyName < statuses[j].ProxyName })
+	return statuses
+}
@@ -11,6 +11,9 @@
 //
 //	renderer := NewRenderer(fs, refParser)
 //	err := renderer.Render(config)
+//
+//	services, err := renderer.SelectServices(config, []string{"user"}, nil)
+//	err = renderer.RenderServices(config, services)
 package compose
 
 import (
@@ -78,15 +81,42 @@ func NewRenderer(fs *projectfs.ProjectFS, refParser *ref.Parser) *Renderer {
 // Performance:
 //   - Template rendering and file I/O
 func (r *Renderer) Render(config *configschema.Config) error {
+	return r.RenderServices(config, nil)
+}
+
+// RenderServices renders Docker Compose configuration for a subset of
+// services, as returned by SelectServices.
+//
+// Parameters:
+//   - config: Project configuration
+//   - services: Services to render (nil renders all)
+//
+// Returns:
+//   - error: Rendering error if any
+//
+// Concurrency:
+//   - Single-threaded
+//
+// Performance:
+//   - Template rendering and file I/O
+func (r *Renderer) RenderServices(config *configschema.Config, services []string) error {
 	ui.Info("Rendering Docker Compose configuration...")
 
+	var selected map[string]bool
+	if services != nil {
+		selected = make(map[string]bool, len(services))
+		for _, name := range services {
+			selected[name] = true
+		}
+	}
+
 	// Create deploy/compose directory
 	if err := r.fs.CreateDirectory("deploy/compose"); err != nil {
 		return fmt.Errorf("failed to create deploy/compose directory: %w", err)
 	}
 
 	// Generate compose.yaml
-	composeYAML, err := r.generateComposeYAML(config)
+	composeYAML, err := r.generateComposeYAML(config, selected)
 	if err != nil {
 		return fmt.Errorf("failed to generate compose.yaml: %w", err)
 	}
@@ -104,6 +134,7 @@ func (r *Renderer) Render(config *configschema.Config) error {
 //
 // Parameters:
 //   - config: Project configuration
+//   - selected: Services to render (nil renders all)
 //
 // Returns:
 //   - string: Compose YAML content
@@ -114,14 +145,19 @@ func (r *Renderer) Render(config *configschema.Config) error {
 //
 // Performance:
 //   - String building and template rendering
-func (r *Renderer) generateComposeYAML(config *configschema.Config) (string, error) {
+func (r *Renderer) generateComposeYAML(config *configschema.Config, selected map[string]bool) (string, error) {
 	var builder strings.Builder
+	include := func(name string) bool { return selected == nil || selected[name] }
+	includeDatabase := config.Database.Enabled && include(DatabaseService)
 
 	// Write Compose header (no version field - Docker Compose no longer requires it)
 	builder.WriteString("services:\n")
 
 	// Render backend services
 	for name, service := range config.Backend {
+		if !include(name) {
+			continue
+		}
 		serviceYAML, err := r.renderBackendService(name, service, config)
 		if err != nil {
 			return "", fmt.Errorf("failed to render backend service %s: %w", name, err)
@@ -132,6 +168,9 @@ func (r *Renderer) generateComposeYAML(config *configschema.Config) (string, err
 
 	// Render frontend services
 	for name, service := range config.Frontend {
+		if !include(name) {
+			continue
+		}
 		serviceYAML, err := r.renderFrontendService(name, service, config)
 		if err != nil {
 			return "", fmt.Errorf("failed to render frontend service %s: %w", name, err)
@@ -141,14 +180,14 @@ func (r *Renderer) generateComposeYAML(config *configschema.Config) (string, err
 	}
 
 	// Add database service if enabled
-	if config.Database.Enabled {
+	if includeDatabase {
 		databaseYAML := r.renderDatabaseService(config.Database, config.ProjectName)
 		builder.WriteString(databaseYAML)
 		builder.WriteString("\n")
 	}
 
 	// Add volumes section if database is enabled
-	if config.Database.Enabled {
+	if includeDatabase {
 		builder.WriteString("volumes:\n")
 		builder.WriteString("  mysql_data:\n\n")
 	}
@@ -196,159 +235,23 @@ func (r *Renderer) renderBackendService(name string, service configschema.Backen
 
 	// Environment variables (servicex standard)
 	builder.WriteString("    environment:\n")
-
-	// Service Identity (servicex required)
-	builder.WriteString("      # Service Identity (servicex standard)\n")
-	builder.WriteString("      - SERVICE_NAME=" + name + "\n")
-	builder.WriteString("      - SERVICE_VERSION=" + config.Version + "\n")
-
-	// Environment (servicex standard: ENV, not APP_ENV)
-	// Priority: service.env.common.ENV > env.backend.ENV > env.global.ENV > default "production"
-	envValue := "production" // default for docker compose
-	if envVal, exists := service.Env.Common["ENV"]; exists && envVal != "" {
-		envValue = envVal
-	} else if envVal, exists := config.Env.Backend["ENV"]; exists && envVal != "" {
-		envValue = envVal
-	} else if envVal, exists := config.Env.Global["ENV"]; exists && envVal != "" {
-		envValue = envVal
-	}
-	builder.WriteString("      - ENV=" + envValue + "\n")
-
-	// Logging Configuration (servicex standard)
-	// Priority: service.env.common.LOG_LEVEL > env.backend.LOG_LEVEL > env.global.LOG_LEVEL > default "info"
-	logLevel := "info" // default
-	if logLevelVal, exists := service.Env.Common["LOG_LEVEL"]; exists && logLevelVal != "" {
-		logLevel = logLevelVal
-	} else if logLevelVal, exists := config.Env.Backend["LOG_LEVEL"]; exists && logLevelVal != "" {
-		logLevel = logLevelVal
-	} else if logLevelVal, exists := config.Env.Global["LOG_LEVEL"]; exists && logLevelVal != "" {
-		logLevel = logLevelVal
-	}
-	builder.WriteString("      # Logging Configuration (servicex standard)\n")
-	builder.WriteString("      # Supported levels: debug, info, warn, error (default: info)\n")
-	builder.WriteString("      - LOG_LEVEL=" + logLevel + "\n")
-
-	// Port Configuration (servicex BaseConfig)
-	builder.WriteString("      # Port Configuration (servicex BaseConfig)\n")
-	builder.WriteString("      - HTTP_PORT=" + strconv.Itoa(ports.HTTP) + "\n")
-	builder.WriteString("      - HEALTH_PORT=" + strconv.Itoa(ports.Health) + "\n")
-	builder.WriteString("      - METRICS_PORT=" + strconv.Itoa(ports.Metrics) + "\n")
-
-	// Database Configuration (servicex BaseConfig.Database)
-	if config.Database.Enabled {
-		builder.WriteString("      # Database Configuration (servicex BaseConfig.Database)\n")
-		builder.WriteString("      - DB_DRIVER=mysql\n")
-		builder.WriteString("      - DB_DSN=" + config.Database.User + ":" + config.Database.Password + "@tcp(mysql:3306)/" + config.Database.Database + "?charset=utf8mb4&parseTime=True&loc=Local\n")
-
-		// Database connection pool settings (only if overridden from defaults)
-		// Defaults: DB_MAX_IDLE=10, DB_MAX_OPEN=100, DB_MAX_LIFETIME=1h, DB_PING_TIMEOUT=5s
-		if dbMaxIdle, exists := config.Env.Backend["DB_MAX_IDLE"]; exists && dbMaxIdle != "" {
-			builder.WriteString("      - DB_MAX_IDLE=" + dbMaxIdle + "\n")
-		}
-		if dbMaxOpen, exists := config.Env.Backend["DB_MAX_OPEN"]; exists && dbMaxOpen != "" {
-			builder.WriteString("      - DB_MAX_OPEN=" + dbMaxOpen + "\n")
-		}
-		if dbMaxLifetime, exists := config.Env.Backend["DB_MAX_LIFETIME"]; exists && dbMaxLifetime != "" {
-			builder.WriteString("      - DB_MAX_LIFETIME=" + dbMaxLifetime + "\n")
-		}
-		if dbPingTimeout, exists := config.Env.Backend["DB_PING_TIMEOUT"]; exists && dbPingTimeout != "" {
-			builder.WriteString("      - DB_PING_TIMEOUT=" + dbPingTimeout + "\n")
-		}
-	}
-
-	// Metrics Configuration (servicex standard)
-	// Default: ENABLE_METRICS=true, only set if explicitly disabled
-	enableMetrics := "true"
-	if metricsVal, exists := service.Env.Common["ENABLE_METRICS"]; exists && metricsVal != "" {
-		enableMetrics = metricsVal
-	} else if metricsVal, exists := config.Env.Backend["ENABLE_METRICS"]; exists && metricsVal != "" {
-		enableMetrics = metricsVal
-	} else if metricsVal, exists := config.Env.Global["ENABLE_METRICS"]; exists && metricsVal != "" {
-		enableMetrics = metricsVal
-	}
-	if enableMetrics != "true" {
-		builder.WriteString("      - ENABLE_METRICS=" + enableMetrics + "\n")
-	}
-
-	// RPC Configuration (servicex standard)
-	// Default: SLOW_REQUEST_MILLIS=1000, only set if overridden
-	if slowRequestMillis, exists := service.Env.Common["SLOW_REQUEST_MILLIS"]; exists && slowRequestMillis != "" && slowRequestMillis != "1000" {
-		builder.WriteString("      - SLOW_REQUEST_MILLIS=" + slowRequestMillis + "\n")
-	} else if slowRequestMillis, exists := config.Env.Backend["SLOW_REQUEST_MILLIS"]; exists && slowRequestMillis != "" && slowRequestMillis != "1000" {
-		builder.WriteString("      - SLOW_REQUEST_MILLIS=" + slowRequestMillis + "\n")
-	} else if slowRequestMillis, exists := config.Env.Global["SLOW_REQUEST_MILLIS"]; exists && slowRequestMillis != "" && slowRequestMillis != "1000" {
-		builder.WriteString("      - SLOW_REQUEST_MILLIS=" + slowRequestMillis + "\n")
-	}
-
-	// Shutdown Configuration (servicex standard)
-	// Default: SHUTDOWN_TIMEOUT=15s, only set if overridden
-	if shutdownTimeout, exists := service.Env.Common["SHUTDOWN_TIMEOUT"]; exists && shutdownTimeout != "" && shutdownTimeout != "15s" {
-		builder.WriteString("      - SHUTDOWN_TIMEOUT=" + shutdownTimeout + "\n")
-	} else if shutdownTimeout, exists := config.Env.Backend["SHUTDOWN_TIMEOUT"]; exists && shutdownTimeout != "" && shutdownTimeout != "15s" {
-		builder.WriteString("      - SHUTDOWN_TIMEOUT=" + shutdownTimeout + "\n")
-	} else if shutdownTimeout, exists := config.Env.Global["SHUTDOWN_TIMEOUT"]; exists && shutdownTimeout != "" && shutdownTimeout != "15s" {
-		builder.WriteString("      - SHUTDOWN_TIMEOUT=" + shutdownTimeout + "\n")
-	}
-
-	// Custom environment variables (excluding servicex standard vars to avoid duplication)
-	servicexVars := map[string]bool{
-		"SERVICE_NAME":        true,
-		"SERVICE_VERSION":     true,
-		"ENV":                 true,
-		"LOG_LEVEL":           true,
-		"HTTP_PORT":           true,
-		"HEALTH_PORT":         true,
-		"METRICS_PORT":        true,
-		"DB_DRIVER":           true,
-		"DB_DSN":              true,
-		"DB_MAX_IDLE":         true,
-		"DB_MAX_OPEN":         true,
-		"DB_MAX_LIFETIME":     true,
-		"DB_PING_TIMEOUT":     true,
-		"ENABLE_METRICS":      true,
-		"SLOW_REQUEST_MILLIS": true,
-		"SHUTDOWN_TIMEOUT":    true,
-	}
-
-	// Global environment (excluding servicex standard vars)
-	for key, value := range config.Env.Global {
-		if !servicexVars[key] {
-			builder.WriteString("      - " + key + "=" + value + "\n")
-		}
-	}
-
-	// Backend environment (excluding servicex standard vars)
-	for key, value := range config.Env.Backend {
-		if !servicexVars[key] {
-			builder.WriteString("      - " + key + "=" + value + "\n")
-		}
-	}
-
-	// Service-specific environment (excluding servicex standard vars)
-	for key, value := range service.Env.Common {
-		if !servicexVars[key] {
-			builder.WriteString("      - " + key + "=" + value + "\n")
-		}
+	env, err := r.BackendEnv(config, name)
+	if err != nil {
+		return "", err
 	}
-
-	// Docker-specific environment
-	for key, value := range service.Env.Docker {
-		if !servicexVars[key] {
-			// Resolve expressions for Compose environment
-			resolved, err := r.refParser.ReplaceAll(value, ref.EnvironmentCompose, config)
-			if err != nil {
-				return "", fmt.Errorf("failed to resolve expression %s: %w", value, err)
-			}
-			builder.WriteString("      - " + key + "=" + resolved + "\n")
+	for _, v := range env {
+		for _, comment := range envComments[v.Name] {
+			builder.WriteString("      # " + comment + "\n")
 		}
+		builder.WriteString("      - " + v.Name + "=" + v.Value + "\n")
 	}
 
-	// Dependencies
+	// Dependencies (database first, then declared depends_on)
+	dependsOn := append([]string{}, service.DependsOn...)
 	if config.Database.Enabled {
-		builder.WriteString("    depends_on:\n")
-		builder.WriteString("      mysql:\n")
-		builder.WriteString("        condition: service_healthy\n")
+		dependsOn = append([]string{DatabaseService}, dependsOn...)
 	}
+	writeDependsOn(&builder, dependsOn)
 
 	// Networks
 	builder.WriteString("    networks:\n")
@@ -401,16 +304,18 @@ func (r *Renderer) renderFrontendService(name string, service configschema.Front
 	// Environment variables
 	builder.WriteString("    environment:\n")
 
-	// Global environment
-	for key, value := range config.Env.Global {
-		builder.WriteString("      - " + key + "=" + value + "\n")
+	// Global environment overridden by frontend environment
+	env, err := r.FrontendEnv(config, name)
+	if err != nil {
+		return "", err
 	}
-
-	// Frontend environment
-	for key, value := range config.Env.Frontend {
-		builder.WriteString("      - " + key + "=" + value + "\n")
+	for _, v := range env {
+		builder.WriteString("      - " + v.Name + "=" + v.Value + "\n")
 	}
 
+	// Dependencies
+	writeDependsOn(&builder, service.DependsOn)
+
 	// Networks
 	builder.WriteString("    networks:\n")
 	builder.WriteString("      - " + config.ProjectName + "-network\n")
@@ -426,6 +331,27 @@ func (r *Renderer) renderFrontendService(name string, service configschema.Front
 	return builder.String(), nil
 }
 
+// envComments are the section comments written before standard variables.
+var envComments = map[string][]string{
+	"SERVICE_NAME": {"Service Identity (servicex standard)"},
+	"LOG_LEVEL":    {"Logging Configuration (servicex standard)", "Supported levels: debug, info, warn, error (default: info)"},
+	"HTTP_PORT":    {"Port Configuration (servicex BaseConfig)"},
+	"DB_DRIVER":    {"Database Configuration (servicex BaseConfig.Database)"},
+}
+
+// writeDependsOn writes a depends_on section waiting for each service to
+// become healthy. Every rendered service defines a health check.
+func writeDependsOn(builder *strings.Builder, services []string) {
+	if len(services) == 0 {
+		return
+	}
+	builder.WriteString("    depends_on:\n")
+	for _, name := range services {
+		builder.WriteString("      " + name + ":\n")
+		builder.WriteString("        condition: service_healthy\n")
+	}
+}
+
 // renderDatabaseService renders the database service configuration.
 //
 // Parameters:
@@ -0,0 +1,171 @@
+package compose
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+)
+
+func testConfig() *configschema.Config {
+	return &configschema.Config{
+		ProjectName:    "shop",
+		Version:        "1.0.0",
+		DockerRegistry: "ghcr.io/acme",
+		BackendDefaults: configschema.BackendDefaultsConfig{
+			Ports: configschema.PortConfig{HTTP: 8080, Health: 8081, Metrics: 9091},
+		},
+		Database: configschema.DatabaseConfig{Enabled: true, Image: "mysql:9.4", Port: 3306},
+		Backend: map[string]configschema.BackendService{
+			"order": {
+				Profiles: []string{"checkout"},
+				Env: configschema.BackendEnvConfig{
+					Docker: map[string]string{"PAYMENT_URL": "http://${svc:payment}:8080"},
+				},
+			},
+			"payment": {DependsOn: []string{"ledger"}},
+			"ledger":  {},
+			"user":    {Profiles: []string{"core"}},
+		},
+		Frontend: map[string]configschema.FrontendService{
+			"web": {DependsOn: []string{"user"}, Profiles: []string{"core"}},
+		},
+	}
+}
+
+func TestSelectServices(t *testing.T) {
+	renderer := NewRenderer(nil, ref.NewParser())
+	config := testConfig()
+
+	tests := []struct {
+		name     string
+		names    []string
+		profiles []string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:  "service closure follows refs and depends_on",
+			names: []string{"order"},
+			want:  []string{"ledger", "mysql", "order", "payment"},
+		},
+		{
+			name:     "profile",
+			profiles: []string{"core"},
+			want:     []string{"mysql", "user", "web"},
+		},
+		{
+			name:  "database only",
+			names: []string{"mysql"},
+			want:  []string{"mysql"},
+		},
+		{
+			name:    "unknown service",
+			names:   []string{"nope"},
+			wantErr: "unknown service: nope",
+		},
+		{
+			name:     "empty profile",
+			profiles: []string{"batch"},
+			wantErr:  "no services in profile: batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.SelectServices(config, tt.names, tt.profiles)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SelectServices() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectServices() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectServices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateComposeYAMLSelected(t *testing.T) {
+	renderer := NewRenderer(nil, ref.NewParser())
+	config := testConfig()
+
+	yaml, err := renderer.generateComposeYAML(config, map[string]bool{"payment": true, "ledger": true, "mysql": true})
+	if err != nil {
+		t.Fatalf("generateComposeYAML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"\n  payment:\n",
+		"\n  ledger:\n",
+		"\n  mysql:\n",
+		"      mysql:\n        condition: service_healthy\n      ledger:\n        condition: service_healthy\n",
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("compose.yaml missing %q:\n%s", want, yaml)
+		}
+	}
+	for _, unwanted := range []string{"\n  order:\n", "\n  user:\n", "\n  web:\n"} {
+		if strings.Contains(yaml, unwanted) {
+			t.Errorf("compose.yaml should not contain %q:\n%s", unwanted, yaml)
+		}
+	}
+}
+
+func TestBackendEnv(t *testing.T) {
+	renderer := NewRenderer(nil, ref.NewParser())
+	config := testConfig()
+	config.Env = configschema.EnvConfig{
+		Global:  map[string]string{"LOG_LEVEL": "warn", "REGION": "eu", "TRACE": "off"},
+		Backend: map[string]string{"REGION": "us", "DB_MAX_OPEN": "20"},
+	}
+	order := config.Backend["order"]
+	order.Env.Common = map[string]string{"TRACE": "on", "LOG_LEVEL": "debug", "HTTP_PORT": "1"}
+	config.Backend["order"] = order
+
+	env, err := renderer.BackendEnv(config, "order")
+	if err != nil {
+		t.Fatalf("BackendEnv() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, v := range env {
+		if _, dup := got[v.Name]; dup {
+			t.Errorf("Duplicate variable %s", v.Name)
+		}
+		got[v.Name] = v.Value
+	}
+	for key, want := range map[string]string{
+		"SERVICE_NAME": "order",
+		"LOG_LEVEL":    "debug",
+		"HTTP_PORT":    "8080",
+		"REGION":       "us",
+		"TRACE":        "on",
+		"DB_MAX_OPEN":  "20",
+		"PAYMENT_URL":  "http://payment:8080",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %q, want %q", key, got[key], want)
+		}
+	}
+
+	yaml, err := renderer.generateComposeYAML(config, map[string]bool{"order": true})
+	if err != nil {
+		t.Fatalf("generateComposeYAML() error = %v", err)
+	}
+	for _, v := range env {
+		if line := "      - " + v.Name + "=" + v.Value + "\n"; !strings.Contains(yaml, line) {
+			t.Errorf("compose.yaml missing %q", line)
+		}
+	}
+
+	if _, err := renderer.BackendEnv(config, "nope"); err == nil {
+		t.Error("Expected error for unknown service")
+	}
+}
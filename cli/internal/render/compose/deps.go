@@ -0,0 +1,169 @@
+package compose
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+)
+
+// DatabaseService is the Compose service name of the database.
+const DatabaseService = "mysql"
+
+// Dependencies returns the services each service needs at runtime: its
+// declared depends_on entries plus the services referenced by ${svc:...}
+// expressions in its common and docker environment. Only defined backend
+// and frontend services are returned.
+//
+// Parameters:
+//   - config: Project configuration
+//
+// Returns:
+//   - map[string][]string: Sorted dependencies keyed by service name
+//   - error: Expression parsing error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of environment values
+func (r *Renderer) Dependencies(config *configschema.Config) (map[string][]string, error) {
+	graph := make(map[string][]string)
+
+	for name, service := range config.Backend {
+		deps := make(map[string]bool)
+		for _, dep := range service.DependsOn {
+			deps[dep] = true
+		}
+		for _, env := range []map[string]string{service.Env.Common, service.Env.Docker} {
+			for key, value := range env {
+				expressions, err := r.refParser.ParseAll(value)
+				if err != nil {
+					return nil, fmt.Errorf("backend.%s.env %s: %w", name, key, err)
+				}
+				for _, expr := range expressions {
+					if expr.Type == ref.TypeService {
+						deps[expr.Resource] = true
+					}
+				}
+			}
+		}
+		graph[name] = definedServices(config, name, deps)
+	}
+
+	for name, service := range config.Frontend {
+		deps := make(map[string]bool)
+		for _, dep := range service.DependsOn {
+			deps[dep] = true
+		}
+		graph[name] = definedServices(config, name, deps)
+	}
+
+	return graph, nil
+}
+
+// SelectServices resolves the services to start: the named services and
+// the services in any of the given profiles, plus everything they depend
+// on transitively. The database is selected by name (mysql) or when a
+// selected backend service uses it.
+//
+// Parameters:
+//   - config: Project configuration
+//   - names: Service names to start
+//   - profiles: Profiles to start
+//
+// Returns:
+//   - []string: Sorted selected services
+//   - error: Error for unknown services or profiles without services
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(V+E) graph traversal
+func (r *Renderer) SelectServices(config *configschema.Config, names, profiles []string) ([]string, error) {
+	graph, err := r.Dependencies(config)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool)
+	var queue []string
+	add := func(name string) {
+		if !selected[name] {
+			selected[name] = true
+			queue = append(queue, name)
+		}
+	}
+
+	for _, name := range names {
+		if name == DatabaseService && config.Database.Enabled {
+			selected[name] = true
+			continue
+		}
+		if _, ok := graph[name]; !ok {
+			return nil, fmt.Errorf("unknown service: %s", name)
+		}
+		add(name)
+	}
+
+	for _, profile := range profiles {
+		matched := false
+		for name, service := range config.Backend {
+			if slices.Contains(service.Profiles, profile) {
+				add(name)
+				matched = true
+			}
+		}
+		for name, service := range config.Frontend {
+			if slices.Contains(service.Profiles, profile) {
+				add(name)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no services in profile: %s", profile)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dep := range graph[name] {
+			add(dep)
+		}
+	}
+
+	if config.Database.Enabled {
+		for name := range selected {
+			if _, ok := config.Backend[name]; ok {
+				selected[DatabaseService] = true
+				break
+			}
+		}
+	}
+
+	services := make([]string, 0, len(selected))
+	for name := range selected {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// definedServices returns the sorted names in deps that are defined
+// backend or frontend services other than self.
+func definedServices(config *configschema.Config, self string, deps map[string]bool) []string {
+	var result []string
+	for dep := range deps {
+		_, isBackend := config.Backend[dep]
+		_, isFrontend := config.Frontend[dep]
+		if dep != self && (isBackend || isFrontend) {
+			result = append(result, dep)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
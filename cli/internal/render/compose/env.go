@@ -0,0 +1,201 @@
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+)
+
+// EnvVar is a single environment variable of a Compose service.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// servicexVars are the servicex standard variables. They are derived from
+// egg.yaml (ports, database) or read with their own precedence, and are
+// never copied from the custom environment layers.
+var servicexVars = map[string]bool{
+	"SERVICE_NAME":        true,
+	"SERVICE_VERSION":     true,
+	"ENV":                 true,
+	"LOG_LEVEL":           true,
+	"HTTP_PORT":           true,
+	"HEALTH_PORT":         true,
+	"METRICS_PORT":        true,
+	"DB_DRIVER":           true,
+	"DB_DSN":              true,
+	"DB_MAX_IDLE":         true,
+	"DB_MAX_OPEN":         true,
+	"DB_MAX_LIFETIME":     true,
+	"DB_PING_TIMEOUT":     true,
+	"ENABLE_METRICS":      true,
+	"SLOW_REQUEST_MILLIS": true,
+	"SHUTDOWN_TIMEOUT":    true,
+}
+
+// BackendEnv returns the environment a backend service gets in Docker
+// Compose: the servicex standard variables first, then the custom
+// variables sorted by name. Custom layers are merged with later layers
+// overriding earlier ones: env.global, env.backend, the service's common
+// env and its docker env. Expressions in the docker env are resolved.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Backend service name
+//
+// Returns:
+//   - []EnvVar: Service environment
+//   - error: Error for unknown services or unresolvable expressions
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of variables
+func (r *Renderer) BackendEnv(config *configschema.Config, name string) ([]EnvVar, error) {
+	service, ok := config.Backend[name]
+	if !ok {
+		return nil, fmt.Errorf("backend service not found: %s", name)
+	}
+
+	ports := service.Ports
+	if ports == nil {
+		ports = &config.BackendDefaults.Ports
+	}
+
+	// Standard variables: service env wins over env.backend over env.global
+	standard := []map[string]string{service.Env.Common, config.Env.Backend, config.Env.Global}
+	lookup := func(key, fallback string) string {
+		for _, layer := range standard {
+			if value, exists := layer[key]; exists && value != "" {
+				return value
+			}
+		}
+		return fallback
+	}
+
+	env := []EnvVar{
+		{Name: "SERVICE_NAME", Value: name},
+		{Name: "SERVICE_VERSION", Value: config.Version},
+		{Name: "ENV", Value: lookup("ENV", "production")},
+		{Name: "LOG_LEVEL", Value: lookup("LOG_LEVEL", "info")},
+		{Name: "HTTP_PORT", Value: strconv.Itoa(ports.HTTP)},
+		{Name: "HEALTH_PORT", Value: strconv.Itoa(ports.Health)},
+		{Name: "METRICS_PORT", Value: strconv.Itoa(ports.Metrics)},
+	}
+
+	if config.Database.Enabled {
+		env = append(env,
+			EnvVar{Name: "DB_DRIVER", Value: "mysql"},
+			EnvVar{Name: "DB_DSN", Value: config.Database.User + ":" + config.Database.Password + "@tcp(mysql:3306)/" + config.Database.Database + "?charset=utf8mb4&parseTime=True&loc=Local"},
+		)
+
+		// Connection pool settings only if overridden from servicex defaults
+		for _, key := range []string{"DB_MAX_IDLE", "DB_MAX_OPEN", "DB_MAX_LIFETIME", "DB_PING_TIMEOUT"} {
+			if value, exists := config.Env.Backend[key]; exists && value != "" {
+				env = append(env, EnvVar{Name: key, Value: value})
+			}
+		}
+	}
+
+	// Runtime settings only if overridden from servicex defaults
+	for _, setting := range []struct{ key, fallback string }{
+		{"ENABLE_METRICS", "true"},
+		{"SLOW_REQUEST_MILLIS", "1000"},
+		{"SHUTDOWN_TIMEOUT", "15s"},
+	} {
+		if value := lookup(setting.key, setting.fallback); value != setting.fallback {
+			env = append(env, EnvVar{Name: setting.key, Value: value})
+		}
+	}
+
+	custom := make(map[string]string)
+	for _, layer := range []map[string]string{config.Env.Global, config.Env.Backend, service.Env.Common} {
+		for key, value := range layer {
+			if !servicexVars[key] {
+				custom[key] = value
+			}
+		}
+	}
+	for key, value := range service.Env.Docker {
+		if servicexVars[key] {
+			continue
+		}
+		resolved, err := r.resolveValue(config, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve expression %s: %w", value, err)
+		}
+		custom[key] = resolved
+	}
+
+	return append(env, sortedEnv(custom)...), nil
+}
+
+// FrontendEnv returns the environment a frontend service gets in Docker
+// Compose: env.global overridden by env.frontend, sorted by name.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Frontend service name
+//
+// Returns:
+//   - []EnvVar: Service environment
+//   - error: Error for unknown services
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of variables
+func (r *Renderer) FrontendEnv(config *configschema.Config, name string) ([]EnvVar, error) {
+	if _, ok := config.Frontend[name]; !ok {
+		return nil, fmt.Errorf("frontend service not found: %s", name)
+	}
+
+	merged := make(map[string]string)
+	for _, layer := range []map[string]string{config.Env.Global, config.Env.Frontend} {
+		for key, value := range layer {
+			merged[key] = value
+		}
+	}
+	return sortedEnv(merged), nil
+}
+
+// resolveValue resolves the expressions in a Compose env value. Service
+// references resolve to the Compose service name, which is its DNS name
+// on the project network.
+func (r *Renderer) resolveValue(config *configschema.Config, value string) (string, error) {
+	expressions, err := r.refParser.ParseAll(value)
+	if err != nil {
+		return "", err
+	}
+
+	result := value
+	for _, expr := range expressions {
+		if expr.Type != ref.TypeService {
+			continue
+		}
+		text := "${" + string(expr.Type) + ":" + expr.Resource
+		if expr.ServiceType != "" {
+			text += "@" + expr.ServiceType
+		}
+		result = strings.ReplaceAll(result, text+"}", expr.Resource)
+	}
+
+	return r.refParser.ReplaceAll(result, ref.EnvironmentCompose, config)
+}
+
+// sortedEnv converts a variable map to a list sorted by name.
+func sortedEnv(vars map[string]string) []EnvVar {
+	env := make([]EnvVar, 0, len(vars))
+	for key, value := range vars {
+		env = append(env, EnvVar{Name: key, Value: value})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+	return env
+}
@@ -0,0 +1,552 @@
+// Package manifests provides plain Kubernetes manifest rendering for egg projects.
+//
+// Overview:
+//   - Responsibility: Render Deployments, Services, ConfigMaps and Secrets from egg configuration
+//   - Key Types: Renderer, Kubernetes object models
+//   - Concurrency Model: Immutable rendering, no shared state between calls
+//   - Error Semantics: Rendering errors for unresolvable expressions
+//   - Performance Notes: In-memory YAML marshaling, deterministic output order
+//
+// Usage:
+//
+//	renderer := NewRenderer(refParser)
+//	manifests, err := renderer.Render(config, "production")
+package manifests
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+	"gopkg.in/yaml.v3"
+)
+
+// Default settings for rendered workloads.
+const (
+	backendReplicas  = 2
+	frontendReplicas = 1
+	frontendHTTPPort = 3000
+)
+
+// Renderer provides Kubernetes manifest rendering functionality.
+//
+// Parameters:
+//   - refParser: Reference expression parser
+//
+// Returns:
+//   - None (data structure)
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Stateless rendering
+type Renderer struct {
+	refParser *ref.Parser
+}
+
+// NewRenderer creates a new manifest renderer.
+//
+// Parameters:
+//   - refParser: Reference expression parser
+//
+// Returns:
+//   - *Renderer: Manifest renderer instance
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Minimal initialization overhead
+func NewRenderer(refParser *ref.Parser) *Renderer {
+	return &Renderer{
+		refParser: refParser,
+	}
+}
+
+// Render renders all project resources as a multi-document YAML stream.
+// ConfigMaps and Secrets come first so workloads referencing them can
+// start on the first apply.
+//
+// Parameters:
+//   - config: Project configuration
+//   - namespace: Namespace set on every object (empty to leave unset)
+//
+// Returns:
+//   - []byte: Multi-document YAML suitable for kubectl apply -f -
+//   - error: Rendering error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of services and environment variables
+func (r *Renderer) Render(config *configschema.Config, namespace string) ([]byte, error) {
+	var objects []any
+
+	for _, name := range sortedKeys(config.Kubernetes.Resources.ConfigMaps) {
+		objects = append(objects, ConfigMap{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Metadata:   r.metadata(config, name, namespace, "", ""),
+			Data:       config.Kubernetes.Resources.ConfigMaps[name],
+		})
+	}
+
+	for _, name := range sortedKeys(config.Kubernetes.Resources.Secrets) {
+		objects = append(objects, Secret{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata:   r.metadata(config, name, namespace, "", ""),
+			Type:       "Opaque",
+			StringData: config.Kubernetes.Resources.Secrets[name],
+		})
+	}
+
+	for _, name := range sortedKeys(config.Backend) {
+		backendObjects, err := r.renderBackend(config, name, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render backend service %s: %w", name, err)
+		}
+		objects = append(objects, backendObjects...)
+	}
+
+	for _, name := range sortedKeys(config.Frontend) {
+		frontendObjects, err := r.renderFrontend(config, name, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render frontend service %s: %w", name, err)
+		}
+		objects = append(objects, frontendObjects...)
+	}
+
+	var buf bytes.Buffer
+	for i, object := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(object); err != nil {
+			return nil, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode manifest: %w", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderBackend renders the Deployment and the clusterIP and headless
+// Services of a backend service.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Backend service name
+//   - namespace: Target namespace
+//
+// Returns:
+//   - []any: Rendered objects
+//   - error: Environment resolution error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of environment variables
+func (r *Renderer) renderBackend(config *configschema.Config, name, namespace string) ([]any, error) {
+	service := config.Backend[name]
+	ports := service.Ports
+	if ports == nil {
+		ports = &config.BackendDefaults.Ports
+	}
+
+	env, err := r.BackendEnv(config, name)
+	if err != nil {
+		return nil, err
+	}
+
+	containerPorts := []ContainerPort{
+		{Name: "http", ContainerPort: ports.HTTP, Protocol: "TCP"},
+		{Name: "health", ContainerPort: ports.Health, Protocol: "TCP"},
+		{Name: "metrics", ContainerPort: ports.Metrics, Protocol: "TCP"},
+	}
+	servicePorts := []ServicePort{
+		{Name: "http", Port: ports.HTTP, TargetPort: "http", Protocol: "TCP"},
+		{Name: "health", Port: ports.Health, TargetPort: "health", Protocol: "TCP"},
+		{Name: "metrics", Port: ports.Metrics, TargetPort: "metrics", Protocol: "TCP"},
+	}
+	probe := func(initialDelay, period int) *Probe {
+		return &Probe{
+			HTTPGet:             HTTPGetAction{Path: "/health", Port: "health"},
+			InitialDelaySeconds: initialDelay,
+			PeriodSeconds:       period,
+		}
+	}
+
+	container := Container{
+		Name:            name,
+		Image:           imageRef(config, name),
+		ImagePullPolicy: "IfNotPresent",
+		Ports:           containerPorts,
+		Env:             env,
+		LivenessProbe:   probe(30, 10),
+		ReadinessProbe:  probe(5, 5),
+		Resources: &Resources{
+			Requests: map[string]string{"cpu": "100m", "memory": "128Mi"},
+			Limits:   map[string]string{"cpu": "500m", "memory": "512Mi"},
+		},
+	}
+
+	clusterIP := service.Kubernetes.Service.ClusterIP
+	headless := service.Kubernetes.Service.Headless
+	return []any{
+		r.deployment(config, name, namespace, "backend", backendReplicas, container),
+		Service{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   r.metadata(config, ServiceName(config, name, "clusterip"), namespace, name, "backend"),
+			Spec: ServiceSpec{
+				Type:                     "ClusterIP",
+				Selector:                 selector(config, name),
+				Ports:                    servicePorts,
+				PublishNotReadyAddresses: clusterIP.PublishNotReadyAddresses,
+			},
+		},
+		Service{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   r.metadata(config, ServiceName(config, name, "headless"), namespace, name, "backend"),
+			Spec: ServiceSpec{
+				Type:                     "ClusterIP",
+				ClusterIP:                "None",
+				Selector:                 selector(config, name),
+				Ports:                    servicePorts,
+				PublishNotReadyAddresses: headless.PublishNotReadyAddresses,
+			},
+		},
+	}, nil
+}
+
+// renderFrontend renders the Deployment and Service of a frontend service.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Frontend service name
+//   - namespace: Target namespace
+//
+// Returns:
+//   - []any: Rendered objects
+//   - error: Environment resolution error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of environment variables
+func (r *Renderer) renderFrontend(config *configschema.Config, name, namespace string) ([]any, error) {
+	env, err := r.FrontendEnv(config, name)
+	if err != nil {
+		return nil, err
+	}
+
+	container := Container{
+		Name:            name,
+		Image:           imageRef(config, name),
+		ImagePullPolicy: "IfNotPresent",
+		Ports:           []ContainerPort{{Name: "http", ContainerPort: frontendHTTPPort, Protocol: "TCP"}},
+		Env:             env,
+		Resources: &Resources{
+			Requests: map[string]string{"cpu": "50m", "memory": "64Mi"},
+			Limits:   map[string]string{"cpu": "200m", "memory": "256Mi"},
+		},
+	}
+
+	return []any{
+		r.deployment(config, name, namespace, "frontend", frontendReplicas, container),
+		Service{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   r.metadata(config, name, namespace, name, "frontend"),
+			Spec: ServiceSpec{
+				Type:     "ClusterIP",
+				Selector: selector(config, name),
+				Ports:    []ServicePort{{Name: "http", Port: frontendHTTPPort, TargetPort: "http", Protocol: "TCP"}},
+			},
+		},
+	}, nil
+}
+
+// BackendEnv returns the container environment of a backend service.
+// Service identity and ports come first and cannot be overridden; the
+// remaining variables merge env.global, env.backend, the service's common
+// env and its kubernetes env, later layers overriding earlier ones.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Backend service name
+//
+// Returns:
+//   - []EnvVar: Container environment
+//   - error: Error for unknown services or unresolvable expressions
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of variables
+func (r *Renderer) BackendEnv(config *configschema.Config, name string) ([]EnvVar, error) {
+	service, ok := config.Backend[name]
+	if !ok {
+		return nil, fmt.Errorf("backend service not found: %s", name)
+	}
+	ports := service.Ports
+	if ports == nil {
+		ports = &config.BackendDefaults.Ports
+	}
+
+	// Service identity and ports are derived from egg.yaml and cannot be
+	// overridden by env settings
+	fixed := []EnvVar{
+		{Name: "SERVICE_NAME", Value: name},
+		{Name: "SERVICE_VERSION", Value: config.Version},
+		{Name: "HTTP_PORT", Value: strconv.Itoa(ports.HTTP)},
+		{Name: "HEALTH_PORT", Value: strconv.Itoa(ports.Health)},
+		{Name: "METRICS_PORT", Value: strconv.Itoa(ports.Metrics)},
+	}
+
+	// Later layers override earlier ones
+	return r.resolveEnv(config, fixed,
+		config.Env.Global,
+		config.Env.Backend,
+		service.Env.Common,
+		service.Env.Kubernetes,
+	)
+}
+
+// FrontendEnv returns the container environment of a frontend service:
+// env.global overridden by env.frontend.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Frontend service name
+//
+// Returns:
+//   - []EnvVar: Container environment
+//   - error: Error for unknown services or unresolvable expressions
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of variables
+func (r *Renderer) FrontendEnv(config *configschema.Config, name string) ([]EnvVar, error) {
+	if _, ok := config.Frontend[name]; !ok {
+		return nil, fmt.Errorf("frontend service not found: %s", name)
+	}
+	return r.resolveEnv(config, nil, config.Env.Global, config.Env.Frontend)
+}
+
+// deployment builds a single-container Deployment.
+func (r *Renderer) deployment(config *configschema.Config, name, namespace, component string, replicas int, container Container) Deployment {
+	return Deployment{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   r.metadata(config, name, namespace, name, component),
+		Spec: DeploymentSpec{
+			Replicas: replicas,
+			Selector: LabelSelector{MatchLabels: selector(config, name)},
+			Template: PodTemplate{
+				Metadata: ObjectMeta{Labels: selector(config, name)},
+				Spec:     PodSpec{Containers: []Container{container}},
+			},
+		},
+	}
+}
+
+// metadata builds object metadata with the project's standard labels.
+func (r *Renderer) metadata(config *configschema.Config, name, namespace, app, component string) ObjectMeta {
+	labels := map[string]string{
+		"app.kubernetes.io/part-of":    config.ProjectName,
+		"app.kubernetes.io/managed-by": "egg",
+	}
+	if app != "" {
+		labels["app.kubernetes.io/name"] = app
+		labels["app.kubernetes.io/version"] = config.Version
+	}
+	if component != "" {
+		labels["app.kubernetes.io/component"] = component
+	}
+	return ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+}
+
+// selector returns the pod labels selecting a service's pods.
+func selector(config *configschema.Config, name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":    name,
+		"app.kubernetes.io/part-of": config.ProjectName,
+	}
+}
+
+// imageRef returns the full image reference of a service.
+func imageRef(config *configschema.Config, name string) string {
+	image := config.GetImageName(name) + ":" + config.Version
+	if config.DockerRegistry != "" {
+		image = config.DockerRegistry + "/" + image
+	}
+	return image
+}
+
+// ServiceName returns the Kubernetes Service name of a backend service for
+// the given service type (clusterip or headless). Names configured in
+// kubernetes.service take precedence; otherwise the clusterIP Service is
+// named after the service and the headless one gets a "-headless" suffix.
+//
+// Parameters:
+//   - config: Project configuration
+//   - name: Backend service name
+//   - serviceType: Service type (clusterip or headless)
+//
+// Returns:
+//   - string: Kubernetes Service name
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(1) lookup
+func ServiceName(config *configschema.Config, name, serviceType string) string {
+	service := config.Backend[name]
+	if strings.EqualFold(serviceType, "headless") {
+		if service.Kubernetes.Service.Headless.Name != "" {
+			return service.Kubernetes.Service.Headless.Name
+		}
+		return name + "-headless"
+	}
+	if service.Kubernetes.Service.ClusterIP.Name != "" {
+		return service.Kubernetes.Service.ClusterIP.Name
+	}
+	return name
+}
+
+// resolveEnv merges environment layers into container env vars. Later
+// layers override earlier ones; fixed vars come first and cannot be
+// overridden.
+//
+// A value that is exactly a ${cfgv:name:key} or ${sec:name[:key]}
+// expression becomes a configMapKeyRef or secretKeyRef; a secret without
+// a key uses the variable name as the key. ${cfg:name} expands to the
+// ConfigMap name and ${svc:name[@type]} to the Service name, anywhere in
+// a value.
+//
+// Parameters:
+//   - config: Project configuration
+//   - fixed: Variables that take precedence over all layers
+//   - layers: Environment maps, lowest precedence first
+//
+// Returns:
+//   - []EnvVar: Container environment, fixed vars then sorted by name
+//   - error: Expression resolution error if any
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of variables
+func (r *Renderer) resolveEnv(config *configschema.Config, fixed []EnvVar, layers ...map[string]string) ([]EnvVar, error) {
+	reserved := make(map[string]bool, len(fixed))
+	for _, v := range fixed {
+		reserved[v.Name] = true
+	}
+
+	merged := make(map[string]string)
+	for _, layer := range layers {
+		for key, value := range layer {
+			if !reserved[key] {
+				merged[key] = value
+			}
+		}
+	}
+
+	env := append([]EnvVar(nil), fixed...)
+	for _, key := range sortedKeys(merged) {
+		v, err := r.resolveValue(config, key, merged[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", key, err)
+		}
+		env = append(env, v)
+	}
+	return env, nil
+}
+
+// resolveValue resolves the expressions in a single env value.
+func (r *Renderer) resolveValue(config *configschema.Config, name, value string) (EnvVar, error) {
+	expressions, err := r.refParser.ParseAll(value)
+	if err != nil {
+		return EnvVar{}, err
+	}
+
+	result := value
+	for _, expr := range expressions {
+		resolved, err := r.refParser.Resolve(expr, ref.EnvironmentKubernetes, config)
+		if err != nil {
+			return EnvVar{}, err
+		}
+
+		text := expressionString(expr)
+		switch expr.Type {
+		case ref.TypeConfigMapValue, ref.TypeSecret:
+			if strings.TrimSpace(value) != text {
+				return EnvVar{}, fmt.Errorf("%s must be the entire value to be injected from a %s", text, kindOf(expr))
+			}
+			key := expr.Key
+			if key == "" {
+				key = name
+			}
+			selector := &KeySelector{Name: resolved.Value, Key: key}
+			if resolved.IsSecret {
+				return EnvVar{Name: name, ValueFrom: &EnvVarSource{SecretKeyRef: selector}}, nil
+			}
+			return EnvVar{Name: name, ValueFrom: &EnvVarSource{ConfigMapKeyRef: selector}}, nil
+		case ref.TypeService:
+			result = strings.ReplaceAll(result, text, ServiceName(config, resolved.ServiceName, resolved.ServiceType))
+		default:
+			result = strings.ReplaceAll(result, text, resolved.Value)
+		}
+	}
+	return EnvVar{Name: name, Value: result}, nil
+}
+
+// expressionString formats an expression in its ${...} source form.
+func expressionString(expr *ref.Expression) string {
+	text := "${" + string(expr.Type) + ":" + expr.Resource
+	if expr.Key != "" {
+		text += ":" + expr.Key
+	}
+	if expr.ServiceType != "" {
+		text += "@" + expr.ServiceType
+	}
+	return text + "}"
+}
+
+// kindOf names the resource kind an injected expression reads from.
+func kindOf(expr *ref.Expression) string {
+	if expr.Type == ref.TypeSecret {
+		return "Secret"
+	}
+	return "ConfigMap"
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
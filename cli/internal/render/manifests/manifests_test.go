@@ -0,0 +1,188 @@
+package manifests
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/cli/internal/configschema"
+	"go.eggybyte.com/egg/cli/internal/ref"
+	"gopkg.in/yaml.v3"
+)
+
+func testConfig() *configschema.Config {
+	return &configschema.Config{
+		ProjectName:    "shop",
+		Version:        "1.2.0",
+		DockerRegistry: "ghcr.io/acme",
+		Env: configschema.EnvConfig{
+			Global:  map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+			Backend: map[string]string{"LOG_LEVEL": "debug"},
+		},
+		BackendDefaults: configschema.BackendDefaultsConfig{
+			Ports: configschema.PortConfig{HTTP: 8080, Health: 8081, Metrics: 9091},
+		},
+		Kubernetes: configschema.KubernetesConfig{
+			Resources: configschema.KubernetesResourcesConfig{
+				ConfigMaps: map[string]map[string]string{"app-config": {"FEATURE": "on"}},
+				Secrets:    map[string]map[string]string{"db-secret": {"DB_DSN": "user:pass@tcp(mysql:3306)/app"}},
+			},
+		},
+		Backend: map[string]configschema.BackendService{
+			"user": {
+				Kubernetes: configschema.BackendKubernetesConfig{
+					Service: configschema.BackendServiceConfig{
+						ClusterIP: configschema.ServiceEndpointConfig{Name: "user-api"},
+						Headless:  configschema.ServiceEndpointConfig{Name: "user-peers", PublishNotReadyAddresses: true},
+					},
+				},
+				Env: configschema.BackendEnvConfig{
+					Common: map[string]string{"REGION": "us", "SERVICE_NAME": "ignored"},
+					Docker: map[string]string{"DB_DSN": "root@tcp(localhost)/app"},
+					Kubernetes: map[string]string{
+						"DB_DSN":      "${sec:db-secret:DB_DSN}",
+						"FEATURE":     "${cfgv:app-config:FEATURE}",
+						"CONFIG_NAME": "${cfg:app-config}",
+						"PEERS":       "${svc:user@headless}:8080",
+						"GREET_URL":   "http://${svc:greet}:8080",
+					},
+				},
+			},
+			"greet": {},
+		},
+	}
+}
+
+// decode splits a multi-document stream into generic objects keyed by
+// kind/name.
+func decode(t *testing.T, data []byte) map[string]map[string]any {
+	t.Helper()
+	objects := map[string]map[string]any{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Invalid YAML: %v\n%s", err, data)
+		}
+		metadata := object["metadata"].(map[string]any)
+		objects[object["kind"].(string)+"/"+metadata["name"].(string)] = object
+	}
+	return objects
+}
+
+// envOf returns the env vars of a Deployment's container by name.
+func envOf(t *testing.T, deployment map[string]any) map[string]map[string]any {
+	t.Helper()
+	spec := deployment["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)
+	container := spec["containers"].([]any)[0].(map[string]any)
+	env := map[string]map[string]any{}
+	for _, v := range container["env"].([]any) {
+		e := v.(map[string]any)
+		env[e["name"].(string)] = e
+	}
+	return env
+}
+
+func TestRender_Objects(t *testing.T) {
+	data, err := NewRenderer(ref.NewParser()).Render(testConfig(), "production")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	objects := decode(t, data)
+
+	for _, key := range []string{
+		"ConfigMap/app-config", "Secret/db-secret",
+		"Deployment/user", "Service/user-api", "Service/user-peers",
+		"Deployment/greet", "Service/greet", "Service/greet-headless",
+	} {
+		object, ok := objects[key]
+		if !ok {
+			t.Errorf("Missing %s", key)
+			continue
+		}
+		if ns := object["metadata"].(map[string]any)["namespace"]; ns != "production" {
+			t.Errorf("%s namespace = %v, want production", key, ns)
+		}
+	}
+
+	if !strings.HasPrefix(string(data), "apiVersion: v1\nkind: ConfigMap") {
+		t.Error("Expected ConfigMaps to be rendered first")
+	}
+
+	secret := objects["Secret/db-secret"]
+	if got := secret["stringData"].(map[string]any)["DB_DSN"]; got != "user:pass@tcp(mysql:3306)/app" {
+		t.Errorf("Secret stringData DB_DSN = %v", got)
+	}
+
+	headless := objects["Service/user-peers"]["spec"].(map[string]any)
+	if headless["clusterIP"] != "None" || headless["publishNotReadyAddresses"] != true {
+		t.Errorf("Headless service spec = %v", headless)
+	}
+	clusterIP := objects["Service/user-api"]["spec"].(map[string]any)
+	if _, ok := clusterIP["clusterIP"]; ok {
+		t.Errorf("ClusterIP service should not set clusterIP: %v", clusterIP)
+	}
+	if ports := clusterIP["ports"].([]any); len(ports) != 3 {
+		t.Errorf("Expected http, health and metrics ports, got %v", ports)
+	}
+
+	container := objects["Deployment/user"]["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	if container["image"] != "ghcr.io/acme/shop-user:1.2.0" {
+		t.Errorf("Image = %v", container["image"])
+	}
+}
+
+func TestRender_Env(t *testing.T) {
+	data, err := NewRenderer(ref.NewParser()).Render(testConfig(), "")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	objects := decode(t, data)
+	env := envOf(t, objects["Deployment/user"])
+
+	values := map[string]string{
+		"SERVICE_NAME": "user",
+		"HTTP_PORT":    "8080",
+		"LOG_LEVEL":    "debug", // env.backend overrides env.global
+		"REGION":       "us",    // env.common overrides env.global
+		"CONFIG_NAME":  "app-config",
+		"PEERS":        "user-peers:8080",
+		"GREET_URL":    "http://greet:8080",
+	}
+	for name, want := range values {
+		if got := env[name]["value"]; got != want {
+			t.Errorf("%s = %v, want %s", name, got, want)
+		}
+	}
+
+	secretRef := env["DB_DSN"]["valueFrom"].(map[string]any)["secretKeyRef"].(map[string]any)
+	if secretRef["name"] != "db-secret" || secretRef["key"] != "DB_DSN" {
+		t.Errorf("DB_DSN secretKeyRef = %v", secretRef)
+	}
+	configRef := env["FEATURE"]["valueFrom"].(map[string]any)["configMapKeyRef"].(map[string]any)
+	if configRef["name"] != "app-config" || configRef["key"] != "FEATURE" {
+		t.Errorf("FEATURE configMapKeyRef = %v", configRef)
+	}
+
+	if _, ok := objects["Deployment/user"]["metadata"].(map[string]any)["namespace"]; ok {
+		t.Error("Namespace should be omitted when empty")
+	}
+}
+
+func TestRender_EmbeddedSecretRejected(t *testing.T) {
+	config := testConfig()
+	config.Backend["greet"] = configschema.BackendService{
+		Env: configschema.BackendEnvConfig{
+			Kubernetes: map[string]string{"DSN": "mysql://${sec:db-secret:DB_DSN}"},
+		},
+	}
+
+	_, err := NewRenderer(ref.NewParser()).Render(config, "")
+	if err == nil || !strings.Contains(err.Error(), "must be the entire value") {
+		t.Errorf("Expected embedded secret error, got %v", err)
+	}
+}
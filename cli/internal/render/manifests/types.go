@@ -0,0 +1,145 @@
+package manifests
+
+// The types below model the subset of the Kubernetes API the renderer
+// emits. Field names and tags follow the upstream API so the output can
+// be applied as-is.
+
+// ObjectMeta is the metadata shared by all rendered objects.
+type ObjectMeta struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// ConfigMap is a v1 ConfigMap.
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+// Secret is a v1 Secret. Values are written as stringData so egg.yaml
+// values need no base64 encoding.
+type Secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData,omitempty"`
+}
+
+// Deployment is an apps/v1 Deployment.
+type Deployment struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   ObjectMeta     `yaml:"metadata"`
+	Spec       DeploymentSpec `yaml:"spec"`
+}
+
+// DeploymentSpec is the spec of a Deployment.
+type DeploymentSpec struct {
+	Replicas int           `yaml:"replicas"`
+	Selector LabelSelector `yaml:"selector"`
+	Template PodTemplate   `yaml:"template"`
+}
+
+// LabelSelector selects pods by label.
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// PodTemplate is the pod template of a Deployment.
+type PodTemplate struct {
+	Metadata ObjectMeta `yaml:"metadata"`
+	Spec     PodSpec    `yaml:"spec"`
+}
+
+// PodSpec is the spec of a pod.
+type PodSpec struct {
+	Containers []Container `yaml:"containers"`
+}
+
+// Container is a container of a pod.
+type Container struct {
+	Name            string          `yaml:"name"`
+	Image           string          `yaml:"image"`
+	ImagePullPolicy string          `yaml:"imagePullPolicy,omitempty"`
+	Ports           []ContainerPort `yaml:"ports,omitempty"`
+	Env             []EnvVar        `yaml:"env,omitempty"`
+	LivenessProbe   *Probe          `yaml:"livenessProbe,omitempty"`
+	ReadinessProbe  *Probe          `yaml:"readinessProbe,omitempty"`
+	Resources       *Resources      `yaml:"resources,omitempty"`
+}
+
+// ContainerPort is a port exposed by a container.
+type ContainerPort struct {
+	Name          string `yaml:"name"`
+	ContainerPort int    `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol"`
+}
+
+// EnvVar is a container environment variable, either a literal value or
+// a reference to a ConfigMap or Secret key.
+type EnvVar struct {
+	Name      string        `yaml:"name"`
+	Value     string        `yaml:"value,omitempty"`
+	ValueFrom *EnvVarSource `yaml:"valueFrom,omitempty"`
+}
+
+// EnvVarSource is the source of a referenced environment variable.
+type EnvVarSource struct {
+	ConfigMapKeyRef *KeySelector `yaml:"configMapKeyRef,omitempty"`
+	SecretKeyRef    *KeySelector `yaml:"secretKeyRef,omitempty"`
+}
+
+// KeySelector selects a key of a ConfigMap or Secret.
+type KeySelector struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+}
+
+// Probe is an HTTP liveness or readiness probe.
+type Probe struct {
+	HTTPGet             HTTPGetAction `yaml:"httpGet"`
+	InitialDelaySeconds int           `yaml:"initialDelaySeconds"`
+	PeriodSeconds       int           `yaml:"periodSeconds"`
+}
+
+// HTTPGetAction is an HTTP GET probe against a named port.
+type HTTPGetAction struct {
+	Path string `yaml:"path"`
+	Port string `yaml:"port"`
+}
+
+// Resources are the compute resource requests and limits of a container.
+type Resources struct {
+	Requests map[string]string `yaml:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty"`
+}
+
+// Service is a v1 Service.
+type Service struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   ObjectMeta  `yaml:"metadata"`
+	Spec       ServiceSpec `yaml:"spec"`
+}
+
+// ServiceSpec is the spec of a Service. ClusterIP is "None" for headless
+// Services.
+type ServiceSpec struct {
+	Type                     string            `yaml:"type"`
+	ClusterIP                string            `yaml:"clusterIP,omitempty"`
+	Selector                 map[string]string `yaml:"selector"`
+	Ports                    []ServicePort     `yaml:"ports"`
+	PublishNotReadyAddresses bool              `yaml:"publishNotReadyAddresses,omitempty"`
+}
+
+// ServicePort is a port exposed by a Service.
+type ServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort string `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
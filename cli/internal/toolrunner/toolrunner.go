@@ -14,8 +14,10 @@
 package toolrunner
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -126,11 +128,34 @@ func (r *Runner) SetVerbose(enabled bool) {
 // Performance:
 //   - Streaming output capture
 func (r *Runner) execute(ctx context.Context, name string, args ...string) (*CommandResult, error) {
+	return r.executeWithInput(ctx, nil, name, args...)
+}
+
+// executeWithInput runs a command with the given standard input and
+// returns the result.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - input: Standard input (nil for none)
+//   - name: Command name
+//   - args: Command arguments
+//
+// Returns:
+//   - *CommandResult: Command execution result
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded per command
+//
+// Performance:
+//   - Streaming output capture
+func (r *Runner) executeWithInput(ctx context.Context, input io.Reader, name string, args ...string) (*CommandResult, error) {
 	start := time.Now()
 
 	// Create command
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = r.workDir
+	cmd.Stdin = input
 
 	// Special handling for 'go work init' to avoid parent workspace conflicts
 	if name == "go" && len(args) >= 2 && args[0] == "work" && args[1] == "init" {
@@ -974,6 +999,44 @@ func (r *Runner) KubectlApply(ctx context.Context, manifest, namespace string) e
 	return nil
 }
 
+// KubectlApplyManifests applies Kubernetes manifests passed on standard input.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - manifests: Multi-document YAML manifests
+//   - namespace: Target namespace (empty for the manifests' own)
+//   - dryRun: Validate on the client without changing the cluster
+//
+// Returns:
+//   - error: Execution error if any
+//
+// Concurrency:
+//   - Single-threaded per command
+//
+// Performance:
+//   - Apply time depends on resource complexity
+func (r *Runner) KubectlApplyManifests(ctx context.Context, manifests []byte, namespace string, dryRun bool) error {
+	args := []string{"apply", "-f", "-"}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	if dryRun {
+		args = append(args, "--dry-run=client")
+	}
+
+	result, err := r.executeWithInput(ctx, bytes.NewReader(manifests), "kubectl", args...)
+	if err != nil {
+		return fmt.Errorf("failed to apply Kubernetes manifests: %w", err)
+	}
+
+	if r.verbose {
+		ui.Debug("Kubernetes manifests applied")
+		ui.Debug("Output: %s", result.Stdout)
+	}
+
+	return nil
+}
+
 // CheckToolAvailability checks if a tool is available in PATH.
 //
 // Parameters:
@@ -0,0 +1,320 @@
+package version
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModulePrefix is the import path prefix of the egg framework modules.
+const ModulePrefix = "go.eggybyte.com/egg/"
+
+// FrameworkModules are the framework modules released together under the
+// same version tag. Projects should pin them to a single version.
+var FrameworkModules = []string{
+	"core", "logx", "configx", "obsx", "httpx",
+	"runtimex", "connectx", "clientx", "storex", "k8sx", "testingx",
+	"servicex",
+}
+
+// Requirement is an egg module required by a project module.
+type Requirement struct {
+	Module   string `json:"module"`            // Module name without ModulePrefix (e.g., "servicex")
+	Version  string `json:"version"`           // Required version
+	Indirect bool   `json:"indirect"`          // Marked // indirect
+	Replace  string `json:"replace,omitempty"` // Replacement path or module@version
+}
+
+// ProjectModule is a Go module of the project and the egg modules it requires.
+type ProjectModule struct {
+	Path     string        `json:"path"` // Module path from the module directive
+	Dir      string        `json:"dir"`  // Directory of go.mod, relative to the project root
+	Requires []Requirement `json:"requires"`
+}
+
+// Mismatch is a framework module pinned to a version other than the
+// version most framework requirements use.
+type Mismatch struct {
+	Module   string   `json:"module"`
+	Version  string   `json:"version"`
+	Expected string   `json:"expected"`
+	Users    []string `json:"users"` // Project module directories pinning Version
+}
+
+// BuildInfo is the build information embedded in the CLI binary.
+type BuildInfo struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified"`
+}
+
+// ReadBuildInfo returns the build information of the running binary.
+//
+// Returns:
+//   - BuildInfo: Main module, Go toolchain, and VCS settings
+//   - bool: False if the binary carries no build information
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n) in the number of build settings
+func ReadBuildInfo() (BuildInfo, bool) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildInfo{}, false
+	}
+
+	build := BuildInfo{
+		Path:      info.Main.Path,
+		Version:   info.Main.Version,
+		GoVersion: info.GoVersion,
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.Time = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build, true
+}
+
+// ScanProject finds the Go modules of a project and their egg requirements.
+// Modules come from the first go.work found in root or root/backend;
+// without a workspace, root/go.mod is used (standalone services).
+//
+// Parameters:
+//   - root: Project root directory
+//
+// Returns:
+//   - []ProjectModule: Project modules sorted by directory
+//   - error: Error if no module is found or a file cannot be parsed
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Reads go.work and each go.mod once
+func ScanProject(root string) ([]ProjectModule, error) {
+	dirs, err := moduleDirs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]ProjectModule, 0, len(dirs))
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(root, dir, "go.mod"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue // Workspace entry not created yet
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Join(dir, "go.mod"), err)
+		}
+
+		module, err := ParseGoMod(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "go.mod"), err)
+		}
+		module.Dir = dir
+		modules = append(modules, module)
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no Go modules found (expected go.work, backend/go.work, or go.mod)")
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Dir < modules[j].Dir })
+	return modules, nil
+}
+
+// moduleDirs returns the module directories of a project relative to root.
+func moduleDirs(root string) ([]string, error) {
+	for _, workDir := range []string{".", "backend"} {
+		data, err := os.ReadFile(filepath.Join(root, workDir, "go.work"))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go.work: %w", err)
+		}
+
+		var dirs []string
+		for _, use := range directiveArgs(string(data), "use") {
+			dirs = append(dirs, filepath.Clean(filepath.Join(workDir, use)))
+		}
+		return dirs, nil
+	}
+	return []string{"."}, nil
+}
+
+// ParseGoMod parses the module path and egg requirements of a go.mod file.
+// Replace directives are attached to the requirement they replace.
+//
+// Parameters:
+//   - content: go.mod file content
+//
+// Returns:
+//   - ProjectModule: Module path and egg requirements sorted by module (Dir unset)
+//   - error: Error if the module directive is missing or a directive is malformed
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - Single pass over each directive
+func ParseGoMod(content string) (ProjectModule, error) {
+	var module ProjectModule
+	if paths := directiveArgs(content, "module"); len(paths) > 0 {
+		module.Path = unquote(paths[0])
+	}
+	if module.Path == "" {
+		return module, fmt.Errorf("missing module directive")
+	}
+
+	replaces := make(map[string]string)
+	for _, line := range directiveArgs(content, "replace") {
+		from, to, ok := strings.Cut(line, "=>")
+		if !ok {
+			return module, fmt.Errorf("malformed replace: %s", line)
+		}
+		fromFields := strings.Fields(from)
+		toFields := strings.Fields(to)
+		if len(fromFields) == 0 || len(toFields) == 0 {
+			return module, fmt.Errorf("malformed replace: %s", line)
+		}
+		replaces[unquote(fromFields[0])] = strings.Join(toFields, "@")
+	}
+
+	for _, line := range directiveArgs(content, "require") {
+		indirect := false
+		if code, comment, ok := strings.Cut(line, "//"); ok {
+			line = code
+			indirect = strings.TrimSpace(comment) == "indirect"
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return module, fmt.Errorf("malformed require: %s", line)
+		}
+		path := unquote(fields[0])
+		if !strings.HasPrefix(path, ModulePrefix) {
+			continue
+		}
+		module.Requires = append(module.Requires, Requirement{
+			Module:   strings.TrimPrefix(path, ModulePrefix),
+			Version:  fields[1],
+			Indirect: indirect,
+			Replace:  replaces[path],
+		})
+	}
+
+	sort.Slice(module.Requires, func(i, j int) bool { return module.Requires[i].Module < module.Requires[j].Module })
+	return module, nil
+}
+
+// directiveArgs returns the arguments of a go.mod or go.work directive in
+// both the single-line and block forms. Comments are stripped, except a
+// trailing "// indirect", which require lines need.
+func directiveArgs(content, directive string) []string {
+	var args []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if code, comment, ok := strings.Cut(line, "//"); ok && strings.TrimSpace(comment) != "indirect" {
+			line = strings.TrimSpace(code)
+		}
+		switch {
+		case line == "":
+			continue
+		case inBlock:
+			if line == ")" {
+				inBlock = false
+			} else {
+				args = append(args, line)
+			}
+		case line == directive+" (":
+			inBlock = true
+		case strings.HasPrefix(line, directive+" "):
+			args = append(args, strings.TrimSpace(strings.TrimPrefix(line, directive+" ")))
+		}
+	}
+	return args
+}
+
+// unquote removes the quotes of a quoted go.mod path.
+func unquote(s string) string {
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+	return s
+}
+
+// FindMismatches reports framework modules pinned to a version other than
+// the one most framework requirements across the project use. Replaced
+// requirements are skipped since their version is not what gets built.
+// Ties are broken toward the lexically greater version.
+//
+// Parameters:
+//   - modules: Project modules from ScanProject
+//
+// Returns:
+//   - string: Expected framework version (empty if no framework modules are pinned)
+//   - []Mismatch: Mismatched requirements sorted by module and version
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of requirements
+func FindMismatches(modules []ProjectModule) (string, []Mismatch) {
+	framework := make(map[string]bool, len(FrameworkModules))
+	for _, name := range FrameworkModules {
+		framework[name] = true
+	}
+
+	counts := make(map[string]int)
+	users := make(map[[2]string][]string) // (module, version) -> project module dirs
+	for _, module := range modules {
+		for _, req := range module.Requires {
+			if !framework[req.Module] || req.Replace != "" {
+				continue
+			}
+			counts[req.Version]++
+			key := [2]string{req.Module, req.Version}
+			users[key] = append(users[key], module.Dir)
+		}
+	}
+
+	expected := ""
+	for version, count := range counts {
+		if count > counts[expected] || (count == counts[expected] && version > expected) {
+			expected = version
+		}
+	}
+
+	var mismatches []Mismatch
+	for key, dirs := range users {
+		if key[1] == expected {
+			continue
+		}
+		sort.Strings(dirs)
+		mismatches = append(mismatches, Mismatch{Module: key[0], Version: key[1], Expected: expected, Users: dirs})
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Module != mismatches[j].Module {
+			return mismatches[i].Module < mismatches[j].Module
+		}
+		return mismatches[i].Version < mismatches[j].Version
+	})
+	return expected, mismatches
+}
@@ -0,0 +1,86 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const userGoMod = `module github.com/acme/shop/backend/user // user service
+
+go 1.25.1
+
+require (
+	github.com/spf13/cobra v1.10.1
+	go.eggybyte.com/egg/core v0.3.2
+	go.eggybyte.com/egg/servicex v0.3.2
+)
+
+require go.eggybyte.com/egg/obsx v0.3.1 // indirect
+
+replace go.eggybyte.com/egg/core => ../../../core
+`
+
+func TestParseGoMod(t *testing.T) {
+	module, err := ParseGoMod(userGoMod)
+	if err != nil {
+		t.Fatalf("ParseGoMod() error = %v", err)
+	}
+
+	if module.Path != "github.com/acme/shop/backend/user" {
+		t.Errorf("Path = %q", module.Path)
+	}
+	want := []Requirement{
+		{Module: "core", Version: "v0.3.2", Replace: "../../../core"},
+		{Module: "obsx", Version: "v0.3.1", Indirect: true},
+		{Module: "servicex", Version: "v0.3.2"},
+	}
+	if !reflect.DeepEqual(module.Requires, want) {
+		t.Errorf("Requires = %+v, want %+v", module.Requires, want)
+	}
+
+	if _, err := ParseGoMod("go 1.25.1\n"); err == nil {
+		t.Error("Expected error for go.mod without module directive")
+	}
+}
+
+func TestScanProjectAndFindMismatches(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"backend/go.work":     "go 1.25.1\n\nuse (\n\t./user\n\t./order\n\t./pending // not created yet\n)\n",
+		"backend/user/go.mod": userGoMod,
+		"backend/order/go.mod": "module github.com/acme/shop/backend/order\n\n" +
+			"require (\n\tgo.eggybyte.com/egg/connectx v0.3.2\n\tgo.eggybyte.com/egg/obsx v0.3.2 // indirect\n)\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modules, err := ScanProject(root)
+	if err != nil {
+		t.Fatalf("ScanProject() error = %v", err)
+	}
+	if len(modules) != 2 || modules[0].Dir != filepath.Join("backend", "order") || modules[1].Dir != filepath.Join("backend", "user") {
+		t.Fatalf("Modules = %+v", modules)
+	}
+
+	expected, mismatches := FindMismatches(modules)
+	if expected != "v0.3.2" {
+		t.Errorf("Expected version = %q, want v0.3.2", expected)
+	}
+	want := []Mismatch{{Module: "obsx", Version: "v0.3.1", Expected: "v0.3.2", Users: []string{filepath.Join("backend", "user")}}}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("Mismatches = %+v, want %+v", mismatches, want)
+	}
+
+	if _, err := ScanProject(t.TempDir()); err == nil {
+		t.Error("Expected error for directory without modules")
+	}
+}
@@ -17,36 +17,126 @@ package clientx
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"connectrpc.com/connect"
 	"github.com/sony/gobreaker"
 	"go.eggybyte.com/egg/clientx/internal"
+	"go.eggybyte.com/egg/core/clock"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // Options configures the HTTP client behavior.
 type Options struct {
 	Timeout            time.Duration // Request timeout (default: 30s)
+	PerAttemptTimeout  time.Duration // Timeout of each retry attempt (default: none)
+	Deadline           time.Duration // Total budget for all attempts and backoff (default: none)
 	MaxRetries         int           // Maximum retry attempts (default: 3)
 	RetryBackoff       time.Duration // Initial backoff duration (default: 100ms)
 	EnableCircuit      bool          // Enable circuit breaker (default: true)
 	CircuitThreshold   uint32        // Circuit breaker failure threshold (default: 5)
 	IdempotencyKey     string        // Custom idempotency key header name
+	IdempotentMethods  []string      // Connect procedures safe to retry (default: none)
+	RetryPolicy        RetryPolicy   // Decides which failures are retried (default: DefaultRetryPolicy)
 	InternalToken      string        // Internal service token
-	InternalTokenHeader string       // Header name for internal token
+	InternalTokenHeader string       // Header name for internal token (default: X-Internal-Token)
+	TokenProvider      TokenProvider // Per-request token source; overrides InternalToken
+	PropagateDeadline  bool          // Send the remaining request deadline as X-RPC-Timeout-Ms (default: true)
+	PropagateHeaders   bool          // Send the headers stored by connectx Options.PropagateHeaders (default: true)
+
+	MaxIdleConns         int           // Idle connections across all hosts (default: 100)
+	MaxIdleConnsPerHost  int           // Idle connections per host (default: 100)
+	MaxConnsPerHost      int           // Connections per host (default: 0, unlimited)
+	IdleConnTimeout      time.Duration // Idle time before a connection is closed (default: 90s)
+	MaxStreamsPerConn    int           // In-flight requests per connection before another is opened (default: 0, unlimited)
+	H2C                  bool          // Use HTTP/2 without TLS for http:// URLs (default: false)
+	HTTP2ReadIdleTimeout time.Duration // Idle time before an HTTP/2 health ping (default: 30s)
+	HTTP2PingTimeout     time.Duration // Time to wait for a ping response (default: 15s)
+
+	Hedging       HedgeOptions         // Backup requests for slow idempotent calls (default: disabled)
+	Streaming     StreamOptions        // Stream timeouts; Timeout then applies to unary calls only (default: disabled)
+	MeterProvider metric.MeterProvider // Records outbound request metrics (default: none)
+	Clock         clock.Clock          // Clock for retry backoff and deadlines (default: system clock)
+
+	Interceptors []connect.Interceptor // Connect client interceptors, first is outermost (default: none)
+}
+
+// HedgeOptions configures request hedging.
+type HedgeOptions struct {
+	Delay         time.Duration        // Wait before each backup request, e.g. the call's p95 latency
+	MaxHedges     int                  // Maximum backup requests per attempt (default: 1)
+	MeterProvider metric.MeterProvider // Records hedge fires and wins (default: the WithMetrics provider)
+}
+
+// StreamOptions configures streaming calls (Connect client, server and bidi
+// streams, and gRPC calls).
+type StreamOptions struct {
+	EstablishTimeout time.Duration // Time for each attempt to receive the response headers (default: 10s)
+	Timeout          time.Duration // Total lifetime of a stream (default: none, bounded by the call context)
 }
 
 // Option is a functional option for configuring the client.
 type Option func(*Options)
 
 // WithTimeout sets the client timeout.
+// It bounds the whole call, including all retries and reading the response
+// body; see WithPerAttemptTimeout and WithDeadline for finer control.
 func WithTimeout(d time.Duration) Option {
 	return func(o *Options) {
 		o.Timeout = d
 	}
 }
 
+// WithPerAttemptTimeout bounds each attempt, including reading its response
+// body, so a hung attempt is abandoned and retried instead of consuming the
+// whole request timeout. The per-attempt timeout is capped by the time left
+// under WithDeadline.
+func WithPerAttemptTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.PerAttemptTimeout = d
+	}
+}
+
+// WithDeadline sets the overall time budget for a request across all attempts
+// and backoff. Time spent is subtracted before every attempt, and no retry is
+// started once the remaining budget cannot cover the next backoff.
+//
+// Precedence, from innermost to outermost: WithPerAttemptTimeout bounds one
+// attempt, WithDeadline bounds the retry loop, and WithTimeout
+// (http.Client.Timeout) bounds the whole call including body reads. A
+// deadline on the request context applies on top of all of them.
+func WithDeadline(d time.Duration) Option {
+	return func(o *Options) {
+		o.Deadline = d
+	}
+}
+
+// WithDeadlinePropagation enables or disables deadline propagation. When
+// enabled, the time left until the request context deadline, or until the
+// inbound RPC deadline recorded by the connectx timeout interceptor if that
+// is earlier, is sent as the X-RPC-Timeout-Ms header so the downstream
+// service never works longer than the caller waits. A call whose deadline
+// has already passed fails with context.DeadlineExceeded without being sent.
+func WithDeadlinePropagation(enabled bool) Option {
+	return func(o *Options) {
+		o.PropagateDeadline = enabled
+	}
+}
+
+// WithHeaderPropagation enables or disables header propagation. When
+// enabled, the inbound headers the connectx identity interceptor stored in
+// the request context for Options.PropagateHeaders are sent on outbound
+// requests, so values such as a tenant ID or locale reach downstream
+// services. Only those allowlisted headers are sent, and headers set on the
+// request explicitly take precedence.
+func WithHeaderPropagation(enabled bool) Option {
+	return func(o *Options) {
+		o.PropagateHeaders = enabled
+	}
+}
+
 // WithRetry sets the maximum retry attempts.
 func WithRetry(maxRetries int) Option {
 	return func(o *Options) {
@@ -68,8 +158,59 @@ func WithIdempotencyKey(key string) Option {
 	}
 }
 
+// WithIdempotentMethods marks Connect procedures ("/pkg.Service/Method") as
+// safe to retry under the default retry policy. Connect unary calls are POST
+// requests, which are otherwise only retried when they carry the idempotency
+// key header.
+func WithIdempotentMethods(procedures ...string) Option {
+	return func(o *Options) {
+		o.IdempotentMethods = append(o.IdempotentMethods, procedures...)
+	}
+}
+
+// RetryPolicy decides whether a failed attempt is retried.
+//
+// err is the transport error, or for an error response (status >= 400) a
+// *connect.Error whose code comes from the Connect error body or, failing
+// that, the HTTP status, so connect.CodeOf(err) works for both. attempt is
+// the number of attempts made so far. WithRetry and WithDeadline still bound
+// the number of retries.
+type RetryPolicy func(req *http.Request, err error, attempt int) bool
+
+// DefaultRetryPolicy returns the policy used unless WithRetryPolicy is given.
+//
+// It retries only idempotent requests: GET, HEAD, OPTIONS, PUT, DELETE and
+// TRACE requests, requests carrying idempotencyHeader, and calls to one of
+// procedures. Among those it retries connection refused and reset errors,
+// timeouts, CodeUnavailable, CodeDeadlineExceeded and 5xx responses.
+//
+// Parameters:
+//   - idempotencyHeader: header marking a request as idempotent (empty to ignore)
+//   - procedures: Connect procedures safe to retry
+//
+// Returns:
+//   - RetryPolicy: the default policy
+func DefaultRetryPolicy(idempotencyHeader string, procedures []string) RetryPolicy {
+	return RetryPolicy(internal.NewDefaultRetryPolicy(idempotencyHeader, procedures))
+}
+
+// WithRetryPolicy replaces the default retry policy.
+//
+// Example:
+//
+//	defaultPolicy := clientx.DefaultRetryPolicy("X-Idempotency-Key", nil)
+//	clientx.WithRetryPolicy(func(req *http.Request, err error, attempt int) bool {
+//	  return connect.CodeOf(err) == connect.CodeResourceExhausted || defaultPolicy(req, err, attempt)
+//	})
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *Options) {
+		o.RetryPolicy = policy
+	}
+}
+
 // WithInternalToken sets the internal token for service-to-service authentication.
-// The token is automatically added to all outgoing requests.
+// The token is automatically added to all outgoing requests. It is a static
+// TokenProvider; WithTokenProvider takes precedence.
 func WithInternalToken(token string) Option {
 	return func(o *Options) {
 		o.InternalToken = token
@@ -79,6 +220,57 @@ func WithInternalToken(token string) Option {
 	}
 }
 
+// TokenProvider returns the token sent with a request in the internal token
+// header. It is called for every request, so it should cache tokens; see
+// CachedTokenProvider.
+//
+// When the server rejects a token with HTTP 401 (Connect CodeUnauthenticated),
+// the provider is called again with a context for which IsTokenRefresh
+// reports true. If it then returns a different token, the request is retried
+// once with it.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// WithTokenProvider sets a provider of short-lived tokens, consulted for
+// every request. Provider errors fail the request without sending it.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(o *Options) {
+		o.TokenProvider = provider
+	}
+}
+
+// DefaultTokenRefreshSkew is how long before expiry CachedTokenProvider
+// refreshes a token, so it does not expire in flight.
+const DefaultTokenRefreshSkew = 30 * time.Second
+
+// CachedTokenProvider returns a TokenProvider caching the tokens returned by
+// fetch until DefaultTokenRefreshSkew before their expiry. A zero expiresAt
+// means the token does not expire. A token rejected by the server is fetched
+// anew, once, however many requests were rejected with it.
+//
+// Concurrency:
+//   - Safe for concurrent use; concurrent callers share a single fetch
+//
+// Example:
+//
+//	provider := clientx.CachedTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+//	  tok, err := sts.Issue(ctx, "user-service")
+//	  if err != nil {
+//	    return "", time.Time{}, err
+//	  }
+//	  return tok.Value, tok.ExpiresAt, nil
+//	})
+//	client := clientx.NewHTTPClient("http://user-service:8080", clientx.WithTokenProvider(provider))
+func CachedTokenProvider(fetch func(ctx context.Context) (token string, expiresAt time.Time, err error)) TokenProvider {
+	return internal.NewTokenCache(fetch, DefaultTokenRefreshSkew).Token
+}
+
+// IsTokenRefresh reports whether a TokenProvider is called because the server
+// rejected the previous token, so any cached token must be replaced.
+func IsTokenRefresh(ctx context.Context) bool {
+	_, ok := internal.RejectedToken(ctx)
+	return ok
+}
+
 // WithInternalTokenHeader sets the header name for internal token.
 func WithInternalTokenHeader(header string) Option {
 	return func(o *Options) {
@@ -86,8 +278,258 @@ func WithInternalTokenHeader(header string) Option {
 	}
 }
 
+// WithMaxIdleConns sets the number of idle connections kept across all hosts.
+// Zero means no limit.
+func WithMaxIdleConns(n int) Option {
+	return func(o *Options) {
+		o.MaxIdleConns = n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept per host.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(o *Options) {
+		o.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithMaxConnsPerHost limits the connections per host, including active ones.
+// Requests beyond the limit wait for a connection. Zero means no limit.
+func WithMaxConnsPerHost(n int) Option {
+	return func(o *Options) {
+		o.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before it is
+// closed. Zero means no limit.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.IdleConnTimeout = d
+	}
+}
+
+// WithMaxStreamsPerConn opens another connection once every connection carries
+// n in-flight requests, instead of multiplexing all requests over a single
+// HTTP/2 connection where one slow stream can delay the others. Connections
+// are capped by WithMaxConnsPerHost; at the cap requests share the least
+// loaded connection. Zero, the default, uses a single HTTP/2 connection.
+func WithMaxStreamsPerConn(n int) Option {
+	return func(o *Options) {
+		o.MaxStreamsPerConn = n
+	}
+}
+
+// WithH2C makes http:// requests use HTTP/2 without TLS (prior knowledge), as
+// served by runtimex with H2C enabled. HTTP/1 is then not used at all, so the
+// target must speak HTTP/2.
+func WithH2C(enabled bool) Option {
+	return func(o *Options) {
+		o.H2C = enabled
+	}
+}
+
+// WithHTTP2HealthCheck configures detection of dead HTTP/2 connections: a
+// connection that received no frame for readIdleTimeout is pinged, and closed
+// if the ping is not answered within pingTimeout. A zero readIdleTimeout
+// disables the pings.
+func WithHTTP2HealthCheck(readIdleTimeout, pingTimeout time.Duration) Option {
+	return func(o *Options) {
+		o.HTTP2ReadIdleTimeout = readIdleTimeout
+		o.HTTP2PingTimeout = pingTimeout
+	}
+}
+
+// WithHedging sends backup copies of slow idempotent requests to cut tail latency.
+//
+// If a call has not completed after h.Delay, a copy is sent, up to h.MaxHedges
+// copies at h.Delay intervals. The first response wins and the other copies
+// are cancelled. Set h.Delay from the latency distribution of the call, e.g.
+// its p95, so only the slowest requests are hedged.
+//
+// Only requests the default retry policy considers idempotent are hedged (see
+// WithIdempotentMethods). Hedging happens within each retry attempt, so the
+// copies share the attempt's timeout and the WithDeadline budget, and no copy
+// is sent with less time left than h.Delay.
+//
+// With h.MeterProvider, or else the WithMetrics provider, rpc_client_hedges_total counts backup requests sent
+// and rpc_client_hedge_wins_total those that returned first.
+//
+// Example:
+//
+//	clientx.WithHedging(clientx.HedgeOptions{
+//	  Delay:         50 * time.Millisecond,
+//	  MaxHedges:     1,
+//	  MeterProvider: provider.MeterProvider(),
+//	})
+func WithHedging(h HedgeOptions) Option {
+	return func(o *Options) {
+		o.Hedging = h
+	}
+}
+
+// WithStreaming prepares the client for streaming calls such as server
+// streams. Streams get their own timeouts: s.EstablishTimeout bounds each
+// attempt until the response headers arrive, and s.Timeout the whole stream.
+// WithTimeout then applies to unary calls only, instead of cutting every
+// stream off after Timeout.
+//
+// Whether or not WithStreaming is used, a stream is retried only if an
+// attempt failed transiently before it obtained a connection, such as a
+// refused dial; the retry policy is not consulted, and once a connection is
+// established the stream is never retried, so no message is sent twice.
+// Hedging and WithPerAttemptTimeout on the body read do not apply to
+// streams; without WithStreaming the per-attempt timeout serves as the
+// establishment timeout. WithDeadline bounds establishment, and the circuit
+// breaker and token injection apply as for unary calls.
+//
+// Example:
+//
+//	greeter, err := clientx.New(cfg.GreetServiceURL, greetv1connect.NewGreeterServiceClient,
+//	  clientx.WithStreaming(clientx.StreamOptions{
+//	    EstablishTimeout: 5 * time.Second,
+//	    Timeout:          10 * time.Minute,
+//	  }),
+//	)
+func WithStreaming(s StreamOptions) Option {
+	return func(o *Options) {
+		if s.EstablishTimeout <= 0 {
+			s.EstablishTimeout = 10 * time.Second
+		}
+		o.Streaming = s
+	}
+}
+
+// WithMetrics records outbound request metrics with meterProvider, such as
+// the one returned by obsx.Provider.MeterProvider. Without it no metrics are
+// recorded.
+//
+// Metrics (labels rpc_service, rpc_method, rpc_code):
+//   - clientx_requests_total: logical requests; retries are not counted again
+//   - clientx_request_attempts_total: attempts, including every retry
+//   - clientx_request_duration_seconds: logical request duration, including retries and backoff
+//
+// Connect calls are labelled with the service and method of their procedure,
+// other requests with the target host and HTTP method. rpc_code is the
+// Connect code of the outcome, "ok" on success.
+func WithMetrics(meterProvider metric.MeterProvider) Option {
+	return func(o *Options) {
+		o.MeterProvider = meterProvider
+	}
+}
+
+// WithClock runs retry backoff and the Deadline budget on c instead of the
+// system clock, so tests can skip backoff with a fake clock such as
+// testingx.FakeClock. Per-attempt timeouts still use the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(o *Options) {
+		o.Clock = c
+	}
+}
+
+// WithInterceptors adds Connect interceptors to clients built by New and
+// NewConnectClient, for example to add auth, tracing or idempotency headers
+// or to log outbound calls without wrapping the generated client. Repeated
+// use appends; the first interceptor is the outermost.
+//
+// Interceptors run once per call, above the HTTP transport where retries,
+// hedging, load balancing, the circuit breaker, token injection and
+// metrics operate. An interceptor therefore sees one logical call: headers
+// it sets are sent with every retry and hedge attempt, the error it sees is
+// the final one after retries, and time it measures includes backoff. To
+// act on each attempt, wrap the transport instead.
+//
+// Clients made from an HTTPClient directly get the interceptors through
+// HTTPClient.ConnectOptions.
+//
+// Example:
+//
+//	greeter, err := clientx.New(cfg.GreetServiceURL, greetv1connect.NewGreeterServiceClient,
+//	  clientx.WithInterceptors(connect.UnaryInterceptorFunc(
+//	    func(next connect.UnaryFunc) connect.UnaryFunc {
+//	      return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+//	        req.Header().Set("X-Tenant-Id", tenantFrom(ctx))
+//	        return next(ctx, req)
+//	      }
+//	    },
+//	  )),
+//	)
+func WithInterceptors(interceptors ...connect.Interceptor) Option {
+	return func(o *Options) {
+		o.Interceptors = append(o.Interceptors, interceptors...)
+	}
+}
+
+// NormalizeBaseURL validates a base URL and returns the form used by clients.
+//
+// A missing scheme defaults to http and trailing slashes are stripped, so
+// "api.example.com:8080/" becomes "http://api.example.com:8080". Unsupported
+// schemes, missing hosts, queries and fragments are rejected.
+//
+// Parameters:
+//   - baseURL: base URL of the target service
+//
+// Returns:
+//   - string: normalized base URL
+//   - error: descriptive validation error if the URL is invalid
+func NormalizeBaseURL(baseURL string) (string, error) {
+	return internal.NormalizeBaseURL(baseURL)
+}
+
+// HTTPClient is a resilient HTTP client bound to a base URL.
+//
+// It embeds *http.Client, so it can be passed wherever a connect.HTTPClient is
+// expected. Each HTTPClient owns its connection pool.
+type HTTPClient struct {
+	*http.Client
+	baseURL      string
+	interceptors []connect.Interceptor
+	probe        http.RoundTripper // Health probe transport: pool, header propagation and token, no retries
+}
+
 // NewHTTPClient creates a new HTTP client with Connect interceptors.
-func NewHTTPClient(baseURL string, opts ...Option) *http.Client {
+// If baseURL is invalid, every request made with the returned client fails
+// immediately with the validation error from NormalizeBaseURL; use
+// NewHTTPClientE or New to get the error at construction instead.
+func NewHTTPClient(baseURL string, opts ...Option) *HTTPClient {
+	options := applyOptions(opts)
+	client, err := newHTTPClient(baseURL, options)
+	if err != nil {
+		return &HTTPClient{
+			Client: &http.Client{
+				Timeout:   options.Timeout,
+				Transport: &internal.ErrorTransport{Err: err},
+			},
+			baseURL:      baseURL,
+			interceptors: options.Interceptors,
+		}
+	}
+	return client
+}
+
+// NewHTTPClientE creates a new HTTP client like NewHTTPClient, validating
+// baseURL up front.
+//
+// Parameters:
+//   - baseURL: base URL of the target service, validated as by NormalizeBaseURL
+//   - opts: client options
+//
+// Returns:
+//   - *HTTPClient: client bound to the normalized base URL
+//   - error: descriptive error if baseURL is invalid or the client cannot be built
+//
+// Example:
+//
+//	client, err := clientx.NewHTTPClientE(cfg.UserServiceURL, clientx.WithTimeout(5*time.Second))
+//	if err != nil {
+//	  return fmt.Errorf("user client: %w", err)
+//	}
+func NewHTTPClientE(baseURL string, opts ...Option) (*HTTPClient, error) {
+	return newHTTPClient(baseURL, applyOptions(opts))
+}
+
+// applyOptions returns the default options with opts applied.
+func applyOptions(opts []Option) Options {
 	options := Options{
 		Timeout:          30 * time.Second,
 		MaxRetries:       3,
@@ -95,11 +537,44 @@ func NewHTTPClient(baseURL string, opts ...Option) *http.Client {
 		EnableCircuit:    true,
 		CircuitThreshold: 5,
 		IdempotencyKey:   "X-Idempotency-Key",
+
+		PropagateDeadline: true,
+		PropagateHeaders:  true,
+
+		InternalTokenHeader: "X-Internal-Token",
+
+		MaxIdleConns:         internal.DefaultMaxIdleConns,
+		MaxIdleConnsPerHost:  internal.DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:      internal.DefaultIdleConnTimeout,
+		HTTP2ReadIdleTimeout: internal.DefaultHTTP2ReadIdleTimeout,
+		HTTP2PingTimeout:     internal.DefaultHTTP2PingTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(&options)
 	}
+	return options
+}
+
+// newHTTPClient builds the resilient client for baseURL, failing if baseURL is
+// invalid or the configured metrics cannot be created.
+func newHTTPClient(baseURL string, options Options) (*HTTPClient, error) {
+	return newClient([]string{baseURL}, nil, options)
+}
+
+// newClient builds the resilient client for the first of baseURLs. With
+// balance set, requests are spread over all baseURLs below the retry
+// transport.
+func newClient(baseURLs []string, balance *BalanceOptions, options Options) (*HTTPClient, error) {
+	normalizedURLs := make([]string, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		normalized, err := internal.NormalizeBaseURL(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		normalizedURLs[i] = normalized
+	}
+	normalized := normalizedURLs[0]
 
 	// Create circuit breaker if enabled
 	var cb *gobreaker.CircuitBreaker
@@ -114,55 +589,339 @@ func NewHTTPClient(baseURL string, opts ...Option) *http.Client {
 		})
 	}
 
+	policy := options.RetryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy(options.IdempotencyKey, options.IdempotentMethods)
+	}
+
+	pooled := internal.NewPooledTransport(internal.PoolConfig{
+		MaxIdleConns:         options.MaxIdleConns,
+		MaxIdleConnsPerHost:  options.MaxIdleConnsPerHost,
+		MaxConnsPerHost:      options.MaxConnsPerHost,
+		IdleConnTimeout:      options.IdleConnTimeout,
+		MaxStreamsPerConn:    options.MaxStreamsPerConn,
+		H2C:                  options.H2C,
+		HTTP2ReadIdleTimeout: options.HTTP2ReadIdleTimeout,
+		HTTP2PingTimeout:     options.HTTP2PingTimeout,
+	})
+
+	var endpoints http.RoundTripper = pooled
+	if balance != nil {
+		balancer, err := internal.NewBalanceTransport(pooled, normalizedURLs, internal.BalanceConfig{
+			Strategy:    internal.BalanceStrategy(balance.Strategy),
+			MaxFailures: balance.MaxFailures,
+			Cooldown:    balance.Cooldown,
+			HealthPath:  balance.HealthPath,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if options.Clock != nil {
+			balancer = balancer.WithClock(internal.NewClock(options.Clock))
+		}
+		endpoints = balancer
+	}
+
+	var metrics *internal.ClientMetrics
+	if options.MeterProvider != nil {
+		var err error
+		if metrics, err = internal.NewClientMetrics(options.MeterProvider); err != nil {
+			return nil, fmt.Errorf("client metrics: %w", err)
+		}
+	}
+
+	hedgeMeter := options.Hedging.MeterProvider
+	if hedgeMeter == nil {
+		hedgeMeter = options.MeterProvider
+	}
+
+	base := endpoints
+	if options.Hedging.Delay > 0 {
+		maxHedges := options.Hedging.MaxHedges
+		if maxHedges <= 0 {
+			maxHedges = 1
+		}
+		hedged, err := internal.NewHedgeTransport(endpoints, options.Hedging.Delay, maxHedges,
+			internal.NewIdempotencyCheck(options.IdempotencyKey, options.IdempotentMethods), hedgeMeter)
+		if err != nil {
+			return nil, fmt.Errorf("hedging metrics: %w", err)
+		}
+		base = hedged
+	}
+	if metrics != nil {
+		base = metrics.AttemptTransport(base)
+	}
+
+	retry := internal.NewRetryTransport(base, options.MaxRetries, options.RetryBackoff, cb).
+		WithBudget(internal.RetryBudget{PerAttempt: options.PerAttemptTimeout, Deadline: options.Deadline}).
+		WithPolicy(internal.RetryPolicy(policy)).
+		WithDeadlinePropagation(options.PropagateDeadline).
+		WithHeaderPropagation(options.PropagateHeaders)
+	timeout := options.Timeout
+	if options.Streaming.EstablishTimeout > 0 || options.Streaming.Timeout > 0 {
+		// Enforce Timeout per unary request so it does not bound streams
+		retry = retry.WithStreams(internal.StreamConfig{
+			EstablishTimeout: options.Streaming.EstablishTimeout,
+			Timeout:          options.Streaming.Timeout,
+		}).WithRequestTimeout(timeout)
+		timeout = 0
+	}
+	if options.Clock != nil {
+		retry = retry.WithClock(internal.NewClock(options.Clock))
+	}
+
+	var transport http.RoundTripper = retry
+
+	tokens := options.TokenProvider
+	if tokens == nil && options.InternalToken != "" {
+		token := options.InternalToken
+		tokens = func(context.Context) (string, error) { return token, nil }
+	}
+	// Health probes share the connection pool, header propagation and token,
+	// but skip balancing, retries and circuit breaking
+	var probe http.RoundTripper = internal.NewRetryTransport(pooled, 0, 0, nil).
+		WithHeaderPropagation(options.PropagateHeaders)
+	if tokens != nil {
+		transport = internal.NewTokenTransport(transport, internal.TokenProvider(tokens), options.InternalTokenHeader)
+		probe = internal.NewTokenTransport(probe, internal.TokenProvider(tokens), options.InternalTokenHeader)
+	}
+	if metrics != nil {
+		transport = metrics.RequestTransport(transport)
+	}
+
 	// Create HTTP client with timeout
 	client := &http.Client{
-		Timeout:   options.Timeout,
-		Transport: internal.NewRetryTransport(http.DefaultTransport, options.MaxRetries, options.RetryBackoff, cb),
+		Timeout:   timeout,
+		Transport: transport,
 	}
 
-	return client
+	return &HTTPClient{Client: client, baseURL: normalized, interceptors: options.Interceptors, probe: probe}, nil
 }
 
-// NewConnectClient creates a Connect client with interceptors.
-// This is a convenience wrapper for creating Connect clients with standard interceptors.
-func NewConnectClient[T any](baseURL, serviceName string, newClient func(connect.HTTPClient, string, ...connect.ClientOption) T, opts ...Option) T {
-	// Apply options
-	options := Options{
-		Timeout:            30 * time.Second,
-		MaxRetries:         3,
-		RetryBackoff:       100 * time.Millisecond,
-		EnableCircuit:      true,
-		CircuitThreshold:   5,
-		IdempotencyKey:     "X-Idempotency-Key",
-		InternalTokenHeader: "X-Internal-Token",
+// BaseURL returns the normalized base URL requests are made against. For a
+// balanced client it is the first URL; requests to it are spread over all
+// endpoints.
+func (c *HTTPClient) BaseURL() string {
+	return c.baseURL
+}
+
+// ConnectOptions returns the Connect client options that apply the
+// interceptors of WithInterceptors, for clients built from an HTTPClient
+// directly. It is empty without interceptors.
+//
+// Example:
+//
+//	client, err := clientx.NewBalancedHTTPClient(urls, clientx.BalanceOptions{}, opts...)
+//	users := userv1connect.NewUserServiceClient(client, client.BaseURL(), client.ConnectOptions()...)
+func (c *HTTPClient) ConnectOptions() []connect.ClientOption {
+	if len(c.interceptors) == 0 {
+		return nil
 	}
-	for _, opt := range opts {
-		opt(&options)
+	return []connect.ClientOption{connect.WithInterceptors(c.interceptors...)}
+}
+
+// WithCallTimeout returns a context whose calls use timeout d in place of the
+// client's WithTimeout, longer or shorter, without building another client.
+// It bounds the whole call, including retries and, for streams, the stream
+// lifetime. WithPerAttemptTimeout, WithDeadline and deadlines of ctx itself
+// still apply; d <= 0 means no timeout.
+//
+// Per-call overrides take precedence over client options. The timeout is
+// applied by Do, which Connect clients use; Get, Post and the other helpers
+// of the embedded http.Client keep the client timeout.
+//
+// Example:
+//
+//	ctx := clientx.WithCallTimeout(ctx, 2*time.Minute)
+//	report, err := reports.Generate(ctx, connect.NewRequest(req))
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return internal.WithCallTimeout(ctx, d)
+}
+
+// WithNoRetry returns a context whose calls are attempted once, whatever
+// WithRetry and WithIdempotentMethods allow, e.g. for a call the caller
+// retries itself. Backup requests of WithHedging are not affected.
+//
+// Example:
+//
+//	_, err := payments.Capture(clientx.WithNoRetry(ctx), connect.NewRequest(req))
+func WithNoRetry(ctx context.Context) context.Context {
+	return internal.WithNoRetry(ctx)
+}
+
+// Do sends req like http.Client.Do, applying a timeout set on the request
+// context with WithCallTimeout in place of the client timeout.
+func (c *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return doCall(c.Client, req)
+}
+
+// doCall sends req with client, using a copy with the per-call timeout of
+// the request context if one is set.
+func doCall(client *http.Client, req *http.Request) (*http.Response, error) {
+	d, ok := internal.CallTimeout(req.Context())
+	if !ok {
+		return client.Do(req)
+	}
+	if d < 0 {
+		d = 0
 	}
+	call := *client
+	call.Timeout = d
+	return call.Do(req)
+}
 
-	httpClient := NewHTTPClient(baseURL, opts...)
+// BalanceStrategy selects the endpoint of each request of a balanced client.
+type BalanceStrategy int
+
+const (
+	// RoundRobin sends requests to endpoints in turn.
+	RoundRobin BalanceStrategy = BalanceStrategy(internal.BalanceRoundRobin)
+	// LeastPending sends requests to the endpoint with the fewest requests
+	// in flight.
+	LeastPending BalanceStrategy = BalanceStrategy(internal.BalanceLeastPending)
+)
+
+// BalanceOptions configures client-side load balancing.
+type BalanceOptions struct {
+	Strategy    BalanceStrategy // Endpoint selection (default: RoundRobin)
+	MaxFailures int             // Consecutive failures that eject an endpoint (default: 3)
+	Cooldown    time.Duration   // Time an ejected endpoint receives no requests (default: 30s)
+	HealthPath  string          // Probed before re-admission, e.g. "/health" (empty = trial request)
+}
+
+// NewBalancedHTTPClient creates a resilient client that spreads requests over
+// several equivalent endpoints of one service.
+//
+// Requests are made against BaseURL (the first URL) and rewritten to the
+// selected endpoint below the retry and circuit breaker transport, so a retry
+// can land on a different endpoint; hedged copies are balanced too. Transport
+// errors and 502, 503 and 504 responses count as endpoint failures. After
+// MaxFailures consecutive failures an endpoint is ejected for Cooldown, then
+// probed at HealthPath and re-admitted once the probe returns 2xx; without a
+// HealthPath the next request is a trial that re-admits or ejects it again.
+// If every endpoint is ejected, requests are spread over all of them. The
+// circuit breaker covers the service as a whole.
+//
+// Parameters:
+//   - urls: base URLs of the endpoints, validated as by NormalizeBaseURL
+//   - balance: balancing configuration
+//   - opts: client options as for NewHTTPClient
+//
+// Returns:
+//   - *HTTPClient: balanced client
+//   - error: if urls is empty or a URL is invalid
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Example:
+//
+//	client, err := clientx.NewBalancedHTTPClient(
+//	  []string{"http://user-a:8080", "http://user-b:8080"},
+//	  clientx.BalanceOptions{Strategy: clientx.LeastPending, HealthPath: "/health"},
+//	)
+//	if err != nil {
+//	  return err
+//	}
+//	users := userv1connect.NewUserServiceClient(client, client.BaseURL())
+func NewBalancedHTTPClient(urls []string, balance BalanceOptions, opts ...Option) (*HTTPClient, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("at least one base URL is required")
+	}
+	return newClient(urls, &balance, applyOptions(opts))
+}
+
+// Warmup opens and primes n connections to the base URL before real traffic,
+// so the first requests do not pay for dialing and TLS handshakes.
+//
+// Warmup sends n concurrent GET requests for the base URL through the client's
+// retry and circuit breaker transport and keeps the resulting connections in
+// the idle pool. Any HTTP response counts as a primed connection, whatever its
+// status. Over HTTP/2 the requests share the negotiated connection as streams.
+//
+// Parameters:
+//   - ctx: bounds the whole warm-up
+//   - n: number of connections to open (no-op if <= 0)
+//
+// Returns:
+//   - error: if any request failed, e.g. because the circuit breaker is open
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Example:
+//
+//	client := clientx.NewHTTPClient("http://user-service:8080")
+//	if err := client.Warmup(ctx, 8); err != nil {
+//	  logger.Warn("warmup failed", log.Str("error", err.Error()))
+//	}
+func (c *HTTPClient) Warmup(ctx context.Context, n int) error {
+	return internal.Warmup(ctx, c.Client, c.baseURL, n)
+}
 
-	// Build client options
-	var clientOpts []connect.ClientOption
+// ConnectFactory is the signature of constructors generated by
+// protoc-gen-connect-go, such as greetv1connect.NewGreeterServiceClient.
+type ConnectFactory[T any] func(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) T
 
-	// Add internal token interceptor if token is provided
-	if options.InternalToken != "" {
-		clientOpts = append(clientOpts, connect.WithInterceptors(
-			internalTokenInterceptor(options.InternalToken, options.InternalTokenHeader),
-		))
+// New creates a typed Connect client backed by a resilient HTTP client.
+//
+// Parameters:
+//   - baseURL: base URL of the target service, validated as by NormalizeBaseURL
+//   - factory: generated client constructor, passed as is
+//   - opts: client options
+//
+// Returns:
+//   - T: client calling the normalized base URL
+//   - error: descriptive error if baseURL is invalid or the client cannot be built
+//
+// Example:
+//
+//	greeter, err := clientx.New(cfg.GreetServiceURL, greetv1connect.NewGreeterServiceClient,
+//	  clientx.WithTimeout(10*time.Second),
+//	)
+//	if err != nil {
+//	  return fmt.Errorf("greet client: %w", err)
+//	}
+func New[T any](baseURL string, factory ConnectFactory[T], opts ...Option) (T, error) {
+	client, err := newHTTPClient(baseURL, applyOptions(opts))
+	if err != nil {
+		var zero T
+		return zero, err
 	}
+	return factory(client, client.baseURL, client.ConnectOptions()...), nil
+}
 
-	return newClient(httpClient, baseURL, clientOpts...)
+// NewConnectClient creates a Connect client with interceptors.
+// This is a convenience wrapper for creating Connect clients with standard interceptors.
+// The base URL is normalized before it is passed to newClient; an invalid base URL
+// yields a client whose calls fail immediately with the validation error.
+// NewConnectClientE and New report that error at construction instead.
+func NewConnectClient[T any](baseURL, serviceName string, newClient func(connect.HTTPClient, string, ...connect.ClientOption) T, opts ...Option) T {
+	httpClient := NewHTTPClient(baseURL, opts...)
+	if normalized, err := internal.NormalizeBaseURL(baseURL); err == nil {
+		baseURL = normalized
+	}
+
+	return newClient(httpClient, baseURL, httpClient.ConnectOptions()...)
 }
 
-// internalTokenInterceptor creates a client-side interceptor that adds internal token to requests.
-func internalTokenInterceptor(token, headerName string) connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			if token != "" && req.Header() != nil {
-				req.Header().Set(headerName, token)
-			}
-			return next(ctx, req)
-		}
+// NewConnectClientE creates a Connect client like NewConnectClient, validating
+// baseURL up front. newClient is not called for an invalid base URL.
+//
+// Parameters:
+//   - baseURL: base URL of the target service, validated as by NormalizeBaseURL
+//   - serviceName: name of the target service
+//   - newClient: client constructor, called with the normalized base URL
+//   - opts: client options
+//
+// Returns:
+//   - T: client calling the normalized base URL
+//   - error: descriptive error if baseURL is invalid or the client cannot be built
+func NewConnectClientE[T any](baseURL, serviceName string, newClient func(connect.HTTPClient, string, ...connect.ClientOption) T, opts ...Option) (T, error) {
+	httpClient, err := NewHTTPClientE(baseURL, opts...)
+	if err != nil {
+		var zero T
+		return zero, err
 	}
+	return newClient(httpClient, httpClient.baseURL, httpClient.ConnectOptions()...), nil
 }
@@ -1,12 +1,26 @@
 package clientx
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
+	"github.com/sony/gobreaker"
 	"go.eggybyte.com/egg/clientx/internal"
+	"go.eggybyte.com/egg/core/clock"
+	"go.eggybyte.com/egg/core/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestNewHTTPClient(t *testing.T) {
@@ -30,6 +44,38 @@ func TestWithTimeout(t *testing.T) {
 	}
 }
 
+func TestWithDeadline_BoundsRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithRetry(10),
+		WithPerAttemptTimeout(time.Second),
+		WithDeadline(150*time.Millisecond),
+		WithCircuitBreaker(false),
+		func(o *Options) { o.RetryBackoff = 20 * time.Millisecond },
+	)
+
+	// Attempt 2 only gets the ~30-40ms left after attempt 1 and one jittered backoff
+	start := time.Now()
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want DeadlineExceeded once the budget runs out", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get() took %v, want retries to stop near the 150ms deadline", elapsed)
+	}
+	if got := attempts.Load(); got > 2 {
+		t.Errorf("upstream received %d attempts, want at most 2 within the deadline", got)
+	}
+}
+
 func TestWithRetry(t *testing.T) {
 	client := NewHTTPClient("https://api.example.com",
 		WithRetry(5),
@@ -82,6 +128,78 @@ func TestRetryOn5xx(t *testing.T) {
 	}
 }
 
+// instantClock is a clock.Clock whose timers fire immediately, recording
+// the durations waited for.
+type instantClock struct {
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (c *instantClock) Now() time.Time { return time.Now() }
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time { return c.NewTimer(d).C() }
+
+func (c *instantClock) NewTimer(d time.Duration) clock.Timer {
+	c.mu.Lock()
+	c.waits = append(c.waits, d)
+	c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return instantTimer{ch: ch}
+}
+
+func (c *instantClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	go f()
+	return instantTimer{}
+}
+
+type instantTimer struct{ ch chan time.Time }
+
+func (t instantTimer) C() <-chan time.Time        { return t.ch }
+func (t instantTimer) Stop() bool                 { return false }
+func (t instantTimer) Reset(d time.Duration) bool { return false }
+
+func TestWithClock_SkipsBackoff(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fake := &instantClock{}
+	client := NewHTTPClient(server.URL,
+		WithRetry(3),
+		WithCircuitBreaker(false),
+		WithClock(fake),
+		func(o *Options) { o.RetryBackoff = time.Hour },
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.waits) != 2 {
+		t.Fatalf("Expected 2 backoff waits on the clock, got %v", fake.waits)
+	}
+	// Equal jitter waits between half and all of the exponential backoff.
+	if fake.waits[0] < 30*time.Minute || fake.waits[0] > time.Hour ||
+		fake.waits[1] < time.Hour || fake.waits[1] > 2*time.Hour {
+		t.Errorf("Expected backoff waits within [30m,1h] and [1h,2h], got %v", fake.waits)
+	}
+}
+
 func TestNoRetryOn4xx(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +231,170 @@ func TestNoRetryOn4xx(t *testing.T) {
 	}
 }
 
+func TestWithNoRetry(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithRetry(3), WithCircuitBreaker(false), WithClock(&instantClock{}))
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want int32
+	}{
+		"client options": {ctx: context.Background(), want: 4},
+		"per call":       {ctx: WithNoRetry(context.Background()), want: 1},
+	} {
+		attempts.Store(0)
+		req, _ := http.NewRequestWithContext(tc.ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: Do() error = %v", name, err)
+		}
+		resp.Body.Close()
+		if got := attempts.Load(); got != tc.want {
+			t.Errorf("%s: attempts = %d, want %d", name, got, tc.want)
+		}
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	get := func(client *HTTPClient, ctx context.Context) error {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// A longer per-call timeout overrides a short client timeout
+	short := NewHTTPClient(server.URL, WithTimeout(50*time.Millisecond), WithRetry(0))
+	if err := get(short, context.Background()); err == nil {
+		t.Error("client timeout should fail the slow call")
+	}
+	if err := get(short, WithCallTimeout(context.Background(), 5*time.Second)); err != nil {
+		t.Errorf("call with a longer timeout failed: %v", err)
+	}
+
+	// A shorter per-call timeout applies too, also with streaming timeouts
+	// moving the client timeout into the transport
+	for name, client := range map[string]*HTTPClient{
+		"unary":     NewHTTPClient(server.URL, WithTimeout(5*time.Second), WithRetry(0)),
+		"streaming": NewHTTPClient(server.URL, WithTimeout(5*time.Second), WithRetry(0), WithStreaming(StreamOptions{Timeout: time.Minute})),
+	} {
+		start := time.Now()
+		if err := get(client, WithCallTimeout(context.Background(), 20*time.Millisecond)); err == nil {
+			t.Errorf("%s: call with a short timeout should fail", name)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("%s: call took %v, want about 20ms", name, elapsed)
+		}
+	}
+}
+
+func TestWithIdempotentMethods(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithRetry(2),
+		WithCircuitBreaker(false),
+		WithIdempotentMethods("/user.v1.UserService/GetUser"),
+	)
+
+	for path, want := range map[string]int32{
+		"/user.v1.UserService/GetUser":    3,
+		"/user.v1.UserService/CreateUser": 1,
+	} {
+		attempts.Store(0)
+		resp, err := client.Post(server.URL+path, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("Post(%s) error = %v", path, err)
+		}
+		resp.Body.Close()
+		if got := attempts.Load(); got != want {
+			t.Errorf("Post(%s) attempts = %d, want %d", path, got, want)
+		}
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithRetry(2),
+		WithCircuitBreaker(false),
+		WithRetryPolicy(func(req *http.Request, err error, attempt int) bool {
+			return connect.CodeOf(err) == connect.CodeResourceExhausted
+		}),
+	)
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3 with a policy retrying 429", got)
+	}
+}
+
+func TestWithStreaming_StreamsOutliveTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		fmt.Fprint(w, "done")
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithTimeout(50*time.Millisecond),
+		WithStreaming(StreamOptions{EstablishTimeout: time.Second}),
+	)
+
+	post := func(contentType string) (string, error) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/greet.v1.GreeterService/SayHelloStream", strings.NewReader("hello"))
+		req.Header.Set("Content-Type", contentType)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	if body, err := post("application/connect+proto"); err != nil || body != "done" {
+		t.Errorf("Stream = %q, %v; want it to outlive the 50ms timeout", body, err)
+	}
+	if _, err := post("application/proto"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unary call error = %v, want deadline exceeded", err)
+	}
+}
+
 func TestCircuitBreakerEnabled(t *testing.T) {
 	client := NewHTTPClient("https://api.example.com",
 		WithCircuitBreaker(true),
@@ -170,3 +452,660 @@ func BenchmarkRetryTransport(b *testing.B) {
 		resp.Body.Close()
 	}
 }
+
+func TestNewHTTPClient_InvalidBaseURL(t *testing.T) {
+	client := NewHTTPClient("ftp://files.example.com", WithCircuitBreaker(false))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:1/", nil)
+	_, err := client.Do(req)
+	if err == nil {
+		t.Fatal("Request with invalid base URL should fail")
+	}
+	if !strings.Contains(err.Error(), "unsupported scheme") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestNewHTTPClientE(t *testing.T) {
+	client, err := NewHTTPClientE("localhost:8080/", WithCircuitBreaker(false))
+	if err != nil {
+		t.Fatalf("NewHTTPClientE() error = %v", err)
+	}
+	if got := client.BaseURL(); got != "http://localhost:8080" {
+		t.Errorf("BaseURL() = %q, want normalized URL", got)
+	}
+
+	client, err = NewHTTPClientE("ftp://files.example.com")
+	if err == nil || !strings.Contains(err.Error(), "unsupported scheme") {
+		t.Errorf("NewHTTPClientE() error = %v, want unsupported scheme", err)
+	}
+	if client != nil {
+		t.Error("NewHTTPClientE() should not return a client for an invalid base URL")
+	}
+}
+
+func TestNewConnectClientE(t *testing.T) {
+	var gotURL string
+	factory := func(_ connect.HTTPClient, url string, _ ...connect.ClientOption) string {
+		gotURL = url
+		return "client"
+	}
+
+	client, err := NewConnectClientE("localhost:8080/", "test-service", factory)
+	if err != nil || client != "client" {
+		t.Fatalf("NewConnectClientE() = %q, %v", client, err)
+	}
+	if gotURL != "http://localhost:8080" {
+		t.Errorf("Expected normalized base URL http://localhost:8080, got %q", gotURL)
+	}
+
+	gotURL = ""
+	client, err = NewConnectClientE("http://", "test-service", factory)
+	if err == nil {
+		t.Fatal("NewConnectClientE() should fail for a URL without host")
+	}
+	if client != "" || gotURL != "" {
+		t.Error("newClient should not be called for an invalid base URL")
+	}
+}
+
+func TestNewConnectClient_NormalizesBaseURL(t *testing.T) {
+	var gotURL string
+	NewConnectClient("localhost:8080/", "test-service",
+		func(_ connect.HTTPClient, url string, _ ...connect.ClientOption) struct{} {
+			gotURL = url
+			return struct{}{}
+		},
+	)
+
+	if gotURL != "http://localhost:8080" {
+		t.Errorf("Expected normalized base URL http://localhost:8080, got %q", gotURL)
+	}
+}
+
+func TestWithH2C(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithH2C(true),
+		WithMaxStreamsPerConn(10),
+		WithHTTP2HealthCheck(5*time.Second, time.Second),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "HTTP/2.0" {
+		t.Errorf("server saw %s, want HTTP/2.0 over h2c", body)
+	}
+}
+
+func TestWithHedging(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("hedged"))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL,
+		WithCircuitBreaker(false),
+		WithHedging(HedgeOptions{Delay: 20 * time.Millisecond}),
+	)
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "hedged" {
+		t.Errorf("body = %q, want the hedged response", body)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() took %v, want the hedge to cut the slow request short", elapsed)
+	}
+}
+
+func TestWithTokenProvider_RefreshesOnUnauthenticated(t *testing.T) {
+	var current atomic.Int32
+	current.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Internal-Token") != fmt.Sprintf("token-%d", current.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var fetches, refreshes atomic.Int32
+	provider := CachedTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		fetches.Add(1)
+		if IsTokenRefresh(ctx) {
+			refreshes.Add(1)
+		}
+		return fmt.Sprintf("token-%d", current.Load()), time.Now().Add(time.Hour), nil
+	})
+	client := NewHTTPClient(server.URL, WithCircuitBreaker(false), WithTokenProvider(provider))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("StatusCode = %d, want 200", resp.StatusCode)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want 1 cached token", got)
+	}
+
+	// The server rotates its token; the next call refreshes and succeeds
+	current.Store(2)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200 after the refresh", resp.StatusCode)
+	}
+	if got := refreshes.Load(); got != 1 {
+		t.Errorf("refreshes = %d, want 1", got)
+	}
+}
+
+func TestWithInternalToken_SetsHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("X-Service-Token")))
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithInternalTokenHeader("X-Service-Token"), WithInternalToken("secret"))
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "secret" {
+		t.Errorf("token header = %q, want secret", body)
+	}
+}
+
+func TestWithHeaderPropagation(t *testing.T) {
+	var mu sync.Mutex
+	var received []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Clone())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	// Inbound request as seen by the connectx identity interceptor
+	inbound := http.Header{}
+	inbound.Set("X-Tenant-Id", "acme")
+	inbound.Set("Accept-Language", "de")
+	inbound.Set("Authorization", "Bearer caller-secret")
+	ctx := propagation.With(context.Background(), propagation.Extract(inbound, []string{"X-Tenant-Id", "Accept-Language"}))
+
+	send := func(client *HTTPClient, header http.Header) http.Header {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		return received[len(received)-1]
+	}
+
+	got := send(NewHTTPClient(server.URL), http.Header{"Accept-Language": {"fr"}})
+	if got.Get("X-Tenant-Id") != "acme" {
+		t.Errorf("X-Tenant-Id = %q, want acme", got.Get("X-Tenant-Id"))
+	}
+	if got.Get("Accept-Language") != "fr" {
+		t.Errorf("Accept-Language = %q, want the explicitly set fr", got.Get("Accept-Language"))
+	}
+	if got.Get("Authorization") != "" {
+		t.Error("headers not in the allowlist must not be propagated")
+	}
+
+	got = send(NewHTTPClient(server.URL, WithHeaderPropagation(false)), nil)
+	if got.Get("X-Tenant-Id") != "" {
+		t.Error("WithHeaderPropagation(false) should not send propagated headers")
+	}
+}
+
+// greeterClient stands in for a generated Connect client.
+type greeterClient interface {
+	BaseURL() string
+}
+
+type fakeGreeter struct {
+	httpClient connect.HTTPClient
+	baseURL    string
+}
+
+func (g *fakeGreeter) BaseURL() string { return g.baseURL }
+
+// newGreeterClient has the signature of a protoc-gen-connect-go constructor.
+func newGreeterClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) greeterClient {
+	return &fakeGreeter{httpClient: httpClient, baseURL: baseURL}
+}
+
+func TestNew_ReturnsTypedClient(t *testing.T) {
+	client, err := New("greet-service:8080/", newGreeterClient, WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := client.BaseURL(); got != "http://greet-service:8080" {
+		t.Errorf("BaseURL() = %q, want normalized URL", got)
+	}
+	if _, ok := client.(*fakeGreeter).httpClient.(*HTTPClient); !ok {
+		t.Error("client should use the resilient HTTPClient")
+	}
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	called := false
+	factory := func(connect.HTTPClient, string, ...connect.ClientOption) greeterClient {
+		called = true
+		return nil
+	}
+
+	client, err := New("ftp://greet-service", factory)
+	if err == nil || !strings.Contains(err.Error(), "unsupported scheme") {
+		t.Errorf("New() error = %v, want unsupported scheme", err)
+	}
+	if client != nil || called {
+		t.Error("factory should not be called for an invalid base URL")
+	}
+}
+
+// stringCodec lets Connect clients exchange plain strings in tests.
+type stringCodec struct{}
+
+func (stringCodec) Name() string { return "text" }
+
+func (stringCodec) Marshal(v any) ([]byte, error) { return []byte(*v.(*string)), nil }
+
+func (stringCodec) Unmarshal(data []byte, v any) error {
+	*v.(*string) = string(data)
+	return nil
+}
+
+func TestWithInterceptors_RunOncePerCall(t *testing.T) {
+	var attempts atomic.Int32
+	var tenants sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := attempts.Add(1)
+		tenants.Store(n, r.Header.Get("X-Tenant-Id"))
+		if n == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/text")
+		_, _ = io.WriteString(w, "hello")
+	}))
+	defer server.Close()
+
+	var order []string
+	var calls atomic.Int32
+	interceptor := func(name string) connect.Interceptor {
+		return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+			return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+				order = append(order, name)
+				if name == "outer" {
+					calls.Add(1)
+					req.Header().Set("X-Tenant-Id", "acme")
+				}
+				return next(ctx, req)
+			}
+		})
+	}
+
+	procedure := "/greet.v1.GreetService/Greet"
+	client := NewConnectClient(server.URL, "greet", func(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) *connect.Client[string, string] {
+		return connect.NewClient[string, string](httpClient, baseURL+procedure, append(opts, connect.WithCodec(stringCodec{}))...)
+	},
+		WithRetry(2),
+		WithCircuitBreaker(false),
+		WithIdempotentMethods(procedure),
+		WithInterceptors(interceptor("outer")),
+		WithInterceptors(interceptor("inner")),
+	)
+
+	msg := "world"
+	resp, err := client.CallUnary(context.Background(), connect.NewRequest(&msg))
+	if err != nil {
+		t.Fatalf("CallUnary() error = %v", err)
+	}
+	if *resp.Msg != "hello" {
+		t.Errorf("response = %q, want hello", *resp.Msg)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("interceptor calls = %d, want 1 per logical call", got)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("interceptor order = %v, want [outer inner]", order)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+	for n := int32(1); n <= 2; n++ {
+		if tenant, _ := tenants.Load(n); tenant != "acme" {
+			t.Errorf("attempt %d X-Tenant-Id = %q, want acme", n, tenant)
+		}
+	}
+}
+
+func TestHTTPClient_ConnectOptions(t *testing.T) {
+	if opts := NewHTTPClient("http://greet-service").ConnectOptions(); len(opts) != 0 {
+		t.Errorf("ConnectOptions() = %d options, want none without interceptors", len(opts))
+	}
+
+	var got int
+	factory := func(_ connect.HTTPClient, baseURL string, opts ...connect.ClientOption) greeterClient {
+		got = len(opts)
+		return &fakeGreeter{baseURL: baseURL}
+	}
+	noop := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc { return next })
+	if _, err := New("http://greet-service", factory, WithInterceptors(noop, noop)); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("factory received %d options, want 1 WithInterceptors option", got)
+	}
+}
+
+func TestWithMetrics_CountsRetriesAsAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	client := NewHTTPClient(server.URL,
+		WithCircuitBreaker(false),
+		WithMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	// A POST not marked idempotent fails without retries; the GET is retried twice
+	resp, err := client.Post(server.URL+"/user.v1.UserService/GetUser", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	resp, err = client.Get(server.URL + "/user.v1.UserService/ListUsers")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	counts := make(map[string]int64)
+	var durations uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					method, _ := dp.Attributes.Value("rpc_method")
+					code, _ := dp.Attributes.Value("rpc_code")
+					counts[m.Name+" "+method.AsString()+" "+code.AsString()] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					durations += dp.Count
+				}
+			}
+		}
+	}
+
+	want := map[string]int64{
+		"clientx_requests_total GetUser unavailable":           1,
+		"clientx_request_attempts_total GetUser unavailable":   1,
+		"clientx_requests_total ListUsers ok":                  1,
+		"clientx_request_attempts_total ListUsers unavailable": 1,
+		"clientx_request_attempts_total ListUsers ok":          1,
+	}
+	for key, n := range want {
+		if counts[key] != n {
+			t.Errorf("%s = %d, want %d (all: %v)", key, counts[key], n, counts)
+		}
+	}
+	if durations != 2 {
+		t.Errorf("duration samples = %d, want 2 logical requests", durations)
+	}
+}
+
+func TestHTTPClient_WarmupReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewHTTPClient(server.URL, WithRetry(0))
+
+	if err := client.Warmup(context.Background(), 4); err != nil {
+		t.Fatalf("Warmup() error = %v", err)
+	}
+	if got := conns.Load(); got != 4 {
+		t.Fatalf("Warmup() opened %d connections, want 4", got)
+	}
+
+	for i := 0; i < 8; i++ {
+		resp, err := client.Get(server.URL + "/call")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	if got := conns.Load(); got != 4 {
+		t.Errorf("calls after Warmup() opened %d new connections, want 0", got-4)
+	}
+}
+
+func TestHTTPClient_WarmupRespectsCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := NewHTTPClient(url, WithRetry(0))
+
+	// Trip the breaker: it opens after more than CircuitThreshold (5) consecutive failures
+	for i := 0; i < 6; i++ {
+		if _, err := client.Get(url); err == nil {
+			t.Fatal("Get() against closed server should fail")
+		}
+	}
+
+	err := client.Warmup(context.Background(), 2)
+	if !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("Warmup() error = %v, want gobreaker.ErrOpenState", err)
+	}
+}
+
+func TestHealthAwareClient_TogglesWithUpstreamHealth(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			if !healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHealthAwareClient(server.URL, "health", 10*time.Millisecond, WithRetry(0))
+	defer client.Close()
+
+	if !client.Healthy() {
+		t.Fatalf("Status() = %+v, want healthy after initial probe", client.Status())
+	}
+	resp, err := client.Get(server.URL + "/call")
+	if err != nil {
+		t.Fatalf("Get() while healthy error = %v", err)
+	}
+	resp.Body.Close()
+
+	healthy.Store(false)
+	waitFor(t, func() bool { return !client.Healthy() })
+
+	start := time.Now()
+	_, err = client.Get(server.URL + "/call")
+	if !errors.Is(err, ErrUpstreamUnhealthy) {
+		t.Fatalf("Get() while unhealthy error = %v, want ErrUpstreamUnhealthy", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unhealthy call took %v, want fast failure", elapsed)
+	}
+	if status := client.Status(); status.Err == nil || status.CheckedAt.IsZero() {
+		t.Errorf("Status() = %+v, want probe error and check time", status)
+	}
+
+	healthy.Store(true)
+	waitFor(t, client.Healthy)
+
+	resp, err = client.Get(server.URL + "/call")
+	if err != nil {
+		t.Fatalf("Get() after recovery error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("upstream received %d calls, want 2", got)
+	}
+}
+
+func TestHealthAwareClient_ProbesWithInternalToken(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Internal-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/health" {
+			probes.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewHealthAwareClient(server.URL, "/health", time.Hour,
+		WithInternalToken("secret"), WithCircuitBreaker(true))
+	defer client.Close()
+
+	if !client.Healthy() {
+		t.Fatalf("Status() = %+v, want healthy with the internal token on probes", client.Status())
+	}
+	if got := probes.Load(); got != 1 {
+		t.Errorf("probes = %d, want 1 initial probe", got)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNewBalancedHTTPClient_RetryLandsOnOtherEndpoint(t *testing.T) {
+	var downCalls, upCalls atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downCalls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	client, err := NewBalancedHTTPClient([]string{down.URL, up.URL}, BalanceOptions{},
+		WithRetryPolicy(func(req *http.Request, err error, attempt int) bool { return true }),
+		WithCircuitBreaker(false),
+	)
+	if err != nil {
+		t.Fatalf("NewBalancedHTTPClient() error = %v", err)
+	}
+	if client.BaseURL() != down.URL {
+		t.Errorf("BaseURL() = %q, want first URL %q", client.BaseURL(), down.URL)
+	}
+
+	resp, err := client.Post(client.BaseURL()+"/pkg.Service/Method", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || downCalls.Load() != 1 || upCalls.Load() != 1 {
+		t.Errorf("status = %d, down = %d, up = %d; want the retry on the healthy endpoint",
+			resp.StatusCode, downCalls.Load(), upCalls.Load())
+	}
+
+	if _, err := NewBalancedHTTPClient(nil, BalanceOptions{}); err == nil {
+		t.Error("NewBalancedHTTPClient() with no URLs should fail")
+	}
+	if _, err := NewBalancedHTTPClient([]string{up.URL, "ftp://x"}, BalanceOptions{}); err == nil {
+		t.Error("NewBalancedHTTPClient() with an invalid URL should fail")
+	}
+}
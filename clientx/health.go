@@ -0,0 +1,109 @@
+// Package clientx provides a health-aware client that tracks upstream health checks.
+package clientx
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.eggybyte.com/egg/clientx/internal"
+)
+
+// ErrUpstreamUnhealthy is returned (wrapped) by HealthAwareClient calls made while
+// the upstream health check is failing. Test with errors.Is.
+var ErrUpstreamUnhealthy = internal.ErrUpstreamUnhealthy
+
+// HealthStatus reports the result of the last upstream health probe.
+type HealthStatus struct {
+	Healthy   bool      // Whether the last probe succeeded
+	CheckedAt time.Time // When the last probe completed
+	Err       error     // Probe failure, nil when healthy
+}
+
+// HealthAwareClient is an HTTP client whose availability follows the upstream's
+// health endpoint. While the last probe failed, calls fail fast with
+// ErrUpstreamUnhealthy instead of waiting on timeouts and retries.
+//
+// It embeds *http.Client, so it can be passed wherever a connect.HTTPClient is expected.
+type HealthAwareClient struct {
+	*http.Client
+	monitor *internal.HealthMonitor
+}
+
+// NewHealthAwareClient creates a resilient client (see NewHTTPClient) gated by
+// periodic probes of baseURL+healthPath. Any 2xx response counts as healthy.
+// The first probe runs synchronously, so the status is known on return.
+// Call Close to stop probing.
+//
+// Probes share the client's connection pool and send its internal token and
+// propagated headers, so they pass the same authentication as calls. They
+// skip retries and circuit breaking, so the status reflects the upstream as
+// it is right now.
+//
+// Parameters:
+//   - baseURL: base URL of the upstream service
+//   - healthPath: health endpoint path, e.g. "/health"
+//   - checkInterval: time between probes (default: 10s if <= 0)
+//   - opts: client options as for NewHTTPClient
+//
+// Returns:
+//   - *HealthAwareClient: client with background health probing started
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Example:
+//
+//	client := clientx.NewHealthAwareClient("http://user-service:8080", "/health", 5*time.Second)
+//	defer client.Close()
+//	userClient := userv1connect.NewUserServiceClient(client, "http://user-service:8080")
+func NewHealthAwareClient(baseURL, healthPath string, checkInterval time.Duration, opts ...Option) *HealthAwareClient {
+	if checkInterval <= 0 {
+		checkInterval = 10 * time.Second
+	}
+
+	client := NewHTTPClient(baseURL, opts...)
+
+	healthURL := baseURL
+	if normalized, err := internal.NormalizeBaseURL(baseURL); err == nil {
+		healthURL = normalized
+	}
+	if healthPath != "" && !strings.HasPrefix(healthPath, "/") {
+		healthPath = "/" + healthPath
+	}
+	healthURL += healthPath
+
+	probe := client.probe
+	if probe == nil {
+		// Invalid baseURL: the probe fails like every other request
+		probe = client.Transport
+	}
+	monitor := internal.NewHealthMonitor(healthURL, checkInterval, probe)
+	monitor.Start()
+
+	client.Transport = &internal.HealthGateTransport{Base: client.Transport, Monitor: monitor}
+
+	return &HealthAwareClient{Client: client.Client, monitor: monitor}
+}
+
+// Do sends req like http.Client.Do, applying a timeout set on the request
+// context with WithCallTimeout in place of the client timeout.
+func (c *HealthAwareClient) Do(req *http.Request) (*http.Response, error) {
+	return doCall(c.Client, req)
+}
+
+// Healthy reports whether the last health probe succeeded.
+func (c *HealthAwareClient) Healthy() bool {
+	return c.monitor.State().Healthy
+}
+
+// Status returns the result of the last health probe.
+func (c *HealthAwareClient) Status() HealthStatus {
+	state := c.monitor.State()
+	return HealthStatus{Healthy: state.Healthy, CheckedAt: state.CheckedAt, Err: state.Err}
+}
+
+// Close stops background health probing.
+func (c *HealthAwareClient) Close() {
+	c.monitor.Stop()
+}
@@ -0,0 +1,266 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BalanceStrategy selects the endpoint of each request.
+type BalanceStrategy int
+
+const (
+	// BalanceRoundRobin sends requests to endpoints in turn.
+	BalanceRoundRobin BalanceStrategy = iota
+	// BalanceLeastPending sends requests to the endpoint with the fewest
+	// requests in flight, in turn among ties.
+	BalanceLeastPending
+)
+
+// BalanceConfig configures a BalanceTransport.
+type BalanceConfig struct {
+	Strategy    BalanceStrategy
+	MaxFailures int           // Consecutive failures that eject an endpoint (default: 3)
+	Cooldown    time.Duration // Time an ejected endpoint is skipped (default: 30s)
+	HealthPath  string        // Probed before re-admission (empty = re-admit on a trial request)
+}
+
+// endpointState is the admission state of an endpoint.
+type endpointState int
+
+const (
+	endpointHealthy endpointState = iota
+	endpointEjected               // Skipped until ejectedUntil
+	endpointProbing               // Skipped while a health probe runs
+	endpointTrial                 // Eligible; the next outcome re-admits or ejects it
+)
+
+// endpoint is one base URL of a BalanceTransport.
+type endpoint struct {
+	url     *url.URL
+	pending atomic.Int64
+
+	mu           sync.Mutex
+	state        endpointState
+	failures     int
+	ejectedUntil time.Time
+}
+
+// BalanceTransport distributes requests across equivalent endpoints.
+//
+// Requests are made against the first endpoint's base URL and rewritten to
+// the selected endpoint, so retries above the transport can land on another
+// endpoint. Transport errors and 502, 503 and 504 responses count as failures;
+// MaxFailures consecutive failures eject an endpoint for Cooldown. After the
+// cooldown the endpoint is probed at HealthPath and re-admitted once the probe
+// returns 2xx; without a HealthPath, the next request is a trial that
+// re-admits or ejects it again. If every endpoint is ejected, requests are
+// spread over all of them rather than failing outright.
+type BalanceTransport struct {
+	base      http.RoundTripper
+	endpoints []*endpoint
+	config    BalanceConfig
+	probe     *http.Client
+	clock     Clock
+
+	next atomic.Uint64
+}
+
+// NewBalanceTransport creates a balancer over normalized base URLs; the first
+// one is the base URL requests are made against.
+func NewBalanceTransport(base http.RoundTripper, baseURLs []string, config BalanceConfig) (*BalanceTransport, error) {
+	if len(baseURLs) == 0 {
+		return nil, fmt.Errorf("at least one base URL is required")
+	}
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = 3
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	if config.HealthPath != "" && !strings.HasPrefix(config.HealthPath, "/") {
+		config.HealthPath = "/" + config.HealthPath
+	}
+
+	endpoints := make([]*endpoint, len(baseURLs))
+	for i, raw := range baseURLs {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL %q: %w", raw, err)
+		}
+		endpoints[i] = &endpoint{url: u}
+	}
+
+	return &BalanceTransport{
+		base:      base,
+		endpoints: endpoints,
+		config:    config,
+		probe:     &http.Client{Timeout: maxProbeTimeout, Transport: base},
+		clock:     realClock{},
+	}, nil
+}
+
+// WithClock sets the clock used for cooldowns.
+func (t *BalanceTransport) WithClock(c Clock) *BalanceTransport {
+	t.clock = c
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BalanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	virtual := t.endpoints[0].url
+	if req.URL.Scheme != virtual.Scheme || req.URL.Host != virtual.Host || !strings.HasPrefix(req.URL.Path, virtual.Path) {
+		// Not addressed to the balanced service
+		return t.base.RoundTrip(req)
+	}
+
+	ep := t.pick()
+	out := *req
+	u := *req.URL
+	u.Scheme, u.Host = ep.url.Scheme, ep.url.Host
+	u.Path = ep.url.Path + strings.TrimPrefix(req.URL.Path, virtual.Path)
+	u.RawPath = ""
+	out.URL = &u
+	if req.Host == virtual.Host {
+		out.Host = ""
+	}
+
+	ep.pending.Add(1)
+	release := sync.OnceFunc(func() { ep.pending.Add(-1) })
+
+	resp, err := t.base.RoundTrip(&out)
+	if req.Context().Err() == nil {
+		// A cancelled caller says nothing about the endpoint
+		t.record(ep, err != nil || isUnavailableStatus(resp.StatusCode))
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: bodyOrEmpty(resp.Body), cancel: release}
+	return resp, nil
+}
+
+// bodyOrEmpty returns body, or http.NoBody if it is nil.
+func bodyOrEmpty(body io.ReadCloser) io.ReadCloser {
+	if body == nil {
+		return http.NoBody
+	}
+	return body
+}
+
+// isUnavailableStatus reports whether a response status means the endpoint
+// could not serve the request.
+func isUnavailableStatus(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// pick selects an endpoint for the next request.
+func (t *BalanceTransport) pick() *endpoint {
+	n := len(t.endpoints)
+	start := int(t.next.Add(1)-1) % n
+
+	var chosen *endpoint
+	for i := 0; i < n; i++ {
+		ep := t.endpoints[(start+i)%n]
+		if !t.eligible(ep) {
+			continue
+		}
+		if t.config.Strategy != BalanceLeastPending {
+			return ep
+		}
+		if chosen == nil || ep.pending.Load() < chosen.pending.Load() {
+			chosen = ep
+		}
+	}
+	if chosen == nil {
+		// Every endpoint is ejected: fail open
+		chosen = t.endpoints[start]
+	}
+	return chosen
+}
+
+// eligible reports whether ep may receive a request, moving it out of
+// ejection once its cooldown has passed.
+func (t *BalanceTransport) eligible(ep *endpoint) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	switch ep.state {
+	case endpointHealthy, endpointTrial:
+		return true
+	case endpointEjected:
+		if t.clock.Now().Before(ep.ejectedUntil) {
+			return false
+		}
+		if t.config.HealthPath == "" {
+			ep.state = endpointTrial
+			return true
+		}
+		ep.state = endpointProbing
+		go t.probeEndpoint(ep)
+	}
+	return false
+}
+
+// probeEndpoint re-admits ep if its health endpoint returns 2xx and ejects
+// it for another cooldown otherwise.
+func (t *BalanceTransport) probeEndpoint(ep *endpoint) {
+	healthy := false
+	resp, err := t.probe.Get(ep.url.String() + t.config.HealthPath)
+	if err == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorPeek))
+		resp.Body.Close()
+		healthy = resp.StatusCode >= 200 && resp.StatusCode < 300
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if healthy {
+		ep.state, ep.failures = endpointHealthy, 0
+		return
+	}
+	ep.state, ep.ejectedUntil = endpointEjected, t.clock.Now().Add(t.config.Cooldown)
+}
+
+// record updates the failure count of ep with the outcome of a request.
+func (t *BalanceTransport) record(ep *endpoint, failed bool) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if !failed {
+		if ep.state == endpointTrial {
+			ep.state = endpointHealthy
+		}
+		ep.failures = 0
+		return
+	}
+
+	ep.failures++
+	if ep.state == endpointTrial || (ep.state == endpointHealthy && ep.failures >= t.config.MaxFailures) {
+		ep.state, ep.ejectedUntil = endpointEjected, t.clock.Now().Add(t.config.Cooldown)
+	}
+}
+
+// Ejected returns the base URLs currently ejected or being probed.
+func (t *BalanceTransport) Ejected() []string {
+	var ejected []string
+	for _, ep := range t.endpoints {
+		ep.mu.Lock()
+		if ep.state == endpointEjected || ep.state == endpointProbing {
+			ejected = append(ejected, ep.url.String())
+		}
+		ep.mu.Unlock()
+	}
+	return ejected
+}
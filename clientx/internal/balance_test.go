@@ -0,0 +1,188 @@
+// Package internal provides tests for clientx load balancing.
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hostCounter answers requests per host with the status in statuses
+// (default 200) and counts the requests each host received.
+type hostCounter struct {
+	mu       sync.Mutex
+	statuses map[string]int
+	counts   map[string]int
+	paths    []string
+}
+
+func (h *hostCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[req.URL.Host]++
+	h.paths = append(h.paths, req.URL.Path)
+	status := http.StatusOK
+	if s, ok := h.statuses[req.URL.Host]; ok {
+		status = s
+	}
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+}
+
+func (h *hostCounter) set(host string, status int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.statuses[host] = status
+}
+
+func (h *hostCounter) count(host string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.counts[host]
+}
+
+func newHostCounter() *hostCounter {
+	return &hostCounter{statuses: make(map[string]int), counts: make(map[string]int)}
+}
+
+func sendN(t *testing.T, transport http.RoundTripper, url string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		req, _ := http.NewRequest(http.MethodPost, url, nil)
+		resp, err := transport.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func TestBalanceTransport_RoundRobin(t *testing.T) {
+	hosts := newHostCounter()
+	balancer, err := NewBalanceTransport(hosts, []string{"http://a:8080/api", "http://b:8080"}, BalanceConfig{})
+	if err != nil {
+		t.Fatalf("NewBalanceTransport() error = %v", err)
+	}
+
+	sendN(t, balancer, "http://a:8080/api/pkg.Service/Method", 4)
+
+	if hosts.count("a:8080") != 2 || hosts.count("b:8080") != 2 {
+		t.Errorf("counts = %v, want 2 per endpoint", hosts.counts)
+	}
+	for _, path := range hosts.paths {
+		if path != "/api/pkg.Service/Method" && path != "/pkg.Service/Method" {
+			t.Errorf("rewritten path = %q", path)
+		}
+	}
+
+	// Requests to other hosts are not balanced
+	sendN(t, balancer, "http://other:8080/pkg.Service/Method", 1)
+	if hosts.count("other:8080") != 1 {
+		t.Error("request to another host should pass through")
+	}
+}
+
+func TestBalanceTransport_LeastPending(t *testing.T) {
+	hosts := newHostCounter()
+	balancer, _ := NewBalanceTransport(hosts, []string{"http://a", "http://b"}, BalanceConfig{Strategy: BalanceLeastPending})
+
+	// Keep one response open on the first endpoint picked
+	req, _ := http.NewRequest(http.MethodPost, "http://a/pkg.Service/Method", nil)
+	held, err := balancer.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendN(t, balancer, "http://a/pkg.Service/Method", 4)
+	if hosts.count("a") != 1 || hosts.count("b") != 4 {
+		t.Errorf("counts = %v, want the idle endpoint to take every request", hosts.counts)
+	}
+
+	held.Body.Close()
+	sendN(t, balancer, "http://a/pkg.Service/Method", 2)
+	if hosts.count("a") != 2 {
+		t.Errorf("counts = %v, want requests back on the released endpoint", hosts.counts)
+	}
+}
+
+func TestBalanceTransport_EjectAndTrial(t *testing.T) {
+	hosts := newHostCounter()
+	hosts.set("b", 0) // Transport error
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	balancer, _ := NewBalanceTransport(hosts, []string{"http://a", "http://b"}, BalanceConfig{MaxFailures: 2, Cooldown: time.Minute})
+	balancer.WithClock(clock)
+
+	sendN(t, balancer, "http://a/x", 4)
+	if got := balancer.Ejected(); len(got) != 1 || got[0] != "http://b" {
+		t.Fatalf("Ejected() = %v, want [http://b]", got)
+	}
+
+	sendN(t, balancer, "http://a/x", 4)
+	if hosts.count("b") != 2 {
+		t.Errorf("ejected endpoint received %d requests, want 2", hosts.count("b"))
+	}
+
+	// After the cooldown a failed trial ejects again at once
+	clock.Advance(time.Minute)
+	sendN(t, balancer, "http://a/x", 4)
+	if hosts.count("b") != 3 || len(balancer.Ejected()) != 1 {
+		t.Errorf("trial: b requests = %d, ejected = %v", hosts.count("b"), balancer.Ejected())
+	}
+
+	// A successful trial re-admits
+	hosts.set("b", http.StatusOK)
+	clock.Advance(time.Minute)
+	sendN(t, balancer, "http://a/x", 4)
+	if len(balancer.Ejected()) != 0 || hosts.count("b") != 5 {
+		t.Errorf("re-admission: b requests = %d, ejected = %v", hosts.count("b"), balancer.Ejected())
+	}
+}
+
+func TestBalanceTransport_HealthProbe(t *testing.T) {
+	hosts := newHostCounter()
+	hosts.set("b", http.StatusServiceUnavailable)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	balancer, _ := NewBalanceTransport(hosts, []string{"http://a", "http://b"}, BalanceConfig{MaxFailures: 1, Cooldown: time.Minute, HealthPath: "health"})
+	balancer.WithClock(clock)
+
+	sendN(t, balancer, "http://a/x", 2)
+	if len(balancer.Ejected()) != 1 {
+		t.Fatalf("Ejected() = %v, want b", balancer.Ejected())
+	}
+
+	hosts.set("b", http.StatusOK)
+	clock.Advance(time.Minute)
+	sendN(t, balancer, "http://a/x", 2) // b's turn starts the probe
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(balancer.Ejected()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(balancer.Ejected()) != 0 {
+		t.Fatal("endpoint should be re-admitted after a healthy probe")
+	}
+
+	hosts.mu.Lock()
+	defer hosts.mu.Unlock()
+	found := false
+	for _, path := range hosts.paths {
+		found = found || path == "/health"
+	}
+	if !found {
+		t.Errorf("paths = %v, want a /health probe", hosts.paths)
+	}
+}
+
+func TestBalanceTransport_AllEjectedFailsOpen(t *testing.T) {
+	hosts := newHostCounter()
+	hosts.set("a", http.StatusServiceUnavailable)
+	balancer, _ := NewBalanceTransport(hosts, []string{"http://a"}, BalanceConfig{MaxFailures: 1})
+
+	sendN(t, balancer, "http://a/x", 3)
+	if hosts.count("a") != 3 {
+		t.Errorf("a requests = %d, want 3 (fail open)", hosts.count("a"))
+	}
+}
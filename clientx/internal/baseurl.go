@@ -0,0 +1,70 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURLScheme is prepended to base URLs that do not specify a scheme.
+const DefaultBaseURLScheme = "http"
+
+// NormalizeBaseURL validates a client base URL and returns its canonical form.
+//
+// A missing scheme defaults to http, and trailing slashes are stripped so that
+// Connect procedure paths ("/pkg.Service/Method") join cleanly. Only http and
+// https are accepted; the URL must have a host and must not carry a query or
+// fragment.
+func NormalizeBaseURL(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", fmt.Errorf("invalid base URL %q: empty", raw)
+	}
+
+	if !strings.Contains(trimmed, "://") {
+		trimmed = DefaultBaseURLScheme + "://" + trimmed
+	}
+
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", raw, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("invalid base URL %q: unsupported scheme %q (want http or https)", raw, u.Scheme)
+	}
+
+	if u.Host == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", raw)
+	}
+	if u.RawQuery != "" || u.ForceQuery {
+		return "", fmt.Errorf("invalid base URL %q: query is not allowed", raw)
+	}
+	if u.Fragment != "" {
+		return "", fmt.Errorf("invalid base URL %q: fragment is not allowed", raw)
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = strings.TrimRight(u.RawPath, "/")
+
+	return u.String(), nil
+}
+
+// ErrorTransport is an http.RoundTripper that fails every request with a fixed error.
+// It is used when a client is constructed with an invalid base URL so that calls
+// fail immediately with the validation error instead of a confusing network error.
+type ErrorTransport struct {
+	Err error
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.Err
+}
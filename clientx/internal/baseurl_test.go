@@ -0,0 +1,84 @@
+// Package internal provides tests for clientx base URL normalization.
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeBaseURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"already canonical", "https://api.example.com", "https://api.example.com"},
+		{"trailing slash", "https://api.example.com/", "https://api.example.com"},
+		{"multiple trailing slashes", "http://api.example.com///", "http://api.example.com"},
+		{"path prefix with trailing slash", "https://api.example.com/rpc/", "https://api.example.com/rpc"},
+		{"missing scheme", "api.example.com", "http://api.example.com"},
+		{"missing scheme with port", "localhost:8080", "http://localhost:8080"},
+		{"missing scheme with trailing slash", "user-service:8080/", "http://user-service:8080"},
+		{"surrounding whitespace", "  http://api.example.com/ ", "http://api.example.com"},
+		{"uppercase scheme", "HTTPS://api.example.com", "https://api.example.com"},
+		{"ipv6 host", "http://[::1]:8080/", "http://[::1]:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeBaseURL(tt.in)
+			if err != nil {
+				t.Fatalf("NormalizeBaseURL(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeBaseURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBaseURL_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{"empty", "", "empty"},
+		{"whitespace only", "   ", "empty"},
+		{"unsupported scheme", "ftp://files.example.com", "unsupported scheme"},
+		{"missing host", "http://", "missing host"},
+		{"missing host with path", "http:///rpc", "missing host"},
+		{"port only", "http://:8080", "missing host"},
+		{"query", "http://api.example.com?x=1", "query is not allowed"},
+		{"fragment", "http://api.example.com#frag", "fragment is not allowed"},
+		{"invalid escape", "http://api.example.com/%zz", "invalid base URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NormalizeBaseURL(tt.in)
+			if err == nil {
+				t.Fatalf("NormalizeBaseURL(%q) should return error", tt.in)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NormalizeBaseURL(%q) error = %q, want it to contain %q", tt.in, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorTransport(t *testing.T) {
+	want := errors.New("invalid base URL")
+	transport := &ErrorTransport{Err: want}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := transport.RoundTrip(req)
+	if resp != nil {
+		t.Error("RoundTrip() should not return a response")
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, want)
+	}
+}
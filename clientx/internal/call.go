@@ -0,0 +1,36 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"time"
+)
+
+// callTimeoutKey and noRetryKey mark per-call overrides in a request context.
+type (
+	callTimeoutKey struct{}
+	noRetryKey     struct{}
+)
+
+// WithCallTimeout returns a context whose calls use timeout d in place of
+// the client timeout.
+func WithCallTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, d)
+}
+
+// CallTimeout returns the per-call timeout set with WithCallTimeout.
+func CallTimeout(ctx context.Context) (time.Duration, bool) {
+	d, ok := ctx.Value(callTimeoutKey{}).(time.Duration)
+	return d, ok
+}
+
+// WithNoRetry returns a context whose calls are attempted once.
+func WithNoRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// NoRetry reports whether retries are disabled for calls made with ctx.
+func NoRetry(ctx context.Context) bool {
+	noRetry, _ := ctx.Value(noRetryKey{}).(bool)
+	return noRetry
+}
@@ -0,0 +1,138 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUpstreamUnhealthy is returned for calls made while the upstream health check is failing.
+var ErrUpstreamUnhealthy = errors.New("upstream unhealthy")
+
+// maxProbeTimeout caps the timeout of a single health probe.
+const maxProbeTimeout = 5 * time.Second
+
+// HealthState is a snapshot of the last health probe.
+type HealthState struct {
+	Healthy   bool
+	CheckedAt time.Time
+	Err       error
+}
+
+// HealthMonitor periodically probes an upstream health endpoint.
+type HealthMonitor struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+
+	mu    sync.RWMutex
+	state HealthState
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewHealthMonitor creates a monitor probing url every interval.
+// Probes are sent through base as they are; base should carry the client's
+// authentication but no retries or circuit breaking, so the state reflects
+// the upstream as it is right now.
+func NewHealthMonitor(url string, interval time.Duration, base http.RoundTripper) *HealthMonitor {
+	timeout := interval
+	if timeout <= 0 || timeout > maxProbeTimeout {
+		timeout = maxProbeTimeout
+	}
+	return &HealthMonitor{
+		url:      url,
+		interval: interval,
+		client:   &http.Client{Timeout: timeout, Transport: base},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start performs an initial probe synchronously, then probes in the background
+// until Stop is called.
+func (m *HealthMonitor) Start() {
+	m.Probe(context.Background())
+
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.Probe(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop stops background probing and waits for the probe loop to exit.
+// Safe to call multiple times; must only be called after Start.
+func (m *HealthMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	<-m.done
+}
+
+// Probe checks the health endpoint once and records the result.
+// Any 2xx response is healthy.
+func (m *HealthMonitor) Probe(ctx context.Context) HealthState {
+	state := HealthState{CheckedAt: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url, nil)
+	if err == nil {
+		var resp *http.Response
+		resp, err = m.client.Do(req)
+		if err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				err = fmt.Errorf("health check returned status %d", resp.StatusCode)
+			}
+		}
+	}
+
+	state.Healthy = err == nil
+	state.Err = err
+
+	m.mu.Lock()
+	m.state = state
+	m.mu.Unlock()
+	return state
+}
+
+// State returns the result of the last probe.
+func (m *HealthMonitor) State() HealthState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state
+}
+
+// HealthGateTransport fails requests fast while the monitor reports unhealthy.
+type HealthGateTransport struct {
+	Base    http.RoundTripper
+	Monitor *HealthMonitor
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *HealthGateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if state := t.Monitor.State(); !state.Healthy {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamUnhealthy, state.Err)
+	}
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,123 @@
+// Package internal provides tests for clientx upstream health monitoring.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitor_Probe(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewHealthMonitor(server.URL+"/health", time.Hour, http.DefaultTransport)
+
+	if state := m.Probe(context.Background()); !state.Healthy || state.Err != nil {
+		t.Fatalf("Probe() = %+v, want healthy", state)
+	}
+
+	healthy.Store(false)
+	state := m.Probe(context.Background())
+	if state.Healthy || state.Err == nil {
+		t.Fatalf("Probe() = %+v, want unhealthy with error", state)
+	}
+	if got := m.State(); got.Healthy || got.CheckedAt.IsZero() {
+		t.Errorf("State() = %+v, want last unhealthy probe", got)
+	}
+}
+
+func TestHealthMonitor_UnreachableIsUnhealthy(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	m := NewHealthMonitor(url+"/health", time.Hour, http.DefaultTransport)
+	if state := m.Probe(context.Background()); state.Healthy {
+		t.Error("Probe() of closed server should be unhealthy")
+	}
+}
+
+func TestHealthMonitor_BackgroundProbing(t *testing.T) {
+	var probes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewHealthMonitor(server.URL, 10*time.Millisecond, http.DefaultTransport)
+	m.Start()
+	if probes.Load() != 1 {
+		t.Errorf("Start() should probe synchronously once, got %d probes", probes.Load())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for probes.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	m.Stop()
+	m.Stop()
+
+	if probes.Load() < 3 {
+		t.Fatalf("expected background probes, got %d", probes.Load())
+	}
+	after := probes.Load()
+	time.Sleep(50 * time.Millisecond)
+	if probes.Load() != after {
+		t.Error("probing continued after Stop()")
+	}
+}
+
+func TestHealthGateTransport(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			if !healthy.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := NewHealthMonitor(server.URL+"/health", time.Hour, http.DefaultTransport)
+	client := &http.Client{Transport: &HealthGateTransport{Base: http.DefaultTransport, Monitor: m}}
+
+	m.Probe(context.Background())
+	_, err := client.Get(server.URL + "/call")
+	if !errors.Is(err, ErrUpstreamUnhealthy) {
+		t.Fatalf("Get() error = %v, want ErrUpstreamUnhealthy", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("unhealthy upstream received %d calls, want 0", calls.Load())
+	}
+
+	healthy.Store(true)
+	m.Probe(context.Background())
+	resp, err := client.Get(server.URL + "/call")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("healthy upstream received %d calls, want 1", calls.Load())
+	}
+}
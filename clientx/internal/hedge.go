@@ -0,0 +1,166 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// HedgeTransport sends backup copies of slow idempotent requests.
+//
+// If a request has not completed after delay, another copy is sent, up to
+// maxHedges copies at delay intervals. The first response to arrive wins and
+// the other copies are cancelled. Transport errors do not win: the remaining
+// copies keep running and the error is returned only once every copy failed.
+type HedgeTransport struct {
+	base       http.RoundTripper
+	delay      time.Duration
+	maxHedges  int
+	idempotent func(*http.Request) bool
+
+	fired metric.Int64Counter
+	wins  metric.Int64Counter
+}
+
+// NewHedgeTransport creates a hedging transport. Only requests for which
+// idempotent returns true are hedged. meterProvider records hedge fires and
+// wins; if nil, no metrics are recorded.
+func NewHedgeTransport(base http.RoundTripper, delay time.Duration, maxHedges int, idempotent func(*http.Request) bool, meterProvider metric.MeterProvider) (*HedgeTransport, error) {
+	if meterProvider == nil {
+		meterProvider = noop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter("go.eggybyte.com/egg/clientx")
+
+	fired, err := meter.Int64Counter(
+		"rpc_client_hedges_total",
+		metric.WithDescription("Total number of hedged requests sent"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	wins, err := meter.Int64Counter(
+		"rpc_client_hedge_wins_total",
+		metric.WithDescription("Total number of hedged requests that returned before the original"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HedgeTransport{
+		base:       base,
+		delay:      delay,
+		maxHedges:  maxHedges,
+		idempotent: idempotent,
+		fired:      fired,
+		wins:       wins,
+	}, nil
+}
+
+// hedgeResult is the outcome of one copy of a hedged request.
+type hedgeResult struct {
+	resp   *http.Response
+	err    error
+	index  int
+	cancel context.CancelFunc
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *HedgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A body that cannot be replayed can only be sent once
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if t.maxHedges <= 0 || t.delay <= 0 || !replayable || !t.idempotent(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	results := make(chan hedgeResult, t.maxHedges+1)
+	cancels := make([]context.CancelFunc, 0, t.maxHedges+1)
+
+	send := func(index int) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		r := req.Clone(ctx)
+		if index > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				results <- hedgeResult{err: err, index: index, cancel: cancel}
+				return
+			}
+			r.Body = body
+		}
+		go func() {
+			resp, err := t.base.RoundTrip(r)
+			results <- hedgeResult{resp: resp, err: err, index: index, cancel: cancel}
+		}()
+	}
+
+	send(0)
+	sent, inFlight := 1, 1
+
+	timer := time.NewTimer(t.delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if sent > t.maxHedges || !t.hasTime(req.Context()) {
+				continue
+			}
+			send(sent)
+			sent++
+			inFlight++
+			t.fired.Add(req.Context(), 1)
+			timer.Reset(t.delay)
+
+		case r := <-results:
+			inFlight--
+			if r.err != nil {
+				r.cancel()
+				if inFlight == 0 {
+					return nil, r.err
+				}
+				continue
+			}
+
+			if r.index > 0 {
+				t.wins.Add(req.Context(), 1)
+			}
+			for i, cancel := range cancels {
+				if i != r.index {
+					cancel()
+				}
+			}
+			go discardHedges(results, inFlight)
+
+			r.resp.Body = &cancelOnClose{ReadCloser: r.resp.Body, cancel: r.cancel}
+			return r.resp, nil
+		}
+	}
+}
+
+// hasTime reports whether a hedge sent now could get at least delay before the
+// request deadline. A hedge with less time than the original already waited
+// is unlikely to win and only adds load.
+func (t *HedgeTransport) hasTime(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > t.delay
+}
+
+// discardHedges closes the responses of the n cancelled copies still in flight.
+func discardHedges(results <-chan hedgeResult, n int) {
+	for ; n > 0; n-- {
+		r := <-results
+		if r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+}
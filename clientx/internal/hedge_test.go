@@ -0,0 +1,163 @@
+// Package internal provides tests for clientx request hedging.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// hedgeCounts collects the hedge counters recorded by reader.
+func hedgeCounts(t *testing.T, reader *sdkmetric.ManualReader) (fired, wins int64) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				switch m.Name {
+				case "rpc_client_hedges_total":
+					fired += dp.Value
+				case "rpc_client_hedge_wins_total":
+					wins += dp.Value
+				}
+			}
+		}
+	}
+	return fired, wins
+}
+
+// slowFirst answers every copy but the first immediately; the first hangs
+// until cancelled, which is recorded in cancelled.
+func slowFirst(calls *atomic.Int32, cancelled chan<- struct{}) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			<-req.Context().Done()
+			close(cancelled)
+			return nil, req.Context().Err()
+		}
+		body, _ := io.ReadAll(req.Body)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(append([]byte("hedge:"), body...)))}, nil
+	})
+}
+
+func newTestHedgeTransport(t *testing.T, base http.RoundTripper, delay time.Duration) (*HedgeTransport, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	transport, err := NewHedgeTransport(base, delay, 1,
+		NewIdempotencyCheck(DefaultIdempotencyHeader, []string{"/user.v1.UserService/GetUser"}),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("NewHedgeTransport() error = %v", err)
+	}
+	return transport, reader
+}
+
+func TestHedgeTransport_HedgeWinsAndCancelsOriginal(t *testing.T) {
+	var calls atomic.Int32
+	cancelled := make(chan struct{})
+	transport, reader := newTestHedgeTransport(t, slowFirst(&calls, cancelled), 20*time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://upstream.test/user.v1.UserService/GetUser", strings.NewReader(`{"id":"1"}`))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `hedge:{"id":"1"}` {
+		t.Errorf("body = %q, want the hedge's response with the replayed request body", body)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("original request was not cancelled after the hedge won")
+	}
+	if fired, wins := hedgeCounts(t, reader); fired != 1 || wins != 1 {
+		t.Errorf("hedges fired/won = %d/%d, want 1/1", fired, wins)
+	}
+}
+
+func TestHedgeTransport_FastResponseNotHedged(t *testing.T) {
+	var calls atomic.Int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport, reader := newTestHedgeTransport(t, base, 50*time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+	if fired, _ := hedgeCounts(t, reader); fired != 0 {
+		t.Errorf("hedges fired = %d, want 0", fired)
+	}
+}
+
+func TestHedgeTransport_NonIdempotentNotHedged(t *testing.T) {
+	var calls atomic.Int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	transport, _ := newTestHedgeTransport(t, base, 5*time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://upstream.test/user.v1.UserService/CreateUser", strings.NewReader("{}"))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1 for a non-idempotent request", got)
+	}
+}
+
+func TestHedgeTransport_RespectsDeadline(t *testing.T) {
+	var calls atomic.Int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	transport, reader := newTestHedgeTransport(t, base, 40*time.Millisecond)
+
+	// After the 40ms delay only ~20ms are left, less than the delay itself
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://upstream.test/", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("RoundTrip() error = nil, want deadline exceeded")
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want no hedge without enough time left", got)
+	}
+	if fired, _ := hedgeCounts(t, reader); fired != 0 {
+		t.Errorf("hedges fired = %d, want 0", fired)
+	}
+}
@@ -0,0 +1,129 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ClientMetrics holds the instruments recording outbound calls.
+//
+// A logical request is one call made by the application, however many
+// attempts retries, hedges and token refreshes make for it; each attempt of
+// the retry loop is counted separately.
+type ClientMetrics struct {
+	requests metric.Int64Counter
+	attempts metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// NewClientMetrics creates the outbound call instruments from meterProvider.
+func NewClientMetrics(meterProvider metric.MeterProvider) (*ClientMetrics, error) {
+	meter := meterProvider.Meter("go.eggybyte.com/egg/clientx")
+
+	requests, err := meter.Int64Counter(
+		"clientx_requests_total",
+		metric.WithDescription("Total number of outbound requests, excluding retries"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := meter.Int64Counter(
+		"clientx_request_attempts_total",
+		metric.WithDescription("Total number of outbound request attempts, including retries"),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram(
+		"clientx_request_duration_seconds",
+		metric.WithDescription("Outbound request duration in seconds, including retries and backoff"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 10,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientMetrics{requests: requests, attempts: attempts, duration: duration}, nil
+}
+
+// RequestTransport records every request through base as one logical request.
+func (m *ClientMetrics) RequestTransport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := base.RoundTrip(req)
+		attrs := metric.WithAttributes(callAttributes(req, resp, err)...)
+		m.requests.Add(req.Context(), 1, attrs)
+		m.duration.Record(req.Context(), time.Since(start).Seconds(), attrs)
+		return resp, err
+	})
+}
+
+// AttemptTransport records every request through base as one attempt.
+func (m *ClientMetrics) AttemptTransport(base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := base.RoundTrip(req)
+		m.attempts.Add(req.Context(), 1, metric.WithAttributes(callAttributes(req, resp, err)...))
+		return resp, err
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// callAttributes labels a call by target service, method and outcome.
+func callAttributes(req *http.Request, resp *http.Response, err error) []attribute.KeyValue {
+	service, method := callTarget(req)
+	return []attribute.KeyValue{
+		attribute.String("rpc_service", service),
+		attribute.String("rpc_method", method),
+		attribute.String("rpc_code", callCode(resp, err)),
+	}
+}
+
+// callTarget returns the service and method of a Connect procedure path
+// ("/pkg.Service/Method"). Other requests are labelled by host and HTTP
+// method, keeping the label set bounded for arbitrary paths.
+func callTarget(req *http.Request) (service, method string) {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	if i := strings.LastIndex(path, "/"); i > 0 && strings.Contains(path[:i], ".") && !strings.Contains(path[:i], "/") {
+		return path[:i], path[i+1:]
+	}
+	return req.URL.Host, req.Method
+}
+
+// callCode returns the Connect code describing the outcome of a call.
+func callCode(resp *http.Response, err error) string {
+	switch {
+	case err == nil:
+		if failure := responseError(resp); failure != nil {
+			return connect.CodeOf(failure).String()
+		}
+		return "ok"
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.CodeDeadlineExceeded.String()
+	case errors.Is(err, context.Canceled):
+		return connect.CodeCanceled.String()
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests), IsTransient(err):
+		return connect.CodeUnavailable.String()
+	default:
+		return connect.CodeOf(err).String()
+	}
+}
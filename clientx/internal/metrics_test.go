@@ -0,0 +1,63 @@
+// Package internal provides tests for clientx request metrics.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestCallTarget(t *testing.T) {
+	tests := []struct {
+		url, method         string
+		wantService, wantOp string
+	}{
+		{"http://user-service:8080/user.v1.UserService/GetUser", http.MethodPost, "user.v1.UserService", "GetUser"},
+		{"http://gateway/api/user.v1.UserService/GetUser", http.MethodPost, "gateway", http.MethodPost},
+		{"http://user-service:8080/users/42", http.MethodGet, "user-service:8080", http.MethodGet},
+		{"http://user-service:8080/", http.MethodGet, "user-service:8080", http.MethodGet},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, tt.url, nil)
+		service, method := callTarget(req)
+		if service != tt.wantService || method != tt.wantOp {
+			t.Errorf("callTarget(%s %s) = %q, %q; want %q, %q", tt.method, tt.url, service, method, tt.wantService, tt.wantOp)
+		}
+	}
+}
+
+func TestCallCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   string
+	}{
+		{"success", http.StatusOK, nil, "ok"},
+		{"not found", http.StatusNotFound, nil, "not_found"},
+		{"unavailable", http.StatusServiceUnavailable, nil, "unavailable"},
+		{"deadline", 0, fmt.Errorf("attempt: %w", context.DeadlineExceeded), "deadline_exceeded"},
+		{"canceled", 0, context.Canceled, "canceled"},
+		{"circuit open", 0, gobreaker.ErrOpenState, "unavailable"},
+		{"connection refused", 0, &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, "unavailable"},
+		{"other", 0, fmt.Errorf("tls: bad certificate"), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status, Header: http.Header{}, Body: http.NoBody}
+			}
+			if got := callCode(resp, tt.err); got != tt.want {
+				t.Errorf("callCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// DefaultIdempotencyHeader marks a request as safe to retry when present.
+const DefaultIdempotencyHeader = "X-Idempotency-Key"
+
+// maxErrorPeek bounds how much of an error response body is read to find its Connect code.
+const maxErrorPeek = 4 << 10
+
+// RetryPolicy decides whether a failed attempt is retried.
+//
+// err is the transport error, or for an error response (status >= 400) a
+// *connect.Error whose code comes from the Connect error body or, failing
+// that, the HTTP status; it wraps a *StatusError. attempt is the number of
+// attempts made so far. The retry and deadline limits apply regardless.
+type RetryPolicy func(req *http.Request, err error, attempt int) bool
+
+// StatusError describes an HTTP error response.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// NewDefaultRetryPolicy returns the default policy: only idempotent requests
+// are retried, and only on transient failures.
+//
+// A request is idempotent if its HTTP method is (GET, HEAD, OPTIONS, PUT,
+// DELETE, TRACE), if its path is one of procedures (Connect unary calls are
+// POSTs, so idempotent RPCs must be listed), or if it carries
+// idempotencyHeader. Transient failures are connection refused or reset,
+// timeouts, CodeUnavailable, CodeDeadlineExceeded and 5xx responses.
+func NewDefaultRetryPolicy(idempotencyHeader string, procedures []string) RetryPolicy {
+	idempotent := NewIdempotencyCheck(idempotencyHeader, procedures)
+	return func(req *http.Request, err error, attempt int) bool {
+		return idempotent(req) && IsTransient(err)
+	}
+}
+
+// NewIdempotencyCheck returns a function reporting whether a request is safe
+// to send more than once: its HTTP method is idempotent (GET, HEAD, OPTIONS,
+// PUT, DELETE, TRACE), it carries idempotencyHeader, or its path is one of
+// procedures. A missing leading slash in procedures is added.
+func NewIdempotencyCheck(idempotencyHeader string, procedures []string) func(*http.Request) bool {
+	idempotent := make(map[string]struct{}, len(procedures))
+	for _, p := range procedures {
+		if p != "" && !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		idempotent[p] = struct{}{}
+	}
+
+	return func(req *http.Request) bool {
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+			return true
+		}
+		if idempotencyHeader != "" && req.Header.Get(idempotencyHeader) != "" {
+			return true
+		}
+		_, ok := idempotent[req.URL.Path]
+		return ok
+	}
+}
+
+// IsTransient reports whether err describes a failure worth retrying.
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode >= 500 {
+		return true
+	}
+
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		switch connectErr.Code() {
+		case connect.CodeUnavailable, connect.CodeDeadlineExceeded:
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// responseError returns the failure described by an error response, or nil
+// for responses below 400. The body is restored after peeking at it.
+func responseError(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	return connect.NewError(responseCode(resp), &StatusError{StatusCode: resp.StatusCode})
+}
+
+// responseCode reads the Connect error code from a JSON error body, falling
+// back to the HTTP status mapping of the Connect protocol.
+func responseCode(resp *http.Response) connect.Code {
+	if resp.Body != nil && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		peek, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorPeek))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+
+		var body struct {
+			Code string `json:"code"`
+		}
+		if json.Unmarshal(peek, &body) == nil && body.Code != "" {
+			var code connect.Code
+			if code.UnmarshalText([]byte(body.Code)) == nil {
+				return code
+			}
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return connect.CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return connect.CodeDeadlineExceeded
+	case http.StatusTooManyRequests:
+		return connect.CodeResourceExhausted
+	case http.StatusNotImplemented:
+		return connect.CodeUnimplemented
+	case http.StatusUnauthorized:
+		return connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		return connect.CodePermissionDenied
+	case http.StatusNotFound:
+		return connect.CodeNotFound
+	case http.StatusInternalServerError:
+		return connect.CodeInternal
+	default:
+		return connect.CodeUnknown
+	}
+}
+
+// equalJitter returns a random duration in [d/2, d], spreading retries of
+// clients that failed together while keeping the exponential growth.
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half+1)
+}
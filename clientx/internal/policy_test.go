@@ -0,0 +1,219 @@
+// Package internal provides tests for clientx retry policies.
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	policy := NewDefaultRetryPolicy(DefaultIdempotencyHeader, []string{"user.v1.UserService/GetUser"})
+	unavailable := connect.NewError(connect.CodeUnavailable, &StatusError{StatusCode: http.StatusServiceUnavailable})
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header bool
+		err    error
+		want   bool
+	}{
+		{"GET on 503", http.MethodGet, "/", false, unavailable, true},
+		{"GET on connection refused", http.MethodGet, "/", false, refused, true},
+		{"GET on timeout", http.MethodGet, "/", false, context.DeadlineExceeded, true},
+		{"GET on deadline code", http.MethodGet, "/", false, connect.NewError(connect.CodeDeadlineExceeded, nil), true},
+		{"GET on 400", http.MethodGet, "/", false, connect.NewError(connect.CodeInvalidArgument, &StatusError{StatusCode: 400}), false},
+		{"GET on other error", http.MethodGet, "/", false, errors.New("tls: bad certificate"), false},
+		{"POST on 503", http.MethodPost, "/user.v1.UserService/CreateUser", false, unavailable, false},
+		{"POST on connection refused", http.MethodPost, "/user.v1.UserService/CreateUser", false, refused, false},
+		{"POST to idempotent procedure", http.MethodPost, "/user.v1.UserService/GetUser", false, unavailable, true},
+		{"POST with idempotency key", http.MethodPost, "/user.v1.UserService/CreateUser", true, unavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "http://upstream.test"+tt.path, nil)
+			if tt.header {
+				req.Header.Set(DefaultIdempotencyHeader, "key-1")
+			}
+			if got := policy(req, tt.err, 1); got != tt.want {
+				t.Errorf("policy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_NonIdempotentNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, 3, time.Millisecond, nil)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/user.v1.UserService/CreateUser", strings.NewReader("{}"))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 for a non-idempotent POST", attempts)
+	}
+}
+
+func TestRetryTransport_IdempotentProcedureRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if attempts < 3 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":"unavailable","message":"overloaded"}`))
+			return
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, 3, time.Millisecond, nil).
+		WithPolicy(NewDefaultRetryPolicy(DefaultIdempotencyHeader, []string{"/user.v1.UserService/GetUser"}))
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/user.v1.UserService/GetUser", strings.NewReader(`{"id":"1"}`))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if string(body) != `{"id":"1"}` {
+		t.Errorf("body = %q, want request body resent on retry", body)
+	}
+}
+
+func TestRetryTransport_ConnectCodeFromErrorBody(t *testing.T) {
+	var codes []connect.Code
+	policy := func(req *http.Request, err error, attempt int) bool {
+		codes = append(codes, connect.CodeOf(err))
+		return false
+	}
+
+	const errorBody = `{"code":"resource_exhausted","message":"quota exceeded"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(errorBody))
+	}))
+	defer server.Close()
+
+	transport := NewRetryTransport(http.DefaultTransport, 3, time.Millisecond, nil).WithPolicy(policy)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if len(codes) != 1 || codes[0] != connect.CodeResourceExhausted {
+		t.Errorf("policy saw codes %v, want [resource_exhausted]", codes)
+	}
+	if string(body) != errorBody {
+		t.Errorf("body = %q, want the error body intact", body)
+	}
+}
+
+func TestRetryTransport_CustomPolicy(t *testing.T) {
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	var seen []int
+	transport := NewRetryTransport(base, 5, time.Millisecond, nil).
+		WithPolicy(func(req *http.Request, err error, attempt int) bool {
+			seen = append(seen, attempt)
+			return connect.CodeOf(err) == connect.CodeResourceExhausted && attempt < 3
+		})
+
+	req, _ := http.NewRequest(http.MethodPost, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if len(seen) != 3 || seen[0] != 1 || seen[2] != 3 {
+		t.Errorf("policy attempts = %v, want [1 2 3]", seen)
+	}
+}
+
+func TestRetryTransport_CancelledRequestNotRetried(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		cancel()
+		return nil, req.Context().Err()
+	})
+
+	transport := NewRetryTransport(base, 3, time.Millisecond, nil)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://upstream.test/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RoundTrip() error = %v, want Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestRetryTransport_BackoffJitter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	})
+
+	transport := NewRetryTransport(base, 4, 100*time.Millisecond, nil).WithClock(clock)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(clock.sleeps) != 4 {
+		t.Fatalf("backoff sleeps = %v, want 4", clock.sleeps)
+	}
+	for i, d := range clock.sleeps {
+		full := 100 * time.Millisecond << i
+		if d < full/2 || d > full {
+			t.Errorf("backoff %d = %v, want within [%v, %v]", i, d, full/2, full)
+		}
+	}
+}
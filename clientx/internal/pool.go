@@ -0,0 +1,155 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default connection pool settings, tuned for Connect over h2c between services.
+const (
+	// DefaultMaxIdleConns is the idle pool size across all hosts.
+	DefaultMaxIdleConns = 100
+	// DefaultMaxIdleConnsPerHost is the idle pool size per host.
+	// http.DefaultTransport keeps only 2, which would discard most warmed connections.
+	DefaultMaxIdleConnsPerHost = 100
+	// DefaultIdleConnTimeout closes idle connections before typical load
+	// balancer and proxy idle timeouts do.
+	DefaultIdleConnTimeout = 90 * time.Second
+	// DefaultHTTP2ReadIdleTimeout pings HTTP/2 connections that received no
+	// frame for this long, so dead connections are detected.
+	DefaultHTTP2ReadIdleTimeout = 30 * time.Second
+	// DefaultHTTP2PingTimeout closes HTTP/2 connections whose ping is not answered.
+	DefaultHTTP2PingTimeout = 15 * time.Second
+)
+
+// PoolConfig configures the connection pool of a client transport.
+type PoolConfig struct {
+	MaxIdleConns        int           // Idle connections across all hosts (0: unlimited)
+	MaxIdleConnsPerHost int           // Idle connections per host
+	MaxConnsPerHost     int           // Connections per host, including active ones (0: unlimited)
+	IdleConnTimeout     time.Duration // Idle time before a connection is closed (0: unlimited)
+	MaxStreamsPerConn   int           // In-flight requests per connection before another is opened (0: unlimited)
+	H2C                 bool          // Use HTTP/2 without TLS (prior knowledge) for http:// URLs
+
+	HTTP2ReadIdleTimeout time.Duration // Idle time before an HTTP/2 health ping (0: no pings)
+	HTTP2PingTimeout     time.Duration // Time to wait for a ping response (0: 15s)
+}
+
+// NewPooledTransport returns a transport configured by cfg.
+//
+// HTTP/2 multiplexes all requests to a host over a single connection, so one
+// slow stream or a saturated connection window delays every other request.
+// With MaxStreamsPerConn set, requests are spread over independent
+// connections instead, opening another connection once every existing one
+// carries MaxStreamsPerConn in-flight requests (up to MaxConnsPerHost).
+func NewPooledTransport(cfg PoolConfig) http.RoundTripper {
+	if cfg.MaxStreamsPerConn <= 0 {
+		return newHTTPTransport(cfg)
+	}
+	return &streamBalancer{
+		newTransport: func() *http.Transport { return newHTTPTransport(cfg) },
+		maxStreams:   cfg.MaxStreamsPerConn,
+		maxConns:     cfg.MaxConnsPerHost,
+	}
+}
+
+// newHTTPTransport returns a clone of http.DefaultTransport configured by cfg.
+func newHTTPTransport(cfg PoolConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+	transport.HTTP2 = &http.HTTP2Config{
+		SendPingTimeout: cfg.HTTP2ReadIdleTimeout,
+		PingTimeout:     cfg.HTTP2PingTimeout,
+	}
+	if cfg.H2C {
+		// Without HTTP1, http:// URLs use HTTP/2 with prior knowledge
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return transport
+}
+
+// streamBalancer spreads requests over several transports, each of which
+// holds its own connection to the host, keeping at most maxStreams requests
+// in flight on each until maxConns transports exist.
+type streamBalancer struct {
+	newTransport func() *http.Transport
+	maxStreams   int
+	maxConns     int
+
+	mu    sync.Mutex
+	conns []*balancedConn
+}
+
+// balancedConn is a transport and its number of in-flight requests.
+type balancedConn struct {
+	transport *http.Transport
+	active    int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (b *streamBalancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	conn := b.acquire()
+	resp, err := conn.transport.RoundTrip(req)
+	if err != nil {
+		b.release(conn)
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() { b.release(conn) }}
+	return resp, nil
+}
+
+// acquire returns the least loaded transport with spare streams, opening a new
+// one when all are full. At the maxConns limit the least loaded one is shared.
+func (b *streamBalancer) acquire() *balancedConn {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var least *balancedConn
+	for _, c := range b.conns {
+		if least == nil || c.active < least.active {
+			least = c
+		}
+	}
+	if least == nil || (least.active >= b.maxStreams && (b.maxConns <= 0 || len(b.conns) < b.maxConns)) {
+		least = &balancedConn{transport: b.newTransport()}
+		b.conns = append(b.conns, least)
+	}
+	least.active++
+	return least
+}
+
+func (b *streamBalancer) release(conn *balancedConn) {
+	b.mu.Lock()
+	conn.active--
+	b.mu.Unlock()
+}
+
+// CloseIdleConnections closes the idle connections of every transport.
+func (b *streamBalancer) CloseIdleConnections() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.conns {
+		c.transport.CloseIdleConnections()
+	}
+}
+
+// releaseOnClose runs release once, when the response body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
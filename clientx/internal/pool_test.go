@@ -0,0 +1,120 @@
+// Package internal provides tests for clientx connection pooling.
+package internal
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newH2CServer starts an h2c server that blocks every request until release
+// is closed, counting the connections it accepts.
+func newH2CServer(t *testing.T, release <-chan struct{}, started chan<- struct{}) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("request used %s, want HTTP/2", r.Proto)
+		}
+		started <- struct{}{}
+		<-release
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+// concurrentConns sends n requests that are all in flight at the same time and
+// returns the number of connections the server accepted for them.
+//
+// Requests are started one after another, each once the previous one reached
+// the server, so concurrent dials for a host without a connection yet do not
+// open extra connections.
+func concurrentConns(t *testing.T, cfg PoolConfig, n int) int32 {
+	t.Helper()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	server, conns := newH2CServer(t, release, started)
+
+	client := &http.Client{Transport: NewPooledTransport(cfg), Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		failed := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+				close(failed)
+				return
+			}
+			resp.Body.Close()
+		}()
+		select {
+		case <-started:
+		case <-failed:
+		}
+	}
+	close(release)
+	wg.Wait()
+	return conns.Load()
+}
+
+func TestNewPooledTransport_MultiplexesByDefault(t *testing.T) {
+	if got := concurrentConns(t, PoolConfig{H2C: true}, 6); got != 1 {
+		t.Errorf("connections = %d, want 1 shared HTTP/2 connection", got)
+	}
+}
+
+func TestNewPooledTransport_MaxStreamsPerConn(t *testing.T) {
+	if got := concurrentConns(t, PoolConfig{H2C: true, MaxStreamsPerConn: 2}, 6); got != 3 {
+		t.Errorf("connections = %d, want 3 for 6 streams at 2 per connection", got)
+	}
+}
+
+func TestNewPooledTransport_MaxStreamsCappedByMaxConns(t *testing.T) {
+	cfg := PoolConfig{H2C: true, MaxStreamsPerConn: 1, MaxConnsPerHost: 2}
+	if got := concurrentConns(t, cfg, 6); got != 2 {
+		t.Errorf("connections = %d, want 2 (MaxConnsPerHost)", got)
+	}
+}
+
+func TestNewPooledTransport_Config(t *testing.T) {
+	transport := NewPooledTransport(PoolConfig{
+		MaxIdleConns:         10,
+		MaxIdleConnsPerHost:  5,
+		MaxConnsPerHost:      20,
+		IdleConnTimeout:      time.Minute,
+		HTTP2ReadIdleTimeout: 10 * time.Second,
+		HTTP2PingTimeout:     3 * time.Second,
+	}).(*http.Transport)
+
+	if transport.MaxIdleConns != 10 || transport.MaxIdleConnsPerHost != 5 || transport.MaxConnsPerHost != 20 {
+		t.Errorf("pool limits = %d/%d/%d, want 10/5/20",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+	}
+	if transport.IdleConnTimeout != time.Minute {
+		t.Errorf("IdleConnTimeout = %v, want 1m", transport.IdleConnTimeout)
+	}
+	if transport.HTTP2 == nil || transport.HTTP2.SendPingTimeout != 10*time.Second || transport.HTTP2.PingTimeout != 3*time.Second {
+		t.Errorf("HTTP2 = %+v, want ping after 10s, timeout 3s", transport.HTTP2)
+	}
+	if transport.Protocols != nil {
+		t.Errorf("Protocols = %v, want default without H2C", transport.Protocols)
+	}
+}
@@ -2,18 +2,79 @@
 package internal
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/sony/gobreaker"
+
+	"go.eggybyte.com/egg/core/clock"
+	rpcdeadline "go.eggybyte.com/egg/core/deadline"
+	"go.eggybyte.com/egg/core/propagation"
 )
 
+// Clock abstracts time for the retry loop so budgets can be tested without sleeping.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter case.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the wall clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// coreClock adapts a core clock.Clock to Clock.
+type coreClock struct {
+	clock clock.Clock
+}
+
+// NewClock returns a Clock backed by c, so backoff waits on c's timers.
+func NewClock(c clock.Clock) Clock {
+	return coreClock{clock: c}
+}
+
+func (c coreClock) Now() time.Time { return c.clock.Now() }
+
+func (c coreClock) Sleep(ctx context.Context, d time.Duration) error {
+	return clock.Sleep(ctx, c.clock, d)
+}
+
+// RetryBudget bounds the time spent on a request across retries.
+// Zero values disable the corresponding limit.
+type RetryBudget struct {
+	PerAttempt time.Duration // Timeout of each attempt, capped by the remaining Deadline
+	Deadline   time.Duration // Total time for all attempts and backoff
+}
+
 // RetryTransport implements http.RoundTripper with retry logic and circuit breaker.
 type RetryTransport struct {
 	base       http.RoundTripper
 	maxRetries int
 	backoff    time.Duration
 	cb         *gobreaker.CircuitBreaker
+	budget     RetryBudget
+	clock      Clock
+	policy     RetryPolicy
+	jitter     func(time.Duration) time.Duration
+	propagate  bool // Cap the budget by the inbound deadline and send it downstream
+	headers    bool // Send the headers stored in the request context with propagation.With
+	stream     StreamConfig
+	timeout    time.Duration // Total time of a non-stream request, including the body read
 }
 
 // NewRetryTransport creates a new retry transport with the given configuration.
@@ -23,15 +84,107 @@ func NewRetryTransport(base http.RoundTripper, maxRetries int, backoff time.Dura
 		maxRetries: maxRetries,
 		backoff:    backoff,
 		cb:         cb,
+		clock:      realClock{},
+		policy:     NewDefaultRetryPolicy(DefaultIdempotencyHeader, nil),
+		jitter:     equalJitter,
 	}
 }
 
+// WithBudget sets the per-attempt timeout and overall deadline and returns t.
+func (t *RetryTransport) WithBudget(budget RetryBudget) *RetryTransport {
+	t.budget = budget
+	return t
+}
+
+// WithClock replaces the clock used for budgets and backoff and returns t.
+func (t *RetryTransport) WithClock(clock Clock) *RetryTransport {
+	t.clock = clock
+	return t
+}
+
+// WithPolicy replaces the policy deciding which failures are retried and returns t.
+// A nil policy keeps the current one.
+func (t *RetryTransport) WithPolicy(policy RetryPolicy) *RetryTransport {
+	if policy != nil {
+		t.policy = policy
+	}
+	return t
+}
+
+// WithDeadlinePropagation enables deadline propagation and returns t. The
+// inbound RPC deadline recorded in the request context caps the Deadline
+// budget, and each attempt sends its remaining time as the
+// rpcdeadline.Header header unless the caller set a shorter one.
+func (t *RetryTransport) WithDeadlinePropagation(enabled bool) *RetryTransport {
+	t.propagate = enabled
+	return t
+}
+
+// WithHeaderPropagation enables header propagation and returns t. Each
+// attempt sends the headers stored in the request context with
+// propagation.With, except those the request already carries.
+func (t *RetryTransport) WithHeaderPropagation(enabled bool) *RetryTransport {
+	t.headers = enabled
+	return t
+}
+
+// WithStreams sets the timeouts of streaming requests and returns t.
+func (t *RetryTransport) WithStreams(stream StreamConfig) *RetryTransport {
+	t.stream = stream
+	return t
+}
+
+// WithRequestTimeout bounds each non-stream request, from the first attempt
+// until its response body is closed, and returns t. It replaces
+// http.Client.Timeout when that would also cut streams short.
+func (t *RetryTransport) WithRequestTimeout(d time.Duration) *RetryTransport {
+	t.timeout = d
+	return t
+}
+
+// WithJitter replaces the function randomizing each backoff and returns t.
+// A nil jitter uses the exact exponential backoff.
+func (t *RetryTransport) WithJitter(jitter func(time.Duration) time.Duration) *RetryTransport {
+	if jitter == nil {
+		jitter = func(d time.Duration) time.Duration { return d }
+	}
+	t.jitter = jitter
+	return t
+}
+
 // RoundTrip implements http.RoundTripper with retry and circuit breaker.
+// Streaming requests (see IsStreamRequest) are only retried before they
+// reach the server. A context marked with WithNoRetry gets one attempt, and
+// one marked with WithCallTimeout replaces the request timeout, which the
+// caller then enforces.
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stream := IsStreamRequest(req)
+	_, callTimeout := CallTimeout(req.Context())
+	if stream || t.timeout <= 0 || callTimeout {
+		return t.roundTrip(req, stream)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.roundTrip(req.WithContext(ctx), false)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// roundTrip sends req through the circuit breaker if enabled.
+func (t *RetryTransport) roundTrip(req *http.Request, stream bool) (*http.Response, error) {
+	send := t.roundTripWithRetry
+	if stream {
+		send = t.roundTripStream
+	}
+
 	// Execute through circuit breaker if enabled
 	if t.cb != nil {
 		result, cbErr := t.cb.Execute(func() (interface{}, error) {
-			return t.roundTripWithRetry(req)
+			return send(req)
 		})
 		if cbErr != nil {
 			return nil, cbErr
@@ -39,22 +192,74 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return result.(*http.Response), nil
 	}
 
-	return t.roundTripWithRetry(req)
+	return send(req)
 }
 
 // roundTripWithRetry performs the request with retry logic.
+//
+// Failures are retried only when the policy allows it: transport errors are
+// passed to it as is, error responses (status >= 400) as a *connect.Error.
+//
+// With a Deadline, the time already spent is subtracted from the budget before
+// every attempt: each attempt gets at most the remaining budget, and retrying
+// stops once the budget cannot cover the next backoff.
 func (t *RetryTransport) roundTripWithRetry(req *http.Request) (*http.Response, error) {
 	var lastResp *http.Response
 	var lastErr error
 
-	for attempt := 0; attempt <= t.maxRetries; attempt++ {
-		// Clone request for retry (body might be consumed)
-		clonedReq := req.Clone(req.Context())
+	maxRetries := t.retries(req)
+	deadline := t.budgetDeadline(req)
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		attemptTimeout := t.budget.PerAttempt
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(t.clock.Now())
+			if remaining <= 0 {
+				// The previous response body is already closed
+				if lastErr == nil {
+					lastErr = context.DeadlineExceeded
+				}
+				return nil, lastErr
+			}
+			if attemptTimeout <= 0 || remaining < attemptTimeout {
+				attemptTimeout = remaining
+			}
+		}
+
+		// Clone request for retry, rewinding the body consumed by earlier attempts
+		ctx, cancel := req.Context(), context.CancelFunc(func() {})
+		if attemptTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, attemptTimeout)
+		}
+		clonedReq := req.Clone(ctx)
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			clonedReq.Body = body
+		}
+		if t.propagate {
+			setDeadlineHeader(clonedReq)
+		}
+		if t.headers {
+			propagation.Inject(ctx, clonedReq.Header)
+		}
 
 		resp, err := t.base.RoundTrip(clonedReq)
 
-		// Success or non-retryable error
-		if err == nil && resp.StatusCode < 500 {
+		failure := err
+		if err == nil {
+			failure = responseError(resp)
+		}
+
+		// Success or non-retryable failure; a cancelled caller is never retried
+		if failure == nil || req.Context().Err() != nil || !t.policy(req, failure, attempt+1) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 			return resp, nil
 		}
 
@@ -62,8 +267,16 @@ func (t *RetryTransport) roundTripWithRetry(req *http.Request) (*http.Response,
 		lastResp = resp
 		lastErr = err
 
-		// Don't retry on last attempt
-		if attempt == t.maxRetries {
+		// Exponential backoff with jitter
+		backoff := t.jitter(t.backoff * time.Duration(1<<uint(attempt)))
+
+		// Don't retry on last attempt, or once the budget cannot cover the backoff
+		if attempt == maxRetries || (!deadline.IsZero() && deadline.Sub(t.clock.Now()) <= backoff) {
+			if resp != nil && resp.Body != nil {
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
 			break
 		}
 
@@ -71,14 +284,65 @@ func (t *RetryTransport) roundTripWithRetry(req *http.Request) (*http.Response,
 		if resp != nil && resp.Body != nil {
 			resp.Body.Close()
 		}
+		cancel()
 
-		// Exponential backoff with jitter
-		backoff := t.backoff * time.Duration(1<<uint(attempt))
-		time.Sleep(backoff)
+		if err := t.clock.Sleep(req.Context(), backoff); err != nil {
+			return nil, err
+		}
 	}
 
 	return lastResp, lastErr
 }
 
+// retries returns the number of retries allowed for req.
+func (t *RetryTransport) retries(req *http.Request) int {
+	if NoRetry(req.Context()) {
+		return 0
+	}
+	return t.maxRetries
+}
+
+// budgetDeadline returns when the retry budget of req runs out on the
+// clock, or the zero time without a budget.
+func (t *RetryTransport) budgetDeadline(req *http.Request) time.Time {
+	var deadline time.Time
+	if t.budget.Deadline > 0 {
+		deadline = t.clock.Now().Add(t.budget.Deadline)
+	}
+	if t.propagate {
+		// The inbound request deadline is wall time; rebase it onto the clock
+		if inbound, ok := rpcdeadline.From(req.Context()); ok {
+			if capped := t.clock.Now().Add(time.Until(inbound)); deadline.IsZero() || capped.Before(deadline) {
+				deadline = capped
+			}
+		}
+	}
+	return deadline
+}
+
+// setDeadlineHeader sends the time left until the request context deadline,
+// or the recorded inbound deadline if earlier, keeping a shorter timeout
+// already set by the caller.
+func setDeadlineHeader(req *http.Request) {
+	remaining, ok := rpcdeadline.Remaining(req.Context())
+	if !ok || remaining <= 0 {
+		return
+	}
+	if preset, err := strconv.ParseInt(req.Header.Get(rpcdeadline.Header), 10, 64); err == nil && preset > 0 && preset <= remaining.Milliseconds() {
+		return
+	}
+	req.Header.Set(rpcdeadline.Header, rpcdeadline.FormatHeader(remaining))
+}
 
+// cancelOnClose releases an attempt's context once its response body is closed,
+// so a per-attempt timeout keeps covering the body read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
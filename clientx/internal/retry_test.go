@@ -3,14 +3,21 @@ package internal
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/sony/gobreaker"
+
+	rpcdeadline "go.eggybyte.com/egg/core/deadline"
 )
 
 func TestNewRetryTransport(t *testing.T) {
@@ -261,6 +268,7 @@ func TestRetryTransport_RoundTrip_RequestBodyCloning(t *testing.T) {
 
 	bodyContent := []byte("test body")
 	req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(bodyContent))
+	req.Header.Set(DefaultIdempotencyHeader, "key-1") // POST is only retried when idempotent
 
 	resp, err := transport.RoundTrip(req)
 	if err != nil {
@@ -490,3 +498,237 @@ func TestRetryTransport_RoundTrip_Status500(t *testing.T) {
 	}
 }
 
+// fakeClock is a manually advanced Clock; Sleep advances time instantly.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.Advance(d)
+	c.mu.Lock()
+	c.sleeps = append(c.sleeps, d)
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// slowUnavailable returns 503 after advancing clock by took, recording the
+// timeout each attempt was given.
+func slowUnavailable(clock *fakeClock, took time.Duration, timeouts *[]time.Duration) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if deadline, ok := req.Context().Deadline(); ok {
+			*timeouts = append(*timeouts, time.Until(deadline))
+		} else {
+			*timeouts = append(*timeouts, 0)
+		}
+		clock.Advance(took)
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+}
+
+func TestRetryTransport_Deadline_StopsWhenBudgetExhausted(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var timeouts []time.Duration
+
+	// Each attempt takes 400ms; backoff is 100ms, then 200ms.
+	// t=0 attempt 1 -> t=0.4, sleep 100ms -> t=0.5, attempt 2 -> t=0.9;
+	// 100ms left cannot cover the 200ms backoff, so retrying stops.
+	transport := NewRetryTransport(slowUnavailable(clock, 400*time.Millisecond, &timeouts), 5, 100*time.Millisecond, nil).
+		WithBudget(RetryBudget{Deadline: time.Second}).
+		WithClock(clock).
+		WithJitter(nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want last attempt's 503", resp.StatusCode)
+	}
+	if len(timeouts) != 2 {
+		t.Fatalf("attempts = %d, want 2 within the 1s budget", len(timeouts))
+	}
+	if len(clock.sleeps) != 1 || clock.sleeps[0] != 100*time.Millisecond {
+		t.Errorf("backoff sleeps = %v, want [100ms]", clock.sleeps)
+	}
+	if elapsed := clock.Now().Sub(time.Unix(0, 0)); elapsed > time.Second {
+		t.Errorf("retries took %v of fake time, want within the 1s budget", elapsed)
+	}
+}
+
+func TestRetryTransport_PerAttemptTimeout_CappedByRemainingBudget(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var timeouts []time.Duration
+
+	transport := NewRetryTransport(slowUnavailable(clock, 300*time.Millisecond, &timeouts), 2, 100*time.Millisecond, nil).
+		WithBudget(RetryBudget{PerAttempt: 500 * time.Millisecond, Deadline: time.Second}).
+		WithClock(clock).
+		WithJitter(nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	// t=0 attempt 1 gets the full 500ms -> t=0.3, sleep 100ms;
+	// t=0.4 attempt 2 gets 500ms of the remaining 600ms -> t=0.7, sleep 200ms;
+	// t=0.9 attempt 3 is capped to the remaining 100ms.
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 100 * time.Millisecond}
+	if len(timeouts) != len(want) {
+		t.Fatalf("attempts = %d, want %d", len(timeouts), len(want))
+	}
+	for i := range want {
+		if d := want[i] - timeouts[i]; d < 0 || d > 50*time.Millisecond {
+			t.Errorf("attempt %d timeout = %v, want ~%v", i+1, timeouts[i], want[i])
+		}
+	}
+}
+
+func TestRetryTransport_PerAttemptTimeout_RetriesHungAttempt(t *testing.T) {
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			<-req.Context().Done() // hung upstream
+			return nil, req.Context().Err()
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte("ok")))}, nil
+	})
+
+	transport := NewRetryTransport(base, 1, time.Millisecond, nil).
+		WithBudget(RetryBudget{PerAttempt: 20 * time.Millisecond})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Errorf("body = %q, %v; want ok", body, err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestRetryTransport_Deadline_NetworkErrors(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	attempts := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		clock.Advance(250 * time.Millisecond)
+		return nil, refused
+	})
+
+	transport := NewRetryTransport(base, 10, 100*time.Millisecond, nil).
+		WithBudget(RetryBudget{Deadline: 500 * time.Millisecond}).
+		WithClock(clock).
+		WithJitter(nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, refused) {
+		t.Fatalf("RoundTrip() error = %v, want last attempt error", err)
+	}
+	// t=0.25 after attempt 1, backoff 100ms -> 0.35, attempt 2 -> 0.6: exhausted
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestRetryTransport_DeadlinePropagation_Header(t *testing.T) {
+	var headers []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		headers = append(headers, req.Header.Get(rpcdeadline.Header))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+	transport := NewRetryTransport(base, 0, 0, nil).WithDeadlinePropagation(true)
+
+	send := func(ctx context.Context, preset string) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://upstream.test/", nil)
+		if preset != "" {
+			req.Header.Set(rpcdeadline.Header, preset)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	inbound := rpcdeadline.With(context.Background(), time.Now().Add(2*time.Second))
+	send(context.Background(), "")
+	send(inbound, "")
+	send(inbound, "500")
+	send(inbound, "60000")
+
+	if headers[0] != "" {
+		t.Errorf("no deadline: header = %q, want none", headers[0])
+	}
+	for i, name := range map[int]string{1: "inbound", 3: "longer preset"} {
+		if ms, err := strconv.Atoi(headers[i]); err != nil || ms <= 1500 || ms > 2000 {
+			t.Errorf("%s: header = %q, want about 2000", name, headers[i])
+		}
+	}
+	if headers[2] != "500" {
+		t.Errorf("shorter preset: header = %q, want 500 kept", headers[2])
+	}
+}
+
+func TestRetryTransport_DeadlinePropagation_CapsBudget(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var timeouts []time.Duration
+
+	// Same schedule as the 1s Deadline budget, taken from the inbound deadline
+	transport := NewRetryTransport(slowUnavailable(clock, 400*time.Millisecond, &timeouts), 5, 100*time.Millisecond, nil).
+		WithClock(clock).
+		WithJitter(nil).
+		WithDeadlinePropagation(true)
+
+	ctx := rpcdeadline.With(context.Background(), time.Now().Add(time.Second))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://upstream.test/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+	if len(timeouts) != 2 {
+		t.Errorf("attempts = %d, want 2 within the inbound deadline", len(timeouts))
+	}
+
+	// An expired inbound deadline fails without sending
+	timeouts = nil
+	expired := rpcdeadline.With(context.Background(), time.Now().Add(-time.Second))
+	req, _ = http.NewRequestWithContext(expired, http.MethodGet, "http://upstream.test/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() error = %v, want DeadlineExceeded", err)
+	}
+	if len(timeouts) != 0 {
+		t.Errorf("attempts = %d, want none after the deadline", len(timeouts))
+	}
+}
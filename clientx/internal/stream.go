@@ -0,0 +1,175 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.eggybyte.com/egg/core/propagation"
+)
+
+// StreamConfig bounds streaming requests. Zero values disable the
+// corresponding limit.
+type StreamConfig struct {
+	EstablishTimeout time.Duration // Time for each attempt to receive the response headers (default: the per-attempt timeout)
+	Timeout          time.Duration // Total lifetime of a stream, including establishment
+}
+
+// IsStreamRequest reports whether req is a streaming call: Connect streams
+// (application/connect+proto, application/connect+json) and gRPC or gRPC-Web
+// calls, whose request bodies are written while the call is in flight and
+// cannot be replayed.
+func IsStreamRequest(req *http.Request) bool {
+	contentType := req.Header.Get("Content-Type")
+	return strings.HasPrefix(contentType, "application/connect+") || strings.HasPrefix(contentType, "application/grpc")
+}
+
+// roundTripStream opens a stream.
+//
+// An attempt is retried only if it failed transiently before a connection
+// was obtained, so nothing reached the server and the request body is
+// untouched; the retry policy is not consulted. Once the stream has a
+// connection it is never retried, and the establishment timeout stops
+// applying as soon as the response headers arrive. The retry budget bounds
+// establishment, not the stream.
+func (t *RetryTransport) roundTripStream(req *http.Request) (*http.Response, error) {
+	ctx, cancelStream := req.Context(), context.CancelFunc(func() {})
+	if t.stream.Timeout > 0 {
+		ctx, cancelStream = context.WithTimeout(ctx, t.stream.Timeout)
+	}
+	establish := t.stream.EstablishTimeout
+	if establish <= 0 {
+		establish = t.budget.PerAttempt
+	}
+	maxRetries := t.retries(req)
+	deadline := t.budgetDeadline(req)
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		timeout := establish
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(t.clock.Now())
+			if remaining <= 0 {
+				if lastErr == nil {
+					lastErr = context.DeadlineExceeded
+				}
+				break
+			}
+			if timeout <= 0 || remaining < timeout {
+				timeout = remaining
+			}
+		}
+
+		resp, connected, err := t.openStream(ctx, req, timeout)
+		if err == nil {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancelStream}
+			return resp, nil
+		}
+		lastErr = err
+
+		backoff := t.jitter(t.backoff * time.Duration(1<<uint(attempt)))
+		if connected || !IsTransient(err) || ctx.Err() != nil || attempt == maxRetries ||
+			(!deadline.IsZero() && deadline.Sub(t.clock.Now()) <= backoff) {
+			break
+		}
+		if err := t.clock.Sleep(ctx, backoff); err != nil {
+			lastErr = err
+			break
+		}
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	cancelStream()
+	return nil, lastErr
+}
+
+// openStream makes one attempt to open a stream, reporting whether the
+// attempt obtained a connection. A failed attempt leaves req.Body open for
+// the next one. The returned response body releases the attempt's context
+// once closed.
+func (t *RetryTransport) openStream(ctx context.Context, req *http.Request, timeout time.Duration) (*http.Response, bool, error) {
+	attemptCtx, cancel := context.WithCancelCause(ctx)
+	var connected atomic.Bool
+	attemptCtx = httptrace.WithClientTrace(attemptCtx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) { connected.Store(true) },
+	})
+
+	var timer *time.Timer
+	timedOut := fmt.Errorf("stream not established within %s: %w", timeout, context.DeadlineExceeded)
+	if timeout > 0 {
+		timer = time.AfterFunc(timeout, func() { cancel(timedOut) })
+	}
+
+	clonedReq := req.Clone(attemptCtx)
+	var body *heldBody
+	if req.Body != nil && req.Body != http.NoBody {
+		body = &heldBody{ReadCloser: req.Body, held: true}
+		clonedReq.Body = body
+	}
+	if t.propagate {
+		setDeadlineHeader(clonedReq)
+	}
+	if t.headers {
+		propagation.Inject(attemptCtx, clonedReq.Header)
+	}
+
+	resp, err := t.base.RoundTrip(clonedReq)
+	if timer != nil && !timer.Stop() && err == nil {
+		// The timer fired while the headers arrived
+		resp.Body.Close()
+		resp, err = nil, timedOut
+	}
+	if err != nil {
+		if context.Cause(attemptCtx) == timedOut {
+			err = timedOut
+		}
+		cancel(nil)
+		return nil, connected.Load(), err
+	}
+
+	body.release()
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: func() { cancel(nil) }}
+	return resp, true, nil
+}
+
+// heldBody is a request body the transport cannot close while held, so a
+// stream attempt that failed before reading it can hand it to the next one.
+// A close requested while held is applied on release.
+type heldBody struct {
+	io.ReadCloser
+	mu     sync.Mutex
+	held   bool
+	closed bool
+}
+
+func (b *heldBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.held {
+		b.closed = true
+		return nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// release hands closing back to the transport once the stream is open.
+func (b *heldBody) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.held = false
+	if b.closed {
+		b.ReadCloser.Close()
+	}
+}
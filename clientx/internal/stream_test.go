@@ -0,0 +1,205 @@
+// Package internal provides tests for clientx internal implementation.
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// trackedBody records whether the request body was closed.
+type trackedBody struct {
+	io.Reader
+	closed atomic.Bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed.Store(true)
+	return nil
+}
+
+// newStreamRequest returns a Connect streaming request with a body that
+// cannot be replayed, as connect-go sends it.
+func newStreamRequest(t *testing.T, message string) (*http.Request, *trackedBody) {
+	t.Helper()
+	body := &trackedBody{Reader: io.MultiReader(strings.NewReader(message))}
+	req, err := http.NewRequest(http.MethodPost, "http://upstream.test/greet.v1.GreeterService/SayHelloStream", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/connect+proto")
+	return req, body
+}
+
+// gotConn reports a connection through the request's client trace.
+func gotConn(req *http.Request) {
+	if trace := httptrace.ContextClientTrace(req.Context()); trace != nil && trace.GotConn != nil {
+		trace.GotConn(httptrace.GotConnInfo{})
+	}
+}
+
+func okResponse() *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("stream"))}
+}
+
+func TestIsStreamRequest(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"application/connect+proto": true,
+		"application/connect+json":  true,
+		"application/grpc":          true,
+		"application/grpc-web+json": true,
+		"application/proto":         false,
+		"application/json":          false,
+		"":                          false,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "http://upstream.test/", nil)
+		req.Header.Set("Content-Type", contentType)
+		if got := IsStreamRequest(req); got != want {
+			t.Errorf("IsStreamRequest(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
+
+func TestRetryTransport_Stream_RetriesBeforeConnect(t *testing.T) {
+	var attempts atomic.Int32
+	var received string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if attempts.Add(1) == 1 {
+			// Like http.Transport, close the body of a failed request
+			req.Body.Close()
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+		}
+		gotConn(req)
+		data, _ := io.ReadAll(req.Body)
+		received = string(data)
+		return okResponse(), nil
+	})
+
+	transport := NewRetryTransport(base, 3, time.Millisecond, nil).WithJitter(nil)
+	req, body := newStreamRequest(t, "hello")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts.Load())
+	}
+	if received != "hello" {
+		t.Errorf("Second attempt received body %q, want %q", received, "hello")
+	}
+	if body.closed.Load() {
+		t.Error("Body should stay open while the stream is open")
+	}
+}
+
+func TestRetryTransport_Stream_NoRetryAfterConnect(t *testing.T) {
+	var attempts atomic.Int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts.Add(1)
+		gotConn(req)
+		io.ReadAll(req.Body)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	})
+
+	// The idempotency key would make a unary call retryable
+	transport := NewRetryTransport(base, 3, time.Millisecond, nil).WithJitter(nil)
+	req, body := newStreamRequest(t, "hello")
+	req.Header.Set(DefaultIdempotencyHeader, "key-1")
+	if _, err := transport.RoundTrip(req); !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("RoundTrip() error = %v, want ECONNRESET", err)
+	}
+
+	if attempts.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+	}
+	if !body.closed.Load() {
+		t.Error("Body should be closed after the stream failed")
+	}
+}
+
+func TestRetryTransport_Stream_EstablishTimeout(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotConn(req)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	transport := NewRetryTransport(base, 3, time.Millisecond, nil).
+		WithStreams(StreamConfig{EstablishTimeout: 20 * time.Millisecond})
+	req, _ := newStreamRequest(t, "hello")
+	_, err := transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "stream not established") {
+		t.Fatalf("RoundTrip() error = %v, want establishment timeout", err)
+	}
+}
+
+func TestRetryTransport_Stream_Timeouts(t *testing.T) {
+	var streamCtx context.Context
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		streamCtx = req.Context()
+		return okResponse(), nil
+	})
+
+	transport := NewRetryTransport(base, 3, time.Millisecond, nil).
+		WithBudget(RetryBudget{PerAttempt: 10 * time.Millisecond}).
+		WithStreams(StreamConfig{EstablishTimeout: 10 * time.Millisecond, Timeout: 100 * time.Millisecond}).
+		WithRequestTimeout(10 * time.Millisecond)
+	req, _ := newStreamRequest(t, "hello")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Neither the establishment, per-attempt nor request timeout cuts an open stream
+	time.Sleep(30 * time.Millisecond)
+	if err := streamCtx.Err(); err != nil {
+		t.Fatalf("Open stream cancelled: %v", err)
+	}
+
+	select {
+	case <-streamCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Stream timeout should end the stream")
+	}
+}
+
+func TestRetryTransport_RequestTimeout(t *testing.T) {
+	var hasDeadline bool
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		_, hasDeadline = req.Context().Deadline()
+		return okResponse(), nil
+	})
+	transport := NewRetryTransport(base, 0, time.Millisecond, nil).WithRequestTimeout(time.Second)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://upstream.test/greet.v1.GreeterService/SayHello", strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "application/proto")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+	if !hasDeadline {
+		t.Error("Unary request should carry the request timeout")
+	}
+
+	stream, _ := newStreamRequest(t, "hello")
+	resp, err = transport.RoundTrip(stream)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+	if hasDeadline {
+		t.Error("Stream should not carry the request timeout")
+	}
+}
@@ -0,0 +1,132 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxRejectedDrain bounds how much of a rejected response body is read so its
+// connection can be reused for the retry.
+const maxRejectedDrain = 4 << 10
+
+// TokenProvider returns the token to send with a request.
+type TokenProvider func(ctx context.Context) (string, error)
+
+// refreshKey marks a context in which a token was rejected by the server.
+type refreshKey struct{}
+
+// WithTokenRefresh returns a context asking token providers to replace
+// rejected instead of returning it from a cache.
+func WithTokenRefresh(ctx context.Context, rejected string) context.Context {
+	return context.WithValue(ctx, refreshKey{}, rejected)
+}
+
+// RejectedToken returns the token rejected by the server, if the token is
+// being requested for a retry after an authentication failure.
+func RejectedToken(ctx context.Context) (string, bool) {
+	rejected, ok := ctx.Value(refreshKey{}).(string)
+	return rejected, ok
+}
+
+// TokenCache caches the token returned by fetch until shortly before it
+// expires. Concurrent callers share a single fetch.
+type TokenCache struct {
+	fetch func(ctx context.Context) (string, time.Time, error)
+	skew  time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewTokenCache creates a cache refreshing tokens skew before they expire.
+// A zero expiry returned by fetch means the token does not expire.
+func NewTokenCache(fetch func(ctx context.Context) (string, time.Time, error), skew time.Duration) *TokenCache {
+	return &TokenCache{fetch: fetch, skew: skew, now: time.Now}
+}
+
+// Token returns the cached token, fetching a new one if there is none, it is
+// about to expire, or ctx reports that it was rejected. A token rejected by
+// several requests at once is only refreshed once.
+func (c *TokenCache) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rejected, refresh := RejectedToken(ctx)
+	if c.token != "" && !(refresh && rejected == c.token) &&
+		(c.expiresAt.IsZero() || c.now().Before(c.expiresAt.Add(-c.skew))) {
+		return c.token, nil
+	}
+
+	token, expiresAt, err := c.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expiresAt = token, expiresAt
+	return token, nil
+}
+
+// TokenTransport sets a token header on every request and, when the server
+// rejects the token, fetches a fresh one and retries once.
+type TokenTransport struct {
+	base     http.RoundTripper
+	provider TokenProvider
+	header   string
+}
+
+// NewTokenTransport creates a transport setting header from provider.
+func NewTokenTransport(base http.RoundTripper, provider TokenProvider, header string) *TokenTransport {
+	return &TokenTransport{base: base, provider: provider, header: header}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.provider(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("token provider: %w", err)
+	}
+
+	r := req.Clone(req.Context())
+	r.Header.Set(t.header, token)
+	resp, err := t.base.RoundTrip(r)
+	if err != nil || !isUnauthenticated(resp) {
+		return resp, err
+	}
+
+	// A body that cannot be replayed can only be sent once
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	fresh, err := t.provider(WithTokenRefresh(req.Context(), token))
+	if err != nil || fresh == token {
+		// Nothing better to retry with; report the original rejection
+		return resp, nil
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxRejectedDrain))
+	resp.Body.Close()
+
+	r = req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	r.Header.Set(t.header, fresh)
+	return t.base.RoundTrip(r)
+}
+
+// isUnauthenticated reports whether resp rejects the request's credentials:
+// HTTP 401, as sent for Connect CodeUnauthenticated, or a gRPC trailers-only
+// response with status 16 (UNAUTHENTICATED).
+func isUnauthenticated(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusUnauthorized || resp.Header.Get("Grpc-Status") == "16"
+}
@@ -0,0 +1,175 @@
+// Package internal provides tests for clientx token providers.
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenCache_RefreshesBeforeExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	fetches := 0
+	cache := NewTokenCache(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("token-%d", fetches), now.Add(time.Minute), nil
+	}, 10*time.Second)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if got, _ := cache.Token(context.Background()); got != "token-1" {
+			t.Fatalf("Token() = %q, want cached token-1", got)
+		}
+	}
+
+	// Within the skew before expiry the token is replaced
+	now = now.Add(55 * time.Second)
+	if got, _ := cache.Token(context.Background()); got != "token-2" {
+		t.Errorf("Token() = %q, want token-2 near expiry", got)
+	}
+}
+
+func TestTokenCache_RejectedTokenRefreshedOnce(t *testing.T) {
+	var fetches atomic.Int32
+	cache := NewTokenCache(func(ctx context.Context) (string, time.Time, error) {
+		return fmt.Sprintf("token-%d", fetches.Add(1)), time.Time{}, nil
+	}, 0)
+
+	first, _ := cache.Token(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, _ := cache.Token(WithTokenRefresh(context.Background(), first)); got != "token-2" {
+				t.Errorf("Token() = %q, want token-2", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches = %d, want 2 for one rejected token", got)
+	}
+}
+
+func TestTokenCache_FetchError(t *testing.T) {
+	fetchErr := errors.New("sts unavailable")
+	cache := NewTokenCache(func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, fetchErr
+	}, 0)
+
+	if _, err := cache.Token(context.Background()); !errors.Is(err, fetchErr) {
+		t.Errorf("Token() error = %v, want %v", err, fetchErr)
+	}
+}
+
+// tokenServer accepts only the token in valid, echoing request bodies.
+func tokenServer(t *testing.T, valid *atomic.Value, seen *[]string) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Internal-Token")
+		mu.Lock()
+		*seen = append(*seen, token)
+		mu.Unlock()
+		if token != valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":"unauthenticated"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTokenTransport_RetriesOnceWithFreshToken(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("token-1")
+	var seen []string
+	server := tokenServer(t, &valid, &seen)
+
+	cache := NewTokenCache(func(ctx context.Context) (string, time.Time, error) {
+		return valid.Load().(string), time.Time{}, nil
+	}, 0)
+	transport := NewTokenTransport(http.DefaultTransport, cache.Token, "X-Internal-Token")
+
+	// Prime the cache, then rotate the token server-side
+	cache.Token(context.Background())
+	valid.Store("token-2")
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+		t.Errorf("response = %d %q, want 200 with the replayed body", resp.StatusCode, body)
+	}
+	if len(seen) != 2 || seen[0] != "token-1" || seen[1] != "token-2" {
+		t.Errorf("tokens sent = %v, want [token-1 token-2]", seen)
+	}
+	if req.Header.Get("X-Internal-Token") != "" {
+		t.Error("caller's request was modified")
+	}
+}
+
+func TestTokenTransport_StaticTokenNotRetried(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("other")
+	var seen []string
+	server := tokenServer(t, &valid, &seen)
+
+	transport := NewTokenTransport(http.DefaultTransport, func(context.Context) (string, error) {
+		return "static", nil
+	}, "X-Internal-Token")
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized || string(body) != `{"code":"unauthenticated"}` {
+		t.Errorf("response = %d %q, want the original 401", resp.StatusCode, body)
+	}
+	if len(seen) != 1 {
+		t.Errorf("requests = %d, want 1 when the token cannot change", len(seen))
+	}
+}
+
+func TestTokenTransport_ProviderError(t *testing.T) {
+	called := false
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	providerErr := errors.New("no credentials")
+	transport := NewTokenTransport(base, func(context.Context) (string, error) {
+		return "", providerErr
+	}, "X-Internal-Token")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.test/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, providerErr) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, providerErr)
+	}
+	if called {
+		t.Error("request was sent without a token")
+	}
+}
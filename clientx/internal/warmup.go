@@ -0,0 +1,68 @@
+// Package internal provides internal implementation details for clientx.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxWarmupDrain bounds how much of a warm-up response body is read before it
+// is closed. Bodies drained to EOF return their connection to the idle pool;
+// larger bodies are closed, which discards the connection.
+const maxWarmupDrain = 64 << 10
+
+// Warmup sends n concurrent GET requests for target through client.
+//
+// All responses are held until every request has completed, so each request
+// needs its own connection (or HTTP/2 stream) and n connections end up in the
+// idle pool. Any HTTP response counts as a successful warm-up, whatever its
+// status; only transport errors, including an open circuit breaker, fail.
+func Warmup(ctx context.Context, client *http.Client, target string, n int) error {
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		wg    sync.WaitGroup
+		resps = make([]*http.Response, n)
+		errs  = make([]error, n)
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			resps[i], errs[i] = client.Do(req)
+		}(i)
+	}
+	wg.Wait()
+
+	var (
+		failed   int
+		firstErr error
+	)
+	for i, resp := range resps {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxWarmupDrain))
+		resp.Body.Close()
+	}
+
+	if firstErr != nil {
+		return fmt.Errorf("warmup: %d of %d connections failed: %w", failed, n, firstErr)
+	}
+	return nil
+}
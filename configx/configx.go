@@ -24,10 +24,12 @@ package configx
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"time"
 
 	"go.eggybyte.com/egg/configx/internal"
+	"go.eggybyte.com/egg/core/clock"
 	"go.eggybyte.com/egg/core/log"
 )
 
@@ -53,6 +55,7 @@ type Manager interface {
 	Value(key string) (string, bool)
 
 	// Bind decodes the configuration into a struct with env tags and default values.
+	// Secret references (file:// and env://) are resolved after merge, before binding.
 	// Supports hot reloading via callback when configuration changes.
 	Bind(target any, opts ...BindOption) error
 
@@ -61,11 +64,70 @@ type Manager interface {
 	OnUpdate(fn func(snapshot map[string]string)) (unsubscribe func())
 }
 
+// ProfileReporter is implemented by managers that report the active
+// environment profile. It is separate from Manager so that existing Manager
+// implementations keep compiling; the manager returned by NewManager
+// implements it.
+//
+// Example:
+//
+//	if reporter, ok := mgr.(configx.ProfileReporter); ok {
+//		logger.Info("config loaded", log.Str("profile", reporter.Profile()))
+//	}
+type ProfileReporter interface {
+	// Profile returns the active environment profile, or "" when profile
+	// layering is disabled or no profile is selected.
+	Profile() string
+}
+
 // Options holds configuration for the manager.
 type Options struct {
 	Logger   log.Logger    // Logger for configuration operations
 	Sources  []Source      // Configuration sources (later sources override earlier ones)
 	Debounce time.Duration // Debounce duration for updates (default: 200ms)
+	Clock    clock.Clock   // Clock for debouncing, e.g. a fake clock in tests (default: system clock)
+
+	// Profile loads a base file and its profile overlay ahead of Sources,
+	// so env vars and flags still override both (nil = disabled)
+	Profile *ProfileOptions
+
+	// AuditChanges logs every applied update that changes the merged
+	// configuration at INFO level: the source of the update, then each
+	// changed key with its kind, source and values. Values of secret keys
+	// (password, secret, token, key, dsn, ...) are logged as "***".
+	AuditChanges bool
+
+	// AuditHook receives every applied update that changes the merged
+	// configuration, redacted like the audit log, e.g. to ship it to an
+	// audit sink. It is called synchronously in update order and should not
+	// block; it is independent of AuditChanges (nil = disabled).
+	AuditHook func(ConfigChange)
+}
+
+// ConfigChange describes an applied configuration update: the source whose
+// update was applied and the changed keys.
+type ConfigChange = internal.ConfigChange
+
+// KeyChange is the change of one key within a ConfigChange.
+type KeyChange = internal.KeyChange
+
+// Kinds of KeyChange.
+const (
+	ChangeAdded   = internal.ChangeAdded
+	ChangeUpdated = internal.ChangeUpdated
+	ChangeRemoved = internal.ChangeRemoved
+)
+
+// ProfileOptions configures environment profile layering. The profile named
+// by EnvVar selects an overlay next to the base file, with the profile
+// inserted before the extension: with APP_ENV=prod, "config.yaml" is
+// overlaid by "config.prod.yaml". Overlay keys win; missing files load as
+// empty.
+type ProfileOptions struct {
+	BaseFile string      // Base configuration file, e.g. "config.yaml" (required)
+	EnvVar   string      // Environment variable selecting the profile (default: APP_ENV)
+	Default  string      // Profile used when EnvVar is unset (empty = base file only)
+	File     FileOptions // Options for the base and overlay file sources
 }
 
 // BindOption configures binding behavior.
@@ -74,7 +136,9 @@ type BindOption interface {
 }
 
 type bindConfig struct {
-	onUpdate func()
+	onUpdate          func()
+	literalSecretRefs bool
+	literalSecretKeys []string
 }
 
 type bindOptionFunc func(*bindConfig)
@@ -90,6 +154,27 @@ func WithUpdateCallback(fn func()) BindOption {
 	})
 }
 
+// WithLiteralSecretRefs disables secret reference resolution for this binding.
+// By default, values of the form "file:///path" bound to a field are replaced by
+// the file content (trailing newlines trimmed) and "env://NAME" by the named
+// environment variable. Use this option when literal values starting with these
+// prefixes must be kept. With keys, only the values of those keys are kept
+// verbatim and references of all other bound keys are still resolved.
+//
+// Example:
+//
+//	// WEBHOOK_URL may legitimately start with env://
+//	err := manager.Bind(&cfg, configx.WithLiteralSecretRefs("WEBHOOK_URL"))
+func WithLiteralSecretRefs(keys ...string) BindOption {
+	return bindOptionFunc(func(cfg *bindConfig) {
+		if len(keys) == 0 {
+			cfg.literalSecretRefs = true
+			return
+		}
+		cfg.literalSecretKeys = append(cfg.literalSecretKeys, keys...)
+	})
+}
+
 // BaseConfig provides common configuration fields for all services.
 // All fields are read from environment variables only.
 type BaseConfig struct {
@@ -146,7 +231,8 @@ func (c *BaseConfig) GetMetricsPort() string {
 
 // manager wraps the internal manager implementation.
 type manager struct {
-	impl *internal.ManagerImpl
+	impl    *internal.ManagerImpl
+	profile string
 }
 
 // NewManager creates a new configuration manager.
@@ -166,14 +252,36 @@ func NewManager(ctx context.Context, opts Options) (Manager, error) {
 		return nil, fmt.Errorf("logger is required")
 	}
 
-	if len(opts.Sources) == 0 {
+	// Profile files come first so that env vars and flags override them
+	var (
+		internalSources []internal.Source
+		profile         string
+	)
+	if opts.Profile != nil {
+		if opts.Profile.BaseFile == "" {
+			return nil, fmt.Errorf("profile base file is required")
+		}
+		var err error
+		profile, err = internal.ResolveProfile(opts.Profile.EnvVar, opts.Profile.Default)
+		if err != nil {
+			return nil, err
+		}
+		internalSources = append(internalSources, NewFileSource(opts.Profile.BaseFile, opts.Profile.File))
+		if profile != "" {
+			overlay := internal.ProfilePath(opts.Profile.BaseFile, profile)
+			internalSources = append(internalSources, NewFileSource(overlay, opts.Profile.File))
+			opts.Logger.Info("configuration profile selected",
+				log.Str("profile", profile), log.Str("overlay", overlay))
+		}
+	}
+
+	if len(internalSources)+len(opts.Sources) == 0 {
 		return nil, fmt.Errorf("at least one source is required")
 	}
 
 	// Convert sources to internal type
-	internalSources := make([]internal.Source, len(opts.Sources))
-	for i, src := range opts.Sources {
-		internalSources[i] = src
+	for _, src := range opts.Sources {
+		internalSources = append(internalSources, src)
 	}
 
 	// Set default debounce duration
@@ -186,13 +294,15 @@ func NewManager(ctx context.Context, opts Options) (Manager, error) {
 	if err != nil {
 		return nil, err
 	}
+	impl.SetClock(opts.Clock)
+	impl.SetAudit(opts.AuditChanges, opts.AuditHook)
 
 	// Initialize the manager
 	if err := impl.Initialize(ctx); err != nil {
 		return nil, err
 	}
 
-	return &manager{impl: impl}, nil
+	return &manager{impl: impl, profile: profile}, nil
 }
 
 // Snapshot returns a copy of the current configuration.
@@ -217,7 +327,9 @@ func (m *manager) Bind(target any, opts ...BindOption) error {
 	}
 
 	return m.impl.Bind(target, internal.BindConfig{
-		OnUpdate: cfg.onUpdate,
+		OnUpdate:          cfg.onUpdate,
+		LiteralSecretRefs: cfg.literalSecretRefs,
+		LiteralSecretKeys: cfg.literalSecretKeys,
 	})
 }
 
@@ -226,6 +338,11 @@ func (m *manager) OnUpdate(fn func(snapshot map[string]string)) func() {
 	return m.impl.OnUpdate(fn)
 }
 
+// Profile returns the active environment profile (see ProfileReporter).
+func (m *manager) Profile() string {
+	return m.profile
+}
+
 // --- Public wrappers for source constructors (delegating to internal) ---
 
 // NewEnvSource creates an environment variable configuration source.
@@ -237,6 +354,28 @@ func NewEnvSource(opts EnvOptions) Source {
 	})
 }
 
+// NewFlagsSource creates a command-line flags configuration source.
+// Only flags explicitly set on the command line are loaded; flags left at
+// their default value do not override other sources. Flag names map to
+// env-style keys ("http-port" becomes "HTTP_PORT"), so flags bind through
+// the same env tags. The flag set must be parsed before the manager is
+// created. Place the source last for defaults < file < env < flags:
+//
+//	fs := flag.NewFlagSet("service", flag.ExitOnError)
+//	fs.String("http-port", "8080", "HTTP port")
+//	_ = fs.Parse(os.Args[1:])
+//	mgr, err := configx.NewManager(ctx, configx.Options{
+//		Logger: logger,
+//		Sources: []configx.Source{
+//			configx.NewFileSource("config.yaml", configx.FileOptions{}),
+//			configx.NewEnvSource(configx.EnvOptions{}),
+//			configx.NewFlagsSource(fs),
+//		},
+//	})
+func NewFlagsSource(fs *flag.FlagSet) Source {
+	return internal.NewFlagsSource(fs)
+}
+
 // NewFileSource creates a file-based configuration source.
 func NewFileSource(path string, opts FileOptions) Source {
 	return internal.NewFileSource(path, internal.FileOptions{
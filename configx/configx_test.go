@@ -3,7 +3,9 @@ package configx
 
 import (
 	"context"
+	"flag"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -135,6 +137,46 @@ func TestManager(t *testing.T) {
 	}
 }
 
+func TestFlagsSource_Precedence(t *testing.T) {
+	t.Setenv("HTTP_PORT", ":7070")
+	t.Setenv("LOG_LEVEL", "warn")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("http-port", ":8081", "HTTP port")
+	fs.String("log-level", "debug", "Log level")
+	if err := fs.Parse([]string{"--http-port=:9090"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	manager, err := NewManager(context.Background(), Options{
+		Logger:  &testLogger{},
+		Sources: []Source{NewEnvSource(EnvOptions{}), NewFlagsSource(fs)},
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	type TestConfig struct {
+		HTTPPort    string `env:"HTTP_PORT" default:":8080"`
+		LogLevel    string `env:"LOG_LEVEL" default:"info"`
+		ServiceName string `env:"FLAGS_TEST_SERVICE_NAME" default:"svc"`
+	}
+	var cfg TestConfig
+	if err := manager.Bind(&cfg); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	if cfg.HTTPPort != ":9090" {
+		t.Errorf("HTTPPort = %q, want :9090 from the set flag", cfg.HTTPPort)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("LogLevel = %q, want warn from env over the defaulted flag", cfg.LogLevel)
+	}
+	if cfg.ServiceName != "svc" {
+		t.Errorf("ServiceName = %q, want svc from the default tag", cfg.ServiceName)
+	}
+}
+
 func TestBuildSources(t *testing.T) {
 	logger := &testLogger{}
 
@@ -237,3 +279,164 @@ func TestOnUpdate(t *testing.T) {
 		t.Errorf("Expected 0 updates, got %d", updateCount)
 	}
 }
+
+// pushSource serves an initial snapshot and publishes the updates sent on ch.
+type pushSource struct {
+	initial map[string]string
+	ch      chan map[string]string
+}
+
+func (s *pushSource) Load(ctx context.Context) (map[string]string, error) { return s.initial, nil }
+
+func (s *pushSource) Watch(ctx context.Context) (<-chan map[string]string, error) { return s.ch, nil }
+
+func TestManager_AuditHook(t *testing.T) {
+	source := &pushSource{
+		initial: map[string]string{"LOG_LEVEL": "info", "API_TOKEN": "old-token"},
+		ch:      make(chan map[string]string),
+	}
+	changes := make(chan ConfigChange, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager, err := NewManager(ctx, Options{
+		Logger:       &testLogger{},
+		Sources:      []Source{source},
+		Debounce:     time.Millisecond,
+		AuditChanges: true,
+		AuditHook:    func(c ConfigChange) { changes <- c },
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	source.ch <- map[string]string{"LOG_LEVEL": "debug", "API_TOKEN": "new-token"}
+	select {
+	case change := <-changes:
+		want := []KeyChange{
+			{Key: "API_TOKEN", Kind: ChangeUpdated, Source: "pushSource", Old: "***", New: "***"},
+			{Key: "LOG_LEVEL", Kind: ChangeUpdated, Source: "pushSource", Old: "info", New: "debug"},
+		}
+		if !reflect.DeepEqual(change.Changes, want) {
+			t.Errorf("Changes = %+v, want %+v", change.Changes, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the audit hook")
+	}
+
+	if got, _ := manager.Value("LOG_LEVEL"); got != "debug" {
+		t.Errorf("LOG_LEVEL = %q, want debug", got)
+	}
+}
+
+func TestManager_Profile(t *testing.T) {
+	dir := t.TempDir()
+	base := dir + "/config.toml"
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	writeFile(base, "SERVICE_NAME = \"base\"\nLOG_LEVEL = \"debug\"\n")
+	writeFile(dir+"/config.prod.toml", "LOG_LEVEL = \"warn\"\n")
+
+	newManager := func() Manager {
+		t.Helper()
+		manager, err := NewManager(context.Background(), Options{
+			Logger:  &testLogger{},
+			Profile: &ProfileOptions{BaseFile: base, Default: "dev"},
+			Sources: []Source{NewEnvSource(EnvOptions{})},
+		})
+		if err != nil {
+			t.Fatalf("NewManager() error = %v", err)
+		}
+		return manager
+	}
+	profile := func(manager Manager) string {
+		t.Helper()
+		reporter, ok := manager.(ProfileReporter)
+		if !ok {
+			t.Fatal("NewManager() result should implement ProfileReporter")
+		}
+		return reporter.Profile()
+	}
+
+	t.Run("overlay wins over base", func(t *testing.T) {
+		t.Setenv("APP_ENV", "prod")
+		manager := newManager()
+		if got := profile(manager); got != "prod" {
+			t.Errorf("Profile() = %q, want prod", got)
+		}
+		if v, _ := manager.Value("LOG_LEVEL"); v != "warn" {
+			t.Errorf("LOG_LEVEL = %q, want warn", v)
+		}
+		if v, _ := manager.Value("SERVICE_NAME"); v != "base" {
+			t.Errorf("SERVICE_NAME = %q, want base", v)
+		}
+	})
+
+	t.Run("env overrides overlay", func(t *testing.T) {
+		t.Setenv("APP_ENV", "prod")
+		t.Setenv("LOG_LEVEL", "error")
+		if v, _ := newManager().Value("LOG_LEVEL"); v != "error" {
+			t.Errorf("LOG_LEVEL = %q, want error", v)
+		}
+	})
+
+	t.Run("default profile without overlay file", func(t *testing.T) {
+		t.Setenv("APP_ENV", "")
+		manager := newManager()
+		if got := profile(manager); got != "dev" {
+			t.Errorf("Profile() = %q, want dev", got)
+		}
+		if v, _ := manager.Value("LOG_LEVEL"); v != "debug" {
+			t.Errorf("LOG_LEVEL = %q, want debug", v)
+		}
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		t.Setenv("APP_ENV", "../prod")
+		_, err := NewManager(context.Background(), Options{
+			Logger:  &testLogger{},
+			Profile: &ProfileOptions{BaseFile: base},
+		})
+		if err == nil {
+			t.Fatal("NewManager() should fail for an invalid profile")
+		}
+	})
+}
+
+func TestManager_ProfileYAML(t *testing.T) {
+	dir := t.TempDir()
+	base := dir + "/config.yaml"
+	if err := os.WriteFile(base, []byte("service_name: base\nlog-level: debug\ndb:\n  max_open: 10\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.WriteFile(dir+"/config.prod.yaml", []byte("log-level: warn\ndb:\n  max_open: 50\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv("APP_ENV", "prod")
+
+	manager, err := NewManager(context.Background(), Options{
+		Logger:  &testLogger{},
+		Profile: &ProfileOptions{BaseFile: base},
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	type Config struct {
+		ServiceName string `env:"SERVICE_NAME"`
+		LogLevel    string `env:"LOG_LEVEL"`
+		MaxOpen     int    `env:"DB_MAX_OPEN"`
+	}
+	var cfg Config
+	if err := manager.Bind(&cfg); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	want := Config{ServiceName: "base", LogLevel: "warn", MaxOpen: 50}
+	if cfg != want {
+		t.Errorf("config = %+v, want %+v (overlay over base)", cfg, want)
+	}
+}
@@ -3,8 +3,10 @@ module go.eggybyte.com/egg/configx
 go 1.25.1
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/go-playground/validator/v10 v10.28.0
 	go.eggybyte.com/egg/core v0.3.3-alpha.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,126 @@
+// Package internal provides internal implementation for the configx package.
+package internal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// Kinds of key changes.
+const (
+	ChangeAdded   = "added"
+	ChangeUpdated = "updated"
+	ChangeRemoved = "removed"
+)
+
+// RedactedValue replaces the values of secret keys in audit records.
+const RedactedValue = "***"
+
+// ConfigChange describes an applied configuration update.
+type ConfigChange struct {
+	Time        time.Time   // When the update was applied
+	Source      string      // Type of the source whose update was applied, e.g. "FileSource"
+	SourceIndex int         // Position of that source in the merge order
+	Changes     []KeyChange // Changed keys, sorted by key
+}
+
+// KeyChange is the change of a single merged key.
+type KeyChange struct {
+	Key    string // Configuration key
+	Kind   string // ChangeAdded, ChangeUpdated or ChangeRemoved
+	Source string // Source providing the new value, or the previous one for removed keys
+	Old    string // Previous value, "" when added; RedactedValue for secret keys
+	New    string // Current value, "" when removed; RedactedValue for secret keys
+}
+
+// Keys returns the changed keys in order.
+func (c ConfigChange) Keys() []string {
+	keys := make([]string, len(c.Changes))
+	for i, change := range c.Changes {
+		keys[i] = change.Key
+	}
+	return keys
+}
+
+// SetAudit enables change auditing: with logChanges every applied update
+// that changes the merged configuration is logged at INFO level, and hook,
+// if not nil, receives it. It must be called before Initialize.
+func (m *ManagerImpl) SetAudit(logChanges bool, hook func(ConfigChange)) {
+	m.auditLog = logChanges
+	m.auditHook = hook
+}
+
+// auditing reports whether applied updates are diffed for auditing.
+func (m *ManagerImpl) auditing() bool {
+	return m.auditLog || m.auditHook != nil
+}
+
+// diffSnapshots returns the key changes from before to after, labelled with
+// the type of the source each value came from.
+func (m *ManagerImpl) diffSnapshots(before, after map[string]string, beforeOrigins, afterOrigins map[string]int) []KeyChange {
+	var changes []KeyChange
+	for key, value := range after {
+		previous, existed := before[key]
+		switch {
+		case !existed:
+			changes = append(changes, KeyChange{Key: key, Kind: ChangeAdded, Source: m.sourceName(afterOrigins[key]),
+				New: auditValue(key, value)})
+		case previous != value:
+			changes = append(changes, KeyChange{Key: key, Kind: ChangeUpdated, Source: m.sourceName(afterOrigins[key]),
+				Old: auditValue(key, previous), New: auditValue(key, value)})
+		}
+	}
+	for key, previous := range before {
+		if _, exists := after[key]; !exists {
+			changes = append(changes, KeyChange{Key: key, Kind: ChangeRemoved, Source: m.sourceName(beforeOrigins[key]),
+				Old: auditValue(key, previous)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// sourceName returns the type name of the source at index i.
+func (m *ManagerImpl) sourceName(i int) string {
+	if i < 0 || i >= len(m.sources) {
+		return ""
+	}
+	return m.getSourceTypeName(m.sources[i])
+}
+
+// audit logs change and passes it to the audit hook.
+func (m *ManagerImpl) audit(change ConfigChange) {
+	if m.auditLog {
+		m.logger.Info("configuration change applied",
+			log.Str("source", change.Source),
+			log.Int("source_index", change.SourceIndex),
+			log.Int("changed_keys", len(change.Changes)),
+			log.Str("keys", strings.Join(change.Keys(), ",")))
+		for _, c := range change.Changes {
+			m.logger.Info("configuration key changed",
+				log.Str("key", c.Key),
+				log.Str("change", c.Kind),
+				log.Str("source", c.Source),
+				log.Str("old", c.Old),
+				log.Str("new", c.New))
+		}
+	}
+	if m.auditHook != nil {
+		m.auditHook(change)
+	}
+}
+
+// auditValue returns value as recorded in audit records: redacted for
+// secret keys, with credentials embedded in URLs masked otherwise.
+func auditValue(key, value string) string {
+	if value == "" {
+		return ""
+	}
+	if isSensitiveKey(key) {
+		return RedactedValue
+	}
+	return maskSensitiveValue(key, value)
+}
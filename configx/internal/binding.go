@@ -21,13 +21,36 @@ type Validator interface {
 // its Validate() method will be called to perform additional validation or
 // post-processing (e.g., parsing structured data from raw strings).
 func BindToStruct(snapshot map[string]string, target any, onUpdate func()) error {
+	return bindTarget(snapshot, target, nil)
+}
+
+// BindToStructWithSecrets binds like BindToStruct, resolving the secret
+// references of the keys the target binds. Values of unbound keys are never
+// read, so a broken reference elsewhere in the snapshot does not fail the
+// binding. Keys for which literal reports true are bound verbatim; a nil
+// literal resolves all bound keys.
+func BindToStructWithSecrets(snapshot map[string]string, target any, onUpdate func(), literal func(key string) bool) error {
+	return bindTarget(snapshot, target, func(key, value string) (string, error) {
+		if literal != nil && literal(key) {
+			return value, nil
+		}
+		return resolveSecretRef(value)
+	})
+}
+
+// valueResolver transforms a value taken from the snapshot before it is set.
+type valueResolver func(key, value string) (string, error)
+
+// bindTarget binds snapshot to target, passing snapshot values through
+// resolve if it is not nil, and runs Validate.
+func bindTarget(snapshot map[string]string, target any, resolve valueResolver) error {
 	targetValue := reflect.ValueOf(target)
 	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("target must be a pointer to struct")
 	}
 
 	// Bind all fields from environment variables
-	if err := bindStructFields(snapshot, targetValue.Elem()); err != nil {
+	if err := bindStructFields(snapshot, targetValue.Elem(), resolve); err != nil {
 		return err
 	}
 
@@ -42,7 +65,7 @@ func BindToStruct(snapshot map[string]string, target any, onUpdate func()) error
 }
 
 // bindStructFields recursively binds configuration values to struct fields.
-func bindStructFields(snapshot map[string]string, structValue reflect.Value) error {
+func bindStructFields(snapshot map[string]string, structValue reflect.Value, resolve valueResolver) error {
 	structType := structValue.Type()
 
 	for i := 0; i < structValue.NumField(); i++ {
@@ -56,7 +79,7 @@ func bindStructFields(snapshot map[string]string, structValue reflect.Value) err
 
 		// Handle nested structs (embedded or regular)
 		if field.Kind() == reflect.Struct {
-			if err := bindStructFields(snapshot, field); err != nil {
+			if err := bindStructFields(snapshot, field, resolve); err != nil {
 				return fmt.Errorf("failed to bind nested struct %s: %w", fieldType.Name, err)
 			}
 			continue
@@ -75,6 +98,12 @@ func bindStructFields(snapshot map[string]string, structValue reflect.Value) err
 		value, exists := snapshot[envTag]
 		if !exists {
 			value = defaultValue
+		} else if resolve != nil {
+			resolved, err := resolve(envTag, value)
+			if err != nil {
+				return fmt.Errorf("failed to resolve secret reference for field %s (key %s): %w", fieldType.Name, envTag, err)
+			}
+			value = resolved
 		}
 
 		// Set field value
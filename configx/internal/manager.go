@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"go.eggybyte.com/egg/core/clock"
 	"go.eggybyte.com/egg/core/log"
 )
 
@@ -18,16 +19,28 @@ type ManagerImpl struct {
 	logger     log.Logger
 	sources    []Source
 	debounce   time.Duration
+	clock      clock.Clock
 	snapshot   map[string]string
+	origins    map[string]int // Index of the source each snapshot value came from
 	mu         sync.RWMutex
 	updateSubs map[int]func(map[string]string)
 	subsMu     sync.RWMutex
 	nextSubID  int
+	auditLog   bool
+	auditHook  func(ConfigChange)
 }
 
 // BindConfig holds bind configuration options.
 type BindConfig struct {
 	OnUpdate func()
+
+	// LiteralSecretRefs disables resolution of file:// and env:// references,
+	// keeping such values verbatim.
+	LiteralSecretRefs bool
+
+	// LiteralSecretKeys keeps the values of these keys verbatim while
+	// references of other keys are still resolved.
+	LiteralSecretKeys []string
 }
 
 // NewManager creates a new configuration manager.
@@ -49,6 +62,7 @@ func NewManager(logger log.Logger, sources []Source, debounce time.Duration) (*M
 		logger:     logger,
 		sources:    sources,
 		debounce:   debounce,
+		clock:      clock.Real(),
 		snapshot:   make(map[string]string),
 		updateSubs: make(map[int]func(map[string]string)),
 	}
@@ -56,6 +70,14 @@ func NewManager(logger log.Logger, sources []Source, debounce time.Duration) (*M
 	return m, nil
 }
 
+// SetClock replaces the clock used to debounce updates. It must be called
+// before Initialize; a nil clock keeps the system clock.
+func (m *ManagerImpl) SetClock(c clock.Clock) {
+	if c != nil {
+		m.clock = c
+	}
+}
+
 // Initialize loads initial configuration and starts watching.
 func (m *ManagerImpl) Initialize(ctx context.Context) error {
 	// Load initial configuration
@@ -74,6 +96,7 @@ func (m *ManagerImpl) Initialize(ctx context.Context) error {
 // loadInitial loads configuration from all sources and merges them.
 func (m *ManagerImpl) loadInitial(ctx context.Context) error {
 	merged := make(map[string]string)
+	origins := make(map[string]int)
 
 	for i, source := range m.sources {
 		snapshot, err := source.Load(ctx)
@@ -89,12 +112,14 @@ func (m *ManagerImpl) loadInitial(ctx context.Context) error {
 		for k, v := range snapshot {
 			if v != "" {
 				merged[k] = v
+				origins[k] = i
 			}
 		}
 	}
 
 	m.mu.Lock()
 	m.snapshot = merged
+	m.origins = origins
 	m.mu.Unlock()
 
 	// Log merged configuration details at DEBUG level
@@ -120,8 +145,7 @@ func (m *ManagerImpl) startWatching(ctx context.Context) error {
 
 // watchSource watches a single source for updates.
 func (m *ManagerImpl) watchSource(ctx context.Context, sourceIndex int, updateChan <-chan map[string]string) {
-	var debounceTimer *time.Timer
-	var pendingUpdate map[string]string
+	var debounceTimer clock.Timer
 
 	for {
 		select {
@@ -137,8 +161,8 @@ func (m *ManagerImpl) watchSource(ctx context.Context, sourceIndex int, updateCh
 				debounceTimer.Stop()
 			}
 
-			pendingUpdate = snapshot
-			debounceTimer = time.AfterFunc(m.debounce, func() {
+			pendingUpdate := snapshot
+			debounceTimer = m.clock.AfterFunc(m.debounce, func() {
 				m.applyUpdate(sourceIndex, pendingUpdate)
 			})
 		}
@@ -148,10 +172,10 @@ func (m *ManagerImpl) watchSource(ctx context.Context, sourceIndex int, updateCh
 // applyUpdate applies a configuration update from a specific source.
 func (m *ManagerImpl) applyUpdate(sourceIndex int, update map[string]string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	// Re-merge all sources with the updated one
 	merged := make(map[string]string)
+	origins := make(map[string]int)
 
 	for i, source := range m.sources {
 		var snapshot map[string]string
@@ -173,15 +197,32 @@ func (m *ManagerImpl) applyUpdate(sourceIndex int, update map[string]string) {
 		for k, v := range snapshot {
 			if v != "" {
 				merged[k] = v
+				origins[k] = i
 			}
 		}
 	}
 
+	var change ConfigChange
+	if m.auditing() {
+		change = ConfigChange{
+			Time:        m.clock.Now(),
+			Source:      m.sourceName(sourceIndex),
+			SourceIndex: sourceIndex,
+			Changes:     m.diffSnapshots(m.snapshot, merged, m.origins, origins),
+		}
+	}
+
 	m.snapshot = merged
+	m.origins = origins
 	m.logger.Info("configuration updated", log.Int("keys", len(merged)))
 
 	// Notify subscribers
 	m.notifySubscribers(merged)
+	m.mu.Unlock()
+
+	if len(change.Changes) > 0 {
+		m.audit(change)
+	}
 }
 
 // notifySubscribers notifies all subscribers of configuration updates.
@@ -226,7 +267,19 @@ func (m *ManagerImpl) Bind(target any, cfg BindConfig) error {
 	}
 
 	snapshot := m.Snapshot()
-	return BindToStruct(snapshot, target, cfg.OnUpdate)
+	if cfg.LiteralSecretRefs {
+		return BindToStruct(snapshot, target, cfg.OnUpdate)
+	}
+
+	var literal func(key string) bool
+	if len(cfg.LiteralSecretKeys) > 0 {
+		keys := make(map[string]bool, len(cfg.LiteralSecretKeys))
+		for _, key := range cfg.LiteralSecretKeys {
+			keys[key] = true
+		}
+		literal = func(key string) bool { return keys[key] }
+	}
+	return BindToStructWithSecrets(snapshot, target, cfg.OnUpdate, literal)
 }
 
 // OnUpdate subscribes to configuration update events.
@@ -368,6 +421,26 @@ func (m *ManagerImpl) getSourceTypeName(source Source) string {
 	return typeName
 }
 
+// sensitiveKeywords mark keys whose values are secret.
+var sensitiveKeywords = []string{
+	"password", "passwd", "pass",
+	"secret", "key", "token",
+	"dsn", "auth", "credential",
+	"api_key", "apikey", "apisecret",
+	"private", "private_key",
+}
+
+// isSensitiveKey reports whether key contains a sensitive keyword.
+func isSensitiveKey(key string) bool {
+	keyLower := strings.ToLower(key)
+	for _, keyword := range sensitiveKeywords {
+		if strings.Contains(keyLower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 // maskSensitiveValue masks sensitive configuration values.
 // Sensitive keys include: password, passwd, secret, key, token, dsn, auth, credential.
 func maskSensitiveValue(key, value string) string {
@@ -377,23 +450,12 @@ func maskSensitiveValue(key, value string) string {
 
 	keyLower := strings.ToLower(key)
 
-	// Check if key contains sensitive keywords
-	sensitiveKeywords := []string{
-		"password", "passwd", "pass",
-		"secret", "key", "token",
-		"dsn", "auth", "credential",
-		"api_key", "apikey", "apisecret",
-		"private", "private_key",
-	}
-
-	for _, keyword := range sensitiveKeywords {
-		if strings.Contains(keyLower, keyword) {
-			// Mask the value, showing only first 4 chars and last 4 chars if long enough
-			if len(value) <= 8 {
-				return "***"
-			}
-			return value[:4] + "***" + value[len(value)-4:]
+	if isSensitiveKey(key) {
+		// Mask the value, showing only first 4 chars and last 4 chars if long enough
+		if len(value) <= 8 {
+			return "***"
 		}
+		return value[:4] + "***" + value[len(value)-4:]
 	}
 
 	// For DSN-like values (containing @), mask the password part
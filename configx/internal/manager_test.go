@@ -3,9 +3,11 @@ package internal
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"go.eggybyte.com/egg/core/clock"
 	"go.eggybyte.com/egg/core/log"
 )
 
@@ -222,6 +224,119 @@ func TestManagerImpl_Initialize(t *testing.T) {
 	}
 }
 
+// chanSource serves a fixed initial snapshot and publishes updates sent on ch.
+type chanSource struct {
+	initial map[string]string
+	ch      chan map[string]string
+}
+
+func (s *chanSource) Load(ctx context.Context) (map[string]string, error) {
+	return s.initial, nil
+}
+
+func (s *chanSource) Watch(ctx context.Context) (<-chan map[string]string, error) {
+	return s.ch, nil
+}
+
+// manualClock records AfterFunc timers; they only run when fired by the test.
+type manualClock struct {
+	mu     sync.Mutex
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	clock   *manualClock
+	d       time.Duration
+	fn      func()
+	stopped bool
+}
+
+func (c *manualClock) Now() time.Time                         { return time.Unix(0, 0) }
+func (c *manualClock) After(d time.Duration) <-chan time.Time { return nil }
+func (c *manualClock) NewTimer(d time.Duration) clock.Timer   { return c.AfterFunc(d, func() {}) }
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{clock: c, d: d, fn: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// waitTimers waits until n timers were created and returns them.
+func (c *manualClock) waitTimers(t *testing.T, n int) []*manualTimer {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		if len(c.timers) >= n {
+			timers := append([]*manualTimer(nil), c.timers...)
+			c.mu.Unlock()
+			return timers
+		}
+		c.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d timers", n)
+	return nil
+}
+
+func (t *manualTimer) C() <-chan time.Time { return nil }
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := !t.stopped
+	t.stopped = true
+	return active
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool { return false }
+
+func TestManagerImpl_DebounceUsesClock(t *testing.T) {
+	source := &chanSource{initial: map[string]string{"LEVEL": "info"}, ch: make(chan map[string]string)}
+	manager, err := NewManager(&mockLogger{}, []Source{source}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	fake := &manualClock{}
+	manager.SetClock(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := manager.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	updates := make(chan map[string]string, 1)
+	manager.OnUpdate(func(snapshot map[string]string) { updates <- snapshot })
+
+	for _, level := range []string{"warn", "debug"} {
+		source.ch <- map[string]string{"LEVEL": level}
+	}
+	timers := fake.waitTimers(t, 2)
+
+	if !timers[0].stopped || timers[1].stopped {
+		t.Fatalf("expected only the latest debounce timer to be active")
+	}
+	if timers[1].d != time.Hour {
+		t.Errorf("debounce = %v, want 1h", timers[1].d)
+	}
+	if got := manager.Snapshot()["LEVEL"]; got != "info" {
+		t.Errorf("snapshot before debounce = %q, want info", got)
+	}
+
+	timers[1].fn()
+	select {
+	case snapshot := <-updates:
+		if snapshot["LEVEL"] != "debug" {
+			t.Errorf("update = %v, want LEVEL=debug", snapshot)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+}
+
 func TestMaskSensitiveValue_Empty(t *testing.T) {
 	result := maskSensitiveValue("key", "")
 	if result != "(empty)" {
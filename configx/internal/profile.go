@@ -0,0 +1,40 @@
+// Package internal provides internal implementation details for configx.
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultProfileEnvVar is the environment variable that selects the profile.
+const DefaultProfileEnvVar = "APP_ENV"
+
+// ResolveProfile returns the profile named by the envVar environment
+// variable, or def when it is unset or blank. Profile names may contain
+// only letters, digits, '-' and '_', so they cannot escape the directory of
+// the base file.
+func ResolveProfile(envVar, def string) (string, error) {
+	if envVar == "" {
+		envVar = DefaultProfileEnvVar
+	}
+	profile := strings.TrimSpace(os.Getenv(envVar))
+	if profile == "" {
+		profile = def
+	}
+	for _, r := range profile {
+		if r != '-' && r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", fmt.Errorf("invalid profile %q from %s: only letters, digits, '-' and '_' are allowed", profile, envVar)
+		}
+	}
+	return profile, nil
+}
+
+// ProfilePath returns the overlay file of profile next to base, with the
+// profile inserted before the extension: "config.yaml" and "prod" give
+// "config.prod.yaml".
+func ProfilePath(base, profile string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "." + profile + ext
+}
@@ -0,0 +1,57 @@
+// Package internal provides tests for configx environment profiles.
+package internal
+
+import "testing"
+
+func TestResolveProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVar  string
+		value   string
+		def     string
+		want    string
+		wantErr bool
+	}{
+		{name: "from default env var", value: "prod", want: "prod"},
+		{name: "custom env var", envVar: "SERVICE_ENV", value: "staging", want: "staging"},
+		{name: "trimmed", value: " dev ", want: "dev"},
+		{name: "default when unset", def: "dev", want: "dev"},
+		{name: "none", want: ""},
+		{name: "path traversal rejected", value: "../secrets", wantErr: true},
+		{name: "dot rejected", value: "prod.eu", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.envVar
+			if name == "" {
+				name = DefaultProfileEnvVar
+			}
+			t.Setenv(name, tt.value)
+
+			got, err := ResolveProfile(tt.envVar, tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfilePath(t *testing.T) {
+	tests := []struct {
+		base, profile, want string
+	}{
+		{"config.yaml", "prod", "config.prod.yaml"},
+		{"/etc/app/config.toml", "staging", "/etc/app/config.staging.toml"},
+		{"conf.d/config", "dev", "conf.d/config.dev"},
+	}
+
+	for _, tt := range tests {
+		if got := ProfilePath(tt.base, tt.profile); got != tt.want {
+			t.Errorf("ProfilePath(%q, %q) = %q, want %q", tt.base, tt.profile, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Package internal provides internal implementation for the configx package.
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// SecretFilePrefix marks a value that should be replaced by the content of a file.
+	SecretFilePrefix = "file://"
+	// SecretEnvPrefix marks a value that should be replaced by another environment variable.
+	SecretEnvPrefix = "env://"
+)
+
+// resolveSecretRef resolves a single value if it is a secret reference.
+// Bind resolves only the keys a target binds; see BindToStructWithSecrets.
+//
+// Values of the form "file:///run/secrets/db_dsn" are replaced by the file content
+// with trailing newlines trimmed. Values of the form "env://DB_DSN" are replaced by
+// the value of the referenced environment variable. All other values are returned as-is.
+func resolveSecretRef(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretFilePrefix):
+		path := strings.TrimPrefix(value, SecretFilePrefix)
+		if path == "" {
+			return "", fmt.Errorf("empty file path")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		if name == "" {
+			return "", fmt.Errorf("empty environment variable name")
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,179 @@
+// Package internal provides tests for configx secret reference resolution.
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveSecretRef_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_dsn")
+	if err := os.WriteFile(path, []byte("user:pass@tcp(db:3306)/app\n\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	resolved, err := resolveSecretRef("file://" + path)
+	if err != nil {
+		t.Fatalf("resolveSecretRef() error = %v", err)
+	}
+	if resolved != "user:pass@tcp(db:3306)/app" {
+		t.Errorf("resolveSecretRef() = %q, want trimmed file content", resolved)
+	}
+
+	if plain, err := resolveSecretRef("value"); err != nil || plain != "value" {
+		t.Errorf("resolveSecretRef(plain) = %q, %v, want %q unchanged", plain, err, "value")
+	}
+}
+
+func TestResolveSecretRef_Env(t *testing.T) {
+	t.Setenv("TEST_SECRET_SOURCE", "s3cr3t")
+
+	resolved, err := resolveSecretRef("env://TEST_SECRET_SOURCE")
+	if err != nil {
+		t.Fatalf("resolveSecretRef() error = %v", err)
+	}
+	if resolved != "s3cr3t" {
+		t.Errorf("resolveSecretRef() = %q, want %q", resolved, "s3cr3t")
+	}
+}
+
+func TestBindToStructWithSecrets_MissingFile(t *testing.T) {
+	type Config struct {
+		DSN string `env:"DB_DSN"`
+	}
+	err := BindToStructWithSecrets(map[string]string{
+		"DB_DSN": "file:///nonexistent/secret",
+	}, &Config{}, nil, nil)
+	if err == nil {
+		t.Fatal("BindToStructWithSecrets() should return error for missing file")
+	}
+	if !strings.Contains(err.Error(), "DB_DSN") {
+		t.Errorf("Error %q should mention the offending key", err.Error())
+	}
+}
+
+func TestBindToStructWithSecrets_MissingEnv(t *testing.T) {
+	type Config struct {
+		Token string `env:"TOKEN"`
+	}
+	err := BindToStructWithSecrets(map[string]string{
+		"TOKEN": "env://TEST_SECRET_UNSET_VARIABLE",
+	}, &Config{}, nil, nil)
+	if err == nil {
+		t.Fatal("BindToStructWithSecrets() should return error for unset variable")
+	}
+	if !strings.Contains(err.Error(), "TOKEN") {
+		t.Errorf("Error %q should mention the offending key", err.Error())
+	}
+}
+
+func TestManagerImpl_Bind_SecretRefs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv("TEST_SECRET_TOKEN", "file://"+path)
+
+	manager, err := NewManager(&mockLogger{}, []Source{NewEnvSource(EnvOptions{})}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.loadInitial(t.Context()); err != nil {
+		t.Fatalf("loadInitial() error = %v", err)
+	}
+
+	type Config struct {
+		Token string `env:"TEST_SECRET_TOKEN"`
+	}
+
+	var cfg Config
+	if err := manager.Bind(&cfg, BindConfig{}); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if cfg.Token != "from-file" {
+		t.Errorf("Token = %q, want %q", cfg.Token, "from-file")
+	}
+
+	var literal Config
+	if err := manager.Bind(&literal, BindConfig{LiteralSecretRefs: true}); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if literal.Token != "file://"+path {
+		t.Errorf("Token = %q, want literal reference", literal.Token)
+	}
+
+	// The stored snapshot must keep the reference, not the secret
+	if v, _ := manager.Value("TEST_SECRET_TOKEN"); v != "file://"+path {
+		t.Errorf("Value() = %q, want unresolved reference", v)
+	}
+}
+
+func TestManagerImpl_Bind_SecretRefsOnlyBoundKeys(t *testing.T) {
+	t.Setenv("TEST_SECRET_BOUND", "env://TEST_SECRET_BOUND_SOURCE")
+	t.Setenv("TEST_SECRET_BOUND_SOURCE", "resolved")
+	t.Setenv("TEST_SECRET_UNRELATED", "file:///nonexistent/secret")
+
+	manager, err := NewManager(&mockLogger{}, []Source{NewEnvSource(EnvOptions{})}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.loadInitial(t.Context()); err != nil {
+		t.Fatalf("loadInitial() error = %v", err)
+	}
+
+	type Config struct {
+		Bound string `env:"TEST_SECRET_BOUND"`
+	}
+	var cfg Config
+	if err := manager.Bind(&cfg, BindConfig{}); err != nil {
+		t.Fatalf("Bind() error = %v, want unbound references ignored", err)
+	}
+	if cfg.Bound != "resolved" {
+		t.Errorf("Bound = %q, want %q", cfg.Bound, "resolved")
+	}
+
+	type Broken struct {
+		Nested struct {
+			Unrelated string `env:"TEST_SECRET_UNRELATED"`
+		}
+	}
+	err = manager.Bind(&Broken{}, BindConfig{})
+	if err == nil {
+		t.Fatal("Bind() should fail for a broken bound reference")
+	}
+	if !strings.Contains(err.Error(), "field Unrelated") || !strings.Contains(err.Error(), "TEST_SECRET_UNRELATED") {
+		t.Errorf("Error %q should name the field and key", err.Error())
+	}
+}
+
+func TestManagerImpl_Bind_LiteralSecretKeys(t *testing.T) {
+	t.Setenv("TEST_SECRET_RESOLVED", "env://TEST_SECRET_LITERAL_SOURCE")
+	t.Setenv("TEST_SECRET_LITERAL", "env://kept-verbatim")
+	t.Setenv("TEST_SECRET_LITERAL_SOURCE", "resolved")
+
+	manager, err := NewManager(&mockLogger{}, []Source{NewEnvSource(EnvOptions{})}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.loadInitial(t.Context()); err != nil {
+		t.Fatalf("loadInitial() error = %v", err)
+	}
+
+	type Config struct {
+		Resolved string `env:"TEST_SECRET_RESOLVED"`
+		Literal  string `env:"TEST_SECRET_LITERAL"`
+	}
+	var cfg Config
+	if err := manager.Bind(&cfg, BindConfig{LiteralSecretKeys: []string{"TEST_SECRET_LITERAL"}}); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if cfg.Resolved != "resolved" {
+		t.Errorf("Resolved = %q, want %q", cfg.Resolved, "resolved")
+	}
+	if cfg.Literal != "env://kept-verbatim" {
+		t.Errorf("Literal = %q, want literal reference", cfg.Literal)
+	}
+}
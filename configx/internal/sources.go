@@ -1,8 +1,8 @@
 // Package internal provides internal implementation details for configx.
 //
 // Overview:
-//   - Responsibility: Implement various configuration sources (Env, File, K8s)
-//   - Key Types: EnvSource, FileSource, K8sConfigMapSource
+//   - Responsibility: Implement various configuration sources (Env, Flags, File, K8s)
+//   - Key Types: EnvSource, FlagsSource, FileSource, K8sConfigMapSource
 //   - Concurrency Model: All sources are safe for concurrent use
 //   - Error Semantics: Sources return errors for initialization and loading failures
 //   - Performance Notes: Sources use efficient watching mechanisms
@@ -15,14 +15,20 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"go.eggybyte.com/egg/core/log"
+	"gopkg.in/yaml.v3"
 )
 
 // EnvOptions configures environment variable source behavior.
@@ -95,6 +101,51 @@ func (s *EnvSource) Watch(ctx context.Context) (<-chan map[string]string, error)
 	return ch, nil
 }
 
+// FlagsSource loads configuration from command-line flags.
+type FlagsSource struct {
+	fs *flag.FlagSet
+}
+
+// NewFlagsSource creates a new command-line flags source.
+func NewFlagsSource(fs *flag.FlagSet) Source {
+	return &FlagsSource{fs: fs}
+}
+
+// Load reads the flags explicitly set on the command line. Flags left at
+// their default value are skipped, so they do not override other sources.
+// Flag names map to env-style keys: "http-port" becomes "HTTP_PORT".
+func (s *FlagsSource) Load(ctx context.Context) (map[string]string, error) {
+	if s.fs == nil {
+		return nil, fmt.Errorf("flag set is nil")
+	}
+	if !s.fs.Parsed() {
+		return nil, fmt.Errorf("flag set %q is not parsed", s.fs.Name())
+	}
+
+	config := make(map[string]string)
+	s.fs.Visit(func(f *flag.Flag) {
+		config[flagKey(f.Name)] = f.Value.String()
+	})
+	return config, nil
+}
+
+// Watch provides a channel that never sends updates for flags.
+// Flags are parsed once at startup.
+func (s *FlagsSource) Watch(ctx context.Context) (<-chan map[string]string, error) {
+	ch := make(chan map[string]string)
+	go func() {
+		defer close(ch)
+		<-ctx.Done()
+	}()
+	return ch, nil
+}
+
+// flagKey converts a flag name to its configuration key by upper-casing it
+// and replacing "-" and "." with "_".
+func flagKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
 // FileOptions configures file source behavior.
 type FileOptions struct {
 	Watch    bool          // Watch file for changes (default: true)
@@ -237,25 +288,125 @@ func parseConfigFile(data []byte, format string) (map[string]string, error) {
 	}
 }
 
-// parseJSONConfig parses JSON configuration (simplified implementation).
+// parseJSONConfig parses a JSON object into flat env-style keys like
+// parseTOMLConfig. Numbers keep their literal form, so 50 stays "50".
 func parseJSONConfig(data []byte) (map[string]string, error) {
-	// This is a simplified implementation
-	// In production, you'd use a proper JSON parser
-	return make(map[string]string), nil
+	config := make(map[string]string)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return config, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	flattenConfig("", raw, config)
+	return config, nil
 }
 
-// parseYAMLConfig parses YAML configuration (simplified implementation).
+// parseYAMLConfig parses a YAML mapping into flat env-style keys like
+// parseTOMLConfig.
 func parseYAMLConfig(data []byte) (map[string]string, error) {
-	// This is a simplified implementation
-	// In production, you'd use a proper YAML parser
-	return make(map[string]string), nil
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	config := make(map[string]string)
+	flattenConfig("", raw, config)
+	return config, nil
 }
 
-// parseTOMLConfig parses TOML configuration (simplified implementation).
+// parseTOMLConfig parses TOML configuration into flat env-style keys.
+// Nested tables are joined with "_" and keys are normalized like flag names
+// (uppercased, "-" and "." replaced by "_"), so that [db] max-open = 50
+// yields DB_MAX_OPEN, matching the env tags used for binding.
 func parseTOMLConfig(data []byte) (map[string]string, error) {
-	// This is a simplified implementation
-	// In production, you'd use a proper TOML parser
-	return make(map[string]string), nil
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse toml: %w", err)
+	}
+
+	config := make(map[string]string)
+	flattenConfig("", raw, config)
+	return config, nil
+}
+
+// flattenConfig flattens decoded TOML, JSON or YAML maps into env-style keys.
+// Arrays of scalars are joined with commas. Arrays holding tables or arrays,
+// such as [[servers]], are flattened per element with a zero-based index, so
+// the host of the second server yields SERVERS_1_HOST. Other scalars use
+// their canonical string form.
+func flattenConfig(prefix string, raw map[string]any, out map[string]string) {
+	for k, v := range raw {
+		key := flagKey(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		flattenValue(key, v, out)
+	}
+}
+
+// flattenValue stores v under key, descending into tables and arrays.
+func flattenValue(key string, v any, out map[string]string) {
+	switch val := v.(type) {
+	case map[string]any:
+		flattenConfig(key, val, out)
+	case map[any]any:
+		// YAML mappings with non-string keys
+		table := make(map[string]any, len(val))
+		for k, item := range val {
+			table[fmt.Sprint(k)] = item
+		}
+		flattenConfig(key, table, out)
+	case []map[string]any:
+		// Arrays of tables decode to a slice of maps
+		for i, table := range val {
+			flattenConfig(key+"_"+strconv.Itoa(i), table, out)
+		}
+	case []any:
+		if !scalarArray(val) {
+			for i, item := range val {
+				flattenValue(key+"_"+strconv.Itoa(i), item, out)
+			}
+			return
+		}
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = formatConfigValue(item)
+		}
+		out[key] = strings.Join(items, ",")
+	default:
+		out[key] = formatConfigValue(val)
+	}
+}
+
+// scalarArray reports whether items holds no tables or arrays.
+func scalarArray(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]any, map[any]any, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// formatConfigValue converts a decoded scalar to its configuration string form.
+func formatConfigValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
 }
 
 // K8sOptions configures Kubernetes ConfigMap source behavior.
@@ -3,7 +3,10 @@ package internal
 
 import (
 	"context"
+	"flag"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -145,6 +148,51 @@ func TestEnvSource_Watch(t *testing.T) {
 	}
 }
 
+func TestFlagsSource_Load(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("http-port", "8080", "HTTP port")
+	fs.String("log.level", "info", "Log level")
+	fs.Bool("debug", false, "Debug mode")
+	fs.Int("workers", 4, "Worker count")
+	if err := fs.Parse([]string{"--http-port=9090", "--debug", "--workers=4"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	config, err := NewFlagsSource(fs).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]string{
+		"HTTP_PORT": "9090",
+		"DEBUG":     "true",
+		"WORKERS":   "4", // Set explicitly, even though it equals the default
+	}
+	if len(config) != len(want) {
+		t.Errorf("Load() = %v, want %v", config, want)
+	}
+	for key, value := range want {
+		if config[key] != value {
+			t.Errorf("config[%q] = %q, want %q", key, config[key], value)
+		}
+	}
+	if _, ok := config["LOG_LEVEL"]; ok {
+		t.Error("Defaulted flag should not be loaded")
+	}
+}
+
+func TestFlagsSource_Load_NotParsed(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("http-port", "8080", "HTTP port")
+
+	if _, err := NewFlagsSource(fs).Load(context.Background()); err == nil {
+		t.Error("Load() should fail for an unparsed flag set")
+	}
+	if _, err := NewFlagsSource(nil).Load(context.Background()); err == nil {
+		t.Error("Load() should fail for a nil flag set")
+	}
+}
+
 func TestNewFileSource(t *testing.T) {
 	opts := FileOptions{
 		Watch:  true,
@@ -270,8 +318,8 @@ func TestParseConfigFile_SupportedFormats(t *testing.T) {
 
 	for _, format := range formats {
 		t.Run(format, func(t *testing.T) {
-			data := []byte("test data")
-			config, err := parseConfigFile(data, format)
+			// An empty document is valid in every format
+			config, err := parseConfigFile(nil, format)
 			if err != nil {
 				t.Errorf("parseConfigFile(%s) error = %v", format, err)
 			}
@@ -282,6 +330,244 @@ func TestParseConfigFile_SupportedFormats(t *testing.T) {
 	}
 }
 
+func TestParseConfigFile_JSON(t *testing.T) {
+	data := []byte(`{
+	"SERVICE_NAME": "json-service",
+	"log-level": "debug",
+	"port": 8080,
+	"ratio": 0.5,
+	"debug": true,
+	"tags": ["a", "b"],
+	"empty": null,
+	"db": {"dsn": "user:pass@tcp(db:3306)/app", "max_open": 50},
+	"servers": [{"host": "s1"}, {"host": "s2"}]
+}`)
+
+	config, err := parseConfigFile(data, "json")
+	if err != nil {
+		t.Fatalf("parseConfigFile(json) error = %v", err)
+	}
+
+	want := map[string]string{
+		"SERVICE_NAME":   "json-service",
+		"LOG_LEVEL":      "debug",
+		"PORT":           "8080",
+		"RATIO":          "0.5",
+		"DEBUG":          "true",
+		"TAGS":           "a,b",
+		"EMPTY":          "",
+		"DB_DSN":         "user:pass@tcp(db:3306)/app",
+		"DB_MAX_OPEN":    "50",
+		"SERVERS_0_HOST": "s1",
+		"SERVERS_1_HOST": "s2",
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %v, want %v", config, want)
+	}
+}
+
+func TestParseConfigFile_YAML(t *testing.T) {
+	data := []byte(`
+SERVICE_NAME: yaml-service
+log-level: debug
+port: 8080
+debug: true
+tags: [a, b]
+db:
+  dsn: "user:pass@tcp(db:3306)/app"
+  max_open: 50
+servers:
+  - host: s1
+  - host: s2
+    ports:
+      1: http
+`)
+
+	config, err := parseConfigFile(data, "yaml")
+	if err != nil {
+		t.Fatalf("parseConfigFile(yaml) error = %v", err)
+	}
+
+	want := map[string]string{
+		"SERVICE_NAME":      "yaml-service",
+		"LOG_LEVEL":         "debug",
+		"PORT":              "8080",
+		"DEBUG":             "true",
+		"TAGS":              "a,b",
+		"DB_DSN":            "user:pass@tcp(db:3306)/app",
+		"DB_MAX_OPEN":       "50",
+		"SERVERS_0_HOST":    "s1",
+		"SERVERS_1_HOST":    "s2",
+		"SERVERS_1_PORTS_1": "http",
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %v, want %v", config, want)
+	}
+}
+
+func TestParseConfigFile_JSONAndYAMLInvalid(t *testing.T) {
+	for format, data := range map[string]string{
+		"json": `{"port": `,
+		"yaml": "- a list\n- not a mapping\n",
+	} {
+		if _, err := parseConfigFile([]byte(data), format); err == nil {
+			t.Errorf("parseConfigFile(%s) should return error for %q", format, data)
+		}
+	}
+}
+
+func TestParseConfigFile_TOML(t *testing.T) {
+	data := []byte(`
+SERVICE_NAME = "toml-service"
+port = 8080
+debug = true
+tags = ["a", "b"]
+
+[db]
+dsn = "user:pass@tcp(db:3306)/app"
+max_open = 50
+`)
+
+	config, err := parseConfigFile(data, "toml")
+	if err != nil {
+		t.Fatalf("parseConfigFile(toml) error = %v", err)
+	}
+
+	want := map[string]string{
+		"SERVICE_NAME": "toml-service",
+		"PORT":         "8080",
+		"DEBUG":        "true",
+		"TAGS":         "a,b",
+		"DB_DSN":       "user:pass@tcp(db:3306)/app",
+		"DB_MAX_OPEN":  "50",
+	}
+	for k, v := range want {
+		if config[k] != v {
+			t.Errorf("config[%q] = %q, want %q", k, config[k], v)
+		}
+	}
+}
+
+func TestParseConfigFile_TOMLArrayOfTables(t *testing.T) {
+	data := []byte(`
+matrix = [[1, 2], ["a"]]
+peers = [{ host = "p1" }, { host = "p2", port = 9000 }]
+
+[[servers]]
+host = "s1"
+port = 8080
+
+[[servers]]
+host = "s2"
+
+[servers.tls]
+enabled = true
+`)
+
+	config, err := parseConfigFile(data, "toml")
+	if err != nil {
+		t.Fatalf("parseConfigFile(toml) error = %v", err)
+	}
+
+	want := map[string]string{
+		"SERVERS_0_HOST":        "s1",
+		"SERVERS_0_PORT":        "8080",
+		"SERVERS_1_HOST":        "s2",
+		"SERVERS_1_TLS_ENABLED": "true",
+		"PEERS_0_HOST":          "p1",
+		"PEERS_1_HOST":          "p2",
+		"PEERS_1_PORT":          "9000",
+		"MATRIX_0":              "1,2",
+		"MATRIX_1":              "a",
+	}
+	for k, v := range want {
+		if config[k] != v {
+			t.Errorf("config[%q] = %q, want %q", k, config[k], v)
+		}
+	}
+	if len(config) != len(want) {
+		t.Errorf("config = %v, want exactly %v", config, want)
+	}
+}
+
+func TestParseConfigFile_TOMLKeyNormalization(t *testing.T) {
+	data := []byte(`
+log-level = "debug"
+"cache.ttl" = "5m"
+
+[db-primary]
+max-open = 50
+`)
+
+	config, err := parseConfigFile(data, "toml")
+	if err != nil {
+		t.Fatalf("parseConfigFile(toml) error = %v", err)
+	}
+
+	want := map[string]string{
+		"LOG_LEVEL":           "debug",
+		"CACHE_TTL":           "5m",
+		"DB_PRIMARY_MAX_OPEN": "50",
+	}
+	for k, v := range want {
+		if config[k] != v {
+			t.Errorf("config[%q] = %q, want %q", k, config[k], v)
+		}
+	}
+	// Keys must match the flag source, so both bind to the same env tag
+	if got := flagKey("log-level"); config[got] != "debug" {
+		t.Errorf("config[%q] = %q, want key shared with FlagsSource", got, config[got])
+	}
+}
+
+func TestParseConfigFile_TOMLInvalid(t *testing.T) {
+	if _, err := parseConfigFile([]byte("not = [valid"), "toml"); err == nil {
+		t.Fatal("parseConfigFile() should return error for invalid toml")
+	}
+}
+
+func TestFileSource_TOMLMergesWithEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	data := []byte(`
+[db]
+dsn = "file-dsn"
+max_open = 50
+`)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Env source is added last, so it overrides the file
+	t.Setenv("DB_DSN", "env-dsn")
+
+	manager, err := NewManager(&mockLogger{}, []Source{
+		NewFileSource(path, FileOptions{}),
+		NewEnvSource(EnvOptions{}),
+	}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := manager.loadInitial(context.Background()); err != nil {
+		t.Fatalf("loadInitial() error = %v", err)
+	}
+
+	type Config struct {
+		DSN     string `env:"DB_DSN"`
+		MaxOpen int    `env:"DB_MAX_OPEN" default:"10"`
+	}
+
+	var cfg Config
+	if err := manager.Bind(&cfg, BindConfig{}); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if cfg.DSN != "env-dsn" {
+		t.Errorf("DSN = %q, want %q (env overrides file)", cfg.DSN, "env-dsn")
+	}
+	if cfg.MaxOpen != 50 {
+		t.Errorf("MaxOpen = %d, want 50 (from file)", cfg.MaxOpen)
+	}
+}
+
 func TestNewK8sConfigMapSource(t *testing.T) {
 	opts := K8sOptions{
 		Namespace: "test-ns",
@@ -356,4 +642,3 @@ func TestK8sConfigMapSource_Watch(t *testing.T) {
 		t.Error("Channel should close within timeout")
 	}
 }
-
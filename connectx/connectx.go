@@ -17,10 +17,14 @@
 package connectx
 
 import (
+	"context"
+	"math/rand/v2"
 	"net/http"
+	"time"
 
 	"connectrpc.com/connect"
 	"go.eggybyte.com/egg/connectx/internal"
+	"go.eggybyte.com/egg/core/deadline"
 	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/obsx"
 )
@@ -54,23 +58,32 @@ func DefaultHeaderMapping() HeaderMapping {
 
 // Options holds configuration for Connect interceptors.
 type Options struct {
-	Logger            log.Logger     // Logger for interceptor operations
-	Otel              *obsx.Provider // OpenTelemetry provider (nil disables tracing)
-	Headers           HeaderMapping  // Header mapping configuration
-	WithRequestBody   bool           // Log request body (default: false for production)
-	WithResponseBody  bool           // Log response body (default: false for production)
-	SlowRequestMillis int64          // Slow request threshold in milliseconds
-	PayloadAccounting bool           // Track inbound/outbound payload sizes
-	DefaultTimeoutMs  int64          // Default RPC timeout in milliseconds (0 = no timeout)
-	EnableTimeout     bool           // Enable timeout interceptor (default: true)
+	Logger             log.Logger     // Logger for interceptor operations
+	Otel               *obsx.Provider // OpenTelemetry provider (nil disables tracing)
+	Headers            HeaderMapping  // Header mapping configuration
+	WithRequestBody    bool           // Log request body (default: false for production)
+	WithResponseBody   bool           // Log response body (default: false for production)
+	SlowRequestMillis  int64          // Slow request threshold in milliseconds
+	PayloadAccounting  bool           // Track inbound/outbound payload sizes
+	WireSizeAccounting bool           // Also record compressed payload sizes in the RPC metrics
+	DefaultTimeoutMs   int64          // Default RPC timeout in milliseconds (0 = no timeout)
+	TimeoutFunc        func() int64   // Reads the default timeout per request; overrides DefaultTimeoutMs when set
+	EnableTimeout      bool           // Enable timeout interceptor (default: true)
+	DisableRecover     bool           // Let handler panics propagate instead of returning CodeInternal
+
+	// PropagateHeaders lists inbound headers (e.g. a tenant ID or locale) the
+	// identity interceptor stores in the request context for clientx to send
+	// on outbound calls; see core/propagation. Headers not listed are never
+	// propagated.
+	PropagateHeaders []string
 }
 
 // DefaultInterceptors returns a set of interceptors with the given options.
 // The interceptors are ordered for optimal performance and functionality:
-// 1. Recovery (panic handling)
+// 1. Recovery (panic handling, unary and streaming; see RecoverInterceptor)
 // 2. Timeout (service-level + request header override)
 // 3. Identity injection (extract headers to context)
-// 4. Metrics collection (RPC request metrics)
+// 4. Metrics collection (RPC request and stream metrics)
 // 5. Error mapping (core/errors to Connect codes)
 // 6. Logging (structured request/response logging)
 func DefaultInterceptors(opts Options) []connect.Interceptor {
@@ -92,12 +105,14 @@ func DefaultInterceptors(opts Options) []connect.Interceptor {
 	var interceptors []connect.Interceptor
 
 	// Add recovery interceptor
-	if opts.Logger != nil {
-		interceptors = append(interceptors, connect.UnaryInterceptorFunc(internal.RecoveryInterceptor(opts.Logger)))
+	if opts.Logger != nil && !opts.DisableRecover {
+		interceptors = append(interceptors, RecoverInterceptor(opts.Logger))
 	}
 
 	// Add timeout interceptor (before identity/logging to ensure proper deadline propagation)
-	if opts.EnableTimeout || opts.DefaultTimeoutMs > 0 {
+	if opts.TimeoutFunc != nil {
+		interceptors = append(interceptors, connect.UnaryInterceptorFunc(internal.DynamicTimeoutInterceptor(opts.TimeoutFunc)))
+	} else if opts.EnableTimeout || opts.DefaultTimeoutMs > 0 {
 		interceptors = append(interceptors, connect.UnaryInterceptorFunc(internal.TimeoutInterceptor(opts.DefaultTimeoutMs)))
 	}
 
@@ -111,12 +126,15 @@ func DefaultInterceptors(opts Options) []connect.Interceptor {
 		RealIP:        opts.Headers.RealIP,
 		ForwardedFor:  opts.Headers.ForwardedFor,
 		UserAgent:     opts.Headers.UserAgent,
+		Propagate:     opts.PropagateHeaders,
 	})))
 
 	// Add metrics interceptor (if OTEL provider is available)
 	if opts.Otel != nil {
-		if collector, err := internal.NewMetricsCollector(opts.Otel); err == nil {
-			interceptors = append(interceptors, connect.UnaryInterceptorFunc(internal.MetricsInterceptor(collector)))
+		if collector, err := internal.NewMetricsCollectorWithConfig(opts.Otel, internal.MetricsConfig{
+			WireSize: opts.WireSizeAccounting,
+		}); err == nil {
+			interceptors = append(interceptors, internal.MetricsInterceptor(collector))
 		}
 		// Silently skip metrics if initialization fails
 	}
@@ -137,6 +155,125 @@ func DefaultInterceptors(opts Options) []connect.Interceptor {
 	return interceptors
 }
 
+// RecoverInterceptor returns an interceptor that turns handler panics into
+// CodeInternal errors instead of tearing down the request or stream. The panic
+// value, procedure and stack are logged at error level. It covers unary and
+// streaming handlers and is included first in DefaultInterceptors unless
+// Options.DisableRecover is set.
+//
+// Example:
+//
+//	path, handler := userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(connectx.RecoverInterceptor(logger)),
+//	)
+func RecoverInterceptor(logger log.Logger) connect.Interceptor {
+	return internal.NewRecoverInterceptor(logger)
+}
+
+// RequireIdentityInterceptor returns an interceptor that rejects calls to the
+// given procedures with CodeUnauthenticated unless a user identity was
+// extracted from the request headers. Other procedures stay open.
+//
+// Procedures are full Connect paths such as "/user.v1.UserService/GetProfile".
+// Append the interceptor after DefaultInterceptors so it runs once identity has
+// been extracted. Identity extraction covers unary calls only, so protected
+// streaming procedures are always rejected.
+//
+// Example:
+//
+//	interceptors := append(connectx.DefaultInterceptors(opts),
+//	    connectx.RequireIdentityInterceptor([]string{
+//	        userv1connect.UserServiceGetProfileProcedure,
+//	    }),
+//	)
+func RequireIdentityInterceptor(methods []string) connect.Interceptor {
+	return internal.NewRequireIdentityInterceptor(methods)
+}
+
+// MethodFilterOptions selects the procedures a MethodFilterInterceptor lets
+// through. Patterns are full procedures such as "/user.v1.UserService/GetProfile"
+// (leading slash optional), where '*' matches any run of characters except '/'.
+type MethodFilterOptions struct {
+	Allow []string // Only these procedures are served (empty = all)
+	Deny  []string // These procedures are rejected, even if allowed
+}
+
+// MethodFilterInterceptor returns an interceptor that rejects calls to
+// denied procedures, or to procedures missing from a non-empty allow list,
+// with CodePermissionDenied before they reach the handler. It disables RPCs
+// without changing the proto, e.g. admin methods of an internal service
+// exposed behind a shared gateway.
+//
+// The filter is independent of identity and internal-token checks and
+// composes with them; filtered calls are rejected for every caller. It covers
+// unary and streaming handlers.
+//
+// Example:
+//
+//	interceptors := append(connectx.DefaultInterceptors(opts),
+//	    connectx.MethodFilterInterceptor(connectx.MethodFilterOptions{
+//	        Deny: []string{"*/Admin*"},
+//	    }),
+//	)
+func MethodFilterInterceptor(opts MethodFilterOptions) connect.Interceptor {
+	return internal.NewMethodFilterInterceptor(opts.Allow, opts.Deny)
+}
+
+// RemainingDeadline returns the time left until the deadline of the inbound
+// RPC handled with ctx, as recorded by the timeout interceptor, or until the
+// context deadline if that is earlier. The result is negative once the
+// deadline has passed; false is returned if there is no deadline.
+//
+// clientx reads the same value for every outgoing request: it sends it as the
+// X-RPC-Timeout-Ms header, which the downstream timeout interceptor uses to
+// shorten its own timeout, and fails the call without sending it once the
+// deadline has passed. Pass the handler's ctx to clientx calls and cascading
+// timeouts need no further setup.
+//
+// Example:
+//
+//	if remaining, ok := connectx.RemainingDeadline(ctx); ok && remaining < 100*time.Millisecond {
+//	    return nil, connect.NewError(connect.CodeDeadlineExceeded, errors.New("not enough time left"))
+//	}
+func RemainingDeadline(ctx context.Context) (time.Duration, bool) {
+	return deadline.Remaining(ctx)
+}
+
+// AuditRecord describes the outcome of a completed RPC selected for auditing.
+type AuditRecord = internal.AuditRecord
+
+// SampledAuditInterceptor returns an interceptor that samples a fraction of
+// completed RPCs and emits their method, duration, code and user to sink.
+// Rates <= 0 disable auditing; rates >= 1 audit every call.
+// The sink is invoked synchronously on the request path and must be fast;
+// hand records off to a buffered channel for expensive writes.
+//
+// Example:
+//
+//	audit := connectx.SampledAuditInterceptor(0.05, func(r connectx.AuditRecord) {
+//	    auditLog.Info("rpc audit", "procedure", r.Procedure, "user_id", r.UserID, "code", r.Code)
+//	})
+//	interceptors := append(connectx.DefaultInterceptors(opts), audit)
+func SampledAuditInterceptor(rate float64, sink func(AuditRecord)) connect.Interceptor {
+	return internal.SampledAuditInterceptor(rate, rand.Float64, sink)
+}
+
+// Event records a named timing mark on the current request.
+// The logging interceptor measures each event from the previous one (or from
+// request start) and includes the ordered breakdown in slow-request logs, e.g.
+// events="auth: 2ms, db: 35ms, render: 3ms".
+//
+// Events are only recorded inside interceptors that run after the logging
+// interceptor and in handlers; elsewhere Event is a no-op.
+//
+// Example:
+//
+//	user, err := s.repo.Get(ctx, id)
+//	connectx.Event(ctx, "db")
+func Event(ctx context.Context, name string) {
+	internal.RecordEvent(ctx, name)
+}
+
 // Bind is a utility function to bind Connect handlers to HTTP mux.
 // This provides a consistent way to mount Connect services.
 func Bind(mux *http.ServeMux, path string, handler http.Handler) {
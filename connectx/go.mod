@@ -8,6 +8,7 @@ require (
 	go.eggybyte.com/egg/obsx v0.3.3-alpha.2
 	go.opentelemetry.io/otel v1.38.0
 	go.opentelemetry.io/otel/metric v1.38.0
+	google.golang.org/protobuf v1.36.9
 	gorm.io/gorm v1.31.1
 )
 
@@ -34,5 +35,4 @@ require (
 	go.yaml.in/yaml/v2 v2.4.2 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
-	google.golang.org/protobuf v1.36.9 // indirect
 )
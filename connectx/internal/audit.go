@@ -0,0 +1,64 @@
+// Package internal contains Connect interceptor implementations.
+package internal
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/identity"
+)
+
+// AuditRecord describes the outcome of a completed RPC selected for auditing.
+type AuditRecord struct {
+	Procedure string        // Full procedure name (e.g., "/user.v1.UserService/GetUser")
+	UserID    string        // Authenticated user ID from identity context (empty if anonymous)
+	RequestID string        // Request ID from request metadata (empty if absent)
+	Code      string        // "ok" on success, otherwise the Connect code name
+	Duration  time.Duration // Handler execution time
+}
+
+// SampledAuditInterceptor creates an interceptor that emits an AuditRecord to sink
+// for a fraction of completed RPCs. The decision is made after the call completes
+// using rnd, which must return values in [0, 1).
+// Rates <= 0 disable sampling; rates >= 1 audit every call.
+func SampledAuditInterceptor(rate float64, rnd func() float64, sink func(AuditRecord)) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if rate <= 0 || sink == nil {
+				return next(ctx, req)
+			}
+
+			startTime := time.Now()
+			resp, err := next(ctx, req)
+			duration := time.Since(startTime)
+
+			if rate < 1 && rnd() >= rate {
+				return resp, err
+			}
+
+			record := AuditRecord{
+				Procedure: req.Spec().Procedure,
+				Code:      auditCode(err),
+				Duration:  duration,
+			}
+			if userInfo, ok := identity.UserFrom(ctx); ok {
+				record.UserID = userInfo.UserID
+			}
+			if requestMeta, ok := identity.MetaFrom(ctx); ok {
+				record.RequestID = requestMeta.RequestID
+			}
+			sink(record)
+
+			return resp, err
+		}
+	}
+}
+
+// auditCode returns the Connect code name for err, or "ok" for nil.
+func auditCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return connect.CodeOf(err).String()
+}
@@ -0,0 +1,86 @@
+// Package internal provides tests for the sampled audit interceptor.
+package internal
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/identity"
+)
+
+func TestSampledAuditInterceptor_SamplesConfiguredFraction(t *testing.T) {
+	const calls = 10000
+	const rate = 0.1
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	sampled := 0
+	interceptor := SampledAuditInterceptor(rate, rng.Float64, func(AuditRecord) {
+		sampled++
+	})
+
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&struct{}{}), nil
+	})
+
+	for i := 0; i < calls; i++ {
+		if _, err := handler(context.Background(), connect.NewRequest(&struct{}{})); err != nil {
+			t.Fatalf("handler() error = %v", err)
+		}
+	}
+
+	// Expect 1000 ± 10%
+	if sampled < 900 || sampled > 1100 {
+		t.Errorf("sampled = %d of %d calls, want about %d", sampled, calls, int(calls*rate))
+	}
+}
+
+func TestSampledAuditInterceptor_RecordFields(t *testing.T) {
+	var records []AuditRecord
+	interceptor := SampledAuditInterceptor(1, func() float64 { return 0.5 }, func(r AuditRecord) {
+		records = append(records, r)
+	})
+
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return nil, connect.NewError(connect.CodeNotFound, nil)
+	})
+
+	ctx := identity.WithUser(context.Background(), &identity.UserInfo{UserID: "u-1"})
+	ctx = identity.WithMeta(ctx, &identity.RequestMeta{RequestID: "req-1"})
+	if _, err := handler(ctx, connect.NewRequest(&struct{}{})); err == nil {
+		t.Fatal("handler() should propagate the error")
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	r := records[0]
+	if r.UserID != "u-1" {
+		t.Errorf("UserID = %q, want %q", r.UserID, "u-1")
+	}
+	if r.RequestID != "req-1" {
+		t.Errorf("RequestID = %q, want %q", r.RequestID, "req-1")
+	}
+	if r.Code != "not_found" {
+		t.Errorf("Code = %q, want %q", r.Code, "not_found")
+	}
+}
+
+func TestSampledAuditInterceptor_ZeroRate(t *testing.T) {
+	called := false
+	interceptor := SampledAuditInterceptor(0, func() float64 { return 0 }, func(AuditRecord) {
+		called = true
+	})
+
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&struct{}{}), nil
+	})
+	if _, err := handler(context.Background(), connect.NewRequest(&struct{}{})); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+
+	if called {
+		t.Error("sink should not be called when rate is 0")
+	}
+}
@@ -0,0 +1,82 @@
+// Package internal contains Connect interceptor implementations.
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/errors"
+	"go.eggybyte.com/egg/core/identity"
+)
+
+// RequireIdentityInterceptor rejects calls to protected procedures with
+// CodeUnauthenticated unless an identity with a user ID is in the context.
+// Unprotected procedures and client-side calls pass through unchanged.
+//
+// Identity is extracted by IdentityInterceptor, which only covers unary calls,
+// so protected streaming procedures reject every call that was not given an
+// identity by an earlier streaming interceptor.
+type RequireIdentityInterceptor struct {
+	protected map[string]struct{}
+}
+
+// NewRequireIdentityInterceptor creates an interceptor protecting procedures.
+// Procedures are full Connect paths ("/pkg.Service/Method"); a missing
+// leading slash is added.
+func NewRequireIdentityInterceptor(procedures []string) *RequireIdentityInterceptor {
+	protected := make(map[string]struct{}, len(procedures))
+	for _, p := range procedures {
+		if p == "" {
+			continue
+		}
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		protected[p] = struct{}{}
+	}
+	return &RequireIdentityInterceptor{protected: protected}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *RequireIdentityInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !req.Spec().IsClient {
+			if err := i.check(ctx, req.Spec().Procedure); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *RequireIdentityInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *RequireIdentityInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// check returns an unauthenticated error if procedure is protected and ctx
+// carries no user identity.
+//
+// The connect error wraps a core error with the same code, so the code
+// survives ErrorMappingInterceptor when this interceptor runs inside it.
+func (i *RequireIdentityInterceptor) check(ctx context.Context, procedure string) error {
+	if _, ok := i.protected[procedure]; !ok {
+		return nil
+	}
+	if user, ok := identity.UserFrom(ctx); ok && user.UserID != "" {
+		return nil
+	}
+	return connect.NewError(connect.CodeUnauthenticated,
+		errors.New(errors.CodeUnauthenticated, "authentication required"))
+}
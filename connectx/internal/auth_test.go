@@ -0,0 +1,65 @@
+// Package internal provides tests for connectx identity enforcement.
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/identity"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRequireIdentityInterceptor(t *testing.T) {
+	whoami := func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		user, _ := identity.UserFrom(ctx)
+		if user == nil {
+			return connect.NewResponse(wrapperspb.String("anonymous")), nil
+		}
+		return connect.NewResponse(wrapperspb.String(user.UserID)), nil
+	}
+
+	// Same order as appending to DefaultInterceptors: extraction and error
+	// mapping run before the requirement.
+	interceptors := connect.WithInterceptors(
+		connect.UnaryInterceptorFunc(IdentityInterceptor(HeaderMapping{UserID: "X-User-Id"})),
+		connect.UnaryInterceptorFunc(ErrorMappingInterceptor()),
+		NewRequireIdentityInterceptor([]string{"test.v1.UserService/GetProfile"}),
+	)
+	mux := http.NewServeMux()
+	for _, procedure := range []string{"/test.v1.UserService/GetProfile", "/test.v1.UserService/GetPublicInfo"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, whoami, interceptors))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	call := func(procedure, userID string) (string, error) {
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+procedure)
+		req := connect.NewRequest(wrapperspb.String(""))
+		if userID != "" {
+			req.Header().Set("X-User-Id", userID)
+		}
+		resp, err := client.CallUnary(context.Background(), req)
+		if err != nil {
+			return "", err
+		}
+		return resp.Msg.GetValue(), nil
+	}
+
+	_, err := call("/test.v1.UserService/GetProfile", "")
+	if connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("protected call without identity error = %v, want CodeUnauthenticated", err)
+	}
+
+	got, err := call("/test.v1.UserService/GetProfile", "u-42")
+	if err != nil || got != "u-42" {
+		t.Errorf("protected call with identity = %q, %v; want u-42", got, err)
+	}
+
+	got, err = call("/test.v1.UserService/GetPublicInfo", "")
+	if err != nil || got != "anonymous" {
+		t.Errorf("public call without identity = %q, %v; want anonymous", got, err)
+	}
+}
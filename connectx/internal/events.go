@@ -0,0 +1,67 @@
+// Package internal contains Connect interceptor implementations.
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type eventRecorderKey struct{}
+
+// RequestEvent is a named timestamp recorded while handling a request.
+// Elapsed is the time since the previous event (or request start for the first).
+type RequestEvent struct {
+	Name    string
+	Elapsed time.Duration
+}
+
+// EventRecorder collects ordered timing events for a single request.
+// It is safe for concurrent use by goroutines serving the same request.
+type EventRecorder struct {
+	mu     sync.Mutex
+	last   time.Time
+	events []RequestEvent
+}
+
+// WithEventRecorder attaches a new recorder to ctx, measuring from start.
+func WithEventRecorder(ctx context.Context, start time.Time) (context.Context, *EventRecorder) {
+	rec := &EventRecorder{last: start}
+	return context.WithValue(ctx, eventRecorderKey{}, rec), rec
+}
+
+// RecordEvent marks the end of the phase called name on the request's recorder.
+// It is a no-op when ctx carries no recorder.
+func RecordEvent(ctx context.Context, name string) {
+	rec, ok := ctx.Value(eventRecorderKey{}).(*EventRecorder)
+	if !ok {
+		return
+	}
+	rec.record(name, time.Now())
+}
+
+func (r *EventRecorder) record(name string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, RequestEvent{Name: name, Elapsed: at.Sub(r.last)})
+	r.last = at
+}
+
+// Events returns a copy of the recorded events in order.
+func (r *EventRecorder) Events() []RequestEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RequestEvent(nil), r.events...)
+}
+
+// Summary formats the events as "auth: 2ms, handler: 40ms".
+// Returns an empty string when no events were recorded.
+func (r *EventRecorder) Summary() string {
+	events := r.Events()
+	parts := make([]string, len(events))
+	for i, e := range events {
+		parts[i] = e.Name + ": " + e.Elapsed.Round(time.Microsecond).String()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+// Package internal provides tests for request timing events.
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/log"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	kv    []any
+}
+
+// field returns the value of key from structured kv pairs built with log.Str and friends.
+func (r logRecord) field(key string) (any, bool) {
+	for _, kv := range r.kv {
+		if pair, ok := kv.([]any); ok && len(pair) == 2 && pair[0] == key {
+			return pair[1], true
+		}
+	}
+	return nil, false
+}
+
+// recordingLogger captures log records for assertions.
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *recordingLogger) add(level, msg string, kv []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, logRecord{level: level, msg: msg, kv: kv})
+}
+
+func (l *recordingLogger) With(kv ...any) log.Logger              { return l }
+func (l *recordingLogger) Debug(msg string, kv ...any)            { l.add("DEBUG", msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...any)             { l.add("INFO", msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...any)             { l.add("WARN", msg, kv) }
+func (l *recordingLogger) Error(err error, msg string, kv ...any) { l.add("ERROR", msg, kv) }
+
+func (l *recordingLogger) find(msg string) (logRecord, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, r := range l.records {
+		if r.msg == msg {
+			return r, true
+		}
+	}
+	return logRecord{}, false
+}
+
+func TestLoggingInterceptor_SlowRequestEvents(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := LoggingInterceptor(logger, LoggingOptions{SlowRequestMillis: 5})
+
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		time.Sleep(2 * time.Millisecond)
+		RecordEvent(ctx, "auth")
+		time.Sleep(5 * time.Millisecond)
+		RecordEvent(ctx, "handler")
+		return nil, nil
+	})
+
+	if _, err := handler(context.Background(), connect.NewRequest(&struct{}{})); err != nil {
+		t.Fatalf("handler() error = %v", err)
+	}
+
+	record, ok := logger.find("slow request")
+	if !ok {
+		t.Fatal("slow request was not logged")
+	}
+	if record.level != "WARN" {
+		t.Errorf("slow request logged at %s, want WARN", record.level)
+	}
+
+	value, ok := record.field("events")
+	if !ok {
+		t.Fatal("slow request log has no events field")
+	}
+	events := value.(string)
+
+	authIdx := strings.Index(events, "auth: ")
+	handlerIdx := strings.Index(events, "handler: ")
+	if authIdx < 0 || handlerIdx < 0 {
+		t.Fatalf("events = %q, want auth and handler entries", events)
+	}
+	if authIdx > handlerIdx {
+		t.Errorf("events = %q, want auth before handler", events)
+	}
+
+	for _, part := range strings.Split(events, ", ") {
+		name, dur, _ := strings.Cut(part, ": ")
+		d, err := time.ParseDuration(dur)
+		if err != nil {
+			t.Errorf("event %s duration %q is not a duration: %v", name, dur, err)
+			continue
+		}
+		if d <= 0 {
+			t.Errorf("event %s duration = %v, want > 0", name, d)
+		}
+	}
+}
+
+func TestLoggingInterceptor_FastRequestNotSlow(t *testing.T) {
+	logger := &recordingLogger{}
+	interceptor := LoggingInterceptor(logger, LoggingOptions{SlowRequestMillis: 1000})
+
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		RecordEvent(ctx, "handler")
+		return nil, nil
+	})
+	handler(context.Background(), connect.NewRequest(&struct{}{}))
+
+	if _, ok := logger.find("slow request"); ok {
+		t.Error("fast request should not be logged as slow")
+	}
+}
+
+func TestRecordEvent_NoRecorder(t *testing.T) {
+	// Must not panic without a recorder in the context
+	RecordEvent(context.Background(), "orphan")
+}
+
+func TestEventRecorder_Summary(t *testing.T) {
+	start := time.Now()
+	_, rec := WithEventRecorder(context.Background(), start)
+	rec.record("auth", start.Add(2*time.Millisecond))
+	rec.record("handler", start.Add(42*time.Millisecond))
+
+	if got, want := rec.Summary(), "auth: 2ms, handler: 40ms"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+// Package internal contains Connect interceptor implementations.
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/errors"
+)
+
+// MethodFilterInterceptor rejects calls to procedures that are denied, or
+// not allowed when an allow list is set, with CodePermissionDenied before
+// they reach the handler. Deny takes precedence over Allow. Client-side calls
+// pass through unchanged.
+type MethodFilterInterceptor struct {
+	allow []string
+	deny  []string
+}
+
+// NewMethodFilterInterceptor creates a filter from procedure patterns.
+// Patterns match full procedures ("pkg.Service/Method", leading slash
+// optional); '*' matches any run of characters except '/', so
+// "*/Admin*" matches Admin methods of every service and "pkg.Service/*"
+// every method of one service. Empty patterns are ignored.
+func NewMethodFilterInterceptor(allow, deny []string) *MethodFilterInterceptor {
+	return &MethodFilterInterceptor{
+		allow: normalizePatterns(allow),
+		deny:  normalizePatterns(deny),
+	}
+}
+
+// normalizePatterns drops empty patterns and leading slashes.
+func normalizePatterns(patterns []string) []string {
+	normalized := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimPrefix(p, "/"); p != "" {
+			normalized = append(normalized, p)
+		}
+	}
+	return normalized
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *MethodFilterInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !req.Spec().IsClient {
+			if err := i.check(req.Spec().Procedure); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *MethodFilterInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *MethodFilterInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.check(conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// check returns a permission denied error if procedure is filtered out.
+//
+// The connect error wraps a core error with the same code, so the code
+// survives ErrorMappingInterceptor when this interceptor runs inside it.
+func (i *MethodFilterInterceptor) check(procedure string) error {
+	procedure = strings.TrimPrefix(procedure, "/")
+	if matchAny(i.deny, procedure) || (len(i.allow) > 0 && !matchAny(i.allow, procedure)) {
+		return connect.NewError(connect.CodePermissionDenied,
+			errors.New(errors.CodePermissionDenied, "method is disabled"))
+	}
+	return nil
+}
+
+// matchAny reports whether procedure matches one of the patterns.
+func matchAny(patterns []string, procedure string) bool {
+	for _, p := range patterns {
+		if matchProcedure(p, procedure) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProcedure matches procedure against pattern, where '*' matches any
+// run of characters except '/'. Matching backtracks to the most recent '*'
+// only, which is linear in practice for procedure-sized inputs.
+func matchProcedure(pattern, procedure string) bool {
+	p, s := 0, 0
+	star, mark := -1, 0
+	for s < len(procedure) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, s
+			p++
+		case p < len(pattern) && pattern[p] == procedure[s]:
+			p++
+			s++
+		case star >= 0 && procedure[mark] != '/':
+			// Let the last '*' absorb one more character
+			mark++
+			p, s = star+1, mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
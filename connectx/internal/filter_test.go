@@ -0,0 +1,77 @@
+// Package internal provides tests for connectx method filtering.
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMatchProcedure(t *testing.T) {
+	tests := []struct {
+		pattern, procedure string
+		want               bool
+	}{
+		{"user.v1.UserService/GetProfile", "user.v1.UserService/GetProfile", true},
+		{"user.v1.UserService/GetProfile", "user.v1.UserService/GetProfiles", false},
+		{"user.v1.UserService/*", "user.v1.UserService/GetProfile", true},
+		{"user.v1.UserService/*", "user.v1.OrderService/GetOrder", false},
+		{"*/Admin*", "user.v1.UserService/AdminReset", true},
+		{"*/Admin*", "user.v1.UserService/GetAdmin", false},
+		{"*/*Admin*", "user.v1.UserService/GetAdminStats", true},
+		{"*Admin*", "user.v1.AdminService/Reset", false}, // '*' does not cross '/'
+		{"*.AdminService/*", "user.v1.AdminService/Reset", true},
+		{"*", "user.v1.UserService/GetProfile", false},
+		{"*/*", "user.v1.UserService/GetProfile", true},
+	}
+	for _, tt := range tests {
+		if got := matchProcedure(tt.pattern, tt.procedure); got != tt.want {
+			t.Errorf("matchProcedure(%q, %q) = %v, want %v", tt.pattern, tt.procedure, got, tt.want)
+		}
+	}
+}
+
+func TestMethodFilterInterceptor(t *testing.T) {
+	echo := func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		return connect.NewResponse(wrapperspb.String("ok")), nil
+	}
+
+	interceptors := connect.WithInterceptors(
+		connect.UnaryInterceptorFunc(ErrorMappingInterceptor()),
+		NewMethodFilterInterceptor(
+			[]string{"/test.v1.UserService/*"},
+			[]string{"*/Admin*"},
+		),
+	)
+	procedures := []string{
+		"/test.v1.UserService/GetProfile",
+		"/test.v1.UserService/AdminReset",
+		"/test.v1.OrderService/GetOrder",
+	}
+	mux := http.NewServeMux()
+	for _, procedure := range procedures {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, echo, interceptors))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	call := func(procedure string) error {
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+procedure)
+		_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("")))
+		return err
+	}
+
+	if err := call("/test.v1.UserService/GetProfile"); err != nil {
+		t.Errorf("allowed call error = %v, want nil", err)
+	}
+	if err := call("/test.v1.UserService/AdminReset"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("denied call error = %v, want CodePermissionDenied", err)
+	}
+	if err := call("/test.v1.OrderService/GetOrder"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("call outside allow list error = %v, want CodePermissionDenied", err)
+	}
+}
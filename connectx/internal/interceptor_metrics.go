@@ -2,25 +2,46 @@
 package internal
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
+	"io"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"connectrpc.com/connect"
 	"go.eggybyte.com/egg/obsx"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/proto"
 )
 
 // MetricsCollector holds OpenTelemetry metrics instruments for RPC monitoring.
 type MetricsCollector struct {
-	requestsTotal     metric.Int64Counter
-	requestDuration   metric.Float64Histogram
-	requestSizeBytes  metric.Int64Histogram
-	responseSizeBytes metric.Int64Histogram
-	enabled           bool
+	requestsTotal      metric.Int64Counter
+	requestDuration    metric.Float64Histogram
+	requestSizeBytes   metric.Int64Histogram
+	responseSizeBytes  metric.Int64Histogram
+	requestWireBytes   metric.Int64Histogram // nil unless MetricsConfig.WireSize
+	responseWireBytes  metric.Int64Histogram // nil unless MetricsConfig.WireSize
+	streamMessagesSent metric.Int64Counter
+	streamMessagesRecv metric.Int64Counter
+	streamDuration     metric.Float64Histogram
+	enabled            bool
 }
 
+// MetricsConfig configures optional RPC metrics.
+type MetricsConfig struct {
+	// WireSize also records the compressed size of payloads in
+	// rpc_request_wire_size_bytes and rpc_response_wire_size_bytes.
+	WireSize bool
+}
+
+// sizeBuckets are the bucket boundaries of the payload size histograms.
+var sizeBuckets = []float64{64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576}
+
 // NewMetricsCollector creates a new metrics collector for RPC monitoring.
 // If otelProvider is nil, metrics collection is disabled.
 //
@@ -34,6 +55,12 @@ type MetricsCollector struct {
 // Concurrency:
 //   - Safe for concurrent use after initialization
 func NewMetricsCollector(otelProvider *obsx.Provider) (*MetricsCollector, error) {
+	return NewMetricsCollectorWithConfig(otelProvider, MetricsConfig{})
+}
+
+// NewMetricsCollectorWithConfig is like NewMetricsCollector and also creates
+// the optional instruments enabled in config.
+func NewMetricsCollectorWithConfig(otelProvider *obsx.Provider, config MetricsConfig) (*MetricsCollector, error) {
 	if otelProvider == nil {
 		return &MetricsCollector{enabled: false}, nil
 	}
@@ -70,9 +97,7 @@ func NewMetricsCollector(otelProvider *obsx.Provider) (*MetricsCollector, error)
 		"rpc_request_size_bytes",
 		metric.WithDescription("RPC request size in bytes"),
 		metric.WithUnit("By"),
-		metric.WithExplicitBucketBoundaries(
-			64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576,
-		),
+		metric.WithExplicitBucketBoundaries(sizeBuckets...),
 	)
 	if err != nil {
 		return nil, err
@@ -83,8 +108,63 @@ func NewMetricsCollector(otelProvider *obsx.Provider) (*MetricsCollector, error)
 		"rpc_response_size_bytes",
 		metric.WithDescription("RPC response size in bytes"),
 		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(sizeBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create wire size histograms if enabled
+	var requestWireBytes, responseWireBytes metric.Int64Histogram
+	if config.WireSize {
+		requestWireBytes, err = meter.Int64Histogram(
+			"rpc_request_wire_size_bytes",
+			metric.WithDescription("RPC request size in bytes after compression"),
+			metric.WithUnit("By"),
+			metric.WithExplicitBucketBoundaries(sizeBuckets...),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		responseWireBytes, err = meter.Int64Histogram(
+			"rpc_response_wire_size_bytes",
+			metric.WithDescription("RPC response size in bytes after compression"),
+			metric.WithUnit("By"),
+			metric.WithExplicitBucketBoundaries(sizeBuckets...),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create stream message counters
+	streamMessagesSent, err := meter.Int64Counter(
+		"rpc_stream_messages_sent_total",
+		metric.WithDescription("Total number of messages sent on RPC streams"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	streamMessagesRecv, err := meter.Int64Counter(
+		"rpc_stream_messages_received_total",
+		metric.WithDescription("Total number of messages received on RPC streams"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create stream duration histogram; streams live longer than unary calls
+	// Buckets: [0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600]
+	streamDuration, err := meter.Float64Histogram(
+		"rpc_stream_duration_seconds",
+		metric.WithDescription("RPC stream duration in seconds, from open to close"),
+		metric.WithUnit("s"),
 		metric.WithExplicitBucketBoundaries(
-			64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576,
+			0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600,
 		),
 	)
 	if err != nil {
@@ -92,119 +172,340 @@ func NewMetricsCollector(otelProvider *obsx.Provider) (*MetricsCollector, error)
 	}
 
 	return &MetricsCollector{
-		requestsTotal:     requestsTotal,
-		requestDuration:   requestDuration,
-		requestSizeBytes:  requestSizeBytes,
-		responseSizeBytes: responseSizeBytes,
-		enabled:           true,
+		requestsTotal:      requestsTotal,
+		requestDuration:    requestDuration,
+		requestSizeBytes:   requestSizeBytes,
+		responseSizeBytes:  responseSizeBytes,
+		requestWireBytes:   requestWireBytes,
+		responseWireBytes:  responseWireBytes,
+		streamMessagesSent: streamMessagesSent,
+		streamMessagesRecv: streamMessagesRecv,
+		streamDuration:     streamDuration,
+		enabled:            true,
 	}, nil
 }
 
 // MetricsInterceptor creates a Connect interceptor that collects RPC metrics.
-// It records request count, duration, and payload sizes for all RPC calls.
+// It records request count, duration, and payload sizes for all unary calls,
+// and message counts and lifetime for streaming handlers.
 //
 // Parameters:
 //   - collector: metrics collector instance
 //
 // Returns:
-//   - connect.UnaryInterceptorFunc: interceptor function
+//   - connect.Interceptor: interceptor for unary calls and streaming handlers
 //
 // Metrics collected:
-//   - rpc_requests_total: counter of requests by service, method, code
-//   - rpc_request_duration_seconds: histogram of request duration in seconds
-//   - rpc_request_size_bytes: histogram of request payload size in bytes
-//   - rpc_response_size_bytes: histogram of response payload size in bytes
+//   - rpc_requests_total: counter of requests by service, method, code; a stream counts once
+//   - rpc_request_duration_seconds: histogram of unary request duration in seconds
+//   - rpc_request_size_bytes: histogram of request payload size in bytes, by service, method, status
+//   - rpc_response_size_bytes: histogram of response payload size in bytes, by service, method, status
+//   - rpc_request_wire_size_bytes, rpc_response_wire_size_bytes: the same sizes after
+//     compression, if MetricsConfig.WireSize is set
+//   - rpc_stream_messages_sent_total: counter of messages sent on streams, by service and method
+//   - rpc_stream_messages_received_total: counter of messages received on streams, by service and method
+//   - rpc_stream_duration_seconds: histogram of stream duration in seconds, by service, method, code
+//
+// For streams, payload sizes are recorded per message with status "ok", as
+// the stream outcome is unknown when a message passes, and the stream
+// duration is kept out of rpc_request_duration_seconds so long-lived streams
+// do not skew unary latency. Streaming clients are passed through unchanged.
 //
 // Labels:
 //   - rpc_service: service name (e.g., "greet.v1.GreeterService")
 //   - rpc_method: method name (e.g., "SayHello")
 //   - rpc_code: Connect error code (e.g., "ok", "not_found", "internal")
+//   - status: outcome of the call on size histograms, "ok" or "error"
+//
+// Service and method come from the procedure in the request spec, which is
+// fixed by the generated handler or client, never from the request URL, so
+// label cardinality is bounded by the defined procedures. Payload sizes are
+// the protobuf wire size; non-protobuf messages are not recorded. The
+// request size of a failed call is recorded too, and its response size is
+// the size of the error message and details.
+//
+// Wire sizes are the gzip size of a payload when the request headers show
+// the client sent or accepts gzip, the only compression Connect handlers
+// register by default, and the plain size otherwise, so their ratio to the
+// plain sizes is the compression ratio. Envelope framing is not counted.
+// Computing them costs an extra marshal and compression per message.
 //
 // Concurrency:
 //   - Safe for concurrent use
-func MetricsInterceptor(collector *MetricsCollector) connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			if !collector.enabled {
-				return next(ctx, req)
-			}
+func MetricsInterceptor(collector *MetricsCollector) connect.Interceptor {
+	return &metricsInterceptor{collector: collector}
+}
 
-			startTime := time.Now()
-			procedure := req.Spec().Procedure
-
-			// Parse procedure into service and method
-			// Procedure format: "/package.ServiceName/MethodName" or "/ServiceName/MethodName"
-			service, method := parseProcedure(procedure)
-
-			// Record request size if available
-			if reqMsg := req.Any(); reqMsg != nil {
-				// Estimate size based on message (this is approximate)
-				// In production, you might want to use proto.Size() for more accurate sizing
-				reqSize := int64(len(procedure)) // Simplified size estimation
-				collector.requestSizeBytes.Record(ctx, reqSize,
-					metric.WithAttributes(
-						attribute.String("rpc_service", service),
-						attribute.String("rpc_method", method),
-					),
-				)
-			}
+// metricsInterceptor records RPC metrics into a MetricsCollector.
+type metricsInterceptor struct {
+	collector *MetricsCollector
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *metricsInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	collector := i.collector
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if !collector.enabled {
+			return next(ctx, req)
+		}
+
+		startTime := time.Now()
+		procedure := req.Spec().Procedure
+
+		// Parse procedure into service and method
+		// Procedure format: "/package.ServiceName/MethodName" or "/ServiceName/MethodName"
+		service, method := parseProcedure(procedure)
+
+		// Call next handler
+		resp, err := next(ctx, req)
+
+		// Calculate duration in seconds
+		duration := time.Since(startTime).Seconds()
+
+		// Determine error code
+		code := metricsCode(err)
+
+		// Common attributes (label whitelist)
+		attrs := []attribute.KeyValue{
+			attribute.String("rpc_service", service),
+			attribute.String("rpc_method", method),
+			attribute.String("rpc_code", code),
+		}
+
+		// Record metrics
+		collector.requestsTotal.Add(ctx, 1, metric.WithAttributes(attrs...))
+		collector.requestDuration.Record(ctx, duration, metric.WithAttributes(attrs...))
 
-			// Call next handler
-			resp, err := next(ctx, req)
-
-			// Calculate duration in seconds
-			duration := time.Since(startTime).Seconds()
-
-			// Determine error code
-			var code string
-			if err != nil {
-				if connectErr, ok := err.(*connect.Error); ok {
-					code = connectErr.Code().String()
-				} else {
-					code = "unknown"
-				}
-			} else {
-				code = "ok"
+		// Record payload sizes, labelled with the outcome
+		sizeAttrs := metric.WithAttributes(
+			attribute.String("rpc_service", service),
+			attribute.String("rpc_method", method),
+			attribute.String("status", sizeStatus(err)),
+		)
+		reqGzip, respGzip := payloadCompression(req.Peer().Protocol, req.Header(), false)
+		collector.recordSize(ctx, req.Any(), reqGzip, collector.requestSizeBytes, collector.requestWireBytes, sizeAttrs)
+		if err != nil {
+			size := errorSize(err)
+			collector.responseSizeBytes.Record(ctx, size, sizeAttrs)
+			if collector.responseWireBytes != nil {
+				collector.responseWireBytes.Record(ctx, size, sizeAttrs)
 			}
+		} else if resp != nil {
+			// Safely extract response message with panic protection
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						// Silently skip response size recording if panic occurs
+						// This prevents metrics collection from breaking the request flow
+					}
+				}()
+
+				collector.recordSize(ctx, resp.Any(), respGzip, collector.responseSizeBytes, collector.responseWireBytes, sizeAttrs)
+			}()
+		}
+
+		return resp, err
+	}
+}
+
+// recordSize records the size of msg, and its wire size if wire is set.
+func (c *MetricsCollector) recordSize(ctx context.Context, msg any, gzipped bool, plain, wire metric.Int64Histogram, attrs metric.MeasurementOption) {
+	size, ok := messageSize(msg)
+	if !ok {
+		return
+	}
+	plain.Record(ctx, size, attrs)
+	if wire == nil {
+		return
+	}
+	if gzipped {
+		if size, ok = gzipSize(msg.(proto.Message)); !ok {
+			return
+		}
+	}
+	wire.Record(ctx, size, attrs)
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *metricsInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *metricsInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	collector := i.collector
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if !collector.enabled {
+			return next(ctx, conn)
+		}
 
-			// Common attributes (label whitelist)
-			attrs := []attribute.KeyValue{
+		startTime := time.Now()
+		service, method := parseProcedure(conn.Spec().Procedure)
+		recvGzip, sendGzip := payloadCompression(conn.Peer().Protocol, conn.RequestHeader(), true)
+		stream := &metricsStreamConn{
+			StreamingHandlerConn: conn,
+			ctx:                  ctx,
+			collector:            collector,
+			attrs: metric.WithAttributes(
 				attribute.String("rpc_service", service),
 				attribute.String("rpc_method", method),
-				attribute.String("rpc_code", code),
-			}
+			),
+			sizeAttrs: metric.WithAttributes(
+				attribute.String("rpc_service", service),
+				attribute.String("rpc_method", method),
+				attribute.String("status", sizeStatus(nil)),
+			),
+			recvGzip: recvGzip,
+			sendGzip: sendGzip,
+		}
 
-			// Record metrics
-			collector.requestsTotal.Add(ctx, 1, metric.WithAttributes(attrs...))
-			collector.requestDuration.Record(ctx, duration, metric.WithAttributes(attrs...))
-
-			// Record response size if available (with safe nil checks)
-			if resp != nil {
-				// Safely extract response message with panic protection
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							// Silently skip response size recording if panic occurs
-							// This prevents metrics collection from breaking the request flow
-						}
-					}()
-
-					if respMsg := resp.Any(); respMsg != nil {
-						// Estimate size (simplified)
-						respSize := int64(len(procedure)) // Simplified size estimation
-						collector.responseSizeBytes.Record(ctx, respSize,
-							metric.WithAttributes(
-								attribute.String("rpc_service", service),
-								attribute.String("rpc_method", method),
-							),
-						)
-					}
-				}()
-			}
+		err := next(ctx, stream)
+
+		attrs := metric.WithAttributes(
+			attribute.String("rpc_service", service),
+			attribute.String("rpc_method", method),
+			attribute.String("rpc_code", metricsCode(err)),
+		)
+		collector.requestsTotal.Add(ctx, 1, attrs)
+		collector.streamDuration.Record(ctx, time.Since(startTime).Seconds(), attrs)
+		return err
+	}
+}
 
-			return resp, err
+// metricsStreamConn counts and sizes the messages of a streaming handler.
+type metricsStreamConn struct {
+	connect.StreamingHandlerConn
+	ctx       context.Context
+	collector *MetricsCollector
+	attrs     metric.MeasurementOption // Service and method labels
+	sizeAttrs metric.MeasurementOption // Service, method and status labels
+	recvGzip  bool                     // Received messages are gzip-compressed
+	sendGzip  bool                     // Sent messages are gzip-compressed
+}
+
+// Receive implements connect.StreamingHandlerConn.
+func (c *metricsStreamConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	c.collector.streamMessagesRecv.Add(c.ctx, 1, c.attrs)
+	c.collector.recordSize(c.ctx, msg, c.recvGzip, c.collector.requestSizeBytes, c.collector.requestWireBytes, c.sizeAttrs)
+	return nil
+}
+
+// Send implements connect.StreamingHandlerConn.
+func (c *metricsStreamConn) Send(msg any) error {
+	if err := c.StreamingHandlerConn.Send(msg); err != nil {
+		return err
+	}
+	c.collector.streamMessagesSent.Add(c.ctx, 1, c.attrs)
+	c.collector.recordSize(c.ctx, msg, c.sendGzip, c.collector.responseSizeBytes, c.collector.responseWireBytes, c.sizeAttrs)
+	return nil
+}
+
+// metricsCode returns the rpc_code label for an RPC result.
+func metricsCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	if connectErr, ok := err.(*connect.Error); ok {
+		return connectErr.Code().String()
+	}
+	return "unknown"
+}
+
+// sizeStatus returns the status label of the size histograms.
+func sizeStatus(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// errorSize returns the payload size of an error response: the size of its
+// message and details.
+func errorSize(err error) int64 {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return int64(len(err.Error()))
+	}
+	size := len(connectErr.Message())
+	for _, detail := range connectErr.Details() {
+		size += len(detail.Bytes())
+	}
+	return int64(size)
+}
+
+// gzipEncoding is the compression Connect handlers register by default.
+const gzipEncoding = "gzip"
+
+// payloadCompression reports whether the request and response payloads of a
+// call are gzip-compressed, from the inbound request headers. As in
+// Connect's negotiation, the response uses the request compression, or else
+// the first accepted one the handler supports.
+func payloadCompression(protocol string, header http.Header, streaming bool) (request, response bool) {
+	contentKey, acceptKey := "Content-Encoding", "Accept-Encoding"
+	switch {
+	case protocol == connect.ProtocolGRPC || protocol == connect.ProtocolGRPCWeb:
+		contentKey, acceptKey = "Grpc-Encoding", "Grpc-Accept-Encoding"
+	case streaming:
+		contentKey, acceptKey = "Connect-Content-Encoding", "Connect-Accept-Encoding"
+	}
+
+	request = strings.TrimSpace(header.Get(contentKey)) == gzipEncoding
+	if request {
+		return true, true
+	}
+	for _, value := range header.Values(acceptKey) {
+		for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if name == gzipEncoding {
+				return false, true
+			}
 		}
 	}
+	return false, false
+}
+
+// gzipWriters pools gzip writers for gzipSize.
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+
+// gzipSize returns the gzip-compressed size of the protobuf encoding of msg.
+func gzipSize(msg proto.Message) (int64, bool) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return 0, false
+	}
+
+	var counter byteCounter
+	zw := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(zw)
+	zw.Reset(&counter)
+	if _, err := zw.Write(data); err != nil {
+		return 0, false
+	}
+	if err := zw.Close(); err != nil {
+		return 0, false
+	}
+	return int64(counter), true
+}
+
+// byteCounter is an io.Writer that counts the bytes written to it.
+type byteCounter int64
+
+// Write implements io.Writer.
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
+// messageSize returns the protobuf wire size of msg.
+// Returns false if msg is nil or not a protobuf message.
+func messageSize(msg any) (int64, bool) {
+	protoMsg, ok := msg.(proto.Message)
+	if !ok || protoMsg == nil {
+		return 0, false
+	}
+	return int64(proto.Size(protoMsg)), true
 }
 
 // parseProcedure splits a Connect procedure into service and method names.
@@ -214,20 +515,29 @@ func MetricsInterceptor(collector *MetricsCollector) connect.UnaryInterceptorFun
 //   - procedure: full procedure path (e.g., "/user.v1.UserService/CreateUser")
 //
 // Returns:
-//   - service: service name (e.g., "user.v1.UserService")
-//   - method: method name (e.g., "CreateUser")
+//   - service: service name (e.g., "user.v1.UserService"), "unknown" if missing
+//   - method: method name (e.g., "CreateUser"), "unknown" if missing
 func parseProcedure(procedure string) (service, method string) {
 	// Remove leading slash
 	procedure = strings.TrimPrefix(procedure, "/")
 
 	// Split by last slash to separate service from method
-	lastSlash := strings.LastIndex(procedure, "/")
-	if lastSlash == -1 {
+	if lastSlash := strings.LastIndex(procedure, "/"); lastSlash == -1 {
 		// No slash found, treat entire string as method
-		return "", procedure
+		method = procedure
+	} else {
+		service = procedure[:lastSlash]
+		method = procedure[lastSlash+1:]
 	}
 
-	service = procedure[:lastSlash]
-	method = procedure[lastSlash+1:]
+	if service == "" {
+		service = unknownLabel
+	}
+	if method == "" {
+		method = unknownLabel
+	}
 	return service, method
 }
+
+// unknownLabel replaces empty service or method label values.
+const unknownLabel = "unknown"
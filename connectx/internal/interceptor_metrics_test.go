@@ -0,0 +1,308 @@
+// Package internal provides tests for connectx RPC metrics.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/obsx"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMetricsInterceptor_SizeHistogramsByMethod(t *testing.T) {
+	ctx := context.Background()
+	provider, err := obsx.NewProvider(ctx, obsx.Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	collector, err := NewMetricsCollector(provider)
+	if err != nil {
+		t.Fatalf("NewMetricsCollector() error = %v", err)
+	}
+
+	echo := func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		return connect.NewResponse(wrapperspb.String(strings.Repeat(req.Msg.GetValue(), 2))), nil
+	}
+	mux := http.NewServeMux()
+	for _, procedure := range []string{"/test.v1.EchoService/Echo", "/test.v1.EchoService/Shout"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, echo,
+			connect.WithInterceptors(MetricsInterceptor(collector)),
+		))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	call := func(procedure, value string) {
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+procedure)
+		if _, err := client.CallUnary(ctx, connect.NewRequest(wrapperspb.String(value))); err != nil {
+			t.Fatalf("CallUnary(%s) error = %v", procedure, err)
+		}
+	}
+	small := "hi"
+	large := strings.Repeat("x", 4096)
+	call("/test.v1.EchoService/Echo", small)
+	call("/test.v1.EchoService/Shout", large)
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	reqSize := func(v string) int { return proto.Size(wrapperspb.String(v)) }
+	respSize := func(v string) int { return proto.Size(wrapperspb.String(strings.Repeat(v, 2))) }
+
+	want := []string{
+		fmt.Sprintf(`rpc_request_size_bytes_sum{rpc_method="Echo",rpc_service="test.v1.EchoService",status="ok"} %d`, reqSize(small)),
+		fmt.Sprintf(`rpc_request_size_bytes_sum{rpc_method="Shout",rpc_service="test.v1.EchoService",status="ok"} %d`, reqSize(large)),
+		fmt.Sprintf(`rpc_response_size_bytes_sum{rpc_method="Echo",rpc_service="test.v1.EchoService",status="ok"} %d`, respSize(small)),
+		fmt.Sprintf(`rpc_response_size_bytes_sum{rpc_method="Shout",rpc_service="test.v1.EchoService",status="ok"} %d`, respSize(large)),
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics output missing %q", line)
+		}
+	}
+	if t.Failed() {
+		t.Logf("metrics output:\n%s", body)
+	}
+}
+
+func TestMetricsInterceptor_ServerStream(t *testing.T) {
+	ctx := context.Background()
+	provider, err := obsx.NewProvider(ctx, obsx.Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	collector, err := NewMetricsCollector(provider)
+	if err != nil {
+		t.Fatalf("NewMetricsCollector() error = %v", err)
+	}
+
+	const procedure = "/test.v1.EchoService/EchoStream"
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewServerStreamHandler(procedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			for i := 0; i < 3; i++ {
+				if err := stream.Send(req.Msg); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		connect.WithInterceptors(MetricsInterceptor(collector)),
+	))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+procedure)
+	stream, err := client.CallServerStream(ctx, connect.NewRequest(wrapperspb.String("hi")))
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	received := 0
+	for stream.Receive() {
+		received++
+	}
+	if err := stream.Err(); err != nil || received != 3 {
+		t.Fatalf("Received %d messages, err = %v", received, err)
+	}
+	stream.Close()
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	labels := `rpc_method="EchoStream",rpc_service="test.v1.EchoService"`
+	want := []string{
+		`rpc_stream_messages_sent_total{` + labels + `} 3`,
+		`rpc_stream_messages_received_total{` + labels + `} 1`,
+		`rpc_stream_duration_seconds_count{rpc_code="ok",` + labels + `} 1`,
+		`rpc_requests_total{rpc_code="ok",` + labels + `} 1`,
+		fmt.Sprintf(`rpc_response_size_bytes_sum{%s,status="ok"} %d`, labels, 3*proto.Size(wrapperspb.String("hi"))),
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics output missing %q", line)
+		}
+	}
+	if strings.Contains(body, `rpc_request_duration_seconds_count{rpc_code="ok",`+labels) {
+		t.Error("stream should not be recorded in rpc_request_duration_seconds")
+	}
+	if t.Failed() {
+		t.Logf("metrics output:\n%s", body)
+	}
+}
+
+func TestMetricsInterceptor_SizesOnError(t *testing.T) {
+	ctx := context.Background()
+	provider, err := obsx.NewProvider(ctx, obsx.Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	collector, err := NewMetricsCollector(provider)
+	if err != nil {
+		t.Fatalf("NewMetricsCollector() error = %v", err)
+	}
+
+	const procedure = "/test.v1.EchoService/Echo"
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no such value"))
+		},
+		connect.WithInterceptors(MetricsInterceptor(collector)),
+	))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+procedure)
+	if _, err := client.CallUnary(ctx, connect.NewRequest(wrapperspb.String("hi"))); err == nil {
+		t.Fatal("CallUnary() should fail")
+	}
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	labels := `rpc_method="Echo",rpc_service="test.v1.EchoService",status="error"`
+	want := []string{
+		fmt.Sprintf(`rpc_request_size_bytes_sum{%s} %d`, labels, proto.Size(wrapperspb.String("hi"))),
+		fmt.Sprintf(`rpc_response_size_bytes_sum{%s} %d`, labels, len("no such value")),
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics output missing %q", line)
+		}
+	}
+	if strings.Contains(body, "rpc_request_wire_size_bytes") {
+		t.Error("wire size histograms should be disabled by default")
+	}
+	if t.Failed() {
+		t.Logf("metrics output:\n%s", body)
+	}
+}
+
+func TestMetricsInterceptor_WireSize(t *testing.T) {
+	ctx := context.Background()
+	provider, err := obsx.NewProvider(ctx, obsx.Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	collector, err := NewMetricsCollectorWithConfig(provider, MetricsConfig{WireSize: true})
+	if err != nil {
+		t.Fatalf("NewMetricsCollectorWithConfig() error = %v", err)
+	}
+
+	echo := func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		return connect.NewResponse(req.Msg), nil
+	}
+	mux := http.NewServeMux()
+	for _, procedure := range []string{"/test.v1.EchoService/Gzip", "/test.v1.EchoService/Plain"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, echo,
+			connect.WithInterceptors(MetricsInterceptor(collector)),
+		))
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	value := strings.Repeat("compressible ", 256)
+	call := func(httpClient *http.Client, procedure string, opts ...connect.ClientOption) {
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, server.URL+procedure, opts...)
+		if _, err := client.CallUnary(ctx, connect.NewRequest(wrapperspb.String(value))); err != nil {
+			t.Fatalf("CallUnary(%s) error = %v", procedure, err)
+		}
+	}
+	call(server.Client(), "/test.v1.EchoService/Gzip", connect.WithSendGzip())
+	// Without gzip support in connect or the transport, nothing is compressed
+	identity := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	call(identity, "/test.v1.EchoService/Plain", connect.WithAcceptCompression("gzip", nil, nil))
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	plain := proto.Size(wrapperspb.String(value))
+	compressed, ok := gzipSize(wrapperspb.String(value))
+	if !ok || compressed >= int64(plain) {
+		t.Fatalf("gzipSize() = %d, %v; want less than %d", compressed, ok, plain)
+	}
+	labels := func(method string) string {
+		return `rpc_method="` + method + `",rpc_service="test.v1.EchoService",status="ok"`
+	}
+	want := []string{
+		fmt.Sprintf(`rpc_request_size_bytes_sum{%s} %d`, labels("Gzip"), plain),
+		fmt.Sprintf(`rpc_request_wire_size_bytes_sum{%s} %d`, labels("Gzip"), compressed),
+		fmt.Sprintf(`rpc_response_wire_size_bytes_sum{%s} %d`, labels("Gzip"), compressed),
+		fmt.Sprintf(`rpc_request_wire_size_bytes_sum{%s} %d`, labels("Plain"), plain),
+		fmt.Sprintf(`rpc_response_wire_size_bytes_sum{%s} %d`, labels("Plain"), plain),
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics output missing %q", line)
+		}
+	}
+	if t.Failed() {
+		t.Logf("metrics output:\n%s", body)
+	}
+}
+
+func TestPayloadCompression(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocol  string
+		header    http.Header
+		streaming bool
+		request   bool
+		response  bool
+	}{
+		{"connect identity", connect.ProtocolConnect, http.Header{}, false, false, false},
+		{"connect gzip request", connect.ProtocolConnect, http.Header{"Content-Encoding": {"gzip"}}, false, true, true},
+		{"connect accepts gzip", connect.ProtocolConnect, http.Header{"Accept-Encoding": {"br, gzip"}}, false, false, true},
+		{"connect stream", connect.ProtocolConnect, http.Header{"Connect-Content-Encoding": {"gzip"}}, true, true, true},
+		{"connect stream ignores unary header", connect.ProtocolConnect, http.Header{"Content-Encoding": {"gzip"}}, true, false, false},
+		{"grpc", connect.ProtocolGRPC, http.Header{"Grpc-Accept-Encoding": {"gzip"}}, true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, response := payloadCompression(tt.protocol, tt.header, tt.streaming)
+			if request != tt.request || response != tt.response {
+				t.Errorf("payloadCompression() = %v, %v; want %v, %v", request, response, tt.request, tt.response)
+			}
+		})
+	}
+}
+
+func TestParseProcedure(t *testing.T) {
+	tests := []struct {
+		procedure   string
+		wantService string
+		wantMethod  string
+	}{
+		{"/user.v1.UserService/CreateUser", "user.v1.UserService", "CreateUser"},
+		{"/UserService/CreateUser", "UserService", "CreateUser"},
+		{"CreateUser", "unknown", "CreateUser"},
+		{"", "unknown", "unknown"},
+	}
+
+	for _, tt := range tests {
+		service, method := parseProcedure(tt.procedure)
+		if service != tt.wantService || method != tt.wantMethod {
+			t.Errorf("parseProcedure(%q) = (%q, %q), want (%q, %q)",
+				tt.procedure, service, method, tt.wantService, tt.wantMethod)
+		}
+	}
+}
@@ -4,50 +4,37 @@ package internal
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/deadline"
 	"go.eggybyte.com/egg/core/errors"
 	"go.eggybyte.com/egg/core/identity"
 	"go.eggybyte.com/egg/core/log"
+	"go.eggybyte.com/egg/core/propagation"
 	"gorm.io/gorm"
 )
 
-// RecoveryInterceptor creates a recovery interceptor that converts panics to errors.
-func RecoveryInterceptor(logger log.Logger) connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
-			defer func() {
-				if r := recover(); r != nil {
-					// Log panic with procedure for debugging
-					logger.Error(nil, "panic recovered",
-						"panic", fmt.Sprintf("%v", r),
-						"procedure", req.Spec().Procedure)
-
-					// Return internal server error
-					err = connect.NewError(connect.CodeInternal, fmt.Errorf("internal server error: panic recovered"))
-					resp = nil
-				}
-			}()
-			return next(ctx, req)
-		}
-	}
-}
-
 // TimeoutInterceptor creates a timeout interceptor based on service-level configuration.
 // Supports per-request timeout override via X-RPC-Timeout-Ms header (can only reduce, not increase).
+// The effective deadline is recorded with deadline.With so downstream calls can inherit it.
 func TimeoutInterceptor(defaultTimeoutMs int64) connect.UnaryInterceptorFunc {
+	return DynamicTimeoutInterceptor(func() int64 { return defaultTimeoutMs })
+}
+
+// DynamicTimeoutInterceptor is like TimeoutInterceptor but reads the default timeout
+// from defaultTimeoutMs on every request, so it can change at runtime (e.g. on config reload).
+func DynamicTimeoutInterceptor(defaultTimeoutMs func() int64) connect.UnaryInterceptorFunc {
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			timeoutMs := defaultTimeoutMs
+			timeoutMs := defaultTimeoutMs()
 
 			// Check for request header override (can only reduce timeout)
 			if req.Header() != nil {
-				if headerTimeout := req.Header().Get("X-RPC-Timeout-Ms"); headerTimeout != "" {
+				if headerTimeout := req.Header().Get(deadline.Header); headerTimeout != "" {
 					if parsed, err := strconv.ParseInt(headerTimeout, 10, 64); err == nil {
 						if parsed > 0 && parsed < timeoutMs {
 							timeoutMs = parsed
@@ -63,6 +50,11 @@ func TimeoutInterceptor(defaultTimeoutMs int64) connect.UnaryInterceptorFunc {
 				defer cancel()
 			}
 
+			// Record the effective deadline, which may be an earlier one set by the caller
+			if effective, ok := ctx.Deadline(); ok {
+				ctx = deadline.With(ctx, effective)
+			}
+
 			return next(ctx, req)
 		}
 	}
@@ -73,6 +65,7 @@ func LoggingInterceptor(logger log.Logger, opts LoggingOptions) connect.UnaryInt
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
 			startTime := time.Now()
+			ctx, recorder := WithEventRecorder(ctx, startTime)
 
 			// Extract request context for logging
 			var requestContext []any
@@ -118,6 +111,19 @@ func LoggingInterceptor(logger log.Logger, opts LoggingOptions) connect.UnaryInt
 				logger.Info("request completed", fields...)
 			}
 
+			// Log slow requests with the timing breakdown recorded via events
+			if opts.SlowRequestMillis > 0 && duration >= time.Duration(opts.SlowRequestMillis)*time.Millisecond {
+				slowFields := append([]any{
+					log.Str("procedure", req.Spec().Procedure),
+					log.Dur("duration", duration),
+					log.Int64("threshold_ms", opts.SlowRequestMillis),
+				}, requestContext...)
+				if summary := recorder.Summary(); summary != "" {
+					slowFields = append(slowFields, log.Str("events", summary))
+				}
+				logger.Warn("slow request", slowFields...)
+			}
+
 			return resp, err
 		}
 	}
@@ -138,6 +144,7 @@ func IdentityInterceptor(headers HeaderMapping) connect.UnaryInterceptorFunc {
 				if requestMeta != nil {
 					ctx = identity.WithMeta(ctx, requestMeta)
 				}
+				ctx = propagation.With(ctx, propagation.Extract(req.Header(), headers.Propagate))
 			}
 
 			return next(ctx, req)
@@ -178,6 +185,7 @@ type HeaderMapping struct {
 	RealIP        string
 	ForwardedFor  string
 	UserAgent     string
+	Propagate     []string // Headers stored for propagation to outbound calls
 }
 
 // extractIdentityFromHeaders extracts user identity and request metadata from HTTP headers.
@@ -2,12 +2,17 @@
 package internal
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
+	coredeadline "go.eggybyte.com/egg/core/deadline"
 	"go.eggybyte.com/egg/core/errors"
+	"go.eggybyte.com/egg/core/propagation"
 	"gorm.io/gorm"
 )
 
@@ -202,3 +207,71 @@ func TestExtractIdentityFromConnectHeaders_NoUserID(t *testing.T) {
 		t.Fatal("Meta should always be created")
 	}
 }
+
+func TestIdentityInterceptor_PropagateHeaders(t *testing.T) {
+	interceptor := IdentityInterceptor(HeaderMapping{
+		RequestID: "X-Request-Id",
+		Propagate: []string{"x-tenant-id", "Accept-Language"},
+	})
+
+	var propagated http.Header
+	var found bool
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		propagated, found = propagation.From(ctx)
+		return nil, nil
+	})
+
+	req := connect.NewRequest(&struct{}{})
+	req.Header().Set("X-Tenant-Id", "acme")
+	req.Header().Set("Accept-Language", "de")
+	req.Header().Set("Authorization", "Bearer secret")
+	handler(context.Background(), req)
+
+	if !found {
+		t.Fatal("listed headers should be stored for propagation")
+	}
+	if propagated.Get("X-Tenant-Id") != "acme" || propagated.Get("Accept-Language") != "de" {
+		t.Errorf("propagated = %v, want tenant and locale", propagated)
+	}
+	if len(propagated) != 2 {
+		t.Errorf("propagated = %v, want only listed headers", propagated)
+	}
+
+	// Without listed headers on the request nothing is stored
+	handler(context.Background(), connect.NewRequest(&struct{}{}))
+	if found {
+		t.Errorf("propagated = %v, want none", propagated)
+	}
+}
+
+func TestDynamicTimeoutInterceptor(t *testing.T) {
+	var timeoutMs atomic.Int64
+	timeoutMs.Store(1000)
+
+	interceptor := DynamicTimeoutInterceptor(timeoutMs.Load)
+
+	var remaining time.Duration
+	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("context should carry a deadline")
+		}
+		remaining = time.Until(deadline)
+		if recorded, ok := coredeadline.From(ctx); !ok || !recorded.Equal(deadline) {
+			t.Errorf("recorded deadline = %v, %v, want %v", recorded, ok, deadline)
+		}
+		return nil, nil
+	})
+
+	handler(context.Background(), connect.NewRequest(&struct{}{}))
+	if remaining <= 500*time.Millisecond || remaining > time.Second {
+		t.Errorf("remaining = %v, want about 1s", remaining)
+	}
+
+	// The new value applies to subsequent requests without rebuilding the interceptor
+	timeoutMs.Store(100)
+	handler(context.Background(), connect.NewRequest(&struct{}{}))
+	if remaining > 100*time.Millisecond {
+		t.Errorf("remaining = %v after update, want at most 100ms", remaining)
+	}
+}
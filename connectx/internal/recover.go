@@ -0,0 +1,65 @@
+// Package internal contains Connect interceptor implementations.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/core/log"
+)
+
+// RecoverInterceptor converts handler panics into CodeInternal errors for both
+// unary and streaming handlers, logging the panic value and stack.
+// Client-side calls are passed through unchanged.
+type RecoverInterceptor struct {
+	logger log.Logger
+}
+
+// NewRecoverInterceptor creates a recover interceptor logging to logger.
+func NewRecoverInterceptor(logger log.Logger) *RecoverInterceptor {
+	return &RecoverInterceptor{logger: logger}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (i *RecoverInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (resp connect.AnyResponse, err error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(r, req.Spec().Procedure)
+				resp = nil
+			}
+		}()
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (i *RecoverInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (i *RecoverInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = i.recovered(r, conn.Spec().Procedure)
+			}
+		}()
+		return next(ctx, conn)
+	}
+}
+
+// recovered logs a recovered panic and returns the error sent to the client.
+func (i *RecoverInterceptor) recovered(r any, procedure string) error {
+	i.logger.Error(nil, "panic recovered",
+		"panic", fmt.Sprintf("%v", r),
+		"procedure", procedure,
+		"stack", string(debug.Stack()))
+	return connect.NewError(connect.CodeInternal, fmt.Errorf("internal server error: panic recovered"))
+}
@@ -0,0 +1,64 @@
+// Package internal provides tests for the recover interceptor.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRecoverInterceptor(t *testing.T) {
+	logger := &recordingLogger{}
+	recoverOpt := connect.WithInterceptors(NewRecoverInterceptor(logger))
+
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.PanicService/Unary", connect.NewUnaryHandler("/test.v1.PanicService/Unary",
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			panic("unary boom")
+		}, recoverOpt))
+	mux.Handle("/test.v1.PanicService/Stream", connect.NewServerStreamHandler("/test.v1.PanicService/Stream",
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue], stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			stream.Send(wrapperspb.String("first"))
+			panic("stream boom")
+		}, recoverOpt))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+
+	unary := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+"/test.v1.PanicService/Unary")
+	_, err := unary.CallUnary(ctx, connect.NewRequest(wrapperspb.String("hi")))
+	if got := connect.CodeOf(err); got != connect.CodeInternal {
+		t.Errorf("unary code = %v (err %v), want %v", got, err, connect.CodeInternal)
+	}
+
+	streaming := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](server.Client(), server.URL+"/test.v1.PanicService/Stream")
+	stream, err := streaming.CallServerStream(ctx, connect.NewRequest(wrapperspb.String("hi")))
+	if err != nil {
+		t.Fatalf("CallServerStream() error = %v", err)
+	}
+	received := 0
+	for stream.Receive() {
+		received++
+	}
+	var connectErr *connect.Error
+	if !errors.As(stream.Err(), &connectErr) || connectErr.Code() != connect.CodeInternal {
+		t.Errorf("stream error = %v, want CodeInternal", stream.Err())
+	}
+	if received != 1 {
+		t.Errorf("received %d messages before the panic, want 1", received)
+	}
+
+	rec, ok := logger.find("panic recovered")
+	if !ok {
+		t.Fatal("panic was not logged")
+	}
+	if len(rec.kv) < 6 || rec.kv[4] != "stack" {
+		t.Errorf("panic log kv = %v, want panic, procedure and stack", rec.kv)
+	}
+}
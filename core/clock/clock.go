@@ -0,0 +1,94 @@
+// Package clock provides a time abstraction for time-driven code.
+//
+// Overview:
+//   - Responsibility: Let retries, debouncing and timeouts run on a replaceable clock
+//   - Key Types: Clock interface, Timer interface
+//   - Concurrency Model: All implementations must be safe for concurrent use
+//   - Error Semantics: Sleep returns ctx.Err() when the context ends first
+//   - Performance Notes: Real() is a thin wrapper around the time package
+//
+// Usage:
+//
+//	c := clock.Real()
+//	timer := c.AfterFunc(200*time.Millisecond, apply)
+//	err := clock.Sleep(ctx, c, backoff)
+//
+// Tests substitute a fake clock, such as testingx.FakeClock, to advance time
+// without sleeping.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and creates timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for d and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTimer creates a timer that sends the current time on its channel after d.
+	NewTimer(d time.Duration) Timer
+	// AfterFunc waits for d and then calls f in its own goroutine.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a single event created by a Clock.
+type Timer interface {
+	// C returns the channel the time is sent on; nil for AfterFunc timers.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, reporting whether it was active.
+	Stop() bool
+	// Reset changes the timer to fire after d, reporting whether it was active.
+	Reset(d time.Duration) bool
+}
+
+// Real returns the system clock.
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or the system clock if c is nil.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return realClock{}
+	}
+	return c
+}
+
+// Sleep waits for d on clock c or until ctx is done, returning ctx.Err()
+// in the latter case.
+func Sleep(ctx context.Context, c Clock, d time.Duration) error {
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// realClock is the system clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return realTimer{time.AfterFunc(d, f)}
+}
+
+// realTimer wraps a *time.Timer.
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time        { return t.t.C }
+func (t realTimer) Stop() bool                 { return t.t.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
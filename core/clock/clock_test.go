@@ -0,0 +1,68 @@
+// Package clock provides tests for the clock abstraction.
+package clock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRealClock(t *testing.T) {
+	c := Real()
+
+	before := time.Now()
+	if now := c.Now(); now.Before(before) {
+		t.Errorf("Now() = %v, want after %v", now, before)
+	}
+
+	select {
+	case <-c.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("After() did not fire")
+	}
+
+	timer := c.NewTimer(time.Hour)
+	if !timer.Stop() {
+		t.Error("Stop() on an active timer = false, want true")
+	}
+	timer.Reset(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("reset timer did not fire")
+	}
+
+	fired := make(chan struct{})
+	fn := c.AfterFunc(time.Millisecond, func() { close(fired) })
+	if fn.C() != nil {
+		t.Error("AfterFunc timer C() should be nil")
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc() did not run")
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	if OrReal(nil) == nil {
+		t.Fatal("OrReal(nil) returned nil")
+	}
+	c := Real()
+	if OrReal(c) != c {
+		t.Error("OrReal(c) should return c")
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if err := Sleep(context.Background(), Real(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, Real(), time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() with cancelled context error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Package deadline provides inbound RPC deadline propagation through context.
+//
+// Overview:
+//   - Responsibility: Record the deadline of an inbound request and report the time left for downstream calls
+//   - Key Types: Header for the wire format; With, From and Remaining for context storage
+//   - Concurrency Model: All functions are safe for concurrent use
+//   - Error Semantics: Functions return boolean to indicate presence of a deadline
+//   - Performance Notes: Minimal allocations, context-based storage
+//
+// Usage:
+//
+//	ctx = deadline.With(ctx, time.Now().Add(5*time.Second))
+//	if remaining, ok := deadline.Remaining(ctx); ok {
+//		req.Header.Set(deadline.Header, deadline.FormatHeader(remaining))
+//	}
+package deadline
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Header carries the remaining deadline of a call in milliseconds. Servers
+// use it to shorten, never extend, their own request timeout.
+const Header = "X-RPC-Timeout-Ms"
+
+type contextKey struct{}
+
+// With records the deadline of the inbound request handled with ctx.
+// Returns a new context with the deadline attached.
+// If t is zero, returns the context unchanged.
+func With(ctx context.Context, t time.Time) context.Context {
+	if t.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// From retrieves the inbound request deadline recorded with With.
+// Returns the deadline and a boolean indicating if it was found.
+func From(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(contextKey{}).(time.Time)
+	return t, ok
+}
+
+// Remaining returns the time left until the inbound request deadline, or
+// until the context deadline if that is earlier. The result is negative
+// once the deadline has passed. Returns false if neither is set.
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	recorded, ok := From(ctx)
+	if ctxDeadline, hasCtx := ctx.Deadline(); hasCtx && (!ok || ctxDeadline.Before(recorded)) {
+		recorded, ok = ctxDeadline, true
+	}
+	if !ok {
+		return 0, false
+	}
+	return time.Until(recorded), true
+}
+
+// FormatHeader formats a remaining duration as a Header value, rounded up
+// to whole milliseconds so a sub-millisecond budget is not sent as zero.
+func FormatHeader(d time.Duration) string {
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	if ms < 1 {
+		ms = 1
+	}
+	return strconv.FormatInt(int64(ms), 10)
+}
@@ -0,0 +1,61 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithAndFrom(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := From(ctx); ok {
+		t.Fatal("Empty context should have no deadline")
+	}
+	if With(ctx, time.Time{}) != ctx {
+		t.Error("Zero deadline should return the context unchanged")
+	}
+
+	want := time.Now().Add(time.Minute)
+	got, ok := From(With(ctx, want))
+	if !ok || !got.Equal(want) {
+		t.Errorf("From() = %v, %v, want %v", got, ok, want)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	if _, ok := Remaining(context.Background()); ok {
+		t.Error("Context without deadlines should report no remaining time")
+	}
+
+	// Recorded deadline only, e.g. after context.WithoutCancel
+	ctx := With(context.Background(), time.Now().Add(time.Minute))
+	if remaining, ok := Remaining(ctx); !ok || remaining <= 50*time.Second || remaining > time.Minute {
+		t.Errorf("Remaining() = %v, %v, want about 1m", remaining, ok)
+	}
+
+	// The earlier of the recorded and context deadlines wins
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if remaining, ok := Remaining(ctx); !ok || remaining > time.Second {
+		t.Errorf("Remaining() = %v, %v, want at most 1s", remaining, ok)
+	}
+
+	past := With(context.Background(), time.Now().Add(-time.Second))
+	if remaining, ok := Remaining(past); !ok || remaining >= 0 {
+		t.Errorf("Remaining() = %v, %v, want negative", remaining, ok)
+	}
+}
+
+func TestFormatHeader(t *testing.T) {
+	tests := map[time.Duration]string{
+		1500 * time.Millisecond: "1500",
+		1500 * time.Microsecond: "2",
+		time.Microsecond:        "1",
+		-time.Second:            "1",
+	}
+	for d, want := range tests {
+		if got := FormatHeader(d); got != want {
+			t.Errorf("FormatHeader(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
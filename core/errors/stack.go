@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStackDepth bounds the frames captured for an error.
+const maxStackDepth = 32
+
+// Stack is a call stack as program counters, innermost call first.
+type Stack []uintptr
+
+// StackTracer is implemented by errors that carry the stack of the call
+// that created them.
+type StackTracer interface {
+	StackTrace() Stack
+}
+
+// Callers captures up to depth frames of the calling goroutine's stack,
+// starting skip frames above the caller of Callers.
+//
+// Example:
+//
+//	stack := errors.Callers(0, 16) // starts at the function calling Callers
+func Callers(skip, depth int) Stack {
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	return Stack(pcs[:n])
+}
+
+// Frames returns up to depth frames of the stack, all of them when depth
+// is zero or negative.
+func (s Stack) Frames(depth int) []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	var frames []runtime.Frame
+	iter := runtime.CallersFrames(s)
+	for depth <= 0 || len(frames) < depth {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// String formats the stack like a goroutine trace: each function
+// followed by its indented file and line.
+func (s Stack) String() string {
+	var b strings.Builder
+	for i, frame := range s.Frames(0) {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
+
+// StackOf returns the innermost stack carried by err or the errors it
+// wraps, so the stack points at where the failure originated. It returns
+// nil if no error in the chain carries a stack.
+//
+// Example:
+//
+//	if stack := errors.StackOf(err); stack != nil {
+//		fmt.Println(stack)
+//	}
+func StackOf(err error) Stack {
+	var stack Stack
+	for err != nil {
+		if st, ok := err.(StackTracer); ok {
+			if s := st.StackTrace(); len(s) > 0 {
+				stack = s
+			}
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				if s := StackOf(inner); s != nil {
+					return s
+				}
+			}
+			return stack
+		default:
+			return stack
+		}
+	}
+	return stack
+}
+
+// WithStack returns err annotated with the stack of the call to WithStack,
+// regardless of SetStackCapture. Errors that already carry a stack are
+// returned unchanged, so the innermost stack is kept. Returns nil if err
+// is nil.
+//
+// Example:
+//
+//	if err := repo.Save(ctx, user); err != nil {
+//		return errors.WithStack(err)
+//	}
+func WithStack(err error) error {
+	if err == nil || hasStack(err) {
+		return err
+	}
+	return &stackError{err: err, stack: Callers(1, maxStackDepth)}
+}
+
+// stackError attaches a stack to an error that has none.
+type stackError struct {
+	err   error
+	stack Stack
+}
+
+// Error returns the message of the annotated error.
+func (e *stackError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the annotated error.
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the stack captured by WithStack.
+func (e *stackError) StackTrace() Stack {
+	return e.stack
+}
+
+// hasStack reports whether err or an error it wraps carries a stack.
+func hasStack(err error) bool {
+	return StackOf(err) != nil
+}
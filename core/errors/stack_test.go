@@ -0,0 +1,171 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newUserError creates an error one call below the test, so the first
+// frame of its stack is known.
+func newUserError() error {
+	return New(CodeNotFound, "user not found")
+}
+
+// enableStackCapture turns on stack capture for the duration of a test.
+func enableStackCapture(t *testing.T) {
+	SetStackCapture(true)
+	t.Cleanup(func() { SetStackCapture(false) })
+}
+
+func TestNew_NoStackByDefault(t *testing.T) {
+	plain := fmt.Errorf("connection refused")
+
+	for name, err := range map[string]error{
+		"New":     New(CodeNotFound, "user not found"),
+		"Wrap":    Wrap(CodeUnavailable, "db.Query", plain),
+		"Wrapf":   Wrapf(CodeUnavailable, "db.Query", plain, "query %d failed", 1),
+		"Builder": Build(CodeUnavailable).WithErr(plain).Err(),
+	} {
+		if stack := StackOf(err); stack != nil {
+			t.Errorf("%s should not record a stack by default, got %v", name, stack.Frames(1))
+		}
+	}
+}
+
+func TestNew_Stack(t *testing.T) {
+	enableStackCapture(t)
+	err := newUserError()
+
+	stack := StackOf(err)
+	if stack == nil {
+		t.Fatal("New should record a stack")
+	}
+	frames := stack.Frames(0)
+	if !strings.HasSuffix(frames[0].Function, ".newUserError") {
+		t.Errorf("Expected first frame newUserError, got %s", frames[0].Function)
+	}
+	if !strings.HasSuffix(frames[1].Function, ".TestNew_Stack") {
+		t.Errorf("Expected second frame TestNew_Stack, got %s", frames[1].Function)
+	}
+}
+
+func TestWrap_KeepsInnermostStack(t *testing.T) {
+	enableStackCapture(t)
+	inner := newUserError()
+	wrapped := Wrap(CodeInternal, "user.Get", inner)
+	built := Build(CodeInternal).WithErr(wrapped).Err()
+
+	var e *E
+	errors.As(wrapped, &e)
+	if e.StackTrace() != nil {
+		t.Error("Wrap should not record a stack when the error already carries one")
+	}
+	errors.As(built, &e)
+	if e.StackTrace() != nil {
+		t.Error("Builder should not record a stack when the error already carries one")
+	}
+
+	frames := StackOf(built).Frames(1)
+	if !strings.HasSuffix(frames[0].Function, ".newUserError") {
+		t.Errorf("Expected innermost stack from newUserError, got %s", frames[0].Function)
+	}
+}
+
+func TestWrap_RecordsStack(t *testing.T) {
+	enableStackCapture(t)
+	plain := fmt.Errorf("connection refused")
+
+	for name, err := range map[string]error{
+		"Wrap":    Wrap(CodeUnavailable, "db.Query", plain),
+		"Wrapf":   Wrapf(CodeUnavailable, "db.Query", plain, "query %d failed", 1),
+		"Builder": Build(CodeUnavailable).WithErr(plain).Err(),
+	} {
+		frames := StackOf(err).Frames(1)
+		if len(frames) != 1 || !strings.HasSuffix(frames[0].Function, ".TestWrap_RecordsStack") {
+			t.Errorf("%s should record the stack of its caller, got %v", name, frames)
+		}
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	plain := fmt.Errorf("connection refused")
+	err := WithStack(plain)
+
+	frames := StackOf(err).Frames(1)
+	if len(frames) != 1 || !strings.HasSuffix(frames[0].Function, ".TestWithStack") {
+		t.Errorf("WithStack should record the stack of its caller, got %v", frames)
+	}
+	if err.Error() != plain.Error() || !errors.Is(err, plain) {
+		t.Errorf("WithStack should keep the message and wrap the error, got %q", err)
+	}
+	if again := WithStack(err); again != err {
+		t.Error("WithStack should return errors carrying a stack unchanged")
+	}
+	if WithStack(nil) != nil {
+		t.Error("WithStack(nil) should return nil")
+	}
+
+	wrapped := Wrap(CodeInternal, "user.Get", WithStack(New(CodeNotFound, "user not found")))
+	if CodeOf(wrapped) != CodeInternal || StackOf(wrapped) == nil {
+		t.Errorf("Expected code and stack through the chain, got %v", wrapped)
+	}
+}
+
+func TestStackOf(t *testing.T) {
+	inner := WithStack(fmt.Errorf("user not found"))
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", fmt.Errorf("plain"), false},
+		{"fmt wrapped", fmt.Errorf("get user: %w", inner), true},
+		{"joined", errors.Join(fmt.Errorf("plain"), inner), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack := StackOf(tt.err)
+			if (stack != nil) != tt.want {
+				t.Errorf("StackOf() = %v, want stack: %v", stack, tt.want)
+			}
+		})
+	}
+}
+
+func TestStack_Frames(t *testing.T) {
+	stack := Callers(0, maxStackDepth)
+	if len(stack) == 0 {
+		t.Fatal("Callers should capture frames")
+	}
+
+	if frames := stack.Frames(1); len(frames) != 1 {
+		t.Errorf("Frames(1) returned %d frames", len(frames))
+	}
+	if all := stack.Frames(0); len(all) < 2 {
+		t.Errorf("Frames(0) should return all frames, got %d", len(all))
+	}
+	if frames := Stack(nil).Frames(0); frames != nil {
+		t.Errorf("Frames of an empty stack = %v, want nil", frames)
+	}
+	if stack := Callers(0, 0); stack != nil {
+		t.Errorf("Callers with zero depth = %v, want nil", stack)
+	}
+}
+
+func TestStack_String(t *testing.T) {
+	lines := strings.Split(Callers(0, 1).String(), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected function and location lines, got %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], ".TestStack_String") {
+		t.Errorf("Expected function line, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "\t") || !strings.Contains(lines[1], "stack_test.go:") {
+		t.Errorf("Expected indented file:line, got %q", lines[1])
+	}
+}
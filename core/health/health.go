@@ -0,0 +1,111 @@
+// Package health provides the health report served by the egg health endpoints.
+//
+// Overview:
+//   - Responsibility: Run named dependency checks and report each result, shared by httpx and runtimex
+//   - Key Types: Report and CheckStatus for the JSON body; Run for concurrent execution
+//   - Concurrency Model: Checks run concurrently; Run is safe for concurrent use
+//   - Error Semantics: Check errors are reported per check, never returned
+//   - Performance Notes: One goroutine per check, bounded by a shared deadline
+//
+// Usage:
+//
+//	report := health.Run(ctx, map[string]func(context.Context) error{
+//		"db": db.PingContext,
+//	}, 2*time.Second)
+//	if !report.Ready() {
+//		w.WriteHeader(http.StatusServiceUnavailable)
+//	}
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Report and check statuses.
+const (
+	StatusReady     = "ready"
+	StatusNotReady  = "not_ready"
+	StatusAlive     = "alive"
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// DefaultTimeout bounds the checks of a Run call given no positive timeout.
+const DefaultTimeout = 5 * time.Second
+
+// CheckStatus is the result of a single health check.
+type CheckStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the JSON body of a health response.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckStatus `json:"checks,omitempty"`
+}
+
+// Ready reports whether every check passed.
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Run runs checks concurrently with a shared timeout and reports each one by
+// name. The timeout (DefaultTimeout if not positive) bounds every check on
+// its own too: a check still running when it expires is reported unhealthy
+// with the context error and is not waited for.
+func Run(ctx context.Context, checks map[string]func(ctx context.Context) error, timeout time.Duration) Report {
+	report := Report{Status: StatusReady}
+	if len(checks) == 0 {
+		return report
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(checks))
+	for name, check := range checks {
+		go func(name string, check func(ctx context.Context) error) {
+			results <- result{name: name, err: check(ctx)}
+		}(name, check)
+	}
+
+	report.Checks = make(map[string]CheckStatus, len(checks))
+	for len(report.Checks) < len(checks) {
+		select {
+		case r := <-results:
+			report.Checks[r.name] = checkStatus(r.err)
+		case <-ctx.Done():
+			// Report the checks that did not finish in time
+			for name := range checks {
+				if _, done := report.Checks[name]; !done {
+					report.Checks[name] = checkStatus(ctx.Err())
+				}
+			}
+		}
+	}
+
+	for _, status := range report.Checks {
+		if status.Status != StatusHealthy {
+			report.Status = StatusNotReady
+			break
+		}
+	}
+	return report
+}
+
+// checkStatus converts the result of a check.
+func checkStatus(err error) CheckStatus {
+	if err != nil {
+		return CheckStatus{Status: StatusUnhealthy, Error: err.Error()}
+	}
+	return CheckStatus{Status: StatusHealthy}
+}
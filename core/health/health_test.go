@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	report := Run(context.Background(), map[string]func(context.Context) error{
+		"db":    func(context.Context) error { return errors.New("connection refused") },
+		"cache": func(context.Context) error { return nil },
+	}, time.Second)
+
+	if report.Status != StatusNotReady || report.Ready() {
+		t.Errorf("Status = %q, want %q", report.Status, StatusNotReady)
+	}
+	if got := report.Checks["db"]; got.Status != StatusUnhealthy || got.Error != "connection refused" {
+		t.Errorf("db = %+v, want unhealthy with error", got)
+	}
+	if got := report.Checks["cache"]; got != (CheckStatus{Status: StatusHealthy}) {
+		t.Errorf("cache = %+v, want healthy", got)
+	}
+}
+
+func TestRun_NoChecks(t *testing.T) {
+	report := Run(context.Background(), nil, time.Second)
+	if !report.Ready() || report.Checks != nil {
+		t.Errorf("report = %+v, want ready without checks", report)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	report := Run(context.Background(), map[string]func(context.Context) error{
+		// Ignores its context, so only the timeout can end the wait
+		"stuck": func(context.Context) error { <-release; return nil },
+		"fast":  func(context.Context) error { return nil },
+	}, 20*time.Millisecond)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run() took %v, want about the timeout", elapsed)
+	}
+	if report.Status != StatusNotReady {
+		t.Errorf("Status = %q, want %q", report.Status, StatusNotReady)
+	}
+	if got := report.Checks["stuck"]; got.Status != StatusUnhealthy || got.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("stuck = %+v, want unhealthy with deadline exceeded", got)
+	}
+	if got := report.Checks["fast"]; got.Status != StatusHealthy {
+		t.Errorf("fast = %+v, want healthy", got)
+	}
+}
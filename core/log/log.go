@@ -79,6 +79,36 @@ func Float64(k string, v float64) any {
 	return []any{k, v}
 }
 
+// ErrorField is the value of an Err field. It behaves as the error it
+// holds, so loggers without stack support log the error message.
+type ErrorField struct {
+	Err error
+}
+
+// Error returns the message of the held error.
+func (f ErrorField) Error() string {
+	if f.Err == nil {
+		return "<nil>"
+	}
+	return f.Err.Error()
+}
+
+// Unwrap returns the held error.
+func (f ErrorField) Unwrap() error {
+	return f.Err
+}
+
+// Err creates an "error" key-value pair for structured logging. Unlike a
+// plain error value, loggers that support stack traces (such as logx) also
+// log the stack carried by the error, e.g. one created by core/errors.
+//
+// Example:
+//
+//	logger.Warn("retrying request", log.Err(err), log.Int("attempt", 2))
+func Err(err error) any {
+	return []any{"error", ErrorField{Err: err}}
+}
+
 // String creates a string key-value pair for structured logging.
 // This is an alias for Str for convenience.
 func String(k, v string) any {
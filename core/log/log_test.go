@@ -1,6 +1,7 @@
 package log
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -104,3 +105,26 @@ func TestLoggerInterface(t *testing.T) {
 		}
 	}
 }
+
+func TestErr(t *testing.T) {
+	cause := errors.New("connection refused")
+	kv := Err(cause)
+
+	slice, ok := kv.([]any)
+	if !ok || len(slice) != 2 || slice[0] != "error" {
+		t.Fatalf("Err should return [\"error\", ErrorField], got %v", kv)
+	}
+	field, ok := slice[1].(ErrorField)
+	if !ok {
+		t.Fatalf("Err value should be ErrorField, got %T", slice[1])
+	}
+	if field.Error() != "connection refused" {
+		t.Errorf("Expected error message, got %q", field.Error())
+	}
+	if !errors.Is(field, cause) {
+		t.Error("ErrorField should unwrap to the held error")
+	}
+	if msg := (ErrorField{}).Error(); msg != "<nil>" {
+		t.Errorf("Expected <nil> for empty field, got %q", msg)
+	}
+}
@@ -0,0 +1,74 @@
+// Package propagation provides allowlisted header propagation through context.
+//
+// Overview:
+//   - Responsibility: Carry selected inbound request headers to outbound calls
+//   - Key Types: Extract selects headers; With and From store them in context; Inject re-emits them
+//   - Concurrency Model: All functions are safe for concurrent use
+//   - Error Semantics: Functions return boolean to indicate presence of headers
+//   - Performance Notes: Headers are copied once on the way in and once per outbound request
+//
+// Usage:
+//
+//	ctx = propagation.With(ctx, propagation.Extract(req.Header(), []string{"X-Tenant-Id", "Accept-Language"}))
+//	propagation.Inject(ctx, outbound.Header)
+package propagation
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// Extract returns the headers of src named in names, with canonical keys and
+// all their values. Headers missing from src are skipped, so the result is
+// never larger than the allowlist. Returns nil if none are present.
+func Extract(src http.Header, names []string) http.Header {
+	var out http.Header
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		values := src.Values(key)
+		if len(values) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(http.Header, len(names))
+		}
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// With stores headers for propagation to outbound calls made with ctx.
+// Returns a new context holding a copy of the headers.
+// If headers is empty, returns the context unchanged.
+func With(ctx context.Context, headers http.Header) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, headers.Clone())
+}
+
+// From retrieves a copy of the headers stored with With.
+// Returns the headers and a boolean indicating if they were found.
+func From(ctx context.Context) (http.Header, bool) {
+	headers, ok := ctx.Value(contextKey{}).(http.Header)
+	if !ok {
+		return nil, false
+	}
+	return headers.Clone(), true
+}
+
+// Inject sets the headers stored in ctx on dst. Headers dst already carries
+// are left alone, so values set explicitly by the caller win.
+func Inject(ctx context.Context, dst http.Header) {
+	headers, ok := ctx.Value(contextKey{}).(http.Header)
+	if !ok {
+		return
+	}
+	for key, values := range headers {
+		if _, set := dst[key]; !set {
+			dst[key] = append([]string(nil), values...)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package propagation
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	src := http.Header{}
+	src.Set("X-Tenant-Id", "acme")
+	src.Add("Accept-Language", "de")
+	src.Add("Accept-Language", "en")
+	src.Set("Authorization", "Bearer secret")
+
+	got := Extract(src, []string{"x-tenant-id", "Accept-Language", "X-Missing"})
+	want := http.Header{
+		"X-Tenant-Id":     {"acme"},
+		"Accept-Language": {"de", "en"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+
+	if got := Extract(src, []string{"X-Missing"}); got != nil {
+		t.Errorf("Extract() = %v, want nil without matching headers", got)
+	}
+}
+
+func TestWithAndFrom(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := From(ctx); ok {
+		t.Fatal("Empty context should have no headers")
+	}
+	if With(ctx, nil) != ctx {
+		t.Error("Empty headers should return the context unchanged")
+	}
+
+	headers := http.Header{"X-Tenant-Id": {"acme"}}
+	ctx = With(ctx, headers)
+	headers.Set("X-Tenant-Id", "changed")
+
+	got, ok := From(ctx)
+	if !ok || got.Get("X-Tenant-Id") != "acme" {
+		t.Errorf("From() = %v, %v, want a copy taken by With", got, ok)
+	}
+	got.Set("X-Tenant-Id", "mutated")
+	if again, _ := From(ctx); again.Get("X-Tenant-Id") != "acme" {
+		t.Error("From() should return a copy")
+	}
+}
+
+func TestInject(t *testing.T) {
+	ctx := With(context.Background(), http.Header{
+		"X-Tenant-Id":     {"acme"},
+		"Accept-Language": {"de"},
+	})
+
+	dst := http.Header{}
+	dst.Set("Accept-Language", "fr")
+	Inject(ctx, dst)
+
+	if got := dst.Get("X-Tenant-Id"); got != "acme" {
+		t.Errorf("X-Tenant-Id = %q, want acme", got)
+	}
+	if got := dst.Values("Accept-Language"); !reflect.DeepEqual(got, []string{"fr"}) {
+		t.Errorf("Accept-Language = %v, want the caller's value", got)
+	}
+
+	empty := http.Header{}
+	Inject(context.Background(), empty)
+	if len(empty) != 0 {
+		t.Errorf("Inject() without headers set %v", empty)
+	}
+}
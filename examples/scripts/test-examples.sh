@@ -224,7 +224,8 @@ run_connect_tester_tests() {
     # Test minimal service
     print_info "Testing minimal-service endpoints..."
     print_info "Coverage: SayHello (multiple languages), SayHelloStream (various counts), Metrics"
-    if ! go run main.go http://localhost:8080 minimal-service; then
+    if ! go run main.go -health-url http://localhost:8081/health -metrics-url http://localhost:9091/metrics \
+        http://localhost:8080 minimal-service; then
         print_error "Minimal service tests failed"
         test_failed=1
     else
@@ -234,7 +235,9 @@ run_connect_tester_tests() {
     # Test user service with comprehensive test suite
     print_info "Testing user-service endpoints..."
     print_info "Coverage: Full CRUD operations, pagination scenarios, error handling, admin operations, metrics"
-    if ! INTERNAL_TOKEN="dev-internal-secret-token-12345" go run main.go http://localhost:8082 user-service; then
+    if ! INTERNAL_TOKEN="dev-internal-secret-token-12345" go run main.go \
+        -health-url http://localhost:8083/health -metrics-url http://localhost:9092/metrics \
+        http://localhost:8082 user-service; then
         print_error "User service tests failed"
         test_failed=1
     else
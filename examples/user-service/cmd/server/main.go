@@ -134,9 +134,16 @@ func registerServices(app *servicex.App) error {
 		ConfigGetter: func() any { return app.Config() },
 		Clients: map[string]servicex.ClientConfig{
 			"greet": {
-				URLKey:       "GreetServiceURL",
-				CreateClient: func(url, token string) any { return client.NewGreetClient(url, token) },
-				ClientName:   "greet service",
+				URLKey: "GreetServiceURL",
+				CreateClient: func(url, token string) any {
+					greetClient, err := client.NewGreetClient(url, token)
+					if err != nil {
+						app.Logger().Error(err, "greet client disabled")
+						return nil
+					}
+					return greetClient
+				},
+				ClientName: "greet service",
 			},
 		},
 	})
@@ -9,7 +9,10 @@
 //
 // Usage:
 //
-//	greetClient := client.NewGreetClient("http://minimal-service:8080", internalToken)
+//	greetClient, err := client.NewGreetClient("http://minimal-service:8080", internalToken)
+//	if err != nil {
+//	    return err
+//	}
 //	response, err := greetClient.SayHello(ctx, connect.NewRequest(&greetv1.SayHelloRequest{
 //	    Name:     "User Service",
 //	    Language: "en",
@@ -21,9 +24,9 @@
 package client
 
 import (
+	"fmt"
 	"time"
 
-	"connectrpc.com/connect"
 	"go.eggybyte.com/egg/clientx"
 	greetv1connect "go.eggybyte.com/egg/examples/minimal-connect-service/gen/go/greet/v1/greetv1connect"
 )
@@ -37,7 +40,7 @@ type GreetClient struct {
 // NewGreetClient creates a new GreetClient with production-ready configuration.
 //
 // This function demonstrates the egg framework's clientx usage pattern:
-//   - Uses clientx.NewConnectClient for automatic retry and circuit breaker
+//   - Uses clientx.New for automatic retry and circuit breaker
 //   - Automatically injects internal token for service-to-service authentication
 //   - Configures appropriate timeouts and retry policies
 //   - Creates reusable clients that can be shared across goroutines
@@ -48,6 +51,7 @@ type GreetClient struct {
 //
 // Returns:
 //   - *GreetClient: Configured client ready for use
+//   - error: if baseURL is invalid
 //
 // Configuration:
 //   - Timeout: 10 seconds (production-ready default)
@@ -57,27 +61,25 @@ type GreetClient struct {
 //
 // Concurrency:
 //   - Returns a client safe for concurrent use
-func NewGreetClient(baseURL, internalToken string) *GreetClient {
-	// Use clientx.NewConnectClient for production-ready client with:
+func NewGreetClient(baseURL, internalToken string) (*GreetClient, error) {
+	// Use clientx.New for production-ready client with:
 	// - Automatic retry with exponential backoff
 	// - Circuit breaker to prevent cascade failures
 	// - Internal token injection for service-to-service auth
 	// - Configurable timeouts
-	client := clientx.NewConnectClient(
-		baseURL,
-		"greet-service",
-		func(httpClient connect.HTTPClient, url string, opts ...connect.ClientOption) greetv1connect.GreeterServiceClient {
-			return greetv1connect.NewGreeterServiceClient(httpClient, url, opts...)
-		},
+	client, err := clientx.New(baseURL, greetv1connect.NewGreeterServiceClient,
 		clientx.WithTimeout(10*time.Second),      // Production timeout
 		clientx.WithRetry(3),                     // Retry up to 3 times
 		clientx.WithCircuitBreaker(true),         // Enable circuit breaker
 		clientx.WithInternalToken(internalToken), // Auto-inject internal token
 	)
+	if err != nil {
+		return nil, fmt.Errorf("greet client: %w", err)
+	}
 
 	return &GreetClient{
 		client: client,
-	}
+	}, nil
 }
 
 // Client returns the underlying Connect client for advanced usage.
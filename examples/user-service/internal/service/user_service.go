@@ -372,7 +372,7 @@ func (s *userService) AdminResetAllUsers(ctx context.Context, req *userv1.AdminR
 //
 // This method showcases the egg framework's recommended pattern for calling other
 // microservices:
-//   - Uses clientx.NewConnectClient for production-ready client features
+//   - Uses clientx.New for production-ready client features
 //   - Automatic retry with exponential backoff
 //   - Circuit breaker to prevent cascade failures
 //   - Internal token injection for service-to-service authentication
@@ -10,7 +10,9 @@
 // # Features
 //
 //   - Bind and validate JSON requests (validator integration)
+//   - JSON responses with an optional {"data": ...} envelope
 //   - Standard JSON error responses (404/405/custom)
+//   - RFC 7807 problem details negotiated via the Accept header
 //   - Security headers middleware with sane defaults
 //   - CORS middleware with configurable options
 //
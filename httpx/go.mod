@@ -2,7 +2,10 @@ module go.eggybyte.com/egg/httpx
 
 go 1.25.1
 
-require github.com/go-playground/validator/v10 v10.28.0
+require (
+	github.com/go-playground/validator/v10 v10.28.0
+	go.eggybyte.com/egg/core v0.3.3-alpha.2
+)
 
 require (
 	github.com/gabriel-vasile/mimetype v1.4.10 // indirect
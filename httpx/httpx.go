@@ -17,11 +17,19 @@
 package httpx
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"go.eggybyte.com/egg/core/health"
+	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/httpx/internal"
 )
 
@@ -32,18 +40,57 @@ type ErrorResponse struct {
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
+// ErrBodyTooLarge is returned by the bind functions when the request body
+// exceeds the configured limit. WriteError and WriteErrorFor answer errors
+// wrapping it with http.StatusRequestEntityTooLarge whatever status they are
+// given, so streamed and chunked bodies cut off by MaxBodyBytes get a 413 like
+// oversized Content-Length bodies do.
+var ErrBodyTooLarge = errors.New("request body exceeds limit")
+
+// BindOptions configures BindAndValidate.
+type BindOptions struct {
+	MaxBytes int64 // Limit on the request body size (0 = no limit)
+}
+
+// BindOption is a function that configures BindOptions.
+type BindOption func(*BindOptions)
+
+// WithMaxBytes limits the request body read by BindAndValidate to n bytes,
+// so oversized input fails with ErrBodyTooLarge instead of being read in full.
+func WithMaxBytes(n int64) BindOption {
+	return func(o *BindOptions) {
+		o.MaxBytes = n
+	}
+}
+
 // BindAndValidate binds JSON request body to target struct and validates it.
 // The target struct should have `json` and `validate` tags.
-func BindAndValidate(r *http.Request, target any) error {
+//
+// Bodies beyond the WithMaxBytes limit, or the MaxBodyBytes middleware limit,
+// fail with an error wrapping ErrBodyTooLarge.
+func BindAndValidate(r *http.Request, target any, opts ...BindOption) error {
 	if r.Body == nil {
 		return fmt.Errorf("request body is empty")
 	}
 
+	var options BindOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	body := r.Body
+	if options.MaxBytes > 0 {
+		body = http.MaxBytesReader(nil, body, options.MaxBytes)
+	}
+
 	// Decode JSON
-	decoder := json.NewDecoder(r.Body)
+	decoder := json.NewDecoder(body)
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(target); err != nil {
+		if tooLarge := bodyTooLarge(err); tooLarge != nil {
+			return tooLarge
+		}
 		return fmt.Errorf("invalid JSON: %w", err)
 	}
 
@@ -56,21 +103,391 @@ func BindAndValidate(r *http.Request, target any) error {
 	return nil
 }
 
+// bodyTooLarge converts a body read error from http.MaxBytesReader into an
+// error wrapping ErrBodyTooLarge, or returns nil for other errors.
+func bodyTooLarge(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return nil
+	}
+	return fmt.Errorf("%w of %d bytes", ErrBodyTooLarge, maxBytesErr.Limit)
+}
+
+// MaxBodyBytes returns middleware limiting request bodies to n bytes.
+//
+// Requests declaring a larger Content-Length are rejected with 413 Request
+// Entity Too Large before the handler runs. Other bodies, such as chunked
+// ones, are wrapped with http.MaxBytesReader, so reading past the limit fails
+// and BindAndValidate returns ErrBodyTooLarge, which WriteError and
+// WriteErrorFor answer with 413 too.
+//
+// Example:
+//
+//	handler := httpx.MaxBodyBytes(1 << 20)(mux) // 1 MiB
+func MaxBodyBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				WriteErrorFor(w, r, fmt.Errorf("%w of %d bytes", ErrBodyTooLarge, n), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, n)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DefaultMultipartMaxMemory is the part of a multipart body kept in memory
+// when MultipartOptions.MaxMemory is unset; the rest spills to temp files.
+const DefaultMultipartMaxMemory = 32 << 20 // 32 MiB
+
+// MultipartOptions configures BindMultipart.
+type MultipartOptions struct {
+	MaxMemory           int64    // Bytes of file parts held in memory before spilling to disk (default: 32 MiB)
+	MaxRequestSize      int64    // Limit on the whole request body, in memory and on disk (0 = no limit)
+	MaxFileSize         int64    // Limit on each uploaded file (0 = no limit)
+	AllowedContentTypes []string // Allowed file media types, e.g. "image/png" or "image/*" (empty = any)
+}
+
+// DefaultMultipartOptions returns multipart options with sensible defaults:
+// 32 MiB in memory, 64 MiB per request and 10 MiB per file, any content type.
+func DefaultMultipartOptions() MultipartOptions {
+	return MultipartOptions{
+		MaxMemory:      DefaultMultipartMaxMemory,
+		MaxRequestSize: 64 << 20,
+		MaxFileSize:    10 << 20,
+	}
+}
+
+// FileError describes an uploaded file rejected by MultipartOptions.
+type FileError = internal.FileError
+
+// FileErrors lists every file of a request rejected by MultipartOptions.
+type FileErrors = internal.FileErrors
+
+// BindMultipart parses a multipart/form-data request into target and validates it.
+//
+// Fields are bound by their `form` tag (or field name) and validated through
+// their `validate` tags, as in BindAndValidate. Fields of type
+// *multipart.FileHeader or []*multipart.FileHeader receive uploaded files,
+// which are checked against the size limit and allowed content types of opts;
+// content types are detected from the file contents, not trusted from the
+// client.
+//
+// Parameters:
+//   - r: Request with a multipart/form-data body
+//   - target: Pointer to the struct to bind
+//   - opts: Memory, size and content type limits
+//
+// Returns:
+//   - error: "invalid multipart form" for malformed bodies, unconvertible
+//     values and ErrBodyTooLarge; "validation failed" wrapping validator.ValidationErrors or
+//     FileErrors for rejected input
+//
+// Files spilled to disk are removed when BindMultipart fails, and otherwise by
+// net/http once the handler returns, so they must not be used after that.
+//
+// Example:
+//
+//	type UploadRequest struct {
+//	  Title  string                `form:"title" validate:"required"`
+//	  Avatar *multipart.FileHeader `form:"avatar" validate:"required"`
+//	}
+//
+//	var req UploadRequest
+//	opts := httpx.DefaultMultipartOptions()
+//	opts.AllowedContentTypes = []string{"image/*"}
+//	if err := httpx.BindMultipart(r, &req, opts); err != nil {
+//	  httpx.WriteError(w, err, http.StatusBadRequest)
+//	  return
+//	}
+func BindMultipart(r *http.Request, target any, opts MultipartOptions) (err error) {
+	if r.Body == nil {
+		return fmt.Errorf("request body is empty")
+	}
+
+	maxMemory := opts.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = DefaultMultipartMaxMemory
+	}
+	if opts.MaxRequestSize > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, opts.MaxRequestSize)
+	}
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		if tooLarge := bodyTooLarge(err); tooLarge != nil {
+			return fmt.Errorf("invalid multipart form: %w", tooLarge)
+		}
+		return fmt.Errorf("invalid multipart form: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			r.MultipartForm.RemoveAll()
+		}
+	}()
+
+	if err := internal.BindForm(r.MultipartForm, target); err != nil {
+		return fmt.Errorf("invalid multipart form: %w", err)
+	}
+	if err := internal.CheckFiles(r.MultipartForm, opts.MaxFileSize, opts.AllowedContentTypes); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(target); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	return nil
+}
+
+// Default pagination limits used when PaginationDefaults fields are unset.
+const (
+	DefaultPageSize    = 10
+	DefaultMaxPageSize = 100
+)
+
+// PaginationDefaults configures ParsePagination.
+type PaginationDefaults struct {
+	PageSize    int    // Page size when none is given (default: 10)
+	MaxPageSize int    // Upper bound for the page size (default: 100)
+	Strict      bool   // Reject negative or non-numeric values instead of normalizing them
+	PageParam   string // Query parameter for the page number (default: "page")
+	SizeParam   string // Query parameter for the page size (default: "page_size")
+}
+
+// Pagination is a normalized page request.
+type Pagination struct {
+	Page     int // 1-based page number
+	PageSize int // Items per page, within [1, MaxPageSize]
+	Offset   int // Index of the first item, (Page-1)*PageSize
+}
+
+// ParsePagination reads the page and page size query parameters of r.
+//
+// Missing or zero pages normalize to page 1, missing or zero sizes to the
+// default page size, and sizes above the maximum are capped. Negative and
+// non-numeric values are normalized the same way unless Strict is set, in
+// which case a "validation failed" error is returned. Pages are capped so
+// that Offset cannot overflow.
+//
+// Example:
+//
+//	page, err := httpx.ParsePagination(r, httpx.PaginationDefaults{Strict: true})
+//	if err != nil {
+//	  httpx.WriteError(w, err, http.StatusBadRequest)
+//	  return
+//	}
+//	users, err := repo.List(ctx, page.Offset, page.PageSize)
+func ParsePagination(r *http.Request, defaults PaginationDefaults) (Pagination, error) {
+	if defaults.PageSize <= 0 {
+		defaults.PageSize = DefaultPageSize
+	}
+	if defaults.MaxPageSize <= 0 {
+		defaults.MaxPageSize = DefaultMaxPageSize
+	}
+	if defaults.PageSize > defaults.MaxPageSize {
+		defaults.PageSize = defaults.MaxPageSize
+	}
+	if defaults.PageParam == "" {
+		defaults.PageParam = "page"
+	}
+	if defaults.SizeParam == "" {
+		defaults.SizeParam = "page_size"
+	}
+
+	query := r.URL.Query()
+	page, err := internal.ParsePageParam(defaults.PageParam, query.Get(defaults.PageParam), defaults.Strict)
+	if err != nil {
+		return Pagination{}, fmt.Errorf("validation failed: %w", err)
+	}
+	pageSize, err := internal.ParsePageParam(defaults.SizeParam, query.Get(defaults.SizeParam), defaults.Strict)
+	if err != nil {
+		return Pagination{}, fmt.Errorf("validation failed: %w", err)
+	}
+
+	page, pageSize, offset := internal.NormalizePagination(page, pageSize, defaults.PageSize, defaults.MaxPageSize)
+	return Pagination{Page: page, PageSize: pageSize, Offset: offset}, nil
+}
+
+// DataResponse is the envelope written by WriteData, matching ErrorResponse
+// for success payloads.
+type DataResponse struct {
+	Data any `json:"data"`
+}
+
+// JSONOptions configures WriteJSON, WriteData and WriteJSONWithETag.
+type JSONOptions struct {
+	Indent       string     // Indentation per level, e.g. "  " in development (default: compact)
+	Logger       log.Logger // Logger for encoding failures (default: not logged)
+	LastModified time.Time  // Last-Modified of the resource, used by WriteJSONWithETag (default: none)
+}
+
+// JSONOption is a function that configures JSONOptions.
+type JSONOption func(*JSONOptions)
+
+// WithIndent pretty-prints JSON responses with indent per nesting level.
+func WithIndent(indent string) JSONOption {
+	return func(o *JSONOptions) {
+		o.Indent = indent
+	}
+}
+
+// WithLogger logs responses that cannot be encoded to logger.
+func WithLogger(logger log.Logger) JSONOption {
+	return func(o *JSONOptions) {
+		o.Logger = logger
+	}
+}
+
+// WithLastModified sets the last modification time of the resource written
+// by WriteJSONWithETag, which sends it as Last-Modified and answers
+// If-Modified-Since with it. WriteJSON and WriteData ignore it.
+func WithLastModified(t time.Time) JSONOption {
+	return func(o *JSONOptions) {
+		o.LastModified = t
+	}
+}
+
 // WriteJSON writes a JSON response.
-func WriteJSON(w http.ResponseWriter, status int, data any) error {
+//
+// The body is encoded before anything is written, so a value that cannot be
+// encoded produces a 500 error response instead of a truncated body. The
+// encoding error is logged if a logger is configured and returned.
+//
+// Example:
+//
+//	httpx.WriteJSON(w, http.StatusCreated, user, httpx.WithIndent("  "))
+func WriteJSON(w http.ResponseWriter, status int, data any, opts ...JSONOption) error {
+	options := applyJSONOptions(opts)
+	body, err := encodeJSON(w, status, data, options)
+	if err != nil {
+		return err
+	}
+
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
+	if body == nil {
+		return nil
+	}
+	_, err = w.Write(body)
+	return err
+}
 
-	if data == nil {
+// WriteJSONWithETag writes a JSON response for a cacheable read endpoint,
+// answering conditional requests with 304 Not Modified.
+//
+// The ETag is a hash of the encoded body, so clients polling an unchanged
+// resource get an empty 304 instead of the full body. With WithLastModified
+// the Last-Modified header is sent as well. For GET and HEAD requests with a
+// 2xx status, If-None-Match is evaluated if present and otherwise
+// If-Modified-Since; other requests and statuses are written as by WriteJSON.
+//
+// Parameters:
+//   - w: response writer
+//   - r: request whose conditional headers are evaluated
+//   - status: status code of the full response
+//   - data: value to encode
+//   - opts: JSON options; WithLastModified enables If-Modified-Since
+//
+// Returns:
+//   - error: encoding or write error, as returned by WriteJSON
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - The body is always encoded and hashed (SHA-256); only the write is saved
+//
+// Example:
+//
+//	httpx.WriteJSONWithETag(w, r, http.StatusOK, user, httpx.WithLastModified(user.UpdatedAt))
+func WriteJSONWithETag(w http.ResponseWriter, r *http.Request, status int, data any, opts ...JSONOption) error {
+	options := applyJSONOptions(opts)
+	if status < 200 || status >= 300 {
+		return WriteJSON(w, status, data, opts...)
+	}
+
+	body, err := encodeJSON(w, status, data, options)
+	if err != nil {
+		return err
+	}
+
+	etag := internal.ETag(body)
+	w.Header().Set("ETag", etag)
+	if !options.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", options.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if internal.NotModified(r, etag, options.LastModified) {
+		w.WriteHeader(http.StatusNotModified)
 		return nil
 	}
 
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(data)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if body == nil {
+		return nil
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// applyJSONOptions returns the JSON options with opts applied.
+func applyJSONOptions(opts []JSONOption) JSONOptions {
+	var options JSONOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// encodeJSON encodes data for a response, returning a nil body for nil data.
+// On failure the 500 error response is written and the error returned.
+func encodeJSON(w http.ResponseWriter, status int, data any, options JSONOptions) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetIndent("", options.Indent)
+	if err := encoder.Encode(data); err != nil {
+		err = fmt.Errorf("encode response: %w", err)
+		if options.Logger != nil {
+			options.Logger.Error(err, "failed to write JSON response", "status", status)
+		}
+		writeEncodingFailure(w)
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// writeEncodingFailure writes the 500 sent in place of an unencodable body.
+func writeEncodingFailure(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error:   http.StatusText(http.StatusInternalServerError),
+		Message: "failed to encode response",
+	})
 }
 
-// WriteError writes a standard error response.
+// WriteData writes data with status 200 wrapped as {"data": ...}, the success
+// counterpart of the ErrorResponse envelope written by WriteError.
+//
+// Example:
+//
+//	httpx.WriteData(w, users) // {"data":[...]}
+func WriteData(w http.ResponseWriter, data any, opts ...JSONOption) error {
+	return WriteJSON(w, http.StatusOK, DataResponse{Data: data}, opts...)
+}
+
+// WriteError writes a standard error response. Errors wrapping
+// ErrBodyTooLarge are always written with status 413.
 func WriteError(w http.ResponseWriter, err error, status int) error {
+	status = errorStatus(err, status)
 	response := ErrorResponse{
 		Error:   http.StatusText(status),
 		Message: err.Error(),
@@ -79,26 +496,169 @@ func WriteError(w http.ResponseWriter, err error, status int) error {
 	return WriteJSON(w, status, response)
 }
 
-// NotFoundHandler returns a standard 404 JSON response.
+// Problem is an RFC 7807 problem details response.
+type Problem struct {
+	Type     string `json:"type"`               // URI identifying the problem type (default: "about:blank")
+	Title    string `json:"title"`              // Short summary (default: status text)
+	Status   int    `json:"status"`             // HTTP status code (default: 500)
+	Detail   string `json:"detail,omitempty"`   // Explanation specific to this occurrence
+	Instance string `json:"instance,omitempty"` // URI identifying this occurrence
+}
+
+// WriteProblem writes p as an application/problem+json response.
+// Unset Type, Title and Status are filled in so that the body always matches
+// the status code sent.
+func WriteProblem(w http.ResponseWriter, p Problem) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	w.Header().Set("Content-Type", internal.ProblemContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// WriteErrorFor writes an error response in the format negotiated from the
+// Accept header of r.
+//
+// Clients listing application/problem+json at least as highly as
+// application/json receive a Problem with the request path as its instance;
+// all others receive the ErrorResponse written by WriteError. Errors wrapping
+// ErrBodyTooLarge are always written with status 413.
+//
+// Example:
+//
+//	if err := svc.Create(r.Context(), req); err != nil {
+//	  httpx.WriteErrorFor(w, r, err, http.StatusConflict)
+//	  return
+//	}
+func WriteErrorFor(w http.ResponseWriter, r *http.Request, err error, status int) error {
+	status = errorStatus(err, status)
+	return writeNegotiatedError(w, r, status, http.StatusText(status), err.Error())
+}
+
+// errorStatus returns the status of an error response: 413 for errors
+// wrapping ErrBodyTooLarge, which handlers binding a body often answer with
+// a generic 400, and status otherwise.
+func errorStatus(err error, status int) int {
+	if errors.Is(err, ErrBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return status
+}
+
+// writeNegotiatedError writes title and detail as a Problem or ErrorResponse.
+func writeNegotiatedError(w http.ResponseWriter, r *http.Request, status int, title, detail string) error {
+	w.Header().Add("Vary", "Accept")
+	if internal.PrefersProblem(strings.Join(r.Header.Values("Accept"), ",")) {
+		return WriteProblem(w, Problem{
+			Title:    title,
+			Status:   status,
+			Detail:   detail,
+			Instance: r.URL.Path,
+		})
+	}
+
+	return WriteJSON(w, status, ErrorResponse{Error: title, Message: detail})
+}
+
+// NotFoundHandler returns a standard 404 response, as JSON or problem details
+// depending on the Accept header.
 func NotFoundHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		response := ErrorResponse{
-			Error:   "Not Found",
-			Message: fmt.Sprintf("Path %s not found", r.URL.Path),
-		}
-		WriteJSON(w, http.StatusNotFound, response)
+		writeNegotiatedError(w, r, http.StatusNotFound, "Not Found",
+			fmt.Sprintf("Path %s not found", r.URL.Path))
 	}
 }
 
-// MethodNotAllowedHandler returns a standard 405 JSON response.
+// MethodNotAllowedHandler returns a standard 405 response, as JSON or problem
+// details depending on the Accept header.
 func MethodNotAllowedHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		response := ErrorResponse{
-			Error:   "Method Not Allowed",
-			Message: fmt.Sprintf("Method %s not allowed for %s", r.Method, r.URL.Path),
-		}
-		WriteJSON(w, http.StatusMethodNotAllowed, response)
+		writeNegotiatedError(w, r, http.StatusMethodNotAllowed, "Method Not Allowed",
+			fmt.Sprintf("Method %s not allowed for %s", r.Method, r.URL.Path))
+	}
+}
+
+// DefaultHealthTimeout bounds all checks of a HealthHandler request.
+const DefaultHealthTimeout = health.DefaultTimeout
+
+// HealthCheckStatus is the result of one check in a HealthReport: "healthy",
+// or "unhealthy" with the error message.
+type HealthCheckStatus = health.CheckStatus
+
+// HealthReport is the JSON body written by HealthHandler: status "ready" or
+// "not_ready", and the result of each check by name. The runtimex readiness
+// endpoint serves the same core/health report.
+type HealthReport = health.Report
+
+// HealthOptions configures HealthHandler.
+type HealthOptions struct {
+	Timeout time.Duration // Time all checks of a request may take together (default: 5s)
+}
+
+// HealthOption is a function that configures HealthOptions.
+type HealthOption func(*HealthOptions)
+
+// WithHealthTimeout bounds the checks of each health request by d.
+func WithHealthTimeout(d time.Duration) HealthOption {
+	return func(o *HealthOptions) {
+		o.Timeout = d
+	}
+}
+
+// HealthHandler returns a readiness handler running checks for every GET or
+// HEAD request.
+//
+// Checks run concurrently under a shared deadline derived from the request
+// context; a check that has not returned when the timeout expires is
+// reported unhealthy with the context error. The response is 200 when every
+// check passed and 503 otherwise, with a HealthReport body:
+//
+//	{"status":"not_ready","checks":{"db":{"status":"unhealthy","error":"connection refused"},"cache":{"status":"healthy"}}}
+//
+// Without checks the handler always reports ready, which makes it usable as
+// a liveness endpoint too. Other methods get a 405 error response in the
+// format of WriteErrorFor.
+//
+// Example:
+//
+//	mux.Handle("/health/ready", httpx.HealthHandler(map[string]func(context.Context) error{
+//	  "db":    db.PingContext,
+//	  "cache": func(ctx context.Context) error { return rdb.Ping(ctx).Err() },
+//	}, httpx.WithHealthTimeout(2*time.Second)))
+//	mux.Handle("/health/live", httpx.HealthHandler(nil))
+func HealthHandler(checks map[string]func(ctx context.Context) error, opts ...HealthOption) http.Handler {
+	options := HealthOptions{Timeout: DefaultHealthTimeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultHealthTimeout
 	}
+
+	checks = maps.Clone(checks)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			MethodNotAllowedHandler()(w, r)
+			return
+		}
+
+		report := health.Run(r.Context(), checks, options.Timeout)
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		WriteJSON(w, status, report)
+	})
 }
 
 // SecurityHeaders adds security headers to HTTP response.
@@ -110,6 +670,7 @@ type SecurityHeaders struct {
 	StrictTransportSec    bool   // Strict-Transport-Security (HSTS)
 	HSTSMaxAge            int    // Max age for HSTS in seconds (default: 31536000 = 1 year)
 	ContentSecurityPolicy string // Optional CSP header
+	TrustForwardedProto   bool   // Treat X-Forwarded-Proto: https as HTTPS for HSTS
 }
 
 // DefaultSecurityHeaders returns security headers with sensible defaults.
@@ -124,19 +685,75 @@ func DefaultSecurityHeaders() SecurityHeaders {
 }
 
 // SecureMiddleware adds security headers to responses.
+// It applies the same rules as SecurityMiddleware, so HSTS is only sent on
+// HTTPS requests. Use SecurityMiddleware to configure header values, such as
+// the HSTS directives or a Permissions-Policy.
 func SecureMiddleware(headers SecurityHeaders) func(http.Handler) http.Handler {
-	internalHeaders := internal.SecurityHeaders{
-		ContentTypeOptions:    headers.ContentTypeOptions,
-		FrameOptions:          headers.FrameOptions,
-		ReferrerPolicy:        headers.ReferrerPolicy,
-		StrictTransportSec:    headers.StrictTransportSec,
-		HSTSMaxAge:            headers.HSTSMaxAge,
-		ContentSecurityPolicy: headers.ContentSecurityPolicy,
+	internalOpts := internal.SecurityOptions{
+		CSP:                    headers.ContentSecurityPolicy,
+		TrustForwardedProto:    headers.TrustForwardedProto,
+		OmitContentTypeOptions: !headers.ContentTypeOptions,
+		OmitFrameOptions:       !headers.FrameOptions,
+		OmitReferrerPolicy:     !headers.ReferrerPolicy,
+	}
+	if headers.StrictTransportSec {
+		internalOpts.HSTSMaxAge = time.Duration(headers.HSTSMaxAge) * time.Second
+		internalOpts.HSTSIncludeSubdomains = true
 	}
 
+	return securityMiddleware(internalOpts)
+}
+
+// SecurityOptions configures the headers set by SecurityMiddleware.
+// Empty FrameOptions and ReferrerPolicy keep the defaults of
+// DefaultSecurityHeaders; other empty fields omit their header.
+type SecurityOptions struct {
+	CSP                   string        // Content-Security-Policy (empty = not sent)
+	HSTSMaxAge            time.Duration // Strict-Transport-Security max-age (0 = HSTS disabled)
+	HSTSIncludeSubdomains bool          // Add includeSubDomains to HSTS
+	HSTSPreload           bool          // Add preload to HSTS (requires a max-age of at least 1 year)
+	FrameOptions          string        // X-Frame-Options (default: DENY)
+	ReferrerPolicy        string        // Referrer-Policy (default: no-referrer)
+	PermissionsPolicy     string        // Permissions-Policy (empty = not sent)
+	TrustForwardedProto   bool          // Treat X-Forwarded-Proto: https as HTTPS for HSTS
+}
+
+// SecurityMiddleware adds configurable security headers to responses.
+//
+// X-Content-Type-Options: nosniff is always set. HSTS is only sent on HTTPS
+// requests, so that enabling it does not break plain-HTTP local development.
+// A request is HTTPS if it arrived over TLS or, with TrustForwardedProto, if
+// X-Forwarded-Proto is https. Only set TrustForwardedProto behind a
+// TLS-terminating proxy that overwrites the header.
+//
+// Example:
+//
+//	handler := httpx.SecurityMiddleware(httpx.SecurityOptions{
+//	  CSP:                   "default-src 'self'",
+//	  HSTSMaxAge:            365 * 24 * time.Hour,
+//	  HSTSIncludeSubdomains: true,
+//	  PermissionsPolicy:     "camera=(), microphone=()",
+//	})(mux)
+func SecurityMiddleware(opts SecurityOptions) func(http.Handler) http.Handler {
+	internalOpts := internal.SecurityOptions{
+		CSP:                   opts.CSP,
+		HSTSMaxAge:            opts.HSTSMaxAge,
+		HSTSIncludeSubdomains: opts.HSTSIncludeSubdomains,
+		HSTSPreload:           opts.HSTSPreload,
+		FrameOptions:          opts.FrameOptions,
+		ReferrerPolicy:        opts.ReferrerPolicy,
+		PermissionsPolicy:     opts.PermissionsPolicy,
+		TrustForwardedProto:   opts.TrustForwardedProto,
+	}
+
+	return securityMiddleware(internalOpts)
+}
+
+// securityMiddleware applies opts to every response.
+func securityMiddleware(opts internal.SecurityOptions) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			internal.ApplySecurityHeaders(w, internalHeaders)
+			internal.ApplySecurityOptions(w, r, opts)
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -144,12 +761,13 @@ func SecureMiddleware(headers SecurityHeaders) func(http.Handler) http.Handler {
 
 // CORSOptions configures CORS behavior.
 type CORSOptions struct {
-	AllowedOrigins   []string // Allowed origins (e.g., ["https://example.com"])
-	AllowedMethods   []string // Allowed methods (default: GET, POST, PUT, DELETE, OPTIONS)
-	AllowedHeaders   []string // Allowed headers (default: Content-Type, Authorization)
-	ExposedHeaders   []string // Exposed headers
-	AllowCredentials bool     // Allow credentials
-	MaxAge           int      // Preflight cache duration in seconds
+	AllowedOrigins   []string                 // Allowed origins (e.g., ["https://example.com"])
+	AllowOriginFunc  func(origin string) bool // Additional origin check (e.g., from NewOriginMatcher)
+	AllowedMethods   []string                 // Allowed methods (default: GET, POST, PUT, DELETE, OPTIONS)
+	AllowedHeaders   []string                 // Allowed headers (default: Content-Type, Authorization)
+	ExposedHeaders   []string                 // Exposed headers
+	AllowCredentials bool                     // Allow credentials; the origin is reflected instead of "*"
+	MaxAge           int                      // Preflight cache duration in seconds (Access-Control-Max-Age)
 }
 
 // DefaultCORSOptions returns CORS options with sensible defaults.
@@ -162,10 +780,37 @@ func DefaultCORSOptions() CORSOptions {
 	}
 }
 
+// NewOriginMatcher compiles exact origins and regular expressions into a
+// function for CORSOptions.AllowOriginFunc.
+//
+// Exact origins are compared case-insensitively; patterns must match the whole
+// origin, so `https://pr-\d+\.example\.dev` does not match a longer host.
+//
+// Example:
+//
+//	match, err := httpx.NewOriginMatcher(
+//	  []string{"https://app.example.com"},
+//	  []string{`https://pr-\d+\.example\.dev`},
+//	)
+//	if err != nil {
+//	  return err
+//	}
+//	opts := httpx.DefaultCORSOptions()
+//	opts.AllowedOrigins = nil
+//	opts.AllowOriginFunc = match
+//	opts.AllowCredentials = true
+func NewOriginMatcher(origins []string, patterns []string) (func(origin string) bool, error) {
+	return internal.NewOriginMatcher(origins, patterns)
+}
+
 // CORSMiddleware adds CORS headers to responses.
+//
+// Responses whose headers depend on the request origin carry Vary: Origin, and
+// preflight responses also vary by the requested method and headers.
 func CORSMiddleware(opts CORSOptions) func(http.Handler) http.Handler {
 	internalOpts := internal.CORSOptions{
 		AllowedOrigins:   opts.AllowedOrigins,
+		AllowOriginFunc:  opts.AllowOriginFunc,
 		AllowedMethods:   opts.AllowedMethods,
 		AllowedHeaders:   opts.AllowedHeaders,
 		ExposedHeaders:   opts.ExposedHeaders,
@@ -2,11 +2,21 @@ package httpx
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.eggybyte.com/egg/core/log"
 )
 
 type TestRequest struct {
@@ -65,6 +75,197 @@ func TestBindAndValidate(t *testing.T) {
 	}
 }
 
+func TestBindAndValidate_MaxBytes(t *testing.T) {
+	body := `{"name":"John Doe","email":"john@example.com"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	var target TestRequest
+	if err := BindAndValidate(req, &target, WithMaxBytes(int64(len(body)))); err != nil {
+		t.Errorf("expected body at the limit to bind, got %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	err := BindAndValidate(req, &target, WithMaxBytes(16))
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	var bindErr error
+	handler := MaxBodyBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var target TestRequest
+		bindErr = BindAndValidate(r, &target)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("declared length", func(t *testing.T) {
+		bindErr = nil
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John Doe","email":"john@example.com"}`))
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status 413, got %d", w.Code)
+		}
+		var response ErrorResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode error response: %v", err)
+		}
+		if response.Error != "Request Entity Too Large" {
+			t.Errorf("unexpected error response %+v", response)
+		}
+		if bindErr != nil {
+			t.Error("handler should not run for an oversized Content-Length")
+		}
+	})
+
+	t.Run("streamed body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John Doe","email":"john@example.com"}`))
+		req.ContentLength = -1
+
+		handler.ServeHTTP(w, req)
+
+		if !errors.Is(bindErr, ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge from BindAndValidate, got %v", bindErr)
+		}
+	})
+}
+
+func TestMaxBodyBytes_ChunkedBody(t *testing.T) {
+	server := httptest.NewServer(MaxBodyBytes(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var target TestRequest
+		if err := BindAndValidate(r, &target); err != nil {
+			WriteError(w, err, http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer server.Close()
+
+	// A reader of unknown length makes the client send a chunked body
+	body := io.MultiReader(strings.NewReader(`{"name":"John Doe","email":"john@example.com"}`))
+	req, err := http.NewRequest(http.MethodPost, server.URL, body)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for an oversized chunked body, got %d", resp.StatusCode)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if response.Error != "Request Entity Too Large" {
+		t.Errorf("unexpected error response %+v", response)
+	}
+}
+
+type UploadRequest struct {
+	Title  string                `form:"title" validate:"required,min=2"`
+	Avatar *multipart.FileHeader `form:"avatar" validate:"required"`
+}
+
+// newMultipartRequest builds a multipart/form-data request with an optional file.
+func newMultipartRequest(t *testing.T, fields map[string]string, file string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		mw.WriteField(name, value)
+	}
+	if file != "" {
+		fw, _ := mw.CreateFormFile(file, "upload.bin")
+		fw.Write(content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestBindMultipart(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	opts := DefaultMultipartOptions()
+	opts.AllowedContentTypes = []string{"image/*"}
+
+	req := newMultipartRequest(t, map[string]string{"title": "Portrait"}, "avatar", png)
+	var target UploadRequest
+	if err := BindMultipart(req, &target, opts); err != nil {
+		t.Fatalf("BindMultipart() error = %v", err)
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	if target.Title != "Portrait" {
+		t.Errorf("Title = %q, want Portrait", target.Title)
+	}
+	if target.Avatar == nil || target.Avatar.Size != int64(len(png)) {
+		t.Errorf("Avatar = %+v, want the uploaded file", target.Avatar)
+	}
+}
+
+func TestBindMultipart_ValidationErrors(t *testing.T) {
+	opts := DefaultMultipartOptions()
+	opts.AllowedContentTypes = []string{"image/*"}
+
+	t.Run("field validation", func(t *testing.T) {
+		req := newMultipartRequest(t, map[string]string{"title": "P"}, "", nil)
+		var target UploadRequest
+		err := BindMultipart(req, &target, opts)
+
+		var validationErrs validator.ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			t.Fatalf("BindMultipart() error = %v, want validator.ValidationErrors", err)
+		}
+		if len(validationErrs) != 2 {
+			t.Errorf("validation errors = %v, want title and avatar", validationErrs)
+		}
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		req := newMultipartRequest(t, map[string]string{"title": "Portrait"}, "avatar", []byte("plain text"))
+		var target UploadRequest
+		err := BindMultipart(req, &target, opts)
+
+		var fileErrs FileErrors
+		if !errors.As(err, &fileErrs) || fileErrs[0].Field != "avatar" {
+			t.Fatalf("BindMultipart() error = %v, want FileErrors for avatar", err)
+		}
+		if !strings.HasPrefix(err.Error(), "validation failed") {
+			t.Errorf("error = %q, want validation failed prefix", err)
+		}
+	})
+
+	t.Run("request size", func(t *testing.T) {
+		req := newMultipartRequest(t, map[string]string{"title": "Portrait"}, "avatar", bytes.Repeat([]byte("x"), 4096))
+		var target UploadRequest
+		err := BindMultipart(req, &target, MultipartOptions{MaxRequestSize: 1024})
+
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("BindMultipart() error = %v, want ErrBodyTooLarge", err)
+		}
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"Portrait"}`))
+		req.Header.Set("Content-Type", "application/json")
+		var target UploadRequest
+		if err := BindMultipart(req, &target, opts); err == nil {
+			t.Error("expected error for a non-multipart body")
+		}
+	})
+}
+
 func TestWriteJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	data := map[string]string{"status": "ok"}
@@ -93,6 +294,165 @@ func TestWriteJSON(t *testing.T) {
 	}
 }
 
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		defaults PaginationDefaults
+		want     Pagination
+		wantErr  bool
+	}{
+		{name: "no parameters", query: "", want: Pagination{Page: 1, PageSize: 10, Offset: 0}},
+		{name: "explicit", query: "page=3&page_size=20", want: Pagination{Page: 3, PageSize: 20, Offset: 40}},
+		{name: "capped size", query: "page=1&page_size=200", want: Pagination{Page: 1, PageSize: 100, Offset: 0}},
+		{name: "zero size", query: "page_size=0", want: Pagination{Page: 1, PageSize: 10, Offset: 0}},
+		{name: "negative lenient", query: "page=-2&page_size=-5", want: Pagination{Page: 1, PageSize: 10, Offset: 0}},
+		{name: "negative strict", query: "page=-2", defaults: PaginationDefaults{Strict: true}, wantErr: true},
+		{name: "huge page", query: "page=" + strconv.Itoa(math.MaxInt), want: Pagination{Page: math.MaxInt / 10, PageSize: 10, Offset: (math.MaxInt/10 - 1) * 10}},
+		{name: "non-numeric strict", query: "page_size=ten", defaults: PaginationDefaults{Strict: true}, wantErr: true},
+		{
+			name:     "custom defaults",
+			query:    "p=2&limit=80",
+			defaults: PaginationDefaults{PageSize: 25, MaxPageSize: 50, PageParam: "p", SizeParam: "limit"},
+			want:     Pagination{Page: 2, PageSize: 50, Offset: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users?"+tt.query, nil)
+
+			got, err := ParsePagination(req, tt.defaults)
+			if tt.wantErr {
+				if err == nil || !strings.HasPrefix(err.Error(), "validation failed") {
+					t.Errorf("expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePagination failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// testLogger is a test logger implementation.
+type testLogger struct {
+	logs []string
+}
+
+func (l *testLogger) With(kv ...any) log.Logger              { return l }
+func (l *testLogger) Debug(msg string, kv ...any)            { l.logs = append(l.logs, "DEBUG: "+msg) }
+func (l *testLogger) Info(msg string, kv ...any)             { l.logs = append(l.logs, "INFO: "+msg) }
+func (l *testLogger) Warn(msg string, kv ...any)             { l.logs = append(l.logs, "WARN: "+msg) }
+func (l *testLogger) Error(err error, msg string, kv ...any) { l.logs = append(l.logs, "ERROR: "+msg) }
+
+func TestWriteJSON_Indent(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"}, WithIndent("  "))
+
+	if got := w.Body.String(); got != "{\n  \"status\": \"ok\"\n}\n" {
+		t.Errorf("expected indented body, got %q", got)
+	}
+}
+
+func TestWriteJSON_EncodingError(t *testing.T) {
+	w := httptest.NewRecorder()
+	logger := &testLogger{}
+
+	err := WriteJSON(w, http.StatusOK, map[string]any{"fn": func() {}}, WithLogger(logger))
+	if err == nil {
+		t.Fatal("expected encoding error")
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if response.Error != "Internal Server Error" {
+		t.Errorf("unexpected error response %+v", response)
+	}
+	if len(logger.logs) != 1 || !strings.HasPrefix(logger.logs[0], "ERROR:") {
+		t.Errorf("expected one error log, got %v", logger.logs)
+	}
+}
+
+func TestWriteData(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	if err := WriteData(w, []string{"a", "b"}); err != nil {
+		t.Fatalf("WriteData failed: %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected application/json content type, got %s", ct)
+	}
+	if got := w.Body.String(); got != `{"data":["a","b"]}`+"\n" {
+		t.Errorf("expected data envelope, got %q", got)
+	}
+}
+
+func TestWriteJSONWithETag(t *testing.T) {
+	user := map[string]any{"id": 1, "name": "Alice"}
+	updated := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	if err := WriteJSONWithETag(w, r, http.StatusOK, user, WithLastModified(updated)); err != nil {
+		t.Fatalf("WriteJSONWithETag() error = %v", err)
+	}
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("First response = %d, ETag %q, %d bytes; want 200 with ETag and body", w.Code, etag, w.Body.Len())
+	}
+	if got := w.Header().Get("Last-Modified"); got != "Thu, 01 Oct 2026 12:00:00 GMT" {
+		t.Errorf("Last-Modified = %q", got)
+	}
+
+	// Unchanged resource: 304 without a body
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("If-None-Match", etag)
+	WriteJSONWithETag(w, r, http.StatusOK, user)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+		t.Errorf("Matching If-None-Match = %d with %d bytes, want empty 304", w.Code, w.Body.Len())
+	}
+
+	// Changed resource: full response with a new ETag
+	w = httptest.NewRecorder()
+	WriteJSONWithETag(w, r, http.StatusOK, map[string]any{"id": 1, "name": "Bob"})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Changed resource = %d, ETag %q; want 200 with a new ETag", w.Code, w.Header().Get("ETag"))
+	}
+
+	// If-Modified-Since against the caller-supplied time
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("If-Modified-Since", "Thu, 01 Oct 2026 12:00:00 GMT")
+	WriteJSONWithETag(w, r, http.StatusOK, user, WithLastModified(updated))
+	if w.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since = %d, want 304", w.Code)
+	}
+
+	// Error statuses are never answered with 304
+	w = httptest.NewRecorder()
+	r.Header.Set("If-None-Match", "*")
+	WriteJSONWithETag(w, r, http.StatusNotFound, ErrorResponse{Error: "Not Found"})
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("Error response = %d, ETag %q; want 404 without ETag", w.Code, w.Header().Get("ETag"))
+	}
+}
+
 func TestWriteError(t *testing.T) {
 	w := httptest.NewRecorder()
 	err := bytes.ErrTooLarge
@@ -155,6 +515,180 @@ func TestMethodNotAllowedHandler(t *testing.T) {
 	}
 }
 
+func TestHealthHandler(t *testing.T) {
+	healthy := true
+	handler := HealthHandler(map[string]func(context.Context) error{
+		"db": func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("checks should run with a deadline")
+			}
+			if !healthy {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}, WithHealthTimeout(time.Second))
+
+	serve := func() (*httptest.ResponseRecorder, HealthReport) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		var report HealthReport
+		if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return w, report
+	}
+
+	w, report := serve()
+	if w.Code != http.StatusOK || report.Status != "ready" {
+		t.Errorf("healthy: status = %d, report = %+v, want 200 ready", w.Code, report)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected Content-Type application/json, got %s", ct)
+	}
+
+	healthy = false
+	w, report = serve()
+	if w.Code != http.StatusServiceUnavailable || report.Status != "not_ready" {
+		t.Errorf("unhealthy: status = %d, report = %+v, want 503 not_ready", w.Code, report)
+	}
+	if got := report.Checks["db"]; got != (HealthCheckStatus{Status: "unhealthy", Error: "connection refused"}) {
+		t.Errorf("db check = %+v, want unhealthy with error", got)
+	}
+}
+
+func TestHealthHandler_NoChecks(t *testing.T) {
+	w := httptest.NewRecorder()
+	HealthHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"ready"`) {
+		t.Errorf("got %d %s, want 200 ready", w.Code, w.Body.String())
+	}
+}
+
+func TestHealthHandler_MethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	HealthHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/health", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("expected Allow GET, HEAD, got %q", allow)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Error != "Method Not Allowed" {
+		t.Errorf("expected the standard error envelope, got %+v (%v)", response, err)
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, Problem{Status: http.StatusConflict, Detail: "user already exists"})
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("expected problem content type, got %s", ct)
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	want := Problem{Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "user already exists"}
+	if problem != want {
+		t.Errorf("expected %+v, got %+v", want, problem)
+	}
+}
+
+func TestWriteProblem_DefaultStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	WriteProblem(w, Problem{})
+
+	var problem Problem
+	json.NewDecoder(w.Body).Decode(&problem)
+	if w.Code != http.StatusInternalServerError || problem.Status != w.Code {
+		t.Errorf("expected status 500 in header and body, got %d and %d", w.Code, problem.Status)
+	}
+}
+
+func TestWriteErrorFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "no accept header", accept: "", contentType: "application/json; charset=utf-8"},
+		{name: "json", accept: "application/json", contentType: "application/json; charset=utf-8"},
+		{name: "wildcard", accept: "*/*", contentType: "application/json; charset=utf-8"},
+		{name: "problem json", accept: "application/problem+json", contentType: "application/problem+json"},
+		{name: "json preferred", accept: "application/json, application/problem+json;q=0.5", contentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			WriteErrorFor(w, req, errors.New("user not found"), http.StatusNotFound)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("expected status 404, got %d", w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expected content type %s, got %s", tt.contentType, ct)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("expected Vary: Accept, got %q", w.Header().Get("Vary"))
+			}
+
+			if tt.contentType == "application/problem+json" {
+				var problem Problem
+				if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+					t.Fatalf("failed to decode problem: %v", err)
+				}
+				if problem.Status != http.StatusNotFound || problem.Title != "Not Found" ||
+					problem.Detail != "user not found" || problem.Instance != "/users/42" {
+					t.Errorf("unexpected problem %+v", problem)
+				}
+				return
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if response.Error != "Not Found" || response.Message != "user not found" {
+				t.Errorf("unexpected error response %+v", response)
+			}
+		})
+	}
+}
+
+func TestNotFoundHandler_Problem(t *testing.T) {
+	handler := NotFoundHandler()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/nonexistent", nil)
+	req.Header.Set("Accept", "application/problem+json")
+
+	handler.ServeHTTP(w, req)
+
+	var problem Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+	if w.Code != http.StatusNotFound || problem.Status != http.StatusNotFound || problem.Instance != "/nonexistent" {
+		t.Errorf("unexpected response %d %+v", w.Code, problem)
+	}
+}
+
 func TestSecureMiddleware(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -188,6 +722,74 @@ func TestSecureMiddleware(t *testing.T) {
 	}
 }
 
+func TestSecureMiddleware_HSTSOnlyOverHTTPS(t *testing.T) {
+	headers := DefaultSecurityHeaders()
+	headers.StrictTransportSec = true
+	headers.FrameOptions = false
+	handler := SecureMiddleware(headers)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("expected no HSTS header on plain HTTP, got %q", got)
+	}
+	if got := w.Header().Get("X-Frame-Options"); got != "" {
+		t.Errorf("expected disabled X-Frame-Options to be omitted, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "https://example.com/", nil))
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("expected HSTS header over TLS, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	handler.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("expected untrusted X-Forwarded-Proto to be ignored, got %q", got)
+	}
+}
+
+func TestSecurityMiddleware(t *testing.T) {
+	handler := SecurityMiddleware(SecurityOptions{
+		CSP:                   "default-src 'self'",
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		PermissionsPolicy:     "geolocation=()",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("X-Frame-Options") != "DENY" {
+		t.Error("expected default X-Frame-Options header")
+	}
+	if w.Header().Get("Content-Security-Policy") != "default-src 'self'" {
+		t.Error("expected Content-Security-Policy header")
+	}
+	if w.Header().Get("Permissions-Policy") != "geolocation=()" {
+		t.Error("expected Permissions-Policy header")
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("expected HSTS header, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("expected no HSTS header on plain HTTP")
+	}
+}
+
 func TestCORSMiddleware(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -238,3 +840,49 @@ func TestCORSPreflightRequest(t *testing.T) {
 		t.Errorf("expected status 204 for preflight, got %d", w.Code)
 	}
 }
+
+func TestCORSMiddleware_OriginMatcher(t *testing.T) {
+	match, err := NewOriginMatcher([]string{"https://app.example.com"}, []string{`https://pr-\d+\.example\.dev`})
+	if err != nil {
+		t.Fatalf("NewOriginMatcher() error = %v", err)
+	}
+
+	opts := DefaultCORSOptions()
+	opts.AllowedOrigins = nil
+	opts.AllowOriginFunc = match
+	opts.AllowCredentials = true
+
+	handler := CORSMiddleware(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://pr-7.example.dev")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204 for preflight, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://pr-7.example.dev" {
+		t.Errorf("expected reflected origin, got %s", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "3600" {
+		t.Errorf("expected Access-Control-Max-Age 3600, got %s", got)
+	}
+	if vary := w.Header().Values("Vary"); len(vary) != 3 || vary[0] != "Origin" {
+		t.Errorf("expected Vary on origin and request method/headers, got %v", vary)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Origin for unknown origin, got %s", got)
+	}
+}
@@ -0,0 +1,58 @@
+// Package internal provides internal implementation details for httpx.
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag returns a strong entity tag for body: the quoted hex of the first
+// 16 bytes of its SHA-256 hash.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified reports whether a GET or HEAD request may be answered with
+// 304 Not Modified, following RFC 9110 section 13.2.2: If-None-Match is
+// evaluated if present, using weak comparison; otherwise If-Modified-Since is
+// compared with lastModified at one-second precision. A zero lastModified
+// disables If-Modified-Since, and an unparsable date is ignored.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if values := r.Header.Values("If-None-Match"); len(values) > 0 {
+		return matchesETag(strings.Join(values, ","), etag)
+	}
+
+	since := r.Header.Get("If-Modified-Since")
+	if lastModified.IsZero() || since == "" {
+		return false
+	}
+	t, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(t)
+}
+
+// matchesETag reports whether an If-None-Match list contains "*" or a tag
+// weakly equal to etag, ignoring W/ prefixes on both sides.
+func matchesETag(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
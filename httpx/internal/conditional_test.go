@@ -0,0 +1,74 @@
+// Package internal provides tests for httpx conditional requests.
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	etag := ETag([]byte(`{"id":1}`))
+	if len(etag) != 34 || etag[0] != '"' || etag[33] != '"' {
+		t.Errorf("ETag() = %s, want a quoted 32-character hex tag", etag)
+	}
+	if ETag([]byte(`{"id":1}`)) != etag {
+		t.Error("ETag() should be stable for the same body")
+	}
+	if ETag([]byte(`{"id":2}`)) == etag {
+		t.Error("ETag() should differ for different bodies")
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	const etag = `"abc"`
+	modified := time.Date(2026, 10, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "no conditions"},
+		{name: "matching tag", headers: map[string]string{"If-None-Match": `"abc"`}, want: true},
+		{name: "weak tag in list", headers: map[string]string{"If-None-Match": `"x", W/"abc"`}, want: true},
+		{name: "wildcard", headers: map[string]string{"If-None-Match": "*"}, want: true},
+		{name: "other tag", headers: map[string]string{"If-None-Match": `"xyz"`}},
+		{name: "HEAD request", method: http.MethodHead, headers: map[string]string{"If-None-Match": `"abc"`}, want: true},
+		{name: "POST request", method: http.MethodPost, headers: map[string]string{"If-None-Match": `"abc"`}},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Thu, 01 Oct 2026 11:59:59 GMT"}},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Thu, 01 Oct 2026 12:00:00 GMT"}, want: true},
+		{name: "invalid date", headers: map[string]string{"If-Modified-Since": "yesterday"}},
+		{
+			name: "If-None-Match takes precedence",
+			headers: map[string]string{
+				"If-None-Match":     `"xyz"`,
+				"If-Modified-Since": "Thu, 01 Oct 2026 12:00:00 GMT",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/users/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := NotModified(req, etag, modified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("If-Modified-Since", "Thu, 01 Oct 2026 12:00:00 GMT")
+	if NotModified(req, etag, time.Time{}) {
+		t.Error("NotModified() should ignore If-Modified-Since without a last-modified time")
+	}
+}
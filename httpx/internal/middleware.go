@@ -4,46 +4,93 @@ package internal
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
+	"time"
 )
 
-// SecurityHeaders adds security headers to HTTP response.
-type SecurityHeaders struct {
-	ContentTypeOptions    bool
-	FrameOptions          bool
-	ReferrerPolicy        bool
-	StrictTransportSec    bool
-	HSTSMaxAge            int
-	ContentSecurityPolicy string
+// Default values for security headers left empty in SecurityOptions.
+const (
+	DefaultFrameOptions   = "DENY"
+	DefaultReferrerPolicy = "no-referrer"
+)
+
+// SecurityOptions configures the value of each security header.
+type SecurityOptions struct {
+	CSP                   string
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	FrameOptions          string
+	ReferrerPolicy        string
+	PermissionsPolicy     string
+	TrustForwardedProto   bool
+
+	// Omit flags drop headers that are otherwise always sent; they back the
+	// boolean toggles of the public SecurityHeaders.
+	OmitContentTypeOptions bool
+	OmitFrameOptions       bool
+	OmitReferrerPolicy     bool
 }
 
-// ApplySecurityHeaders applies security headers to the response writer.
-func ApplySecurityHeaders(w http.ResponseWriter, headers SecurityHeaders) {
-	if headers.ContentTypeOptions {
-		w.Header().Set("X-Content-Type-Options", "nosniff")
+// ApplySecurityOptions applies security headers to the response writer.
+//
+// Empty frame and referrer options fall back to their defaults, other empty
+// values omit their header. HSTS is only sent over HTTPS, as browsers ignore
+// it on plain HTTP and it would otherwise break local development.
+func ApplySecurityOptions(w http.ResponseWriter, r *http.Request, opts SecurityOptions) {
+	h := w.Header()
+	if !opts.OmitContentTypeOptions {
+		h.Set("X-Content-Type-Options", "nosniff")
+	}
+	if !opts.OmitFrameOptions {
+		h.Set("X-Frame-Options", valueOr(opts.FrameOptions, DefaultFrameOptions))
+	}
+	if !opts.OmitReferrerPolicy {
+		h.Set("Referrer-Policy", valueOr(opts.ReferrerPolicy, DefaultReferrerPolicy))
+	}
+
+	if opts.CSP != "" {
+		h.Set("Content-Security-Policy", opts.CSP)
 	}
 
-	if headers.FrameOptions {
-		w.Header().Set("X-Frame-Options", "DENY")
+	if opts.PermissionsPolicy != "" {
+		h.Set("Permissions-Policy", opts.PermissionsPolicy)
 	}
 
-	if headers.ReferrerPolicy {
-		w.Header().Set("Referrer-Policy", "no-referrer")
+	if opts.HSTSMaxAge > 0 && IsHTTPS(r, opts.TrustForwardedProto) {
+		hstsValue := fmt.Sprintf("max-age=%d", int64(opts.HSTSMaxAge/time.Second))
+		if opts.HSTSIncludeSubdomains {
+			hstsValue += "; includeSubDomains"
+		}
+		if opts.HSTSPreload {
+			hstsValue += "; preload"
+		}
+		h.Set("Strict-Transport-Security", hstsValue)
 	}
+}
 
-	if headers.StrictTransportSec {
-		hstsValue := fmt.Sprintf("max-age=%d; includeSubDomains", headers.HSTSMaxAge)
-		w.Header().Set("Strict-Transport-Security", hstsValue)
+// IsHTTPS reports whether r reached the service over TLS. X-Forwarded-Proto
+// is only honored when trustForwarded is set, since any client can send it
+// unless a TLS-terminating proxy in front of the service overwrites it.
+func IsHTTPS(r *http.Request, trustForwarded bool) bool {
+	if r.TLS != nil {
+		return true
 	}
+	return trustForwarded && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
 
-	if headers.ContentSecurityPolicy != "" {
-		w.Header().Set("Content-Security-Policy", headers.ContentSecurityPolicy)
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
 	}
+	return value
 }
 
 // CORSOptions configures CORS behavior.
 type CORSOptions struct {
 	AllowedOrigins   []string
+	AllowOriginFunc  func(origin string) bool
 	AllowedMethods   []string
 	AllowedHeaders   []string
 	ExposedHeaders   []string
@@ -52,23 +99,26 @@ type CORSOptions struct {
 }
 
 // ApplyCORSHeaders applies CORS headers to the response writer.
+//
+// The allowed origin is reflected back unless every origin is allowed
+// without credentials, in which case "*" is sent; reflected responses vary by
+// Origin so that caches do not serve them to other origins.
 func ApplyCORSHeaders(w http.ResponseWriter, r *http.Request, opts CORSOptions) bool {
 	origin := r.Header.Get("Origin")
+	anyOrigin := allowsAnyOrigin(opts)
 
-	// Check if origin is allowed
-	allowed := false
-	for _, allowedOrigin := range opts.AllowedOrigins {
-		if allowedOrigin == "*" || allowedOrigin == origin {
-			allowed = true
-			break
-		}
+	if !anyOrigin {
+		addVary(w.Header(), "Origin")
+	}
+	if r.Method == http.MethodOptions {
+		addVary(w.Header(), "Access-Control-Request-Method", "Access-Control-Request-Headers")
 	}
 
-	if !allowed {
+	if !originAllowed(origin, opts) {
 		return false
 	}
 
-	if len(opts.AllowedOrigins) == 1 && opts.AllowedOrigins[0] == "*" {
+	if anyOrigin {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 	} else {
 		w.Header().Set("Access-Control-Allow-Origin", origin)
@@ -97,3 +147,69 @@ func ApplyCORSHeaders(w http.ResponseWriter, r *http.Request, opts CORSOptions)
 	return true
 }
 
+// allowsAnyOrigin reports whether the response can use "*" for every origin.
+// Browsers reject "*" on credentialed requests, so the origin is reflected
+// instead when credentials are allowed.
+func allowsAnyOrigin(opts CORSOptions) bool {
+	return len(opts.AllowedOrigins) == 1 && opts.AllowedOrigins[0] == "*" &&
+		opts.AllowOriginFunc == nil && !opts.AllowCredentials
+}
+
+// originAllowed reports whether origin matches AllowedOrigins or AllowOriginFunc.
+func originAllowed(origin string, opts CORSOptions) bool {
+	if origin == "" {
+		return false
+	}
+	for _, allowedOrigin := range opts.AllowedOrigins {
+		if allowedOrigin == "*" || allowedOrigin == origin {
+			return true
+		}
+	}
+	return opts.AllowOriginFunc != nil && opts.AllowOriginFunc(origin)
+}
+
+// addVary appends values to the Vary header, skipping those already present.
+func addVary(h http.Header, values ...string) {
+	existing := map[string]bool{}
+	for _, line := range h.Values("Vary") {
+		for _, v := range strings.Split(line, ",") {
+			existing[strings.ToLower(strings.TrimSpace(v))] = true
+		}
+	}
+	for _, v := range values {
+		if !existing[strings.ToLower(v)] {
+			h.Add("Vary", v)
+			existing[strings.ToLower(v)] = true
+		}
+	}
+}
+
+// NewOriginMatcher returns a function matching origins equal to one of
+// origins (case-insensitively) or fully matching one of patterns.
+func NewOriginMatcher(origins []string, patterns []string) (func(origin string) bool, error) {
+	exact := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		exact[strings.ToLower(strings.TrimSuffix(o, "/"))] = true
+	}
+
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid origin pattern %q: %w", p, err)
+		}
+		regexps = append(regexps, re)
+	}
+
+	return func(origin string) bool {
+		if exact[strings.ToLower(origin)] {
+			return true
+		}
+		for _, re := range regexps {
+			if re.MatchString(origin) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -4,94 +4,106 @@ package internal
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
-func TestApplySecurityHeaders_AllEnabled(t *testing.T) {
+func TestApplySecurityOptions_OmitHeaders(t *testing.T) {
 	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
 
-	headers := SecurityHeaders{
-		ContentTypeOptions:    true,
-		FrameOptions:          true,
-		ReferrerPolicy:        true,
-		StrictTransportSec:    true,
-		HSTSMaxAge:            31536000,
-		ContentSecurityPolicy: "default-src 'self'",
-	}
-
-	ApplySecurityHeaders(w, headers)
+	ApplySecurityOptions(w, req, SecurityOptions{
+		OmitContentTypeOptions: true,
+		OmitFrameOptions:       true,
+		OmitReferrerPolicy:     true,
+	})
 
-	result := w.Header()
-
-	if result.Get("X-Content-Type-Options") != "nosniff" {
-		t.Errorf("X-Content-Type-Options = %q, want %q", result.Get("X-Content-Type-Options"), "nosniff")
-	}
-	if result.Get("X-Frame-Options") != "DENY" {
-		t.Errorf("X-Frame-Options = %q, want %q", result.Get("X-Frame-Options"), "DENY")
-	}
-	if result.Get("Referrer-Policy") != "no-referrer" {
-		t.Errorf("Referrer-Policy = %q, want %q", result.Get("Referrer-Policy"), "no-referrer")
-	}
-	hstsValue := result.Get("Strict-Transport-Security")
-	expectedHSTS := "max-age=31536000; includeSubDomains"
-	if hstsValue != expectedHSTS {
-		t.Errorf("Strict-Transport-Security = %q, want %q", hstsValue, expectedHSTS)
-	}
-	if result.Get("Content-Security-Policy") != "default-src 'self'" {
-		t.Errorf("Content-Security-Policy = %q, want %q", result.Get("Content-Security-Policy"), "default-src 'self'")
+	for _, header := range []string{"X-Content-Type-Options", "X-Frame-Options", "Referrer-Policy", "Strict-Transport-Security"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("%s = %q, want not set", header, got)
+		}
 	}
 }
 
-func TestApplySecurityHeaders_NoneEnabled(t *testing.T) {
+func TestApplySecurityOptions_Defaults(t *testing.T) {
 	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
 
-	headers := SecurityHeaders{
-		ContentTypeOptions:    false,
-		FrameOptions:          false,
-		ReferrerPolicy:        false,
-		StrictTransportSec:    false,
-		ContentSecurityPolicy: "",
-	}
+	ApplySecurityOptions(w, req, SecurityOptions{})
 
-	ApplySecurityHeaders(w, headers)
-
-	result := w.Header()
-
-	if result.Get("X-Content-Type-Options") != "" {
-		t.Error("X-Content-Type-Options should not be set")
-	}
-	if result.Get("X-Frame-Options") != "" {
-		t.Error("X-Frame-Options should not be set")
+	want := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "no-referrer",
+		"Content-Security-Policy":   "",
+		"Permissions-Policy":        "",
+		"Strict-Transport-Security": "",
 	}
-	if result.Get("Referrer-Policy") != "" {
-		t.Error("Referrer-Policy should not be set")
-	}
-	if result.Get("Strict-Transport-Security") != "" {
-		t.Error("Strict-Transport-Security should not be set")
-	}
-	if result.Get("Content-Security-Policy") != "" {
-		t.Error("Content-Security-Policy should not be set")
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
 	}
 }
 
-func TestApplySecurityHeaders_Partial(t *testing.T) {
+func TestApplySecurityOptions_Custom(t *testing.T) {
 	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+
+	ApplySecurityOptions(w, req, SecurityOptions{
+		CSP:                   "default-src 'self'",
+		HSTSMaxAge:            2 * 365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		HSTSPreload:           true,
+		FrameOptions:          "SAMEORIGIN",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     "camera=()",
+	})
+
+	want := map[string]string{
+		"X-Frame-Options":           "SAMEORIGIN",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Content-Security-Policy":   "default-src 'self'",
+		"Permissions-Policy":        "camera=()",
+		"Strict-Transport-Security": "max-age=63072000; includeSubDomains; preload",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
 
-	headers := SecurityHeaders{
-		ContentTypeOptions: true,
-		FrameOptions:      false,
-		HSTSMaxAge:        3600,
+func TestApplySecurityOptions_HSTSOnlyOverHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		forwarded string
+		trust     bool
+		want      string
+	}{
+		{name: "plain http", url: "http://localhost:8080/", want: ""},
+		{name: "tls", url: "https://example.com/", want: "max-age=3600"},
+		{name: "tls terminated by trusted proxy", url: "http://example.com/", forwarded: "https", trust: true, want: "max-age=3600"},
+		{name: "untrusted forwarded proto", url: "http://example.com/", forwarded: "https", want: ""},
+		{name: "plain http via proxy", url: "http://example.com/", forwarded: "http", trust: true, want: ""},
 	}
 
-	ApplySecurityHeaders(w, headers)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwarded)
+			}
 
-	result := w.Header()
+			ApplySecurityOptions(w, req, SecurityOptions{HSTSMaxAge: time.Hour, TrustForwardedProto: tt.trust})
 
-	if result.Get("X-Content-Type-Options") != "nosniff" {
-		t.Error("X-Content-Type-Options should be set")
-	}
-	if result.Get("X-Frame-Options") != "" {
-		t.Error("X-Frame-Options should not be set")
+			if got := w.Header().Get("Strict-Transport-Security"); got != tt.want {
+				t.Errorf("Strict-Transport-Security = %q, want %q", got, tt.want)
+			}
+		})
 	}
 }
 
@@ -370,3 +382,107 @@ func TestApplyCORSHeaders_AllOptions(t *testing.T) {
 	}
 }
 
+
+func TestApplyCORSHeaders_AllowOriginFunc(t *testing.T) {
+	opts := CORSOptions{
+		AllowedOrigins:  []string{"https://example.com"},
+		AllowOriginFunc: func(origin string) bool { return strings.HasSuffix(origin, ".example.dev") },
+	}
+
+	for origin, want := range map[string]bool{
+		"https://example.com":        true,
+		"https://pr-42.example.dev":  true,
+		"https://evil.com":           false,
+		"https://example.dev.evil.x": false,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", origin)
+
+		if got := ApplyCORSHeaders(w, req, opts); got != want {
+			t.Errorf("ApplyCORSHeaders(%q) = %v, want %v", origin, got, want)
+		}
+		if want && w.Header().Get("Access-Control-Allow-Origin") != origin {
+			t.Errorf("Access-Control-Allow-Origin = %q, want %q", w.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Errorf("Vary = %q, want Origin for %q", w.Header().Get("Vary"), origin)
+		}
+	}
+}
+
+func TestApplyCORSHeaders_WildcardWithCredentials(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	ApplyCORSHeaders(w, req, CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want reflected origin", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
+
+func TestApplyCORSHeaders_WildcardDoesNotVary(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+
+	ApplyCORSHeaders(w, req, CORSOptions{AllowedOrigins: []string{"*"}})
+
+	if got := w.Header().Get("Vary"); got != "" {
+		t.Errorf("Vary = %q, want empty for a wildcard response", got)
+	}
+}
+
+func TestApplyCORSHeaders_PreflightVary(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Add("Vary", "Accept-Encoding, origin")
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	ApplyCORSHeaders(w, req, CORSOptions{AllowedOrigins: []string{"https://example.com"}, MaxAge: 600})
+
+	vary := strings.Join(w.Header().Values("Vary"), ", ")
+	if vary != "Accept-Encoding, origin, Access-Control-Request-Method, Access-Control-Request-Headers" {
+		t.Errorf("Vary = %q", vary)
+	}
+	if w.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 600", w.Header().Get("Access-Control-Max-Age"))
+	}
+}
+
+func TestNewOriginMatcher(t *testing.T) {
+	match, err := NewOriginMatcher(
+		[]string{"https://app.example.com", "https://Admin.Example.com/"},
+		[]string{`https://pr-\d+\.example\.dev`},
+	)
+	if err != nil {
+		t.Fatalf("NewOriginMatcher() error = %v", err)
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"https://admin.example.com", true},
+		{"https://pr-123.example.dev", true},
+		{"https://pr-abc.example.dev", false},
+		{"https://pr-1.example.dev.evil.com", false},
+		{"http://app.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := match(tt.origin); got != tt.want {
+			t.Errorf("match(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	if _, err := NewOriginMatcher(nil, []string{"https://(unclosed"}); err == nil {
+		t.Error("NewOriginMatcher() should reject an invalid pattern")
+	}
+}
@@ -0,0 +1,208 @@
+// Package internal provides internal implementation details for httpx.
+package internal
+
+import (
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// sniffLen is how much of a file is read to detect its content type.
+const sniffLen = 512
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// FileError describes an uploaded file rejected by the upload limits.
+type FileError struct {
+	Field    string // Form field name
+	Filename string // Client-provided file name
+	Reason   string // Why the file was rejected
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("file %q in field %q: %s", e.Filename, e.Field, e.Reason)
+}
+
+// FileErrors lists every rejected file of a form.
+type FileErrors []FileError
+
+func (e FileErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// BindForm sets the fields of the struct pointed to by dst from form.
+//
+// Fields are matched by their `form` tag, or their name if untagged; a tag of
+// "-" skips the field. *multipart.FileHeader and []*multipart.FileHeader
+// fields receive uploaded files. Other fields may be strings, bools, integers,
+// floats, or slices of those.
+func BindForm(form *multipart.Form, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("target must be a non-nil pointer to a struct, got %T", dst)
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fv := v.Field(i)
+		switch field.Type {
+		case fileHeaderType:
+			if files := form.File[name]; len(files) > 0 {
+				fv.Set(reflect.ValueOf(files[0]))
+			}
+			continue
+		case fileHeadersType:
+			if files := form.File[name]; len(files) > 0 {
+				fv.Set(reflect.ValueOf(files))
+			}
+			continue
+		}
+
+		values, ok := form.Value[name]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if err := setField(fv, values); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField converts values into fv: all of them for slices, the first otherwise.
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := setScalar(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return setScalar(fv, values[0])
+}
+
+func setScalar(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// CheckFiles applies the per-file size limit (if maxSize > 0) and the allowed
+// content types (if any) to every file of form.
+//
+// Content types are detected from the file contents rather than trusted from
+// the client. An allowed type may end in "/*" to allow a whole family, such
+// as "image/*".
+func CheckFiles(form *multipart.Form, maxSize int64, allowed []string) error {
+	var errs FileErrors
+	for field, files := range form.File {
+		for _, fh := range files {
+			if maxSize > 0 && fh.Size > maxSize {
+				errs = append(errs, FileError{Field: field, Filename: fh.Filename,
+					Reason: fmt.Sprintf("size %d exceeds limit of %d bytes", fh.Size, maxSize)})
+				continue
+			}
+			if len(allowed) == 0 {
+				continue
+			}
+			contentType, err := detectContentType(fh)
+			if err != nil {
+				errs = append(errs, FileError{Field: field, Filename: fh.Filename, Reason: err.Error()})
+				continue
+			}
+			if !contentTypeAllowed(contentType, allowed) {
+				errs = append(errs, FileError{Field: field, Filename: fh.Filename,
+					Reason: fmt.Sprintf("content type %q is not allowed", contentType)})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// detectContentType sniffs the media type of an uploaded file.
+func detectContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, _ := f.Read(buf)
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	if err != nil {
+		return "", fmt.Errorf("cannot detect content type: %w", err)
+	}
+	return mediaType, nil
+}
+
+func contentTypeAllowed(contentType string, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == contentType {
+			return true
+		}
+		if family, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(contentType, family+"/") {
+			return true
+		}
+	}
+	return false
+}
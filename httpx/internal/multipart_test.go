@@ -0,0 +1,152 @@
+// Package internal provides tests for httpx multipart binding.
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG file for content type detection.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// parseForm builds and parses a multipart form with values and files.
+func parseForm(t *testing.T, values map[string][]string, files map[string][]byte) *multipart.Form {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, vs := range values {
+		for _, v := range vs {
+			mw.WriteField(name, v)
+		}
+	}
+	for name, content := range files {
+		fw, _ := mw.CreateFormFile(name, name+".bin")
+		fw.Write(content)
+	}
+	mw.Close()
+
+	form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("ReadForm() error = %v", err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form
+}
+
+func TestBindForm_Values(t *testing.T) {
+	type target struct {
+		Name     string   `form:"name"`
+		Age      int      `form:"age"`
+		Score    float64  `form:"score"`
+		Active   bool     `form:"active"`
+		Tags     []string `form:"tag"`
+		IDs      []uint16 `form:"id"`
+		Untagged string
+		Skipped  string `form:"-"`
+	}
+
+	form := parseForm(t, map[string][]string{
+		"name":     {"Ada"},
+		"age":      {"36"},
+		"score":    {"9.5"},
+		"active":   {"true"},
+		"tag":      {"a", "b"},
+		"id":       {"1", "2"},
+		"Untagged": {"yes"},
+		"-":        {"no"},
+	}, nil)
+
+	var got target
+	if err := BindForm(form, &got); err != nil {
+		t.Fatalf("BindForm() error = %v", err)
+	}
+	if got.Name != "Ada" || got.Age != 36 || got.Score != 9.5 || !got.Active {
+		t.Errorf("scalars = %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[1] != "b" || len(got.IDs) != 2 || got.IDs[1] != 2 {
+		t.Errorf("slices = %v %v", got.Tags, got.IDs)
+	}
+	if got.Untagged != "yes" || got.Skipped != "" {
+		t.Errorf("Untagged = %q, Skipped = %q", got.Untagged, got.Skipped)
+	}
+}
+
+func TestBindForm_Files(t *testing.T) {
+	type target struct {
+		Avatar      *multipart.FileHeader   `form:"avatar"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+		Missing     *multipart.FileHeader   `form:"missing"`
+	}
+
+	form := parseForm(t, nil, map[string][]byte{"avatar": pngHeader, "attachments": []byte("doc")})
+
+	var got target
+	if err := BindForm(form, &got); err != nil {
+		t.Fatalf("BindForm() error = %v", err)
+	}
+	if got.Avatar == nil || got.Avatar.Filename != "avatar.bin" {
+		t.Errorf("Avatar = %+v", got.Avatar)
+	}
+	if len(got.Attachments) != 1 {
+		t.Errorf("Attachments = %v", got.Attachments)
+	}
+	if got.Missing != nil {
+		t.Errorf("Missing = %+v, want nil", got.Missing)
+	}
+}
+
+func TestBindForm_Errors(t *testing.T) {
+	form := parseForm(t, map[string][]string{"age": {"old"}}, nil)
+
+	var target struct {
+		Age int `form:"age"`
+	}
+	if err := BindForm(form, &target); err == nil || !strings.Contains(err.Error(), `field "age"`) {
+		t.Errorf("BindForm() error = %v, want conversion error naming the field", err)
+	}
+	if err := BindForm(form, target); err == nil {
+		t.Error("BindForm() with a non-pointer target should fail")
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	form := parseForm(t, nil, map[string][]byte{
+		"image": pngHeader,
+		"text":  []byte("plain text"),
+	})
+
+	tests := []struct {
+		name     string
+		maxSize  int64
+		allowed  []string
+		rejected []string
+	}{
+		{name: "no limits"},
+		{name: "exact type", allowed: []string{"image/png"}, rejected: []string{"text"}},
+		{name: "type family", allowed: []string{"image/*", "text/plain"}},
+		{name: "size limit", maxSize: 12, rejected: []string{"image"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckFiles(form, tt.maxSize, tt.allowed)
+
+			var fileErrs FileErrors
+			if len(tt.rejected) == 0 {
+				if err != nil {
+					t.Errorf("CheckFiles() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &fileErrs) {
+				t.Fatalf("CheckFiles() error = %v, want FileErrors", err)
+			}
+			if len(fileErrs) != len(tt.rejected) || fileErrs[0].Field != tt.rejected[0] {
+				t.Errorf("rejected = %+v, want fields %v", fileErrs, tt.rejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package internal provides internal implementation details for httpx.
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the RFC 7807 media type for problem details.
+const ProblemContentType = "application/problem+json"
+
+// PrefersProblem reports whether an Accept header asks for problem details
+// rather than plain JSON.
+//
+// Problem details are chosen only when application/problem+json is listed
+// explicitly with a quality at least that of application/json; wildcards and
+// a missing header fall back to plain JSON.
+func PrefersProblem(accept string) bool {
+	var problemQ, jsonQ float64
+	problemListed := false
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, q := parseMediaRange(part)
+		switch mediaType {
+		case ProblemContentType:
+			problemListed = true
+			problemQ = max(problemQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+
+	return problemListed && problemQ > 0 && problemQ >= jsonQ
+}
+
+// parseMediaRange returns the lower-cased media type of an Accept element and
+// its quality, defaulting to 1 when absent or malformed.
+func parseMediaRange(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+	q := 1.0
+	for _, param := range params[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && parsed >= 0 && parsed <= 1 {
+			q = parsed
+		}
+	}
+	return mediaType, q
+}
@@ -0,0 +1,32 @@
+// Package internal provides tests for httpx content negotiation.
+package internal
+
+import "testing"
+
+func TestPrefersProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   bool
+	}{
+		{name: "no accept header", accept: "", want: false},
+		{name: "wildcard", accept: "*/*", want: false},
+		{name: "plain json", accept: "application/json", want: false},
+		{name: "problem json", accept: "application/problem+json", want: true},
+		{name: "problem json case insensitive", accept: "Application/Problem+JSON", want: true},
+		{name: "problem listed first", accept: "application/problem+json, application/json", want: true},
+		{name: "problem with wildcard fallback", accept: "application/problem+json, */*;q=0.8", want: true},
+		{name: "json preferred by quality", accept: "application/problem+json;q=0.5, application/json", want: false},
+		{name: "problem preferred by quality", accept: "application/json;q=0.5, application/problem+json", want: true},
+		{name: "problem refused", accept: "application/problem+json;q=0", want: false},
+		{name: "malformed quality", accept: "application/problem+json;q=high", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrefersProblem(tt.accept); got != tt.want {
+				t.Errorf("PrefersProblem(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package internal provides internal implementation details for httpx.
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParsePageParam parses a page or page size query value. Empty values return
+// 0, leaving the choice of default to NormalizePagination. In strict mode,
+// values that are not non-negative integers are reported as errors; otherwise
+// they are treated as empty.
+func ParsePageParam(name, value string, strict bool) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		if strict {
+			return 0, fmt.Errorf("query parameter %q must be a non-negative integer, got %q", name, value)
+		}
+		return 0, nil
+	}
+	return n, nil
+}
+
+// NormalizePagination clamps page to at least 1 and pageSize to
+// [1, maxPageSize], using defaultPageSize when pageSize is unset, and returns
+// them with the offset of the first item. Page is also capped at
+// math.MaxInt/pageSize so that the offset cannot overflow.
+func NormalizePagination(page, pageSize, defaultPageSize, maxPageSize int) (int, int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	if maxPage := math.MaxInt / pageSize; page > maxPage {
+		page = maxPage
+	}
+	return page, pageSize, (page - 1) * pageSize
+}
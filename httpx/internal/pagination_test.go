@@ -0,0 +1,62 @@
+// Package internal provides tests for httpx pagination.
+package internal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParsePageParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		strict  bool
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "valid", value: "3", want: 3},
+		{name: "zero", value: "0", strict: true, want: 0},
+		{name: "negative lenient", value: "-1", want: 0},
+		{name: "negative strict", value: "-1", strict: true, wantErr: true},
+		{name: "not a number lenient", value: "abc", want: 0},
+		{name: "not a number strict", value: "abc", strict: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePageParam("page", tt.value, tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePageParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePageParam() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePagination(t *testing.T) {
+	tests := []struct {
+		name                        string
+		page, pageSize              int
+		wantPage, wantSize, wantOff int
+	}{
+		{name: "defaults", page: 0, pageSize: 0, wantPage: 1, wantSize: 10, wantOff: 0},
+		{name: "in range", page: 3, pageSize: 20, wantPage: 3, wantSize: 20, wantOff: 40},
+		{name: "capped size", page: 2, pageSize: 200, wantPage: 2, wantSize: 100, wantOff: 100},
+		{name: "negative", page: -4, pageSize: -1, wantPage: 1, wantSize: 10, wantOff: 0},
+		{name: "max page", page: math.MaxInt, pageSize: 20, wantPage: math.MaxInt / 20, wantSize: 20, wantOff: (math.MaxInt/20 - 1) * 20},
+		{name: "max page default size", page: math.MaxInt, pageSize: 0, wantPage: math.MaxInt / 10, wantSize: 10, wantOff: (math.MaxInt/10 - 1) * 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, size, offset := NormalizePagination(tt.page, tt.pageSize, 10, 100)
+			if page != tt.wantPage || size != tt.wantSize || offset != tt.wantOff {
+				t.Errorf("NormalizePagination() = (%d, %d, %d), want (%d, %d, %d)",
+					page, size, offset, tt.wantPage, tt.wantSize, tt.wantOff)
+			}
+		})
+	}
+}
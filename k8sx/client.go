@@ -0,0 +1,90 @@
+// Package k8sx provides the Kubernetes client shared by watchers and resolvers.
+package k8sx
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+
+	"go.eggybyte.com/egg/k8sx/internal"
+)
+
+// ClientOptions holds configuration for NewClient.
+type ClientOptions struct {
+	Kubeconfig string               // Explicit kubeconfig path; skips in-cluster detection (optional)
+	Context    string               // Kubeconfig context (default: current context)
+	Clientset  kubernetes.Interface // Use this clientset as is, e.g. a fake clientset in tests (optional)
+}
+
+// Client is a Kubernetes client shared by the watch, leader election and
+// resolve functions. It is safe for concurrent use.
+type Client struct {
+	clientset kubernetes.Interface
+}
+
+// NewClient creates a Kubernetes client, detecting where it runs.
+//
+// Configuration is chosen in this order:
+//   - opts.Clientset, if set
+//   - opts.Kubeconfig, if set
+//   - the in-cluster service account, when running in a pod
+//   - the kubeconfig files listed in $KUBECONFIG, or ~/.kube/config
+//
+// Parameters:
+//   - opts: Optional kubeconfig path, context or clientset override
+//
+// Returns:
+//   - *Client: Client to pass via the Client field of the watch options
+//   - error: No usable configuration was found or it is invalid
+//
+// Example:
+//
+//	client, err := k8sx.NewClient(k8sx.ClientOptions{})
+//	err = k8sx.WatchConfigMap(ctx, "app-config", k8sx.WatchOptions{
+//	  Client: client,
+//	  Logger: logger,
+//	}, onUpdate)
+func NewClient(opts ClientOptions) (*Client, error) {
+	if opts.Clientset != nil {
+		return &Client{clientset: opts.Clientset}, nil
+	}
+
+	clientset, err := internal.NewClient(internal.ClientConfig{
+		Kubeconfig: opts.Kubeconfig,
+		Context:    opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{clientset: clientset}, nil
+}
+
+// Clientset returns the underlying client-go clientset.
+func (c *Client) Clientset() kubernetes.Interface {
+	return c.clientset
+}
+
+// Resolve resolves a Kubernetes service to its endpoints using this client.
+// See the package-level Resolve.
+func (c *Client) Resolve(ctx context.Context, service string, kind ServiceKind) ([]string, error) {
+	if service == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+
+	return internal.NewServiceResolver(c.clientset).ResolveService(ctx, service, string(kind))
+}
+
+// clientset returns the clientset of c, or of a client created with
+// NewClient(ClientOptions{}) when c is nil.
+func clientset(c *Client) (kubernetes.Interface, error) {
+	if c != nil {
+		return c.clientset, nil
+	}
+
+	c, err := NewClient(ClientOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.clientset, nil
+}
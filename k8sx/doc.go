@@ -9,8 +9,9 @@
 //
 // # Features
 //
-//   - Debounced ConfigMap watching with callback hooks
-//   - Service endpoint resolution (headless and ClusterIP)
+//   - Debounced ConfigMap and Secret watching with callback hooks
+//   - Service endpoint resolution (headless and ClusterIP) and live endpoint watching
+//   - Lease-based leader election for singleton jobs
 //   - Context cancellation and resource-safe stop semantics
 //
 // # Usage
@@ -0,0 +1,54 @@
+// Package internal contains Kubernetes client construction.
+package internal
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClientConfig configures NewClient.
+type ClientConfig struct {
+	Kubeconfig string // Explicit kubeconfig path; skips in-cluster detection
+	Context    string // Kubeconfig context (default: current context)
+}
+
+// NewClient creates a Kubernetes client.
+//
+// Without an explicit kubeconfig, the in-cluster service account is used
+// when running in a pod; otherwise the kubeconfig files from $KUBECONFIG
+// or ~/.kube/config are loaded.
+func NewClient(cfg ClientConfig) (kubernetes.Interface, error) {
+	config, err := restConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes config: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+// restConfig resolves the REST config described by cfg.
+func restConfig(cfg ClientConfig) (*rest.Config, error) {
+	if cfg.Kubeconfig == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+		if err != rest.ErrNotInCluster {
+			return nil, err
+		}
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = cfg.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cfg.Context}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+}
@@ -0,0 +1,102 @@
+// Package internal provides tests for Kubernetes client construction.
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: admin
+- name: prod
+  context:
+    cluster: prod
+    user: admin
+`
+
+func writeKubeconfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestRestConfigKubeconfig(t *testing.T) {
+	path := writeKubeconfig(t)
+
+	tests := []struct {
+		name    string
+		context string
+		want    string
+	}{
+		{name: "current context", want: "https://dev.example.com:6443"},
+		{name: "explicit context", context: "prod", want: "https://prod.example.com:6443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := restConfig(ClientConfig{Kubeconfig: path, Context: tt.context})
+			if err != nil {
+				t.Fatalf("restConfig() error = %v", err)
+			}
+			if config.Host != tt.want {
+				t.Errorf("Host = %q, want %q", config.Host, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestConfigKubeconfigEnv(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBECONFIG", writeKubeconfig(t))
+
+	config, err := restConfig(ClientConfig{})
+	if err != nil {
+		t.Fatalf("restConfig() error = %v", err)
+	}
+	if config.Host != "https://dev.example.com:6443" {
+		t.Errorf("Host = %q, want dev cluster", config.Host)
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+	}{
+		{name: "no configuration", cfg: ClientConfig{}},
+		{name: "missing kubeconfig", cfg: ClientConfig{Kubeconfig: filepath.Join(t.TempDir(), "missing")}},
+		{name: "unknown context", cfg: ClientConfig{Kubeconfig: writeKubeconfig(t), Context: "staging"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(tt.cfg); err == nil {
+				t.Error("NewClient() expected error")
+			}
+		})
+	}
+}
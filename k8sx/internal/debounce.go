@@ -0,0 +1,52 @@
+// Package internal contains watch callback debouncing.
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Debounce returns a function that delays calling fn until no call has been
+// made for d, then calls it once with the latest value. Calls to fn never
+// overlap, and pending calls are dropped once ctx is done.
+//
+// A non-positive d returns fn unchanged.
+func Debounce[T any](ctx context.Context, d time.Duration, fn func(T)) func(T) {
+	if d <= 0 || fn == nil {
+		return fn
+	}
+
+	var (
+		mu     sync.Mutex
+		callMu sync.Mutex
+		timer  *time.Timer
+		latest T
+		gen    uint64
+	)
+	return func(value T) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		latest = value
+		gen++
+		current := gen
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, func() {
+			callMu.Lock()
+			defer callMu.Unlock()
+
+			mu.Lock()
+			stale := current != gen
+			value := latest
+			mu.Unlock()
+
+			if stale || ctx.Err() != nil {
+				return
+			}
+			fn(value)
+		})
+	}
+}
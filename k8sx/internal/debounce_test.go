@@ -0,0 +1,69 @@
+// Package internal provides tests for watch callback debouncing.
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDebounce_CoalescesBursts(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []int
+	)
+	done := make(chan struct{}, 1)
+	fn := Debounce(context.Background(), 20*time.Millisecond, func(v int) {
+		mu.Lock()
+		calls = append(calls, v)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	for i := 1; i <= 5; i++ {
+		fn(i)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("debounced function not called")
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls) != 1 || calls[0] != 5 {
+		t.Errorf("calls = %v, want [5]", calls)
+	}
+}
+
+func TestDebounce_DropsPendingAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	called := make(chan int, 1)
+	fn := Debounce(ctx, 20*time.Millisecond, func(v int) { called <- v })
+
+	fn(1)
+	cancel()
+
+	select {
+	case v := <-called:
+		t.Errorf("debounced function called with %d after cancel", v)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestDebounce_Disabled(t *testing.T) {
+	calls := 0
+	fn := Debounce(context.Background(), 0, func(int) { calls++ })
+	fn(1)
+	fn(2)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 without debouncing", calls)
+	}
+
+	if Debounce[int](context.Background(), time.Second, nil) != nil {
+		t.Error("Debounce(nil) should return nil")
+	}
+}
@@ -0,0 +1,248 @@
+// Package internal contains Kubernetes endpoint watching implementation.
+package internal
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// Endpoint is a single ready address and port backing a service.
+type Endpoint struct {
+	Host     string // Pod IP address
+	Port     int32  // Port number, 0 if unknown
+	PortName string // Name of the service port, if named
+	Hostname string // Pod hostname, if set
+	NodeName string // Node hosting the endpoint, if known
+	Zone     string // Zone of the node, if known
+}
+
+// Address returns the endpoint as "host:port", or just the host when the
+// port is unknown.
+func (e Endpoint) Address() string {
+	if e.Port == 0 {
+		return e.Host
+	}
+	return net.JoinHostPort(e.Host, strconv.Itoa(int(e.Port)))
+}
+
+// EndpointsConfig configures WatchEndpoints.
+type EndpointsConfig struct {
+	Service      string        // Service name
+	Namespace    string        // Service namespace
+	Logger       log.Logger    // Logger for watch operations
+	FallbackPort int32         // Port reported for DNS-resolved endpoints
+	DNSInterval  time.Duration // How often DNS is resolved in fallback mode
+
+	// LookupHost resolves a host name; nil uses net.DefaultResolver.
+	LookupHost func(ctx context.Context, host string) ([]string, error)
+}
+
+// WatchEndpoints reports the ready endpoints of a service to onUpdate each
+// time they change, until ctx is done. The first call reports the initial
+// set, which may be empty.
+//
+// Endpoints come from an EndpointSlice informer. When RBAC forbids listing
+// EndpointSlices, the service's DNS name is resolved every DNSInterval
+// instead; DNS only returns ready addresses of headless services.
+func WatchEndpoints(ctx context.Context, client kubernetes.Interface, cfg EndpointsConfig, onUpdate func([]Endpoint)) error {
+	selector := discoveryv1.LabelServiceName + "=" + cfg.Service
+	_, err := client.DiscoveryV1().EndpointSlices(cfg.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+		Limit:         1,
+	})
+	switch {
+	case apierrors.IsForbidden(err):
+		cfg.Logger.Warn("watching endpoints forbidden, falling back to DNS",
+			log.Str("service", cfg.Service),
+			log.Str("namespace", cfg.Namespace))
+		return watchDNS(ctx, cfg, onUpdate)
+	case err != nil:
+		return fmt.Errorf("failed to list endpoints for service %s: %w", cfg.Service, err)
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(cfg.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector
+		}))
+	sliceInformer := factory.Discovery().V1().EndpointSlices()
+	informer := sliceInformer.Informer()
+	lister := sliceInformer.Lister().EndpointSlices(cfg.Namespace)
+
+	notify := changeNotifier(onUpdate)
+	synced := make(chan struct{})
+	refresh := func() {
+		select {
+		case <-synced:
+		default:
+			return // The initial set is reported once the cache is synced.
+		}
+		items, err := lister.List(labels.Everything())
+		if err != nil {
+			cfg.Logger.Error(err, "failed to list cached endpoints",
+				log.Str("service", cfg.Service),
+				log.Str("namespace", cfg.Namespace))
+			return
+		}
+		notify(EndpointsFromSlices(items))
+	}
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { refresh() },
+		UpdateFunc: func(any, any) { refresh() },
+		DeleteFunc: func(any) { refresh() },
+	}); err != nil {
+		return fmt.Errorf("failed to register endpoints handler: %w", err)
+	}
+
+	cfg.Logger.Info("starting endpoints watcher",
+		log.Str("service", cfg.Service),
+		log.Str("namespace", cfg.Namespace))
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil // ctx done before the initial list completed
+	}
+	close(synced)
+	refresh()
+
+	<-ctx.Done()
+	cfg.Logger.Info("endpoints watcher stopped",
+		log.Str("service", cfg.Service),
+		log.Str("namespace", cfg.Namespace))
+	return nil
+}
+
+// watchDNS polls the service's DNS name until ctx is done.
+func watchDNS(ctx context.Context, cfg EndpointsConfig, onUpdate func([]Endpoint)) error {
+	lookup := cfg.LookupHost
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupHost
+	}
+	host := fmt.Sprintf("%s.%s.svc", cfg.Service, cfg.Namespace)
+	notify := changeNotifier(onUpdate)
+
+	ticker := time.NewTicker(cfg.DNSInterval)
+	defer ticker.Stop()
+	for {
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			// Keep reporting the last known endpoints while DNS fails.
+			cfg.Logger.Warn("failed to resolve service",
+				log.Str("host", host),
+				log.Str("error", err.Error()))
+		} else {
+			endpoints := make([]Endpoint, 0, len(addrs))
+			for _, addr := range addrs {
+				endpoints = append(endpoints, Endpoint{Host: addr, Port: cfg.FallbackPort})
+			}
+			notify(sortEndpoints(endpoints))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// changeNotifier returns a function calling onUpdate on the first call and
+// whenever the endpoints differ from the previous call.
+func changeNotifier(onUpdate func([]Endpoint)) func([]Endpoint) {
+	var (
+		mu       sync.Mutex
+		last     []Endpoint
+		notified bool
+	)
+	return func(endpoints []Endpoint) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if notified && slices.Equal(last, endpoints) {
+			return
+		}
+		last, notified = endpoints, true
+		if onUpdate != nil {
+			onUpdate(slices.Clone(endpoints))
+		}
+	}
+}
+
+// EndpointsFromSlices returns the ready endpoints of the given slices,
+// one per address and port, sorted and without duplicates.
+func EndpointsFromSlices(items []*discoveryv1.EndpointSlice) []Endpoint {
+	var endpoints []Endpoint
+	for _, es := range items {
+		for _, ep := range es.Endpoints {
+			// A nil Ready condition means ready, per the EndpointSlice API.
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			for _, addr := range ep.Addresses {
+				base := Endpoint{
+					Host:     addr,
+					Hostname: deref(ep.Hostname),
+					NodeName: deref(ep.NodeName),
+					Zone:     deref(ep.Zone),
+				}
+				if len(es.Ports) == 0 {
+					endpoints = append(endpoints, base)
+				}
+				for _, port := range es.Ports {
+					e := base
+					e.Port = derefInt32(port.Port)
+					e.PortName = deref(port.Name)
+					endpoints = append(endpoints, e)
+				}
+			}
+		}
+	}
+	return slices.Compact(sortEndpoints(endpoints))
+}
+
+// sortEndpoints sorts endpoints by host and port, then by the remaining fields.
+func sortEndpoints(endpoints []Endpoint) []Endpoint {
+	slices.SortFunc(endpoints, func(a, b Endpoint) int {
+		return cmp.Or(
+			cmp.Compare(a.Host, b.Host),
+			cmp.Compare(a.Port, b.Port),
+			cmp.Compare(a.PortName, b.PortName),
+			cmp.Compare(a.Hostname, b.Hostname),
+			cmp.Compare(a.NodeName, b.NodeName),
+			cmp.Compare(a.Zone, b.Zone),
+		)
+	})
+	return endpoints
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefInt32(n *int32) int32 {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
@@ -0,0 +1,197 @@
+// Package internal provides tests for Kubernetes endpoint watching.
+package internal
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func testSlice(name string, ready map[string]*bool, ports ...int32) *discoveryv1.EndpointSlice {
+	es := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "backend"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, port := range ports {
+		es.Ports = append(es.Ports, discoveryv1.EndpointPort{Port: ptr(port)})
+	}
+	for addr, r := range ready {
+		es.Endpoints = append(es.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{addr},
+			Conditions: discoveryv1.EndpointConditions{Ready: r},
+		})
+	}
+	return es
+}
+
+func addresses(endpoints []Endpoint) []string {
+	out := make([]string, len(endpoints))
+	for i, e := range endpoints {
+		out[i] = e.Address()
+	}
+	return out
+}
+
+func TestEndpointsFromSlices(t *testing.T) {
+	got := EndpointsFromSlices([]*discoveryv1.EndpointSlice{
+		testSlice("a", map[string]*bool{
+			"10.0.0.2": ptr(true),
+			"10.0.0.1": nil,
+			"10.0.0.3": ptr(false),
+		}, 8080, 9090),
+		// Duplicate of a ready endpoint reported by a second slice.
+		testSlice("b", map[string]*bool{"10.0.0.1": ptr(true)}, 8080),
+	})
+
+	want := []string{"10.0.0.1:8080", "10.0.0.1:9090", "10.0.0.2:8080", "10.0.0.2:9090"}
+	if !slices.Equal(addresses(got), want) {
+		t.Errorf("EndpointsFromSlices() = %v, want %v", addresses(got), want)
+	}
+}
+
+func TestEndpoint_Address(t *testing.T) {
+	tests := []struct {
+		endpoint Endpoint
+		want     string
+	}{
+		{endpoint: Endpoint{Host: "10.0.0.1", Port: 80}, want: "10.0.0.1:80"},
+		{endpoint: Endpoint{Host: "fd00::1", Port: 80}, want: "[fd00::1]:80"},
+		{endpoint: Endpoint{Host: "10.0.0.1"}, want: "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		if got := tt.endpoint.Address(); got != tt.want {
+			t.Errorf("Address() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// receive waits for the next endpoints update.
+func receive(t *testing.T, updates <-chan []Endpoint) []string {
+	t.Helper()
+	select {
+	case endpoints := <-updates:
+		return addresses(endpoints)
+	case <-time.After(3 * time.Second):
+		t.Fatal("no endpoints update received")
+		return nil
+	}
+}
+
+func TestWatchEndpoints_Informer(t *testing.T) {
+	client := fake.NewSimpleClientset(testSlice("a", map[string]*bool{"10.0.0.1": ptr(true)}, 8080))
+	updates := make(chan []Endpoint, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchEndpoints(ctx, client, EndpointsConfig{
+			Service:   "backend",
+			Namespace: "default",
+			Logger:    &recordingLogger{},
+		}, func(endpoints []Endpoint) { updates <- endpoints })
+	}()
+
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.1:8080"}) {
+		t.Errorf("initial endpoints = %v, want [10.0.0.1:8080]", got)
+	}
+
+	sliceClient := client.DiscoveryV1().EndpointSlices("default")
+	if _, err := sliceClient.Update(ctx, testSlice("a", map[string]*bool{
+		"10.0.0.1": ptr(false),
+		"10.0.0.2": ptr(true),
+	}, 8080), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := receive(t, updates); len(got) != 1 || got[0] != "10.0.0.2:8080" {
+		t.Errorf("updated endpoints = %v, want [10.0.0.2:8080]", got)
+	}
+
+	if err := sliceClient.Delete(ctx, "a", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := receive(t, updates); len(got) != 0 {
+		t.Errorf("endpoints after delete = %v, want none", got)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WatchEndpoints() error = %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("WatchEndpoints() did not return after cancel")
+	}
+}
+
+func TestWatchEndpoints_DNSFallback(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "endpointslices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "discovery.k8s.io", Resource: "endpointslices"}, "", nil)
+	})
+
+	lookups := make(chan string, 10)
+	answers := [][]string{{"10.0.0.2", "10.0.0.1"}, {"10.0.0.2", "10.0.0.1"}, {"10.0.0.3"}}
+	updates := make(chan []Endpoint, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchEndpoints(ctx, client, EndpointsConfig{
+		Service:      "backend",
+		Namespace:    "prod",
+		Logger:       &recordingLogger{},
+		FallbackPort: 8080,
+		DNSInterval:  10 * time.Millisecond,
+		LookupHost: func(ctx context.Context, host string) ([]string, error) {
+			lookups <- host
+			if len(answers) > 1 {
+				answer := answers[0]
+				answers = answers[1:]
+				return answer, nil
+			}
+			return answers[0], nil
+		},
+	}, func(endpoints []Endpoint) { updates <- endpoints })
+
+	if host := <-lookups; host != "backend.prod.svc" {
+		t.Errorf("looked up %q, want backend.prod.svc", host)
+	}
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.1:8080", "10.0.0.2:8080"}) {
+		t.Errorf("initial endpoints = %v, want sorted DNS answers", got)
+	}
+	// The unchanged second answer is not reported.
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.3:8080"}) {
+		t.Errorf("updated endpoints = %v, want [10.0.0.3:8080]", got)
+	}
+}
+
+func TestWatchEndpoints_ListError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "endpointslices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("unavailable")
+	})
+
+	err := WatchEndpoints(context.Background(), client, EndpointsConfig{
+		Service:   "backend",
+		Namespace: "default",
+		Logger:    &recordingLogger{},
+	}, nil)
+	if err == nil {
+		t.Error("WatchEndpoints() error = nil, want list error")
+	}
+}
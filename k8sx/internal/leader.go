@@ -0,0 +1,121 @@
+// Package internal contains Kubernetes lease-based leader election.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// LeaderConfig configures RunLeaderElection.
+type LeaderConfig struct {
+	Name          string        // Lease name
+	Namespace     string        // Lease namespace
+	Identity      string        // Unique identity of this candidate
+	LeaseDuration time.Duration // How long non-leaders wait before taking over
+	RenewDeadline time.Duration // How long the leader retries renewing before giving up
+	RetryPeriod   time.Duration // Interval between acquire and renew attempts
+	Logger        log.Logger
+}
+
+// RunLeaderElection campaigns for the lease until ctx is done. Each time
+// leadership is acquired, onStarted runs with a context cancelled when
+// leadership is lost; onStopped runs after onStarted has returned. After
+// losing leadership the candidate campaigns again.
+//
+// The lease is released when ctx is cancelled, so another candidate can take
+// over without waiting for the lease to expire.
+func RunLeaderElection(ctx context.Context, client kubernetes.Interface, cfg LeaderConfig, onStarted func(ctx context.Context), onStopped func()) error {
+	lock := &trackedLock{Interface: &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      cfg.Name,
+			Namespace: cfg.Namespace,
+		},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: cfg.Identity},
+	}}
+
+	for ctx.Err() == nil {
+		done := make(chan struct{})
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   cfg.LeaseDuration,
+			RenewDeadline:   cfg.RenewDeadline,
+			RetryPeriod:     cfg.RetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            cfg.Name,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(leaderCtx context.Context) {
+					defer close(done)
+					cfg.Logger.Info("acquired leadership",
+						log.Str("lease", cfg.Name),
+						log.Str("namespace", cfg.Namespace),
+						log.Str("identity", cfg.Identity))
+					if onStarted != nil {
+						onStarted(leaderCtx)
+					}
+				},
+				// Called even when leadership was never acquired, so
+				// leadership loss is reported after Run returns instead.
+				OnStoppedLeading: func() {},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("invalid leader election config: %w", err)
+		}
+
+		lock.held.Store(false)
+		elector.Run(ctx)
+		if !lock.held.Load() {
+			// ctx was cancelled before leadership was acquired.
+			continue
+		}
+
+		// Wait for onStarted so jobs of consecutive terms never overlap.
+		<-done
+		cfg.Logger.Info("lost leadership",
+			log.Str("lease", cfg.Name),
+			log.Str("namespace", cfg.Namespace),
+			log.Str("identity", cfg.Identity))
+		if onStopped != nil {
+			onStopped()
+		}
+	}
+
+	return nil
+}
+
+// trackedLock records whether this candidate wrote itself into the lease,
+// which the elector does exactly when it acquires or renews leadership.
+type trackedLock struct {
+	resourcelock.Interface
+	held atomic.Bool
+}
+
+// Create implements resourcelock.Interface.
+func (l *trackedLock) Create(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	err := l.Interface.Create(ctx, record)
+	l.track(record, err)
+	return err
+}
+
+// Update implements resourcelock.Interface.
+func (l *trackedLock) Update(ctx context.Context, record resourcelock.LeaderElectionRecord) error {
+	err := l.Interface.Update(ctx, record)
+	l.track(record, err)
+	return err
+}
+
+func (l *trackedLock) track(record resourcelock.LeaderElectionRecord, err error) {
+	if err == nil && record.HolderIdentity == l.Identity() {
+		l.held.Store(true)
+	}
+}
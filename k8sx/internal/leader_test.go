@@ -0,0 +1,150 @@
+// Package internal provides tests for Kubernetes leader election.
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testLeaderConfig(identity string) LeaderConfig {
+	return LeaderConfig{
+		Name:          "jobs",
+		Namespace:     "default",
+		Identity:      identity,
+		LeaseDuration: 2 * time.Second,
+		RenewDeadline: time.Second,
+		RetryPeriod:   50 * time.Millisecond,
+		Logger:        &recordingLogger{},
+	}
+}
+
+func TestRunLeaderElection_SingleLeaderAndHandover(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	var leaders atomic.Int32
+	var overlap atomic.Bool
+	started := make(chan string, 2)
+	run := func(ctx context.Context, identity string, stopped chan<- struct{}) {
+		err := RunLeaderElection(ctx, client, testLeaderConfig(identity), func(ctx context.Context) {
+			if leaders.Add(1) > 1 {
+				overlap.Store(true)
+			}
+			started <- identity
+			<-ctx.Done()
+			leaders.Add(-1)
+		}, func() {
+			stopped <- struct{}{}
+		})
+		if err != nil {
+			t.Errorf("RunLeaderElection(%s) error = %v", identity, err)
+		}
+	}
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	stoppedA := make(chan struct{}, 1)
+	doneA := make(chan struct{})
+	go func() {
+		defer close(doneA)
+		run(ctxA, "a", stoppedA)
+	}()
+
+	select {
+	case id := <-started:
+		if id != "a" {
+			t.Fatalf("first leader = %q, want a", id)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no leader elected")
+	}
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	stoppedB := make(chan struct{}, 1)
+	doneB := make(chan struct{})
+	go func() {
+		defer close(doneB)
+		run(ctxB, "b", stoppedB)
+	}()
+
+	// b must not lead while a holds the lease.
+	select {
+	case id := <-started:
+		t.Fatalf("%q became leader while a holds the lease", id)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancelA()
+	<-doneA
+	select {
+	case <-stoppedA:
+	default:
+		t.Error("onStopped not called for a")
+	}
+
+	// The released lease lets b take over well before it would expire.
+	select {
+	case id := <-started:
+		if id != "b" {
+			t.Errorf("second leader = %q, want b", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("b did not take over after a released the lease")
+	}
+
+	cancelB()
+	<-doneB
+	if overlap.Load() {
+		t.Error("two leaders ran at the same time")
+	}
+}
+
+func TestRunLeaderElection_CancelledBeforeLeading(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	// Another candidate holds a fresh lease.
+	holder, cancelHolder := context.WithCancel(context.Background())
+	defer cancelHolder()
+	leading := make(chan struct{})
+	go RunLeaderElection(holder, client, testLeaderConfig("holder"), func(ctx context.Context) {
+		close(leading)
+		<-ctx.Done()
+	}, nil)
+	<-leading
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	stopped := false
+	err := RunLeaderElection(ctx, client, testLeaderConfig("waiter"), func(ctx context.Context) {
+		t.Error("waiter became leader")
+	}, func() {
+		stopped = true
+	})
+	if err != nil {
+		t.Errorf("RunLeaderElection() error = %v", err)
+	}
+	if stopped {
+		t.Error("onStopped called without leadership")
+	}
+
+	lease, err := client.CoordinationV1().Leases("default").Get(context.Background(), "jobs", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get lease error = %v", err)
+	}
+	if got := *lease.Spec.HolderIdentity; got != "holder" {
+		t.Errorf("lease holder = %q, want holder", got)
+	}
+}
+
+func TestRunLeaderElection_InvalidConfig(t *testing.T) {
+	cfg := testLeaderConfig("a")
+	cfg.RenewDeadline = cfg.LeaseDuration
+
+	if err := RunLeaderElection(context.Background(), fake.NewSimpleClientset(), cfg, nil, nil); err == nil {
+		t.Error("RunLeaderElection() error = nil, want invalid config error")
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 )
 
 // ServiceResolver resolves Kubernetes services to endpoints.
@@ -18,25 +17,10 @@ type ServiceResolver struct {
 }
 
 // NewServiceResolver creates a new service resolver.
-func NewServiceResolver() (*ServiceResolver, error) {
-	// Create Kubernetes client
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		// Fallback to kubeconfig for local development
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Kubernetes config: %w", err)
-		}
-	}
-
-	client, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
-	}
-
+func NewServiceResolver(client kubernetes.Interface) *ServiceResolver {
 	return &ServiceResolver{
 		client: client,
-	}, nil
+	}
 }
 
 // ResolveHeadlessService resolves a headless service to individual pod endpoints.
@@ -1,4 +1,4 @@
-// Package internal contains Kubernetes ConfigMap watcher implementation.
+// Package internal contains Kubernetes ConfigMap and Secret watcher implementations.
 package internal
 
 import (
@@ -11,7 +11,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 
 	"go.eggybyte.com/egg/core/log"
 )
@@ -29,8 +28,9 @@ type ConfigMapWatcher struct {
 }
 
 // NewConfigMapWatcher creates a new ConfigMap watcher.
-func NewConfigMapWatcher(name, namespace string, logger log.Logger, onUpdate func(data map[string]string)) *ConfigMapWatcher {
+func NewConfigMapWatcher(client kubernetes.Interface, name, namespace string, logger log.Logger, onUpdate func(data map[string]string)) *ConfigMapWatcher {
 	return &ConfigMapWatcher{
+		client:    client,
 		name:      name,
 		namespace: namespace,
 		logger:    logger,
@@ -48,19 +48,8 @@ func (w *ConfigMapWatcher) Start(ctx context.Context) error {
 		return fmt.Errorf("watcher is already running")
 	}
 
-	// Create Kubernetes client
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		// Fallback to kubeconfig for local development
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return fmt.Errorf("failed to create Kubernetes config: %w", err)
-		}
-	}
-
-	w.client, err = kubernetes.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	if w.client == nil {
+		return fmt.Errorf("kubernetes client is nil")
 	}
 
 	w.logger.Info("starting ConfigMap watcher",
@@ -68,7 +57,14 @@ func (w *ConfigMapWatcher) Start(ctx context.Context) error {
 		log.Str("namespace", w.namespace))
 
 	// Start watching in a goroutine
-	go w.watch(ctx)
+	go watchLoop(ctx, w.stopCh, watchTarget{
+		kind:      "ConfigMap",
+		name:      w.name,
+		namespace: w.namespace,
+		logger:    w.logger,
+		watch:     w.client.CoreV1().ConfigMaps(w.namespace).Watch,
+		handle:    w.handle,
+	})
 
 	w.isRunning = true
 	return nil
@@ -92,37 +88,194 @@ func (w *ConfigMapWatcher) Stop(ctx context.Context) error {
 	return nil
 }
 
-// watch performs the actual watching of the ConfigMap.
-func (w *ConfigMapWatcher) watch(ctx context.Context) {
-	defer func() {
-		w.logger.Info("ConfigMap watcher stopped",
+// handle delivers ConfigMap events to the update callback.
+func (w *ConfigMapWatcher) handle(event watch.Event) {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if cm, ok := event.Object.(*corev1.ConfigMap); ok {
+			w.logger.Info("ConfigMap updated",
+				log.Str("name", cm.Name),
+				log.Str("namespace", cm.Namespace),
+				log.Int("data_keys", len(cm.Data)))
+
+			if w.onUpdate != nil {
+				w.onUpdate(cm.Data)
+			}
+		}
+	case watch.Deleted:
+		w.logger.Info("ConfigMap deleted",
+			log.Str("name", w.name),
+			log.Str("namespace", w.namespace))
+
+		if w.onUpdate != nil {
+			w.onUpdate(make(map[string]string))
+		}
+	case watch.Error:
+		w.logger.Error(nil, "ConfigMap watcher error",
+			log.Str("name", w.name),
+			log.Str("namespace", w.namespace))
+	}
+}
+
+// SecretWatcher watches a Secret for changes using Kubernetes client-go.
+// Secret values are passed to the callback but never logged.
+type SecretWatcher struct {
+	name      string
+	namespace string
+	logger    log.Logger
+	onUpdate  func(data map[string][]byte)
+	client    kubernetes.Interface
+	stopCh    chan struct{}
+	mu        sync.RWMutex
+	isRunning bool
+}
+
+// NewSecretWatcher creates a new Secret watcher.
+func NewSecretWatcher(client kubernetes.Interface, name, namespace string, logger log.Logger, onUpdate func(data map[string][]byte)) *SecretWatcher {
+	return &SecretWatcher{
+		client:    client,
+		name:      name,
+		namespace: namespace,
+		logger:    logger,
+		onUpdate:  onUpdate,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start starts watching the Secret.
+func (w *SecretWatcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isRunning {
+		return fmt.Errorf("watcher is already running")
+	}
+
+	if w.client == nil {
+		return fmt.Errorf("kubernetes client is nil")
+	}
+
+	w.logger.Info("starting Secret watcher",
+		log.Str("name", w.name),
+		log.Str("namespace", w.namespace))
+
+	go watchLoop(ctx, w.stopCh, watchTarget{
+		kind:      "Secret",
+		name:      w.name,
+		namespace: w.namespace,
+		logger:    w.logger,
+		watch:     w.client.CoreV1().Secrets(w.namespace).Watch,
+		handle:    w.handle,
+	})
+
+	w.isRunning = true
+	return nil
+}
+
+// Stop stops watching the Secret.
+func (w *SecretWatcher) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.isRunning {
+		return nil
+	}
+
+	w.logger.Info("stopping Secret watcher",
+		log.Str("name", w.name),
+		log.Str("namespace", w.namespace))
+
+	close(w.stopCh)
+	w.isRunning = false
+	return nil
+}
+
+// handle delivers Secret events to the update callback. Data holds the
+// decoded values: the API server's base64 encoding is removed when the
+// object is decoded. Only key counts are logged.
+func (w *SecretWatcher) handle(event watch.Event) {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		if secret, ok := event.Object.(*corev1.Secret); ok {
+			w.logger.Info("Secret updated",
+				log.Str("name", secret.Name),
+				log.Str("namespace", secret.Namespace),
+				log.Int("data_keys", len(secret.Data)))
+
+			if w.onUpdate != nil {
+				w.onUpdate(secretData(secret))
+			}
+		}
+	case watch.Deleted:
+		w.logger.Info("Secret deleted",
 			log.Str("name", w.name),
 			log.Str("namespace", w.namespace))
+
+		if w.onUpdate != nil {
+			w.onUpdate(make(map[string][]byte))
+		}
+	case watch.Error:
+		w.logger.Error(nil, "Secret watcher error",
+			log.Str("name", w.name),
+			log.Str("namespace", w.namespace))
+	}
+}
+
+// secretData returns the Secret's values, including write-only StringData
+// entries that were not yet merged into Data by the API server.
+func secretData(secret *corev1.Secret) map[string][]byte {
+	data := make(map[string][]byte, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		data[k] = v
+	}
+	for k, v := range secret.StringData {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// watchTarget describes a single named object watched by watchLoop.
+type watchTarget struct {
+	kind      string
+	name      string
+	namespace string
+	logger    log.Logger
+	watch     func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	handle    func(event watch.Event)
+}
+
+// watchLoop watches the target, recreating the watch when it fails or the
+// result channel closes, until ctx is done or stopCh is closed.
+func watchLoop(ctx context.Context, stopCh <-chan struct{}, t watchTarget) {
+	defer func() {
+		t.logger.Info(t.kind+" watcher stopped",
+			log.Str("name", t.name),
+			log.Str("namespace", t.namespace))
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-w.stopCh:
+		case <-stopCh:
 			return
 		default:
 		}
 
 		// Create watcher
-		watcher, err := w.client.CoreV1().ConfigMaps(w.namespace).Watch(ctx, metav1.ListOptions{
-			FieldSelector: fmt.Sprintf("metadata.name=%s", w.name),
+		watcher, err := t.watch(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("metadata.name=%s", t.name),
 		})
 		if err != nil {
-			w.logger.Error(err, "failed to create ConfigMap watcher",
-				log.Str("name", w.name),
-				log.Str("namespace", w.namespace))
+			t.logger.Error(err, "failed to create "+t.kind+" watcher",
+				log.Str("name", t.name),
+				log.Str("namespace", t.namespace))
 
 			// Wait before retrying
 			select {
 			case <-ctx.Done():
 				return
-			case <-w.stopCh:
+			case <-stopCh:
 				return
 			case <-time.After(5 * time.Second):
 				continue
@@ -137,41 +290,16 @@ func (w *ConfigMapWatcher) watch(ctx context.Context) {
 				select {
 				case <-ctx.Done():
 					return
-				case <-w.stopCh:
+				case <-stopCh:
 					return
 				case event, ok := <-watcher.ResultChan():
 					if !ok {
-						w.logger.Warn("ConfigMap watcher channel closed",
-							log.Str("name", w.name),
-							log.Str("namespace", w.namespace))
+						t.logger.Warn(t.kind+" watcher channel closed",
+							log.Str("name", t.name),
+							log.Str("namespace", t.namespace))
 						return
 					}
-
-					switch event.Type {
-					case watch.Added, watch.Modified:
-						if cm, ok := event.Object.(*corev1.ConfigMap); ok {
-							w.logger.Info("ConfigMap updated",
-								log.Str("name", cm.Name),
-								log.Str("namespace", cm.Namespace),
-								log.Int("data_keys", len(cm.Data)))
-
-							if w.onUpdate != nil {
-								w.onUpdate(cm.Data)
-							}
-						}
-					case watch.Deleted:
-						w.logger.Info("ConfigMap deleted",
-							log.Str("name", w.name),
-							log.Str("namespace", w.namespace))
-
-						if w.onUpdate != nil {
-							w.onUpdate(make(map[string]string))
-						}
-					case watch.Error:
-						w.logger.Error(nil, "ConfigMap watcher error",
-							log.Str("name", w.name),
-							log.Str("namespace", w.namespace))
-					}
+					t.handle(event)
 				}
 			}
 		}()
@@ -180,7 +308,7 @@ func (w *ConfigMapWatcher) watch(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-w.stopCh:
+		case <-stopCh:
 			return
 		case <-time.After(1 * time.Second):
 		}
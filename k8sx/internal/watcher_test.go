@@ -0,0 +1,159 @@
+// Package internal provides tests for Kubernetes ConfigMap and Secret watchers.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// recordingLogger records every message with its key-value pairs.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) record(msg string, kv []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(msg, kv))
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) { l.record(msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordingLogger) Error(err error, msg string, kv ...interface{}) {
+	l.record(msg, kv)
+}
+func (l *recordingLogger) With(kv ...interface{}) log.Logger { return l }
+
+func (l *recordingLogger) output() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+// fakeWatchClient returns a fake clientset whose watches on resource are
+// served by the returned FakeWatcher.
+func fakeWatchClient(resource string) (*fake.Clientset, *watch.FakeWatcher) {
+	client := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	client.PrependWatchReactor(resource, k8stesting.DefaultWatchReactor(watcher, nil))
+	return client, watcher
+}
+
+func TestSecretWatcher_DeliversDecodedData(t *testing.T) {
+	client, fw := fakeWatchClient("secrets")
+	logger := &recordingLogger{}
+	updates := make(chan map[string][]byte, 2)
+
+	w := NewSecretWatcher(client, "db", "default", logger, func(data map[string][]byte) {
+		updates <- data
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop(ctx)
+
+	fw.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+		StringData: map[string]string{"user": "admin"},
+	})
+
+	select {
+	case data := <-updates:
+		if string(data["password"]) != "hunter2" || string(data["user"]) != "admin" {
+			t.Errorf("onUpdate() data = %q, want decoded password and user", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("onUpdate() not called after Added event")
+	}
+
+	fw.Delete(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}})
+	select {
+	case data := <-updates:
+		if len(data) != 0 {
+			t.Errorf("onUpdate() after delete = %q, want empty", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("onUpdate() not called after Deleted event")
+	}
+
+	if out := logger.output(); strings.Contains(out, "hunter2") || strings.Contains(out, "admin") {
+		t.Errorf("logs contain secret values:\n%s", out)
+	}
+}
+
+func TestSecretWatcher_StopsOnContextCancel(t *testing.T) {
+	client, fw := fakeWatchClient("secrets")
+	logger := &recordingLogger{}
+
+	w := NewSecretWatcher(client, "db", "default", logger, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := w.Start(ctx); err == nil {
+		t.Error("Start() twice should fail")
+	}
+	// Wait until the watch is established.
+	fw.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}})
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !fw.IsStopped() {
+		if time.Now().After(deadline) {
+			t.Fatal("watch not stopped after context cancel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := w.Stop(context.Background()); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestConfigMapWatcher_DeliversData(t *testing.T) {
+	client, fw := fakeWatchClient("configmaps")
+	updates := make(chan map[string]string, 1)
+
+	w := NewConfigMapWatcher(client, "app", "default", &recordingLogger{}, func(data map[string]string) {
+		updates <- data
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer w.Stop(ctx)
+
+	fw.Modify(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Data:       map[string]string{"LOG_LEVEL": "debug"},
+	})
+
+	select {
+	case data := <-updates:
+		if data["LOG_LEVEL"] != "debug" {
+			t.Errorf("onUpdate() data = %v, want LOG_LEVEL=debug", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("onUpdate() not called after Modified event")
+	}
+}
@@ -0,0 +1,31 @@
+// Package k8stest provides an in-memory Kubernetes API for testing code
+// built on k8sx.
+//
+// # Overview
+//
+// A Cluster wraps a fake client-go clientset and exposes it as a *k8sx.Client.
+// Tests pass that client to WatchConfigMap, WatchSecret, WatchEndpoints or
+// RunLeaderElection, then push changes with SetConfigMap, SetSecret and
+// SetEndpoints to simulate updates without a real cluster.
+//
+// Watches behave like the API server: a new watch first reports the
+// objects that already exist, so changes pushed before the watcher starts
+// are not lost.
+//
+// # Usage
+//
+//	cluster := k8stest.New(t)
+//	updates := make(chan map[string]string, 1)
+//	go k8sx.WatchConfigMap(ctx, "app-config", k8sx.WatchOptions{
+//		Client:   cluster.Client(),
+//		Debounce: 50 * time.Millisecond,
+//		Logger:   logger,
+//	}, func(data map[string]string) { updates <- data })
+//
+//	cluster.SetConfigMap("default", "app-config", map[string]string{"LOG_LEVEL": "debug"})
+//	data := <-updates
+//
+// # Stability
+//
+// Experimental until v0.1.0.
+package k8stest
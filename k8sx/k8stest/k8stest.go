@@ -0,0 +1,359 @@
+package k8stest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"go.eggybyte.com/egg/k8sx"
+)
+
+var (
+	configMapsResource     = corev1.SchemeGroupVersion.WithResource("configmaps")
+	secretsResource        = corev1.SchemeGroupVersion.WithResource("secrets")
+	endpointSlicesResource = discoveryv1.SchemeGroupVersion.WithResource("endpointslices")
+)
+
+// watchedKinds maps the resources whose watches report existing objects
+// first to their kinds.
+var watchedKinds = map[schema.GroupVersionResource]schema.GroupVersionKind{
+	configMapsResource:     corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	secretsResource:        corev1.SchemeGroupVersion.WithKind("Secret"),
+	endpointSlicesResource: discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"),
+}
+
+// Cluster is an in-memory Kubernetes API backed by a fake clientset.
+// Its methods are safe for concurrent use, but report failures with
+// t.Fatalf and so must be called from the test goroutine.
+type Cluster struct {
+	t         testing.TB
+	clientset *fake.Clientset
+	client    *k8sx.Client
+
+	// mu orders pushed changes against new watches so that each change is
+	// reported exactly once.
+	mu sync.Mutex
+}
+
+// New creates a cluster holding the given objects.
+//
+// Parameters:
+//   - t: Test used to report failures
+//   - objects: Initial objects, e.g. a *corev1.Service for k8sx.Resolve
+//
+// Returns:
+//   - *Cluster: Cluster ready to pass to k8sx via Client()
+//
+// Example:
+//
+//	cluster := k8stest.New(t)
+//	err := k8sx.WatchSecret(ctx, "db", k8sx.WatchOptions{Client: cluster.Client(), Logger: logger}, onUpdate)
+func New(t testing.TB, objects ...runtime.Object) *Cluster {
+	t.Helper()
+
+	c := &Cluster{t: t, clientset: fake.NewSimpleClientset(objects...)}
+	for gvr := range watchedKinds {
+		c.clientset.PrependWatchReactor(gvr.Resource, c.watch)
+	}
+
+	client, err := k8sx.NewClient(k8sx.ClientOptions{Clientset: c.clientset})
+	if err != nil {
+		t.Fatalf("k8stest: failed to create client: %v", err)
+	}
+	c.client = client
+	return c
+}
+
+// Client returns the k8sx client to set in WatchOptions, EndpointsOptions
+// or LeaderOptions.
+func (c *Cluster) Client() *k8sx.Client {
+	return c.client
+}
+
+// Clientset returns the underlying fake clientset, for objects and
+// reactions the Set methods do not cover.
+func (c *Cluster) Clientset() *fake.Clientset {
+	return c.clientset
+}
+
+// SetConfigMap creates the ConfigMap or replaces its data.
+// An empty namespace means "default".
+func (c *Cluster) SetConfigMap(namespace, name string, data map[string]string) {
+	c.t.Helper()
+	c.set(configMapsResource, &corev1.ConfigMap{
+		ObjectMeta: objectMeta(namespace, name, nil),
+		Data:       data,
+	})
+}
+
+// DeleteConfigMap deletes the ConfigMap.
+func (c *Cluster) DeleteConfigMap(namespace, name string) {
+	c.t.Helper()
+	c.delete(configMapsResource, namespace, name)
+}
+
+// SetSecret creates the Secret or replaces its data. Values are given
+// decoded, as WatchSecret reports them.
+// An empty namespace means "default".
+func (c *Cluster) SetSecret(namespace, name string, data map[string][]byte) {
+	c.t.Helper()
+	c.set(secretsResource, &corev1.Secret{
+		ObjectMeta: objectMeta(namespace, name, nil),
+		Data:       data,
+	})
+}
+
+// DeleteSecret deletes the Secret.
+func (c *Cluster) DeleteSecret(namespace, name string) {
+	c.t.Helper()
+	c.delete(secretsResource, namespace, name)
+}
+
+// SetEndpoints replaces the endpoints of a service. All endpoints are
+// ready; call it without endpoints to simulate a service with no ready pods.
+// An empty namespace means "default".
+//
+// Endpoints are stored in one EndpointSlice per port, as the endpoint slice
+// controller does, so a change to a multi-port service is applied one slice
+// at a time.
+func (c *Cluster) SetEndpoints(namespace, service string, endpoints ...k8sx.Endpoint) {
+	c.t.Helper()
+
+	type port struct {
+		number int32
+		name   string
+	}
+	byPort := make(map[port][]discoveryv1.Endpoint)
+	for _, e := range endpoints {
+		p := port{number: e.Port, name: e.PortName}
+		byPort[p] = append(byPort[p], discoveryv1.Endpoint{
+			Addresses:  []string{e.Host},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr(true)},
+			Hostname:   optional(e.Hostname),
+			NodeName:   optional(e.NodeName),
+			Zone:       optional(e.Zone),
+		})
+	}
+	ports := make([]port, 0, len(byPort))
+	for p := range byPort {
+		ports = append(ports, p)
+	}
+	slices.SortFunc(ports, func(a, b port) int {
+		return cmp.Or(cmp.Compare(a.number, b.number), cmp.Compare(a.name, b.name))
+	})
+
+	serviceLabel := map[string]string{discoveryv1.LabelServiceName: service}
+	keep := make(map[string]bool, len(ports))
+	for i, p := range ports {
+		slice := &discoveryv1.EndpointSlice{
+			ObjectMeta:  objectMeta(namespace, fmt.Sprintf("%s-%d", service, i), serviceLabel),
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   byPort[p],
+		}
+		if p.number != 0 {
+			slice.Ports = []discoveryv1.EndpointPort{{Port: ptr(p.number), Name: optional(p.name)}}
+		}
+		keep[slice.Name] = true
+		c.set(endpointSlicesResource, slice)
+	}
+
+	// Remove slices of ports that are gone.
+	existing, err := c.clientset.Tracker().List(endpointSlicesResource, watchedKinds[endpointSlicesResource], namespaceOrDefault(namespace))
+	if err != nil {
+		c.t.Fatalf("k8stest: failed to list endpoint slices: %v", err)
+	}
+	for _, slice := range existing.(*discoveryv1.EndpointSliceList).Items {
+		if slice.Labels[discoveryv1.LabelServiceName] == service && !keep[slice.Name] {
+			c.delete(endpointSlicesResource, namespace, slice.Name)
+		}
+	}
+}
+
+// set creates or updates obj in the tracker.
+func (c *Cluster) set(gvr schema.GroupVersionResource, obj runtime.Object) {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		c.t.Fatalf("k8stest: invalid object: %v", err)
+	}
+	tracker := c.clientset.Tracker()
+	_, err = tracker.Get(gvr, m.GetNamespace(), m.GetName())
+	switch {
+	case apierrors.IsNotFound(err):
+		err = tracker.Create(gvr, obj, m.GetNamespace())
+	case err == nil:
+		err = tracker.Update(gvr, obj, m.GetNamespace())
+	}
+	if err != nil {
+		c.t.Fatalf("k8stest: failed to set %s %s/%s: %v", gvr.Resource, m.GetNamespace(), m.GetName(), err)
+	}
+}
+
+// delete removes an object from the tracker.
+func (c *Cluster) delete(gvr schema.GroupVersionResource, namespace, name string) {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	namespace = namespaceOrDefault(namespace)
+	if err := c.clientset.Tracker().Delete(gvr, namespace, name); err != nil {
+		c.t.Fatalf("k8stest: failed to delete %s %s/%s: %v", gvr.Resource, namespace, name, err)
+	}
+}
+
+// watch serves watches from the tracker. Unlike the default fake reactor,
+// it applies label and field selectors and, when no resource version is
+// given, reports existing objects first, as the API server does.
+func (c *Cluster) watch(action k8stesting.Action) (bool, watch.Interface, error) {
+	watchAction, ok := action.(k8stesting.WatchActionImpl)
+	if !ok {
+		return false, nil, nil
+	}
+	gvr := action.GetResource()
+	namespace := action.GetNamespace()
+	restrictions := watchAction.GetWatchRestrictions()
+	match := func(obj runtime.Object) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return false
+		}
+		return restrictions.Labels.Matches(labels.Set(m.GetLabels())) &&
+			restrictions.Fields.Matches(fields.Set{
+				"metadata.name":      m.GetName(),
+				"metadata.namespace": m.GetNamespace(),
+			})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tracker := c.clientset.Tracker()
+	source, err := tracker.Watch(gvr, namespace)
+	if err != nil {
+		return true, nil, err
+	}
+
+	var initial []runtime.Object
+	if restrictions.ResourceVersion == "" {
+		list, err := tracker.List(gvr, watchedKinds[gvr], namespace)
+		if err != nil {
+			source.Stop()
+			return true, nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			source.Stop()
+			return true, nil, err
+		}
+		for _, item := range items {
+			if match(item) {
+				initial = append(initial, item)
+			}
+		}
+	}
+
+	return true, newFilteredWatch(source, initial, match), nil
+}
+
+// filteredWatch reports initial objects as added, then the events of
+// source that match.
+type filteredWatch struct {
+	source watch.Interface
+	result chan watch.Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newFilteredWatch(source watch.Interface, initial []runtime.Object, match func(runtime.Object) bool) *filteredWatch {
+	w := &filteredWatch{
+		source: source,
+		result: make(chan watch.Event),
+		done:   make(chan struct{}),
+	}
+	go w.run(initial, match)
+	return w
+}
+
+func (w *filteredWatch) run(initial []runtime.Object, match func(runtime.Object) bool) {
+	defer close(w.result)
+
+	send := func(event watch.Event) bool {
+		select {
+		case w.result <- event:
+			return true
+		case <-w.done:
+			return false
+		}
+	}
+	for _, obj := range initial {
+		if !send(watch.Event{Type: watch.Added, Object: obj}) {
+			return
+		}
+	}
+	for {
+		select {
+		case event, ok := <-w.source.ResultChan():
+			if !ok {
+				return
+			}
+			if match(event.Object) && !send(event) {
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Stop implements watch.Interface.
+func (w *filteredWatch) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+		w.source.Stop()
+	})
+}
+
+// ResultChan implements watch.Interface.
+func (w *filteredWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func objectMeta(namespace, name string, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: namespaceOrDefault(namespace), Labels: labels}
+}
+
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,194 @@
+// Package k8stest provides tests for the fake cluster harness.
+package k8stest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"go.eggybyte.com/egg/core/log"
+	"go.eggybyte.com/egg/k8sx"
+)
+
+// nopLogger discards all log messages.
+type nopLogger struct{}
+
+func (nopLogger) With(kv ...any) log.Logger              { return nopLogger{} }
+func (nopLogger) Debug(msg string, kv ...any)            {}
+func (nopLogger) Info(msg string, kv ...any)             {}
+func (nopLogger) Warn(msg string, kv ...any)             {}
+func (nopLogger) Error(err error, msg string, kv ...any) {}
+
+// receive returns the next value from ch, failing the test after a timeout.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for callback")
+		var zero T
+		return zero
+	}
+}
+
+// expectNone fails the test if ch receives a value within d.
+func expectNone[T any](t *testing.T, ch <-chan T, d time.Duration) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Fatalf("unexpected callback: %v", v)
+	case <-time.After(d):
+	}
+}
+
+func TestWatchConfigMap(t *testing.T) {
+	cluster := New(t)
+	cluster.SetConfigMap("apps", "app-config", map[string]string{"LOG_LEVEL": "info"})
+	cluster.SetConfigMap("apps", "other-config", map[string]string{"LOG_LEVEL": "warn"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan map[string]string, 4)
+	go k8sx.WatchConfigMap(ctx, "app-config", k8sx.WatchOptions{
+		Namespace: "apps",
+		Client:    cluster.Client(),
+		Logger:    nopLogger{},
+	}, func(data map[string]string) { updates <- data })
+
+	// Existing ConfigMaps are reported when the watch starts.
+	if got := receive(t, updates); got["LOG_LEVEL"] != "info" {
+		t.Errorf("initial data = %v, want LOG_LEVEL=info", got)
+	}
+
+	cluster.SetConfigMap("apps", "app-config", map[string]string{"LOG_LEVEL": "debug"})
+	if got := receive(t, updates); got["LOG_LEVEL"] != "debug" {
+		t.Errorf("updated data = %v, want LOG_LEVEL=debug", got)
+	}
+
+	cluster.SetConfigMap("apps", "other-config", map[string]string{"LOG_LEVEL": "error"})
+	cluster.DeleteConfigMap("apps", "app-config")
+	if got := receive(t, updates); len(got) != 0 {
+		t.Errorf("data after delete = %v, want empty", got)
+	}
+	expectNone(t, updates, 100*time.Millisecond)
+}
+
+func TestWatchConfigMapDebounce(t *testing.T) {
+	cluster := New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan map[string]string, 4)
+	go k8sx.WatchConfigMap(ctx, "app-config", k8sx.WatchOptions{
+		Client:   cluster.Client(),
+		Debounce: 200 * time.Millisecond,
+		Logger:   nopLogger{},
+	}, func(data map[string]string) { updates <- data })
+
+	for _, level := range []string{"info", "warn", "debug"} {
+		cluster.SetConfigMap("", "app-config", map[string]string{"LOG_LEVEL": level})
+	}
+
+	if got := receive(t, updates); got["LOG_LEVEL"] != "debug" {
+		t.Errorf("debounced data = %v, want latest LOG_LEVEL=debug", got)
+	}
+	expectNone(t, updates, 400*time.Millisecond)
+}
+
+func TestWatchSecret(t *testing.T) {
+	cluster := New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan map[string][]byte, 4)
+	go k8sx.WatchSecret(ctx, "db", k8sx.WatchOptions{
+		Client: cluster.Client(),
+		Logger: nopLogger{},
+	}, func(data map[string][]byte) { updates <- data })
+
+	cluster.SetSecret("", "db", map[string][]byte{"password": []byte("hunter2")})
+	if got := receive(t, updates); string(got["password"]) != "hunter2" {
+		t.Errorf("secret data = %q, want password", got)
+	}
+
+	cluster.DeleteSecret("", "db")
+	if got := receive(t, updates); len(got) != 0 {
+		t.Errorf("secret data after delete = %q, want empty", got)
+	}
+}
+
+func TestWatchEndpoints(t *testing.T) {
+	cluster := New(t)
+	a := k8sx.Endpoint{Host: "10.0.0.1", Port: 8080, PortName: "http"}
+	b := k8sx.Endpoint{Host: "10.0.0.2", Port: 8080, PortName: "http", NodeName: "node-b"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan []k8sx.Endpoint, 8)
+	go k8sx.WatchEndpoints(ctx, "backend", k8sx.EndpointsOptions{
+		Client: cluster.Client(),
+		Logger: nopLogger{},
+	}, func(endpoints []k8sx.Endpoint) { updates <- endpoints })
+
+	if got := receive(t, updates); len(got) != 0 {
+		t.Errorf("initial endpoints = %v, want none", got)
+	}
+
+	cluster.SetEndpoints("", "backend", b, a)
+	if got, want := receive(t, updates), []k8sx.Endpoint{a, b}; !slices.Equal(got, want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+
+	cluster.SetEndpoints("", "backend", a)
+	if got, want := receive(t, updates), []k8sx.Endpoint{a}; !slices.Equal(got, want) {
+		t.Errorf("endpoints after scale down = %v, want %v", got, want)
+	}
+
+	cluster.SetEndpoints("", "backend")
+	if got := receive(t, updates); len(got) != 0 {
+		t.Errorf("endpoints after clear = %v, want none", got)
+	}
+}
+
+func TestSetEndpointsPorts(t *testing.T) {
+	cluster := New(t)
+	http := k8sx.Endpoint{Host: "10.0.0.1", Port: 8080, PortName: "http"}
+	grpc := k8sx.Endpoint{Host: "10.0.0.1", Port: 9090, PortName: "grpc"}
+
+	cluster.SetEndpoints("apps", "backend", http, grpc)
+	cluster.SetEndpoints("apps", "backend", grpc)
+
+	list, err := cluster.Clientset().DiscoveryV1().EndpointSlices("apps").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("got %d endpoint slices, want 1", len(list.Items))
+	}
+	if port := list.Items[0].Ports[0]; *port.Port != 9090 || *port.Name != "grpc" {
+		t.Errorf("port = %d/%s, want 9090/grpc", *port.Port, *port.Name)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cluster := New(t, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.96.0.10",
+			Ports:     []corev1.ServicePort{{Port: 80}},
+		},
+	})
+
+	endpoints, err := cluster.Client().Resolve(context.Background(), "backend", k8sx.ServiceKindClusterIP)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := []string{"10.96.0.10:80"}; !slices.Equal(endpoints, want) {
+		t.Errorf("Resolve() = %v, want %v", endpoints, want)
+	}
+}
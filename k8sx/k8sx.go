@@ -1,4 +1,4 @@
-// Package k8sx provides Kubernetes ConfigMap watching and service discovery.
+// Package k8sx provides Kubernetes ConfigMap and Secret watching and service discovery.
 //
 // Overview:
 //   - Responsibility: Watch ConfigMaps for configuration updates and resolve service endpoints
@@ -20,17 +20,21 @@ package k8sx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/k8sx/internal"
 )
 
-// WatchOptions holds configuration for ConfigMap watching.
+// WatchOptions holds configuration for ConfigMap and Secret watching.
 type WatchOptions struct {
 	Namespace    string        // Kubernetes namespace (default: current namespace)
 	ResyncPeriod time.Duration // Resync period for informer (default: 10 minutes)
+	Debounce     time.Duration // Quiet period before the callback runs with the latest data (default: 0, no debouncing)
+	Client       *Client       // Kubernetes client (default: NewClient(ClientOptions{}))
 	Logger       log.Logger    // Logger for watch operations
 }
 
@@ -46,26 +50,22 @@ const (
 
 // WatchConfigMap watches a ConfigMap for changes and calls the callback on updates.
 // The callback function receives the ConfigMap data as a map of string key-value pairs.
+// With WatchOptions.Debounce set, bursts of updates result in a single callback
+// with the latest data.
 // This function blocks until the context is cancelled or an error occurs.
 func WatchConfigMap(ctx context.Context, name string, opts WatchOptions, onUpdate func(data map[string]string)) error {
-	if opts.Logger == nil {
-		return fmt.Errorf("logger is required")
-	}
-
-	// Set default namespace if not provided
-	namespace := opts.Namespace
-	if namespace == "" {
-		namespace = "default"
+	namespace, err := watchNamespace(opts)
+	if err != nil {
+		return err
 	}
 
-	// Set default resync period
-	resyncPeriod := opts.ResyncPeriod
-	if resyncPeriod == 0 {
-		resyncPeriod = 10 * time.Minute
+	client, err := clientset(opts.Client)
+	if err != nil {
+		return fmt.Errorf("failed to start ConfigMap watcher: %w", err)
 	}
 
 	// Create and start the watcher
-	watcher := internal.NewConfigMapWatcher(name, namespace, opts.Logger, onUpdate)
+	watcher := internal.NewConfigMapWatcher(client, name, namespace, opts.Logger, internal.Debounce(ctx, opts.Debounce, onUpdate))
 
 	if err := watcher.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start ConfigMap watcher: %w", err)
@@ -82,21 +82,297 @@ func WatchConfigMap(ctx context.Context, name string, opts WatchOptions, onUpdat
 	return nil
 }
 
+// WatchSecret watches a Secret for changes and calls the callback on updates.
+// It mirrors WatchConfigMap, including debouncing and stop semantics.
+//
+// The callback receives decoded values: data["password"] holds the password
+// itself, not its base64 encoding. A deleted Secret is reported as an empty
+// map. Secret values are never logged; only the number of keys is.
+// This function blocks until the context is cancelled or an error occurs.
+//
+// Parameters:
+//   - ctx: Watch lifetime; cancelling it stops the watch and drops pending callbacks
+//   - name: Secret name
+//   - opts: Namespace, debounce and logger settings
+//   - onUpdate: Called with the Secret data on every (debounced) change
+//
+// Returns:
+//   - error: nil after ctx is cancelled; otherwise a configuration or client error
+//
+// Example:
+//
+//	err := k8sx.WatchSecret(ctx, "db-credentials", k8sx.WatchOptions{
+//	  Namespace: "production",
+//	  Debounce:  time.Second,
+//	  Logger:    logger,
+//	}, func(data map[string][]byte) {
+//	  pool.Rotate(string(data["username"]), string(data["password"]))
+//	})
+func WatchSecret(ctx context.Context, name string, opts WatchOptions, onUpdate func(data map[string][]byte)) error {
+	namespace, err := watchNamespace(opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := clientset(opts.Client)
+	if err != nil {
+		return fmt.Errorf("failed to start Secret watcher: %w", err)
+	}
+
+	watcher := internal.NewSecretWatcher(client, name, namespace, opts.Logger, internal.Debounce(ctx, opts.Debounce, onUpdate))
+
+	if err := watcher.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start Secret watcher: %w", err)
+	}
+
+	<-ctx.Done()
+
+	if err := watcher.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop Secret watcher: %w", err)
+	}
+
+	return nil
+}
+
+// watchNamespace validates opts and returns the namespace to watch.
+func watchNamespace(opts WatchOptions) (string, error) {
+	if opts.Logger == nil {
+		return "", fmt.Errorf("logger is required")
+	}
+
+	// Set default namespace if not provided
+	if opts.Namespace == "" {
+		return "default", nil
+	}
+	return opts.Namespace, nil
+}
+
+// DefaultDNSRefreshInterval is how often WatchEndpoints resolves DNS when
+// watching endpoints is forbidden.
+const DefaultDNSRefreshInterval = 30 * time.Second
+
+// Endpoint is a ready address and port backing a service.
+type Endpoint = internal.Endpoint
+
+// EndpointsOptions holds configuration for endpoint watching.
+type EndpointsOptions struct {
+	Namespace          string        // Service namespace (default: "default")
+	Debounce           time.Duration // Quiet period before the callback runs with the latest endpoints (default: 0)
+	FallbackPort       int32         // Port for DNS-resolved endpoints, which carry no port (default: 0)
+	DNSRefreshInterval time.Duration // DNS resolution interval in fallback mode (default: 30s)
+	Client             *Client       // Kubernetes client (default: NewClient(ClientOptions{}))
+	Logger             log.Logger    // Logger for watch operations (required)
+}
+
+// WatchEndpoints watches the EndpointSlices of a service and calls onUpdate
+// with its ready endpoints, first with the initial set and then whenever the
+// set changes, so a client-side load balancer can keep a live list.
+//
+// Endpoints that are not ready, such as starting or terminating pods, are
+// left out. If the service account may not list EndpointSlices, the
+// service's DNS name is resolved every DNSRefreshInterval instead, which
+// yields the ready pod IPs of a headless service with FallbackPort.
+// This function blocks until the context is cancelled or an error occurs.
+//
+// Parameters:
+//   - ctx: Watch lifetime
+//   - service: Service name
+//   - opts: Namespace, debounce, DNS fallback and logger settings
+//   - onUpdate: Called with the sorted ready endpoints on every change
+//
+// Returns:
+//   - error: nil after ctx is cancelled; otherwise a configuration or client error
+//
+// Example:
+//
+//	err := k8sx.WatchEndpoints(ctx, "backend", k8sx.EndpointsOptions{
+//	  Namespace:    "production",
+//	  FallbackPort: 8080,
+//	  Logger:       logger,
+//	}, func(endpoints []k8sx.Endpoint) {
+//	  balancer.SetTargets(endpoints)
+//	})
+func WatchEndpoints(ctx context.Context, service string, opts EndpointsOptions, onUpdate func(endpoints []Endpoint)) error {
+	if service == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if opts.Logger == nil {
+		return fmt.Errorf("logger is required")
+	}
+
+	cfg := internal.EndpointsConfig{
+		Service:      service,
+		Namespace:    opts.Namespace,
+		Logger:       opts.Logger,
+		FallbackPort: opts.FallbackPort,
+		DNSInterval:  opts.DNSRefreshInterval,
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+	if cfg.DNSInterval <= 0 {
+		cfg.DNSInterval = DefaultDNSRefreshInterval
+	}
+
+	client, err := clientset(opts.Client)
+	if err != nil {
+		return fmt.Errorf("failed to start endpoints watcher: %w", err)
+	}
+
+	return internal.WatchEndpoints(ctx, client, cfg, internal.Debounce(ctx, opts.Debounce, onUpdate))
+}
+
+// Default leader election timings, matching the Kubernetes controller defaults.
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// LeaderOptions holds configuration for lease-based leader election.
+type LeaderOptions struct {
+	Name          string        // Lease name shared by all candidates (required)
+	Namespace     string        // Lease namespace (default: "default")
+	Identity      string        // Unique candidate identity (default: hostname, i.e. the pod name)
+	LeaseDuration time.Duration // How long a lost leader's lease blocks others (default: 15s)
+	RenewDeadline time.Duration // How long the leader keeps trying to renew (default: 10s)
+	RetryPeriod   time.Duration // Interval between acquire and renew attempts (default: 2s)
+	Client        *Client       // Kubernetes client (default: NewClient(ClientOptions{}))
+	Logger        log.Logger    // Logger for leadership changes (required)
+}
+
+// RunLeaderElection campaigns for a coordination.k8s.io Lease so that only
+// one replica at a time runs onStarted.
+//
+// onStarted is called with a context that is cancelled when leadership is
+// lost; it should return promptly once that happens. onStopped is called
+// after onStarted returns. A replica that loses leadership campaigns again.
+// Cancelling ctx releases the lease so another replica takes over at once.
+// This function blocks until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Election lifetime
+//   - opts: Lease name, namespace, identity and timings
+//   - onStarted: Runs while this replica is the leader (optional)
+//   - onStopped: Runs after each leadership term ends (optional)
+//
+// Returns:
+//   - error: nil after ctx is cancelled; otherwise a configuration or client error
+//
+// Example:
+//
+//	err := k8sx.RunLeaderElection(ctx, k8sx.LeaderOptions{
+//	  Name:   "billing-reconciler",
+//	  Logger: logger,
+//	}, func(ctx context.Context) {
+//	  reconcileUntilDone(ctx)
+//	}, nil)
+func RunLeaderElection(ctx context.Context, opts LeaderOptions, onStarted func(ctx context.Context), onStopped func()) error {
+	cfg, err := leaderConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := clientset(opts.Client)
+	if err != nil {
+		return fmt.Errorf("failed to start leader election: %w", err)
+	}
+
+	return internal.RunLeaderElection(ctx, client, cfg, onStarted, onStopped)
+}
+
+// LeaderWorker wraps job so that it only runs on the elected leader. The
+// returned function matches servicex.WithWorker: every replica runs it, but
+// job runs on one replica at a time, with a context cancelled when that
+// replica loses leadership or shuts down.
+//
+// If job returns an error while still leading, the election stops, the lease
+// is released and the error is returned. If job returns nil, the replica
+// steps down and the function returns nil.
+//
+// Example:
+//
+//	servicex.WithWorker(k8sx.LeaderWorker(k8sx.LeaderOptions{
+//	  Name:   "billing-reconciler",
+//	  Logger: logger,
+//	}, reconcileLoop))
+func LeaderWorker(opts LeaderOptions, job func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var jobErr error
+		err := RunLeaderElection(ctx, opts, func(leaderCtx context.Context) {
+			err := job(leaderCtx)
+			if leaderCtx.Err() != nil {
+				// Leadership lost or shutting down; campaign again or exit.
+				return
+			}
+			jobErr = err
+			cancel()
+		}, nil)
+
+		return errors.Join(err, jobErr)
+	}
+}
+
+// leaderConfig validates opts and applies defaults.
+func leaderConfig(opts LeaderOptions) (internal.LeaderConfig, error) {
+	if opts.Name == "" {
+		return internal.LeaderConfig{}, fmt.Errorf("lease name is required")
+	}
+	if opts.Logger == nil {
+		return internal.LeaderConfig{}, fmt.Errorf("logger is required")
+	}
+
+	cfg := internal.LeaderConfig{
+		Name:          opts.Name,
+		Namespace:     opts.Namespace,
+		Identity:      opts.Identity,
+		LeaseDuration: opts.LeaseDuration,
+		RenewDeadline: opts.RenewDeadline,
+		RetryPeriod:   opts.RetryPeriod,
+		Logger:        opts.Logger,
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+	if cfg.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return internal.LeaderConfig{}, fmt.Errorf("failed to determine leader identity: %w", err)
+		}
+		cfg.Identity = hostname
+	}
+	if cfg.LeaseDuration == 0 {
+		cfg.LeaseDuration = DefaultLeaseDuration
+	}
+	if cfg.RenewDeadline == 0 {
+		cfg.RenewDeadline = DefaultRenewDeadline
+	}
+	if cfg.RetryPeriod == 0 {
+		cfg.RetryPeriod = DefaultRetryPeriod
+	}
+	return cfg, nil
+}
+
 // Resolve resolves a Kubernetes service to its endpoints.
 // For headless services, returns individual pod endpoints.
 // For ClusterIP services, returns the service endpoint.
 // Returns a slice of "host:port" strings.
+// It uses a client created with NewClient(ClientOptions{}); use
+// Client.Resolve to resolve with a specific client.
 func Resolve(ctx context.Context, service string, kind ServiceKind) ([]string, error) {
 	if service == "" {
 		return nil, fmt.Errorf("service name is required")
 	}
 
-	// Create service resolver
-	resolver, err := internal.NewServiceResolver()
+	// Create Kubernetes client
+	client, err := NewClient(ClientOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create service resolver: %w", err)
 	}
 
 	// Resolve the service
-	return resolver.ResolveService(ctx, service, string(kind))
+	return client.Resolve(ctx, service, kind)
 }
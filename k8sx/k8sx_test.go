@@ -3,12 +3,33 @@ package k8sx
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
 	"go.eggybyte.com/egg/core/log"
 )
 
+// TestMain hides any local kubeconfig so tests never reach a real cluster.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "k8sx-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("KUBECONFIG", filepath.Join(dir, "missing"))
+	os.Unsetenv("KUBERNETES_SERVICE_HOST")
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // testLogger is a test logger implementation.
 type testLogger struct {
 	logs []string
@@ -180,3 +201,155 @@ func TestWatchOptions(t *testing.T) {
 		t.Error("Logger is nil")
 	}
 }
+
+func TestWatchSecret(t *testing.T) {
+	t.Run("missing logger", func(t *testing.T) {
+		err := WatchSecret(context.Background(), "test-secret", WatchOptions{}, func(data map[string][]byte) {})
+		if err == nil {
+			t.Error("WatchSecret() error = nil, want error for missing logger")
+		}
+	})
+
+	t.Run("no cluster", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+
+		err := WatchSecret(ctx, "test-secret", WatchOptions{
+			Debounce: 100 * time.Millisecond,
+			Logger:   &testLogger{},
+		}, func(data map[string][]byte) {
+			t.Logf("Secret update received with %d keys", len(data))
+		})
+		if err == nil {
+			t.Error("WatchSecret() error = nil, want error outside a cluster")
+		}
+	})
+}
+
+func TestRunLeaderElection(t *testing.T) {
+	logger := &testLogger{}
+
+	tests := []struct {
+		name string
+		opts LeaderOptions
+	}{
+		{name: "missing name", opts: LeaderOptions{Logger: logger}},
+		{name: "missing logger", opts: LeaderOptions{Name: "jobs"}},
+		{name: "no cluster", opts: LeaderOptions{Name: "jobs", Logger: logger}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancel()
+
+			err := RunLeaderElection(ctx, tt.opts, func(ctx context.Context) {
+				t.Error("onStarted called without a cluster")
+			}, nil)
+			if err == nil {
+				t.Error("RunLeaderElection() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLeaderWorker(t *testing.T) {
+	worker := LeaderWorker(LeaderOptions{Name: "jobs", Logger: &testLogger{}}, func(ctx context.Context) error {
+		t.Error("job ran without a cluster")
+		return nil
+	})
+
+	if err := worker(context.Background()); err == nil {
+		t.Error("LeaderWorker() error = nil, want error outside a cluster")
+	}
+}
+
+func TestLeaderConfigDefaults(t *testing.T) {
+	cfg, err := leaderConfig(LeaderOptions{Name: "jobs", Logger: &testLogger{}})
+	if err != nil {
+		t.Fatalf("leaderConfig() error = %v", err)
+	}
+
+	if cfg.Namespace != "default" {
+		t.Errorf("Namespace = %q, want default", cfg.Namespace)
+	}
+	if cfg.Identity == "" {
+		t.Error("Identity is empty, want hostname")
+	}
+	if cfg.LeaseDuration != DefaultLeaseDuration || cfg.RenewDeadline != DefaultRenewDeadline || cfg.RetryPeriod != DefaultRetryPeriod {
+		t.Errorf("timings = %v/%v/%v, want defaults", cfg.LeaseDuration, cfg.RenewDeadline, cfg.RetryPeriod)
+	}
+}
+
+func TestWatchEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		opts    EndpointsOptions
+	}{
+		{name: "empty service name", service: "", opts: EndpointsOptions{Logger: &testLogger{}}},
+		{name: "missing logger", service: "backend", opts: EndpointsOptions{}},
+		{name: "no cluster", service: "backend", opts: EndpointsOptions{FallbackPort: 8080, Logger: &testLogger{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			defer cancel()
+
+			err := WatchEndpoints(ctx, tt.service, tt.opts, func(endpoints []Endpoint) {
+				t.Logf("Endpoints update received: %v", endpoints)
+			})
+			if err == nil {
+				t.Error("WatchEndpoints() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	client, err := NewClient(ClientOptions{Clientset: clientset})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.Clientset() != clientset {
+		t.Error("Clientset() does not return the injected clientset")
+	}
+
+	if _, err := NewClient(ClientOptions{}); err == nil {
+		t.Error("NewClient() without configuration expected error")
+	}
+	if _, err := NewClient(ClientOptions{Kubeconfig: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("NewClient() with missing kubeconfig expected error")
+	}
+}
+
+func TestClientResolve(t *testing.T) {
+	client, err := NewClient(ClientOptions{Clientset: fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "apps"},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "10.0.0.10",
+			Ports:     []corev1.ServicePort{{Name: "http", Port: 8080}},
+		},
+	})})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	endpoints, err := client.Resolve(context.Background(), "backend.apps", ServiceKindClusterIP)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := []string{"10.0.0.10:8080"}; !slices.Equal(endpoints, want) {
+		t.Errorf("Resolve() = %v, want %v", endpoints, want)
+	}
+
+	if _, err := client.Resolve(context.Background(), "", ServiceKindClusterIP); err == nil {
+		t.Error("Resolve() with empty service name expected error")
+	}
+	if _, err := client.Resolve(context.Background(), "missing.apps", ServiceKindClusterIP); err == nil {
+		t.Error("Resolve() of missing service expected error")
+	}
+}
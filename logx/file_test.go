@@ -0,0 +1,78 @@
+package logx
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var stderr bytes.Buffer
+	logger := New(WithWriter(&stderr), WithFileOutput(path, RotateOptions{})).(*Logger)
+	child := logger.With("component", "api").(*Logger)
+
+	child.Info("to file")
+	if err := child.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), `msg="to file"`) || !strings.Contains(string(data), `component="api"`) {
+		t.Errorf("Expected record in log file, got: %q", data)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("Expected nothing on the writer without Tee, got: %q", stderr.String())
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := child.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+}
+
+func TestWithFileOutput_Tee(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var stderr bytes.Buffer
+	logger := New(WithWriter(&stderr), WithFileOutput(path, RotateOptions{Tee: true})).(*Logger)
+
+	logger.Info("both")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "both") {
+		t.Errorf("Expected record in log file, got: %q", data)
+	}
+	if !strings.Contains(stderr.String(), "both") {
+		t.Errorf("Expected record on the writer with Tee, got: %q", stderr.String())
+	}
+}
+
+func TestWithFileOutput_OpenError(t *testing.T) {
+	// A regular file where the log directory should be
+	dir := filepath.Join(t.TempDir(), "blocked")
+	if err := os.WriteFile(dir, nil, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	logger := New(WithWriter(&stderr), WithFileOutput(filepath.Join(dir, "app.log"), RotateOptions{})).(*Logger)
+
+	logger.Info("fallback")
+	if !strings.Contains(stderr.String(), "failed to open log file") {
+		t.Errorf("Expected open error on the writer, got: %q", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "fallback") {
+		t.Errorf("Expected records to fall back to the writer, got: %q", stderr.String())
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close() without a file error = %v", err)
+	}
+}
@@ -3,58 +3,113 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // Options configures the logger behavior.
 type Options struct {
-	Format           string     // Output format: logfmt or json
+	Format           string     // Output format: logfmt, json or console
 	Level            slog.Level // Minimum log level
 	Color            bool       // Enable colorization for level field only
 	Writer           io.Writer  // Output writer (default: os.Stderr)
 	PayloadMaxBytes  int        // Maximum bytes to log for large payloads (0 = unlimited)
 	SensitiveFields  []string   // Field names to mask (e.g., "password", "token")
+	RedactKeys       []string   // Key patterns whose values are replaced with Redacted
 	DisableTimestamp bool       // Disable timestamp in output
 	DisableCaller    bool       // Disable caller information
+
+	Sampling *SamplingOptions // Suppress repeated records (nil = log everything)
+	LevelVar *slog.LevelVar   // Changeable minimum level; overrides Level when set
 }
 
-// Handler is a custom slog.Handler that outputs logfmt with sorted fields.
+// Handler is a custom slog.Handler that outputs logfmt, JSON or console
+// lines with sorted fields.
 type Handler struct {
 	opts   Options
 	mu     sync.Mutex
 	writer io.Writer
 	attrs  []slog.Attr
-	group  string
+	group  string   // Innermost open group
+	groups []string // Open groups, outermost first
+
+	sampler *Sampler       // Shared by derived handlers; nil when sampling is off
+	level   *slog.LevelVar // Minimum level, shared by derived handlers
 }
 
 // NewHandler creates a new Handler with the given options.
 func NewHandler(opts Options, writer io.Writer) *Handler {
-	return &Handler{
+	h := &Handler{
 		opts:   opts,
 		writer: writer,
+		level:  opts.LevelVar,
+	}
+	if h.level == nil {
+		h.level = new(slog.LevelVar)
+		h.level.Set(opts.Level)
 	}
+	if opts.Sampling != nil {
+		h.sampler = NewSampler(*opts.Sampling)
+	}
+	return h
 }
 
 // handle writes the log record (internal method).
 func (h *Handler) handle(level slog.Level, msg string, attrs []slog.Attr) {
 	// Check if level is enabled
-	if level < h.opts.Level {
+	if level < h.level.Level() {
+		return
+	}
+
+	allowed := true
+	if h.sampler != nil {
+		if dropped := h.sampler.TakeDropped(); dropped > 0 {
+			h.writeDropSummary(dropped)
+		}
+		allowed = h.sampler.Allow(level, msg)
+	}
+	if !allowed {
 		return
 	}
 
+	// Attributes of the record belong to the open groups
+	if len(h.groups) > 0 && len(attrs) > 0 {
+		attrs = []slog.Attr{nestGroups(h.groups, attrs)}
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	h.write(level, msg, attrs)
+}
+
+// writeDropSummary reports records dropped by sampling in the last interval.
+// The summary is never sampled and carries no handler attributes.
+func (h *Handler) writeDropSummary(dropped uint64) {
+	summary := &Handler{opts: h.opts, writer: h.writer}
+	summary.write(slog.LevelWarn, "log records dropped by sampling", []slog.Attr{
+		slog.Uint64("dropped", dropped),
+		slog.Duration("interval", h.sampler.Interval()),
+	})
+}
 
+// write formats the record according to the configured format.
+func (h *Handler) write(level slog.Level, msg string, attrs []slog.Attr) {
 	// Use different formatting based on format type
-	if h.opts.Format == "console" {
+	switch h.opts.Format {
+	case "console":
 		h.formatConsole(level, msg, attrs)
-	} else {
+	case "json":
+		h.formatJSON(level, msg, attrs)
+	default:
 		h.formatLogfmt(level, msg, attrs)
 	}
 }
@@ -88,8 +143,8 @@ func (h *Handler) formatLogfmt(level slog.Level, msg string, attrs []slog.Attr)
 	allAttrs := append([]slog.Attr{}, h.attrs...)
 	allAttrs = append(allAttrs, attrs...)
 
-	// Sort attributes by key for stable output
-	sortedAttrs := SortAttrs(allAttrs)
+	// Sort attributes by key for stable output; groups use dotted keys
+	sortedAttrs := SortAttrs(FlattenAttrs(allAttrs))
 
 	// Add sorted attributes in key=value format
 	for _, attr := range sortedAttrs {
@@ -134,8 +189,8 @@ func (h *Handler) formatConsole(level slog.Level, msg string, attrs []slog.Attr)
 	allAttrs := append([]slog.Attr{}, h.attrs...)
 	allAttrs = append(allAttrs, attrs...)
 
-	// Sort attributes by key for stable output
-	sortedAttrs := SortAttrs(allAttrs)
+	// Sort attributes by key for stable output; groups use dotted keys
+	sortedAttrs := SortAttrs(FlattenAttrs(allAttrs))
 
 	// Add attributes on new lines with indentation for readability
 	if len(sortedAttrs) > 0 {
@@ -161,6 +216,154 @@ func (h *Handler) formatConsole(level slog.Level, msg string, attrs []slog.Attr)
 	h.writer.Write([]byte(buf.String()))
 }
 
+// formatJSON writes the log record as a single JSON object.
+// Attribute values keep their JSON types and groups become nested objects.
+func (h *Handler) formatJSON(level slog.Level, msg string, attrs []slog.Attr) {
+	buf := make([]byte, 0, 256)
+	buf = append(buf, '{')
+
+	// Add timestamp if not disabled (usually disabled in containers)
+	if !h.opts.DisableTimestamp {
+		buf = append(buf, `"time":`...)
+		buf = appendJSONString(buf, time.Now().Format(time.RFC3339))
+		buf = append(buf, ',')
+	}
+
+	// Level is never colorized, so the output stays valid JSON
+	buf = append(buf, `"level":`...)
+	buf = appendJSONString(buf, LevelString(level))
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSONString(buf, msg)
+
+	// Combine handler attrs with record attrs
+	allAttrs := append([]slog.Attr{}, h.attrs...)
+	allAttrs = append(allAttrs, attrs...)
+
+	for _, attr := range SortAttrs(mergeGroups(allAttrs)) {
+		buf = append(buf, ',')
+		buf = h.appendJSONAttr(buf, attr)
+	}
+
+	buf = append(buf, '}', '\n')
+
+	// Write to output
+	h.writer.Write(buf)
+}
+
+// appendJSONAttr appends attr as a "key":value member, with groups already
+// merged by mergeGroups.
+func (h *Handler) appendJSONAttr(buf []byte, attr slog.Attr) []byte {
+	buf = appendJSONString(buf, attr.Key)
+	buf = append(buf, ':')
+	if IsSensitive(attr.Key, h.opts) {
+		return appendJSONString(buf, "***REDACTED***")
+	}
+	if IsRedacted(attr.Key, h.opts) {
+		return appendJSONString(buf, Redacted)
+	}
+
+	v := attr.Value
+	switch v.Kind() {
+	case slog.KindGroup:
+		buf = append(buf, '{')
+		for i, member := range SortAttrs(v.Group()) {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = h.appendJSONAttr(buf, member)
+		}
+		return append(buf, '}')
+	case slog.KindString:
+		s := v.String()
+		// Apply payload limit
+		if h.opts.PayloadMaxBytes > 0 && len(s) > h.opts.PayloadMaxBytes {
+			s = fmt.Sprintf("%s...(truncated, %d bytes)", s[:h.opts.PayloadMaxBytes], len(s))
+		}
+		return appendJSONString(buf, s)
+	case slog.KindInt64:
+		return strconv.AppendInt(buf, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			// JSON has no representation for NaN and infinities
+			return appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		return strconv.AppendFloat(buf, f, 'g', -1, 64)
+	case slog.KindBool:
+		return strconv.AppendBool(buf, v.Bool())
+	case slog.KindDuration:
+		// Duration in milliseconds, as in logfmt output
+		return strconv.AppendInt(buf, v.Duration().Milliseconds(), 10)
+	case slog.KindTime:
+		return appendJSONString(buf, v.Time().Format(time.RFC3339))
+	default:
+		return appendJSONAny(buf, v.Any())
+	}
+}
+
+// appendJSONAny appends an arbitrary value: errors as their message,
+// JSON-encodable values as encoded, and anything else as its string form.
+func appendJSONAny(buf []byte, value any) []byte {
+	switch val := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case error:
+		return appendJSONString(buf, val.Error())
+	case fmt.Stringer:
+		if _, ok := val.(json.Marshaler); !ok {
+			return appendJSONString(buf, val.String())
+		}
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return appendJSONString(buf, fmt.Sprintf("%+v", value))
+	}
+	return append(buf, encoded...)
+}
+
+// appendJSONString appends s as a quoted JSON string. Invalid UTF-8 is
+// replaced with U+FFFD; HTML characters are left as is.
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\':
+				buf = append(buf, '\\', b)
+			case b == '\n':
+				buf = append(buf, '\\', 'n')
+			case b == '\r':
+				buf = append(buf, '\\', 'r')
+			case b == '\t':
+				buf = append(buf, '\\', 't')
+			case b < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xf])
+			default:
+				buf = append(buf, b)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			// Line and paragraph separators break JavaScript parsers
+			buf = append(buf, `\u202`...)
+			buf = append(buf, hex[r&0xf])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
+
 // LogRecord writes a log record (public method for logx package).
 func (h *Handler) LogRecord(level slog.Level, msg string, attrs []slog.Attr) {
 	h.handle(level, msg, attrs)
@@ -168,7 +371,13 @@ func (h *Handler) LogRecord(level slog.Level, msg string, attrs []slog.Attr) {
 
 // Enabled reports whether the handler handles records at the given level.
 func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.opts.Level
+	return level >= h.level.Level()
+}
+
+// LevelVar returns the handler's minimum level, which can be changed at
+// any time.
+func (h *Handler) LevelVar() *slog.LevelVar {
+	return h.level
 }
 
 // Handle implements slog.Handler (renamed from HandleRecord).
@@ -182,27 +391,205 @@ func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
 	return nil
 }
 
-// WithAttrs returns a new Handler with the given attributes.
+// WithAttrs returns a new Handler with the given attributes, placed in the
+// currently open groups.
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	newAttrs := append([]slog.Attr{}, h.attrs...)
-	newAttrs = append(newAttrs, attrs...)
+	if len(h.groups) > 0 && len(attrs) > 0 {
+		newAttrs = append(newAttrs, nestGroups(h.groups, attrs))
+	} else {
+		newAttrs = append(newAttrs, attrs...)
+	}
 
 	return &Handler{
-		opts:   h.opts,
-		writer: h.writer,
-		attrs:  newAttrs,
-		group:  h.group,
+		opts:    h.opts,
+		writer:  h.writer,
+		attrs:   newAttrs,
+		group:   h.group,
+		groups:  h.groups,
+		sampler: h.sampler,
+		level:   h.level,
 	}
 }
 
-// WithGroup returns a new Handler with the given group name.
+// WithGroup returns a new Handler that places subsequent attributes in the
+// named group, nested in any group already open. An empty name returns h.
 func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]string{}, h.groups...), name)
+
 	return &Handler{
-		opts:   h.opts,
-		writer: h.writer,
-		attrs:  h.attrs,
-		group:  name,
+		opts:    h.opts,
+		writer:  h.writer,
+		attrs:   h.attrs,
+		group:   name,
+		groups:  groups,
+		sampler: h.sampler,
+		level:   h.level,
+	}
+}
+
+// nestGroups wraps attrs in the given groups, outermost first.
+func nestGroups(groups []string, attrs []slog.Attr) slog.Attr {
+	attr := slog.Attr{Key: groups[len(groups)-1], Value: slog.GroupValue(attrs...)}
+	for i := len(groups) - 2; i >= 0; i-- {
+		attr = slog.Attr{Key: groups[i], Value: slog.GroupValue(attr)}
+	}
+	return attr
+}
+
+// mergeGroups resolves attribute values and combines groups sharing a key,
+// so attributes added to the same group at different times end up in one
+// object. Groups with an empty key are inlined, empty groups are dropped,
+// and a repeated non-group key keeps its last value.
+func mergeGroups(attrs []slog.Attr) []slog.Attr {
+	merged := make([]slog.Attr, 0, len(attrs))
+	index := make(map[string]int, len(attrs))
+
+	var add func(attr slog.Attr)
+	add = func(attr slog.Attr) {
+		attr.Value = attr.Value.Resolve()
+		if attr.Value.Kind() == slog.KindGroup {
+			if attr.Key == "" {
+				for _, member := range attr.Value.Group() {
+					add(member)
+				}
+				return
+			}
+			if len(attr.Value.Group()) == 0 {
+				return
+			}
+		}
+
+		i, exists := index[attr.Key]
+		if !exists {
+			index[attr.Key] = len(merged)
+			merged = append(merged, attr)
+			return
+		}
+		prev := merged[i].Value
+		if prev.Kind() == slog.KindGroup && attr.Value.Kind() == slog.KindGroup {
+			members := append(append([]slog.Attr{}, prev.Group()...), attr.Value.Group()...)
+			attr.Value = slog.GroupValue(members...)
+		}
+		merged[i] = attr
+	}
+	for _, attr := range attrs {
+		add(attr)
+	}
+
+	// Merge nested groups once all members are known
+	for i, attr := range merged {
+		if attr.Value.Kind() == slog.KindGroup {
+			merged[i].Value = slog.GroupValue(mergeGroups(attr.Value.Group())...)
+		}
+	}
+	return merged
+}
+
+// FlattenAttrs expands group attributes into attributes with dotted keys,
+// such as "request.method", for the flat logfmt and console formats.
+func FlattenAttrs(attrs []slog.Attr) []slog.Attr {
+	flat := make([]slog.Attr, 0, len(attrs))
+	var add func(prefix string, attrs []slog.Attr)
+	add = func(prefix string, attrs []slog.Attr) {
+		for _, attr := range attrs {
+			key := attr.Key
+			if prefix != "" && key != "" {
+				key = prefix + "." + key
+			} else if prefix != "" {
+				key = prefix
+			}
+			value := attr.Value.Resolve()
+			if value.Kind() == slog.KindGroup {
+				add(key, value.Group())
+				continue
+			}
+			flat = append(flat, slog.Attr{Key: key, Value: value})
+		}
+	}
+	add("", attrs)
+	return flat
+}
+
+// IsSensitive reports whether key, or its last dotted segment, is one of
+// the configured sensitive fields.
+func IsSensitive(key string, opts Options) bool {
+	leaf := key
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		leaf = key[i+1:]
+	}
+	for _, field := range opts.SensitiveFields {
+		if strings.EqualFold(key, field) || strings.EqualFold(leaf, field) {
+			return true
+		}
 	}
+	return false
+}
+
+// Redacted replaces the values of keys matching Options.RedactKeys.
+const Redacted = "***"
+
+// DefaultRedactKeys are the key patterns redacted unless default redaction
+// is disabled.
+var DefaultRedactKeys = []string{
+	"password", "*_password",
+	"token", "*_token",
+	"secret", "*_secret",
+	"authorization",
+}
+
+// IsRedacted reports whether a dotted segment of key matches one of the
+// RedactKeys patterns, ignoring case. Checking every segment redacts the
+// members of a matching group in formats that flatten groups to dotted keys
+// as well as in JSON, where the group value is replaced as a whole.
+func IsRedacted(key string, opts Options) bool {
+	if len(opts.RedactKeys) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	for {
+		segment, rest, more := strings.Cut(key, ".")
+		for _, pattern := range opts.RedactKeys {
+			if matchKey(strings.ToLower(pattern), segment) {
+				return true
+			}
+		}
+		if !more {
+			return false
+		}
+		key = rest
+	}
+}
+
+// matchKey matches key against pattern, where '*' matches any run of
+// characters. Matching backtracks to the most recent '*' only, which is
+// linear in practice for key-sized inputs.
+func matchKey(pattern, key string) bool {
+	p, k := 0, 0
+	star, mark := -1, 0
+	for k < len(key) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, k
+			p++
+		case p < len(pattern) && pattern[p] == key[k]:
+			p++
+			k++
+		case star >= 0:
+			// Let the last '*' absorb one more character
+			mark++
+			p, k = star+1, mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
 }
 
 // KVToAttrs converts key-value pairs to slog.Attr slice.
@@ -247,10 +634,11 @@ func SortAttrs(attrs []slog.Attr) []slog.Attr {
 // FormatValue formats a slog.Value for logfmt output.
 func FormatValue(key string, v slog.Value, opts Options) string {
 	// Check if this is a sensitive field (by key name)
-	for _, field := range opts.SensitiveFields {
-		if strings.EqualFold(key, field) {
-			return `"***REDACTED***"`
-		}
+	if IsSensitive(key, opts) {
+		return `"***REDACTED***"`
+	}
+	if IsRedacted(key, opts) {
+		return strconv.Quote(Redacted)
 	}
 
 	switch v.Kind() {
@@ -306,10 +694,11 @@ func LevelString(level slog.Level) string {
 // FormatConsoleValue formats a slog.Value for console output (more human-readable).
 func FormatConsoleValue(key string, v slog.Value, opts Options) string {
 	// Check if this is a sensitive field (by key name)
-	for _, field := range opts.SensitiveFields {
-		if strings.EqualFold(key, field) {
-			return "***REDACTED***"
-		}
+	if IsSensitive(key, opts) {
+		return "***REDACTED***"
+	}
+	if IsRedacted(key, opts) {
+		return Redacted
 	}
 
 	switch v.Kind() {
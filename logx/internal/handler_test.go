@@ -4,7 +4,10 @@ package internal
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"log/slog"
+	"math"
 	"strings"
 	"sync"
 	"testing"
@@ -274,6 +277,336 @@ func TestFormatLogfmt_WithColor(t *testing.T) {
 	}
 }
 
+// decodeJSONLine parses a single JSON log line, failing the test if it is
+// not valid JSON terminated by a newline.
+func decodeJSONLine(t *testing.T, output string) map[string]any {
+	t.Helper()
+	if !strings.HasSuffix(output, "\n") || strings.Count(output, "\n") != 1 {
+		t.Fatalf("Output should be one line, got: %q", output)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(output), &entry); err != nil {
+		t.Fatalf("Output should be valid JSON: %v, got: %q", err, output)
+	}
+	return entry
+}
+
+func TestFormatJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		Color:            false,
+	}, buf)
+
+	attrs := []slog.Attr{
+		slog.String("key1", "value1"),
+		slog.Int("key2", 42),
+		slog.Bool("key3", true),
+		slog.Float64("key4", 1.5),
+		slog.Duration("key5", 1500*time.Millisecond),
+	}
+
+	handler.LogRecord(slog.LevelInfo, "test message", attrs)
+
+	output := buf.String()
+	want := `{"level":"INFO","msg":"test message","key1":"value1","key2":42,"key3":true,"key4":1.5,"key5":1500}` + "\n"
+	if output != want {
+		t.Errorf("Output = %q, want %q", output, want)
+	}
+	decodeJSONLine(t, output)
+}
+
+func TestFormatJSON_WithTimestamp(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: false,
+	}, buf)
+
+	handler.LogRecord(slog.LevelInfo, "test message", nil)
+
+	entry := decodeJSONLine(t, buf.String())
+	ts, ok := entry["time"].(string)
+	if !ok {
+		t.Fatalf("Output should contain time when DisableTimestamp is false, got: %v", entry)
+	}
+	if _, err := time.Parse(time.RFC3339, ts); err != nil {
+		t.Errorf("time = %q, want RFC3339: %v", ts, err)
+	}
+}
+
+func TestFormatJSON_IgnoresColor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		Color:            true,
+	}, buf)
+
+	handler.LogRecord(slog.LevelInfo, "test message", nil)
+
+	output := buf.String()
+	if strings.Contains(output, "\033[") {
+		t.Errorf("JSON output should not contain ANSI color codes, got: %q", output)
+	}
+	if entry := decodeJSONLine(t, output); entry["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", entry["level"])
+	}
+}
+
+func TestFormatJSON_Escaping(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+	}, buf)
+
+	msg := "quote \" backslash \\ newline \n tab \t bell \a <html> é  "
+	handler.LogRecord(slog.LevelInfo, msg, []slog.Attr{
+		slog.String("key \"quoted\"", msg),
+		slog.String("invalid", "a\xffb"),
+	})
+
+	output := buf.String()
+	if strings.Contains(output, " ") {
+		t.Errorf("Line separator should be escaped, got: %q", output)
+	}
+	entry := decodeJSONLine(t, output)
+	if entry["msg"] != msg {
+		t.Errorf("msg = %q, want %q", entry["msg"], msg)
+	}
+	if entry["key \"quoted\""] != msg {
+		t.Errorf("Escaped key should round-trip, got: %v", entry)
+	}
+	if entry["invalid"] != "a�b" {
+		t.Errorf("invalid = %q, want replacement character", entry["invalid"])
+	}
+}
+
+func TestFormatJSON_Groups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+	}, buf)
+
+	handler := base.WithAttrs([]slog.Attr{slog.String("service", "api")}).
+		WithGroup("request").
+		WithAttrs([]slog.Attr{slog.String("method", "GET")}).
+		WithGroup("client").
+		WithAttrs([]slog.Attr{slog.String("ip", "10.0.0.1")})
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "handled", 0)
+	record.AddAttrs(
+		slog.Int("port", 443),
+		slog.Group("tls", slog.String("version", "1.3")),
+	)
+	if err := handler.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	output := buf.String()
+	want := `{"level":"INFO","msg":"handled","request":{"client":{"ip":"10.0.0.1","port":443,"tls":{"version":"1.3"}},"method":"GET"},"service":"api"}` + "\n"
+	if output != want {
+		t.Errorf("Output = %q, want %q", output, want)
+	}
+	decodeJSONLine(t, output)
+}
+
+func TestFormatJSON_EmptyGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+	}, buf)
+
+	handler.LogRecord(slog.LevelInfo, "test", []slog.Attr{
+		slog.Group("empty"),
+		slog.Group("", slog.String("inlined", "yes")),
+	})
+	handler.WithGroup("unused").(*Handler).LogRecord(slog.LevelInfo, "test", nil)
+
+	want := `{"level":"INFO","msg":"test","inlined":"yes"}` + "\n" +
+		`{"level":"INFO","msg":"test"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Output = %q, want %q", got, want)
+	}
+}
+
+func TestFormatJSON_Values(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+	}, buf)
+
+	handler.LogRecord(slog.LevelError, "failed", []slog.Attr{
+		slog.Any("error", errors.New("boom")),
+		slog.Any("tags", []string{"a", "b"}),
+		slog.Any("nil", nil),
+		slog.Uint64("count", 7),
+		slog.Float64("nan", math.NaN()),
+		slog.Time("at", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)),
+	})
+
+	output := buf.String()
+	want := `{"level":"ERROR","msg":"failed","at":"2024-01-15T10:30:00Z","count":7,"error":"boom","nan":"NaN","nil":null,"tags":["a","b"]}` + "\n"
+	if output != want {
+		t.Errorf("Output = %q, want %q", output, want)
+	}
+	decodeJSONLine(t, output)
+}
+
+func TestFormatJSON_SensitiveAndPayloadLimit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		SensitiveFields:  []string{"password"},
+		PayloadMaxBytes:  10,
+	}, buf)
+
+	handler.LogRecord(slog.LevelInfo, "login", []slog.Attr{
+		slog.Group("user", slog.String("password", "secret123")),
+		slog.String("body", strings.Repeat("a", 100)),
+	})
+
+	entry := decodeJSONLine(t, buf.String())
+	user, _ := entry["user"].(map[string]any)
+	if user["password"] != "***REDACTED***" {
+		t.Errorf("user.password = %v, want redacted", user["password"])
+	}
+	body, _ := entry["body"].(string)
+	if !strings.Contains(body, "truncated, 100 bytes") {
+		t.Errorf("body = %q, want truncated", body)
+	}
+}
+
+func TestFormatLogfmt_Groups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "logfmt",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		SensitiveFields:  []string{"token"},
+	}, buf)
+
+	grouped := handler.WithGroup("request").WithAttrs([]slog.Attr{slog.String("method", "GET")}).(*Handler)
+	grouped.LogRecord(slog.LevelInfo, "handled", []slog.Attr{slog.String("token", "abc")})
+
+	want := `level=INFO msg="handled" request.method="GET" request.token="***REDACTED***"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Output = %q, want %q", got, want)
+	}
+}
+
+func TestRedactKeys_Logfmt(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "logfmt",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		RedactKeys:       []string{"*_key", "Authorization", "credentials"},
+	}, buf)
+
+	grouped := handler.WithGroup("request").(*Handler)
+	grouped.LogRecord(slog.LevelInfo, "handled", []slog.Attr{
+		slog.String("authorization", "Bearer abc"),
+		slog.String("API_KEY", "sk_live_123"),
+		slog.Group("credentials", slog.String("user", "alice"), slog.Int("pin", 1234)),
+		slog.String("keyboard", "qwerty"),
+	})
+
+	want := `level=INFO msg="handled" request.API_KEY="***" request.authorization="***" ` +
+		`request.credentials.pin="***" request.credentials.user="***" request.keyboard="qwerty"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Output = %q, want %q", got, want)
+	}
+}
+
+func TestRedactKeys_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "json",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		RedactKeys:       DefaultRedactKeys,
+	}, buf)
+
+	handler.LogRecord(slog.LevelInfo, "login", []slog.Attr{
+		slog.Group("user",
+			slog.String("name", "alice"),
+			slog.String("password", "secret123"),
+			slog.Group("session", slog.String("refresh_token", "rt-1")),
+		),
+		slog.Group("secret", slog.String("value", "s3")),
+		slog.Int("token_count", 3),
+	})
+
+	output := buf.String()
+	for _, leaked := range []string{"secret123", "rt-1", "s3"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("output leaks %q: %s", leaked, output)
+		}
+	}
+	entry := decodeJSONLine(t, output)
+	user, _ := entry["user"].(map[string]any)
+	if user["password"] != Redacted || user["name"] != "alice" {
+		t.Errorf("user = %v, want password redacted and name kept", user)
+	}
+	session, _ := user["session"].(map[string]any)
+	if session["refresh_token"] != Redacted {
+		t.Errorf("user.session.refresh_token = %v, want redacted", session["refresh_token"])
+	}
+	if entry["secret"] != Redacted {
+		t.Errorf("secret = %v, want redacted group", entry["secret"])
+	}
+	if entry["token_count"] != float64(3) {
+		t.Errorf("token_count = %v, want 3", entry["token_count"])
+	}
+}
+
+func TestRedactKeys_SensitiveFieldsTakePrecedence(t *testing.T) {
+	opts := Options{SensitiveFields: []string{"password"}, RedactKeys: []string{"password"}}
+	if got := FormatValue("password", slog.StringValue("x"), opts); got != `"***REDACTED***"` {
+		t.Errorf("FormatValue() = %q, want the sensitive field mask", got)
+	}
+	if got := FormatConsoleValue("db.token", slog.StringValue("x"), Options{RedactKeys: []string{"token"}}); got != Redacted {
+		t.Errorf("FormatConsoleValue() = %q, want %q", got, Redacted)
+	}
+}
+
+func TestMatchKey(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"token", "token", true},
+		{"token", "tokens", false},
+		{"*_token", "access_token", true},
+		{"*_token", "token", false},
+		{"*secret*", "client_secret_hash", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"*", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchKey(tt.pattern, tt.key); got != tt.want {
+			t.Errorf("matchKey(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
 func TestSortAttrs(t *testing.T) {
 	attrs := []slog.Attr{
 		slog.String("zebra", "value"),
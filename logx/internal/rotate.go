@@ -0,0 +1,334 @@
+// Package internal provides internal implementation details for logx.
+package internal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in rotated file names, e.g.
+// app-2024-01-15T10-30-00.000.log.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// ErrFileClosed is returned by writes to a closed RotatingFile.
+var ErrFileClosed = errors.New("log file is closed")
+
+// RotateConfig configures a RotatingFile.
+type RotateConfig struct {
+	MaxBytes      int64         // Rotate before the file exceeds this size
+	MaxBackups    int           // Rotated files kept (0 = all)
+	MaxAge        time.Duration // Rotated files older than this are removed (0 = no limit)
+	Compress      bool          // Gzip rotated files
+	BufferSize    int           // Write buffer size in bytes
+	FlushInterval time.Duration // How often buffered writes are flushed (<= 0 disables)
+}
+
+// RotatingFile is a buffered, size-rotated log file. It is safe for
+// concurrent use.
+type RotatingFile struct {
+	path string
+	cfg  RotateConfig
+	now  func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	buf    *bufio.Writer
+	size   int64
+	closed bool
+
+	cleanup chan struct{} // Signals the cleanup goroutine, capacity 1
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// OpenRotatingFile opens path for appending, creating it and its
+// directory if needed, and starts the background flush and cleanup
+// goroutines.
+func OpenRotatingFile(path string, cfg RotateConfig) (*RotatingFile, error) {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 64 << 10
+	}
+	f := &RotatingFile{
+		path:    path,
+		cfg:     cfg,
+		now:     time.Now,
+		cleanup: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	f.wg.Add(1)
+	go f.runCleanup()
+	if cfg.FlushInterval > 0 {
+		f.wg.Add(1)
+		go f.runFlush()
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first if p would make the file exceed
+// MaxBytes. A single write larger than MaxBytes goes to a fresh file.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, ErrFileClosed
+	}
+	if f.cfg.MaxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.cfg.MaxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.buf.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync flushes buffered writes and commits the file to stable storage.
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	if err := f.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush log file: %w", err)
+	}
+	return f.file.Sync()
+}
+
+// Close flushes buffered writes, closes the file and waits for background
+// compression and cleanup to finish. Closing twice is a no-op.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	err := errors.Join(f.buf.Flush(), f.file.Close())
+	f.mu.Unlock()
+
+	close(f.done)
+	f.wg.Wait()
+	return err
+}
+
+// open opens the current file and resets the size from its length.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	if f.buf == nil {
+		f.buf = bufio.NewWriterSize(file, f.cfg.BufferSize)
+	} else {
+		f.buf.Reset(file)
+	}
+	return nil
+}
+
+// rotate moves the current file aside and opens a new one. The caller
+// holds f.mu.
+func (f *RotatingFile) rotate() error {
+	if err := f.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush log file: %w", err)
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	if err := os.Rename(f.path, f.nextBackupName()); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	select {
+	case f.cleanup <- struct{}{}:
+	default: // A cleanup is already pending
+	}
+	return nil
+}
+
+// backupName returns the rotated name of the file at t.
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, prefix, ext := f.nameParts()
+	return filepath.Join(dir, prefix+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+// nextBackupName returns the rotated name for now, moving past names
+// already taken by rotations within the same millisecond.
+func (f *RotatingFile) nextBackupName() string {
+	t := f.now()
+	for {
+		name := f.backupName(t)
+		_, err := os.Lstat(name)
+		_, gzErr := os.Lstat(name + ".gz")
+		if os.IsNotExist(err) && os.IsNotExist(gzErr) {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// nameParts splits the path into directory, backup prefix and extension.
+func (f *RotatingFile) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(f.path)
+	base := filepath.Base(f.path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// runFlush flushes buffered writes every FlushInterval until Close.
+func (f *RotatingFile) runFlush() {
+	defer f.wg.Done()
+	ticker := time.NewTicker(f.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.done:
+			return
+		case <-ticker.C:
+			f.mu.Lock()
+			if !f.closed {
+				_ = f.buf.Flush()
+			}
+			f.mu.Unlock()
+		}
+	}
+}
+
+// runCleanup compresses and prunes rotated files after each rotation, off
+// the logging path.
+func (f *RotatingFile) runCleanup() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.cleanup:
+			_ = f.cleanupBackups()
+		case <-f.done:
+			select {
+			case <-f.cleanup:
+				_ = f.cleanupBackups()
+			default:
+			}
+			return
+		}
+	}
+}
+
+// backup is a rotated file and the time it was rotated.
+type backup struct {
+	path string
+	time time.Time
+}
+
+// cleanupBackups compresses uncompressed backups when Compress is set and
+// removes backups beyond MaxBackups or older than MaxAge.
+func (f *RotatingFile) cleanupBackups() error {
+	backups, err := f.listBackups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	cutoff := time.Time{}
+	if f.cfg.MaxAge > 0 {
+		cutoff = f.now().Add(-f.cfg.MaxAge)
+	}
+	for i, b := range backups {
+		expired := !cutoff.IsZero() && b.time.Before(cutoff)
+		if expired || (f.cfg.MaxBackups > 0 && i >= f.cfg.MaxBackups) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if f.cfg.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := compressFile(b.path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listBackups returns rotated files, newest first.
+func (f *RotatingFile) listBackups() ([]backup, error) {
+	dir, prefix, ext := f.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log directory: %w", err)
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue // Not a backup of this file
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+// compressFile gzips path to path.gz and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed backup: %w", err)
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return fmt.Errorf("failed to compress backup: %w", err)
+	}
+	if err := errors.Join(zw.Close(), dst.Close()); err != nil {
+		os.Remove(path + ".gz")
+		return fmt.Errorf("failed to compress backup: %w", err)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,283 @@
+// Package internal provides tests for logx file rotation.
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openTestFile opens a rotating file in a temporary directory whose clock
+// advances by one second per rotation.
+func openTestFile(t *testing.T, cfg RotateConfig) (*RotatingFile, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenRotatingFile(path, cfg)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile() error = %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	f.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	t.Cleanup(func() { f.Close() })
+	return f, path
+}
+
+// waitForBackups waits for the cleanup goroutine to leave want backups.
+func waitForBackups(t *testing.T, f *RotatingFile, want int) []backup {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		backups, err := f.listBackups()
+		if err != nil {
+			t.Fatalf("listBackups() error = %v", err)
+		}
+		if len(backups) == want || time.Now().After(deadline) {
+			if len(backups) != want {
+				t.Fatalf("Expected %d backups, got %d", want, len(backups))
+			}
+			return backups
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRotatingFile_Write(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 1 << 20})
+
+	if _, err := f.Write([]byte("line 1\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("Expected write to be buffered, file contains %q", data)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "line 1\n" {
+		t.Errorf("File = %q, want %q", data, "line 1\n")
+	}
+}
+
+func TestRotatingFile_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("existing\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRotatingFile(path, RotateConfig{MaxBytes: 12})
+	if err != nil {
+		t.Fatalf("OpenRotatingFile() error = %v", err)
+	}
+	f.Write([]byte("new\n"))
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The existing size counts towards MaxBytes
+	if data, _ := os.ReadFile(path); string(data) != "new\n" {
+		t.Errorf("File = %q, want %q after rotation", data, "new\n")
+	}
+}
+
+func TestRotatingFile_RotateBySize(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 10})
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	f.Sync()
+
+	if data, _ := os.ReadFile(path); string(data) != "cccc\n" {
+		t.Errorf("Current file = %q, want %q", data, "cccc\n")
+	}
+	backups := waitForBackups(t, f, 1)
+	if data, _ := os.ReadFile(backups[0].path); string(data) != "aaaa\nbbbb\n" {
+		t.Errorf("Backup = %q, want %q", data, "aaaa\nbbbb\n")
+	}
+	if want := "app-2023-11-14T22-13-21.000.log"; filepath.Base(backups[0].path) != want {
+		t.Errorf("Backup name = %q, want %q", filepath.Base(backups[0].path), want)
+	}
+}
+
+func TestRotatingFile_MaxBackups(t *testing.T) {
+	f, _ := openTestFile(t, RotateConfig{MaxBytes: 5, MaxBackups: 2})
+
+	for _, line := range []string{"111\n", "222\n", "333\n", "444\n", "555\n"} {
+		f.Write([]byte(line))
+	}
+
+	backups := waitForBackups(t, f, 2)
+	for i, want := range []string{"444\n", "333\n"} {
+		if data, _ := os.ReadFile(backups[i].path); string(data) != want {
+			t.Errorf("Backup %d = %q, want %q", i, data, want)
+		}
+	}
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 5, MaxAge: time.Hour})
+
+	// A backup rotated two hours ago and an unrelated file
+	old := f.backupName(time.Unix(1700000000, 0).Add(-2 * time.Hour))
+	if err := os.WriteFile(old, []byte("old\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(filepath.Dir(path), "app-notes.log")
+	if err := os.WriteFile(other, []byte("keep\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Write([]byte("111\n"))
+	f.Write([]byte("222\n"))
+
+	waitForBackups(t, f, 1)
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("Expected expired backup to be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Expected non-backup file to be kept, stat error = %v", err)
+	}
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	f, _ := openTestFile(t, RotateConfig{MaxBytes: 5, Compress: true})
+
+	f.Write([]byte("111\n"))
+	f.Write([]byte("222\n"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	var backups []backup
+	for {
+		backups, _ = f.listBackups()
+		if len(backups) == 1 && strings.HasSuffix(backups[0].path, ".gz") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected one compressed backup, got %+v", backups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	file, err := os.Open(backups[0].path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Failed to read compressed backup: %v", err)
+	}
+	if string(data) != "111\n" {
+		t.Errorf("Decompressed backup = %q, want %q", data, "111\n")
+	}
+	if _, err := os.Stat(strings.TrimSuffix(backups[0].path, ".gz")); !os.IsNotExist(err) {
+		t.Errorf("Expected uncompressed backup to be removed, stat error = %v", err)
+	}
+}
+
+func TestRotatingFile_Close(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 1 << 20, FlushInterval: time.Hour})
+
+	f.Write([]byte("buffered\n"))
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "buffered\n" {
+		t.Errorf("Expected Close to flush, file = %q", data)
+	}
+	if _, err := f.Write([]byte("late\n")); !errors.Is(err, ErrFileClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrFileClosed", err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Errorf("Sync() after Close error = %v", err)
+	}
+}
+
+func TestRotatingFile_FlushInterval(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 1 << 20, FlushInterval: 10 * time.Millisecond})
+
+	f.Write([]byte("tick\n"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if data, _ := os.ReadFile(path); string(data) == "tick\n" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected buffered write to be flushed by the interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRotatingFile_Concurrency(t *testing.T) {
+	f, path := openTestFile(t, RotateConfig{MaxBytes: 1 << 10, FlushInterval: time.Millisecond})
+	const goroutines, perGoroutine = 20, 200
+	line := []byte(strings.Repeat("x", 31) + "\n")
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				if _, err := f.Write(line); err != nil {
+					t.Errorf("Write() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Every line lands whole in exactly one file
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "app*.log"))
+	var total int
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) > 1<<10 {
+			t.Errorf("%s is %d bytes, exceeds MaxBytes", filepath.Base(name), len(data))
+		}
+		for _, l := range bytes.SplitAfter(data, []byte("\n")) {
+			if len(l) == 0 {
+				continue
+			}
+			if !bytes.Equal(l, line) {
+				t.Fatalf("Corrupted line in %s: %q", filepath.Base(name), l)
+			}
+			total++
+		}
+	}
+	if total != goroutines*perGoroutine {
+		t.Errorf("Expected %d lines, got %d", goroutines*perGoroutine, total)
+	}
+}
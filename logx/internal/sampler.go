@@ -0,0 +1,121 @@
+// Package internal provides internal implementation details for logx.
+package internal
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// samplerBuckets is the number of counters messages are hashed into. Two
+// messages sharing a bucket are sampled together.
+const samplerBuckets = 4096
+
+// SamplingOptions configures log sampling.
+type SamplingOptions struct {
+	Initial    int           // Records logged per level and message each interval (default: 100)
+	Thereafter int           // Log every Mth record after Initial (0 = drop the rest)
+	Interval   time.Duration // Counting window (default: 1s)
+}
+
+// Sampler decides which records are logged, keyed by a hash of the level
+// and message. It is safe for concurrent use and does not allocate.
+type Sampler struct {
+	initial    uint64
+	thereafter uint64
+	interval   time.Duration
+	now        func() time.Time
+
+	counters  [samplerBuckets]sampleCounter
+	dropped   atomic.Uint64
+	summaryAt atomic.Int64 // Unix nanoseconds of the next drop summary
+}
+
+// sampleCounter counts records of one bucket in the current interval.
+type sampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// NewSampler creates a sampler, applying defaults to unset options.
+func NewSampler(opts SamplingOptions) *Sampler {
+	if opts.Initial <= 0 {
+		opts.Initial = 100
+	}
+	if opts.Thereafter < 0 {
+		opts.Thereafter = 0
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	return &Sampler{
+		initial:    uint64(opts.Initial),
+		thereafter: uint64(opts.Thereafter),
+		interval:   opts.Interval,
+		now:        time.Now,
+	}
+}
+
+// Allow reports whether a record with the given level and message should
+// be logged, counting it as dropped otherwise.
+func (s *Sampler) Allow(level slog.Level, msg string) bool {
+	counter := &s.counters[sampleKey(level, msg)%samplerBuckets]
+	n := counter.inc(s.now().UnixNano(), s.interval)
+	if n <= s.initial || (s.thereafter > 0 && (n-s.initial)%s.thereafter == 0) {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// TakeDropped returns the number of records dropped since the last call
+// once per interval, resetting the count. It returns 0 between intervals
+// and when nothing was dropped.
+func (s *Sampler) TakeDropped() uint64 {
+	now := s.now().UnixNano()
+	next := s.summaryAt.Load()
+	if now < next {
+		return 0
+	}
+	if !s.summaryAt.CompareAndSwap(next, now+s.interval.Nanoseconds()) {
+		return 0 // Another goroutine is reporting this interval
+	}
+	return s.dropped.Swap(0)
+}
+
+// Interval returns the counting window.
+func (s *Sampler) Interval() time.Duration {
+	return s.interval
+}
+
+// inc counts a record at now, starting a new interval if the current one
+// has ended, and returns the count within the interval.
+func (c *sampleCounter) inc(now int64, interval time.Duration) uint64 {
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+interval.Nanoseconds()) {
+		// Another goroutine started the interval first
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// sampleKey hashes level and message with FNV-1a.
+func sampleKey(level slog.Level, msg string) uint64 {
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+	h := uint64(offset)
+	h ^= uint64(uint8(level))
+	h *= prime
+	for i := 0; i < len(msg); i++ {
+		h ^= uint64(msg[i])
+		h *= prime
+	}
+	return h
+}
@@ -0,0 +1,190 @@
+// Package internal provides tests for logx log sampling.
+package internal
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestSampler returns a sampler whose clock is controlled by the test.
+func newTestSampler(opts SamplingOptions) (*Sampler, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	s := NewSampler(opts)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestNewSampler_Defaults(t *testing.T) {
+	s := NewSampler(SamplingOptions{Thereafter: -1})
+	if s.initial != 100 {
+		t.Errorf("initial = %d, want 100", s.initial)
+	}
+	if s.thereafter != 0 {
+		t.Errorf("thereafter = %d, want 0", s.thereafter)
+	}
+	if s.Interval() != time.Second {
+		t.Errorf("Interval() = %v, want 1s", s.Interval())
+	}
+}
+
+func TestSampler_Allow(t *testing.T) {
+	s, _ := newTestSampler(SamplingOptions{Initial: 2, Thereafter: 3, Interval: time.Second})
+
+	var got []bool
+	for i := 0; i < 9; i++ {
+		got = append(got, s.Allow(slog.LevelError, "db down"))
+	}
+
+	// First 2 logged, then every 3rd: records 5 and 8
+	want := []bool{true, true, false, false, true, false, false, true, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Allow() sequence = %v, want %v", got, want)
+		}
+	}
+	if dropped := s.TakeDropped(); dropped != 5 {
+		t.Errorf("TakeDropped() = %d, want 5", dropped)
+	}
+}
+
+func TestSampler_DropsAllAfterInitial(t *testing.T) {
+	s, _ := newTestSampler(SamplingOptions{Initial: 1, Interval: time.Second})
+
+	if !s.Allow(slog.LevelInfo, "tick") {
+		t.Fatal("First record should be allowed")
+	}
+	for i := 0; i < 10; i++ {
+		if s.Allow(slog.LevelInfo, "tick") {
+			t.Fatal("Records after Initial should be dropped when Thereafter is 0")
+		}
+	}
+}
+
+func TestSampler_KeyedByLevelAndMessage(t *testing.T) {
+	s, _ := newTestSampler(SamplingOptions{Initial: 1, Interval: time.Second})
+
+	if !s.Allow(slog.LevelInfo, "a") || s.Allow(slog.LevelInfo, "a") {
+		t.Fatal("Second identical record should be dropped")
+	}
+	if !s.Allow(slog.LevelInfo, "b") {
+		t.Error("Different message should be sampled separately")
+	}
+	if !s.Allow(slog.LevelError, "a") {
+		t.Error("Different level should be sampled separately")
+	}
+}
+
+func TestSampler_IntervalReset(t *testing.T) {
+	s, now := newTestSampler(SamplingOptions{Initial: 1, Interval: time.Second})
+
+	s.Allow(slog.LevelInfo, "tick")
+	if s.Allow(slog.LevelInfo, "tick") {
+		t.Fatal("Second record in the interval should be dropped")
+	}
+
+	*now = now.Add(time.Second)
+	if !s.Allow(slog.LevelInfo, "tick") {
+		t.Error("First record of a new interval should be allowed")
+	}
+}
+
+func TestSampler_TakeDropped(t *testing.T) {
+	s, now := newTestSampler(SamplingOptions{Initial: 1, Interval: time.Second})
+
+	if dropped := s.TakeDropped(); dropped != 0 {
+		t.Fatalf("TakeDropped() = %d, want 0 before any drops", dropped)
+	}
+
+	s.Allow(slog.LevelInfo, "tick")
+	s.Allow(slog.LevelInfo, "tick")
+	s.Allow(slog.LevelInfo, "tick")
+	if dropped := s.TakeDropped(); dropped != 0 {
+		t.Errorf("TakeDropped() = %d, want 0 within the same interval", dropped)
+	}
+
+	*now = now.Add(time.Second)
+	if dropped := s.TakeDropped(); dropped != 2 {
+		t.Errorf("TakeDropped() = %d, want 2 after the interval", dropped)
+	}
+	if dropped := s.TakeDropped(); dropped != 0 {
+		t.Errorf("TakeDropped() = %d, want 0 after reporting", dropped)
+	}
+}
+
+func TestSampler_Concurrency(t *testing.T) {
+	s := NewSampler(SamplingOptions{Initial: 10, Interval: time.Hour})
+	const goroutines, perGoroutine = 50, 100
+
+	// Start the interval first; concurrent interval starts are approximate
+	s.Allow(slog.LevelWarn, "flood")
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			n := 0
+			for j := 0; j < perGoroutine; j++ {
+				if s.Allow(slog.LevelWarn, "flood") {
+					n++
+				}
+			}
+			mu.Lock()
+			allowed += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 9 {
+		t.Errorf("allowed = %d, want 9", allowed)
+	}
+	if dropped := s.dropped.Load(); dropped != goroutines*perGoroutine-9 {
+		t.Errorf("dropped = %d, want %d", dropped, goroutines*perGoroutine-9)
+	}
+}
+
+func TestHandler_Sampling(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewHandler(Options{
+		Format:           "logfmt",
+		Level:            slog.LevelInfo,
+		DisableTimestamp: true,
+		Sampling:         &SamplingOptions{Initial: 2, Interval: time.Second},
+	}, buf)
+	now := time.Unix(1700000000, 0)
+	handler.sampler.now = func() time.Time { return now }
+
+	child := handler.WithAttrs([]slog.Attr{slog.String("component", "db")}).(*Handler)
+	for i := 0; i < 5; i++ {
+		child.LogRecord(slog.LevelError, "query failed", nil)
+	}
+	handler.LogRecord(slog.LevelError, "query failed", nil)
+	if got := strings.Count(buf.String(), "query failed"); got != 2 {
+		t.Fatalf("Expected 2 sampled records across derived handlers, got %d: %q", got, buf.String())
+	}
+
+	buf.Reset()
+	now = now.Add(time.Second)
+	child.LogRecord(slog.LevelError, "query failed", nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected summary and record, got: %q", buf.String())
+	}
+	wantSummary := `level=WARN msg="log records dropped by sampling" dropped=4 interval=1000`
+	if lines[0] != wantSummary {
+		t.Errorf("Summary = %q, want %q", lines[0], wantSummary)
+	}
+	if !strings.Contains(lines[1], `msg="query failed"`) {
+		t.Errorf("Record after the interval should be logged, got: %q", lines[1])
+	}
+}
@@ -0,0 +1,107 @@
+package logx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// LevelVar is a minimum log level that can change while loggers use it.
+// Reading it is a single atomic load, so level checks stay cheap.
+type LevelVar = slog.LevelVar
+
+// NewLevelVar creates a LevelVar set to level.
+//
+// Example:
+//
+//	levelVar := logx.NewLevelVar(slog.LevelInfo)
+//	logger := logx.New(logx.WithLevelVar(levelVar))
+//	levelVar.Set(slog.LevelDebug) // takes effect immediately
+func NewLevelVar(level slog.Level) *LevelVar {
+	v := new(LevelVar)
+	v.Set(level)
+	return v
+}
+
+// levelBody is the JSON body served and accepted by LevelHandler.
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// maxLevelBodyBytes bounds PUT bodies accepted by LevelHandler.
+const maxLevelBodyBytes = 1 << 10
+
+// LevelHandler serves the level in v over HTTP: GET returns it as
+// {"level":"INFO"}, and PUT replaces it, accepting the same JSON body or a
+// plain-text level name (debug, info, warn, warning, error,
+// case-insensitive). Unknown levels are rejected with 400.
+//
+// The handler changes logging for the whole process; mount it on an
+// internal or admin port only.
+//
+// Example:
+//
+//	mux.Handle("/debug/log-level", logx.LevelHandler(levelVar))
+//	// curl -X PUT -d debug http://localhost:8081/debug/log-level
+func LevelHandler(v *LevelVar) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut:
+			level, err := readLevel(r)
+			if err != nil {
+				writeLevelError(w, http.StatusBadRequest, err)
+				return
+			}
+			v.Set(level)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelBody{Level: v.Level().String()})
+	})
+}
+
+// readLevel parses the level from a PUT body.
+func readLevel(r *http.Request) (slog.Level, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLevelBodyBytes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	name := strings.TrimSpace(string(body))
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		var req levelBody
+		if err := json.Unmarshal(body, &req); err != nil {
+			return 0, fmt.Errorf("invalid JSON body: %w", err)
+		}
+		name = req.Level
+	}
+
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// writeLevelError writes err as a JSON error body.
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
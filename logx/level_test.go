@@ -0,0 +1,116 @@
+package logx
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogger_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf)).(*Logger)
+	child := logger.With("component", "api")
+
+	child.Debug("hidden")
+	logger.SetLevel(slog.LevelDebug)
+	child.Debug("visible")
+
+	if logger.Level() != slog.LevelDebug {
+		t.Errorf("expected level DEBUG, got %s", logger.Level())
+	}
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("expected debug record before SetLevel to be dropped: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "visible") {
+		t.Errorf("expected derived logger to follow SetLevel: %s", buf.String())
+	}
+}
+
+func TestWithLevelVar(t *testing.T) {
+	var buf bytes.Buffer
+	levelVar := NewLevelVar(slog.LevelWarn)
+	first := New(WithWriter(&buf), WithLevelVar(levelVar))
+	second := New(WithWriter(&buf), WithLevelVar(levelVar), WithLevel(slog.LevelDebug))
+
+	first.Info("first hidden")
+	second.Info("second hidden")
+	levelVar.Set(slog.LevelInfo)
+	first.Info("first visible")
+	second.Info("second visible")
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Errorf("expected LevelVar to override WithLevel: %s", output)
+	}
+	if !strings.Contains(output, "first visible") || !strings.Contains(output, "second visible") {
+		t.Errorf("expected both loggers to follow the LevelVar: %s", output)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	levelVar := NewLevelVar(slog.LevelInfo)
+	handler := LevelHandler(levelVar)
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+		wantLevel   slog.Level
+	}{
+		{"get", http.MethodGet, "", "", http.StatusOK, `{"level":"INFO"}`, slog.LevelInfo},
+		{"put text", http.MethodPut, "text/plain", "DEBUG\n", http.StatusOK, `{"level":"DEBUG"}`, slog.LevelDebug},
+		{"put json", http.MethodPut, "application/json; charset=utf-8", `{"level":"warning"}`, http.StatusOK, `{"level":"WARN"}`, slog.LevelWarn},
+		{"unknown level", http.MethodPut, "text/plain", "verbose", http.StatusBadRequest, `{"error":"unknown log level \"verbose\""}`, slog.LevelWarn},
+		{"invalid json", http.MethodPut, "application/json", `{`, http.StatusBadRequest, "", slog.LevelWarn},
+		{"method not allowed", http.MethodPost, "", "", http.StatusMethodNotAllowed, "", slog.LevelWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/log-level", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("expected body %s, got %s", tt.wantBody, rec.Body.String())
+			}
+			if levelVar.Level() != tt.wantLevel {
+				t.Errorf("expected level %s, got %s", tt.wantLevel, levelVar.Level())
+			}
+		})
+	}
+}
+
+func TestLevelVar_Concurrency(t *testing.T) {
+	levelVar := NewLevelVar(slog.LevelInfo)
+	logger := New(WithWriter(&bytes.Buffer{}), WithLevelVar(levelVar))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			logger.Debug("tick", "i", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			levelVar.Set(slog.Level((i % 2) * -4))
+		}
+	}()
+	wg.Wait()
+}
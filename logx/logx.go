@@ -18,6 +18,8 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"sync"
+	"time"
 
 	"go.eggybyte.com/egg/core/identity"
 	"go.eggybyte.com/egg/core/log"
@@ -44,14 +46,42 @@ type Options struct {
 	Writer           io.Writer  // Output writer (default: os.Stderr)
 	PayloadMaxBytes  int        // Maximum bytes to log for large payloads (0 = unlimited)
 	SensitiveFields  []string   // Field names to mask (e.g., "password", "token")
+	RedactKeys       []string   // Key patterns whose values are replaced with "***"
 	DisableTimestamp bool       // Disable timestamp in output
 	DisableCaller    bool       // Disable caller information
+
+	Sampling      *SamplingOptions // Suppress repeated records (nil = log everything)
+	ContextFields []string         // Correlation fields added by FromContext (nil = request_id, user_id)
+	LevelVar      *LevelVar        // Changeable minimum level; overrides Level when set
+	FilePath      string           // Rotating log file ("" = write to Writer only)
+	Rotate        RotateOptions    // Rotation of FilePath
+	StackTraces   bool             // Add a stack to every logged error (default: log.Err fields with a stack only)
+	StackDepth    int              // Maximum frames per stack (default: 32)
+
+	DisableDefaultRedaction bool // Do not redact password, token, secret and authorization keys
+}
+
+// RotateOptions configures the rotating log file of WithFileOutput.
+type RotateOptions struct {
+	MaxSizeMB  int  // Rotate before the file exceeds this size (default: 100)
+	MaxBackups int  // Rotated files kept (0 = all)
+	MaxAgeDays int  // Rotated files older than this are removed (0 = no limit)
+	Compress   bool // Gzip rotated files
+	Tee        bool // Also write to Writer (default: file only)
 }
 
+// SamplingOptions configures log sampling: per level and message, the
+// first Initial records of each Interval are logged, then every
+// Thereafter-th record.
+type SamplingOptions = internal.SamplingOptions
+
 // Logger implements the core/log.Logger interface using slog.
 type Logger struct {
-	handler *internal.Handler
-	attrs   []slog.Attr
+	handler       *internal.Handler
+	attrs         []slog.Attr
+	contextFields []string
+	file          *internal.RotatingFile // nil without WithFileOutput
+	stacks        stackOptions
 }
 
 // New creates a new Logger with the given options.
@@ -72,6 +102,26 @@ func New(opts ...Option) log.Logger {
 		options.Writer = os.Stderr
 	}
 
+	var (
+		file    *internal.RotatingFile
+		fileErr error
+	)
+	if options.FilePath != "" {
+		file, fileErr = openLogFile(options.FilePath, options.Rotate)
+		if fileErr == nil {
+			if options.Rotate.Tee {
+				options.Writer = io.MultiWriter(file, options.Writer)
+			} else {
+				options.Writer = file
+			}
+		}
+	}
+
+	redactKeys := options.RedactKeys
+	if !options.DisableDefaultRedaction {
+		redactKeys = append(append([]string{}, internal.DefaultRedactKeys...), redactKeys...)
+	}
+
 	handler := internal.NewHandler(internal.Options{
 		Format:           string(options.Format),
 		Level:            options.Level,
@@ -79,13 +129,40 @@ func New(opts ...Option) log.Logger {
 		Writer:           options.Writer,
 		PayloadMaxBytes:  options.PayloadMaxBytes,
 		SensitiveFields:  options.SensitiveFields,
+		RedactKeys:       redactKeys,
 		DisableTimestamp: options.DisableTimestamp,
 		DisableCaller:    options.DisableCaller,
+		Sampling:         options.Sampling,
+		LevelVar:         options.LevelVar,
 	}, options.Writer)
 
-	return &Logger{
-		handler: handler,
+	logger := &Logger{
+		handler:       handler,
+		contextFields: options.ContextFields,
+		file:          file,
+		stacks:        newStackOptions(options.StackTraces, options.StackDepth),
 	}
+	if fileErr != nil {
+		// New cannot fail, so report the problem where logs still go
+		logger.Error(fileErr, "failed to open log file, logging to writer",
+			"path", options.FilePath)
+	}
+	return logger
+}
+
+// openLogFile opens the rotating file configured by WithFileOutput.
+func openLogFile(path string, opts RotateOptions) (*internal.RotatingFile, error) {
+	maxSizeMB := opts.MaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	return internal.OpenRotatingFile(path, internal.RotateConfig{
+		MaxBytes:      int64(maxSizeMB) << 20,
+		MaxBackups:    opts.MaxBackups,
+		MaxAge:        time.Duration(opts.MaxAgeDays) * 24 * time.Hour,
+		Compress:      opts.Compress,
+		FlushInterval: time.Second,
+	})
 }
 
 // Option configures logger behavior.
@@ -105,6 +182,36 @@ func WithLevel(level slog.Level) Option {
 	}
 }
 
+// WithLevelVar makes the logger use v as its minimum level, so the level
+// can be changed at runtime, e.g. on configuration reload or through
+// LevelHandler. Loggers created with the same v change together.
+func WithLevelVar(v *LevelVar) Option {
+	return func(o *Options) {
+		o.LevelVar = v
+	}
+}
+
+// WithFileOutput writes logs to a file at path, rotated by size. Rotated
+// files are named after the file and the rotation time, e.g.
+// app-2024-01-15T10-30-00.000.log. Writes are buffered and flushed every
+// second; call Sync or Close on shutdown to flush the rest. If the file
+// cannot be opened, logs go to the Writer and the error is logged there.
+//
+// Example:
+//
+//	logger := logx.New(logx.WithFileOutput("/var/log/app/app.log", logx.RotateOptions{
+//		MaxSizeMB:  100,
+//		MaxBackups: 7,
+//		Compress:   true,
+//	}))
+//	defer logger.(*logx.Logger).Close()
+func WithFileOutput(path string, opts RotateOptions) Option {
+	return func(o *Options) {
+		o.FilePath = path
+		o.Rotate = opts
+	}
+}
+
 // WithColor enables colorization for the level field only.
 func WithColor(enabled bool) Option {
 	return func(o *Options) {
@@ -133,16 +240,97 @@ func WithSensitiveFields(fields ...string) Option {
 	}
 }
 
+// WithRedactKeys replaces with "***" the values of attributes whose key, or
+// the key of an enclosing group, matches one of the patterns. Matching
+// ignores case, and '*' matches any run of characters, so "*_key" covers
+// "api_key" and "signing_key". Redaction applies at emit time in every
+// format. The patterns add to the default list, which covers password,
+// token, secret and authorization keys (see WithoutDefaultRedaction).
+// Values are masked by key only; fields inside a struct logged as one
+// value are not inspected.
+//
+// Example:
+//
+//	logger := logx.New(logx.WithRedactKeys([]string{"*_key", "ssn"}))
+//	logger.Info("signup", "api_key", "sk_live_123", "ssn", "078-05-1120")
+//	// Output: level=INFO msg="signup" api_key="***" ssn="***"
+func WithRedactKeys(patterns []string) Option {
+	return func(o *Options) {
+		o.RedactKeys = append(o.RedactKeys, patterns...)
+	}
+}
+
+// WithoutDefaultRedaction disables the default redaction of password,
+// token, secret and authorization keys. Patterns set with WithRedactKeys
+// still apply.
+func WithoutDefaultRedaction() Option {
+	return func(o *Options) {
+		o.DisableDefaultRedaction = true
+	}
+}
+
+// WithSampling suppresses floods of identical records. Records are keyed
+// by level and a hash of the message, so attributes do not affect sampling.
+// When records were dropped, a WARN summary with the dropped count is
+// written before the next record once per interval.
+//
+// Example:
+//
+//	logger := logx.New(logx.WithSampling(logx.SamplingOptions{
+//		Initial:    100,
+//		Thereafter: 100,
+//		Interval:   time.Second,
+//	}))
+func WithSampling(opts SamplingOptions) Option {
+	return func(o *Options) {
+		o.Sampling = &opts
+	}
+}
+
 // With returns a new Logger with the given key-value pairs attached.
 func (l *Logger) With(kv ...any) log.Logger {
-	attrs := internal.KVToAttrs(kv)
+	attrs := l.errorAttrs(internal.KVToAttrs(kv))
 	newAttrs := append([]slog.Attr{}, l.attrs...)
 	newAttrs = append(newAttrs, attrs...)
 
 	return &Logger{
-		handler: l.handler,
-		attrs:   newAttrs,
+		handler:       l.handler,
+		attrs:         newAttrs,
+		contextFields: l.contextFields,
+		file:          l.file,
+		stacks:        l.stacks,
+	}
+}
+
+// SetLevel changes the minimum level of l and of every logger sharing its
+// level: loggers derived with With, and loggers created with the same
+// WithLevelVar.
+func (l *Logger) SetLevel(level slog.Level) {
+	l.handler.LevelVar().Set(level)
+}
+
+// Level returns the current minimum level.
+func (l *Logger) Level() slog.Level {
+	return l.handler.LevelVar().Level()
+}
+
+// Sync flushes buffered writes to the log file. It is a no-op without
+// WithFileOutput.
+func (l *Logger) Sync() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Sync()
+}
+
+// Close flushes and closes the log file; later records to the file are
+// dropped. It affects every logger derived from l and is a no-op without
+// WithFileOutput.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
 	}
+	return l.file.Close()
 }
 
 // Debug logs a debug message.
@@ -162,16 +350,22 @@ func (l *Logger) Warn(msg string, kv ...any) {
 
 // Error logs an error message.
 func (l *Logger) Error(err error, msg string, kv ...any) {
-	attrs := internal.KVToAttrs(kv)
+	attrs := l.errorAttrs(internal.KVToAttrs(kv))
 	if err != nil {
-		attrs = append([]slog.Attr{slog.Any("error", err)}, attrs...)
+		errAttrs := []slog.Attr{slog.Any("error", err)}
+		if l.stacks.enabled {
+			if stack, ok := l.stackAttr(err); ok {
+				errAttrs = append(errAttrs, stack)
+			}
+		}
+		attrs = append(errAttrs, attrs...)
 	}
 	l.logWithAttrs(slog.LevelError, msg, attrs)
 }
 
 // log is the internal logging method.
 func (l *Logger) log(level slog.Level, msg string, kv ...any) {
-	attrs := internal.KVToAttrs(kv)
+	attrs := l.errorAttrs(internal.KVToAttrs(kv))
 	l.logWithAttrs(level, msg, attrs)
 }
 
@@ -184,28 +378,150 @@ func (l *Logger) logWithAttrs(level slog.Level, msg string, attrs []slog.Attr) {
 	l.handler.LogRecord(level, msg, allAttrs)
 }
 
-// FromContext creates a logger with context-injected fields (trace_id, request_id, user_id).
-func FromContext(ctx context.Context, base log.Logger) log.Logger {
-	var attrs []any
+// Correlation fields FromContext can add, extracted from the identity and
+// request metadata connectx stores in the context.
+const (
+	FieldRequestID = "request_id" // identity.RequestMeta.RequestID
+	FieldUserID    = "user_id"    // identity.UserInfo.UserID
+	FieldUserName  = "user_name"  // identity.UserInfo.UserName
+	FieldRemoteIP  = "remote_ip"  // identity.RequestMeta.RemoteIP
+	FieldUserAgent = "user_agent" // identity.RequestMeta.UserAgent
+)
 
-	// Extract request metadata
-	if meta, ok := identity.MetaFrom(ctx); ok {
-		if meta.RequestID != "" {
-			attrs = append(attrs, "request_id", meta.RequestID)
-		}
+// defaultContextFields are added by FromContext unless WithContextFields
+// configures others.
+var defaultContextFields = []string{FieldRequestID, FieldUserID}
+
+// ContextFieldFunc extracts a correlation field from a context, reporting
+// whether it is present.
+type ContextFieldFunc func(ctx context.Context) (any, bool)
+
+var (
+	contextFieldsMu sync.RWMutex
+	contextFields   = map[string]ContextFieldFunc{
+		FieldRequestID: metaField(func(m *identity.RequestMeta) string { return m.RequestID }),
+		FieldRemoteIP:  metaField(func(m *identity.RequestMeta) string { return m.RemoteIP }),
+		FieldUserAgent: metaField(func(m *identity.RequestMeta) string { return m.UserAgent }),
+		FieldUserID:    userField(func(u *identity.UserInfo) string { return u.UserID }),
+		FieldUserName:  userField(func(u *identity.UserInfo) string { return u.UserName }),
 	}
+)
+
+// RegisterContextField makes a correlation field available to
+// WithContextFields, replacing any extractor registered for key. Call it
+// during initialization, e.g. to add trace IDs without logx depending on
+// OpenTelemetry.
+//
+// Example:
+//
+//	logx.RegisterContextField("trace_id", func(ctx context.Context) (any, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.HasTraceID()
+//	})
+func RegisterContextField(key string, extract ContextFieldFunc) {
+	contextFieldsMu.Lock()
+	defer contextFieldsMu.Unlock()
+	contextFields[key] = extract
+}
 
-	// Extract user info
-	if user, ok := identity.UserFrom(ctx); ok {
-		if user.UserID != "" {
-			attrs = append(attrs, "user_id", user.UserID)
+// WithContextFields sets the correlation fields FromContext adds, in order
+// (default: request_id, user_id). Keys are the Field constants or keys
+// registered with RegisterContextField; unknown keys are ignored.
+func WithContextFields(keys ...string) Option {
+	return func(o *Options) {
+		o.ContextFields = append([]string{}, keys...)
+	}
+}
+
+// loggerContextKey is the context key of the logger stored by NewContext.
+type loggerContextKey struct{}
+
+// NewContext returns a context carrying logger, used by FromContext when
+// no base logger is passed.
+func NewContext(ctx context.Context, logger log.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns a logger with the correlation fields found in ctx,
+// such as request_id and user_id injected by connectx.
+//
+// The logger is base if given, otherwise the logger stored with
+// NewContext, otherwise a default logger. Fields are those configured
+// with WithContextFields on that logger; fields missing or empty in ctx
+// are omitted.
+//
+// Example:
+//
+//	ctx = logx.NewContext(ctx, logger) // once, e.g. in middleware
+//	logx.FromContext(ctx).Info("processing request")
+//	// level=INFO msg="processing request" request_id="req-123" user_id="u-456"
+func FromContext(ctx context.Context, base ...log.Logger) log.Logger {
+	logger := contextLogger(ctx, base)
+
+	keys := defaultContextFields
+	if l, ok := logger.(*Logger); ok && l.contextFields != nil {
+		keys = l.contextFields
+	}
+
+	var attrs []any
+	contextFieldsMu.RLock()
+	for _, key := range keys {
+		extract, ok := contextFields[key]
+		if !ok {
+			continue
+		}
+		if value, ok := extract(ctx); ok {
+			attrs = append(attrs, key, value)
 		}
 	}
+	contextFieldsMu.RUnlock()
 
 	if len(attrs) > 0 {
-		return base.With(attrs...)
+		return logger.With(attrs...)
+	}
+	return logger
+}
+
+// defaultLogger is used by FromContext when no logger is available.
+var (
+	defaultOnce   sync.Once
+	defaultLogger log.Logger
+)
+
+// contextLogger picks the logger FromContext adds fields to.
+func contextLogger(ctx context.Context, base []log.Logger) log.Logger {
+	if len(base) > 0 && base[0] != nil {
+		return base[0]
+	}
+	if logger, ok := ctx.Value(loggerContextKey{}).(log.Logger); ok && logger != nil {
+		return logger
+	}
+	defaultOnce.Do(func() {
+		defaultLogger = New()
+	})
+	return defaultLogger
+}
+
+// metaField returns an extractor for a non-empty request metadata field.
+func metaField(get func(*identity.RequestMeta) string) ContextFieldFunc {
+	return func(ctx context.Context) (any, bool) {
+		meta, ok := identity.MetaFrom(ctx)
+		if !ok || get(meta) == "" {
+			return nil, false
+		}
+		return get(meta), true
+	}
+}
+
+// userField returns an extractor for a non-empty user identity field.
+func userField(get func(*identity.UserInfo) string) ContextFieldFunc {
+	return func(ctx context.Context) (any, bool) {
+		user, ok := identity.UserFrom(ctx)
+		if !ok || get(user) == "" {
+			return nil, false
+		}
+		return get(user), true
 	}
-	return base
 }
 
 // ParseLevel converts a log level string to slog.Level.
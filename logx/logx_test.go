@@ -3,6 +3,7 @@ package logx
 import (
 	"bytes"
 	"context"
+	"errors"
 	"log/slog"
 	"strings"
 	"testing"
@@ -29,6 +30,18 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithFormat(FormatJSON))
+
+	logger.With("service", "api").Error(errors.New("db down"), "request failed", "status", 503)
+
+	want := `{"level":"ERROR","msg":"request failed","error":"db down","service":"api","status":503}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestFieldSorting(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(WithWriter(&buf), WithColor(false))
@@ -83,6 +96,29 @@ func TestSensitiveFields(t *testing.T) {
 	}
 }
 
+func TestRedactKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithRedactKeys([]string{"*_key"}))
+
+	logger.Info("call", "api_key", "sk_live_123", "authorization", "Bearer abc", "user", "alice")
+
+	want := `level=INFO msg="call" api_key="***" authorization="***" user="alice"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Output = %q, want %q", got, want)
+	}
+}
+
+func TestWithoutDefaultRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithFormat(FormatJSON), WithoutDefaultRedaction())
+
+	logger.Info("login", "password", "secret123")
+
+	if !strings.Contains(buf.String(), `"password":"secret123"`) {
+		t.Errorf("password should not be redacted: %s", buf.String())
+	}
+}
+
 func TestPayloadLimit(t *testing.T) {
 	var buf bytes.Buffer
 	logger := New(
@@ -212,6 +248,58 @@ func TestFromContext(t *testing.T) {
 	}
 }
 
+func TestFromContext_StoredLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), New(WithWriter(&buf)))
+	ctx = identity.WithMeta(ctx, &identity.RequestMeta{RequestID: "req-abc"})
+
+	FromContext(ctx).Info("test message")
+
+	if !strings.Contains(buf.String(), `request_id="req-abc"`) {
+		t.Errorf("expected request_id from the stored logger: %s", buf.String())
+	}
+	if FromContext(context.Background()) == nil {
+		t.Error("expected a default logger without a stored logger")
+	}
+}
+
+func TestFromContext_WithContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithContextFields(FieldRemoteIP, FieldUserName, "unknown"))
+
+	ctx := identity.WithUser(context.Background(), &identity.UserInfo{UserID: "u-123", UserName: "alice"})
+	ctx = identity.WithMeta(ctx, &identity.RequestMeta{RequestID: "req-abc", RemoteIP: "10.0.0.1"})
+
+	// Fields survive With, and missing fields are skipped
+	FromContext(ctx, logger.With("component", "api")).Info("test message")
+
+	want := `level=INFO msg="test message" component="api" remote_ip="10.0.0.1" user_name="alice"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRegisterContextField(t *testing.T) {
+	type traceKey struct{}
+	RegisterContextField("trace_id", func(ctx context.Context) (any, bool) {
+		id, ok := ctx.Value(traceKey{}).(string)
+		return id, ok
+	})
+
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithContextFields("trace_id", FieldRequestID))
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f35")
+
+	FromContext(ctx, logger).Info("test message")
+
+	if !strings.Contains(buf.String(), `trace_id="4bf92f35"`) {
+		t.Errorf("expected trace_id in output: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "request_id") {
+		t.Errorf("expected missing request_id to be omitted: %s", buf.String())
+	}
+}
+
 func BenchmarkLogger(b *testing.B) {
 	logger := New(WithWriter(&bytes.Buffer{}), WithColor(false))
 
@@ -0,0 +1,142 @@
+package logx
+
+import (
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.eggybyte.com/egg/core/errors"
+	"go.eggybyte.com/egg/core/log"
+)
+
+// defaultStackDepth is the number of frames logged per stack unless
+// WithStackDepth sets another.
+const defaultStackDepth = 32
+
+// loggerFramePrefix prefixes the frames of Logger methods, which are
+// trimmed from stacks captured at the log call.
+const loggerFramePrefix = "go.eggybyte.com/egg/logx.(*Logger)."
+
+// stackOptions configures stack traces of logged errors.
+type stackOptions struct {
+	enabled bool // Stacks for every logged error, captured at the call if needed
+	depth   int  // Maximum frames per stack
+}
+
+// newStackOptions applies defaults to the configured stack options.
+func newStackOptions(enabled bool, depth int) stackOptions {
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+	return stackOptions{enabled: enabled, depth: depth}
+}
+
+// WithStackTraces adds a stack attribute to every logged error: the error
+// passed to Error and log.Err fields. Errors carrying a stack, such as
+// those annotated by core/errors WithStack, log that stack; others log the stack of
+// the log call. Off by default, when only log.Err fields whose error
+// carries a stack get one; capturing stacks costs a few microseconds per
+// record.
+//
+// Example:
+//
+//	logger := logx.New(logx.WithStackTraces(true))
+//	logger.Error(err, "request failed")
+//	// level=ERROR msg="request failed" error="..." stack="main.handle\n\t/app/main.go:42\n..."
+func WithStackTraces(enabled bool) Option {
+	return func(o *Options) {
+		o.StackTraces = enabled
+	}
+}
+
+// WithStackDepth sets the maximum number of frames logged per stack
+// (default: 32). Stacks recorded by core/errors hold at most 32 frames.
+func WithStackDepth(depth int) Option {
+	return func(o *Options) {
+		o.StackDepth = depth
+	}
+}
+
+// errorAttrs replaces log.Err fields with their error, each followed by
+// its stack when one is available.
+func (l *Logger) errorAttrs(attrs []slog.Attr) []slog.Attr {
+	var out []slog.Attr
+	for i, attr := range attrs {
+		field, ok := errorField(attr)
+		if !ok {
+			if out != nil {
+				out = append(out, attr)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]slog.Attr, 0, len(attrs)+1), attrs[:i]...)
+		}
+		out = append(out, slog.Any(attr.Key, field.Err))
+		if stack, ok := l.stackAttr(field.Err); ok {
+			out = append(out, stack)
+		}
+	}
+	if out == nil {
+		return attrs
+	}
+	return out
+}
+
+// errorField returns the log.Err field held by attr, if any.
+func errorField(attr slog.Attr) (log.ErrorField, bool) {
+	if attr.Value.Kind() != slog.KindAny {
+		return log.ErrorField{}, false
+	}
+	field, ok := attr.Value.Any().(log.ErrorField)
+	return field, ok
+}
+
+// stackAttr returns the stack attribute for err: the stack err carries,
+// or with stack traces enabled the stack of the log call.
+func (l *Logger) stackAttr(err error) (slog.Attr, bool) {
+	if err == nil {
+		return slog.Attr{}, false
+	}
+	var frames []runtime.Frame
+	if stack := errors.StackOf(err); stack != nil {
+		frames = stack.Frames(l.stacks.depth)
+	} else if l.stacks.enabled {
+		frames = callerFrames(l.stacks.depth)
+	}
+	if len(frames) == 0 {
+		return slog.Attr{}, false
+	}
+	return slog.String("stack", formatFrames(frames)), true
+}
+
+// callerFrames returns up to depth frames of the stack above the Logger
+// method that was called.
+func callerFrames(depth int) []runtime.Frame {
+	// Headroom for the Logger frames trimmed below
+	frames := errors.Callers(1, depth+8).Frames(0)
+	for len(frames) > 0 && strings.HasPrefix(frames[0].Function, loggerFramePrefix) {
+		frames = frames[1:]
+	}
+	if len(frames) > depth {
+		frames = frames[:depth]
+	}
+	return frames
+}
+
+// formatFrames renders frames in the layout of errors.Stack.String.
+func formatFrames(frames []runtime.Frame) string {
+	var b strings.Builder
+	for i, frame := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package logx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/core/errors"
+	"go.eggybyte.com/egg/core/log"
+)
+
+// newNotFound creates a core/errors error with a stack one call below the test.
+func newNotFound() error {
+	return errors.WithStack(errors.New(errors.CodeNotFound, "user not found"))
+}
+
+func TestErrField_CarriedStack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf))
+
+	logger.Warn("lookup failed", log.Err(newNotFound()))
+
+	output := buf.String()
+	if !strings.Contains(output, `error="NOT_FOUND: user not found"`) {
+		t.Errorf("Expected error message, got: %s", output)
+	}
+	if !strings.Contains(output, `stack="go.eggybyte.com/egg/logx.newNotFound\n\t`) {
+		t.Errorf("Expected stack from where the error was created, got: %s", output)
+	}
+}
+
+func TestErrField_NoStack(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf))
+
+	logger.Warn("lookup failed", log.Err(fmt.Errorf("plain")))
+	logger.Error(newNotFound(), "request failed")
+
+	output := buf.String()
+	if strings.Contains(output, "stack=") {
+		t.Errorf("Expected no stacks with stack traces off, got: %s", output)
+	}
+	if !strings.Contains(output, `error="plain"`) {
+		t.Errorf("Expected error message, got: %s", output)
+	}
+}
+
+func TestWithStackTraces(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithStackTraces(true)).With("component", "api")
+
+	logger.Error(fmt.Errorf("plain"), "request failed")
+	line := strings.TrimSpace(buf.String())
+	if !strings.Contains(line, `stack="go.eggybyte.com/egg/logx.TestWithStackTraces\n\t`) {
+		t.Errorf("Expected stack of the log call, got: %s", line)
+	}
+	if strings.Contains(line, "(*Logger)") {
+		t.Errorf("Expected Logger frames to be trimmed, got: %s", line)
+	}
+
+	buf.Reset()
+	logger.Error(newNotFound(), "request failed")
+	if !strings.Contains(buf.String(), `stack="go.eggybyte.com/egg/logx.newNotFound\n\t`) {
+		t.Errorf("Expected the carried stack to win, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.Info("retrying", log.Err(fmt.Errorf("plain")))
+	if !strings.Contains(buf.String(), "stack=") {
+		t.Errorf("Expected stack for log.Err field, got: %s", buf.String())
+	}
+}
+
+func TestWithStackDepth(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(WithWriter(&buf), WithStackTraces(true), WithStackDepth(2), WithFormat(FormatJSON))
+
+	logger.Error(newNotFound(), "request failed")
+
+	output := buf.String()
+	start := strings.Index(output, `"stack":"`)
+	if start < 0 {
+		t.Fatalf("Expected stack attribute, got: %s", output)
+	}
+	stack := output[start+len(`"stack":"`):]
+	stack = stack[:strings.Index(stack, `"`)]
+	if frames := strings.Count(stack, `\n\t`); frames != 2 {
+		t.Errorf("Expected 2 frames, got %d: %s", frames, stack)
+	}
+}
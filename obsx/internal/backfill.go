@@ -0,0 +1,118 @@
+// Package internal provides internal implementation for the obsx package.
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+)
+
+// BackfillMetric is a Prometheus collector that keeps the last recorded value per
+// label set together with an optional explicit observation time.
+//
+// OpenTelemetry instruments always record at collection time, so backfilled data
+// goes straight to the Prometheus registry instead. Samples with a timestamp are
+// exported with that timestamp; samples without one are exported without a
+// timestamp and take the scrape time.
+type BackfillMetric struct {
+	name      string
+	help      string
+	desc      *promclient.Desc
+	valueType promclient.ValueType
+	numLabels int
+
+	mu      sync.Mutex
+	samples map[string]*backfillSample
+}
+
+type backfillSample struct {
+	labelValues []string
+	value       float64
+	timestamp   time.Time
+}
+
+// NewBackfillMetric creates a backfill collector of the given value type.
+// Call Register to expose it on the provider's Prometheus registry.
+func NewBackfillMetric(name, help string, valueType promclient.ValueType, labelNames []string) *BackfillMetric {
+	return &BackfillMetric{
+		name:      name,
+		help:      help,
+		desc:      promclient.NewDesc(name, help, labelNames, nil),
+		valueType: valueType,
+		numLabels: len(labelNames),
+		samples:   make(map[string]*backfillSample),
+	}
+}
+
+// Set replaces the value for the label set and records ts as its observation time.
+// A zero ts exports the sample without a timestamp.
+func (m *BackfillMetric) Set(value float64, labelValues []string, ts time.Time) error {
+	return m.record(labelValues, ts, func(s *backfillSample) { s.value = value })
+}
+
+// Add increases the value for the label set and records ts as its observation time.
+// A zero ts exports the sample without a timestamp.
+func (m *BackfillMetric) Add(delta float64, labelValues []string, ts time.Time) error {
+	if m.valueType == promclient.CounterValue && delta < 0 {
+		return fmt.Errorf("counter cannot decrease: delta %v", delta)
+	}
+	return m.record(labelValues, ts, func(s *backfillSample) { s.value += delta })
+}
+
+func (m *BackfillMetric) record(labelValues []string, ts time.Time, update func(*backfillSample)) error {
+	if len(labelValues) != m.numLabels {
+		return fmt.Errorf("expected %d label values, got %d", m.numLabels, len(labelValues))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.samples[key]
+	if !ok {
+		s = &backfillSample{labelValues: append([]string(nil), labelValues...)}
+		m.samples[key] = s
+	}
+	update(s)
+	s.timestamp = ts
+	return nil
+}
+
+// Describe implements prometheus.Collector.
+func (m *BackfillMetric) Describe(ch chan<- *promclient.Desc) {
+	ch <- m.desc
+}
+
+// Collect implements prometheus.Collector.
+func (m *BackfillMetric) Collect(ch chan<- promclient.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.samples {
+		metric, err := promclient.NewConstMetric(m.desc, m.valueType, s.value, s.labelValues...)
+		if err != nil {
+			ch <- promclient.NewInvalidMetric(m.desc, err)
+			continue
+		}
+		if !s.timestamp.IsZero() {
+			metric = promclient.NewMetricWithTimestamp(s.timestamp, metric)
+		}
+		ch <- metric
+	}
+}
+
+// RegisterBackfillMetric registers a backfill collector on the provider's Prometheus registry.
+func (p *Provider) RegisterBackfillMetric(m *BackfillMetric) error {
+	if p.registerer == nil {
+		return fmt.Errorf("prometheus registry is not available")
+	}
+	if err := p.registerer.Register(m); err != nil {
+		return fmt.Errorf("failed to register backfill metric: %w", err)
+	}
+	p.catalog.AddCollectorMetric(m.name, m.help, m.valueType)
+	return nil
+}
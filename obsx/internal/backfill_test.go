@@ -0,0 +1,133 @@
+// Package internal provides tests for obsx backfill metrics.
+package internal
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newBackfillTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	provider, err := NewProvider(context.Background(), ProviderOptions{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return provider
+}
+
+func findBackfillFamily(t *testing.T, provider *Provider, name string) *dto.MetricFamily {
+	t.Helper()
+	families, err := provider.gatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() == name {
+			return mf
+		}
+	}
+	t.Fatalf("metric family %s not exported", name)
+	return nil
+}
+
+func TestBackfillMetric_ExportsObservationTime(t *testing.T) {
+	provider := newBackfillTestProvider(t)
+
+	gauge := NewBackfillMetric("import_orders_open", "Open orders", promclient.GaugeValue, []string{"region"})
+	if err := provider.RegisterBackfillMetric(gauge); err != nil {
+		t.Fatalf("RegisterBackfillMetric() error = %v", err)
+	}
+
+	observed := time.Date(2023, 3, 14, 15, 9, 26, 0, time.UTC)
+	if err := gauge.Set(42, []string{"eu"}, observed); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := gauge.Set(7, []string{"us"}, time.Time{}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	mf := findBackfillFamily(t, provider, "import_orders_open")
+	if len(mf.GetMetric()) != 2 {
+		t.Fatalf("exported %d series, want 2", len(mf.GetMetric()))
+	}
+	for _, m := range mf.GetMetric() {
+		region := m.GetLabel()[0].GetValue()
+		switch region {
+		case "eu":
+			if m.GetGauge().GetValue() != 42 {
+				t.Errorf("eu value = %v, want 42", m.GetGauge().GetValue())
+			}
+			if m.TimestampMs == nil || m.GetTimestampMs() != observed.UnixMilli() {
+				t.Errorf("eu timestamp = %v, want %d", m.TimestampMs, observed.UnixMilli())
+			}
+		case "us":
+			if m.TimestampMs != nil {
+				t.Errorf("us timestamp = %d, want none", m.GetTimestampMs())
+			}
+		default:
+			t.Errorf("unexpected region %q", region)
+		}
+	}
+
+	// The timestamp must survive the text exposition served to scrapers
+	rec := httptest.NewRecorder()
+	provider.GetPrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	want := `import_orders_open{region="eu"} 42 1678806566000`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("exposition missing %q:\n%s", want, rec.Body.String())
+	}
+}
+
+func TestBackfillMetric_CounterAccumulates(t *testing.T) {
+	provider := newBackfillTestProvider(t)
+
+	counter := NewBackfillMetric("import_rows", "Imported rows", promclient.CounterValue, nil)
+	if err := provider.RegisterBackfillMetric(counter); err != nil {
+		t.Fatalf("RegisterBackfillMetric() error = %v", err)
+	}
+
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	counter.Add(10, nil, first)
+	counter.Add(5, nil, second)
+
+	m := findBackfillFamily(t, provider, "import_rows").GetMetric()[0]
+	if m.GetCounter().GetValue() != 15 {
+		t.Errorf("counter value = %v, want 15", m.GetCounter().GetValue())
+	}
+	if m.GetTimestampMs() != second.UnixMilli() {
+		t.Errorf("counter timestamp = %d, want %d", m.GetTimestampMs(), second.UnixMilli())
+	}
+
+	if err := counter.Add(-1, nil, second); err == nil {
+		t.Error("Add() should reject negative delta on a counter")
+	}
+}
+
+func TestBackfillMetric_LabelMismatch(t *testing.T) {
+	gauge := NewBackfillMetric("import_lag_seconds", "Import lag", promclient.GaugeValue, []string{"source"})
+	if err := gauge.Set(1, nil, time.Time{}); err == nil {
+		t.Error("Set() should reject missing label values")
+	}
+	if err := gauge.Set(1, []string{"a", "b"}, time.Time{}); err == nil {
+		t.Error("Set() should reject extra label values")
+	}
+}
+
+func TestRegisterBackfillMetric_Duplicate(t *testing.T) {
+	provider := newBackfillTestProvider(t)
+
+	if err := provider.RegisterBackfillMetric(NewBackfillMetric("import_dup", "dup", promclient.GaugeValue, nil)); err != nil {
+		t.Fatalf("RegisterBackfillMetric() error = %v", err)
+	}
+	if err := provider.RegisterBackfillMetric(NewBackfillMetric("import_dup", "dup", promclient.GaugeValue, nil)); err == nil {
+		t.Error("RegisterBackfillMetric() should reject duplicate metric names")
+	}
+}
@@ -0,0 +1,176 @@
+// Package internal provides internal implementation for obsx.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"go.eggybyte.com/egg/core/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// SeriesCountMetricName is the gauge exposing the sampled series count per metric.
+const SeriesCountMetricName = "obsx_metric_series_count"
+
+// CardinalityOptions configures the cardinality monitor.
+type CardinalityOptions struct {
+	Logger    log.Logger    // Logger receiving threshold warnings (required)
+	Threshold int           // Series count per metric that triggers a warning (default: 1000)
+	Interval  time.Duration // Sampling interval (default: 30s)
+}
+
+// CardinalityMonitor periodically samples series counts per metric family
+// and warns when a metric exceeds the configured threshold.
+type CardinalityMonitor struct {
+	gatherer  promclient.Gatherer
+	logger    log.Logger
+	threshold int
+	interval  time.Duration
+
+	mu       sync.RWMutex
+	counts   map[string]int
+	exceeded map[string]bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewCardinalityMonitor creates a monitor and registers the series count gauge.
+// The monitor does not sample until Start or Sample is called.
+//
+// Parameters:
+//   - meterProvider: OpenTelemetry meter provider for the series count gauge
+//   - gatherer: Prometheus gatherer used to count series
+//   - opts: monitor configuration
+//
+// Returns:
+//   - *CardinalityMonitor: monitor instance
+//   - error: registration error if any
+func NewCardinalityMonitor(meterProvider *sdkmetric.MeterProvider, gatherer promclient.Gatherer, opts CardinalityOptions) (*CardinalityMonitor, error) {
+	if opts.Logger == nil {
+		return nil, fmt.Errorf("logger is required")
+	}
+	if gatherer == nil {
+		return nil, fmt.Errorf("prometheus registry is not available")
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = 1000
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+
+	m := &CardinalityMonitor{
+		gatherer:  gatherer,
+		logger:    opts.Logger,
+		threshold: opts.Threshold,
+		interval:  opts.Interval,
+		counts:    make(map[string]int),
+		exceeded:  make(map[string]bool),
+		stop:      make(chan struct{}),
+	}
+
+	meter := meterProvider.Meter("go.eggybyte.com/egg/obsx/cardinality")
+	seriesCount, err := meter.Int64ObservableGauge(
+		SeriesCountMetricName,
+		metric.WithDescription("Number of series per metric as of the last cardinality sample"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.RegisterCallback(
+		func(ctx context.Context, observer metric.Observer) error {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+			for name, count := range m.counts {
+				observer.ObserveInt64(seriesCount, int64(count),
+					metric.WithAttributes(attribute.String("metric", name)))
+			}
+			return nil
+		},
+		seriesCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Start runs the sampling loop in the background until ctx is cancelled or Stop is called.
+func (m *CardinalityMonitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.Sample()
+			}
+		}
+	}()
+}
+
+// Stop stops the sampling loop.
+// Safe to call multiple times and before Start.
+func (m *CardinalityMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Sample gathers all metric families once, updates the series counts and
+// logs a warning for every metric that newly crossed the threshold.
+// A metric warns again only after dropping back below the threshold.
+func (m *CardinalityMonitor) Sample() {
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		m.logger.Error(err, "failed to gather metrics for cardinality sampling")
+		return
+	}
+
+	counts := make(map[string]int, len(families))
+	for _, mf := range families {
+		if mf.GetName() == SeriesCountMetricName {
+			continue
+		}
+		counts[mf.GetName()] = len(mf.GetMetric())
+	}
+
+	m.mu.Lock()
+	m.counts = counts
+	var spiked []string
+	for name, count := range counts {
+		over := count > m.threshold
+		if over && !m.exceeded[name] {
+			spiked = append(spiked, name)
+		}
+		m.exceeded[name] = over
+	}
+	m.mu.Unlock()
+
+	for _, name := range spiked {
+		m.logger.Warn("metric cardinality exceeds threshold",
+			log.Str("metric", name),
+			log.Int("series", counts[name]),
+			log.Int("threshold", m.threshold))
+	}
+}
+
+// SeriesCount returns the series count of a metric as of the last sample.
+func (m *CardinalityMonitor) SeriesCount(name string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.counts[name]
+}
@@ -0,0 +1,152 @@
+// Package internal provides tests for obsx cardinality monitoring.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.eggybyte.com/egg/core/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type warnRecord struct {
+	msg string
+	kv  []any
+}
+
+// captureLogger records warnings for assertions.
+type captureLogger struct {
+	mu    sync.Mutex
+	warns []warnRecord
+}
+
+func (l *captureLogger) With(kv ...any) log.Logger              { return l }
+func (l *captureLogger) Debug(msg string, kv ...any)            {}
+func (l *captureLogger) Info(msg string, kv ...any)             {}
+func (l *captureLogger) Error(err error, msg string, kv ...any) {}
+func (l *captureLogger) Warn(msg string, kv ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, warnRecord{msg: msg, kv: kv})
+}
+
+func (l *captureLogger) warnCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.warns)
+}
+
+func TestCardinalityMonitor_WarnsPastThreshold(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, ProviderOptions{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	logger := &captureLogger{}
+	monitor, err := NewCardinalityMonitor(provider.MeterProvider, provider.gatherer, CardinalityOptions{
+		Logger:    logger,
+		Threshold: 10,
+	})
+	if err != nil {
+		t.Fatalf("NewCardinalityMonitor() error = %v", err)
+	}
+
+	counter, err := provider.MeterProvider.Meter("test").Int64Counter("test_requests")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+
+	// Stay at the threshold: no warning
+	for i := 0; i < 10; i++ {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("user", fmt.Sprintf("u%d", i))))
+	}
+	monitor.Sample()
+	if got := monitor.SeriesCount("test_requests"); got != 10 {
+		t.Errorf("SeriesCount() = %d, want 10", got)
+	}
+	if logger.warnCount() != 0 {
+		t.Fatalf("Warn() called %d times at threshold, want 0", logger.warnCount())
+	}
+
+	// Cross the threshold: warning fires once
+	for i := 10; i < 50; i++ {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("user", fmt.Sprintf("u%d", i))))
+	}
+	monitor.Sample()
+	if logger.warnCount() != 1 {
+		t.Fatalf("Warn() called %d times past threshold, want 1", logger.warnCount())
+	}
+	if logger.warns[0].msg != "metric cardinality exceeds threshold" {
+		t.Errorf("Warn() msg = %q", logger.warns[0].msg)
+	}
+
+	// Sampling again while still over the threshold must not repeat the warning
+	monitor.Sample()
+	if logger.warnCount() != 1 {
+		t.Errorf("Warn() called %d times on repeated sample, want 1", logger.warnCount())
+	}
+}
+
+func TestCardinalityMonitor_ExposesSeriesCountGauge(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, ProviderOptions{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	monitor, err := provider.EnableCardinalityMonitor(ctx, CardinalityOptions{Logger: &captureLogger{}})
+	if err != nil {
+		t.Fatalf("EnableCardinalityMonitor() error = %v", err)
+	}
+
+	counter, _ := provider.MeterProvider.Meter("test").Int64Counter("test_events")
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "a")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", "b")))
+	monitor.Sample()
+
+	families, err := provider.gatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+
+	found := false
+	for _, mf := range families {
+		if mf.GetName() != SeriesCountMetricName {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if lp.GetName() == "metric" && lp.GetValue() == "test_events" {
+					found = true
+					if v := m.GetGauge().GetValue(); v != 2 {
+						t.Errorf("%s{metric=test_events} = %v, want 2", SeriesCountMetricName, v)
+					}
+				}
+			}
+		}
+	}
+	if !found {
+		t.Errorf("%s{metric=test_events} not exported", SeriesCountMetricName)
+	}
+
+	if _, err := provider.EnableCardinalityMonitor(ctx, CardinalityOptions{Logger: &captureLogger{}}); err == nil {
+		t.Error("EnableCardinalityMonitor() should fail when already enabled")
+	}
+}
+
+func TestNewCardinalityMonitor_NilLogger(t *testing.T) {
+	provider, err := NewProvider(context.Background(), ProviderOptions{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	if _, err := NewCardinalityMonitor(provider.MeterProvider, provider.gatherer, CardinalityOptions{}); err == nil {
+		t.Error("NewCardinalityMonitor() should return error for nil logger")
+	}
+}
@@ -0,0 +1,122 @@
+// Package internal provides internal implementation for the obsx package.
+package internal
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// MetricInfo describes a metric exported on the Prometheus endpoint.
+type MetricInfo struct {
+	Name        string `json:"name"`            // Exported Prometheus name
+	Type        string `json:"type"`            // Prometheus type: counter, gauge, or histogram
+	Description string `json:"description"`     // Help text
+	Unit        string `json:"unit,omitempty"`  // OpenTelemetry unit (e.g., "s", "By")
+	Scope       string `json:"scope,omitempty"` // Instrumentation scope (meter name)
+}
+
+// Catalog records the metrics created on a provider. Instruments are seen
+// when they are created, through a view, so metrics appear in the catalog
+// before their first measurement.
+type Catalog struct {
+	renames map[string]string
+
+	mu      sync.RWMutex
+	metrics map[string]MetricInfo
+}
+
+// NewCatalog creates an empty catalog. renames maps instrument names to
+// exported names, as in ProviderOptions.MetricRenames.
+func NewCatalog(renames map[string]string) *Catalog {
+	return &Catalog{
+		renames: renames,
+		metrics: make(map[string]MetricInfo),
+	}
+}
+
+// View returns a view that records every instrument it sees without
+// changing its stream.
+func (c *Catalog) View() metric.View {
+	return func(inst metric.Instrument) (metric.Stream, bool) {
+		name := inst.Name
+		if to, ok := c.renames[name]; ok {
+			name = to
+		}
+		c.add(MetricInfo{
+			Name:        sanitizeMetricName(name),
+			Type:        prometheusType(inst.Kind),
+			Description: inst.Description,
+			Unit:        inst.Unit,
+			Scope:       inst.Scope.Name,
+		})
+		return metric.Stream{}, false
+	}
+}
+
+// AddCollectorMetric records a metric registered directly on the Prometheus
+// registry, such as a backfill metric. It is a no-op on a nil catalog.
+func (c *Catalog) AddCollectorMetric(name, help string, valueType promclient.ValueType) {
+	if c == nil {
+		return
+	}
+	typ := "gauge"
+	if valueType == promclient.CounterValue {
+		typ = "counter"
+	}
+	c.add(MetricInfo{Name: name, Type: typ, Description: help})
+}
+
+// Metrics returns the recorded metrics sorted by name. A nil catalog has
+// no metrics.
+func (c *Catalog) Metrics() []MetricInfo {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	metrics := make([]MetricInfo, 0, len(c.metrics))
+	for _, info := range c.metrics {
+		metrics = append(metrics, info)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+// add records a metric; views run once per reader, so repeats are ignored.
+func (c *Catalog) add(info MetricInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.metrics[info.Name]; !ok {
+		c.metrics[info.Name] = info
+	}
+}
+
+// prometheusType returns the Prometheus type an instrument kind is exported
+// as. Non-monotonic sums are exported as gauges.
+func prometheusType(kind metric.InstrumentKind) string {
+	switch kind {
+	case metric.InstrumentKindCounter, metric.InstrumentKindObservableCounter:
+		return "counter"
+	case metric.InstrumentKindHistogram:
+		return "histogram"
+	default:
+		return "gauge"
+	}
+}
+
+// sanitizeMetricName replaces characters that are invalid in Prometheus
+// metric names with underscores, as the exporter does.
+func sanitizeMetricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,88 @@
+// Package internal provides tests for the metrics catalog.
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestProvider_MetricsCatalog(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, ProviderOptions{
+		ServiceName:   "test-service",
+		MetricRenames: map[string]string{"orders.created": "orders_created_total"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	meter := provider.MeterProvider.Meter("test")
+	if _, err := meter.Int64Counter("orders.created", metric.WithDescription("Orders created")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := meter.Float64Histogram("checkout.duration", metric.WithDescription("Checkout duration"), metric.WithUnit("s")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := meter.Int64UpDownCounter("carts_open"); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.RegisterBackfillMetric(NewBackfillMetric("import_rows_total", "Imported rows", promclient.CounterValue, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]MetricInfo)
+	for _, info := range provider.MetricsCatalog() {
+		got[info.Name] = info
+	}
+
+	// No measurement was recorded: instruments are listed at creation
+	want := map[string]MetricInfo{
+		"orders_created_total": {Name: "orders_created_total", Type: "counter", Description: "Orders created", Scope: "test"},
+		"checkout_duration":    {Name: "checkout_duration", Type: "histogram", Description: "Checkout duration", Unit: "s", Scope: "test"},
+		"carts_open":           {Name: "carts_open", Type: "gauge", Scope: "test"},
+		"import_rows_total":    {Name: "import_rows_total", Type: "counter", Description: "Imported rows"},
+	}
+	for name, info := range want {
+		if got[name] != info {
+			t.Errorf("catalog[%s] = %+v, want %+v", name, got[name], info)
+		}
+	}
+	if _, ok := got["service_build_info"]; !ok {
+		t.Errorf("catalog missing service_build_info")
+	}
+	if _, ok := got["orders_created"]; ok {
+		t.Error("catalog should list renamed metrics under their exported name")
+	}
+}
+
+func TestProvider_GetCatalogHandler(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, ProviderOptions{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	w := httptest.NewRecorder()
+	provider.GetCatalogHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics/catalog", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body struct {
+		Metrics []MetricInfo `json:"metrics"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, w.Body.String())
+	}
+	if len(body.Metrics) == 0 {
+		t.Error("catalog should list the build info metric")
+	}
+}
@@ -3,8 +3,11 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	promclient "github.com/prometheus/client_golang/prometheus"
@@ -21,13 +24,22 @@ import (
 type ProviderOptions struct {
 	ServiceName    string
 	ServiceVersion string
+	GitCommit      string // Commit for service_build_info (empty = VCS revision from build info)
+	GoVersion      string // Go version for service_build_info (empty = runtime.Version())
 	ResourceAttrs  map[string]string
+	Registerer     promclient.Registerer // Existing registry to export into (nil = private registry)
+	MetricRenames  map[string]string     // Instrument name -> exported name
+	Readers        []metric.Reader       // Additional readers next to the Prometheus exporter
+	SkipGlobal     bool                  // Leave the global OpenTelemetry meter provider unchanged
 }
 
 // Provider manages OpenTelemetry metrics provider with Prometheus export.
 type Provider struct {
-	MeterProvider      *metric.MeterProvider
-	prometheusRegistry *promclient.Registry
+	MeterProvider *metric.MeterProvider
+	registerer    promclient.Registerer
+	gatherer      promclient.Gatherer // nil if the registerer cannot be gathered
+	catalog       *Catalog
+	cardinality   *CardinalityMonitor
 }
 
 // NewProvider creates a new metrics provider with Prometheus export.
@@ -53,21 +65,88 @@ func NewProvider(ctx context.Context, opts ProviderOptions) (*Provider, error) {
 		return nil, err
 	}
 
+	views, err := renameViews(opts.MetricRenames)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record every instrument for the metrics catalog
+	catalog := NewCatalog(opts.MetricRenames)
+	views = append(views, catalog.View())
+
 	// Create meter provider with Prometheus support
-	mp, promRegistry, err := createMeterProvider(ctx, res)
+	registerer, gatherer := resolveRegistry(opts.Registerer)
+	mp, err := createMeterProvider(ctx, res, registerer, opts.Readers, views...)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := RegisterBuildInfo(mp, opts.ServiceVersion, opts.GitCommit, opts.GoVersion); err != nil {
+		_ = mp.Shutdown(ctx)
+		return nil, fmt.Errorf("failed to register build info metric: %w", err)
+	}
+
 	// Set global meter provider
-	otel.SetMeterProvider(mp)
+	if !opts.SkipGlobal {
+		otel.SetMeterProvider(mp)
+	}
 
 	return &Provider{
-		MeterProvider:      mp,
-		prometheusRegistry: promRegistry,
+		MeterProvider: mp,
+		registerer:    registerer,
+		gatherer:      gatherer,
+		catalog:       catalog,
 	}, nil
 }
 
+// resolveRegistry returns the registerer metrics are exported into and the
+// gatherer the Prometheus handler serves from.
+//
+// Without a registerer a private registry is created. A supplied registerer is
+// also used as the gatherer when it is one (e.g. *prometheus.Registry); the
+// default registerer pairs with prometheus.DefaultGatherer.
+func resolveRegistry(registerer promclient.Registerer) (promclient.Registerer, promclient.Gatherer) {
+	if registerer == nil {
+		registry := promclient.NewRegistry()
+		return registry, registry
+	}
+	if registerer == promclient.DefaultRegisterer {
+		return registerer, promclient.DefaultGatherer
+	}
+	if gatherer, ok := registerer.(promclient.Gatherer); ok {
+		return registerer, gatherer
+	}
+	return registerer, nil
+}
+
+// renameViews builds one view per entry that exports the instrument named by
+// the key under the name given by the value. Matching is exact: wildcard
+// source names are rejected, since one target name cannot serve several
+// instruments.
+func renameViews(renames map[string]string) ([]metric.View, error) {
+	sources := make([]string, 0, len(renames))
+	for from := range renames {
+		sources = append(sources, from)
+	}
+	sort.Strings(sources)
+
+	views := make([]metric.View, 0, len(sources))
+	for _, from := range sources {
+		to := renames[from]
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid metric rename %q -> %q: names must not be empty", from, to)
+		}
+		if strings.ContainsAny(from, "*?") {
+			return nil, fmt.Errorf("invalid metric rename %q: wildcards are not supported", from)
+		}
+		views = append(views, metric.NewView(
+			metric.Instrument{Name: from},
+			metric.Stream{Name: to},
+		))
+	}
+	return views, nil
+}
+
 // createResource creates an OpenTelemetry resource with service attributes.
 func createResource(ctx context.Context, opts ProviderOptions) (*resource.Resource, error) {
 	res, err := resource.New(ctx,
@@ -95,37 +174,43 @@ func createResource(ctx context.Context, opts ProviderOptions) (*resource.Resour
 	return res, nil
 }
 
-// createMeterProvider creates a meter provider with Prometheus export only.
-// It returns the meter provider and a Prometheus registry for HTTP handler.
+// createMeterProvider creates a meter provider with Prometheus export.
+// The Prometheus exporter registers into registerer.
 //
 // Parameters:
 //   - ctx: context for initialization
 //   - res: OpenTelemetry resource with service attributes
+//   - registerer: Prometheus registerer for the exporter
+//   - readers: additional readers, e.g. manual readers in tests
+//   - views: views applied to every instrument, e.g. renames
 //
 // Returns:
 //   - *metric.MeterProvider: meter provider instance
-//   - *promclient.Registry: Prometheus registry for HTTP handler
 //   - error: creation error if any
-func createMeterProvider(ctx context.Context, res *resource.Resource) (*metric.MeterProvider, *promclient.Registry, error) {
-	// Create Prometheus registry and exporter
-	promRegistry := promclient.NewRegistry()
+func createMeterProvider(ctx context.Context, res *resource.Resource, registerer promclient.Registerer, readers []metric.Reader, views ...metric.View) (*metric.MeterProvider, error) {
+	// Create Prometheus exporter
 	promExporter, err := prometheus.New(
-		prometheus.WithRegisterer(promRegistry),
+		prometheus.WithRegisterer(registerer),
 		prometheus.WithoutUnits(),           // Prometheus prefers base units without suffix
 		prometheus.WithoutScopeInfo(),       // Remove otel_scope_* labels to reduce cardinality
 		prometheus.WithoutCounterSuffixes(), // Remove _total suffix duplication
 	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
 	}
 
 	// Create meter provider with Prometheus reader
-	mp := metric.NewMeterProvider(
+	opts := []metric.Option{
 		metric.WithResource(res),
 		metric.WithReader(promExporter),
-	)
+		metric.WithView(views...),
+	}
+	for _, reader := range readers {
+		opts = append(opts, metric.WithReader(reader))
+	}
+	mp := metric.NewMeterProvider(opts...)
 
-	return mp, promRegistry, nil
+	return mp, nil
 }
 
 // GetPrometheusHandler returns an HTTP handler for the Prometheus metrics endpoint.
@@ -137,19 +222,73 @@ func createMeterProvider(ctx context.Context, res *resource.Resource) (*metric.M
 // Concurrency:
 //   - Safe for concurrent use
 func (p *Provider) GetPrometheusHandler() http.Handler {
-	if p.prometheusRegistry == nil {
-		// Return a no-op handler if Prometheus is not initialized
+	if p.gatherer == nil {
+		// Return a no-op handler if Prometheus is not initialized or the
+		// metrics live in an external registerer that cannot be gathered
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			w.Write([]byte("# Prometheus metrics not available\n"))
 		})
 	}
 
-	return promhttp.HandlerFor(p.prometheusRegistry, promhttp.HandlerOpts{
+	return promhttp.HandlerFor(p.gatherer, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
 	})
 }
 
+// MetricsCatalog returns the metrics created on the provider sorted by name.
+//
+// Returns:
+//   - []MetricInfo: exported name, type, and description of each metric
+//
+// Concurrency:
+//   - Safe for concurrent use
+func (p *Provider) MetricsCatalog() []MetricInfo {
+	return p.catalog.Metrics()
+}
+
+// GetCatalogHandler returns an HTTP handler serving the metrics catalog as JSON.
+//
+// Returns:
+//   - http.Handler: handler writing {"metrics": [...]}
+//
+// Concurrency:
+//   - Safe for concurrent use
+func (p *Provider) GetCatalogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Metrics []MetricInfo `json:"metrics"`
+		}{Metrics: p.catalog.Metrics()})
+	})
+}
+
+// EnableCardinalityMonitor starts a background sampler that tracks series count
+// per metric and logs a warning when a metric exceeds the configured threshold.
+// The sampler stops when ctx is cancelled or the provider is shut down.
+//
+// Parameters:
+//   - ctx: context controlling the sampler lifetime
+//   - opts: monitor configuration
+//
+// Returns:
+//   - *CardinalityMonitor: running monitor instance
+//   - error: registration error if any
+func (p *Provider) EnableCardinalityMonitor(ctx context.Context, opts CardinalityOptions) (*CardinalityMonitor, error) {
+	if p.cardinality != nil {
+		return nil, fmt.Errorf("cardinality monitor already enabled")
+	}
+
+	monitor, err := NewCardinalityMonitor(p.MeterProvider, p.gatherer, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	p.cardinality = monitor
+	monitor.Start(ctx)
+	return monitor, nil
+}
+
 // Shutdown gracefully shuts down the metrics provider.
 //
 // Parameters:
@@ -162,6 +301,10 @@ func (p *Provider) GetPrometheusHandler() http.Handler {
 //   - Safe to call from multiple goroutines
 //   - Blocks until shutdown completes or timeout
 func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.cardinality != nil {
+		p.cardinality.Stop()
+	}
+
 	// Create a timeout context for shutdown
 	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -6,9 +6,12 @@ import (
 	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
 )
 
 func TestNewProvider_Success(t *testing.T) {
@@ -105,8 +108,8 @@ func TestProvider_GetPrometheusHandler(t *testing.T) {
 
 func TestProvider_GetPrometheusHandler_NilRegistry(t *testing.T) {
 	provider := &Provider{
-		MeterProvider:      nil,
-		prometheusRegistry: nil,
+		MeterProvider: nil,
+		gatherer:      nil,
 	}
 
 	handler := provider.GetPrometheusHandler()
@@ -373,6 +376,26 @@ func TestProvider_GetPrometheusHandler_MetricsFormat(t *testing.T) {
 	}
 }
 
+func TestNewProvider_BuildInfo(t *testing.T) {
+	provider, err := NewProvider(context.Background(), ProviderOptions{
+		ServiceName:    "test-service",
+		ServiceVersion: "1.2.3",
+		GitCommit:      "abc1234",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	w := httptest.NewRecorder()
+	provider.GetPrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `service_build_info{git_commit="abc1234",go_version="` + runtime.Version() + `",version="1.2.3"} 1`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("Metrics missing %s\n%s", want, w.Body.String())
+	}
+}
+
 func TestProvider_Shutdown_WithTimeout(t *testing.T) {
 	opts := ProviderOptions{
 		ServiceName:    "test-service",
@@ -496,3 +519,38 @@ func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
 
+func TestNewProvider_WithNonGatheringRegisterer(t *testing.T) {
+	ctx := context.Background()
+	registry := promclient.NewRegistry()
+	wrapped := promclient.WrapRegistererWith(promclient.Labels{"team": "payments"}, registry)
+
+	provider, err := NewProvider(ctx, ProviderOptions{ServiceName: "test-service", Registerer: wrapped})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	counter, _ := provider.MeterProvider.Meter("test").Int64Counter("wrapped_events")
+	counter.Add(ctx, 1)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	found := false
+	for _, mf := range families {
+		if mf.GetName() == "wrapped_events" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("wrapped_events not exported into the underlying registry")
+	}
+
+	// The wrapper cannot be gathered, so the provider's own handler is unavailable
+	w := httptest.NewRecorder()
+	provider.GetPrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GetPrometheusHandler() status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
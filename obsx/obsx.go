@@ -23,7 +23,10 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/obsx/internal"
 	api "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
@@ -34,6 +37,36 @@ type Options struct {
 	ServiceName    string            // Service name for metrics
 	ServiceVersion string            // Service version
 	ResourceAttrs  map[string]string // Additional resource attributes
+
+	// GitCommit and GoVersion label the service_build_info gauge, which is
+	// always exported with value 1 next to ServiceVersion. An empty GitCommit
+	// is read from the VCS revision stamped into the binary by the Go
+	// toolchain; an empty GoVersion defaults to runtime.Version().
+	GitCommit string
+	GoVersion string
+
+	// Registerer is an existing Prometheus registry the OTel exporter registers
+	// into, so obsx metrics and metrics registered directly with client_golang
+	// are served from one scrape. When it also implements prometheus.Gatherer
+	// (e.g. *prometheus.Registry), PrometheusHandler serves the whole registry;
+	// otherwise serve the registry with your own handler. Nil creates a private registry.
+	Registerer prometheus.Registerer
+
+	// MetricRenames exports instruments under different names, keyed by the
+	// instrument name as created (e.g. "rpc_requests_total") with the name
+	// dashboards expect as value (e.g. "http_requests_total"). The source name
+	// is no longer exported. Matching is exact; wildcards are rejected.
+	MetricRenames map[string]string
+
+	// Readers receive the same metrics as the Prometheus exporter, e.g. a
+	// testingx.MetricsRecorder reader to assert on metrics in tests.
+	Readers []metric.Reader
+
+	// SkipGlobal leaves the global OpenTelemetry meter provider unchanged.
+	// Every provider owns its registry, readers and meters, so several can
+	// run in one process, e.g. one per hosted service; without SkipGlobal
+	// the provider created last becomes the global one.
+	SkipGlobal bool
 }
 
 // Provider manages OpenTelemetry metrics provider with Prometheus export.
@@ -67,6 +100,53 @@ func (p *Provider) PrometheusHandler() http.Handler {
 	return p.impl.GetPrometheusHandler()
 }
 
+// MetricInfo describes a metric exported on the Prometheus endpoint.
+type MetricInfo struct {
+	Name        string `json:"name"`            // Exported Prometheus name (e.g., "rpc_requests_total")
+	Type        string `json:"type"`            // Prometheus type: counter, gauge, or histogram
+	Description string `json:"description"`     // Help text
+	Unit        string `json:"unit,omitempty"`  // OpenTelemetry unit (e.g., "s", "By")
+	Scope       string `json:"scope,omitempty"` // Meter name that created the metric
+}
+
+// MetricsCatalog returns the metrics created on the provider, including
+// custom instruments from Meter and backfill metrics. Instruments are listed
+// as soon as they are created, before their first measurement, so tools can
+// discover the available metrics instead of hardcoding them.
+//
+// Returns:
+//   - []MetricInfo: metrics sorted by exported name
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Performance:
+//   - O(n log n) in the number of metrics; no registry gathering
+func (p *Provider) MetricsCatalog() []MetricInfo {
+	catalog := p.impl.MetricsCatalog()
+	metrics := make([]MetricInfo, len(catalog))
+	for i, info := range catalog {
+		metrics[i] = MetricInfo(info)
+	}
+	return metrics
+}
+
+// MetricsCatalogHandler returns an HTTP handler serving MetricsCatalog as
+// JSON: {"metrics": [{"name": ..., "type": ..., "description": ...}]}.
+//
+// Returns:
+//   - http.Handler: handler that serves the catalog at any path
+//
+// Concurrency:
+//   - Safe for concurrent use
+//
+// Example:
+//
+//	mux.Handle("/metrics/catalog", provider.MetricsCatalogHandler())
+func (p *Provider) MetricsCatalogHandler() http.Handler {
+	return p.impl.GetCatalogHandler()
+}
+
 // Meter returns an OpenTelemetry Meter for creating custom metrics.
 // The meter name should be the service or component name.
 //
@@ -108,7 +188,13 @@ func NewProvider(ctx context.Context, opts Options) (*Provider, error) {
 	impl, err := internal.NewProvider(ctx, internal.ProviderOptions{
 		ServiceName:    opts.ServiceName,
 		ServiceVersion: opts.ServiceVersion,
+		GitCommit:      opts.GitCommit,
+		GoVersion:      opts.GoVersion,
 		ResourceAttrs:  opts.ResourceAttrs,
+		Registerer:     opts.Registerer,
+		MetricRenames:  opts.MetricRenames,
+		Readers:        opts.Readers,
+		SkipGlobal:     opts.SkipGlobal,
 	})
 	if err != nil {
 		return nil, err
@@ -250,3 +336,162 @@ func (p *Provider) RegisterDBMetrics(name string, db *sql.DB) error {
 func (p *Provider) RegisterGORMMetrics(name string, gormDB interface{ DB() (*sql.DB, error) }) error {
 	return internal.RegisterGORMMetrics(name, gormDB, p.impl.MeterProvider)
 }
+
+// CardinalityOptions configures the metric cardinality monitor.
+type CardinalityOptions struct {
+	Logger    log.Logger    // Logger receiving threshold warnings (required)
+	Threshold int           // Series count per metric that triggers a warning (default: 1000)
+	Interval  time.Duration // Sampling interval (default: 30s)
+}
+
+// EnableCardinalityMonitor starts a background sampler that counts series per metric
+// and logs a warning when a metric exceeds the configured threshold.
+// The sampled counts are exposed as the obsx_metric_series_count{metric} gauge.
+//
+// Parameters:
+//   - ctx: context controlling the sampler lifetime
+//   - opts: monitor configuration
+//
+// Returns:
+//   - error: registration error if any, or if the monitor is already enabled
+//
+// Concurrency:
+//   - Must be called at most once per provider
+//
+// Performance:
+//   - Each sample gathers the full Prometheus registry; keep the interval coarse
+//
+// Example:
+//
+//	err := provider.EnableCardinalityMonitor(ctx, obsx.CardinalityOptions{
+//	    Logger:    logger,
+//	    Threshold: 500,
+//	})
+func (p *Provider) EnableCardinalityMonitor(ctx context.Context, opts CardinalityOptions) error {
+	_, err := p.impl.EnableCardinalityMonitor(ctx, internal.CardinalityOptions{
+		Logger:    opts.Logger,
+		Threshold: opts.Threshold,
+		Interval:  opts.Interval,
+	})
+	return err
+}
+
+// RecordOption configures a single backfill observation.
+type RecordOption func(*recordConfig)
+
+type recordConfig struct {
+	labelValues []string
+	timestamp   time.Time
+}
+
+// WithLabelValues sets the label values of an observation, in the order the
+// label names were declared on the instrument.
+func WithLabelValues(values ...string) RecordOption {
+	return func(c *recordConfig) {
+		c.labelValues = values
+	}
+}
+
+// WithObservationTime records the observation at t instead of scrape time.
+// The timestamp is carried through the Prometheus exposition format.
+func WithObservationTime(t time.Time) RecordOption {
+	return func(c *recordConfig) {
+		c.timestamp = t
+	}
+}
+
+func applyRecordOptions(opts []RecordOption) recordConfig {
+	var c recordConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// BackfillGauge is a gauge that accepts explicit observation times.
+// It is exported directly through the Prometheus registry, bypassing the
+// OpenTelemetry SDK, which always stamps observations at collection time.
+type BackfillGauge struct {
+	impl *internal.BackfillMetric
+}
+
+// Set records the gauge value for the label set given by WithLabelValues.
+//
+// Parameters:
+//   - value: gauge value
+//   - opts: observation options (WithLabelValues, WithObservationTime)
+//
+// Returns:
+//   - error: if the number of label values does not match the instrument
+//
+// Concurrency:
+//   - Safe for concurrent use
+func (g *BackfillGauge) Set(value float64, opts ...RecordOption) error {
+	c := applyRecordOptions(opts)
+	return g.impl.Set(value, c.labelValues, c.timestamp)
+}
+
+// BackfillCounter is a counter that accepts explicit observation times.
+// Like BackfillGauge it is exported directly through the Prometheus registry.
+type BackfillCounter struct {
+	impl *internal.BackfillMetric
+}
+
+// Add increases the counter for the label set given by WithLabelValues.
+//
+// Parameters:
+//   - delta: non-negative increment
+//   - opts: observation options (WithLabelValues, WithObservationTime)
+//
+// Returns:
+//   - error: if delta is negative or the label values do not match the instrument
+//
+// Concurrency:
+//   - Safe for concurrent use
+func (c *BackfillCounter) Add(delta float64, opts ...RecordOption) error {
+	cfg := applyRecordOptions(opts)
+	return c.impl.Add(delta, cfg.labelValues, cfg.timestamp)
+}
+
+// NewBackfillGauge creates and registers a gauge for recording historical data
+// with explicit observation times, e.g. from batch importers.
+//
+// Parameters:
+//   - name: Prometheus metric name
+//   - help: metric description
+//   - labelNames: label names; values are supplied per observation
+//
+// Returns:
+//   - *BackfillGauge: registered gauge
+//   - error: if the name is invalid or already registered
+//
+// Example:
+//
+//	gauge, _ := provider.NewBackfillGauge("import_orders_open", "Open orders", "region")
+//	gauge.Set(42, obsx.WithLabelValues("eu"), obsx.WithObservationTime(row.RecordedAt))
+func (p *Provider) NewBackfillGauge(name, help string, labelNames ...string) (*BackfillGauge, error) {
+	m := internal.NewBackfillMetric(name, help, prometheus.GaugeValue, labelNames)
+	if err := p.impl.RegisterBackfillMetric(m); err != nil {
+		return nil, err
+	}
+	return &BackfillGauge{impl: m}, nil
+}
+
+// NewBackfillCounter creates and registers a counter for recording historical data
+// with explicit observation times.
+//
+// Parameters:
+//   - name: Prometheus metric name
+//   - help: metric description
+//   - labelNames: label names; values are supplied per observation
+//
+// Returns:
+//   - *BackfillCounter: registered counter
+//   - error: if the name is invalid or already registered
+func (p *Provider) NewBackfillCounter(name, help string, labelNames ...string) (*BackfillCounter, error) {
+	m := internal.NewBackfillMetric(name, help, prometheus.CounterValue, labelNames)
+	if err := p.impl.RegisterBackfillMetric(m); err != nil {
+		return nil, err
+	}
+	return &BackfillCounter{impl: m}, nil
+}
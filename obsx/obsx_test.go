@@ -3,8 +3,16 @@ package obsx
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -125,3 +133,262 @@ func TestProviderWithRuntimeMetrics(t *testing.T) {
 		t.Errorf("Shutdown() error = %v", err)
 	}
 }
+
+func TestProvider_NewBackfillGauge(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	gauge, err := provider.NewBackfillGauge("import_queue_depth", "Queue depth", "queue")
+	if err != nil {
+		t.Fatalf("NewBackfillGauge() error = %v", err)
+	}
+	if err := gauge.Set(3, WithLabelValues("orders"), WithObservationTime(time.Unix(1700000000, 0))); err != nil {
+		t.Errorf("Set() error = %v", err)
+	}
+	if err := gauge.Set(3); err == nil {
+		t.Error("Set() without label values should fail")
+	}
+
+	counter, err := provider.NewBackfillCounter("import_rows_total", "Imported rows")
+	if err != nil {
+		t.Fatalf("NewBackfillCounter() error = %v", err)
+	}
+	if err := counter.Add(1, WithObservationTime(time.Unix(1700000000, 0))); err != nil {
+		t.Errorf("Add() error = %v", err)
+	}
+
+	if _, err := provider.NewBackfillGauge("import_queue_depth", "Queue depth", "queue"); err == nil {
+		t.Error("NewBackfillGauge() should reject duplicate names")
+	}
+}
+
+func TestNewProvider_WithRegisterer(t *testing.T) {
+	ctx := context.Background()
+
+	registry := prometheus.NewRegistry()
+	legacy := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "legacy_jobs_total",
+		Help: "Jobs processed by the legacy pipeline",
+	})
+	registry.MustRegister(legacy)
+	legacy.Add(3)
+
+	provider, err := NewProvider(ctx, Options{ServiceName: "test-service", Registerer: registry})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	counter, err := provider.Meter("test").Int64Counter("otel_orders")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	counter.Add(ctx, 2)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	names := make(map[string]bool)
+	for _, mf := range families {
+		names[mf.GetName()] = true
+	}
+	for _, name := range []string{"legacy_jobs_total", "otel_orders"} {
+		if !names[name] {
+			t.Errorf("custom registry is missing %s", name)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "legacy_jobs_total 3") || !strings.Contains(body, "otel_orders") {
+		t.Errorf("PrometheusHandler() should serve both metric sets, got:\n%s", body)
+	}
+}
+
+func TestNewProvider_MultipleProviders(t *testing.T) {
+	ctx := context.Background()
+	global := otel.GetMeterProvider()
+
+	mux := http.NewServeMux()
+	providers := make(map[string]*Provider)
+	for _, tenant := range []string{"billing", "search"} {
+		provider, err := NewProvider(ctx, Options{
+			ServiceName:   tenant + "-service",
+			ResourceAttrs: map[string]string{"tenant": tenant},
+			SkipGlobal:    true,
+		})
+		if err != nil {
+			t.Fatalf("NewProvider(%s) error = %v", tenant, err)
+		}
+		defer provider.Shutdown(ctx)
+		if err := provider.EnableRuntimeMetrics(ctx); err != nil {
+			t.Fatalf("EnableRuntimeMetrics(%s) error = %v", tenant, err)
+		}
+
+		counter, err := provider.Meter("test").Int64Counter("jobs_processed")
+		if err != nil {
+			t.Fatalf("Int64Counter(%s) error = %v", tenant, err)
+		}
+		counter.Add(ctx, int64(len(providers)+1))
+
+		providers[tenant] = provider
+		mux.Handle("/"+tenant+"/metrics", provider.PrometheusHandler())
+	}
+
+	if otel.GetMeterProvider() != global {
+		t.Error("SkipGlobal providers should not replace the global meter provider")
+	}
+
+	scrape := func(path string) string {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d", path, w.Code)
+		}
+		return w.Body.String()
+	}
+
+	for tenant, want := range map[string]struct{ self, other, value string }{
+		"billing": {self: "billing", other: "search", value: "jobs_processed 1"},
+		"search":  {self: "search", other: "billing", value: "jobs_processed 2"},
+	} {
+		body := scrape("/" + tenant + "/metrics")
+		if !strings.Contains(body, want.value) {
+			t.Errorf("%s scrape should contain %q, got:\n%s", tenant, want.value, body)
+		}
+		if !strings.Contains(body, `tenant="`+want.self+`"`) || !strings.Contains(body, want.self+"-service") {
+			t.Errorf("%s scrape should carry its own resource attributes", tenant)
+		}
+		if strings.Contains(body, want.other) {
+			t.Errorf("%s scrape leaks metrics of %s", tenant, want.other)
+		}
+		if strings.Count(body, "# TYPE service_build_info ") != 1 {
+			t.Errorf("%s scrape should export service_build_info once", tenant)
+		}
+	}
+}
+
+func TestNewProvider_WithMetricRenames(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewProvider(ctx, Options{
+		ServiceName:   "test-service",
+		MetricRenames: map[string]string{"rpc_requests_total": "http_requests_total"},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	counter, err := provider.Meter("test").Int64Counter("rpc_requests_total")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	counter.Add(ctx, 5)
+
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	if !strings.Contains(body, "http_requests_total 5") {
+		t.Errorf("renamed metric http_requests_total missing, got:\n%s", body)
+	}
+	if strings.Contains(body, "rpc_requests_total") {
+		t.Errorf("source metric rpc_requests_total should not be exported, got:\n%s", body)
+	}
+}
+
+func TestNewProvider_WithReaders(t *testing.T) {
+	ctx := context.Background()
+	reader := metric.NewManualReader()
+
+	provider, err := NewProvider(ctx, Options{
+		ServiceName: "test-service",
+		Readers:     []metric.Reader{reader},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	counter, err := provider.Meter("test").Int64Counter("jobs_total")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	counter.Add(ctx, 3)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	found := false
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "jobs_total" {
+				found = len(sum.DataPoints) == 1 && sum.DataPoints[0].Value == 3
+			}
+		}
+	}
+	if !found {
+		t.Errorf("reader did not collect jobs_total = 3, got %+v", rm.ScopeMetrics)
+	}
+
+	// The Prometheus exporter still receives the metric.
+	w := httptest.NewRecorder()
+	provider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "jobs_total 3") {
+		t.Errorf("prometheus export missing jobs_total, got:\n%s", w.Body.String())
+	}
+}
+
+func TestNewProvider_InvalidMetricRenames(t *testing.T) {
+	ctx := context.Background()
+
+	for _, renames := range []map[string]string{
+		{"rpc_*": "http_requests_total"},
+		{"rpc_requests_total": ""},
+	} {
+		if _, err := NewProvider(ctx, Options{ServiceName: "test-service", MetricRenames: renames}); err == nil {
+			t.Errorf("NewProvider() with renames %v should fail", renames)
+		}
+	}
+}
+
+func TestProvider_MetricsCatalog(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewProvider(ctx, Options{ServiceName: "test-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	if _, err := provider.Meter("payments").Int64Counter("payments_processed_total"); err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	if _, err := provider.NewBackfillGauge("import_orders_open", "Open orders"); err != nil {
+		t.Fatalf("NewBackfillGauge() error = %v", err)
+	}
+
+	found := make(map[string]string)
+	for _, info := range provider.MetricsCatalog() {
+		found[info.Name] = info.Type
+	}
+	for name, typ := range map[string]string{"payments_processed_total": "counter", "import_orders_open": "gauge"} {
+		if found[name] != typ {
+			t.Errorf("catalog type of %s = %q, want %q", name, found[name], typ)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	provider.MetricsCatalogHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics/catalog", nil))
+	if !strings.Contains(w.Body.String(), `"name":"payments_processed_total"`) {
+		t.Errorf("catalog handler missing custom metric, got:\n%s", w.Body.String())
+	}
+}
@@ -0,0 +1,109 @@
+// Package internal contains the runtime implementation.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// drainLogInterval is how often drain progress is logged during shutdown.
+var drainLogInterval = time.Second
+
+// serverTracker tracks the open connections and in-flight requests of an
+// http.Server and owns the base context of its requests, so that handlers still
+// running at the shutdown deadline can be cancelled.
+type serverTracker struct {
+	name     string
+	server   *http.Server
+	inFlight atomic.Int64
+	conns    atomic.Int64
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// trackServer installs connection and request tracking on server.
+// It must be called before the server starts serving.
+func trackServer(name string, server *http.Server) *serverTracker {
+	t := &serverTracker{name: name, server: server}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+
+	handler := server.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.inFlight.Add(1)
+		defer t.inFlight.Add(-1)
+		handler.ServeHTTP(w, r)
+	})
+
+	baseContext := server.BaseContext
+	server.BaseContext = func(ln net.Listener) context.Context {
+		if baseContext == nil {
+			return t.ctx
+		}
+		ctx, cancel := context.WithCancel(baseContext(ln))
+		context.AfterFunc(t.ctx, cancel)
+		return ctx
+	}
+
+	connState := server.ConnState
+	server.ConnState = func(conn net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			t.conns.Add(1)
+		case http.StateClosed, http.StateHijacked:
+			t.conns.Add(-1)
+		}
+		if connState != nil {
+			connState(conn, state)
+		}
+	}
+
+	return t
+}
+
+// drain stops the server from accepting connections (and, for HTTP/2, new
+// streams) and waits for in-flight requests until ctx expires, logging
+// progress meanwhile. At the deadline the remaining request contexts are
+// cancelled and their connections closed.
+func (t *serverTracker) drain(ctx context.Context, logger log.Logger) error {
+	defer t.cancel()
+
+	logger.Info("draining server", t.progress()...)
+
+	done := make(chan error, 1)
+	go func() { done <- t.server.Shutdown(ctx) }()
+
+	ticker := time.NewTicker(drainLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			if err == nil {
+				return nil
+			}
+			remaining := t.inFlight.Load()
+			t.cancel()
+			t.server.Close()
+			return fmt.Errorf("%d requests still in flight: %w", remaining, err)
+		case <-ticker.C:
+			logger.Info("draining server", t.progress()...)
+		}
+	}
+}
+
+func (t *serverTracker) progress() []any {
+	return []any{
+		log.Str("server", t.name),
+		log.Int("in_flight", int(t.inFlight.Load())),
+		log.Int("connections", int(t.conns.Load())),
+	}
+}
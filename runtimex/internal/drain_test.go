@@ -0,0 +1,187 @@
+// Package internal provides tests for runtimex server draining.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// drainLogger counts drain progress log lines.
+type drainLogger struct {
+	mockLogger
+	progress atomic.Int32
+}
+
+func (l *drainLogger) Info(msg string, kv ...interface{}) {
+	if msg == "draining server" {
+		l.progress.Add(1)
+	}
+}
+
+// serveTracked starts server on a loopback port with tracking installed.
+func serveTracked(t *testing.T, server *http.Server) (*serverTracker, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	tracker := trackServer("http", server)
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Close() })
+	return tracker, "http://" + ln.Addr().String()
+}
+
+func waitInFlight(t *testing.T, tracker *serverTracker, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for tracker.inFlight.Load() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("in-flight requests = %d, want %d", tracker.inFlight.Load(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServerTracker_DrainWaitsForInFlight(t *testing.T) {
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})}
+	tracker, url := serveTracked(t, server)
+
+	result := make(chan error, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		result <- err
+	}()
+	waitInFlight(t, tracker, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := tracker.drain(ctx, &mockLogger{}); err != nil {
+		t.Fatalf("drain() error = %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("in-flight request failed during drain: %v", err)
+	}
+}
+
+func TestServerTracker_DrainDeadlineCancelsRequests(t *testing.T) {
+	old := drainLogInterval
+	drainLogInterval = 20 * time.Millisecond
+	defer func() { drainLogInterval = old }()
+
+	cancelled := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A long-lived stream that only stops when its context ends
+		<-r.Context().Done()
+		close(cancelled)
+	})}
+	tracker, url := serveTracked(t, server)
+
+	go func() {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	waitInFlight(t, tracker, 1)
+
+	logger := &drainLogger{}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := tracker.drain(ctx, logger)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("drain() took %v, want bounded by the deadline", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("drain() error = %v, want DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "1 requests still in flight") {
+		t.Errorf("drain() error = %q, want in-flight count", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("request context was not cancelled at the drain deadline")
+	}
+	if got := logger.progress.Load(); got < 2 {
+		t.Errorf("drain progress logged %d times, want at least 2", got)
+	}
+}
+
+func TestServerTracker_H2CRefusesNewStreams(t *testing.T) {
+	release := make(chan struct{})
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		w.Write([]byte(r.Proto))
+	})}
+	server.Protocols = new(http.Protocols)
+	server.Protocols.SetHTTP1(true)
+	server.Protocols.SetUnencryptedHTTP2(true)
+	tracker, url := serveTracked(t, server)
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport, Timeout: time.Second}
+
+	slow := make(chan *http.Response, 1)
+	go func() {
+		resp, err := client.Get(url + "/slow")
+		if err != nil {
+			t.Errorf("in-flight stream failed: %v", err)
+			close(slow)
+			return
+		}
+		slow <- resp
+	}()
+	waitInFlight(t, tracker, 1)
+
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		drained <- tracker.drain(ctx, &mockLogger{})
+	}()
+
+	// Once draining has begun, new streams must be refused
+	deadline := time.Now().Add(time.Second)
+	for {
+		resp, err := client.Get(url + "/fast")
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("new streams were still accepted during drain")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(release)
+	resp, ok := <-slow
+	if !ok {
+		return
+	}
+	resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Errorf("in-flight stream used %s, want HTTP/2", resp.Proto)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("drain() error = %v", err)
+	}
+}
@@ -3,7 +3,13 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"sync"
+	"time"
+
+	"go.eggybyte.com/egg/core/health"
 )
 
 // HealthChecker defines the interface for health checks.
@@ -44,6 +50,18 @@ func CheckHealth(ctx context.Context) error {
 	return nil
 }
 
+// HasHealthChecker reports whether a registered health checker is named name.
+func HasHealthChecker(name string) bool {
+	healthCheckersMu.RLock()
+	defer healthCheckersMu.RUnlock()
+	for _, checker := range healthCheckers {
+		if checker.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 // ClearHealthCheckers clears all registered health checkers (intended for testing).
 func ClearHealthCheckers() {
 	healthCheckersMu.Lock()
@@ -51,3 +69,82 @@ func ClearHealthCheckers() {
 	healthCheckers = nil
 }
 
+// HealthCheck is a named dependency check reported by the health endpoint.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// RunHealthChecks runs checks and all registered health checkers concurrently
+// and reports each one by name, bounding each by timeout (health.DefaultTimeout
+// if not positive). Unlike CheckHealth, it does not stop at the first failure.
+//
+// Checks keep their names. A registered checker whose name is already taken,
+// by a check or an earlier checker, is reported as "<name>_<n>" with the
+// smallest free n from 2, so no result is silently dropped.
+func RunHealthChecks(ctx context.Context, checks []HealthCheck, timeout time.Duration) health.Report {
+	healthCheckersMu.RLock()
+	all := make(map[string]func(ctx context.Context) error, len(checks)+len(healthCheckers))
+	for _, check := range checks {
+		all[check.Name] = check.Check
+	}
+	for _, checker := range healthCheckers {
+		all[uniqueCheckName(all, checker.Name())] = checker.Check
+	}
+	healthCheckersMu.RUnlock()
+
+	return health.Run(ctx, all, timeout)
+}
+
+// uniqueCheckName returns name, or name with the first free numeric suffix
+// if checks already has it.
+func uniqueCheckName(checks map[string]func(ctx context.Context) error, name string) string {
+	if _, taken := checks[name]; !taken {
+		return name
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d", name, n)
+		if _, taken := checks[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// NewHealthHandler serves the health endpoint.
+//
+// /health/live reports the process is up and never runs checks. /health/ready
+// (and any other path, for compatibility) runs every check and responds 200
+// when all pass or 503 otherwise, with per-check status in the JSON body.
+// Each check is bounded by timeout.
+func NewHealthHandler(checks []HealthCheck, timeout time.Duration) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/health/live", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeHealthJSON(w, http.StatusOK, health.Report{Status: health.StatusAlive})
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report := RunHealthChecks(r.Context(), checks, timeout)
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthJSON(w, status, report)
+	})
+
+	return mux
+}
+
+func writeHealthJSON(w http.ResponseWriter, status int, report health.Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -3,11 +3,14 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"go.eggybyte.com/egg/core/health"
 	"go.eggybyte.com/egg/core/log"
 )
 
@@ -165,6 +168,112 @@ func TestClearHealthCheckers(t *testing.T) {
 	}
 }
 
+func TestNewHealthHandler_ReportsPerCheckStatus(t *testing.T) {
+	ClearHealthCheckers()
+	defer ClearHealthCheckers()
+	RegisterHealthChecker(&mockHealthChecker{name: "registered"})
+
+	var dbErr error
+	handler := NewHealthHandler([]HealthCheck{
+		{Name: "db", Check: func(context.Context) error { return dbErr }},
+		{Name: "cache", Check: func(context.Context) error { return nil }},
+	}, time.Second)
+
+	get := func(path string) (int, health.Report) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report health.Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("GET %s body %q is not JSON: %v", path, rec.Body.String(), err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := get("/health/ready")
+	if code != http.StatusOK || report.Status != "ready" {
+		t.Fatalf("GET /health/ready = %d %+v, want 200 ready", code, report)
+	}
+	for _, name := range []string{"db", "cache", "registered"} {
+		if report.Checks[name].Status != "healthy" {
+			t.Errorf("check %s = %+v, want healthy", name, report.Checks[name])
+		}
+	}
+
+	dbErr = errors.New("connection refused")
+	code, report = get("/health/ready")
+	if code != http.StatusServiceUnavailable || report.Status != "not_ready" {
+		t.Fatalf("GET /health/ready = %d %+v, want 503 not_ready", code, report)
+	}
+	if got := report.Checks["db"]; got.Status != "unhealthy" || got.Error != "connection refused" {
+		t.Errorf("check db = %+v, want unhealthy with error", got)
+	}
+	if got := report.Checks["cache"]; got.Status != "healthy" {
+		t.Errorf("check cache = %+v, want healthy (checks must not fail fast)", got)
+	}
+
+	if code, _ := get("/health"); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /health = %d, want 503", code)
+	}
+
+	code, report = get("/health/live")
+	if code != http.StatusOK || report.Status != "alive" || len(report.Checks) != 0 {
+		t.Errorf("GET /health/live = %d %+v, want 200 alive without checks", code, report)
+	}
+}
+
+func TestRunHealthChecks_Timeout(t *testing.T) {
+	ClearHealthCheckers()
+	defer ClearHealthCheckers()
+	RegisterHealthChecker(&mockHealthChecker{name: "slow", delay: time.Minute})
+
+	start := time.Now()
+	report := RunHealthChecks(context.Background(), []HealthCheck{
+		{Name: "fast", Check: func(context.Context) error { return nil }},
+	}, 20*time.Millisecond)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("RunHealthChecks took %v, want it bounded by the timeout", elapsed)
+	}
+	if report.Ready() {
+		t.Fatal("expected not ready with a timed out check")
+	}
+	if got := report.Checks["slow"]; got.Status != "unhealthy" || got.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("check slow = %+v, want unhealthy with deadline exceeded", got)
+	}
+	if got := report.Checks["fast"]; got.Status != "healthy" {
+		t.Errorf("check fast = %+v, want healthy", got)
+	}
+}
+
+func TestRunHealthChecks_DuplicateNames(t *testing.T) {
+	ClearHealthCheckers()
+	defer ClearHealthCheckers()
+	RegisterHealthChecker(&mockHealthChecker{name: "db", shouldFail: true, failErr: errors.New("global down")})
+	RegisterHealthChecker(&mockHealthChecker{name: "db"})
+
+	report := RunHealthChecks(context.Background(), []HealthCheck{
+		{Name: "db", Check: func(context.Context) error { return nil }},
+		{Name: "db_2", Check: func(context.Context) error { return nil }},
+	}, time.Second)
+
+	if len(report.Checks) != 4 {
+		t.Fatalf("Checks = %v, want all four results", report.Checks)
+	}
+	if got := report.Checks["db"]; got.Status != "healthy" {
+		t.Errorf("check db = %+v, want the configured check", got)
+	}
+	if got := report.Checks["db_3"]; got.Status != "unhealthy" || got.Error != "global down" {
+		t.Errorf("check db_3 = %+v, want the first registered checker", got)
+	}
+	if _, ok := report.Checks["db_4"]; !ok {
+		t.Errorf("Checks = %v, want the second registered checker as db_4", report.Checks)
+	}
+	if report.Ready() {
+		t.Error("a failing registered checker must not be hidden by a duplicate name")
+	}
+}
+
 // mockHealthChecker is a test implementation of HealthChecker.
 type mockHealthChecker struct {
 	name       string
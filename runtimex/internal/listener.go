@@ -0,0 +1,105 @@
+// Package internal contains the runtime implementation.
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// Listener is a raw TCP or gRPC server managed by the runtime.
+type Listener struct {
+	Name     string
+	Listener net.Listener // Pre-bound listener (takes precedence over Addr)
+	Addr     string       // Address to listen on when Listener is nil
+	Serve    func(net.Listener) error
+	Stop     func(ctx context.Context) error // Graceful stop; nil closes the listener
+
+	done chan struct{}
+}
+
+// AddListener registers a listener to be served on Start and stopped on Stop.
+func (r *Runtime) AddListener(l *Listener) {
+	r.listeners = append(r.listeners, l)
+}
+
+// startListeners binds and serves all listeners.
+// Binding happens synchronously so address errors fail Start; on failure the
+// listeners bound so far are closed.
+func (r *Runtime) startListeners() error {
+	for i, l := range r.listeners {
+		if l.Listener == nil {
+			ln, err := net.Listen("tcp", l.Addr)
+			if err != nil {
+				for _, bound := range r.listeners[:i] {
+					bound.Listener.Close()
+				}
+				return fmt.Errorf("listener %s: %w", l.Name, err)
+			}
+			l.Listener = ln
+		}
+	}
+
+	for _, l := range r.listeners {
+		l.done = make(chan struct{})
+		go func(l *Listener) {
+			defer close(l.done)
+			r.logger.Info("starting listener", log.Str("name", l.Name), log.Str("addr", l.Listener.Addr().String()))
+			if err := l.Serve(l.Listener); err != nil && !isClosedError(err) {
+				r.logger.Error(err, "listener failed", log.Str("name", l.Name))
+			}
+		}(l)
+	}
+	return nil
+}
+
+// stopListeners stops all listeners concurrently and waits for their Serve
+// functions to return or ctx to expire. Returns the joined drain errors.
+func (r *Runtime) stopListeners(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(r.listeners))
+
+	for i, l := range r.listeners {
+		if l.done == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, l *Listener) {
+			defer wg.Done()
+			r.logger.Info("stopping listener", log.Str("name", l.Name))
+
+			var err error
+			if l.Stop != nil {
+				err = l.Stop(ctx)
+			} else if closeErr := l.Listener.Close(); closeErr != nil && !isClosedError(closeErr) {
+				err = closeErr
+			}
+
+			if err == nil {
+				select {
+				case <-l.done:
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+
+			if err != nil {
+				r.logger.Error(err, "listener shutdown failed", log.Str("name", l.Name))
+				errs[i] = fmt.Errorf("listener %s shutdown failed: %w", l.Name, err)
+			}
+		}(i, l)
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// isClosedError reports whether err signals a normal close of a listener or server.
+func isClosedError(err error) bool {
+	return errors.Is(err, net.ErrClosed) || errors.Is(err, http.ErrServerClosed)
+}
@@ -0,0 +1,113 @@
+// Package internal provides tests for runtimex raw listeners.
+package internal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echoServe accepts connections and echoes one line back on each.
+func echoServe(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			conn.Write([]byte(line))
+		}()
+	}
+}
+
+func TestRuntime_Listener(t *testing.T) {
+	r := NewRuntime(&mockLogger{}, nil, time.Second)
+	l := &Listener{Name: "echo", Addr: "127.0.0.1:0", Serve: echoServe}
+	r.AddListener(l)
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	conn, err := net.Dial("tcp", l.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	conn.Write([]byte("ping\n"))
+	reply, _ := bufio.NewReader(conn).ReadString('\n')
+	conn.Close()
+	if reply != "ping\n" {
+		t.Errorf("reply = %q, want %q", reply, "ping\n")
+	}
+
+	if err := r.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if _, err := net.Dial("tcp", l.Listener.Addr().String()); err == nil {
+		t.Error("listener still accepting after Stop()")
+	}
+}
+
+func TestRuntime_Listener_BindError(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer taken.Close()
+
+	r := NewRuntime(&mockLogger{}, nil, time.Second)
+	first := &Listener{Name: "first", Addr: "127.0.0.1:0", Serve: echoServe}
+	r.AddListener(first)
+	r.AddListener(&Listener{Name: "second", Addr: taken.Addr().String(), Serve: echoServe})
+
+	err = r.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "listener second") {
+		t.Fatalf("Start() error = %v, want bind error for second", err)
+	}
+	// The listener bound before the failure is released
+	if _, err := first.Listener.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("first listener Accept() error = %v, want net.ErrClosed", err)
+	}
+}
+
+func TestRuntime_Listener_DrainTimeout(t *testing.T) {
+	r := NewRuntime(&mockLogger{}, nil, 50*time.Millisecond)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	r.AddListener(&Listener{
+		Name: "stuck",
+		Addr: "127.0.0.1:0",
+		Serve: func(ln net.Listener) error {
+			<-release // ignores Stop, like a server that cannot drain
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	r.AddListener(&Listener{Name: "ok", Addr: "127.0.0.1:0", Serve: echoServe})
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	start := time.Now()
+	err := r.Stop(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Stop() took %v, want bounded by shutdown timeout", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop() error = %v, want DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "listener stuck") || strings.Contains(err.Error(), "listener ok") {
+		t.Errorf("Stop() error = %q, want only the stuck listener", err)
+	}
+}
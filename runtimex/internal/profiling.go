@@ -0,0 +1,39 @@
+// Package internal contains the runtime implementation.
+package internal
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+// profilingPrefix is the path prefix of the net/http/pprof handlers.
+const profilingPrefix = "/debug/pprof/"
+
+// NewProfilingHandler returns a mux serving the net/http/pprof handlers under
+// /debug/pprof/ for the dedicated profiling server.
+func NewProfilingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(profilingPrefix, pprof.Index)
+	mux.HandleFunc(profilingPrefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(profilingPrefix+"profile", pprof.Profile)
+	mux.HandleFunc(profilingPrefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(profilingPrefix+"trace", pprof.Trace)
+	return mux
+}
+
+// hideProfiling guards servers that fall back to http.DefaultServeMux.
+// Importing net/http/pprof registers its handlers there, so without the guard
+// they would be reachable on the main ports.
+func hideProfiling(server *http.Server) {
+	if server.Handler != nil && server.Handler != http.DefaultServeMux {
+		return
+	}
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, profilingPrefix) {
+			http.NotFound(w, r)
+			return
+		}
+		http.DefaultServeMux.ServeHTTP(w, r)
+	})
+}
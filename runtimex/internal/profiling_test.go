@@ -0,0 +1,48 @@
+// Package internal provides tests for runtimex profiling endpoints.
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewProfilingHandler(t *testing.T) {
+	handler := NewProfilingHandler()
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/goroutine?debug=1"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, rec.Code)
+		}
+	}
+}
+
+func TestHideProfiling(t *testing.T) {
+	http.HandleFunc("/hide-profiling-test", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := &http.Server{}
+	hideProfiling(server)
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+	if code := get("/debug/pprof/cmdline"); code != http.StatusNotFound {
+		t.Errorf("GET /debug/pprof/cmdline on default mux = %d, want 404", code)
+	}
+	if code := get("/hide-profiling-test"); code != http.StatusNoContent {
+		t.Errorf("GET /hide-profiling-test = %d, want default mux to serve it", code)
+	}
+
+	mux := http.NewServeMux()
+	custom := &http.Server{Handler: mux}
+	hideProfiling(custom)
+	if custom.Handler != mux {
+		t.Error("hideProfiling() should leave an explicit mux untouched")
+	}
+}
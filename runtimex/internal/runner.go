@@ -0,0 +1,97 @@
+// Package internal contains the runtime implementation.
+package internal
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// Runner is a started runtime that stops on context cancellation, on one of
+// its signals, or on an explicit Shutdown, whichever comes first.
+type Runner struct {
+	runtime *Runtime
+	signals []os.Signal
+	sigCh   chan os.Signal
+
+	stopOnce sync.Once
+	done     chan struct{}
+	err      error
+}
+
+// NewRunner creates a runner for runtime that shuts down on signals.
+// No signal handling is installed if signals is empty.
+func NewRunner(runtime *Runtime, signals []os.Signal) *Runner {
+	return &Runner{
+		runtime: runtime,
+		signals: signals,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start starts the runtime and watches for shutdown triggers in the background.
+func (r *Runner) Start(ctx context.Context) error {
+	if err := r.runtime.Start(ctx); err != nil {
+		return err
+	}
+
+	if len(r.signals) > 0 {
+		r.sigCh = make(chan os.Signal, 1)
+		signal.Notify(r.sigCh, r.signals...)
+	}
+
+	go r.watch(ctx)
+	return nil
+}
+
+// watch stops the runtime once ctx is cancelled or a signal arrives.
+// A nil sigCh blocks forever, so the signal case is disabled without signals.
+func (r *Runner) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case sig := <-r.sigCh:
+		r.runtime.logger.Info("received shutdown signal", log.Str("signal", sig.String()))
+	case <-r.done:
+		return
+	}
+	r.stop(context.Background())
+}
+
+// stop stops the runtime exactly once and records the result.
+func (r *Runner) stop(ctx context.Context) {
+	r.stopOnce.Do(func() {
+		if r.sigCh != nil {
+			signal.Stop(r.sigCh)
+		}
+		r.err = r.runtime.Stop(ctx)
+		close(r.done)
+	})
+}
+
+// Shutdown gracefully stops the runtime and waits until it has stopped or ctx
+// expires. Only the first shutdown, from any trigger, runs; later calls wait
+// for it and return its result.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	go r.stop(ctx)
+
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done returns a channel closed once the runtime has stopped.
+func (r *Runner) Done() <-chan struct{} {
+	return r.done
+}
+
+// Wait blocks until the runtime has stopped and returns the shutdown error.
+func (r *Runner) Wait() error {
+	<-r.done
+	return r.err
+}
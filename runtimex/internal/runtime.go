@@ -3,6 +3,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -18,6 +19,9 @@ type Runtime struct {
 	rpcServer       *http.Server
 	healthServer    *http.Server
 	metricsServer   *http.Server
+	profilingServer *http.Server
+	listeners       []*Listener
+	trackers        map[*http.Server]*serverTracker
 	services        []Service
 	shutdownTimeout time.Duration
 }
@@ -70,11 +74,26 @@ func (r *Runtime) Start(ctx context.Context) error {
 		}
 	}
 
+	// Bind and serve raw listeners
+	if err := r.startListeners(); err != nil {
+		return err
+	}
+
+	r.trackers = make(map[*http.Server]*serverTracker)
+
 	// Start HTTP server if configured
 	if r.httpServer != nil {
+		hideProfiling(r.httpServer)
+		r.trackers[r.httpServer] = trackServer("http", r.httpServer)
 		go func() {
-			r.logger.Info("starting HTTP server", log.Str("addr", r.httpServer.Addr))
-			if err := r.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			useTLS := r.httpServer.TLSConfig != nil
+			r.logger.Info("starting HTTP server", log.Str("addr", r.httpServer.Addr), log.Bool("tls", useTLS))
+			serve := r.httpServer.ListenAndServe
+			if useTLS {
+				// Certificates are already in TLSConfig
+				serve = func() error { return r.httpServer.ListenAndServeTLS("", "") }
+			}
+			if err := serve(); err != nil && err != http.ErrServerClosed {
 				r.logger.Error(err, "HTTP server failed")
 			}
 		}()
@@ -82,6 +101,8 @@ func (r *Runtime) Start(ctx context.Context) error {
 
 	// Start RPC server if configured
 	if r.rpcServer != nil {
+		hideProfiling(r.rpcServer)
+		r.trackers[r.rpcServer] = trackServer("rpc", r.rpcServer)
 		go func() {
 			r.logger.Info("starting RPC server", log.Str("addr", r.rpcServer.Addr))
 			if err := r.rpcServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -92,6 +113,7 @@ func (r *Runtime) Start(ctx context.Context) error {
 
 	// Start health server if configured
 	if r.healthServer != nil {
+		r.trackers[r.healthServer] = trackServer("health", r.healthServer)
 		go func() {
 			r.logger.Info("starting health server", log.Str("addr", r.healthServer.Addr))
 			if err := r.healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -102,6 +124,7 @@ func (r *Runtime) Start(ctx context.Context) error {
 
 	// Start metrics server if configured
 	if r.metricsServer != nil {
+		r.trackers[r.metricsServer] = trackServer("metrics", r.metricsServer)
 		go func() {
 			r.logger.Info("starting metrics server", log.Str("addr", r.metricsServer.Addr))
 			if err := r.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -110,11 +133,27 @@ func (r *Runtime) Start(ctx context.Context) error {
 		}()
 	}
 
+	// Start profiling server if configured
+	if r.profilingServer != nil {
+		r.trackers[r.profilingServer] = trackServer("profiling", r.profilingServer)
+		go func() {
+			r.logger.Info("starting profiling server", log.Str("addr", r.profilingServer.Addr))
+			if err := r.profilingServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				r.logger.Error(err, "profiling server failed")
+			}
+		}()
+	}
+
 	r.logger.Info("runtime started successfully")
 	return nil
 }
 
 // Stop gracefully shuts down all services and servers.
+// Servers stop accepting new connections and streams immediately and drain
+// in-flight requests, logging progress. Servers and listeners share the
+// shutdown timeout; requests still running at the deadline have their contexts
+// cancelled, and the errors of everything that failed to drain in time are
+// joined into the returned error.
 func (r *Runtime) Stop(ctx context.Context) error {
 	r.logger.Info("stopping runtime")
 
@@ -151,37 +190,64 @@ func (r *Runtime) Stop(ctx context.Context) error {
 		}
 	}
 
-	// Stop servers
+	// Stop servers and listeners
+	var drainErrs []error
+
+	if err := r.stopListeners(shutdownCtx); err != nil {
+		drainErrs = append(drainErrs, err)
+	}
+
 	if r.httpServer != nil {
 		r.logger.Info("stopping HTTP server")
-		if err := r.httpServer.Shutdown(shutdownCtx); err != nil {
+		if err := r.shutdownServer(shutdownCtx, r.httpServer); err != nil {
 			r.logger.Error(err, "HTTP server shutdown failed")
+			drainErrs = append(drainErrs, fmt.Errorf("HTTP server shutdown failed: %w", err))
 		}
 	}
 
 	if r.rpcServer != nil {
 		r.logger.Info("stopping RPC server")
-		if err := r.rpcServer.Shutdown(shutdownCtx); err != nil {
+		if err := r.shutdownServer(shutdownCtx, r.rpcServer); err != nil {
 			r.logger.Error(err, "RPC server shutdown failed")
+			drainErrs = append(drainErrs, fmt.Errorf("RPC server shutdown failed: %w", err))
 		}
 	}
 
 	if r.healthServer != nil {
 		r.logger.Info("stopping health server")
-		if err := r.healthServer.Shutdown(shutdownCtx); err != nil {
+		if err := r.shutdownServer(shutdownCtx, r.healthServer); err != nil {
 			r.logger.Error(err, "health server shutdown failed")
+			drainErrs = append(drainErrs, fmt.Errorf("health server shutdown failed: %w", err))
 		}
 	}
 
 	if r.metricsServer != nil {
 		r.logger.Info("stopping metrics server")
-		if err := r.metricsServer.Shutdown(shutdownCtx); err != nil {
+		if err := r.shutdownServer(shutdownCtx, r.metricsServer); err != nil {
 			r.logger.Error(err, "metrics server shutdown failed")
+			drainErrs = append(drainErrs, fmt.Errorf("metrics server shutdown failed: %w", err))
+		}
+	}
+
+	if r.profilingServer != nil {
+		r.logger.Info("stopping profiling server")
+		if err := r.shutdownServer(shutdownCtx, r.profilingServer); err != nil {
+			r.logger.Error(err, "profiling server shutdown failed")
+			drainErrs = append(drainErrs, fmt.Errorf("profiling server shutdown failed: %w", err))
 		}
 	}
 
 	r.logger.Info("runtime stopped")
-	return nil
+	return errors.Join(drainErrs...)
+}
+
+// shutdownServer drains server through its tracker, or shuts it down directly
+// if it was never started.
+func (r *Runtime) shutdownServer(ctx context.Context, server *http.Server) error {
+	if t, ok := r.trackers[server]; ok {
+		return t.drain(ctx, r.logger)
+	}
+	return server.Shutdown(ctx)
 }
 
 // SetHTTPServer sets the HTTP server.
@@ -203,3 +269,8 @@ func (r *Runtime) SetHealthServer(server *http.Server) {
 func (r *Runtime) SetMetricsServer(server *http.Server) {
 	r.metricsServer = server
 }
+
+// SetProfilingServer sets the pprof profiling server.
+func (r *Runtime) SetProfilingServer(server *http.Server) {
+	r.profilingServer = server
+}
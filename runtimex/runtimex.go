@@ -19,8 +19,12 @@ package runtimex
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"syscall"
 	"time"
 
 	"go.eggybyte.com/egg/core/log"
@@ -47,10 +51,137 @@ type Endpoint struct {
 }
 
 // HTTPOptions configures the HTTP server.
+//
+// Zero timeouts and MaxHeaderBytes take secure defaults; a negative timeout
+// disables it. ReadTimeout and WriteTimeout bound whole request bodies and
+// responses (per stream under HTTP/2), which would cut off long-lived streaming
+// RPCs, so with H2C or TLS (which negotiates HTTP/2) they default to unlimited.
+// Set them explicitly only if the server carries no streams that can outlive
+// them.
+//
+// H2C and TLS are mutually exclusive: H2C serves HTTP/2 without encryption,
+// while TLS negotiates HTTP/2 over ALPN on the same port.
 type HTTPOptions struct {
 	Port int            // Port number (e.g., 8080)
 	H2C  bool           // Enable HTTP/2 Cleartext support
+	TLS  *TLSConfig     // Serve HTTPS, terminating TLS in-process (default: plain HTTP)
 	Mux  *http.ServeMux // HTTP request multiplexer
+
+	ReadHeaderTimeout time.Duration // Time to read request headers (default: 10s)
+	ReadTimeout       time.Duration // Time to read the whole request (default: 30s; unlimited with H2C)
+	WriteTimeout      time.Duration // Time to write the response (default: 30s; unlimited with H2C)
+	IdleTimeout       time.Duration // Keep-alive idle time (default: 120s)
+	MaxHeaderBytes    int           // Maximum request header size (default: 1 MiB)
+}
+
+// TLSConfig configures in-process TLS termination for the HTTP server.
+//
+// Certificates come from CertFile and KeyFile, loaded at Start, or from
+// GetCertificate, which takes precedence and suits SNI or ACME (e.g.
+// autocert.Manager.GetCertificate). TLS 1.2 is the minimum version, with
+// only forward-secret AEAD cipher suites for TLS 1.2; TLS 1.3 suites are not
+// configurable.
+type TLSConfig struct {
+	CertFile       string                                               // PEM certificate (chain) file
+	KeyFile        string                                               // PEM private key file
+	GetCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error) // Per-handshake certificate (optional)
+	MinVersion     uint16                                               // Minimum TLS version (default: tls.VersionTLS12)
+}
+
+// tlsCipherSuites are the TLS 1.2 cipher suites offered: ECDHE key exchange
+// with AEAD ciphers only.
+var tlsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// newTLSConfig builds the server TLS configuration, loading the certificate
+// files unless GetCertificate is set.
+func newTLSConfig(c *TLSConfig) (*tls.Config, error) {
+	minVersion := c.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+	if minVersion < tls.VersionTLS12 {
+		return nil, fmt.Errorf("minimum TLS version %s is insecure, use TLS 1.2 or later", tls.VersionName(minVersion))
+	}
+
+	config := &tls.Config{
+		MinVersion:     minVersion,
+		CipherSuites:   tlsCipherSuites,
+		GetCertificate: c.GetCertificate,
+	}
+	if c.GetCertificate != nil {
+		return config, nil
+	}
+
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, fmt.Errorf("TLS requires CertFile and KeyFile, or GetCertificate")
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	config.Certificates = []tls.Certificate{cert}
+	return config, nil
+}
+
+// Default HTTP server limits applied when HTTPOptions leaves them unset.
+const (
+	DefaultReadHeaderTimeout = 10 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultMaxHeaderBytes    = 1 << 20
+)
+
+// newHTTPServer builds the main HTTP server with timeouts, header limits and,
+// if enabled, native h2c applied.
+func newHTTPServer(o *HTTPOptions) *http.Server {
+	readTimeout := DefaultReadTimeout
+	writeTimeout := DefaultWriteTimeout
+	if o.H2C || o.TLS != nil {
+		readTimeout, writeTimeout = 0, 0
+	}
+	maxHeaderBytes := o.MaxHeaderBytes
+	if maxHeaderBytes <= 0 {
+		maxHeaderBytes = DefaultMaxHeaderBytes
+	}
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", o.Port),
+		Handler:           o.Mux,
+		ReadHeaderTimeout: timeoutOrDefault(o.ReadHeaderTimeout, DefaultReadHeaderTimeout),
+		ReadTimeout:       timeoutOrDefault(o.ReadTimeout, readTimeout),
+		WriteTimeout:      timeoutOrDefault(o.WriteTimeout, writeTimeout),
+		IdleTimeout:       timeoutOrDefault(o.IdleTimeout, DefaultIdleTimeout),
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+	if o.H2C {
+		// Native h2c keeps HTTP/2 connections owned by the server, so
+		// shutdown sends GOAWAY and drains streams instead of losing them
+		// to a hijacked connection.
+		httpServer.Protocols = new(http.Protocols)
+		httpServer.Protocols.SetHTTP1(true)
+		httpServer.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return httpServer
+}
+
+// timeoutOrDefault maps an option value to an http.Server timeout:
+// zero takes def and negative disables the timeout.
+func timeoutOrDefault(v, def time.Duration) time.Duration {
+	switch {
+	case v < 0:
+		return 0
+	case v == 0:
+		return def
+	default:
+		return v
+	}
 }
 
 // RPCOptions configures the RPC server (for split port strategy).
@@ -58,19 +189,100 @@ type RPCOptions struct {
 	Port int // Port number (e.g., 9090)
 }
 
+// Listener configures a raw TCP or gRPC server run alongside the HTTP servers.
+//
+// Serve is called with the bound listener and must block until the server
+// stops. Stop must make Serve return, draining in-flight work within ctx;
+// if Stop is nil the listener is closed instead.
+//
+// Example (gRPC):
+//
+//	runtimex.Listener{
+//	    Name:  "grpc",
+//	    Addr:  ":9090",
+//	    Serve: grpcServer.Serve,
+//	    Stop: func(ctx context.Context) error {
+//	        done := make(chan struct{})
+//	        go func() { grpcServer.GracefulStop(); close(done) }()
+//	        select {
+//	        case <-done:
+//	            return nil
+//	        case <-ctx.Done():
+//	            grpcServer.Stop()
+//	            return ctx.Err()
+//	        }
+//	    },
+//	}
+type Listener struct {
+	Name     string                          // Name used in logs and errors
+	Listener net.Listener                    // Pre-bound listener (takes precedence over Addr)
+	Addr     string                          // Address to listen on, e.g. ":9090"
+	Serve    func(net.Listener) error        // Serves connections until stopped
+	Stop     func(ctx context.Context) error // Graceful stop (optional)
+}
+
+// HealthCheck is a named dependency check aggregated by the health endpoint.
+// Check should return quickly and honor ctx, which is derived from the probe
+// request context and expires after Options.HealthTimeout.
+type HealthCheck struct {
+	Name  string                          // Name reported in the health JSON
+	Check func(ctx context.Context) error // Returns nil when the dependency is healthy
+}
+
 // Options holds configuration for the runtime.
 type Options struct {
 	Logger          log.Logger    // Logger for runtime operations
 	HTTP            *HTTPOptions  // HTTP server options (required for single port)
 	RPC             *RPCOptions   // RPC server options (optional, for split ports)
 	Health          *Endpoint     // Health check endpoint (recommended)
+	HealthChecks    []HealthCheck // Dependency checks aggregated by /health/ready (optional)
+	HealthTimeout   time.Duration // Time each /health/ready check may take (default: health.DefaultTimeout, 5s)
 	Metrics         *Endpoint     // Metrics endpoint (recommended)
+	Profiling       *Endpoint     // net/http/pprof endpoint (optional, off by default)
+	Listeners       []Listener    // Raw TCP/gRPC listeners (optional)
 	ShutdownTimeout time.Duration // Graceful shutdown timeout
+
+	// Signals trigger graceful shutdown. Nil defaults to SIGINT and SIGTERM;
+	// an empty non-nil slice disables signal handling.
+	Signals []os.Signal
+}
+
+// Runner is a handle to a runtime started with Start.
+// Shutdown is triggered by cancelling the Start context, by one of
+// Options.Signals, or by calling Shutdown, and runs only once.
+type Runner struct {
+	impl *internal.Runner
+}
+
+// Shutdown gracefully stops the runtime and waits for it to stop.
+//
+// Parameters:
+//   - ctx: bounds the wait; ShutdownTimeout still applies to draining
+//
+// Returns:
+//   - error: joined drain errors of the shutdown (whichever trigger ran it),
+//     or ctx.Err() if ctx expired first
+//
+// Concurrency:
+//   - Safe to call concurrently and more than once
+func (r *Runner) Shutdown(ctx context.Context) error {
+	return r.impl.Shutdown(ctx)
+}
+
+// Done returns a channel that is closed once the runtime has stopped.
+func (r *Runner) Done() <-chan struct{} {
+	return r.impl.Done()
+}
+
+// Wait blocks until the runtime has stopped and returns the shutdown error.
+func (r *Runner) Wait() error {
+	return r.impl.Wait()
 }
 
 // Run starts all services and manages their lifecycle.
-// This function blocks until the context is cancelled or an error occurs.
-// Services are started concurrently and stopped gracefully on shutdown.
+// This function blocks until the context is cancelled, a shutdown signal
+// arrives or an error occurs. Services are started concurrently and stopped
+// gracefully on shutdown. Run is Start followed by Runner.Wait.
 //
 // Parameters:
 //   - ctx: context for lifecycle management
@@ -78,14 +290,48 @@ type Options struct {
 //   - opts: runtime configuration options
 //
 // Returns:
-//   - error: runtime error if any
+//   - error: runtime error if any; on shutdown, the joined errors of every
+//     server or listener that failed to drain within ShutdownTimeout
 //
 // Concurrency:
 //   - Services are started and stopped concurrently
-//   - Blocks until context is cancelled
+//   - Blocks until shutdown completes
 func Run(ctx context.Context, services []Service, opts Options) error {
+	runner, err := Start(ctx, services, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := runner.Wait(); err != nil {
+		return fmt.Errorf("runtime stop failed: %w", err)
+	}
+
+	return nil
+}
+
+// Start starts all services and servers and returns without blocking.
+// The returned Runner shuts down when ctx is cancelled, when one of
+// opts.Signals arrives, or when Runner.Shutdown is called.
+//
+// Parameters:
+//   - ctx: context for lifecycle management
+//   - services: list of services to manage
+//   - opts: runtime configuration options
+//
+// Returns:
+//   - *Runner: handle for triggering and awaiting shutdown
+//   - error: configuration or startup error if any
+//
+// Example:
+//
+//	runner, err := runtimex.Start(ctx, services, runtimex.Options{Logger: logger, HTTP: httpOpts})
+//	if err != nil {
+//	    return err
+//	}
+//	defer runner.Shutdown(context.Background())
+func Start(ctx context.Context, services []Service, opts Options) (*Runner, error) {
 	if opts.Logger == nil {
-		return fmt.Errorf("logger is required")
+		return nil, fmt.Errorf("logger is required")
 	}
 
 	// Set default shutdown timeout
@@ -105,10 +351,16 @@ func Run(ctx context.Context, services []Service, opts Options) error {
 
 	// Configure servers
 	if opts.HTTP != nil {
-		addr := fmt.Sprintf(":%d", opts.HTTP.Port)
-		httpServer := &http.Server{
-			Addr:    addr,
-			Handler: opts.HTTP.Mux,
+		httpServer := newHTTPServer(opts.HTTP)
+		if opts.HTTP.TLS != nil {
+			if opts.HTTP.H2C {
+				return nil, fmt.Errorf("HTTP server %s: H2C and TLS are mutually exclusive on one listener", httpServer.Addr)
+			}
+			tlsConfig, err := newTLSConfig(opts.HTTP.TLS)
+			if err != nil {
+				return nil, fmt.Errorf("HTTP server %s: %w", httpServer.Addr, err)
+			}
+			httpServer.TLSConfig = tlsConfig
 		}
 		runtime.SetHTTPServer(httpServer)
 	}
@@ -121,14 +373,31 @@ func Run(ctx context.Context, services []Service, opts Options) error {
 		runtime.SetRPCServer(rpcServer)
 	}
 
+	healthChecks := make([]internal.HealthCheck, len(opts.HealthChecks))
+	seen := make(map[string]bool, len(opts.HealthChecks))
+	for i, c := range opts.HealthChecks {
+		if c.Check == nil {
+			return nil, fmt.Errorf("health check %d: check function is required", i)
+		}
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("check_%d", i)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate health check name %q", name)
+		}
+		if internal.HasHealthChecker(name) {
+			return nil, fmt.Errorf("health check name %q is already used by a registered HealthChecker", name)
+		}
+		seen[name] = true
+		healthChecks[i] = internal.HealthCheck{Name: name, Check: c.Check}
+	}
+
 	if opts.Health != nil {
 		addr := fmt.Sprintf(":%d", opts.Health.Port)
 		healthServer := &http.Server{
-			Addr: addr,
-			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("OK"))
-			}),
+			Addr:    addr,
+			Handler: internal.NewHealthHandler(healthChecks, opts.HealthTimeout),
 		}
 		runtime.SetHealthServer(healthServer)
 	}
@@ -145,20 +414,47 @@ func Run(ctx context.Context, services []Service, opts Options) error {
 		runtime.SetMetricsServer(metricsServer)
 	}
 
-	// Start runtime
-	if err := runtime.Start(ctx); err != nil {
-		return fmt.Errorf("runtime start failed: %w", err)
+	if opts.Profiling != nil {
+		addr := fmt.Sprintf(":%d", opts.Profiling.Port)
+		profilingServer := &http.Server{
+			Addr:    addr,
+			Handler: internal.NewProfilingHandler(),
+		}
+		runtime.SetProfilingServer(profilingServer)
 	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
+	for i, l := range opts.Listeners {
+		if l.Serve == nil {
+			return nil, fmt.Errorf("listener %d: serve function is required", i)
+		}
+		if l.Listener == nil && l.Addr == "" {
+			return nil, fmt.Errorf("listener %d: listener or addr is required", i)
+		}
+		name := l.Name
+		if name == "" {
+			name = fmt.Sprintf("listener_%d", i)
+		}
+		runtime.AddListener(&internal.Listener{
+			Name:     name,
+			Listener: l.Listener,
+			Addr:     l.Addr,
+			Serve:    l.Serve,
+			Stop:     l.Stop,
+		})
+	}
 
-	// Stop runtime
-	if err := runtime.Stop(context.Background()); err != nil {
-		return fmt.Errorf("runtime stop failed: %w", err)
+	signals := opts.Signals
+	if signals == nil {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
 	}
 
-	return nil
+	// Start runtime
+	runner := internal.NewRunner(runtime, signals)
+	if err := runner.Start(ctx); err != nil {
+		return nil, fmt.Errorf("runtime start failed: %w", err)
+	}
+
+	return &Runner{impl: runner}, nil
 }
 
 // --- Health check aggregation ---
@@ -174,6 +470,11 @@ type HealthChecker interface {
 
 // RegisterHealthChecker registers a global health checker.
 //
+// Run and Start reject Options.HealthChecks named like a checker registered
+// before them. A checker registered later whose name is taken is reported
+// under "<name>_2" (or the next free suffix) instead of replacing the other
+// result.
+//
 // Parameters:
 //   - checker: health checker implementation
 //
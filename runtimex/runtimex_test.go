@@ -3,8 +3,22 @@ package runtimex
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -305,3 +319,395 @@ func TestRun_WithServices(t *testing.T) {
 		t.Error("Service Stop should have been called")
 	}
 }
+
+func TestRun_WithListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+
+	served := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx, nil, Options{
+			Logger: &testLogger{},
+			Listeners: []Listener{{
+				Name:     "tcp",
+				Listener: ln,
+				Serve: func(l net.Listener) error {
+					for {
+						conn, err := l.Accept()
+						if err != nil {
+							return err
+						}
+						conn.Close()
+						close(served)
+					}
+				},
+			}},
+			ShutdownTimeout: time.Second,
+		})
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	conn.Close()
+
+	select {
+	case <-served:
+	case <-time.After(2 * time.Second):
+		t.Fatal("listener did not accept the connection")
+	}
+
+	cancel()
+	if err := <-errChan; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestRun_ListenerValidation(t *testing.T) {
+	err := Run(context.Background(), nil, Options{
+		Logger:    &testLogger{},
+		Listeners: []Listener{{Name: "tcp", Addr: ":0"}},
+	})
+	if err == nil {
+		t.Error("Run() should reject a listener without a serve function")
+	}
+
+	err = Run(context.Background(), nil, Options{
+		Logger:    &testLogger{},
+		Listeners: []Listener{{Serve: func(net.Listener) error { return nil }}},
+	})
+	if err == nil {
+		t.Error("Run() should reject a listener without a listener or addr")
+	}
+}
+
+func TestRun_HealthCheckValidation(t *testing.T) {
+	err := Run(context.Background(), nil, Options{
+		Logger:       &testLogger{},
+		HealthChecks: []HealthCheck{{Name: "db"}},
+	})
+	if err == nil {
+		t.Error("Run() should reject a health check without a check function")
+	}
+
+	ok := func(context.Context) error { return nil }
+	err = Run(context.Background(), nil, Options{
+		Logger:       &testLogger{},
+		HealthChecks: []HealthCheck{{Name: "db", Check: ok}, {Name: "db", Check: ok}},
+	})
+	if err == nil {
+		t.Error("Run() should reject duplicate health check names")
+	}
+
+	ClearHealthCheckers()
+	defer ClearHealthCheckers()
+	RegisterHealthChecker(namedChecker("db"))
+	err = Run(context.Background(), nil, Options{
+		Logger:       &testLogger{},
+		HealthChecks: []HealthCheck{{Name: "db", Check: ok}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "registered HealthChecker") {
+		t.Errorf("Run() error = %v, want rejection of a name used by a registered checker", err)
+	}
+}
+
+// namedChecker is a HealthChecker that always passes.
+type namedChecker string
+
+func (c namedChecker) Name() string                    { return string(c) }
+func (c namedChecker) Check(ctx context.Context) error { return nil }
+
+func TestStart_Shutdown(t *testing.T) {
+	service := &mockService{}
+
+	runner, err := Start(context.Background(), []Service{service}, Options{
+		Logger:  &testLogger{},
+		Signals: []os.Signal{},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !service.getStartCalled() {
+		t.Fatal("Service Start should have been called before Start() returns")
+	}
+
+	select {
+	case <-runner.Done():
+		t.Fatal("Runner stopped before Shutdown()")
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !service.getStopCalled() {
+		t.Error("Service Stop should have been called")
+	}
+
+	select {
+	case <-runner.Done():
+	default:
+		t.Error("Done() should be closed after Shutdown()")
+	}
+	if err := runner.Shutdown(ctx); err != nil {
+		t.Errorf("second Shutdown() error = %v, want nil", err)
+	}
+	if err := runner.Wait(); err != nil {
+		t.Errorf("Wait() error = %v, want nil", err)
+	}
+}
+
+func TestStart_CustomSignal(t *testing.T) {
+	service := &mockService{}
+
+	runner, err := Start(context.Background(), []Service{service}, Options{
+		Logger:  &testLogger{},
+		Signals: []os.Signal{syscall.SIGHUP},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer runner.Shutdown(context.Background())
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess() error = %v", err)
+	}
+	if err := proc.Signal(syscall.SIGHUP); err != nil {
+		t.Skipf("sending SIGHUP is not supported: %v", err)
+	}
+
+	select {
+	case <-runner.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Runner did not stop on the configured signal")
+	}
+	if !service.getStopCalled() {
+		t.Error("Service Stop should have been called")
+	}
+}
+
+func TestNewHTTPServer_Timeouts(t *testing.T) {
+	tests := []struct {
+		name                     string
+		opts                     HTTPOptions
+		wantReadHeader, wantRead time.Duration
+		wantWrite, wantIdle      time.Duration
+		wantMaxHeaderBytes       int
+	}{
+		{
+			name:               "defaults",
+			opts:               HTTPOptions{Port: 8080},
+			wantReadHeader:     DefaultReadHeaderTimeout,
+			wantRead:           DefaultReadTimeout,
+			wantWrite:          DefaultWriteTimeout,
+			wantIdle:           DefaultIdleTimeout,
+			wantMaxHeaderBytes: DefaultMaxHeaderBytes,
+		},
+		{
+			name:               "h2c leaves streams unlimited",
+			opts:               HTTPOptions{Port: 8080, H2C: true},
+			wantReadHeader:     DefaultReadHeaderTimeout,
+			wantIdle:           DefaultIdleTimeout,
+			wantMaxHeaderBytes: DefaultMaxHeaderBytes,
+		},
+		{
+			name:               "tls leaves streams unlimited",
+			opts:               HTTPOptions{Port: 8080, TLS: &TLSConfig{}},
+			wantReadHeader:     DefaultReadHeaderTimeout,
+			wantIdle:           DefaultIdleTimeout,
+			wantMaxHeaderBytes: DefaultMaxHeaderBytes,
+		},
+		{
+			name: "explicit values and disabled timeouts",
+			opts: HTTPOptions{
+				Port:              8080,
+				H2C:               true,
+				ReadHeaderTimeout: 2 * time.Second,
+				ReadTimeout:       5 * time.Second,
+				WriteTimeout:      -1,
+				IdleTimeout:       -1,
+				MaxHeaderBytes:    8 << 10,
+			},
+			wantReadHeader:     2 * time.Second,
+			wantRead:           5 * time.Second,
+			wantMaxHeaderBytes: 8 << 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newHTTPServer(&tt.opts)
+			if server.ReadHeaderTimeout != tt.wantReadHeader {
+				t.Errorf("ReadHeaderTimeout = %v, want %v", server.ReadHeaderTimeout, tt.wantReadHeader)
+			}
+			if server.ReadTimeout != tt.wantRead {
+				t.Errorf("ReadTimeout = %v, want %v", server.ReadTimeout, tt.wantRead)
+			}
+			if server.WriteTimeout != tt.wantWrite {
+				t.Errorf("WriteTimeout = %v, want %v", server.WriteTimeout, tt.wantWrite)
+			}
+			if server.IdleTimeout != tt.wantIdle {
+				t.Errorf("IdleTimeout = %v, want %v", server.IdleTimeout, tt.wantIdle)
+			}
+			if server.MaxHeaderBytes != tt.wantMaxHeaderBytes {
+				t.Errorf("MaxHeaderBytes = %d, want %d", server.MaxHeaderBytes, tt.wantMaxHeaderBytes)
+			}
+			if got := server.Protocols != nil && server.Protocols.UnencryptedHTTP2(); got != tt.opts.H2C {
+				t.Errorf("UnencryptedHTTP2 = %v, want %v", got, tt.opts.H2C)
+			}
+		})
+	}
+}
+
+// writeTestCert writes a self-signed certificate for localhost into dir and
+// returns the certificate and key file paths.
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestStart_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	runner, err := Start(context.Background(), nil, Options{
+		Logger:  &testLogger{},
+		Signals: []os.Signal{},
+		HTTP: &HTTPOptions{
+			Port: port,
+			Mux:  mux,
+			TLS:  &TLSConfig{CertFile: certFile, KeyFile: keyFile},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer runner.Shutdown(context.Background())
+
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	url := "https://127.0.0.1:" + strconv.Itoa(port) + "/"
+
+	var resp *http.Response
+	for i := 0; i < 20; i++ {
+		if resp, err = client.Get(url); err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+	if resp.TLS == nil || resp.TLS.Version < tls.VersionTLS12 {
+		t.Errorf("TLS connection state = %+v, want TLS 1.2 or later", resp.TLS)
+	}
+}
+
+func TestStart_TLSValidation(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil }
+
+	tests := []struct {
+		name    string
+		opts    HTTPOptions
+		wantErr bool
+	}{
+		{name: "h2c with tls", opts: HTTPOptions{Port: 18443, H2C: true, TLS: &TLSConfig{CertFile: certFile, KeyFile: keyFile}}, wantErr: true},
+		{name: "no certificate source", opts: HTTPOptions{Port: 18443, TLS: &TLSConfig{}}, wantErr: true},
+		{name: "missing key file", opts: HTTPOptions{Port: 18443, TLS: &TLSConfig{CertFile: certFile}}, wantErr: true},
+		{name: "unreadable certificate", opts: HTTPOptions{Port: 18443, TLS: &TLSConfig{CertFile: keyFile, KeyFile: keyFile}}, wantErr: true},
+		{name: "insecure minimum version", opts: HTTPOptions{Port: 18443, TLS: &TLSConfig{GetCertificate: getCertificate, MinVersion: tls.VersionTLS10}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			runner, err := Start(context.Background(), nil, Options{
+				Logger:  &testLogger{},
+				Signals: []os.Signal{},
+				HTTP:    &opts,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Start() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if runner != nil {
+				runner.Shutdown(context.Background())
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil }
+
+	config, err := newTLSConfig(&TLSConfig{GetCertificate: getCertificate})
+	if err != nil {
+		t.Fatalf("newTLSConfig() error = %v", err)
+	}
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", config.MinVersion)
+	}
+	if config.GetCertificate == nil || len(config.Certificates) != 0 {
+		t.Error("GetCertificate should be used instead of certificate files")
+	}
+	if len(config.CipherSuites) == 0 {
+		t.Error("CipherSuites should be restricted")
+	}
+}
@@ -2,12 +2,16 @@
 package internal
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"go.eggybyte.com/egg/configx"
 	"go.eggybyte.com/egg/core/log"
+	"go.eggybyte.com/egg/storex"
 	"gorm.io/gorm"
 )
 
@@ -22,18 +26,41 @@ type ServiceConfig struct {
 	EnableDebug    bool
 	RegisterFn     func(interface{}) error // Takes *App interface
 
+	// Lifecycle hooks
+	PreStartHooks     []func(interface{}) error // Run after RegisterFn, before servers start
+	PostShutdownHooks []func(context.Context)   // Run after servers drain
+
+	// HTTP middleware wrapping the whole mux (first is outermost)
+	HTTPMiddleware []func(http.Handler) http.Handler
+
+	// Background workers sharing the service lifecycle
+	Workers []Worker
+
+	// Startup summary
+	StartupSummary bool      // Log one line summarizing the started service
+	StartupBanner  io.Writer // Also write the summary as a JSON line (nil = log only)
+
+	// Readiness
+	ReadinessChecks      []ReadinessCheck // Checks that gate the /ready endpoint
+	ReadinessWaitTimeout time.Duration    // Wait for readiness before reporting started (0 = don't wait)
+
 	// Server ports
-	HTTPPort    int
-	HealthPort  int
-	MetricsPort int
+	HTTPPort      int
+	HealthPort    int
+	MetricsPort   int
+	PortOverrides PortOverrides // Explicit ports from WithPorts (override env-derived ports)
 
 	// Connect options
+	EnableRecover     bool // Convert handler panics into CodeInternal errors
 	DefaultTimeoutMs  int64
+	TimeoutFromConfig func(any) int64 // Reads DefaultTimeoutMs from the bound config (re-read on reload)
 	SlowRequestMillis int64
 
 	// Database
 	DBConfig          *DatabaseConfig
 	AutoMigrateModels []any
+	Migrations        []storex.Migration    // Versioned migrations run after AutoMigrateModels
+	NamedDBConfigs    []NamedDatabaseConfig // Additional databases, opened after DBConfig
 
 	// Shutdown
 	ShutdownTimeout time.Duration
@@ -44,7 +71,7 @@ type ServiceConfig struct {
 type MetricsConfig struct {
 	EnableRuntime bool // Enable Go runtime metrics (goroutines, GC, memory)
 	EnableProcess bool // Enable process metrics (CPU, RSS, uptime)
-	EnableDB      bool // Enable database connection pool metrics
+	EnableDB      bool // Enable database pool, query latency and slow query metrics
 	EnableClient  bool // Enable client-side RPC metrics
 }
 
@@ -58,6 +85,12 @@ type DatabaseConfig struct {
 	PingTimeout     time.Duration
 }
 
+// NamedDatabaseConfig is an additional database reached by name.
+type NamedDatabaseConfig struct {
+	Name   string
+	Config *DatabaseConfig
+}
+
 // NewServiceConfig creates a new service configuration with defaults.
 func NewServiceConfig() *ServiceConfig {
 	return &ServiceConfig{
@@ -68,6 +101,7 @@ func NewServiceConfig() *ServiceConfig {
 		HTTPPort:          8080,
 		HealthPort:        8081,
 		MetricsPort:       9091,
+		EnableRecover:     true,
 		DefaultTimeoutMs:  30000,
 		SlowRequestMillis: 1000,
 		ShutdownTimeout:   15 * time.Second,
@@ -146,3 +180,23 @@ func AutoMigrate(db *gorm.DB, logger log.Logger, models []any) error {
 	logger.Info("database auto-migration completed successfully")
 	return nil
 }
+
+// RunMigrations applies the pending versioned migrations in order.
+func RunMigrations(ctx context.Context, db *gorm.DB, logger log.Logger, migrations []storex.Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+	migrator, err := storex.NewMigrator(db, migrations...)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	for _, id := range applied {
+		logger.Info("database migration applied", log.Str("id", id))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("database migrations up to date", log.Int("applied", len(applied)), log.Int("total", len(migrations)))
+	return nil
+}
@@ -2,10 +2,19 @@
 package internal
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.eggybyte.com/egg/configx"
+	"go.eggybyte.com/egg/logx"
+	"go.eggybyte.com/egg/storex"
+	"gorm.io/gorm"
 )
 
 // TestNewServiceConfig tests default service configuration creation.
@@ -268,3 +277,128 @@ func TestServiceConfigWithMetricsConfig(t *testing.T) {
 		t.Error("EnableClient should be false")
 	}
 }
+
+// TestRunMigrations tests that versioned migrations run once and failures surface.
+func TestRunMigrations(t *testing.T) {
+	ctx := context.Background()
+	logger := logx.New()
+	store, err := storex.NewSQLiteStore(filepath.Join(t.TempDir(), "app.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	runs := 0
+	migrations := []storex.Migration{{
+		ID: "001_count",
+		Up: func(tx *gorm.DB) error { runs++; return nil },
+	}}
+	for i := 0; i < 2; i++ {
+		if err := RunMigrations(ctx, store.GetDB(), logger, migrations); err != nil {
+			t.Fatalf("RunMigrations() error = %v", err)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("migration ran %d times, want 1", runs)
+	}
+
+	failing := append(migrations, storex.Migration{
+		ID: "002_fail",
+		Up: func(tx *gorm.DB) error { return errors.New("boom") },
+	})
+	if err := RunMigrations(ctx, store.GetDB(), logger, failing); err == nil {
+		t.Error("RunMigrations() should fail when a migration fails")
+	}
+}
+
+func TestInitializeDatabase_Named(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sqlite := func(file string) *DatabaseConfig {
+		return &DatabaseConfig{Driver: "sqlite", DSN: filepath.Join(dir, file)}
+	}
+
+	config := NewServiceConfig()
+	config.DBConfig = sqlite("main.db")
+	config.NamedDBConfigs = []NamedDatabaseConfig{
+		{Name: "billing", Config: sqlite("billing.db")},
+		{Name: "audit", Config: sqlite("audit.db")},
+	}
+	r, _ := NewServiceRuntime(config)
+	r.logger = logx.New()
+
+	if err := r.initializeDatabase(ctx); err != nil {
+		t.Fatalf("initializeDatabase() error = %v", err)
+	}
+	defer func() {
+		r.store.Close()
+		for _, store := range r.namedStores {
+			store.Close()
+		}
+	}()
+
+	if r.db == nil || r.namedDBs["billing"] == nil || r.namedDBs["audit"] == nil {
+		t.Fatalf("databases not opened: default=%v named=%v", r.db, r.namedDBs)
+	}
+	if r.namedDBs["billing"] == r.db || r.namedDBs["billing"] == r.namedDBs["audit"] {
+		t.Error("named databases should be separate connections")
+	}
+
+	// Pool metrics are labelled with the database name
+	config.ServiceName = "orders"
+	config.EnableMetrics = true
+	config.MetricsConfig = &MetricsConfig{EnableDB: true}
+	if err := r.initializeObservability(ctx); err != nil || r.otelProvider == nil {
+		t.Fatalf("initializeObservability() error = %v", err)
+	}
+	defer r.otelProvider.Shutdown(ctx)
+	w := httptest.NewRecorder()
+	r.otelProvider.PrometheusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, name := range []string{"orders", "billing", "audit"} {
+		if !strings.Contains(w.Body.String(), `db_pool_max_open{db_name="`+name+`"`) {
+			t.Errorf("metrics output missing pool metrics of %s", name)
+		}
+	}
+
+	// Every database gates readiness
+	if err := r.readiness.Check(ctx); err != nil {
+		t.Fatalf("readiness.Check() error = %v", err)
+	}
+	sqlDB, _ := r.namedDBs["audit"].DB()
+	sqlDB.Close()
+	err := r.readiness.Check(ctx)
+	if err == nil || !strings.Contains(err.Error(), "database:audit") {
+		t.Errorf("readiness.Check() error = %v, want database:audit failure", err)
+	}
+}
+
+func TestInitializeDatabase_NamedValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		named []NamedDatabaseConfig
+	}{
+		{"empty name", []NamedDatabaseConfig{{Config: &DatabaseConfig{Driver: "sqlite"}}}},
+		{"missing config", []NamedDatabaseConfig{{Name: "billing"}}},
+		{"duplicate name", []NamedDatabaseConfig{
+			{Name: "billing", Config: &DatabaseConfig{Driver: "sqlite", DSN: filepath.Join(t.TempDir(), "a.db")}},
+			{Name: "billing", Config: &DatabaseConfig{Driver: "sqlite", DSN: filepath.Join(t.TempDir(), "b.db")}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewServiceConfig()
+			config.NamedDBConfigs = tt.named
+			r, _ := NewServiceRuntime(config)
+			r.logger = logx.New()
+
+			err := r.initializeDatabase(context.Background())
+			for _, store := range r.namedStores {
+				store.Close()
+			}
+			if err == nil {
+				t.Error("initializeDatabase() should fail")
+			}
+		})
+	}
+}
@@ -2,25 +2,38 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 )
 
+// ErrCircularDependency is returned when resolving a type requires that type
+// again through its own dependency chain.
+var ErrCircularDependency = errors.New("circular dependency detected")
+
 // Container is a simple dependency injection container.
+//
+// Constructors registered with Provide/ProvideTyped are singletons: they run at
+// most once, on first resolution, and the instance is shared by every later
+// Resolve, even when Resolve is called concurrently. Constructors registered
+// with ProvideTransient run on every resolution of their type.
 type Container struct {
 	mu           sync.RWMutex
 	constructors map[reflect.Type]reflect.Value
+	transient    map[reflect.Type]bool
 	instances    map[reflect.Type]reflect.Value
-	building     map[reflect.Type]bool
+	buildLocks   map[reflect.Type]*sync.Mutex
 }
 
 // NewContainer creates a new DI container.
 func NewContainer() *Container {
 	return &Container{
 		constructors: make(map[reflect.Type]reflect.Value),
+		transient:    make(map[reflect.Type]bool),
 		instances:    make(map[reflect.Type]reflect.Value),
-		building:     make(map[reflect.Type]bool),
+		buildLocks:   make(map[reflect.Type]*sync.Mutex),
 	}
 }
 
@@ -34,6 +47,21 @@ func (c *Container) Provide(constructor any) error {
 	return c.ProvideTyped(constructor)
 }
 
+// ProvideTransient registers a constructor that builds a fresh instance on every
+// resolution instead of caching a singleton.
+//
+// Singletons that depend on a transient type receive one instance when they are
+// built and keep it; transient instances may freely depend on singletons.
+//
+// Parameters:
+//   - constructor: Function that returns one value (and optionally an error)
+//
+// Returns:
+//   - error: nil on success; descriptive error if validation fails
+func (c *Container) ProvideTransient(constructor any) error {
+	return c.register("ProvideTransient", constructor, true)
+}
+
 // Resolve resolves a dependency and stores it in the provided pointer.
 func (c *Container) Resolve(target any) error {
 	targetValue := reflect.ValueOf(target)
@@ -52,49 +80,120 @@ func (c *Container) Resolve(target any) error {
 	return nil
 }
 
-// getInstance gets or creates an instance of the given type.
+// getInstance validates the dependency graph of typ and returns its instance.
+//
+// Cycles are rejected before any constructor runs, so building can hold a
+// per-type lock for the duration of a singleton's construction without risk of
+// deadlock.
 func (c *Container) getInstance(typ reflect.Type) (reflect.Value, error) {
+	if err := c.checkCycles(typ, nil); err != nil {
+		return reflect.Value{}, err
+	}
+	return c.build(typ)
+}
+
+// checkCycles walks the constructor graph from typ without building anything.
+//
+// path holds the types on the current chain, outermost first. Seeing typ on the
+// path again means the constructors form a cycle, which is reported with the
+// full chain (e.g. "*A -> *B -> *A"). Already-built singletons end the walk;
+// missing constructors are left for build to report.
+func (c *Container) checkCycles(typ reflect.Type, path []reflect.Type) error {
+	for i, t := range path {
+		if t == typ {
+			cycle := append(append([]reflect.Type(nil), path[i:]...), typ)
+			return fmt.Errorf("%w: %s", ErrCircularDependency, formatDependencyPath(cycle))
+		}
+	}
+
+	c.mu.RLock()
+	_, built := c.instances[typ]
+	constructor, ok := c.constructors[typ]
+	c.mu.RUnlock()
+
+	if built || !ok {
+		return nil
+	}
+
+	path = append(path, typ)
+	constructorType := constructor.Type()
+	for i := 0; i < constructorType.NumIn(); i++ {
+		if err := c.checkCycles(constructorType.In(i), path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build returns the cached singleton for typ or constructs a new instance.
+// Concurrent first resolutions of a singleton wait on a per-type lock so the
+// constructor runs exactly once.
+func (c *Container) build(typ reflect.Type) (reflect.Value, error) {
 	c.mu.RLock()
 	// Check if already built
 	if instance, ok := c.instances[typ]; ok {
 		c.mu.RUnlock()
 		return instance, nil
 	}
+	constructor, ok := c.constructors[typ]
+	transient := c.transient[typ]
+	c.mu.RUnlock()
 
-	// Check for circular dependency
-	if c.building[typ] {
-		c.mu.RUnlock()
-		return reflect.Value{}, fmt.Errorf("circular dependency detected for type %s", typ)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("no constructor registered for type %s", typ)
 	}
+
+	if transient {
+		return c.construct(constructor)
+	}
+
+	lock := c.buildLock(typ)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Another resolution may have built it while we waited
+	c.mu.RLock()
+	instance, ok := c.instances[typ]
 	c.mu.RUnlock()
+	if ok {
+		return instance, nil
+	}
+
+	instance, err := c.construct(constructor)
+	if err != nil {
+		return reflect.Value{}, err
+	}
 
-	// Mark as building
+	// Cache instance
 	c.mu.Lock()
-	c.building[typ] = true
+	c.instances[typ] = instance
 	c.mu.Unlock()
 
-	defer func() {
-		c.mu.Lock()
-		delete(c.building, typ)
-		c.mu.Unlock()
-	}()
+	return instance, nil
+}
 
-	// Get constructor
-	c.mu.RLock()
-	constructor, ok := c.constructors[typ]
-	c.mu.RUnlock()
+// buildLock returns the lock serializing construction of a singleton type.
+func (c *Container) buildLock(typ reflect.Type) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	lock, ok := c.buildLocks[typ]
 	if !ok {
-		return reflect.Value{}, fmt.Errorf("no constructor registered for type %s", typ)
+		lock = &sync.Mutex{}
+		c.buildLocks[typ] = lock
 	}
+	return lock
+}
 
+// construct resolves the constructor's parameters and calls it.
+func (c *Container) construct(constructor reflect.Value) (reflect.Value, error) {
 	// Build dependencies
 	constructorType := constructor.Type()
 	args := make([]reflect.Value, constructorType.NumIn())
 
 	for i := 0; i < constructorType.NumIn(); i++ {
 		paramType := constructorType.In(i)
-		paramInstance, err := c.getInstance(paramType)
+		paramInstance, err := c.build(paramType)
 		if err != nil {
 			return reflect.Value{}, fmt.Errorf("failed to resolve dependency %s: %w", paramType, err)
 		}
@@ -111,14 +210,16 @@ func (c *Container) getInstance(typ reflect.Type) (reflect.Value, error) {
 		}
 	}
 
-	instance := results[0]
-
-	// Cache instance
-	c.mu.Lock()
-	c.instances[typ] = instance
-	c.mu.Unlock()
+	return results[0], nil
+}
 
-	return instance, nil
+// formatDependencyPath renders a dependency chain as "A -> B -> A".
+func formatDependencyPath(path []reflect.Type) string {
+	names := make([]string, len(path))
+	for i, t := range path {
+		names[i] = t.String()
+	}
+	return strings.Join(names, " -> ")
 }
 
 // ProvideTyped is a convenience wrapper around Provide with improved error messages.
@@ -130,31 +231,37 @@ func (c *Container) getInstance(typ reflect.Type) (reflect.Value, error) {
 // Returns:
 //   - error: nil on success; descriptive error if validation fails
 func (c *Container) ProvideTyped(constructor any) error {
-	constructorValue := reflect.ValueOf(constructor)
-	constructorType := constructorValue.Type()
+	return c.register("ProvideTyped", constructor, false)
+}
 
-	if constructorType.Kind() != reflect.Func {
-		return fmt.Errorf("ProvideTyped: constructor must be a function, got %T", constructor)
+// register validates a constructor and registers it for its return type,
+// replacing any earlier registration for that type.
+func (c *Container) register(op string, constructor any, transient bool) error {
+	constructorValue := reflect.ValueOf(constructor)
+	if !constructorValue.IsValid() || constructorValue.Kind() != reflect.Func {
+		return fmt.Errorf("%s: constructor must be a function, got %T", op, constructor)
 	}
+	constructorType := constructorValue.Type()
 
 	if constructorType.NumOut() == 0 || constructorType.NumOut() > 2 {
-		return fmt.Errorf("ProvideTyped: constructor must return 1 or 2 values (got %d), signature: %s", constructorType.NumOut(), constructorType.String())
+		return fmt.Errorf("%s: constructor must return 1 or 2 values (got %d), signature: %s", op, constructorType.NumOut(), constructorType.String())
 	}
 
 	// Check if second return value is error
 	if constructorType.NumOut() == 2 {
 		errorInterface := reflect.TypeOf((*error)(nil)).Elem()
 		if !constructorType.Out(1).Implements(errorInterface) {
-			return fmt.Errorf("ProvideTyped: constructor's second return value must be error, got %s", constructorType.Out(1).String())
+			return fmt.Errorf("%s: constructor's second return value must be error, got %s", op, constructorType.Out(1).String())
 		}
 	}
 
 	returnType := constructorType.Out(0)
-	
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	
+
 	c.constructors[returnType] = constructorValue
+	c.transient[returnType] = transient
 	return nil
 }
 
@@ -179,7 +286,7 @@ func (c *Container) ProvideTyped(constructor any) error {
 func ResolveTyped[T any](c *Container) (T, error) {
 	var zero T
 	targetType := reflect.TypeOf((*T)(nil)).Elem()
-	
+
 	instance, err := c.getInstance(targetType)
 	if err != nil {
 		return zero, err
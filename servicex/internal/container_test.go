@@ -0,0 +1,182 @@
+// Package internal provides tests for the servicex DI container.
+package internal
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cycleA struct{ b *cycleB }
+type cycleB struct{ a *cycleA }
+type cycleC struct{}
+type cycleRoot struct{ a *cycleA }
+
+func TestContainer_Resolve(t *testing.T) {
+	c := NewContainer()
+	calls := 0
+	if err := c.Provide(func() *cycleC { calls++; return &cycleC{} }); err != nil {
+		t.Fatalf("Provide() error = %v", err)
+	}
+
+	var first, second *cycleC
+	if err := c.Resolve(&first); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if err := c.Resolve(&second); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if first != second || calls != 1 {
+		t.Errorf("expected a single cached instance, constructor called %d times", calls)
+	}
+}
+
+func TestContainer_Resolve_Cycle(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func(b *cycleB) *cycleA { return &cycleA{b: b} })
+	c.Provide(func(a *cycleA) *cycleB { return &cycleB{a: a} })
+
+	var a *cycleA
+	err := c.Resolve(&a)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("Resolve() error = %v, want ErrCircularDependency", err)
+	}
+
+	want := "circular dependency detected: *internal.cycleA -> *internal.cycleB -> *internal.cycleA"
+	if err.Error() != want {
+		t.Errorf("Resolve() error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestContainer_Resolve_CycleBelowRoot(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func(a *cycleA) *cycleRoot { return &cycleRoot{a: a} })
+	c.Provide(func(b *cycleB) *cycleA { return &cycleA{b: b} })
+	c.Provide(func(a *cycleA) *cycleB { return &cycleB{a: a} })
+
+	_, err := ResolveTyped[*cycleRoot](c)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("ResolveTyped() error = %v, want ErrCircularDependency", err)
+	}
+	// The path names only the cycle, not the type that led into it
+	if strings.Contains(err.Error(), "cycleRoot") {
+		t.Errorf("cycle path should start at the repeated type, got %q", err.Error())
+	}
+}
+
+func TestContainer_Resolve_SelfDependency(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func(c *cycleC) *cycleC { return c })
+
+	_, err := ResolveTyped[*cycleC](c)
+	if err == nil || !strings.HasSuffix(err.Error(), "*internal.cycleC -> *internal.cycleC") {
+		t.Errorf("ResolveTyped() error = %v, want self-cycle path", err)
+	}
+}
+
+func TestContainer_Resolve_Concurrent(t *testing.T) {
+	c := NewContainer()
+	c.Provide(func() *cycleC { return &cycleC{} })
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := ResolveTyped[*cycleC](c); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Concurrent resolution of the same type must not be mistaken for a cycle
+	for err := range errs {
+		t.Errorf("ResolveTyped() error = %v", err)
+	}
+}
+
+type transientDep struct{ id int }
+type singletonUser struct{ dep *transientDep }
+
+func TestContainer_ProvideTransient(t *testing.T) {
+	c := NewContainer()
+	next := 0
+	if err := c.ProvideTransient(func() *transientDep { next++; return &transientDep{id: next} }); err != nil {
+		t.Fatalf("ProvideTransient() error = %v", err)
+	}
+	c.Provide(func(d *transientDep) *singletonUser { return &singletonUser{dep: d} })
+
+	first, _ := ResolveTyped[*transientDep](c)
+	second, _ := ResolveTyped[*transientDep](c)
+	if first == second || first.id == second.id {
+		t.Errorf("transient resolved the same instance twice (id %d)", first.id)
+	}
+
+	// The singleton captures one transient instance and keeps it
+	u1, _ := ResolveTyped[*singletonUser](c)
+	u2, _ := ResolveTyped[*singletonUser](c)
+	if u1 != u2 || u1.dep != u2.dep {
+		t.Error("singleton should be built once with a single transient dependency")
+	}
+	if next != 3 {
+		t.Errorf("transient constructor called %d times, want 3", next)
+	}
+}
+
+func TestContainer_ProvideTransient_Invalid(t *testing.T) {
+	c := NewContainer()
+	if err := c.ProvideTransient("not a function"); err == nil {
+		t.Error("ProvideTransient() should reject non-functions")
+	}
+	if err := c.ProvideTransient(nil); err == nil {
+		t.Error("ProvideTransient() should reject nil")
+	}
+}
+
+func TestContainer_SingletonBuiltOnceConcurrently(t *testing.T) {
+	c := NewContainer()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c.Provide(func() *cycleC {
+		calls.Add(1)
+		<-release // hold construction open so other resolutions pile up
+		return &cycleC{}
+	})
+
+	const n = 20
+	results := make(chan *cycleC, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := ResolveTyped[*cycleC](c)
+			if err != nil {
+				t.Errorf("ResolveTyped() error = %v", err)
+			}
+			results <- v
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("singleton constructor called %d times, want 1", got)
+	}
+	var first *cycleC
+	for v := range results {
+		if first == nil {
+			first = v
+		} else if v != first {
+			t.Fatal("concurrent resolutions returned different singleton instances")
+		}
+	}
+}
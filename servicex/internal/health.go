@@ -11,6 +11,13 @@ import (
 
 // SetupHealthEndpoints registers health check endpoints on the given mux.
 func SetupHealthEndpoints(mux *http.ServeMux, logger log.Logger) {
+	SetupHealthEndpointsWithReadiness(mux, logger, nil)
+}
+
+// SetupHealthEndpointsWithReadiness registers health check endpoints on the given mux.
+// The /ready endpoint additionally requires all checks in readiness to pass;
+// readiness may be nil.
+func SetupHealthEndpointsWithReadiness(mux *http.ServeMux, logger log.Logger, readiness *Readiness) {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -36,7 +43,11 @@ func SetupHealthEndpoints(mux *http.ServeMux, logger log.Logger) {
 		}
 
 		ctx := r.Context()
-		if err := runtimex.CheckHealth(ctx); err != nil {
+		err := runtimex.CheckHealth(ctx)
+		if err == nil && readiness != nil {
+			err = readiness.Check(ctx)
+		}
+		if err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			fmt.Fprintf(w, `{"status":"not_ready","error":"%s"}`, err.Error())
 			return
@@ -11,7 +11,16 @@ import (
 // BuildInterceptors creates the default Connect interceptors based on options.
 // enableDebugLogs determines whether to log request/response bodies (verbose logging).
 func BuildInterceptors(logger log.Logger, otel *obsx.Provider, slowRequestMillis int64, enableDebugLogs, payloadAccounting bool) []connect.Interceptor {
+	return BuildInterceptorsWithTimeout(logger, otel, slowRequestMillis, enableDebugLogs, payloadAccounting, true, nil)
+}
+
+// BuildInterceptorsWithTimeout is like BuildInterceptors but reads the default RPC
+// timeout from timeoutMs on every request. A nil timeoutMs uses the connectx default.
+// enableRecover controls the panic-recovery interceptor at the head of the chain.
+func BuildInterceptorsWithTimeout(logger log.Logger, otel *obsx.Provider, slowRequestMillis int64, enableDebugLogs, payloadAccounting, enableRecover bool, timeoutMs func() int64) []connect.Interceptor {
 	connectxOpts := connectx.Options{
+		DisableRecover:    !enableRecover,
+		TimeoutFunc:       timeoutMs,
 		Logger:            logger,
 		Otel:              otel,
 		WithRequestBody:   enableDebugLogs,
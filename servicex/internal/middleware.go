@@ -0,0 +1,16 @@
+// Package internal provides internal implementation details for servicex.
+package internal
+
+import "net/http"
+
+// ChainMiddleware wraps handler with the given middleware.
+// The first middleware is the outermost: it sees the request first and the
+// response last. Nil entries are skipped.
+func ChainMiddleware(handler http.Handler, middleware []func(http.Handler) http.Handler) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			handler = middleware[i](handler)
+		}
+	}
+	return handler
+}
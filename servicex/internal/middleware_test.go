@@ -0,0 +1,43 @@
+// Package internal provides tests for HTTP middleware chaining.
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestChainMiddleware tests that middleware run outermost-first.
+func TestChainMiddleware(t *testing.T) {
+	var order []string
+	mw := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name+":before")
+				next.ServeHTTP(w, r)
+				order = append(order, name+":after")
+			})
+		}
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+
+	chained := ChainMiddleware(handler, []func(http.Handler) http.Handler{mw("cors"), nil, mw("auth")})
+	chained.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := "cors:before,auth:before,handler,auth:after,cors:after"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("execution order = %s, want %s", got, want)
+	}
+}
+
+// TestChainMiddleware_Empty tests that no middleware returns the handler unchanged.
+func TestChainMiddleware_Empty(t *testing.T) {
+	mux := http.NewServeMux()
+	if got := ChainMiddleware(mux, nil); got != mux {
+		t.Error("ChainMiddleware() with no middleware should return the handler as-is")
+	}
+}
@@ -0,0 +1,68 @@
+// Package internal provides internal implementation details for servicex.
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ports holds the listen ports of the service's servers.
+type Ports struct {
+	HTTP    int // Connect/HTTP server port
+	Health  int // Health check server port
+	Metrics int // Prometheus metrics server port (only served when metrics are enabled)
+}
+
+// PortOverrides holds explicitly configured ports. Empty fields keep the
+// env-derived or default port.
+type PortOverrides struct {
+	HTTP    string
+	Health  string
+	Metrics string
+}
+
+// Ports returns the ports currently configured.
+func (c *ServiceConfig) Ports() Ports {
+	return Ports{HTTP: c.HTTPPort, Health: c.HealthPort, Metrics: c.MetricsPort}
+}
+
+// applyPortOverrides replaces the configured ports with the explicit overrides.
+// Overrides take precedence over HTTP_PORT/HEALTH_PORT/METRICS_PORT.
+func (c *ServiceConfig) applyPortOverrides() error {
+	overrides := []struct {
+		name  string
+		value string
+		port  *int
+	}{
+		{"http", c.PortOverrides.HTTP, &c.HTTPPort},
+		{"health", c.PortOverrides.Health, &c.HealthPort},
+		{"metrics", c.PortOverrides.Metrics, &c.MetricsPort},
+	}
+
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		port, err := ParsePort(o.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s port: %w", o.name, err)
+		}
+		*o.port = port
+	}
+	return nil
+}
+
+// ParsePort parses a port given as ":8080" or "8080".
+// Port 0 lets the OS pick a free port. Returns an error outside 0-65535.
+func ParsePort(s string) (int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), ":")
+	port, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a port number", s)
+	}
+	if port < 0 || port > 65535 {
+		return 0, fmt.Errorf("%q is out of range 0-65535", s)
+	}
+	return port, nil
+}
@@ -0,0 +1,59 @@
+// Package internal provides tests for servicex port configuration.
+package internal
+
+import "testing"
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{":8080", 8080, false},
+		{"8080", 8080, false},
+		{" :9090 ", 9090, false},
+		{"0", 0, false},
+		{"", 0, true},
+		{"http", 0, true},
+		{":70000", 0, true},
+		{"-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePort(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePort(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePort(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPortOverrides(t *testing.T) {
+	cfg := NewServiceConfig()
+	cfg.HTTPPort = 18080 // as if derived from HTTP_PORT
+	cfg.PortOverrides = PortOverrides{Health: ":18081", Metrics: "19090"}
+
+	if err := cfg.applyPortOverrides(); err != nil {
+		t.Fatalf("applyPortOverrides() error = %v", err)
+	}
+
+	want := Ports{HTTP: 18080, Health: 18081, Metrics: 19090}
+	if got := cfg.Ports(); got != want {
+		t.Errorf("Ports() = %+v, want %+v", got, want)
+	}
+}
+
+func TestApplyPortOverrides_Invalid(t *testing.T) {
+	cfg := NewServiceConfig()
+	cfg.PortOverrides = PortOverrides{Metrics: "metrics"}
+
+	if err := cfg.applyPortOverrides(); err == nil {
+		t.Fatal("applyPortOverrides() should fail for invalid port")
+	}
+	if cfg.MetricsPort != 9091 {
+		t.Errorf("MetricsPort = %d after failed override, want default 9091", cfg.MetricsPort)
+	}
+}
@@ -0,0 +1,73 @@
+// Package internal provides internal implementation details for servicex.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReadinessCheck is a named probe that must pass before the service reports ready.
+type ReadinessCheck struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+// Readiness aggregates readiness checks for the /ready endpoint.
+// It is safe for concurrent use.
+type Readiness struct {
+	mu     sync.RWMutex
+	checks []ReadinessCheck
+}
+
+// NewReadiness creates a readiness aggregator with the given checks.
+func NewReadiness(checks ...ReadinessCheck) *Readiness {
+	return &Readiness{checks: append([]ReadinessCheck(nil), checks...)}
+}
+
+// Add registers an additional readiness check.
+func (r *Readiness) Add(name string, check func(context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, ReadinessCheck{Name: name, Check: check})
+}
+
+// Check runs all readiness checks in registration order.
+// Returns nil if all pass, otherwise the first failure annotated with the check name.
+func (r *Readiness) Check(ctx context.Context) error {
+	r.mu.RLock()
+	checks := make([]ReadinessCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	for _, c := range checks {
+		if err := c.Check(ctx); err != nil {
+			return fmt.Errorf("readiness check %s failed: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Wait polls Check every interval until all checks pass or timeout elapses.
+// Returns the last check error if readiness was not reached in time.
+func (r *Readiness) Wait(ctx context.Context, timeout, interval time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := r.Check(waitCtx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("service not ready after %s: %w", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
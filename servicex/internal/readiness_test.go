@@ -0,0 +1,102 @@
+// Package internal provides tests for readiness gating.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.eggybyte.com/egg/logx"
+)
+
+// TestReadiness_Check tests that the first failing check is reported by name.
+func TestReadiness_Check(t *testing.T) {
+	readiness := NewReadiness(ReadinessCheck{Name: "cache", Check: func(context.Context) error { return nil }})
+	if err := readiness.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	readiness.Add("database", func(context.Context) error { return errors.New("connection refused") })
+	err := readiness.Check(context.Background())
+	if err == nil {
+		t.Fatal("Check() should fail when a check fails")
+	}
+	if !strings.Contains(err.Error(), "database") {
+		t.Errorf("Check() error = %q, want it to name the failing check", err.Error())
+	}
+}
+
+// TestReadiness_Wait tests waiting until checks start passing.
+func TestReadiness_Wait(t *testing.T) {
+	var calls atomic.Int32
+	readiness := NewReadiness(ReadinessCheck{Name: "database", Check: func(context.Context) error {
+		if calls.Add(1) < 3 {
+			return errors.New("still connecting")
+		}
+		return nil
+	}})
+
+	if err := readiness.Wait(context.Background(), time.Second, time.Millisecond); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("check called %d times, want 3", calls.Load())
+	}
+}
+
+// TestReadiness_WaitTimeout tests that Wait gives up after the timeout.
+func TestReadiness_WaitTimeout(t *testing.T) {
+	readiness := NewReadiness(ReadinessCheck{Name: "database", Check: func(context.Context) error {
+		return errors.New("still connecting")
+	}})
+
+	err := readiness.Wait(context.Background(), 20*time.Millisecond, time.Millisecond)
+	if err == nil {
+		t.Fatal("Wait() should fail when readiness is never reached")
+	}
+	if !strings.Contains(err.Error(), "still connecting") {
+		t.Errorf("Wait() error = %q, want last check error", err.Error())
+	}
+}
+
+// TestReadyEndpoint_ReadinessGating tests that /ready reflects readiness checks.
+func TestReadyEndpoint_ReadinessGating(t *testing.T) {
+	var ready atomic.Bool
+	readiness := NewReadiness(ReadinessCheck{Name: "database", Check: func(context.Context) error {
+		if !ready.Load() {
+			return errors.New("not connected")
+		}
+		return nil
+	}})
+
+	mux := http.NewServeMux()
+	SetupHealthEndpointsWithReadiness(mux, logx.New(), readiness)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("GET /ready returned %d before ready, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(w.Body.String(), "not_ready") {
+		t.Errorf("GET /ready body = %q, want not_ready status", w.Body.String())
+	}
+
+	// Liveness is unaffected by readiness
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/live", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /live returned %d, want %d", w.Code, http.StatusOK)
+	}
+
+	ready.Store(true)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /ready returned %d after ready, want %d", w.Code, http.StatusOK)
+	}
+}
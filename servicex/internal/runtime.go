@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"connectrpc.com/connect"
@@ -18,6 +20,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// readinessPollInterval is how often readiness is re-checked while waiting for startup.
+const readinessPollInterval = 200 * time.Millisecond
+
 // ServiceRuntime manages the service lifecycle and components.
 type ServiceRuntime struct {
 	config        *ServiceConfig
@@ -26,6 +31,12 @@ type ServiceRuntime struct {
 	otelProvider  *obsx.Provider
 	db            *gorm.DB
 	store         storex.GORMStore
+	namedDBs      map[string]*gorm.DB
+	namedStores   []storex.GORMStore
+	readiness     *Readiness
+	timeoutMs     atomic.Int64
+	unsubscribe   []func()
+	workers       *WorkerGroup
 	httpServer    *http.Server
 	healthServer  *http.Server
 	metricsServer *http.Server
@@ -34,10 +45,13 @@ type ServiceRuntime struct {
 
 // NewServiceRuntime creates a new service runtime instance.
 func NewServiceRuntime(config *ServiceConfig) (*ServiceRuntime, error) {
-	return &ServiceRuntime{
+	r := &ServiceRuntime{
 		config:        config,
+		readiness:     NewReadiness(config.ReadinessChecks...),
 		shutdownHooks: []func(context.Context) error{},
-	}, nil
+	}
+	r.timeoutMs.Store(config.DefaultTimeoutMs)
+	return r, nil
 }
 
 // Run starts the service with all components.
@@ -64,6 +78,11 @@ func (r *ServiceRuntime) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Explicit ports win over env-derived ones
+	if err := r.config.applyPortOverrides(); err != nil {
+		return err
+	}
+
 	// Initialize database if configured
 	if err := r.initializeDatabase(ctx); err != nil {
 		return err
@@ -87,17 +106,45 @@ func (r *ServiceRuntime) Run(ctx context.Context) error {
 		}
 	}
 
+	// Run pre-start hooks
+	for i, hook := range r.config.PreStartHooks {
+		if err := hook(app); err != nil {
+			return fmt.Errorf("pre-start hook %d failed: %w", i, err)
+		}
+	}
+
 	// Start servers
 	if err := r.startServers(ctx, app); err != nil {
 		return err
 	}
+	if r.config.StartupSummary {
+		r.reportStartupSummary(app)
+	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
+	// Start background workers; a failing worker cancels runCtx
+	runCtx, workers := StartWorkers(ctx, r.config.Workers, r.logger)
+	r.workers = workers
+
+	// Optionally wait until readiness checks pass before reporting started
+	if r.config.ReadinessWaitTimeout > 0 {
+		r.logger.Info("waiting for readiness", "timeout", r.config.ReadinessWaitTimeout.String())
+		if err := r.readiness.Wait(runCtx, r.config.ReadinessWaitTimeout, readinessPollInterval); err != nil && runCtx.Err() == nil {
+			r.logger.Error(err, "readiness not reached, shutting down")
+			r.gracefulShutdown(app)
+			return err
+		}
+	}
+	r.logger.Info("service started", "service", r.config.ServiceName)
+
+	// Wait for context cancellation or a worker failure
+	<-runCtx.Done()
 	r.logger.Info("shutting down service")
 
 	// Perform graceful shutdown
-	return r.gracefulShutdown(app)
+	if err := r.gracefulShutdown(app); err != nil {
+		return err
+	}
+	return workers.Err()
 }
 
 // preBindBaseConfig performs lightweight pre-binding of critical BaseConfig fields.
@@ -195,6 +242,8 @@ func (r *ServiceRuntime) initializeConfig(ctx context.Context) error {
 	r.configMgr = configMgr
 	r.logger.Info("configuration loaded", "keys", len(configMgr.Snapshot()))
 
+	r.initializeTimeoutFromConfig()
+
 	// Extract port configuration from BaseConfig if available
 	if baseGetter, ok := r.config.Config.(interface {
 		GetHTTPPort() string
@@ -235,31 +284,96 @@ func (r *ServiceRuntime) initializeConfig(ctx context.Context) error {
 	return nil
 }
 
-// initializeDatabase initializes the database connection and performs migrations.
-func (r *ServiceRuntime) initializeDatabase(ctx context.Context) error {
-	if r.config.DBConfig == nil {
-		return nil
+// initializeTimeoutFromConfig applies the default RPC timeout read from the bound
+// config and re-reads it whenever the config manager publishes an update.
+//
+// Updates are bound into a fresh copy of the config struct so the struct handed
+// to the application is never mutated concurrently.
+func (r *ServiceRuntime) initializeTimeoutFromConfig() {
+	getter := r.config.TimeoutFromConfig
+	if getter == nil {
+		return
 	}
 
-	r.logger.Info("initializing database", "driver", r.config.DBConfig.Driver)
+	if ms := getter(r.config.Config); ms > 0 {
+		r.timeoutMs.Store(ms)
+	}
+	r.logger.Info("default RPC timeout loaded from config", "timeout_ms", r.timeoutMs.Load())
+
+	configType := reflect.TypeOf(r.config.Config)
+	if configType.Kind() != reflect.Ptr {
+		return
+	}
+
+	unsubscribe := r.configMgr.OnUpdate(func(map[string]string) {
+		fresh := reflect.New(configType.Elem()).Interface()
+		if err := r.configMgr.Bind(fresh); err != nil {
+			r.logger.Error(err, "failed to re-read RPC timeout from updated config")
+			return
+		}
+		ms := getter(fresh)
+		if ms <= 0 {
+			return
+		}
+		if old := r.timeoutMs.Swap(ms); old != ms {
+			r.logger.Info("default RPC timeout updated", "old_timeout_ms", old, "timeout_ms", ms)
+		}
+	})
+	r.unsubscribe = append(r.unsubscribe, unsubscribe)
+}
+
+// DefaultTimeoutMs returns the default RPC timeout currently in effect.
+func (r *ServiceRuntime) DefaultTimeoutMs() int64 {
+	return r.timeoutMs.Load()
+}
+
+// initializeDatabase initializes the database connections and performs
+// migrations on the default database.
+func (r *ServiceRuntime) initializeDatabase(ctx context.Context) error {
+	if err := r.initializeDefaultDatabase(ctx); err != nil {
+		return err
+	}
+	return r.initializeNamedDatabases(ctx)
+}
 
+// openDatabase opens a GORM store and pings it.
+func (r *ServiceRuntime) openDatabase(ctx context.Context, cfg *DatabaseConfig) (storex.GORMStore, error) {
 	store, err := storex.NewGORMStore(storex.GORMOptions{
-		DSN:             r.config.DBConfig.DSN,
-		Driver:          r.config.DBConfig.Driver,
-		MaxIdleConns:    r.config.DBConfig.MaxIdleConns,
-		MaxOpenConns:    r.config.DBConfig.MaxOpenConns,
-		ConnMaxLifetime: r.config.DBConfig.ConnMaxLifetime,
+		DSN:             cfg.DSN,
+		Driver:          cfg.Driver,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		MaxOpenConns:    cfg.MaxOpenConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
 		Logger:          r.logger,
 	})
 	if err != nil {
-		return fmt.Errorf("database init failed: %w", err)
+		return nil, fmt.Errorf("database init failed: %w", err)
 	}
 
-	pingCtx, cancel := context.WithTimeout(ctx, r.config.DBConfig.PingTimeout)
+	pingTimeout := cfg.PingTimeout
+	if pingTimeout <= 0 {
+		pingTimeout = 5 * time.Second
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
 	defer cancel()
 	if err := store.Ping(pingCtx); err != nil {
 		store.Close()
-		return fmt.Errorf("database ping failed: %w", err)
+		return nil, fmt.Errorf("database ping failed: %w", err)
+	}
+	return store, nil
+}
+
+// initializeDefaultDatabase opens the database returned by App.DB.
+func (r *ServiceRuntime) initializeDefaultDatabase(ctx context.Context) error {
+	if r.config.DBConfig == nil {
+		return nil
+	}
+
+	r.logger.Info("initializing database", "driver", r.config.DBConfig.Driver)
+
+	store, err := r.openDatabase(ctx, r.config.DBConfig)
+	if err != nil {
+		return err
 	}
 
 	r.db = store.GetDB()
@@ -272,6 +386,9 @@ func (r *ServiceRuntime) initializeDatabase(ctx context.Context) error {
 			return fmt.Errorf("auto-migration failed: %w", err)
 		}
 	}
+	if err := RunMigrations(ctx, r.db, r.logger, r.config.Migrations); err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
+	}
 
 	// Register store in health check registry
 	registry := storex.NewRegistry()
@@ -279,9 +396,72 @@ func (r *ServiceRuntime) initializeDatabase(ctx context.Context) error {
 		return fmt.Errorf("failed to register database health check: %w", err)
 	}
 
+	// A configured database gates readiness automatically
+	r.readiness.Add("database", registry.Ping)
+
+	return nil
+}
+
+// initializeNamedDatabases opens the databases returned by App.NamedDB. Each
+// one gates readiness under "database:<name>". Migrations only run on the
+// default database.
+func (r *ServiceRuntime) initializeNamedDatabases(ctx context.Context) error {
+	if len(r.config.NamedDBConfigs) == 0 {
+		return nil
+	}
+
+	r.namedDBs = make(map[string]*gorm.DB, len(r.config.NamedDBConfigs))
+	for _, named := range r.config.NamedDBConfigs {
+		if named.Name == "" {
+			return fmt.Errorf("named database: name is required")
+		}
+		if named.Config == nil {
+			return fmt.Errorf("named database %q: config is required", named.Name)
+		}
+		if _, ok := r.namedDBs[named.Name]; ok {
+			return fmt.Errorf("named database %q: duplicate name", named.Name)
+		}
+
+		r.logger.Info("initializing database", "name", named.Name, "driver", named.Config.Driver)
+
+		store, err := r.openDatabase(ctx, named.Config)
+		if err != nil {
+			return fmt.Errorf("named database %q: %w", named.Name, err)
+		}
+		r.namedStores = append(r.namedStores, store)
+		r.namedDBs[named.Name] = store.GetDB()
+
+		check := "database:" + named.Name
+		registry := storex.NewRegistry()
+		if err := registry.Register(check, store); err != nil {
+			return fmt.Errorf("failed to register database %q health check: %w", named.Name, err)
+		}
+		r.readiness.Add(check, registry.Ping)
+	}
 	return nil
 }
 
+// registerDBMetrics registers pool and query metrics of db under name, their
+// db_name label.
+func (r *ServiceRuntime) registerDBMetrics(name string, db *gorm.DB) {
+	if err := storex.EnableQueryMetrics(db, storex.QueryMetricsOptions{
+		Name:          name,
+		MeterProvider: r.otelProvider.MeterProvider(),
+		OnSlowQuery: func(ctx context.Context, q storex.SlowQuery) {
+			r.logger.Warn("slow database query",
+				"db_name", name,
+				"operation", q.Operation,
+				"table", q.Table,
+				"sql", q.SQL,
+				"duration_ms", q.Duration.Milliseconds())
+		},
+	}); err != nil {
+		r.logger.Error(err, "failed to enable database metrics", "db_name", name)
+		return
+	}
+	r.logger.Info("database metrics enabled", "db_name", name)
+}
+
 // initializeObservability initializes metrics provider.
 func (r *ServiceRuntime) initializeObservability(ctx context.Context) error {
 	// Skip if metrics is disabled
@@ -319,14 +499,13 @@ func (r *ServiceRuntime) initializeObservability(ctx context.Context) error {
 			}
 		}
 
-		if r.config.MetricsConfig.EnableDB && r.db != nil {
-			sqlDB, err := r.db.DB()
-			if err == nil {
-				if err := otelProvider.RegisterDBMetrics(r.config.ServiceName, sqlDB); err != nil {
-					r.logger.Error(err, "failed to register database metrics")
-				} else {
-					r.logger.Info("database metrics enabled")
-				}
+		if r.config.MetricsConfig.EnableDB {
+			// The default database is labelled with the service name
+			if r.db != nil {
+				r.registerDBMetrics(r.config.ServiceName, r.db)
+			}
+			for _, named := range r.config.NamedDBConfigs {
+				r.registerDBMetrics(named.Name, r.namedDBs[named.Name])
 			}
 		}
 	}
@@ -339,12 +518,14 @@ func (r *ServiceRuntime) buildApp() (*App, error) {
 	mux := http.NewServeMux()
 
 	// Build default interceptors
-	interceptors := BuildInterceptors(
+	interceptors := BuildInterceptorsWithTimeout(
 		r.logger,
 		r.otelProvider,
 		r.config.SlowRequestMillis,
 		r.config.EnableDebug,
 		true, // payloadAccounting enabled by default
+		r.config.EnableRecover,
+		r.timeoutMs.Load,
 	)
 
 	// Read internal token from environment
@@ -363,8 +544,11 @@ func (r *ServiceRuntime) buildApp() (*App, error) {
 		Container:     NewContainer(),
 		ShutdownHooks: []func(context.Context) error{},
 		DB:            r.db,
+		NamedDBs:      r.namedDBs,
 		InternalToken: internalToken,
 		Config:        r.config.Config,
+		ConfigManager: r.configMgr,
+		Ports:         r.config.Ports(),
 	}
 
 	return app, nil
@@ -374,12 +558,13 @@ func (r *ServiceRuntime) buildApp() (*App, error) {
 func (r *ServiceRuntime) startServers(ctx context.Context, app *App) error {
 	// Create separate health check mux
 	healthMux := http.NewServeMux()
-	SetupHealthEndpoints(healthMux, r.logger)
+	SetupHealthEndpointsWithReadiness(healthMux, r.logger, r.readiness)
 
 	// Start servers
 	httpAddr := fmt.Sprintf(":%d", r.config.HTTPPort)
 	healthAddr := fmt.Sprintf(":%d", r.config.HealthPort)
-	r.httpServer = &http.Server{Addr: httpAddr, Handler: app.Mux}
+	handler := ChainMiddleware(app.Mux, r.config.HTTPMiddleware)
+	r.httpServer = &http.Server{Addr: httpAddr, Handler: handler}
 	r.healthServer = &http.Server{Addr: healthAddr, Handler: healthMux}
 
 	go func() {
@@ -450,6 +635,11 @@ func (r *ServiceRuntime) gracefulShutdown(app *App) error {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.config.ShutdownTimeout)
 	defer cancel()
 
+	// Stop background workers first so they stop producing work
+	if r.workers != nil {
+		r.workers.Shutdown(shutdownCtx)
+	}
+
 	// Execute shutdown hooks in LIFO order
 	for i := len(app.ShutdownHooks) - 1; i >= 0; i-- {
 		if err := app.ShutdownHooks[i](shutdownCtx); err != nil {
@@ -476,6 +666,16 @@ func (r *ServiceRuntime) gracefulShutdown(app *App) error {
 		}
 	}
 
+	// Stop listening for config updates
+	for _, unsubscribe := range r.unsubscribe {
+		unsubscribe()
+	}
+
+	// Run post-shutdown hooks after servers have drained
+	for _, hook := range r.config.PostShutdownHooks {
+		hook(shutdownCtx)
+	}
+
 	// Shutdown observability
 	if r.otelProvider != nil {
 		if err := r.otelProvider.Shutdown(shutdownCtx); err != nil {
@@ -483,14 +683,24 @@ func (r *ServiceRuntime) gracefulShutdown(app *App) error {
 		}
 	}
 
-	// Close database connection
+	// Close database connections
 	if r.store != nil {
 		if err := r.store.Close(); err != nil {
 			r.logger.Error(err, "database close failed")
 		}
 	}
+	for _, store := range r.namedStores {
+		if err := store.Close(); err != nil {
+			r.logger.Error(err, "database close failed")
+		}
+	}
 
 	r.logger.Info("service stopped")
+
+	// Flush buffered log output, e.g. from logx.WithFileOutput
+	if syncer, ok := r.logger.(interface{ Sync() error }); ok {
+		_ = syncer.Sync()
+	}
 	return nil
 }
 
@@ -503,6 +713,10 @@ type App struct {
 	Container     *Container
 	ShutdownHooks []func(context.Context) error
 	DB            *gorm.DB
+	NamedDBs      map[string]*gorm.DB // Databases added with WithNamedDatabase
 	InternalToken string
 	Config        any
+	ConfigManager configx.Manager
+	Ports         Ports
+	ConnectPaths  []string // Paths mounted by RegisterConnectHandler(s)
 }
@@ -0,0 +1,93 @@
+// Package internal provides internal implementation details for servicex.
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StartupSummary describes what came up once a service has started.
+type StartupSummary struct {
+	Service      string       `json:"service"`
+	Version      string       `json:"version"`
+	Ports        SummaryPorts `json:"ports"`
+	Metrics      []string     `json:"metrics"`             // Enabled metric types, empty when metrics are off
+	DBDriver     string       `json:"db_driver,omitempty"` // Empty without a database
+	ConnectPaths []string     `json:"connect_paths"`       // Connect services in registration order
+}
+
+// SummaryPorts lists the ports the servers listen on; Metrics is 0 when no
+// metrics server runs.
+type SummaryPorts struct {
+	HTTP    int `json:"http"`
+	Health  int `json:"health"`
+	Metrics int `json:"metrics,omitempty"`
+}
+
+// startupSummary collects the summary of the started runtime.
+func (r *ServiceRuntime) startupSummary(app *App) StartupSummary {
+	summary := StartupSummary{
+		Service: r.config.ServiceName,
+		Version: r.config.ServiceVersion,
+		Ports: SummaryPorts{
+			HTTP:   r.config.HTTPPort,
+			Health: r.config.HealthPort,
+		},
+		Metrics:      []string{},
+		ConnectPaths: append([]string{}, app.ConnectPaths...),
+	}
+
+	if r.metricsServer != nil {
+		summary.Ports.Metrics = r.config.MetricsPort
+	}
+	if r.otelProvider != nil {
+		summary.Metrics = append(summary.Metrics, "rpc")
+		if m := r.config.MetricsConfig; m != nil {
+			for _, kind := range []struct {
+				name    string
+				enabled bool
+			}{
+				{"runtime", m.EnableRuntime},
+				{"process", m.EnableProcess},
+				{"db", m.EnableDB && (r.db != nil || len(r.namedDBs) > 0)},
+				{"client", m.EnableClient},
+			} {
+				if kind.enabled {
+					summary.Metrics = append(summary.Metrics, kind.name)
+				}
+			}
+		}
+	}
+	if r.db != nil && r.config.DBConfig != nil {
+		summary.DBDriver = r.config.DBConfig.Driver
+	}
+	return summary
+}
+
+// reportStartupSummary logs the startup summary as one line and, with a
+// banner writer configured, writes it there as one JSON object per line.
+func (r *ServiceRuntime) reportStartupSummary(app *App) {
+	summary := r.startupSummary(app)
+	r.logger.Info("startup summary",
+		"service", summary.Service,
+		"version", summary.Version,
+		"http_port", summary.Ports.HTTP,
+		"health_port", summary.Ports.Health,
+		"metrics_port", summary.Ports.Metrics,
+		"metrics", strings.Join(summary.Metrics, ","),
+		"db_driver", summary.DBDriver,
+		"connect_paths", strings.Join(summary.ConnectPaths, ","),
+	)
+
+	if r.config.StartupBanner == nil {
+		return
+	}
+	data, err := json.Marshal(summary)
+	if err == nil {
+		_, err = fmt.Fprintf(r.config.StartupBanner, "%s\n", data)
+	}
+	if err != nil {
+		r.logger.Error(err, "failed to write startup banner")
+	}
+}
@@ -0,0 +1,35 @@
+// Package internal provides tests for the servicex startup summary.
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.eggybyte.com/egg/obsx"
+)
+
+func TestStartupSummary_Metrics(t *testing.T) {
+	provider, err := obsx.NewProvider(context.Background(), obsx.Options{ServiceName: "summary-service"})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	config := NewServiceConfig()
+	config.MetricsConfig = &MetricsConfig{EnableRuntime: true, EnableDB: true, EnableClient: true}
+	r := &ServiceRuntime{config: config, otelProvider: provider}
+
+	summary := r.startupSummary(&App{ConnectPaths: []string{"/user.v1.UserService/"}})
+
+	// Database metrics are only reported with a database
+	if got := strings.Join(summary.Metrics, ","); got != "rpc,runtime,client" {
+		t.Errorf("Metrics = %s, want rpc,runtime,client", got)
+	}
+	if summary.Ports.HTTP != 8080 || summary.Ports.Health != 8081 || summary.Ports.Metrics != 0 {
+		t.Errorf("Ports = %+v, want metrics port 0 without a metrics server", summary.Ports)
+	}
+	if len(summary.ConnectPaths) != 1 || summary.DBDriver != "" {
+		t.Errorf("Summary = %+v", summary)
+	}
+}
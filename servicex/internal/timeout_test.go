@@ -0,0 +1,146 @@
+// Package internal provides tests for config-sourced RPC timeouts.
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.eggybyte.com/egg/configx"
+	"go.eggybyte.com/egg/logx"
+)
+
+type timeoutTestConfig struct {
+	RPCTimeoutMs int64 `env:"SERVICEX_TEST_RPC_TIMEOUT_MS" default:"0"`
+}
+
+func rpcTimeoutGetter(cfg any) int64 {
+	return cfg.(*timeoutTestConfig).RPCTimeoutMs
+}
+
+// fakeConfigManager binds a fixed timeout and lets tests publish updates.
+type fakeConfigManager struct {
+	timeoutMs int64
+	subs      []func(map[string]string)
+}
+
+func (m *fakeConfigManager) Snapshot() map[string]string     { return map[string]string{} }
+func (m *fakeConfigManager) Value(key string) (string, bool) { return "", false }
+func (m *fakeConfigManager) Bind(target any, opts ...configx.BindOption) error {
+	target.(*timeoutTestConfig).RPCTimeoutMs = m.timeoutMs
+	return nil
+}
+func (m *fakeConfigManager) OnUpdate(fn func(map[string]string)) func() {
+	m.subs = append(m.subs, fn)
+	return func() { m.subs = nil }
+}
+
+func (m *fakeConfigManager) publish(timeoutMs int64) {
+	m.timeoutMs = timeoutMs
+	for _, fn := range m.subs {
+		fn(map[string]string{})
+	}
+}
+
+// deadlineFromInterceptors runs a request through the interceptor chain and
+// returns the time remaining until the context deadline seen by the handler.
+func deadlineFromInterceptors(t *testing.T, interceptors []connect.Interceptor) time.Duration {
+	t.Helper()
+
+	var remaining time.Duration
+	var next connect.UnaryFunc = func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("handler context should carry a deadline")
+		}
+		remaining = time.Until(deadline)
+		return connect.NewResponse(&struct{}{}), nil
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next = interceptors[i].WrapUnary(next)
+	}
+
+	if _, err := next(context.Background(), connect.NewRequest(&struct{}{})); err != nil {
+		t.Fatalf("unary call error = %v", err)
+	}
+	return remaining
+}
+
+// TestTimeoutFromConfig tests that the configured timeout is used by the interceptors.
+func TestTimeoutFromConfig(t *testing.T) {
+	t.Setenv("SERVICEX_TEST_RPC_TIMEOUT_MS", "1500")
+
+	cfg := NewServiceConfig()
+	cfg.Config = &timeoutTestConfig{}
+	cfg.TimeoutFromConfig = rpcTimeoutGetter
+
+	r, err := NewServiceRuntime(cfg)
+	if err != nil {
+		t.Fatalf("NewServiceRuntime() error = %v", err)
+	}
+	r.logger = logx.New()
+
+	if err := r.initializeConfig(context.Background()); err != nil {
+		t.Fatalf("initializeConfig() error = %v", err)
+	}
+	if got := r.DefaultTimeoutMs(); got != 1500 {
+		t.Fatalf("DefaultTimeoutMs() = %d, want 1500", got)
+	}
+
+	interceptors := BuildInterceptorsWithTimeout(r.logger, nil, 1000, false, false, true, r.timeoutMs.Load)
+	remaining := deadlineFromInterceptors(t, interceptors)
+	if remaining <= time.Second || remaining > 1500*time.Millisecond {
+		t.Errorf("handler deadline in %v, want about 1.5s", remaining)
+	}
+}
+
+// TestTimeoutFromConfig_Reload tests that config updates change the timeout in effect.
+func TestTimeoutFromConfig_Reload(t *testing.T) {
+	cfg := NewServiceConfig()
+	cfg.Config = &timeoutTestConfig{RPCTimeoutMs: 2000}
+	cfg.TimeoutFromConfig = rpcTimeoutGetter
+
+	r, _ := NewServiceRuntime(cfg)
+	r.logger = logx.New()
+	mgr := &fakeConfigManager{}
+	r.configMgr = mgr
+
+	r.initializeTimeoutFromConfig()
+	if got := r.DefaultTimeoutMs(); got != 2000 {
+		t.Fatalf("DefaultTimeoutMs() = %d, want 2000", got)
+	}
+
+	mgr.publish(250)
+	if got := r.DefaultTimeoutMs(); got != 250 {
+		t.Errorf("DefaultTimeoutMs() after reload = %d, want 250", got)
+	}
+
+	// The application's config struct is not mutated by reloads
+	if got := cfg.Config.(*timeoutTestConfig).RPCTimeoutMs; got != 2000 {
+		t.Errorf("bound config RPCTimeoutMs = %d, want 2000", got)
+	}
+
+	// Non-positive values keep the current timeout
+	mgr.publish(0)
+	if got := r.DefaultTimeoutMs(); got != 250 {
+		t.Errorf("DefaultTimeoutMs() after zero update = %d, want 250", got)
+	}
+}
+
+// TestTimeoutFromConfig_Fallback tests that WithTimeout applies without a usable config value.
+func TestTimeoutFromConfig_Fallback(t *testing.T) {
+	cfg := NewServiceConfig()
+	cfg.DefaultTimeoutMs = 5000
+	cfg.Config = &timeoutTestConfig{}
+	cfg.TimeoutFromConfig = rpcTimeoutGetter
+
+	r, _ := NewServiceRuntime(cfg)
+	r.logger = logx.New()
+	r.configMgr = &fakeConfigManager{}
+
+	r.initializeTimeoutFromConfig()
+	if got := r.DefaultTimeoutMs(); got != 5000 {
+		t.Errorf("DefaultTimeoutMs() = %d, want 5000", got)
+	}
+}
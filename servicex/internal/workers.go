@@ -0,0 +1,128 @@
+// Package internal provides internal implementation details for servicex.
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.eggybyte.com/egg/core/log"
+	"go.eggybyte.com/egg/runtimex"
+)
+
+// Worker is a background task that shares the service lifecycle.
+type Worker struct {
+	Name string
+	// Run blocks until ctx is cancelled or the worker fails.
+	Run func(ctx context.Context) error
+	// Stop is called during shutdown after ctx is cancelled (optional).
+	Stop func(ctx context.Context) error
+}
+
+// ServiceWorker adapts a runtimex.Service to a Worker.
+// Start is called on startup; Stop is called during graceful shutdown.
+func ServiceWorker(name string, svc runtimex.Service) Worker {
+	return Worker{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			if err := svc.Start(ctx); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return nil
+		},
+		Stop: svc.Stop,
+	}
+}
+
+// WorkerGroup runs workers under a shared context.
+// The first worker error cancels the group context so the service shuts down.
+type WorkerGroup struct {
+	workers []Worker
+	logger  log.Logger
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// StartWorkers starts each worker in its own goroutine.
+// A worker returning a non-nil error (or panicking) cancels the context
+// returned alongside the group, triggering a coordinated shutdown.
+func StartWorkers(ctx context.Context, workers []Worker, logger log.Logger) (context.Context, *WorkerGroup) {
+	groupCtx, cancel := context.WithCancel(ctx)
+	g := &WorkerGroup{workers: workers, logger: logger, cancel: cancel}
+
+	for _, w := range workers {
+		g.wg.Add(1)
+		go g.run(groupCtx, w)
+	}
+
+	return groupCtx, g
+}
+
+func (g *WorkerGroup) run(ctx context.Context, w Worker) {
+	defer g.wg.Done()
+
+	g.logger.Info("worker started", log.Str("worker", w.Name))
+	err := safeRun(ctx, w.Run)
+
+	if err != nil && !(errors.Is(err, context.Canceled) && ctx.Err() != nil) {
+		g.logger.Error(err, "worker failed, shutting down service", log.Str("worker", w.Name))
+		g.mu.Lock()
+		if g.err == nil {
+			g.err = fmt.Errorf("worker %s failed: %w", w.Name, err)
+		}
+		g.mu.Unlock()
+		g.cancel()
+		return
+	}
+
+	g.logger.Info("worker stopped", log.Str("worker", w.Name))
+}
+
+// safeRun calls run and converts a panic into an error.
+func safeRun(ctx context.Context, run func(context.Context) error) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return run(ctx)
+}
+
+// Err returns the first worker error, if any.
+func (g *WorkerGroup) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+// Shutdown cancels the workers, calls their Stop functions and waits for them
+// to return or for ctx to expire.
+func (g *WorkerGroup) Shutdown(ctx context.Context) {
+	g.cancel()
+
+	for _, w := range g.workers {
+		if w.Stop == nil {
+			continue
+		}
+		if err := w.Stop(ctx); err != nil {
+			g.logger.Error(err, "worker stop failed", log.Str("worker", w.Name))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		g.logger.Warn("workers did not stop before shutdown timeout")
+	}
+}
@@ -0,0 +1,108 @@
+// Package internal provides tests for background workers.
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.eggybyte.com/egg/logx"
+)
+
+// TestWorkerGroup_ShutdownCancelsWorkers tests that shutdown cancels worker contexts.
+func TestWorkerGroup_ShutdownCancelsWorkers(t *testing.T) {
+	var stopped atomic.Int32
+	worker := Worker{Name: "loop", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped.Add(1)
+		return ctx.Err()
+	}}
+
+	_, group := StartWorkers(context.Background(), []Worker{worker, worker}, logx.New())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	group.Shutdown(shutdownCtx)
+
+	if stopped.Load() != 2 {
+		t.Errorf("%d workers stopped, want 2", stopped.Load())
+	}
+	if err := group.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil for clean cancellation", err)
+	}
+}
+
+// TestWorkerGroup_FailureCancelsGroup tests that a failing worker triggers shutdown.
+func TestWorkerGroup_FailureCancelsGroup(t *testing.T) {
+	workerErr := errors.New("reconcile failed")
+	failing := Worker{Name: "reconciler", Run: func(ctx context.Context) error {
+		return workerErr
+	}}
+
+	var peerCancelled atomic.Bool
+	peer := Worker{Name: "peer", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		peerCancelled.Store(true)
+		return nil
+	}}
+
+	groupCtx, group := StartWorkers(context.Background(), []Worker{failing, peer}, logx.New())
+
+	select {
+	case <-groupCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("group context should be cancelled after a worker failure")
+	}
+
+	group.Shutdown(context.Background())
+	if !peerCancelled.Load() {
+		t.Error("peer worker should observe cancellation")
+	}
+	if err := group.Err(); !errors.Is(err, workerErr) {
+		t.Errorf("Err() = %v, want wrapped %v", err, workerErr)
+	}
+}
+
+// TestWorkerGroup_Panic tests that a panicking worker is reported as a failure.
+func TestWorkerGroup_Panic(t *testing.T) {
+	worker := Worker{Name: "boom", Run: func(ctx context.Context) error {
+		panic("unexpected")
+	}}
+
+	groupCtx, group := StartWorkers(context.Background(), []Worker{worker}, logx.New())
+	<-groupCtx.Done()
+	group.Shutdown(context.Background())
+
+	if group.Err() == nil {
+		t.Error("Err() should report the panic")
+	}
+}
+
+type fakeRuntimeService struct {
+	started atomic.Bool
+	stopped atomic.Bool
+}
+
+func (s *fakeRuntimeService) Start(ctx context.Context) error { s.started.Store(true); return nil }
+func (s *fakeRuntimeService) Stop(ctx context.Context) error  { s.stopped.Store(true); return nil }
+
+// TestServiceWorker tests the runtimex.Service adapter.
+func TestServiceWorker(t *testing.T) {
+	svc := &fakeRuntimeService{}
+	_, group := StartWorkers(context.Background(), []Worker{ServiceWorker("svc", svc)}, logx.New())
+
+	deadline := time.Now().Add(time.Second)
+	for !svc.started.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !svc.started.Load() {
+		t.Fatal("Start() was not called")
+	}
+
+	group.Shutdown(context.Background())
+	if !svc.stopped.Load() {
+		t.Error("Stop() was not called on shutdown")
+	}
+}
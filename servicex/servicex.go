@@ -35,6 +35,7 @@ package servicex
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
@@ -44,7 +45,9 @@ import (
 	"go.eggybyte.com/egg/configx"
 	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/obsx"
+	"go.eggybyte.com/egg/runtimex"
 	"go.eggybyte.com/egg/servicex/internal"
+	"go.eggybyte.com/egg/storex"
 	"gorm.io/gorm"
 )
 
@@ -57,8 +60,12 @@ type App struct {
 	container     *internal.Container
 	shutdownHooks []func(context.Context) error
 	db            *gorm.DB
+	namedDBs      map[string]*gorm.DB
 	internalToken string
 	config        any
+	configManager configx.Manager
+	ports         Ports
+	connectPaths  []string
 }
 
 // Mux returns the HTTP mux for handler registration.
@@ -77,12 +84,36 @@ func (a *App) OtelProvider() *obsx.Provider { return a.otel }
 //
 // This is a convenience wrapper around ProvideTyped for backward compatibility.
 // Prefer ProvideTyped for better error messages.
+//
+// The constructor is a singleton: it runs once, on first resolution, and every
+// Resolve returns the same instance, including under concurrent resolution.
 func (a *App) Provide(constructor any) error { return a.container.Provide(constructor) }
 
+// ProvideTransient registers a constructor that runs on every resolution, so each
+// Resolve returns a fresh instance. Use it for per-request or stateful helpers.
+//
+// A singleton that depends on a transient type receives one instance when it is
+// built and keeps it.
+//
+// Usage:
+//
+//	err := app.ProvideTransient(func(db *gorm.DB) *UnitOfWork {
+//	    return NewUnitOfWork(db)
+//	})
+func (a *App) ProvideTransient(constructor any) error {
+	return a.container.ProvideTransient(constructor)
+}
+
+// ErrCircularDependency is returned (wrapped) by Resolve, MustResolve and
+// ResolveTyped when constructors depend on each other. The error message names
+// the cycle, e.g. "circular dependency detected: *A -> *B -> *A".
+var ErrCircularDependency = internal.ErrCircularDependency
+
 // Resolve resolves a dependency from the DI container.
 //
 // This stores the resolved instance in the provided pointer.
 // Prefer ResolveTyped for type-safe resolution without type assertions.
+// Cyclic constructors are reported as ErrCircularDependency.
 func (a *App) Resolve(target any) error { return a.container.Resolve(target) }
 
 // ProvideTyped registers a constructor in the DI container with improved error messages.
@@ -300,6 +331,10 @@ func (a *App) MustDB() *gorm.DB {
 	return a.db
 }
 
+// NamedDB returns the GORM database added with WithNamedDatabase under
+// name, or nil if there is none.
+func (a *App) NamedDB(name string) *gorm.DB { return a.namedDBs[name] }
+
 // InternalToken returns the configured internal token from environment.
 // Returns empty string if INTERNAL_TOKEN is not set.
 func (a *App) InternalToken() string { return a.internalToken }
@@ -315,6 +350,33 @@ func (a *App) InternalToken() string { return a.internalToken }
 //	}
 func (a *App) Config() any { return a.config }
 
+// ConfigManager returns the live configuration manager that bound the struct
+// passed to WithConfig. Use it to read current values or subscribe to hot updates
+// at runtime. Returns nil if no config was provided.
+//
+// Usage:
+//
+//	if mgr := app.ConfigManager(); mgr != nil {
+//	    unsubscribe := mgr.OnUpdate(func(snapshot map[string]string) {
+//	        logger.Info("config updated", "keys", len(snapshot))
+//	    })
+//	    app.AddShutdownHook(func(context.Context) error { unsubscribe(); return nil })
+//	}
+func (a *App) ConfigManager() configx.Manager { return a.configManager }
+
+// Ports describes the listen ports of the service's servers.
+type Ports = internal.Ports
+
+// Ports returns the ports the service listens on, after applying WithPorts over
+// HTTP_PORT/HEALTH_PORT/METRICS_PORT and the defaults (8080/8081/9091).
+// The metrics port is reported even when metrics are disabled.
+//
+// Usage:
+//
+//	ports := app.Ports()
+//	logger.Info("serving", "http", ports.HTTP, "metrics", ports.Metrics)
+func (a *App) Ports() Ports { return a.ports }
+
 // RegisterConnectHandler registers a Connect service handler with automatic interceptor injection.
 //
 // This is a convenience method that simplifies Connect handler registration by automatically
@@ -343,10 +405,67 @@ func (a *App) Config() any { return a.config }
 func (a *App) RegisterConnectHandler(handler any, newHandler func(handler any, opts ...connect.HandlerOption) (string, http.Handler)) error {
 	path, connectHandler := newHandler(handler, connect.WithInterceptors(a.interceptors...))
 	a.mux.Handle(path, connectHandler)
+	a.connectPaths = append(a.connectPaths, path)
 	a.logger.Info("registered Connect handler", "path", path)
 	return nil
 }
 
+// ConnectRegistration mounts one Connect service using the handler options supplied
+// by RegisterConnectHandlers. Build registrations with ConnectHandler.
+type ConnectRegistration func(opts ...connect.HandlerOption) (string, http.Handler)
+
+// ConnectHandler captures a handler implementation and its generated constructor
+// (e.g., userv1connect.NewUserServiceHandler) as a ConnectRegistration.
+//
+// Usage:
+//
+//	servicex.ConnectHandler(userHandler, userv1connect.NewUserServiceHandler)
+func ConnectHandler[T any](handler T, newHandler func(T, ...connect.HandlerOption) (string, http.Handler)) ConnectRegistration {
+	return func(opts ...connect.HandlerOption) (string, http.Handler) {
+		return newHandler(handler, opts...)
+	}
+}
+
+// RegisterConnectHandlers mounts several Connect services on the app mux.
+//
+// The configured interceptors are applied to every registration and each mounted
+// path is logged. Registration stops at the first nil registration or duplicate path.
+//
+// Parameters:
+//   - registrations: Services to mount, typically built with ConnectHandler
+//
+// Returns:
+//   - error: nil on success; error describing the failed registration
+//
+// Usage:
+//
+//	err := app.RegisterConnectHandlers(
+//	    servicex.ConnectHandler(userHandler, userv1connect.NewUserServiceHandler),
+//	    servicex.ConnectHandler(orderHandler, orderv1connect.NewOrderServiceHandler),
+//	)
+func (a *App) RegisterConnectHandlers(registrations ...ConnectRegistration) error {
+	opts := connect.WithInterceptors(a.interceptors...)
+	seen := make(map[string]bool, len(registrations))
+
+	for i, register := range registrations {
+		if register == nil {
+			return fmt.Errorf("connect registration %d is nil", i)
+		}
+
+		path, connectHandler := register(opts)
+		if seen[path] {
+			return fmt.Errorf("connect handler path %s registered more than once", path)
+		}
+		seen[path] = true
+
+		a.mux.Handle(path, connectHandler)
+		a.connectPaths = append(a.connectPaths, path)
+		a.logger.Info("registered Connect handler", "path", path)
+	}
+
+	return nil
+}
+
 // Option is a functional option for configuring the service.
 type Option func(*internal.ServiceConfig)
 
@@ -427,25 +546,189 @@ func WithMetricsConfig(runtime, process, db, client bool) Option {
 // WithRegister sets the service registration function.
 func WithRegister(fn func(*App) error) Option {
 	return func(c *internal.ServiceConfig) {
-		c.RegisterFn = func(app interface{}) error {
-			// Convert internal.App to servicex.App
-			internalApp := app.(*internal.App)
-			servicexApp := &App{
-				mux:           internalApp.Mux,
-				logger:        internalApp.Logger,
-				interceptors:  internalApp.Interceptors,
-				otel:          internalApp.OtelProvider,
-				container:     internalApp.Container,
-				shutdownHooks: internalApp.ShutdownHooks,
-				db:            internalApp.DB,
-				internalToken: internalApp.InternalToken,
-				config:        internalApp.Config,
-			}
-			err := fn(servicexApp)
-			// Copy shutdown hooks back to internal app after registration
-			internalApp.ShutdownHooks = servicexApp.shutdownHooks
-			return err
+		c.RegisterFn = withApp(fn)
+	}
+}
+
+// WithPreStart registers a hook that runs after service registration and before
+// the servers start listening. Hooks run in registration order; an error aborts
+// startup and is returned from Run. Typical uses are warming caches or verifying
+// that migrations have been applied.
+func WithPreStart(fn func(*App) error) Option {
+	return func(c *internal.ServiceConfig) {
+		c.PreStartHooks = append(c.PreStartHooks, withApp(fn))
+	}
+}
+
+// WithPostShutdown registers a hook that runs after the servers have fully drained.
+//
+// Shutdown order:
+//  1. Background workers are cancelled and stopped
+//  2. Hooks added via App.AddShutdownHook (LIFO)
+//  3. Metrics, health and HTTP servers drain
+//  4. Post-shutdown hooks (registration order)
+//  5. Observability provider shutdown and database close
+//
+// The context carries the remaining shutdown timeout.
+func WithPostShutdown(fn func(context.Context)) Option {
+	return func(c *internal.ServiceConfig) {
+		c.PostShutdownHooks = append(c.PostShutdownHooks, fn)
+	}
+}
+
+// WithHTTPMiddleware wraps the whole HTTP mux with net/http middleware, so plain
+// HTTP routes get the same treatment (CORS, security headers, auth) as Connect handlers.
+//
+// Middleware are applied in the order given, across repeated calls: the first is
+// the outermost and sees each request first. They run before routing and therefore
+// before any Connect interceptor. Health and metrics servers are not wrapped.
+//
+// Example:
+//
+//	servicex.WithHTTPMiddleware(
+//	    httpx.CORSMiddleware(corsOpts),
+//	    httpx.SecureMiddleware(httpx.DefaultSecurityHeaders()),
+//	    authMiddleware,
+//	)
+func WithHTTPMiddleware(mw ...func(http.Handler) http.Handler) Option {
+	return func(c *internal.ServiceConfig) {
+		c.HTTPMiddleware = append(c.HTTPMiddleware, mw...)
+	}
+}
+
+// WithWorker registers a background worker that shares the service lifecycle,
+// such as a periodic reconciliation loop. Workers start after the servers and
+// run until their context is cancelled at shutdown, which happens before
+// shutdown hooks run. fn should return promptly once ctx is done.
+//
+// A worker returning a non-nil error (other than the context's own cancellation)
+// or panicking triggers a coordinated shutdown, and Run returns that error.
+// Returning nil before shutdown simply ends the worker.
+//
+// Example:
+//
+//	servicex.WithWorker(func(ctx context.Context) error {
+//	    ticker := time.NewTicker(time.Minute)
+//	    defer ticker.Stop()
+//	    for {
+//	        select {
+//	        case <-ctx.Done():
+//	            return nil
+//	        case <-ticker.C:
+//	            if err := reconcile(ctx); err != nil {
+//	                return err
+//	            }
+//	        }
+//	    }
+//	})
+func WithWorker(fn func(ctx context.Context) error) Option {
+	return func(c *internal.ServiceConfig) {
+		c.Workers = append(c.Workers, internal.Worker{
+			Name: fmt.Sprintf("worker_%d", len(c.Workers)),
+			Run:  fn,
+		})
+	}
+}
+
+// WithRuntimeService runs a runtimex.Service alongside the HTTP server.
+// Start is called with the worker context after the servers start; a Start error
+// triggers a coordinated shutdown. Stop is called during graceful shutdown with
+// the shutdown timeout context.
+func WithRuntimeService(svc runtimex.Service) Option {
+	return func(c *internal.ServiceConfig) {
+		c.Workers = append(c.Workers, internal.ServiceWorker(fmt.Sprintf("worker_%d", len(c.Workers)), svc))
+	}
+}
+
+// WithReadinessCheck registers a check that must pass before the /ready endpoint
+// reports ready. Checks run in registration order on every readiness probe and
+// should be fast and honor the context deadline. A database configured via
+// WithDatabase or BaseConfig contributes a readiness check automatically.
+//
+// Example:
+//
+//	servicex.WithReadinessCheck(func(ctx context.Context) error {
+//	    return cache.Ping(ctx)
+//	})
+func WithReadinessCheck(fn func(context.Context) error) Option {
+	return func(c *internal.ServiceConfig) {
+		c.ReadinessChecks = append(c.ReadinessChecks, internal.ReadinessCheck{
+			Name:  fmt.Sprintf("check_%d", len(c.ReadinessChecks)),
+			Check: fn,
+		})
+	}
+}
+
+// WithStartupSummary logs one line summarizing the started service once
+// registration completes and the servers are started: service name and
+// version, ports, enabled metric types, database driver, and the Connect
+// paths registered with RegisterConnectHandler(s). Ops tooling can parse
+// this single line to know exactly what came up.
+//
+// If banner is non-nil (e.g. os.Stdout), the summary is also written to it
+// as one JSON object on its own line:
+//
+//	{"service":"user-service","version":"1.0.0","ports":{"http":8080,"health":8081,"metrics":9091},"metrics":["rpc","runtime"],"db_driver":"mysql","connect_paths":["/user.v1.UserService/"]}
+func WithStartupSummary(banner io.Writer) Option {
+	return func(c *internal.ServiceConfig) {
+		c.StartupSummary = true
+		c.StartupBanner = banner
+	}
+}
+
+// WithReadinessWait makes Run wait up to timeout for all readiness checks to pass
+// after the servers start, before logging that the service has started. If the
+// timeout elapses first, the service shuts down and Run returns the last check error.
+// A zero timeout (the default) disables waiting.
+func WithReadinessWait(timeout time.Duration) Option {
+	return func(c *internal.ServiceConfig) {
+		c.ReadinessWaitTimeout = timeout
+	}
+}
+
+// withApp adapts a function on *App to the internal hook signature.
+// Shutdown hooks and Connect paths added by fn are copied back to the internal app.
+func withApp(fn func(*App) error) func(interface{}) error {
+	return func(app interface{}) error {
+		// Convert internal.App to servicex.App
+		internalApp := app.(*internal.App)
+		servicexApp := &App{
+			mux:           internalApp.Mux,
+			logger:        internalApp.Logger,
+			interceptors:  internalApp.Interceptors,
+			otel:          internalApp.OtelProvider,
+			container:     internalApp.Container,
+			shutdownHooks: internalApp.ShutdownHooks,
+			db:            internalApp.DB,
+			namedDBs:      internalApp.NamedDBs,
+			internalToken: internalApp.InternalToken,
+			config:        internalApp.Config,
+			configManager: internalApp.ConfigManager,
+			ports:         internalApp.Ports,
+			connectPaths:  internalApp.ConnectPaths,
 		}
+		err := fn(servicexApp)
+		// Copy shutdown hooks and registered paths back to internal app
+		internalApp.ShutdownHooks = servicexApp.shutdownHooks
+		internalApp.ConnectPaths = servicexApp.connectPaths
+		return err
+	}
+}
+
+// WithPorts sets the HTTP, health and metrics ports explicitly, overriding
+// HTTP_PORT/HEALTH_PORT/METRICS_PORT. Ports are given as ":8080" or "8080";
+// an empty string keeps the env-derived or default port. Invalid ports make
+// Run fail at startup.
+//
+// Example:
+//
+//	servicex.Run(ctx,
+//	    servicex.WithService("user-service", "1.0.0"),
+//	    servicex.WithPorts(":8080", ":8081", ":9090"),
+//	)
+func WithPorts(http, health, metrics string) Option {
+	return func(c *internal.ServiceConfig) {
+		c.PortOverrides = internal.PortOverrides{HTTP: http, Health: health, Metrics: metrics}
 	}
 }
 
@@ -456,6 +739,36 @@ func WithTimeout(timeoutMs int64) Option {
 	}
 }
 
+// WithTimeoutFromConfig sources the default RPC timeout (in milliseconds) from the
+// struct passed to WithConfig instead of a build-time constant, so operators can
+// tune it per environment. getter receives the bound config and returns the timeout;
+// non-positive values are ignored and the WithTimeout value (default 30s) applies.
+//
+// When the config manager publishes an update (e.g. a watched ConfigMap changes),
+// the config is re-bound into a fresh copy and getter is called again; the new
+// timeout applies to subsequent requests.
+//
+// Example:
+//
+//	servicex.WithTimeoutFromConfig(func(cfg any) int64 {
+//	    return cfg.(*AppConfig).RPCTimeoutMs
+//	})
+func WithTimeoutFromConfig(getter func(any) int64) Option {
+	return func(c *internal.ServiceConfig) {
+		c.TimeoutFromConfig = getter
+	}
+}
+
+// WithRecover enables or disables the panic-recovery interceptor (default: true).
+// When enabled, a panic in a unary or streaming handler is logged with its stack
+// through the service logger and the client receives CodeInternal, while the
+// service keeps serving. Disable it only if another layer recovers panics.
+func WithRecover(enabled bool) Option {
+	return func(c *internal.ServiceConfig) {
+		c.EnableRecover = enabled
+	}
+}
+
 // WithSlowRequestThreshold sets the slow request threshold in milliseconds.
 func WithSlowRequestThreshold(millis int64) Option {
 	return func(c *internal.ServiceConfig) {
@@ -528,6 +841,48 @@ func WithDatabase(cfg *DatabaseConfig) Option {
 	}
 }
 
+// WithNamedDatabase adds a database reached with App.NamedDB(name), for
+// services that talk to more than one database. It can be repeated with
+// different names; the database of WithDatabase stays the default returned
+// by App.DB and App.MustDB.
+//
+// Each named database is pinged at startup, gates readiness as a
+// "database:<name>" check, and, with database metrics enabled, registers its
+// pool and query metrics with name as the db_name label. Auto-migration and
+// versioned migrations only run on the default database.
+//
+// Example:
+//
+//	servicex.Run(ctx,
+//	    servicex.WithDatabase(&cfg.Database),
+//	    servicex.WithNamedDatabase("billing", &servicex.DatabaseConfig{
+//	        Driver:      "postgres",
+//	        DSN:         cfg.BillingDSN,
+//	        PingTimeout: 5 * time.Second,
+//	    }),
+//	    servicex.WithRegister(func(app *servicex.App) error {
+//	        billing := app.NamedDB("billing")
+//	        // ...
+//	        return nil
+//	    }),
+//	)
+func WithNamedDatabase(name string, cfg *DatabaseConfig) Option {
+	return func(c *internal.ServiceConfig) {
+		var dbCfg *internal.DatabaseConfig
+		if cfg != nil {
+			dbCfg = &internal.DatabaseConfig{
+				Driver:          cfg.Driver,
+				DSN:             cfg.DSN,
+				MaxIdleConns:    cfg.MaxIdleConns,
+				MaxOpenConns:    cfg.MaxOpenConns,
+				ConnMaxLifetime: cfg.ConnMaxLifetime,
+				PingTimeout:     cfg.PingTimeout,
+			}
+		}
+		c.NamedDBConfigs = append(c.NamedDBConfigs, internal.NamedDatabaseConfig{Name: name, Config: dbCfg})
+	}
+}
+
 // WithAutoMigrate specifies database models to auto-migrate during startup.
 func WithAutoMigrate(models ...any) Option {
 	return func(c *internal.ServiceConfig) {
@@ -535,6 +890,25 @@ func WithAutoMigrate(models ...any) Option {
 	}
 }
 
+// WithMigrations specifies versioned migrations to apply during startup,
+// after WithAutoMigrate models. Unlike AutoMigrate, migrations can move data
+// and drop or rename columns; each pending one runs once, in order, in its
+// own transaction, and is recorded in the schema_migrations table (see
+// storex.Migrator). Startup fails if a migration fails.
+//
+// Example:
+//
+//	servicex.WithMigrations(
+//	    storex.Migration{ID: "20240601_drop_legacy_flag", Up: func(tx *gorm.DB) error {
+//	        return tx.Migrator().DropColumn(&model.Order{}, "legacy_flag")
+//	    }},
+//	)
+func WithMigrations(migrations ...storex.Migration) Option {
+	return func(c *internal.ServiceConfig) {
+		c.Migrations = append(c.Migrations, migrations...)
+	}
+}
+
 // WithAppConfig is a convenience function that combines WithConfig and WithDatabase.
 // It automatically detects database configuration from the provided config struct.
 // This simplifies the common pattern of using BaseConfig with database.
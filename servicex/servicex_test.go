@@ -3,9 +3,16 @@ package servicex
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +20,7 @@ import (
 	"go.eggybyte.com/egg/configx"
 	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/servicex/internal"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gorm.io/gorm"
 )
 
@@ -152,6 +160,64 @@ func TestApp_Interceptors(t *testing.T) {
 	}
 }
 
+// fakeServiceHandler stands in for a generated Connect service handler interface.
+type fakeServiceHandler interface{ Name() string }
+
+type namedHandler string
+
+func (n namedHandler) Name() string { return string(n) }
+
+// newFakeServiceHandler mimics a generated NewXServiceHandler constructor.
+func newFakeServiceHandler(h fakeServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	path := "/" + h.Name() + "/"
+	return path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s opts=%d", h.Name(), len(opts))
+	})
+}
+
+// TestApp_RegisterConnectHandlers tests registering several Connect services at once.
+func TestApp_RegisterConnectHandlers(t *testing.T) {
+	logger := &MockLogger{}
+	app := &App{mux: http.NewServeMux(), logger: logger}
+
+	var registrations []ConnectRegistration
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("svc%d.v1.Service", i)
+		registrations = append(registrations, ConnectHandler[fakeServiceHandler](namedHandler(name), newFakeServiceHandler))
+	}
+
+	if err := app.RegisterConnectHandlers(registrations...); err != nil {
+		t.Fatalf("RegisterConnectHandlers() error = %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("svc%d.v1.Service", i)
+		rec := httptest.NewRecorder()
+		app.Mux().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/"+name+"/Call", nil))
+		if want := name + " opts=1"; rec.Body.String() != want {
+			t.Errorf("response = %q, want %q", rec.Body.String(), want)
+		}
+	}
+
+	if len(logger.infos) != 5 {
+		t.Errorf("logged %d registrations, want 5", len(logger.infos))
+	}
+}
+
+// TestApp_RegisterConnectHandlers_Errors tests nil and duplicate registrations.
+func TestApp_RegisterConnectHandlers_Errors(t *testing.T) {
+	app := &App{mux: http.NewServeMux(), logger: &MockLogger{}}
+	if err := app.RegisterConnectHandlers(nil); err == nil {
+		t.Error("RegisterConnectHandlers() should reject nil registration")
+	}
+
+	app = &App{mux: http.NewServeMux(), logger: &MockLogger{}}
+	reg := ConnectHandler[fakeServiceHandler](namedHandler("dup.v1.Service"), newFakeServiceHandler)
+	if err := app.RegisterConnectHandlers(reg, reg); err == nil {
+		t.Error("RegisterConnectHandlers() should reject duplicate paths")
+	}
+}
+
 func TestDatabaseConfig(t *testing.T) {
 	config := DatabaseConfig{
 		Driver:          "mysql",
@@ -400,6 +466,234 @@ func TestServiceWithShutdownHook(t *testing.T) {
 	}
 }
 
+// TestServiceLifecycleHooks tests pre-start and post-shutdown hook ordering.
+func TestServiceLifecycleHooks(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithRegister(func(app *App) error {
+				record("register")
+				return nil
+			}),
+			WithPreStart(func(app *App) error {
+				record("pre-start")
+				app.AddShutdownHook(func(ctx context.Context) error {
+					record("shutdown-hook")
+					return nil
+				})
+				return nil
+			}),
+			WithPostShutdown(func(ctx context.Context) {
+				record("post-shutdown")
+			}),
+		)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Service did not shut down in time")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"register", "pre-start", "shutdown-hook", "post-shutdown"}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+// TestServicePreStartError tests that a failing pre-start hook aborts startup.
+func TestServicePreStartError(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	expectedErr := fmt.Errorf("cache warm-up failed")
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithPreStart(func(app *App) error {
+				return expectedErr
+			}),
+		)
+	}()
+
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("Run() error = %v, want wrapped %v", err, expectedErr)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Run() should return without waiting for context cancellation")
+	}
+}
+
+// TestServiceReadinessWaitTimeout tests that Run fails when readiness is never reached.
+func TestServiceReadinessWaitTimeout(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checkErr := fmt.Errorf("database still connecting")
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithMetrics(false),
+			WithReadinessCheck(func(ctx context.Context) error {
+				return checkErr
+			}),
+			WithReadinessWait(300*time.Millisecond),
+		)
+	}()
+
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, checkErr) {
+			t.Errorf("Run() error = %v, want wrapped %v", err, checkErr)
+		}
+	case <-time.After(1500 * time.Millisecond):
+		t.Fatal("Run() should return once the readiness wait times out")
+	}
+}
+
+// TestServiceWorkerFailure tests that a failing worker shuts the service down.
+func TestServiceWorkerFailure(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	workerErr := fmt.Errorf("reconcile failed")
+	var peerStopped bool
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithMetrics(false),
+			WithWorker(func(ctx context.Context) error {
+				<-ctx.Done()
+				peerStopped = true
+				return nil
+			}),
+			WithWorker(func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return workerErr
+			}),
+		)
+	}()
+
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, workerErr) {
+			t.Errorf("Run() error = %v, want wrapped %v", err, workerErr)
+		}
+		if !peerStopped {
+			t.Error("peer worker should be stopped during shutdown")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() should return after a worker failure")
+	}
+}
+
+// bannerWriter collects startup banner lines written by the runtime goroutine.
+type bannerWriter struct {
+	lines chan string
+}
+
+func (w *bannerWriter) Write(p []byte) (int, error) {
+	w.lines <- string(p)
+	return len(p), nil
+}
+
+// TestWithStartupSummary tests that the startup banner describes the started service.
+func TestWithStartupSummary(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	banner := &bannerWriter{lines: make(chan string, 1)}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("summary-service", "1.2.3"),
+			WithMetrics(false),
+			WithStartupSummary(banner),
+			WithRegister(func(app *App) error {
+				return app.RegisterConnectHandlers(
+					ConnectHandler(http.NotFoundHandler(), func(h http.Handler, opts ...connect.HandlerOption) (string, http.Handler) {
+						return "/greet.v1.GreeterService/", h
+					}),
+					ConnectHandler(http.NotFoundHandler(), func(h http.Handler, opts ...connect.HandlerOption) (string, http.Handler) {
+						return "/user.v1.UserService/", h
+					}),
+				)
+			}),
+		)
+	}()
+
+	select {
+	case line := <-banner.lines:
+		var summary internal.StartupSummary
+		if err := json.Unmarshal([]byte(line), &summary); err != nil {
+			t.Fatalf("Banner is not JSON: %v\n%s", err, line)
+		}
+		if !strings.HasSuffix(line, "}\n") || strings.Count(line, "\n") != 1 {
+			t.Errorf("Banner should be a single line: %q", line)
+		}
+		if summary.Service != "summary-service" || summary.Version != "1.2.3" {
+			t.Errorf("Service = %s %s", summary.Service, summary.Version)
+		}
+		if len(summary.Metrics) != 0 || summary.Ports.Metrics != 0 || summary.DBDriver != "" {
+			t.Errorf("Metrics and database should be off: %+v", summary)
+		}
+		want := []string{"/greet.v1.GreeterService/", "/user.v1.UserService/"}
+		if strings.Join(summary.ConnectPaths, " ") != strings.Join(want, " ") {
+			t.Errorf("ConnectPaths = %v, want %v", summary.ConnectPaths, want)
+		}
+	case err := <-errChan:
+		t.Fatalf("Run() returned before the banner: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Startup banner not written")
+	}
+
+	cancel()
+	<-errChan
+}
+
 // TestServiceRegistrationError tests error handling during service registration.
 func TestServiceRegistrationError(t *testing.T) {
 	cleanup := setupTestPorts(t)
@@ -539,6 +833,11 @@ func TestServiceAppMethods(t *testing.T) {
 					return fmt.Errorf("DB() should be nil without database config")
 				}
 
+				// Test ConfigManager (should be nil without config)
+				if app.ConfigManager() != nil {
+					return fmt.Errorf("ConfigManager() should be nil without config")
+				}
+
 				return nil
 			}),
 		)
@@ -559,3 +858,226 @@ func TestServiceAppMethods(t *testing.T) {
 		t.Fatal("Service did not complete in time")
 	}
 }
+
+// TestServiceConfigManager tests runtime access to the config manager.
+func TestServiceConfigManager(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+	t.Setenv("SERVICEX_TEST_FEATURE", "enabled")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cfg := &configx.BaseConfig{}
+	var value string
+	var found bool
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithMetrics(false),
+			WithConfig(cfg),
+			WithRegister(func(app *App) error {
+				mgr := app.ConfigManager()
+				if mgr == nil {
+					return fmt.Errorf("ConfigManager() returned nil with config")
+				}
+				value, found = mgr.Value("SERVICEX_TEST_FEATURE")
+				return nil
+			}),
+		)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	if err := <-errChan; err != nil && err != context.Canceled {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !found || value != "enabled" {
+		t.Errorf("Value(SERVICEX_TEST_FEATURE) = %q, %v; want %q, true", value, found, "enabled")
+	}
+}
+
+// TestWithHTTPMiddleware tests that middleware accumulate in registration order.
+func TestWithHTTPMiddleware(t *testing.T) {
+	var order []string
+	mw := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	cfg := internal.NewServiceConfig()
+	WithHTTPMiddleware(mw("cors"), mw("security"))(cfg)
+	WithHTTPMiddleware(mw("auth"))(cfg)
+
+	if len(cfg.HTTPMiddleware) != 3 {
+		t.Fatalf("HTTPMiddleware has %d entries, want 3", len(cfg.HTTPMiddleware))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/ping", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+	handler := internal.ChainMiddleware(mux, cfg.HTTPMiddleware)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+
+	if got := strings.Join(order, ","); got != "cors,security,auth,handler" {
+		t.Errorf("execution order = %s, want cors,security,auth,handler", got)
+	}
+}
+
+// TestWithPorts tests that explicit ports override env-derived ports and are reported by App.Ports.
+func TestWithPorts(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+
+	httpPort, healthPort := freePort(t), freePort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var ports Ports
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithMetrics(false),
+			WithConfig(&configx.BaseConfig{}),
+			WithPorts(fmt.Sprintf(":%d", httpPort), strconv.Itoa(healthPort), ""),
+			WithRegister(func(app *App) error {
+				ports = app.Ports()
+				return nil
+			}),
+		)
+	}()
+
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get(fmt.Sprintf("http://127.0.0.1:%d/health", healthPort))
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("health server not listening on override port %d: %v", healthPort, err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	if err := <-errChan; err != nil && err != context.Canceled {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// METRICS_PORT=0 from the environment is kept because its override is empty
+	want := Ports{HTTP: httpPort, Health: healthPort, Metrics: 0}
+	if ports != want {
+		t.Errorf("Ports() = %+v, want %+v", ports, want)
+	}
+}
+
+// TestWithPorts_Invalid tests that an invalid explicit port fails startup.
+func TestWithPorts_Invalid(t *testing.T) {
+	err := Run(context.Background(),
+		WithService("test-service", "1.0.0"),
+		WithPorts("http", "", ""),
+	)
+	if err == nil || !strings.Contains(err.Error(), "invalid http port") {
+		t.Fatalf("Run() error = %v, want invalid http port", err)
+	}
+}
+
+// freePort returns a TCP port that was free at the time of the call.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// TestWithRecover tests that a panicking handler returns CodeInternal and the service keeps serving.
+func TestWithRecover(t *testing.T) {
+	cleanup := setupTestPorts(t)
+	defer cleanup()
+	httpPort := freePort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const procedure = "/test.v1.PanicService/Panic"
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- Run(ctx,
+			WithService("test-service", "1.0.0"),
+			WithMetrics(false),
+			WithPorts(strconv.Itoa(httpPort), "", ""),
+			WithRecover(true),
+			WithRegister(func(app *App) error {
+				app.Mux().Handle(procedure, connect.NewUnaryHandler(procedure,
+					func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+						if req.Msg.GetValue() == "panic" {
+							panic("handler bug")
+						}
+						return connect.NewResponse(req.Msg), nil
+					},
+					connect.WithInterceptors(app.Interceptors()...),
+				))
+				return nil
+			}),
+		)
+	}()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		http.DefaultClient, fmt.Sprintf("http://127.0.0.1:%d%s", httpPort, procedure))
+
+	var err error
+	for i := 0; i < 50; i++ {
+		_, err = client.CallUnary(ctx, connect.NewRequest(wrapperspb.String("panic")))
+		if connect.CodeOf(err) != connect.CodeUnavailable {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := connect.CodeOf(err); got != connect.CodeInternal {
+		t.Fatalf("panicking call code = %v (err %v), want %v", got, err, connect.CodeInternal)
+	}
+
+	// The service is still up after the panic
+	resp, err := client.CallUnary(ctx, connect.NewRequest(wrapperspb.String("ok")))
+	if err != nil {
+		t.Fatalf("call after panic error = %v", err)
+	}
+	if resp.Msg.GetValue() != "ok" {
+		t.Errorf("call after panic = %q, want %q", resp.Msg.GetValue(), "ok")
+	}
+
+	cancel()
+	if err := <-errChan; err != nil && err != context.Canceled {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// TestWithRecoverDisabled tests that WithRecover(false) removes the recovery interceptor.
+func TestWithRecoverDisabled(t *testing.T) {
+	cfg := internal.NewServiceConfig()
+	if !cfg.EnableRecover {
+		t.Fatal("recover should be enabled by default")
+	}
+	WithRecover(false)(cfg)
+
+	enabled := internal.BuildInterceptorsWithTimeout(&MockLogger{}, nil, 1000, false, false, true, nil)
+	disabled := internal.BuildInterceptorsWithTimeout(&MockLogger{}, nil, 1000, false, false, cfg.EnableRecover, nil)
+	if len(disabled) != len(enabled)-1 {
+		t.Errorf("interceptors with recover disabled = %d, want %d", len(disabled), len(enabled)-1)
+	}
+}
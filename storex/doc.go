@@ -20,6 +20,7 @@
 //	mysql, _ := storex.NewMySQLStore(dsn, logger)
 //	_ = reg.Register("mysql", mysql)
 //	_ = reg.Ping(ctx)
+//	_ = reg.CloseAll(shutdownCtx)
 //
 // # Layer
 //
@@ -3,6 +3,7 @@ package internal
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
@@ -71,6 +72,21 @@ func (s *GORMStore) GetDB() *gorm.DB {
 	return s.db
 }
 
+// Stats returns the connection pool statistics of the database, or zero
+// statistics if there is no open connection.
+func (s *GORMStore) Stats() sql.DBStats {
+	if s.db == nil {
+		return sql.DBStats{}
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+
+	return sqlDB.Stats()
+}
+
 // GORMOptions holds configuration for GORM database connections.
 type GORMOptions struct {
 	DSN             string          // Database connection string
@@ -0,0 +1,274 @@
+// Package internal contains GORM database adapter implementation.
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MigrationsTable records the IDs of applied migrations.
+const MigrationsTable = "schema_migrations"
+
+// MigrationsLockTable holds the lock row serializing migration runs on
+// databases without advisory locks, such as SQLite.
+const MigrationsLockTable = MigrationsTable + "_lock"
+
+// lockPollInterval is how often a run waiting for MigrationsLockTable retries.
+const lockPollInterval = 100 * time.Millisecond
+
+// Migration is a versioned schema or data change.
+type Migration struct {
+	ID   string                  // Unique version, e.g. "20240601_add_orders_status"
+	Up   func(tx *gorm.DB) error // Applies the change (required)
+	Down func(tx *gorm.DB) error // Reverts the change (nil = irreversible)
+}
+
+// schemaMigration is a row of MigrationsTable.
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey;size:255"`
+	AppliedAt time.Time
+}
+
+// TableName implements gorm's Tabler.
+func (schemaMigration) TableName() string { return MigrationsTable }
+
+// migrationLock is the single row of MigrationsLockTable held by a run.
+type migrationLock struct {
+	ID       int `gorm:"primaryKey;autoIncrement:false"`
+	LockedAt time.Time
+}
+
+// TableName implements gorm's Tabler.
+func (migrationLock) TableName() string { return MigrationsLockTable }
+
+// Migrator applies migrations in registration order and records them in
+// MigrationsTable. Every migration runs in its own transaction together with
+// its bookkeeping row, so a failing migration leaves no trace; databases that
+// commit DDL implicitly (MySQL) cannot roll back schema statements, though.
+// Up and Down hold a database-wide lock, so concurrent runs from several
+// instances apply each migration once.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator validates migrations: IDs must be non-empty and unique, and
+// every migration needs an Up function.
+func NewMigrator(db *gorm.DB, migrations []Migration) (*Migrator, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	seen := make(map[string]struct{}, len(migrations))
+	for i, m := range migrations {
+		if m.ID == "" {
+			return nil, fmt.Errorf("migration %d: ID is required", i)
+		}
+		if _, ok := seen[m.ID]; ok {
+			return nil, fmt.Errorf("migration %s: duplicate ID", m.ID)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %s: Up is required", m.ID)
+		}
+		seen[m.ID] = struct{}{}
+	}
+	return &Migrator{db: db, migrations: append([]Migration(nil), migrations...)}, nil
+}
+
+// applied returns the IDs recorded in MigrationsTable. A missing table is
+// created if create is set and means nothing is applied otherwise.
+func (m *Migrator) applied(ctx context.Context, create bool) (map[string]bool, error) {
+	db := m.db.WithContext(ctx)
+	if create {
+		if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", MigrationsTable, err)
+		}
+	} else if !db.Migrator().HasTable(&schemaMigration{}) {
+		return map[string]bool{}, nil
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", MigrationsTable, err)
+	}
+	applied := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		applied[row.ID] = true
+	}
+	return applied, nil
+}
+
+// Pending returns the IDs of migrations not applied yet, in the order Up
+// would apply them. It only reads; without MigrationsTable, every migration
+// is pending.
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	applied, err := m.applied(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if !applied[migration.ID] {
+			pending = append(pending, migration.ID)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration in order and returns the IDs applied.
+// It stops at the first failure; migrations applied before it stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, migration := range m.migrations {
+		if applied[migration.ID] {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: migration.ID, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s failed: %w", migration.ID, err)
+		}
+		done = append(done, migration.ID)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, latest first in
+// registration order, and returns the IDs reverted. Reverting a migration
+// without a Down function fails.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	applied, err := m.applied(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if !applied[migration.ID] {
+			continue
+		}
+		if migration.Down == nil {
+			return done, fmt.Errorf("migration %s is irreversible", migration.ID)
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{ID: migration.ID}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %s failed: %w", migration.ID, err)
+		}
+		done = append(done, migration.ID)
+	}
+	return done, nil
+}
+
+// lock blocks until this run holds the migration lock of the database, or
+// ctx is done, and returns the function releasing it.
+//
+// PostgreSQL and MySQL use session advisory locks, held on a dedicated pool
+// connection and released when it closes, so a crashed run frees the lock.
+// Other databases insert the row of MigrationsLockTable; a crashed run leaves
+// it behind and it must be deleted by hand.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	switch m.db.Dialector.Name() {
+	case "postgres":
+		return m.sessionLock(ctx, func(conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", MigrationsTable)
+			return err
+		}, "SELECT pg_advisory_unlock(hashtext($1))")
+	case "mysql":
+		return m.sessionLock(ctx, func(conn *sql.Conn) error {
+			// GET_LOCK returns 1 once acquired, 0 or NULL on failure
+			var acquired sql.NullInt64
+			if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", MigrationsTable).Scan(&acquired); err != nil {
+				return err
+			}
+			if acquired.Int64 != 1 {
+				return fmt.Errorf("GET_LOCK(%q) was not granted", MigrationsTable)
+			}
+			return nil
+		}, "SELECT RELEASE_LOCK(?)")
+	default:
+		return m.tableLock(ctx)
+	}
+}
+
+// sessionLock takes an advisory lock named after MigrationsTable with acquire
+// on a dedicated connection, which stays checked out of the pool until the
+// lock is released with unlockSQL.
+func (m *Migrator) sessionLock(ctx context.Context, acquire func(conn *sql.Conn) error, unlockSQL string) (func(), error) {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB for migration lock: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if err := acquire(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), unlockSQL, MigrationsTable)
+		conn.Close()
+	}, nil
+}
+
+// tableLock inserts the row of MigrationsLockTable, polling while another run
+// holds it.
+func (m *Migrator) tableLock(ctx context.Context) (func(), error) {
+	db := m.db.WithContext(ctx)
+	if err := db.AutoMigrate(&migrationLock{}); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", MigrationsLockTable, err)
+	}
+
+	for {
+		err := db.Create(&migrationLock{ID: 1, LockedAt: time.Now().UTC()}).Error
+		if err == nil {
+			return func() { m.db.Delete(&migrationLock{ID: 1}) }, nil
+		}
+
+		// Only an existing row means another run holds the lock
+		var held int64
+		if countErr := db.Model(&migrationLock{}).Count(&held).Error; countErr != nil || held == 0 {
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for migration lock in %s: %w", MigrationsLockTable, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
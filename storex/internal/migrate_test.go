@@ -0,0 +1,199 @@
+// Package internal provides tests for storex migrations.
+package internal
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			ID:   "001_create_widgets",
+			Up:   func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&widget{}) },
+			Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&widget{}) },
+		},
+		{
+			ID:   "002_seed_widgets",
+			Up:   func(tx *gorm.DB) error { return tx.Create(&widget{Name: "gear"}).Error },
+			Down: func(tx *gorm.DB) error { return tx.Where("name = ?", "gear").Delete(&widget{}).Error },
+		},
+	}
+}
+
+func TestMigrator_UpAndPending(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	migrator, err := NewMigrator(db, testMigrations())
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if want := []string{"001_create_widgets", "002_seed_widgets"}; !reflect.DeepEqual(pending, want) {
+		t.Errorf("Pending() = %v, want %v", pending, want)
+	}
+	if db.Migrator().HasTable(&widget{}) {
+		t.Fatal("Pending() should not apply migrations")
+	}
+	if db.Migrator().HasTable(MigrationsTable) {
+		t.Fatalf("Pending() should not create %s", MigrationsTable)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if !reflect.DeepEqual(applied, pending) {
+		t.Errorf("Up() = %v, want %v", applied, pending)
+	}
+
+	// A second run is a no-op
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("second Up() = %v, %v; want nothing applied", applied, err)
+	}
+
+	var count int64
+	db.Model(&widget{}).Count(&count)
+	if count != 1 {
+		t.Errorf("widgets = %d, want 1", count)
+	}
+	db.Table(MigrationsTable).Count(&count)
+	if count != 2 {
+		t.Errorf("%s rows = %d, want 2", MigrationsTable, count)
+	}
+}
+
+func TestMigrator_FailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	migrations := append(testMigrations(), Migration{
+		ID: "003_broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Create(&widget{Name: "sprocket"}).Error; err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	})
+	migrator, _ := NewMigrator(db, migrations)
+
+	applied, err := migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("Up() should fail")
+	}
+	if len(applied) != 2 {
+		t.Errorf("Up() applied %v before failing, want the first two", applied)
+	}
+
+	var count int64
+	db.Model(&widget{}).Where("name = ?", "sprocket").Count(&count)
+	if count != 0 {
+		t.Error("failed migration should be rolled back")
+	}
+	if pending, _ := migrator.Pending(ctx); !reflect.DeepEqual(pending, []string{"003_broken"}) {
+		t.Errorf("Pending() = %v, want the failed migration", pending)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	migrator, _ := NewMigrator(db, testMigrations())
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil || !reflect.DeepEqual(reverted, []string{"002_seed_widgets"}) {
+		t.Fatalf("Down(1) = %v, %v", reverted, err)
+	}
+	reverted, err = migrator.Down(ctx, 5)
+	if err != nil || !reflect.DeepEqual(reverted, []string{"001_create_widgets"}) {
+		t.Fatalf("Down(5) = %v, %v", reverted, err)
+	}
+	if db.Migrator().HasTable(&widget{}) {
+		t.Error("widgets table should be dropped")
+	}
+
+	irreversible, _ := NewMigrator(db, []Migration{{ID: "001", Up: func(*gorm.DB) error { return nil }}})
+	irreversible.Up(ctx)
+	if _, err := irreversible.Down(ctx, 1); err == nil {
+		t.Error("Down() of a migration without Down should fail")
+	}
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	db := newTestDB(t)
+	migrator, _ := NewMigrator(db, testMigrations())
+
+	// Another run holds the lock
+	unlock, err := migrator.lock(context.Background())
+	if err != nil {
+		t.Fatalf("lock() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockPollInterval)
+	defer cancel()
+	if _, err := migrator.Up(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Up() while locked error = %v, want deadline exceeded", err)
+	}
+	if db.Migrator().HasTable(&widget{}) {
+		t.Fatal("Up() should not apply migrations without the lock")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := migrator.Up(context.Background())
+		done <- err
+	}()
+	time.Sleep(lockPollInterval)
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatalf("Up() after unlock error = %v", err)
+	}
+
+	var held int64
+	db.Table(MigrationsLockTable).Count(&held)
+	if held != 0 {
+		t.Errorf("%s rows = %d after Up, want the lock released", MigrationsLockTable, held)
+	}
+}
+
+func TestNewMigrator_Validation(t *testing.T) {
+	up := func(*gorm.DB) error { return nil }
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{"empty ID", []Migration{{Up: up}}},
+		{"duplicate ID", []Migration{{ID: "001", Up: up}, {ID: "001", Up: up}}},
+		{"missing Up", []Migration{{ID: "001"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMigrator(newTestDB(t), tt.migrations); err == nil {
+				t.Error("NewMigrator() should fail")
+			}
+		})
+	}
+	if _, err := NewMigrator(nil, nil); err == nil {
+		t.Error("NewMigrator(nil) should fail")
+	}
+}
@@ -0,0 +1,100 @@
+// Package internal contains GORM database adapter implementation.
+package internal
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterPoolMetrics registers connection pool instruments observing stats
+// on every collection, labeled with db_name. The instrument names match the
+// obsx database metrics, so dashboards work with either.
+//
+// Metrics collected:
+//   - db_pool_open_connections: Number of established connections
+//   - db_pool_in_use: Number of connections currently in use
+//   - db_pool_idle: Number of idle connections
+//   - db_pool_wait_count_total: Total number of connections waited for
+//   - db_pool_wait_seconds_total: Total time blocked waiting for connections
+//   - db_pool_max_open: Maximum number of open connections
+func RegisterPoolMetrics(name string, stats func() sql.DBStats, meterProvider metric.MeterProvider) error {
+	meter := meterProvider.Meter("go.eggybyte.com/egg/storex")
+	dbAttr := metric.WithAttributes(attribute.String("db_name", name))
+
+	openConns, err := meter.Int64ObservableGauge(
+		"db_pool_open_connections",
+		metric.WithDescription("Number of established connections both in use and idle"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := meter.Int64ObservableGauge(
+		"db_pool_in_use",
+		metric.WithDescription("Number of connections currently in use"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	idle, err := meter.Int64ObservableGauge(
+		"db_pool_idle",
+		metric.WithDescription("Number of idle connections"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	waitCount, err := meter.Int64ObservableCounter(
+		"db_pool_wait_count_total",
+		metric.WithDescription("Total number of connections waited for"),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	waitDuration, err := meter.Float64ObservableCounter(
+		"db_pool_wait_seconds_total",
+		metric.WithDescription("Total time blocked waiting for new connections"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	maxOpen, err := meter.Int64ObservableGauge(
+		"db_pool_max_open",
+		metric.WithDescription("Maximum number of open connections to the database"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(
+		func(ctx context.Context, observer metric.Observer) error {
+			s := stats()
+			observer.ObserveInt64(openConns, int64(s.OpenConnections), dbAttr)
+			observer.ObserveInt64(inUse, int64(s.InUse), dbAttr)
+			observer.ObserveInt64(idle, int64(s.Idle), dbAttr)
+			observer.ObserveInt64(waitCount, s.WaitCount, dbAttr)
+			observer.ObserveFloat64(waitDuration, s.WaitDuration.Seconds(), dbAttr)
+			observer.ObserveInt64(maxOpen, int64(s.MaxOpenConnections), dbAttr)
+			return nil
+		},
+		openConns,
+		inUse,
+		idle,
+		waitCount,
+		waitDuration,
+		maxOpen,
+	)
+	return err
+}
@@ -0,0 +1,142 @@
+// Package internal contains GORM database adapter implementation.
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"gorm.io/gorm"
+)
+
+// queryStartKey is the statement instance key holding the query start time.
+const queryStartKey = "storex:query_start"
+
+// SlowQuery describes a query that took at least the slow query threshold.
+type SlowQuery struct {
+	Operation    string        // create, query, update, delete, row or raw
+	Table        string        // Table of the statement, if known
+	SQL          string        // Statement with placeholders; bound values are not included
+	Duration     time.Duration // Time spent executing the statement
+	RowsAffected int64         // Rows affected or returned
+	Err          error         // Statement error, if any
+}
+
+// QueryMetricsConfig configures QueryMetricsPlugin.
+type QueryMetricsConfig struct {
+	Name          string               // db_name attribute value
+	SlowThreshold time.Duration        // Queries at least this slow are reported (<= 0 disables)
+	MeterProvider metric.MeterProvider // Provider for the query instruments (nil = no metrics)
+	OnSlowQuery   func(ctx context.Context, q SlowQuery)
+}
+
+// QueryMetricsPlugin is a GORM plugin recording query durations and
+// reporting slow queries.
+type QueryMetricsPlugin struct {
+	cfg      QueryMetricsConfig
+	duration metric.Float64Histogram
+	slow     metric.Int64Counter
+}
+
+// NewQueryMetricsPlugin creates the query instruments from cfg.MeterProvider.
+func NewQueryMetricsPlugin(cfg QueryMetricsConfig) (*QueryMetricsPlugin, error) {
+	meterProvider := cfg.MeterProvider
+	if meterProvider == nil {
+		meterProvider = noop.NewMeterProvider()
+	}
+	meter := meterProvider.Meter("go.eggybyte.com/egg/storex")
+
+	duration, err := meter.Float64Histogram(
+		"db_query_duration_seconds",
+		metric.WithDescription("Database query duration in seconds"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(
+			0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	slow, err := meter.Int64Counter(
+		"db_slow_queries_total",
+		metric.WithDescription("Total number of queries exceeding the slow query threshold"),
+		metric.WithUnit("{query}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryMetricsPlugin{cfg: cfg, duration: duration, slow: slow}, nil
+}
+
+// Name implements gorm.Plugin.
+func (p *QueryMetricsPlugin) Name() string {
+	return "storex:query_metrics"
+}
+
+// Initialize implements gorm.Plugin, registering callbacks around every
+// statement type.
+func (p *QueryMetricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("storex:before_create", p.before),
+		cb.Create().After("gorm:create").Register("storex:after_create", p.after("create")),
+		cb.Query().Before("gorm:query").Register("storex:before_query", p.before),
+		cb.Query().After("gorm:query").Register("storex:after_query", p.after("query")),
+		cb.Update().Before("gorm:update").Register("storex:before_update", p.before),
+		cb.Update().After("gorm:update").Register("storex:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("storex:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("storex:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("storex:before_row", p.before),
+		cb.Row().After("gorm:row").Register("storex:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("storex:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("storex:after_raw", p.after("raw")),
+	)
+}
+
+func (p *QueryMetricsPlugin) before(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *QueryMetricsPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		attrs := metric.WithAttributes(
+			attribute.String("db_name", p.cfg.Name),
+			attribute.String("operation", operation),
+		)
+		p.duration.Record(ctx, elapsed.Seconds(), attrs)
+
+		if p.cfg.SlowThreshold <= 0 || elapsed < p.cfg.SlowThreshold {
+			return
+		}
+		p.slow.Add(ctx, 1, attrs)
+		if p.cfg.OnSlowQuery != nil {
+			p.cfg.OnSlowQuery(ctx, SlowQuery{
+				Operation:    operation,
+				Table:        db.Statement.Table,
+				SQL:          db.Statement.SQL.String(),
+				Duration:     elapsed,
+				RowsAffected: db.Statement.RowsAffected,
+				Err:          db.Error,
+			})
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package internal provides tests for storex query metrics.
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect returns the metrics recorded by reader, keyed by name.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestQueryMetricsPlugin_RecordsDurations(t *testing.T) {
+	db := newTestDB(t)
+	reader := sdkmetric.NewManualReader()
+	plugin, err := NewQueryMetricsPlugin(QueryMetricsConfig{
+		Name:          "main",
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	})
+	if err != nil {
+		t.Fatalf("NewQueryMetricsPlugin() error = %v", err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	db.Create(&txRecord{Name: "a"})
+	var records []txRecord
+	db.Find(&records)
+
+	metrics := collect(t, reader)
+	hist, ok := metrics["db_query_duration_seconds"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("db_query_duration_seconds not recorded: %v", metrics)
+	}
+	operations := map[string]uint64{}
+	for _, dp := range hist.DataPoints {
+		if name, _ := dp.Attributes.Value("db_name"); name.AsString() != "main" {
+			t.Errorf("db_name = %q, want main", name.AsString())
+		}
+		op, _ := dp.Attributes.Value("operation")
+		operations[op.AsString()] = dp.Count
+	}
+	if operations["create"] != 1 || operations["query"] != 1 {
+		t.Errorf("operations = %v, want one create and one query", operations)
+	}
+	if _, ok := metrics["db_slow_queries_total"]; ok {
+		t.Error("no slow queries expected with the threshold disabled")
+	}
+}
+
+func TestQueryMetricsPlugin_SlowQueries(t *testing.T) {
+	db := newTestDB(t)
+	reader := sdkmetric.NewManualReader()
+
+	var mu sync.Mutex
+	var slow []SlowQuery
+	plugin, err := NewQueryMetricsPlugin(QueryMetricsConfig{
+		Name:          "main",
+		SlowThreshold: time.Nanosecond,
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		OnSlowQuery: func(ctx context.Context, q SlowQuery) {
+			mu.Lock()
+			slow = append(slow, q)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewQueryMetricsPlugin() error = %v", err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	db.Where("name = ?", "secret").Find(&[]txRecord{})
+
+	if len(slow) != 1 {
+		t.Fatalf("slow queries = %d, want 1", len(slow))
+	}
+	q := slow[0]
+	if q.Operation != "query" || q.Table != "tx_records" || q.Duration <= 0 {
+		t.Errorf("slow query = %+v", q)
+	}
+	if q.SQL != "SELECT * FROM `tx_records` WHERE name = ?" {
+		t.Errorf("SQL = %q, want the statement without bound values", q.SQL)
+	}
+
+	counter, ok := collect(t, reader)["db_slow_queries_total"].(metricdata.Sum[int64])
+	if !ok || len(counter.DataPoints) != 1 || counter.DataPoints[0].Value != 1 {
+		t.Errorf("db_slow_queries_total = %+v, want 1", counter)
+	}
+}
+
+func TestQueryMetricsPlugin_NilMeterProvider(t *testing.T) {
+	db := newTestDB(t)
+	called := false
+	plugin, err := NewQueryMetricsPlugin(QueryMetricsConfig{
+		SlowThreshold: time.Nanosecond,
+		OnSlowQuery:   func(context.Context, SlowQuery) { called = true },
+	})
+	if err != nil {
+		t.Fatalf("NewQueryMetricsPlugin() error = %v", err)
+	}
+	if err := db.Use(plugin); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	db.Find(&[]txRecord{})
+
+	if !called {
+		t.Error("OnSlowQuery should be called without a meter provider")
+	}
+}
+
+func TestGORMStore_Stats(t *testing.T) {
+	if stats := NewGORMStore(nil, nil).Stats(); stats.OpenConnections != 0 {
+		t.Errorf("Stats() for nil db = %+v, want zero", stats)
+	}
+
+	db := newTestDB(t)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(7)
+
+	if stats := NewGORMStore(db, nil).Stats(); stats.MaxOpenConnections != 7 {
+		t.Errorf("MaxOpenConnections = %d, want 7", stats.MaxOpenConnections)
+	}
+}
+
+func TestRegisterPoolMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	stats := func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 1, Idle: 2}
+	}
+	if err := RegisterPoolMetrics("main", stats, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))); err != nil {
+		t.Fatalf("RegisterPoolMetrics() error = %v", err)
+	}
+
+	metrics := collect(t, reader)
+	want := map[string]int64{
+		"db_pool_open_connections": 3,
+		"db_pool_in_use":           1,
+		"db_pool_idle":             2,
+		"db_pool_max_open":         10,
+	}
+	for name, value := range want {
+		gauge, ok := metrics[name].(metricdata.Gauge[int64])
+		if !ok || len(gauge.DataPoints) != 1 {
+			t.Errorf("%s not recorded: %v", name, metrics[name])
+			continue
+		}
+		dp := gauge.DataPoints[0]
+		if dp.Value != value {
+			t.Errorf("%s = %d, want %d", name, dp.Value, value)
+		}
+		if db, _ := dp.Attributes.Value("db_name"); db.AsString() != "main" {
+			t.Errorf("%s db_name = %q, want main", name, db.AsString())
+		}
+	}
+}
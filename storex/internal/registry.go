@@ -3,10 +3,41 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// DefaultCloseTimeout bounds CloseAll when the context has no deadline.
+const DefaultCloseTimeout = 5 * time.Second
+
+// DefaultDegradedThreshold is the ping latency above which a store without
+// its own threshold is reported as degraded.
+const DefaultDegradedThreshold = 500 * time.Millisecond
+
+// pingTimeout bounds Ping and HealthReport.
+const pingTimeout = 5 * time.Second
+
+// HealthState is the health of a store as seen by HealthReport.
+type HealthState string
+
+// Health states reported by HealthReport.
+const (
+	HealthOK       HealthState = "ok"       // Ping succeeded within the threshold
+	HealthDegraded HealthState = "degraded" // Ping succeeded but exceeded the threshold
+	HealthDown     HealthState = "down"     // Ping failed or timed out
 )
 
+// HealthStatus is the result of pinging one store.
+type HealthStatus struct {
+	State   HealthState   // ok, degraded or down
+	Latency time.Duration // Time the ping took
+	Err     error         // Ping error, nil unless down
+}
+
 // Store defines the interface for storage backends.
 type Store interface {
 	Ping(ctx context.Context) error
@@ -15,13 +46,16 @@ type Store interface {
 
 // Registry manages multiple storage connections and their health.
 type Registry struct {
-	stores map[string]Store
+	stores     map[string]Store
+	thresholds map[string]time.Duration
+	logger     log.Logger
 }
 
 // NewRegistry creates a new storage registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		stores: make(map[string]Store),
+		stores:     make(map[string]Store),
+		thresholds: make(map[string]time.Duration),
 	}
 }
 
@@ -48,6 +82,21 @@ func (r *Registry) Unregister(name string) error {
 	}
 
 	delete(r.stores, name)
+	delete(r.thresholds, name)
+	return nil
+}
+
+// SetDegradedThreshold sets the ping latency above which the named store is
+// reported as degraded.
+func (r *Registry) SetDegradedThreshold(name string, threshold time.Duration) error {
+	if _, exists := r.stores[name]; !exists {
+		return fmt.Errorf("store %s not found", name)
+	}
+	if threshold <= 0 {
+		return fmt.Errorf("degraded threshold must be positive")
+	}
+
+	r.thresholds[name] = threshold
 	return nil
 }
 
@@ -57,7 +106,7 @@ func (r *Registry) Ping(ctx context.Context) error {
 		return nil
 	}
 
-	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
 	defer cancel()
 
 	var errors []error
@@ -74,6 +123,57 @@ func (r *Registry) Ping(ctx context.Context) error {
 	return nil
 }
 
+// HealthReport pings all stores concurrently and reports each store's state
+// and latency. Unlike Ping it never fails as a whole.
+func (r *Registry) HealthReport(ctx context.Context) map[string]HealthStatus {
+	report := make(map[string]HealthStatus, len(r.stores))
+	if len(r.stores) == 0 {
+		return report
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, store := range r.stores {
+		threshold, ok := r.thresholds[name]
+		if !ok {
+			threshold = DefaultDegradedThreshold
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := checkHealth(pingCtx, store, threshold)
+			mu.Lock()
+			report[name] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// checkHealth pings store and classifies the result against threshold.
+func checkHealth(ctx context.Context, store Store, threshold time.Duration) HealthStatus {
+	start := time.Now()
+	err := store.Ping(ctx)
+	latency := time.Since(start)
+
+	switch {
+	case err != nil:
+		return HealthStatus{State: HealthDown, Latency: latency, Err: err}
+	case latency > threshold:
+		return HealthStatus{State: HealthDegraded, Latency: latency}
+	default:
+		return HealthStatus{State: HealthOK, Latency: latency}
+	}
+}
+
 // Close closes all registered storage connections.
 func (r *Registry) Close() error {
 	var errors []error
@@ -90,6 +190,73 @@ func (r *Registry) Close() error {
 	return nil
 }
 
+// SetLogger sets the logger used to report CloseAll failures.
+func (r *Registry) SetLogger(logger log.Logger) {
+	r.logger = logger
+}
+
+// CloseAll closes all registered stores concurrently. Each store must close
+// before ctx is done, or within DefaultCloseTimeout if ctx has no deadline;
+// stores still closing at that point are reported as timed out and left
+// behind so shutdown can proceed.
+//
+// Failures are logged per store and returned joined into a single error.
+func (r *Registry) CloseAll(ctx context.Context) error {
+	if len(r.stores) == 0 {
+		return nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultCloseTimeout)
+		defer cancel()
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for name, store := range r.stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.closeStore(ctx, name, store); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// closeStore closes one store, giving up when ctx is done.
+func (r *Registry) closeStore(ctx context.Context, name string, store Store) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- store.Close()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			if r.logger != nil {
+				r.logger.Error(err, "store close failed", log.Str("store", name))
+			}
+			return fmt.Errorf("store %s close failed: %w", name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		if r.logger != nil {
+			r.logger.Warn("store close timed out", log.Str("store", name))
+		}
+		return fmt.Errorf("store %s close timed out: %w", name, ctx.Err())
+	}
+}
+
 // List returns the names of all registered stores.
 func (r *Registry) List() []string {
 	names := make([]string, 0, len(r.stores))
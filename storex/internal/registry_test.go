@@ -0,0 +1,201 @@
+// Package internal provides tests for storex registry health reports and shutdown.
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.eggybyte.com/egg/core/log"
+)
+
+// hangingStore blocks in Close until release is closed.
+type hangingStore struct {
+	release chan struct{}
+}
+
+func (s *hangingStore) Ping(ctx context.Context) error { return nil }
+
+func (s *hangingStore) Close() error {
+	<-s.release
+	return nil
+}
+
+// recordingLogger records warning and error messages.
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) {}
+func (l *recordingLogger) Info(msg string, kv ...interface{})  {}
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record(msg) }
+func (l *recordingLogger) Error(err error, msg string, kv ...interface{}) {
+	l.record(msg)
+}
+func (l *recordingLogger) With(kv ...interface{}) log.Logger { return l }
+
+func (l *recordingLogger) record(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestRegistry_CloseAll_Success(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("a", &mockStore{})
+	registry.Register("b", &mockStore{})
+
+	if err := registry.CloseAll(context.Background()); err != nil {
+		t.Errorf("CloseAll() error = %v, want nil", err)
+	}
+}
+
+func TestRegistry_CloseAll_Empty(t *testing.T) {
+	if err := NewRegistry().CloseAll(context.Background()); err != nil {
+		t.Errorf("CloseAll() error = %v, want nil", err)
+	}
+}
+
+func TestRegistry_CloseAll_AggregatesErrors(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+	logger := &recordingLogger{}
+
+	registry := NewRegistry()
+	registry.SetLogger(logger)
+	registry.Register("a", &mockStore{closeErr: errA})
+	registry.Register("b", &mockStore{closeErr: errB})
+	registry.Register("c", &mockStore{})
+
+	err := registry.CloseAll(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("CloseAll() error = %v, want both store errors", err)
+	}
+	if !strings.Contains(err.Error(), "store a close failed") {
+		t.Errorf("CloseAll() error = %q, want store name", err)
+	}
+	if len(logger.msgs) != 2 {
+		t.Errorf("logged %v, want 2 messages", logger.msgs)
+	}
+}
+
+func TestRegistry_CloseAll_Timeout(t *testing.T) {
+	hanging := &hangingStore{release: make(chan struct{})}
+	defer close(hanging.release)
+	logger := &recordingLogger{}
+
+	registry := NewRegistry()
+	registry.SetLogger(logger)
+	registry.Register("hanging", hanging)
+	registry.Register("ok", &mockStore{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := registry.CloseAll(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CloseAll() took %v, want bounded by the context", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CloseAll() error = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "store hanging close timed out") {
+		t.Errorf("CloseAll() error = %q, want timed out store name", err)
+	}
+	if len(logger.msgs) != 1 || logger.msgs[0] != "store close timed out" {
+		t.Errorf("logged %v, want one timeout message", logger.msgs)
+	}
+}
+
+// slowStore delays Ping by delay, honoring ctx.
+type slowStore struct {
+	delay time.Duration
+}
+
+func (s *slowStore) Ping(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *slowStore) Close() error { return nil }
+
+func TestRegistry_HealthReport(t *testing.T) {
+	pingErr := errors.New("connection refused")
+
+	registry := NewRegistry()
+	registry.Register("ok", &mockStore{})
+	registry.Register("slow", &slowStore{delay: 20 * time.Millisecond})
+	registry.Register("down", &mockStore{pingErr: pingErr})
+	if err := registry.SetDegradedThreshold("slow", 5*time.Millisecond); err != nil {
+		t.Fatalf("SetDegradedThreshold() error = %v", err)
+	}
+
+	report := registry.HealthReport(context.Background())
+	if len(report) != 3 {
+		t.Fatalf("HealthReport() has %d entries, want 3", len(report))
+	}
+
+	if got := report["ok"]; got.State != HealthOK || got.Err != nil {
+		t.Errorf("ok = %+v, want state ok", got)
+	}
+	if got := report["slow"]; got.State != HealthDegraded || got.Latency < 20*time.Millisecond {
+		t.Errorf("slow = %+v, want state degraded with latency >= 20ms", got)
+	}
+	if got := report["down"]; got.State != HealthDown || !errors.Is(got.Err, pingErr) {
+		t.Errorf("down = %+v, want state down with ping error", got)
+	}
+}
+
+func TestRegistry_HealthReport_DefaultThreshold(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("slow", &slowStore{delay: 20 * time.Millisecond})
+
+	if got := registry.HealthReport(context.Background())["slow"]; got.State != HealthOK {
+		t.Errorf("slow = %+v, want state ok below the default threshold", got)
+	}
+}
+
+func TestRegistry_HealthReport_CancelledContext(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("slow", &slowStore{delay: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if got := registry.HealthReport(ctx)["slow"]; got.State != HealthDown || !errors.Is(got.Err, context.Canceled) {
+		t.Errorf("slow = %+v, want state down with context.Canceled", got)
+	}
+}
+
+func TestRegistry_HealthReport_Empty(t *testing.T) {
+	if report := NewRegistry().HealthReport(context.Background()); len(report) != 0 {
+		t.Errorf("HealthReport() = %v, want empty", report)
+	}
+}
+
+func TestRegistry_SetDegradedThreshold(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.SetDegradedThreshold("missing", time.Second); err == nil {
+		t.Error("SetDegradedThreshold() on unknown store should fail")
+	}
+
+	registry.Register("db", &mockStore{})
+	if err := registry.SetDegradedThreshold("db", 0); err == nil {
+		t.Error("SetDegradedThreshold() with zero threshold should fail")
+	}
+
+	registry.SetDegradedThreshold("db", time.Second)
+	registry.Unregister("db")
+	if _, exists := registry.thresholds["db"]; exists {
+		t.Error("Unregister() should drop the store's threshold")
+	}
+}
@@ -0,0 +1,215 @@
+// Package internal contains GORM database adapter implementation.
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// replicaPoolKey is the statement instance key holding the primary pool
+// while a statement runs on a replica.
+const replicaPoolKey = "storex:replica_conn_pool"
+
+// primaryContextKey marks a context whose reads must go to the primary.
+type primaryContextKey struct{}
+
+// WithPrimary returns a context that routes every statement to the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// IsPrimaryForced reports whether ctx was created by WithPrimary.
+func IsPrimaryForced(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	forced, _ := ctx.Value(primaryContextKey{}).(bool)
+	return forced
+}
+
+// ReplicaStore is a GORM-backed store usable as a primary or replica.
+type ReplicaStore interface {
+	Store
+	GetDB() *gorm.DB
+	Stats() sql.DBStats
+}
+
+// replica holds a replica store and its last known health.
+type replica struct {
+	store   ReplicaStore
+	pool    gorm.ConnPool
+	healthy atomic.Bool
+}
+
+// ReplicaSet routes reads to healthy replicas and everything else to the
+// primary.
+type ReplicaSet struct {
+	primary  ReplicaStore
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// NewReplicaSet creates a replica set and installs the routing plugin on the
+// primary's database. All replicas start out healthy.
+func NewReplicaSet(primary ReplicaStore, replicas ...ReplicaStore) (*ReplicaSet, error) {
+	if primary == nil || primary.GetDB() == nil {
+		return nil, fmt.Errorf("primary database connection is nil")
+	}
+
+	rs := &ReplicaSet{primary: primary}
+	for i, store := range replicas {
+		if store == nil || store.GetDB() == nil {
+			return nil, fmt.Errorf("replica %d database connection is nil", i)
+		}
+		r := &replica{store: store, pool: store.GetDB().ConnPool}
+		r.healthy.Store(true)
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	if err := primary.GetDB().Use(&replicaRouter{set: rs}); err != nil {
+		return nil, fmt.Errorf("failed to register replica routing plugin: %w", err)
+	}
+
+	return rs, nil
+}
+
+// GetDB returns the primary's database, which routes reads to replicas.
+func (rs *ReplicaSet) GetDB() *gorm.DB {
+	return rs.primary.GetDB()
+}
+
+// Stats returns the connection pool statistics of the primary.
+func (rs *ReplicaSet) Stats() sql.DBStats {
+	return rs.primary.Stats()
+}
+
+// HealthyReplicas returns the number of replicas that passed the last ping.
+func (rs *ReplicaSet) HealthyReplicas() int {
+	n := 0
+	for _, r := range rs.replicas {
+		if r.healthy.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// Ping checks the primary and every replica, updating replica health.
+// Only a primary failure is returned: reads fall back to the primary while
+// replicas are down.
+func (rs *ReplicaSet) Ping(ctx context.Context) error {
+	for _, r := range rs.replicas {
+		r.healthy.Store(r.store.Ping(ctx) == nil)
+	}
+
+	if err := rs.primary.Ping(ctx); err != nil {
+		return fmt.Errorf("primary ping failed: %w", err)
+	}
+	return nil
+}
+
+// Close closes the replicas and the primary.
+func (rs *ReplicaSet) Close() error {
+	var errs []error
+	for i, r := range rs.replicas {
+		if err := r.store.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("replica %d close failed: %w", i, err))
+		}
+	}
+	if err := rs.primary.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("primary close failed: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// pick returns the connection pool of the next healthy replica in
+// round-robin order, or nil if no replica is healthy.
+func (rs *ReplicaSet) pick() gorm.ConnPool {
+	n := len(rs.replicas)
+	if n == 0 {
+		return nil
+	}
+
+	start := rs.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := rs.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r.pool
+		}
+	}
+	return nil
+}
+
+// replicaRouter is a GORM plugin switching read statements to a replica.
+type replicaRouter struct {
+	set *ReplicaSet
+}
+
+// Name implements gorm.Plugin.
+func (p *replicaRouter) Name() string {
+	return "storex:replica_routing"
+}
+
+// Initialize implements gorm.Plugin, registering callbacks around queries
+// and row scans.
+func (p *replicaRouter) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Query().Before("gorm:query").Register("storex:route_query", p.route),
+		cb.Query().After("gorm:query").Register("storex:restore_query", p.restore),
+		cb.Row().Before("gorm:row").Register("storex:route_row", p.route),
+		cb.Row().After("gorm:row").Register("storex:restore_row", p.restore),
+	)
+}
+
+// route sends the statement to a replica unless it runs in a transaction,
+// takes row locks, is not a SELECT, or its context forces the primary.
+func (p *replicaRouter) route(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.ConnPool != db.Config.ConnPool {
+		// Transactions and prepared sessions carry their own pool.
+		return
+	}
+	if IsPrimaryForced(stmt.Context) {
+		return
+	}
+	if _, locking := stmt.Clauses["FOR"]; locking {
+		return
+	}
+	if query := stmt.SQL.String(); query != "" && !isReadQuery(query) {
+		// Raw statements such as INSERT ... RETURNING scanned into a result.
+		return
+	}
+
+	pool := p.set.pick()
+	if pool == nil {
+		return
+	}
+	db.InstanceSet(replicaPoolKey, stmt.ConnPool)
+	stmt.ConnPool = pool
+}
+
+// restore switches the statement back to the primary so a reused statement
+// does not write to a replica.
+func (p *replicaRouter) restore(db *gorm.DB) {
+	if value, ok := db.InstanceGet(replicaPoolKey); ok {
+		if pool, ok := value.(gorm.ConnPool); ok {
+			db.Statement.ConnPool = pool
+		}
+	}
+}
+
+// isReadQuery reports whether query is a SELECT without row locks.
+func isReadQuery(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if !strings.HasPrefix(query, "SELECT") {
+		return false
+	}
+	return !strings.Contains(query, " FOR UPDATE") && !strings.Contains(query, " FOR SHARE")
+}
@@ -0,0 +1,207 @@
+// Package internal provides tests for storex replica routing.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// newNamedTestStore opens an in-memory SQLite store holding one txRecord
+// named after the database.
+func newNamedTestStore(t *testing.T, name string) *GORMStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s_%s?mode=memory&cache=shared", t.Name(), name)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.AutoMigrate(&txRecord{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	if err := db.Create(&txRecord{Name: name}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return NewGORMStore(db, nil)
+}
+
+// firstName returns the name of the first txRecord visible through db.
+func firstName(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var rec txRecord
+	if err := db.Order("id").First(&rec).Error; err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	return rec.Name
+}
+
+func TestReplicaSet_RoutesReadsToReplicas(t *testing.T) {
+	primary := newNamedTestStore(t, "primary")
+	rs, err := NewReplicaSet(primary, newNamedTestStore(t, "replica1"), newNamedTestStore(t, "replica2"))
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+
+	seen := map[string]int{}
+	for i := 0; i < 4; i++ {
+		seen[firstName(t, rs.GetDB())]++
+	}
+	if seen["replica1"] != 2 || seen["replica2"] != 2 {
+		t.Errorf("reads = %v, want 2 per replica", seen)
+	}
+
+	var n int64
+	if err := rs.GetDB().Model(&txRecord{}).Count(&n).Error; err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("replica count = %d, want 1", n)
+	}
+}
+
+func TestReplicaSet_WritesGoToPrimary(t *testing.T) {
+	primary := newNamedTestStore(t, "primary")
+	replica := newNamedTestStore(t, "replica")
+	rs, err := NewReplicaSet(primary, replica)
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+
+	if err := rs.GetDB().Create(&txRecord{Name: "written"}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := rs.GetDB().Model(&txRecord{}).Where("name = ?", "primary").Update("name", "updated").Error; err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if got := countRecords(t, primary.GetDB().WithContext(WithPrimary(context.Background()))); got != 2 {
+		t.Errorf("primary records = %d, want 2", got)
+	}
+	if got := countRecords(t, replica.GetDB()); got != 1 {
+		t.Errorf("replica records = %d, want 1", got)
+	}
+}
+
+func TestReplicaSet_ForcedPrimaryReads(t *testing.T) {
+	rs, err := NewReplicaSet(newNamedTestStore(t, "primary"), newNamedTestStore(t, "replica"))
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+	db := rs.GetDB()
+
+	if got := firstName(t, db.WithContext(WithPrimary(context.Background()))); got != "primary" {
+		t.Errorf("WithPrimary read from %q, want primary", got)
+	}
+	if got := firstName(t, db.Clauses(clause.Locking{Strength: "UPDATE"})); got != "primary" {
+		t.Errorf("locking read from %q, want primary", got)
+	}
+
+	err = RunInTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		if got := firstName(t, tx); got != "primary" {
+			t.Errorf("transaction read from %q, want primary", got)
+		}
+		return nil
+	}, TxConfig{})
+	if err != nil {
+		t.Fatalf("RunInTransaction() error = %v", err)
+	}
+
+	if got := firstName(t, db); got != "replica" {
+		t.Errorf("plain read from %q, want replica", got)
+	}
+}
+
+func TestReplicaSet_SkipsUnhealthyReplicas(t *testing.T) {
+	down := newNamedTestStore(t, "down")
+	rs, err := NewReplicaSet(newNamedTestStore(t, "primary"), down, newNamedTestStore(t, "up"))
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+
+	if err := down.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := rs.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v, want nil while primary is up", err)
+	}
+	if got := rs.HealthyReplicas(); got != 1 {
+		t.Errorf("HealthyReplicas() = %d, want 1", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		if got := firstName(t, rs.GetDB()); got != "up" {
+			t.Errorf("read %d from %q, want up", i, got)
+		}
+	}
+}
+
+func TestReplicaSet_FallsBackToPrimary(t *testing.T) {
+	replica := newNamedTestStore(t, "replica")
+	rs, err := NewReplicaSet(newNamedTestStore(t, "primary"), replica)
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+
+	replica.Close()
+	rs.Ping(context.Background())
+
+	if got := firstName(t, rs.GetDB()); got != "primary" {
+		t.Errorf("read from %q with no healthy replica, want primary", got)
+	}
+}
+
+func TestReplicaSet_PingPrimaryFailure(t *testing.T) {
+	primary := newNamedTestStore(t, "primary")
+	rs, err := NewReplicaSet(primary, newNamedTestStore(t, "replica"))
+	if err != nil {
+		t.Fatalf("NewReplicaSet() error = %v", err)
+	}
+
+	primary.Close()
+	if err := rs.Ping(context.Background()); err == nil {
+		t.Error("Ping() error = nil, want primary failure")
+	}
+}
+
+func TestNewReplicaSet_NilStores(t *testing.T) {
+	if _, err := NewReplicaSet(nil); err == nil {
+		t.Error("NewReplicaSet(nil) error = nil, want error")
+	}
+	if _, err := NewReplicaSet(NewGORMStore(nil, nil)); err == nil {
+		t.Error("NewReplicaSet(nil db) error = nil, want error")
+	}
+	if _, err := NewReplicaSet(newNamedTestStore(t, "primary"), NewGORMStore(nil, nil)); err == nil {
+		t.Error("NewReplicaSet(nil replica db) error = nil, want error")
+	}
+}
+
+func TestIsReadQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "SELECT * FROM users", want: true},
+		{query: "  select id from users", want: true},
+		{query: "SELECT * FROM users FOR UPDATE", want: false},
+		{query: "SELECT * FROM users FOR SHARE", want: false},
+		{query: "INSERT INTO users (name) VALUES (?) RETURNING id", want: false},
+		{query: "UPDATE users SET name = ?", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isReadQuery(tt.query); got != tt.want {
+			t.Errorf("isReadQuery(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
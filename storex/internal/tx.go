@@ -0,0 +1,83 @@
+// Package internal contains GORM database adapter implementation.
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Error codes reported by databases for transactions that lost a conflict
+// and can succeed when run again.
+const (
+	mysqlDeadlock            = 1213    // ER_LOCK_DEADLOCK
+	postgresSerialization    = "40001" // serialization_failure
+	postgresDeadlockDetected = "40P01" // deadlock_detected
+)
+
+// TxConfig configures RunInTransaction.
+type TxConfig struct {
+	MaxRetries int            // Retries after the first attempt
+	Backoff    time.Duration  // Delay before the first retry, doubled for each further retry
+	TxOptions  *sql.TxOptions // Isolation level and read-only mode, nil for driver defaults
+}
+
+// RunInTransaction runs fn in a transaction on db, retrying the whole
+// transaction when it fails with a deadlock or serialization failure.
+//
+// ctx is bound to every attempt, and a cancelled context stops retrying. The
+// returned error reports how many attempts were made.
+func RunInTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error, cfg TxConfig) error {
+	if db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("transaction not started: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		var err error
+		if cfg.TxOptions != nil {
+			err = db.WithContext(ctx).Transaction(fn, cfg.TxOptions)
+		} else {
+			err = db.WithContext(ctx).Transaction(fn)
+		}
+		if err == nil {
+			return nil
+		}
+		if attempt > cfg.MaxRetries || !IsRetryableTxError(err) {
+			return fmt.Errorf("transaction failed after %d attempt(s): %w", attempt, err)
+		}
+
+		timer := time.NewTimer(cfg.Backoff << (attempt - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("transaction failed after %d attempt(s): %w", attempt, errors.Join(err, ctx.Err()))
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryableTxError reports whether err is a deadlock or serialization
+// failure: MySQL error 1213, or PostgreSQL SQLSTATE 40001 or 40P01.
+func IsRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresSerialization || pgErr.Code == postgresDeadlockDetected
+	}
+
+	return false
+}
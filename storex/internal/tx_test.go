@@ -0,0 +1,192 @@
+// Package internal provides tests for storex transactions.
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type txRecord struct {
+	ID   uint
+	Name string
+}
+
+// newTestDB opens an in-memory SQLite database with a txRecord table.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.AutoMigrate(&txRecord{}); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func countRecords(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(&txRecord{}).Count(&n).Error; err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	return n
+}
+
+func TestRunInTransaction_RetriesDeadlock(t *testing.T) {
+	db := newTestDB(t)
+
+	attempts := 0
+	err := RunInTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&txRecord{Name: "row"}).Error; err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+		}
+		return nil
+	}, TxConfig{MaxRetries: 3, Backoff: time.Millisecond})
+
+	if err != nil {
+		t.Fatalf("RunInTransaction() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if n := countRecords(t, db); n != 1 {
+		t.Errorf("records = %d, want 1 (failed attempts rolled back)", n)
+	}
+}
+
+func TestRunInTransaction_RetriesExhausted(t *testing.T) {
+	db := newTestDB(t)
+	serialization := &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+
+	attempts := 0
+	err := RunInTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		attempts++
+		return serialization
+	}, TxConfig{MaxRetries: 2, Backoff: time.Millisecond})
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if !errors.Is(err, serialization) {
+		t.Errorf("RunInTransaction() error = %v, want %v", err, serialization)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "transaction failed after 3 attempt(s)") {
+		t.Errorf("RunInTransaction() error = %v, want the attempt count", err)
+	}
+}
+
+func TestRunInTransaction_NonRetryable(t *testing.T) {
+	db := newTestDB(t)
+	businessErr := errors.New("insufficient funds")
+
+	attempts := 0
+	err := RunInTransaction(context.Background(), db, func(tx *gorm.DB) error {
+		attempts++
+		tx.Create(&txRecord{Name: "row"})
+		return businessErr
+	}, TxConfig{MaxRetries: 3})
+
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+	if !errors.Is(err, businessErr) {
+		t.Errorf("RunInTransaction() error = %v, want %v", err, businessErr)
+	}
+	if n := countRecords(t, db); n != 0 {
+		t.Errorf("records = %d, want 0 after rollback", n)
+	}
+}
+
+func TestRunInTransaction_ContextCancelled(t *testing.T) {
+	db := newTestDB(t)
+
+	t.Run("before start", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		err := RunInTransaction(ctx, db, func(tx *gorm.DB) error {
+			called = true
+			return nil
+		}, TxConfig{})
+
+		if called {
+			t.Error("fn should not run with a cancelled context")
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("RunInTransaction() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("during backoff", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		attempts := 0
+		err := RunInTransaction(ctx, db, func(tx *gorm.DB) error {
+			attempts++
+			return &mysql.MySQLError{Number: 1213}
+		}, TxConfig{MaxRetries: 5, Backoff: time.Hour})
+
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("RunInTransaction() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestRunInTransaction_NilDB(t *testing.T) {
+	err := RunInTransaction(context.Background(), nil, func(tx *gorm.DB) error { return nil }, TxConfig{})
+	if err == nil {
+		t.Error("RunInTransaction() should fail for nil db")
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, want: true},
+		{name: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062}, want: false},
+		{name: "postgres serialization", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "postgres deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "postgres unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "wrapped", err: fmt.Errorf("update: %w", &mysql.MySQLError{Number: 1213}), want: true},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableTxError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableTxError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,10 +16,13 @@ package storex
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"go.eggybyte.com/egg/core/log"
 	"go.eggybyte.com/egg/storex/internal"
+	"go.opentelemetry.io/otel/metric"
 	"gorm.io/gorm"
 )
 
@@ -42,6 +45,9 @@ type GORMStore interface {
 	// GetDB returns the underlying GORM database instance.
 	// The returned *gorm.DB is safe for concurrent use.
 	GetDB() *gorm.DB
+
+	// Stats returns the connection pool statistics of the database.
+	Stats() sql.DBStats
 }
 
 // HealthChecker defines the interface for health check operations.
@@ -56,14 +62,67 @@ type Registry struct {
 	impl *internal.Registry
 }
 
+// RegistryOptions configures a Registry.
+type RegistryOptions struct {
+	Logger log.Logger // Logger for close failures (optional)
+}
+
+// RegistryOption is a function that configures RegistryOptions.
+type RegistryOption func(*RegistryOptions)
+
+// WithRegistryLogger sets the logger used to report stores that fail or
+// time out in CloseAll.
+func WithRegistryLogger(logger log.Logger) RegistryOption {
+	return func(o *RegistryOptions) {
+		o.Logger = logger
+	}
+}
+
 // NewRegistry creates a new storage registry.
-func NewRegistry() *Registry {
-	return &Registry{impl: internal.NewRegistry()}
+func NewRegistry(opts ...RegistryOption) *Registry {
+	var options RegistryOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	impl := internal.NewRegistry()
+	impl.SetLogger(options.Logger)
+	return &Registry{impl: impl}
+}
+
+// RegisterOptions configures a registered store.
+type RegisterOptions struct {
+	DegradedThreshold time.Duration // Ping latency reported as degraded (default: 500ms)
+}
+
+// RegisterOption is a function that configures RegisterOptions.
+type RegisterOption func(*RegisterOptions)
+
+// WithDegradedThreshold sets the ping latency above which HealthReport
+// reports the store as degraded instead of ok.
+func WithDegradedThreshold(d time.Duration) RegisterOption {
+	return func(o *RegisterOptions) {
+		o.DegradedThreshold = d
+	}
 }
 
 // Register registers a storage backend with the given name.
-func (r *Registry) Register(name string, store Store) error {
-	return r.impl.Register(name, store)
+func (r *Registry) Register(name string, store Store, opts ...RegisterOption) error {
+	var options RegisterOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := r.impl.Register(name, store); err != nil {
+		return err
+	}
+	if options.DegradedThreshold != 0 {
+		if err := r.impl.SetDegradedThreshold(name, options.DegradedThreshold); err != nil {
+			r.impl.Unregister(name)
+			return err
+		}
+	}
+	return nil
 }
 
 // Unregister removes a storage backend from the registry.
@@ -76,11 +135,76 @@ func (r *Registry) Ping(ctx context.Context) error {
 	return r.impl.Ping(ctx)
 }
 
+// DefaultDegradedThreshold is the ping latency above which HealthReport
+// reports a store as degraded unless WithDegradedThreshold is given.
+const DefaultDegradedThreshold = internal.DefaultDegradedThreshold
+
+// HealthState is the health of a store in a HealthReport.
+type HealthState = internal.HealthState
+
+// Health states reported by HealthReport.
+const (
+	HealthOK       = internal.HealthOK       // Ping succeeded within the threshold
+	HealthDegraded = internal.HealthDegraded // Ping succeeded but was slow
+	HealthDown     = internal.HealthDown     // Ping failed or timed out
+)
+
+// HealthStatus is the state, ping latency and error of one store.
+type HealthStatus = internal.HealthStatus
+
+// HealthReport pings every store concurrently and reports its state and
+// latency, distinguishing slow stores from unavailable ones.
+//
+// Parameters:
+//   - ctx: Context for the pings, bounded to 5 seconds like Ping
+//
+// Returns:
+//   - map[string]HealthStatus: Status of every registered store by name
+//
+// Example:
+//
+//	for name, status := range registry.HealthReport(ctx) {
+//	  if status.State != storex.HealthOK {
+//	    logger.Warn("store unhealthy", "store", name, "state", status.State, "latency", status.Latency)
+//	  }
+//	}
+func (r *Registry) HealthReport(ctx context.Context) map[string]HealthStatus {
+	return r.impl.HealthReport(ctx)
+}
+
 // Close closes all registered storage connections.
+// Close waits for every store; use CloseAll to bound shutdown time.
 func (r *Registry) Close() error {
 	return r.impl.Close()
 }
 
+// DefaultCloseTimeout bounds CloseAll when the context has no deadline.
+const DefaultCloseTimeout = internal.DefaultCloseTimeout
+
+// CloseAll closes all registered stores concurrently, bounded by ctx.
+//
+// Each store gets until ctx's deadline, or DefaultCloseTimeout if ctx has
+// none. A store that hangs in Close is reported as timed out and does not
+// delay the others.
+//
+// Parameters:
+//   - ctx: Shutdown context whose deadline bounds every Close call
+//
+// Returns:
+//   - error: nil if every store closed; otherwise the failed and timed out
+//     stores joined into one error, also logged with the registry logger
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := registry.CloseAll(ctx); err != nil {
+//	  logger.Error(err, "failed to close stores")
+//	}
+func (r *Registry) CloseAll(ctx context.Context) error {
+	return r.impl.CloseAll(ctx)
+}
+
 // List returns the names of all registered stores.
 func (r *Registry) List() []string {
 	return r.impl.List()
@@ -140,3 +264,348 @@ func NewSQLiteStore(dsn string, logger log.Logger) (GORMStore, error) {
 		Logger: logger,
 	})
 }
+
+// Default retry settings for WithinTransaction.
+const (
+	DefaultTxMaxRetries = 3
+	DefaultTxBackoff    = 10 * time.Millisecond
+)
+
+// TxOptions configures WithinTransaction.
+type TxOptions struct {
+	MaxRetries int            // Retries after a deadlock or serialization failure (default: 3)
+	Backoff    time.Duration  // Delay before the first retry, doubled per retry (default: 10ms)
+	SQL        *sql.TxOptions // Isolation level and read-only mode (default: driver defaults)
+}
+
+// TxOption is a function that configures TxOptions.
+type TxOption func(*TxOptions)
+
+// WithTxRetries sets how many times a transaction is retried after a
+// deadlock or serialization failure. Zero disables retries.
+func WithTxRetries(n int) TxOption {
+	return func(o *TxOptions) {
+		o.MaxRetries = n
+	}
+}
+
+// WithTxBackoff sets the delay before the first retry; each further retry
+// waits twice as long as the previous one.
+func WithTxBackoff(d time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.Backoff = d
+	}
+}
+
+// WithTxIsolation runs the transaction at the given isolation level. Retries
+// are most useful with sql.LevelSerializable or sql.LevelRepeatableRead.
+func WithTxIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *TxOptions) {
+		o.SQL = &sql.TxOptions{Isolation: level}
+	}
+}
+
+// WithinTransaction runs fn in a GORM transaction, committing if fn returns
+// nil and rolling back otherwise.
+//
+// Transactions failing with a deadlock or serialization failure (MySQL error
+// 1213, PostgreSQL SQLSTATE 40001 or 40P01) are rolled back and run again, so
+// fn must be safe to repeat and only touch the database through tx.
+//
+// Parameters:
+//   - ctx: Bound to every attempt; cancellation stops further retries
+//   - db: Database to begin transactions on, e.g. GORMStore.GetDB()
+//   - fn: Transaction body
+//   - opts: Retry and isolation options
+//
+// Returns:
+//   - error: nil on commit; otherwise the last error, annotated with the
+//     number of attempts and matchable with errors.Is and errors.As
+//
+// Example:
+//
+//	err := storex.WithinTransaction(ctx, store.GetDB(), func(tx *gorm.DB) error {
+//	  if err := tx.Model(&from).Update("balance", gorm.Expr("balance - ?", amount)).Error; err != nil {
+//	    return err
+//	  }
+//	  return tx.Model(&to).Update("balance", gorm.Expr("balance + ?", amount)).Error
+//	}, storex.WithTxIsolation(sql.LevelSerializable))
+func WithinTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error, opts ...TxOption) error {
+	options := TxOptions{
+		MaxRetries: DefaultTxMaxRetries,
+		Backoff:    DefaultTxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return internal.RunInTransaction(ctx, db, fn, internal.TxConfig{
+		MaxRetries: options.MaxRetries,
+		Backoff:    options.Backoff,
+		TxOptions:  options.SQL,
+	})
+}
+
+// IsRetryableTxError reports whether err is a deadlock or serialization
+// failure that WithinTransaction retries.
+func IsRetryableTxError(err error) bool {
+	return internal.IsRetryableTxError(err)
+}
+
+// DefaultSlowQueryThreshold is the slow query threshold used when
+// QueryMetricsOptions.SlowThreshold is unset.
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// SlowQuery describes a query that exceeded the slow query threshold.
+type SlowQuery = internal.SlowQuery
+
+// QueryMetricsOptions configures EnableQueryMetrics.
+type QueryMetricsOptions struct {
+	Name          string                                 // db_name attribute of all metrics (default: "default")
+	SlowThreshold time.Duration                          // Slow query threshold (default: 200ms, negative disables)
+	MeterProvider metric.MeterProvider                   // Provider for pool and query metrics (nil = callback only)
+	OnSlowQuery   func(ctx context.Context, q SlowQuery) // Called for each slow query (optional)
+}
+
+// EnableQueryMetrics registers the connection pool metrics of db and installs
+// a GORM plugin recording query durations and counting slow queries, all
+// labeled with db_name.
+//
+// Metrics recorded:
+//   - db_pool_*: Pool gauges and wait counters from sql.DBStats, as in obsx
+//   - db_query_duration_seconds: Query duration histogram (also labeled operation)
+//   - db_slow_queries_total: Queries taking at least SlowThreshold (also labeled operation)
+//
+// Pool metrics require a MeterProvider. Do not also call obsx's
+// RegisterDBMetrics for the same database, or the pool metrics are exported
+// twice.
+//
+// Parameters:
+//   - db: Database to instrument, e.g. GORMStore.GetDB()
+//   - opts: Metric provider, threshold and slow query callback
+//
+// Returns:
+//   - error: instrument creation or plugin registration error; the plugin
+//     can only be installed once per database
+//
+// Example:
+//
+//	err := storex.EnableQueryMetrics(store.GetDB(), storex.QueryMetricsOptions{
+//	  Name:          "main",
+//	  MeterProvider: otelProvider.MeterProvider(),
+//	  OnSlowQuery: func(ctx context.Context, q storex.SlowQuery) {
+//	    logger.Warn("slow query", "sql", q.SQL, "duration", q.Duration)
+//	  },
+//	})
+func EnableQueryMetrics(db *gorm.DB, opts QueryMetricsOptions) error {
+	if db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
+	if opts.SlowThreshold == 0 {
+		opts.SlowThreshold = DefaultSlowQueryThreshold
+	}
+
+	plugin, err := internal.NewQueryMetricsPlugin(internal.QueryMetricsConfig{
+		Name:          opts.Name,
+		SlowThreshold: opts.SlowThreshold,
+		MeterProvider: opts.MeterProvider,
+		OnSlowQuery:   opts.OnSlowQuery,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create query metrics: %w", err)
+	}
+
+	if err := db.Use(plugin); err != nil {
+		return fmt.Errorf("failed to register query metrics plugin: %w", err)
+	}
+
+	if opts.MeterProvider == nil {
+		return nil
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB for pool metrics: %w", err)
+	}
+	if err := internal.RegisterPoolMetrics(opts.Name, sqlDB.Stats, opts.MeterProvider); err != nil {
+		return fmt.Errorf("failed to register pool metrics: %w", err)
+	}
+	return nil
+}
+
+// ReplicaSet is a GORMStore that sends writes to a primary and reads to
+// healthy replicas.
+type ReplicaSet struct {
+	impl    *internal.ReplicaSet
+	primary GORMStore
+}
+
+// NewReplicaSet wraps a primary and its read replicas.
+//
+// The routing plugin is installed on primary.GetDB(), so every query made
+// through it picks a replica in round-robin order. Statements stay on the
+// primary when they:
+//   - create, update, delete or execute raw SQL
+//   - run inside a transaction, including WithinTransaction
+//   - take row locks (clause.Locking, SELECT ... FOR UPDATE)
+//   - use a context returned by WithPrimary
+//
+// Replicas start out healthy. Ping, usually driven by a Registry, marks
+// replicas that fail to respond as down, and they are skipped until a later
+// ping succeeds. Reads use the primary when no replica is healthy.
+//
+// Parameters:
+//   - primary: Store receiving writes, and reads when no replica is healthy
+//   - replicas: Read replicas of primary
+//
+// Returns:
+//   - *ReplicaSet: Store to register and query through
+//   - error: nil primary or replica, or plugin registration error
+//
+// Example:
+//
+//	primary, _ := storex.NewMySQLStore(primaryDSN, logger)
+//	replica, _ := storex.NewMySQLStore(replicaDSN, logger)
+//	rs, err := storex.NewReplicaSet(primary, replica)
+//	registry.Register("main", rs)
+//	rs.GetDB().Find(&users) // runs on the replica
+func NewReplicaSet(primary GORMStore, replicas ...GORMStore) (*ReplicaSet, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("primary database connection is nil")
+	}
+	internalReplicas := make([]internal.ReplicaStore, 0, len(replicas))
+	for i, replica := range replicas {
+		if replica == nil {
+			return nil, fmt.Errorf("replica %d database connection is nil", i)
+		}
+		internalReplicas = append(internalReplicas, replica)
+	}
+
+	impl, err := internal.NewReplicaSet(primary, internalReplicas...)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplicaSet{impl: impl, primary: primary}, nil
+}
+
+// Ping checks the primary and all replicas, updating replica health.
+// Only a primary failure is returned; down replicas are skipped for reads.
+func (rs *ReplicaSet) Ping(ctx context.Context) error {
+	return rs.impl.Ping(ctx)
+}
+
+// Close closes the replicas and the primary.
+func (rs *ReplicaSet) Close() error {
+	return rs.impl.Close()
+}
+
+// GetDB returns the primary database with read routing installed.
+func (rs *ReplicaSet) GetDB() *gorm.DB {
+	return rs.impl.GetDB()
+}
+
+// Stats returns the connection pool statistics of the primary.
+func (rs *ReplicaSet) Stats() sql.DBStats {
+	return rs.impl.Stats()
+}
+
+// Primary returns the primary store.
+func (rs *ReplicaSet) Primary() GORMStore {
+	return rs.primary
+}
+
+// HealthyReplicas returns the number of replicas that passed the last ping.
+func (rs *ReplicaSet) HealthyReplicas() int {
+	return rs.impl.HealthyReplicas()
+}
+
+// WithPrimary returns a context that keeps every statement on the primary,
+// for reads that must observe a preceding write.
+//
+// Example:
+//
+//	ctx = storex.WithPrimary(ctx)
+//	rs.GetDB().WithContext(ctx).First(&order, id)
+func WithPrimary(ctx context.Context) context.Context {
+	return internal.WithPrimary(ctx)
+}
+
+// MigrationsTable is the table in which Migrator records applied migrations.
+const MigrationsTable = internal.MigrationsTable
+
+// Migration is a versioned schema or data change with an ID, an Up function
+// and an optional Down function. Both receive the migration's transaction.
+type Migration = internal.Migration
+
+// Migrator runs ordered, versioned migrations for changes AutoMigrate cannot
+// make safely, such as data migrations, renames and drops.
+type Migrator struct {
+	impl *internal.Migrator
+}
+
+// NewMigrator creates a migrator for migrations, applied in the given order.
+//
+// Applied migration IDs are recorded in the schema_migrations table, created
+// by the first Up or Down. Each migration runs in its own transaction together with its
+// record, so a failed migration is rolled back and retried on the next run.
+// MySQL commits DDL statements implicitly; keep such migrations to a single
+// statement so a failure cannot leave them half applied.
+//
+// Parameters:
+//   - db: Database to migrate, e.g. GORMStore.GetDB()
+//   - migrations: Migrations in application order; IDs must be unique
+//
+// Returns:
+//   - *Migrator: Migrator ready to run
+//   - error: nil db, empty or duplicate ID, or missing Up function
+//
+// Concurrency:
+//   - Up and Down hold a database-wide lock, so instances starting together
+//     apply each migration once: an advisory lock on PostgreSQL and MySQL,
+//     the schema_migrations_lock row elsewhere
+//   - The advisory lock keeps one pool connection checked out while
+//     migrations run, which needs a pool of at least two connections
+//   - A run that crashes while holding schema_migrations_lock leaves its row
+//     behind; delete it before migrating again
+//
+// Example:
+//
+//	migrator, err := storex.NewMigrator(store.GetDB(),
+//	  storex.Migration{
+//	    ID: "20240601_backfill_order_status",
+//	    Up: func(tx *gorm.DB) error {
+//	      return tx.Exec("UPDATE orders SET status = 'open' WHERE status IS NULL").Error
+//	    },
+//	  },
+//	)
+//	pending, _ := migrator.Pending(ctx) // dry run
+//	applied, err := migrator.Up(ctx)
+func NewMigrator(db *gorm.DB, migrations ...Migration) (*Migrator, error) {
+	impl, err := internal.NewMigrator(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{impl: impl}, nil
+}
+
+// Pending returns the IDs of migrations Up would apply, in order, without
+// applying them (a dry run). It only reads and takes no lock; before the
+// first Up every migration is pending.
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	return m.impl.Pending(ctx)
+}
+
+// Up applies every pending migration in order and returns the IDs applied.
+// It stops at the first failing migration; earlier ones stay applied. Up
+// waits for the migration lock until ctx is done.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	return m.impl.Up(ctx)
+}
+
+// Down reverts the last steps applied migrations, latest first, and returns
+// the IDs reverted. It fails at a migration without a Down function.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	return m.impl.Down(ctx, steps)
+}
@@ -3,10 +3,15 @@ package storex
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"go.eggybyte.com/egg/core/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gorm.io/gorm"
 )
 
 // testLogger is a test logger implementation.
@@ -224,3 +229,146 @@ func TestConvenienceFunctions(t *testing.T) {
 		}
 	})
 }
+
+func TestWithinTransaction_NilDB(t *testing.T) {
+	err := WithinTransaction(context.Background(), nil, func(tx *gorm.DB) error { return nil })
+	if err == nil {
+		t.Error("Expected error for nil database")
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	if !IsRetryableTxError(&mysql.MySQLError{Number: 1213}) {
+		t.Error("Expected MySQL deadlock to be retryable")
+	}
+	if IsRetryableTxError(errors.New("constraint violation")) {
+		t.Error("Expected generic error not to be retryable")
+	}
+}
+
+func TestEnableQueryMetrics_NilDB(t *testing.T) {
+	if err := EnableQueryMetrics(nil, QueryMetricsOptions{}); err == nil {
+		t.Error("Expected error for nil database")
+	}
+}
+
+func TestEnableQueryMetrics_PoolAndQueryMetrics(t *testing.T) {
+	store, err := NewSQLiteStore("file:query_metrics?mode=memory&cache=shared", nil)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	reader := sdkmetric.NewManualReader()
+	if err := EnableQueryMetrics(store.GetDB(), QueryMetricsOptions{
+		Name:          "main",
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}); err != nil {
+		t.Fatalf("EnableQueryMetrics() error = %v", err)
+	}
+	if err := store.GetDB().Exec("SELECT 1").Error; err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+		}
+	}
+	for _, name := range []string{"db_pool_open_connections", "db_pool_in_use", "db_query_duration_seconds"} {
+		if !found[name] {
+			t.Errorf("Expected metric %s, got %v", name, found)
+		}
+	}
+}
+
+func TestReplicaSet(t *testing.T) {
+	t.Run("NilPrimary", func(t *testing.T) {
+		if _, err := NewReplicaSet(nil); err == nil {
+			t.Error("Expected error for nil primary")
+		}
+	})
+
+	t.Run("RegistryPing", func(t *testing.T) {
+		primary, err := NewSQLiteStore("file:replicaset_primary?mode=memory&cache=shared", nil)
+		if err != nil {
+			t.Fatalf("Failed to open primary: %v", err)
+		}
+		replica, err := NewSQLiteStore("file:replicaset_replica?mode=memory&cache=shared", nil)
+		if err != nil {
+			t.Fatalf("Failed to open replica: %v", err)
+		}
+
+		rs, err := NewReplicaSet(primary, replica)
+		if err != nil {
+			t.Fatalf("Failed to create replica set: %v", err)
+		}
+		var _ GORMStore = rs
+
+		registry := NewRegistry()
+		if err := registry.Register("main", rs); err != nil {
+			t.Fatalf("Failed to register replica set: %v", err)
+		}
+
+		replica.Close()
+		if err := registry.Ping(context.Background()); err != nil {
+			t.Errorf("Expected ping to succeed with a primary, got %v", err)
+		}
+		if rs.HealthyReplicas() != 0 {
+			t.Errorf("Expected 0 healthy replicas, got %d", rs.HealthyReplicas())
+		}
+		if rs.Primary() != primary {
+			t.Error("Expected Primary to return the primary store")
+		}
+
+		if err := registry.Close(); err != nil {
+			t.Errorf("Failed to close registry: %v", err)
+		}
+	})
+}
+
+func TestRegistryCloseAll(t *testing.T) {
+	logger := &testLogger{}
+	registry := NewRegistry(WithRegistryLogger(logger))
+	registry.Register("ok", &mockStore{})
+	registry.Register("broken", &mockStore{closeErr: errors.New("close failed")})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := registry.CloseAll(ctx); err == nil {
+		t.Error("Expected error from failing store")
+	}
+	if len(logger.logs) != 1 || logger.logs[0] != "ERROR: store close failed" {
+		t.Errorf("Expected close failure to be logged, got %v", logger.logs)
+	}
+}
+
+func TestRegistryHealthReport(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("ok", &mockStore{}, WithDegradedThreshold(time.Second)); err != nil {
+		t.Fatalf("Failed to register store: %v", err)
+	}
+	if err := registry.Register("down", &mockStore{pingErr: errors.New("unreachable")}); err != nil {
+		t.Fatalf("Failed to register store: %v", err)
+	}
+	if err := registry.Register("bad", &mockStore{}, WithDegradedThreshold(-time.Second)); err == nil {
+		t.Error("Expected error for negative threshold")
+	}
+	if _, exists := registry.Get("bad"); exists {
+		t.Error("Expected store with invalid options not to be registered")
+	}
+
+	report := registry.HealthReport(context.Background())
+	if report["ok"].State != HealthOK {
+		t.Errorf("Expected ok store to be %q, got %q", HealthOK, report["ok"].State)
+	}
+	if report["down"].State != HealthDown || report["down"].Err == nil {
+		t.Errorf("Expected down store to be %q with error, got %+v", HealthDown, report["down"])
+	}
+}
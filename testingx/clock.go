@@ -0,0 +1,207 @@
+package testingx
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.eggybyte.com/egg/core/clock"
+)
+
+// FakeClock is a clock.Clock whose time only moves when Advance is called.
+// It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+	nextSeq uint64
+}
+
+// fakeTimer is a timer created by a FakeClock.
+type fakeTimer struct {
+	clock  *FakeClock
+	ch     chan time.Time // nil for AfterFunc timers
+	fn     func()
+	when   time.Time
+	seq    uint64 // creation order, to fire timers with equal deadlines in order
+	active bool
+}
+
+// NewFakeClock creates a fake clock set to start.
+//
+// Example:
+//
+//	fake := testingx.NewFakeClock(time.Unix(0, 0))
+//	client := clientx.NewHTTPClient(url, clientx.WithClock(fake))
+//	go client.Do(req)
+//	fake.BlockUntil(1)                  // the client is waiting to retry
+//	fake.Advance(100 * time.Millisecond) // skip the backoff
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+var _ clock.Clock = (*FakeClock)(nil)
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the fake time once it has advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer creates a timer firing once the fake time has advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	return c.add(d, make(chan time.Time, 1), nil)
+}
+
+// AfterFunc calls f once the fake time has advanced by d. f runs during the
+// Advance call that reaches its deadline, so its effects are visible when
+// Advance returns.
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	return c.add(d, nil, f)
+}
+
+// Advance moves the fake time forward by d, firing every timer whose
+// deadline is reached, in deadline order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		t := c.nextDue(target)
+		if t == nil {
+			break
+		}
+		c.now = t.when
+		c.fire(t) // Unlocks while running AfterFunc callbacks.
+	}
+	c.now = target
+	c.mu.Unlock()
+}
+
+// Pending returns the number of timers that have not fired or been stopped.
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil waits until at least n timers are pending, e.g. until the code
+// under test has started waiting on the clock.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// add registers a timer; timers already due fire immediately.
+func (c *FakeClock) add(d time.Duration, ch chan time.Time, fn func()) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, ch: ch, fn: fn}
+	c.schedule(t, d)
+	return t
+}
+
+// schedule arms t to fire after d. Must be called with c.mu held.
+func (c *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	t.when = c.now.Add(d)
+	c.nextSeq++
+	t.seq = c.nextSeq
+	if d <= 0 {
+		// Like time.NewTimer, a non-positive duration fires right away.
+		t.active = false
+		if t.ch != nil {
+			t.send(c.now)
+		} else {
+			go t.fn()
+		}
+		return
+	}
+	t.active = true
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+}
+
+// nextDue returns the earliest pending timer due at or before target.
+// Must be called with c.mu held.
+func (c *FakeClock) nextDue(target time.Time) *fakeTimer {
+	sort.Slice(c.timers, func(i, j int) bool {
+		a, b := c.timers[i], c.timers[j]
+		if !a.when.Equal(b.when) {
+			return a.when.Before(b.when)
+		}
+		return a.seq < b.seq
+	})
+	if len(c.timers) == 0 || c.timers[0].when.After(target) {
+		return nil
+	}
+	return c.timers[0]
+}
+
+// fire removes and fires t. Must be called with c.mu held; the lock is
+// released while an AfterFunc callback runs.
+func (c *FakeClock) fire(t *fakeTimer) {
+	c.remove(t)
+	if t.ch != nil {
+		t.send(c.now)
+		return
+	}
+	c.mu.Unlock()
+	t.fn()
+	c.mu.Lock()
+}
+
+// remove deletes t from the pending timers. Must be called with c.mu held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// send delivers now without blocking, dropping it if a value is unread.
+func (t *fakeTimer) send(now time.Time) {
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+// C implements clock.Timer.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// Stop implements clock.Timer.
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+// Reset implements clock.Timer.
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d)
+	return active
+}
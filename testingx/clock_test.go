@@ -0,0 +1,149 @@
+// Package testingx provides tests for the fake clock.
+package testingx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.eggybyte.com/egg/core/clock"
+)
+
+func TestFakeClock_Now(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	if !c.Now().Equal(start) {
+		t.Errorf("Now() = %v, want %v", c.Now(), start)
+	}
+	c.Advance(time.Minute)
+	if want := start.Add(time.Minute); !c.Now().Equal(want) {
+		t.Errorf("Now() after Advance = %v, want %v", c.Now(), want)
+	}
+}
+
+func TestFakeClock_Timer(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	timer := c.NewTimer(time.Second)
+
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	select {
+	case fired := <-timer.C():
+		if !fired.Equal(time.Unix(1, 0)) {
+			t.Errorf("timer fired at %v, want %v", fired, time.Unix(1, 0))
+		}
+	default:
+		t.Fatal("timer did not fire at its deadline")
+	}
+	if timer.Stop() {
+		t.Error("Stop() on a fired timer = true, want false")
+	}
+}
+
+func TestFakeClock_After(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	ch := c.After(time.Second)
+	c.Advance(2 * time.Second)
+	select {
+	case <-ch:
+	default:
+		t.Fatal("After() channel did not fire")
+	}
+}
+
+func TestFakeClock_StopAndReset(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	timer := c.NewTimer(time.Second)
+
+	if !timer.Stop() {
+		t.Error("Stop() on a pending timer = false, want true")
+	}
+	if c.Pending() != 0 {
+		t.Errorf("Pending() = %d, want 0", c.Pending())
+	}
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Reset() of a stopped timer = true, want false")
+	}
+	c.Advance(time.Second)
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("reset timer did not fire")
+	}
+}
+
+func TestFakeClock_AfterFuncOrder(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	var order []int
+	c.AfterFunc(3*time.Second, func() { order = append(order, 3) })
+	c.AfterFunc(time.Second, func() { order = append(order, 1) })
+	c.AfterFunc(2*time.Second, func() {
+		order = append(order, 2)
+		// Callbacks may use the clock; the new timer is due within this Advance.
+		c.AfterFunc(500*time.Millisecond, func() { order = append(order, 25) })
+	})
+
+	c.Advance(3 * time.Second)
+
+	want := []int{1, 2, 25, 3}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestFakeClock_NonPositiveDuration(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	select {
+	case <-c.After(0):
+	default:
+		t.Fatal("After(0) did not fire immediately")
+	}
+
+	fired := make(chan struct{})
+	c.AfterFunc(-time.Second, func() { close(fired) })
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc with negative duration did not run")
+	}
+}
+
+func TestFakeClock_BlockUntil(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	var woke atomic.Bool
+	done := make(chan error)
+	go func() {
+		err := clock.Sleep(context.Background(), c, time.Hour)
+		woke.Store(true)
+		done <- err
+	}()
+
+	c.BlockUntil(1)
+	if woke.Load() {
+		t.Fatal("Sleep returned before the clock advanced")
+	}
+	c.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Errorf("Sleep() error = %v", err)
+	}
+}
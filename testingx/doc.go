@@ -8,18 +8,24 @@
 //
 // # Features
 //
-//   - MockLogger with in-memory capture and assertions
+//   - MockLogger with in-memory capture and message and field assertions
 //   - Context helpers for identity and request metadata
 //   - Error assertion helpers for core/errors codes
+//   - In-memory Connect interceptor harness with context and header capture
+//   - In-memory OpenTelemetry metrics recorder with value assertions
+//   - FakeClock implementing core/clock for tests that must not sleep
+//   - TestServer running handlers behind interceptors and middleware, with a clientx client
 //
 // # Usage
 //
 //	logger := testingx.NewMockLogger(t)
 //	ctx := testingx.NewContextWithIdentity(t, &identity.UserInfo{UserID: "u-1"})
+//	logger.AssertLogged("ERROR", "failed to load user", map[string]any{"user_id": "u-1"})
 //
 // # Layer
 //
-// testingx is an auxiliary module for tests only and depends on core modules.
+// testingx is an auxiliary module for tests only and depends on core modules,
+// clientx, connect-go and the OpenTelemetry metrics SDK.
 //
 // # Stability
 //
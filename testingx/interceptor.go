@@ -0,0 +1,214 @@
+package testingx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.eggybyte.com/egg/core/errors"
+)
+
+// DefaultTestProcedure is the procedure an InterceptorTest calls unless
+// WithProcedure is set.
+const DefaultTestProcedure = "/test.v1.TestService/Call"
+
+// InterceptorTest runs Connect interceptors around a fake unary handler
+// in memory, without starting a server.
+type InterceptorTest struct {
+	t            *testing.T
+	interceptors []connect.Interceptor
+	procedure    string
+	ctx          context.Context
+	header       http.Header
+	handler      connect.UnaryFunc
+}
+
+// InterceptorResult describes one call through the interceptor chain.
+type InterceptorResult struct {
+	t *testing.T
+
+	Called        bool            // Whether the chain reached the handler
+	Context       context.Context // Context seen by the handler, including interceptor mutations
+	RequestHeader http.Header     // Request headers seen by the handler
+	Header        http.Header     // Response headers sent to the caller
+	Trailer       http.Header     // Response trailers sent to the caller
+	Err           error           // Error returned by the outermost interceptor, before encoding
+}
+
+// NewInterceptorTest creates a test for the given interceptors, applied in
+// the same order as connect.WithInterceptors.
+//
+// Parameters:
+//   - t: Test used to report failures
+//   - interceptors: Interceptor chain under test
+//
+// Returns:
+//   - *InterceptorTest: Builder for the request; call Run to execute it
+//
+// Example:
+//
+//	interceptors := connectx.DefaultInterceptors(opts)
+//	result := testingx.NewInterceptorTest(t, interceptors...).
+//		WithProcedure("/user.v1.UserService/GetProfile").
+//		WithHeader("X-User-Id", "u-1").
+//		Run()
+//	result.AssertNoError()
+func NewInterceptorTest(t *testing.T, interceptors ...connect.Interceptor) *InterceptorTest {
+	t.Helper()
+	return &InterceptorTest{
+		t:            t,
+		interceptors: interceptors,
+		procedure:    DefaultTestProcedure,
+		ctx:          context.Background(),
+		header:       make(http.Header),
+		handler: func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+	}
+}
+
+// WithProcedure sets the full procedure name, e.g. "/user.v1.UserService/GetUser".
+func (it *InterceptorTest) WithProcedure(procedure string) *InterceptorTest {
+	it.procedure = procedure
+	return it
+}
+
+// WithContext sets the context the request is served with.
+func (it *InterceptorTest) WithContext(ctx context.Context) *InterceptorTest {
+	it.ctx = ctx
+	return it
+}
+
+// WithHeader adds a request header.
+func (it *InterceptorTest) WithHeader(key, value string) *InterceptorTest {
+	it.header.Add(key, value)
+	return it
+}
+
+// WithHandler sets the handler at the end of the chain. The default handler
+// returns an empty response. Headers and trailers of the returned response
+// are sent to the caller.
+func (it *InterceptorTest) WithHandler(handler connect.UnaryFunc) *InterceptorTest {
+	it.handler = handler
+	return it
+}
+
+// Run sends one unary request through the interceptor chain.
+func (it *InterceptorTest) Run() *InterceptorResult {
+	it.t.Helper()
+	result := &InterceptorResult{t: it.t}
+
+	// The capture interceptor is outermost, so it sees the error the chain
+	// returns before it is encoded for the wire.
+	capture := connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resp, err := next(ctx, req)
+			result.Err = err
+			return resp, err
+		}
+	})
+	interceptors := append([]connect.Interceptor{capture}, it.interceptors...)
+
+	handler := connect.NewUnaryHandler(it.procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			result.Called = true
+			result.Context = ctx
+			result.RequestHeader = req.Header().Clone()
+
+			resp, err := it.handler(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			out := connect.NewResponse(&emptypb.Empty{})
+			if resp != nil {
+				copyHeader(out.Header(), resp.Header())
+				copyHeader(out.Trailer(), resp.Trailer())
+			}
+			return out, nil
+		},
+		connect.WithInterceptors(interceptors...),
+	)
+
+	req := httptest.NewRequestWithContext(it.ctx, http.MethodPost, it.procedure, bytes.NewReader(nil))
+	for key, values := range it.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	req.Header.Set("Content-Type", "application/proto")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	defer resp.Body.Close()
+	result.Header = resp.Header
+	result.Trailer = make(http.Header)
+	for key, values := range resp.Header {
+		// Connect sends unary trailers as prefixed headers.
+		if name, ok := cutTrailerPrefix(key); ok {
+			result.Trailer[name] = values
+		}
+	}
+	return result
+}
+
+// AssertNoError asserts that the chain returned no error.
+func (r *InterceptorResult) AssertNoError() {
+	r.t.Helper()
+	AssertNoError(r.t, r.Err)
+}
+
+// AssertError asserts that the chain returned an error with the given
+// core/errors code, before any mapping to Connect codes.
+func (r *InterceptorResult) AssertError(expectedCode errors.Code) {
+	r.t.Helper()
+	AssertError(r.t, r.Err, expectedCode)
+}
+
+// AssertConnectCode asserts that the chain returned an error with the given
+// Connect code, as a client would see it.
+func (r *InterceptorResult) AssertConnectCode(expectedCode connect.Code) {
+	r.t.Helper()
+	if r.Err == nil {
+		r.t.Fatalf("Expected error with Connect code %s, got nil", expectedCode)
+	}
+
+	if code := connect.CodeOf(r.Err); code != expectedCode {
+		r.t.Errorf("Expected Connect code %s, got %s", expectedCode, code)
+	}
+}
+
+// AssertHeader asserts that the response header key has the given value.
+func (r *InterceptorResult) AssertHeader(key, value string) {
+	r.t.Helper()
+	if got := r.Header.Get(key); got != value {
+		r.t.Errorf("Expected response header %s=%q, got %q", key, value, got)
+	}
+}
+
+// AssertCalled asserts whether the chain reached the handler.
+func (r *InterceptorResult) AssertCalled(called bool) {
+	r.t.Helper()
+	if r.Called != called {
+		r.t.Errorf("Expected handler called=%t, got %t", called, r.Called)
+	}
+}
+
+func copyHeader(dst, src http.Header) {
+	for key, values := range src {
+		dst[key] = append(dst[key], values...)
+	}
+}
+
+// cutTrailerPrefix strips the Connect unary trailer prefix from a header key.
+func cutTrailerPrefix(key string) (string, bool) {
+	const prefix = "Trailer-"
+	if len(key) > len(prefix) && http.CanonicalHeaderKey(key[:len(prefix)]) == prefix {
+		return http.CanonicalHeaderKey(key[len(prefix):]), true
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+// Package testingx provides tests for the Connect interceptor harness.
+package testingx
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	"go.eggybyte.com/egg/core/errors"
+	"go.eggybyte.com/egg/core/identity"
+)
+
+// identityInterceptor stores the X-User-Id header as the request identity.
+func identityInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if userID := req.Header().Get("X-User-Id"); userID != "" {
+				ctx = identity.WithUser(ctx, &identity.UserInfo{UserID: userID})
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// requireUserInterceptor rejects requests without identity with a core error.
+func requireUserInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if _, ok := identity.UserFrom(ctx); !ok {
+				return nil, errors.New(errors.CodeUnauthenticated, "identity required")
+			}
+			resp, err := next(ctx, req)
+			if resp != nil {
+				resp.Header().Set("X-Procedure", req.Spec().Procedure)
+			}
+			return resp, err
+		}
+	}
+}
+
+func TestInterceptorTest_ContextMutation(t *testing.T) {
+	result := NewInterceptorTest(t, identityInterceptor(), requireUserInterceptor()).
+		WithProcedure("/user.v1.UserService/GetUser").
+		WithHeader("X-User-Id", "u-1").
+		Run()
+
+	result.AssertNoError()
+	result.AssertCalled(true)
+	result.AssertHeader("X-Procedure", "/user.v1.UserService/GetUser")
+
+	user, ok := identity.UserFrom(result.Context)
+	if !ok || user.UserID != "u-1" {
+		t.Errorf("handler identity = %+v, want u-1", user)
+	}
+	if got := result.RequestHeader.Get("X-User-Id"); got != "u-1" {
+		t.Errorf("handler header X-User-Id = %q, want u-1", got)
+	}
+}
+
+func TestInterceptorTest_Error(t *testing.T) {
+	result := NewInterceptorTest(t, identityInterceptor(), requireUserInterceptor()).Run()
+
+	result.AssertCalled(false)
+	result.AssertError(errors.CodeUnauthenticated)
+	if result.Context != nil {
+		t.Error("Context should be nil when the handler is not reached")
+	}
+}
+
+func TestInterceptorTest_ConnectCode(t *testing.T) {
+	result := NewInterceptorTest(t).
+		WithHandler(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			return nil, connect.NewError(connect.CodeNotFound, nil)
+		}).
+		Run()
+
+	result.AssertCalled(true)
+	result.AssertConnectCode(connect.CodeNotFound)
+}
+
+func TestInterceptorTest_WithContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	result := NewInterceptorTest(t).WithContext(ctx).Run()
+
+	result.AssertNoError()
+	if got := result.Context.Value(key{}); got != "value" {
+		t.Errorf("handler context value = %v, want value", got)
+	}
+}
+
+func TestInterceptorTest_HandlerHeaders(t *testing.T) {
+	result := NewInterceptorTest(t).
+		WithHandler(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resp := connect.NewResponse(&struct{}{})
+			resp.Header().Set("X-Request-Id", "req-1")
+			resp.Trailer().Set("X-Rows", "3")
+			return resp, nil
+		}).
+		Run()
+
+	result.AssertNoError()
+	result.AssertHeader("X-Request-Id", "req-1")
+	if got := result.Trailer.Get("X-Rows"); got != "3" {
+		t.Errorf("trailer X-Rows = %q, want 3", got)
+	}
+	if result.Header.Get("Content-Type") != "application/proto" {
+		t.Errorf("Content-Type = %q, want application/proto", result.Header.Get("Content-Type"))
+	}
+}
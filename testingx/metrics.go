@@ -0,0 +1,227 @@
+package testingx
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// MetricsRecorder collects OpenTelemetry metrics in memory so tests can
+// assert on them without scraping a Prometheus endpoint.
+//
+// Use either MeterProvider, for code that accepts a metric.MeterProvider,
+// or Reader, to attach the recorder to an obsx provider via
+// obsx.Options.Readers. A reader can only be attached to one provider.
+type MetricsRecorder struct {
+	t      *testing.T
+	reader *sdkmetric.ManualReader
+
+	once     sync.Once
+	provider *sdkmetric.MeterProvider
+}
+
+// NewMetricsRecorder creates a metrics recorder backed by a manual reader.
+// The recorder is shut down when the test ends.
+//
+// Example:
+//
+//	recorder := testingx.NewMetricsRecorder(t)
+//	provider, _ := obsx.NewProvider(ctx, obsx.Options{
+//		ServiceName: "test",
+//		Readers:     []metric.Reader{recorder.Reader()},
+//	})
+//	// ... exercise code instrumented with provider ...
+//	got := recorder.CounterValue("rpc_requests_total", map[string]string{"rpc_method": "GetUser"})
+func NewMetricsRecorder(t *testing.T) *MetricsRecorder {
+	t.Helper()
+	r := &MetricsRecorder{t: t, reader: sdkmetric.NewManualReader()}
+	t.Cleanup(func() {
+		if r.provider != nil {
+			_ = r.provider.Shutdown(context.Background())
+		}
+	})
+	return r
+}
+
+// Reader returns the underlying reader, for attaching to a meter provider.
+func (r *MetricsRecorder) Reader() sdkmetric.Reader {
+	return r.reader
+}
+
+// MeterProvider returns a meter provider reporting to the recorder.
+func (r *MetricsRecorder) MeterProvider() *sdkmetric.MeterProvider {
+	r.once.Do(func() {
+		r.provider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(r.reader))
+	})
+	return r.provider
+}
+
+// Collect returns all metrics recorded so far.
+func (r *MetricsRecorder) Collect() metricdata.ResourceMetrics {
+	r.t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.reader.Collect(context.Background(), &rm); err != nil {
+		r.t.Fatalf("Failed to collect metrics: %v", err)
+	}
+	return rm
+}
+
+// HasMetric reports whether a metric with the given name was recorded.
+func (r *MetricsRecorder) HasMetric(name string) bool {
+	r.t.Helper()
+	_, ok := r.find(name)
+	return ok
+}
+
+// CounterValue returns the sum of all counter data points of the named
+// metric whose attributes include labels. A nil labels map matches every
+// data point. Missing metrics count as zero.
+func (r *MetricsRecorder) CounterValue(name string, labels map[string]string) float64 {
+	r.t.Helper()
+	m, ok := r.find(name)
+	if !ok {
+		return 0
+	}
+
+	var total float64
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				total += float64(dp.Value)
+			}
+		}
+	case metricdata.Sum[float64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				total += dp.Value
+			}
+		}
+	default:
+		r.t.Fatalf("Metric %s is a %T, not a counter", name, m.Data)
+	}
+	return total
+}
+
+// GaugeValue returns the sum of the last values of all gauge data points of
+// the named metric whose attributes include labels.
+func (r *MetricsRecorder) GaugeValue(name string, labels map[string]string) float64 {
+	r.t.Helper()
+	m, ok := r.find(name)
+	if !ok {
+		return 0
+	}
+
+	var total float64
+	switch data := m.Data.(type) {
+	case metricdata.Gauge[int64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				total += float64(dp.Value)
+			}
+		}
+	case metricdata.Gauge[float64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				total += dp.Value
+			}
+		}
+	default:
+		r.t.Fatalf("Metric %s is a %T, not a gauge", name, m.Data)
+	}
+	return total
+}
+
+// HistogramCount returns the number of observations of the named histogram
+// whose attributes include labels.
+func (r *MetricsRecorder) HistogramCount(name string, labels map[string]string) uint64 {
+	r.t.Helper()
+	count, _ := r.histogram(name, labels)
+	return count
+}
+
+// HistogramSum returns the sum of observations of the named histogram whose
+// attributes include labels.
+func (r *MetricsRecorder) HistogramSum(name string, labels map[string]string) float64 {
+	r.t.Helper()
+	_, sum := r.histogram(name, labels)
+	return sum
+}
+
+// AssertCounter asserts that CounterValue(name, labels) equals expected.
+func (r *MetricsRecorder) AssertCounter(name string, labels map[string]string, expected float64) {
+	r.t.Helper()
+	if got := r.CounterValue(name, labels); got != expected {
+		r.t.Errorf("Expected counter %s%v = %v, got %v", name, labels, expected, got)
+	}
+}
+
+// AssertHistogramCount asserts that HistogramCount(name, labels) equals expected.
+func (r *MetricsRecorder) AssertHistogramCount(name string, labels map[string]string, expected uint64) {
+	r.t.Helper()
+	if got := r.HistogramCount(name, labels); got != expected {
+		r.t.Errorf("Expected histogram %s%v count = %d, got %d", name, labels, expected, got)
+	}
+}
+
+// histogram returns the observation count and sum of matching data points.
+func (r *MetricsRecorder) histogram(name string, labels map[string]string) (uint64, float64) {
+	r.t.Helper()
+	m, ok := r.find(name)
+	if !ok {
+		return 0, 0
+	}
+
+	var (
+		count uint64
+		sum   float64
+	)
+	switch data := m.Data.(type) {
+	case metricdata.Histogram[int64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				count += dp.Count
+				sum += float64(dp.Sum)
+			}
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range data.DataPoints {
+			if matchLabels(dp.Attributes, labels) {
+				count += dp.Count
+				sum += dp.Sum
+			}
+		}
+	default:
+		r.t.Fatalf("Metric %s is a %T, not a histogram", name, m.Data)
+	}
+	return count, sum
+}
+
+// find returns the first metric with the given name across all scopes.
+func (r *MetricsRecorder) find(name string) (metricdata.Metrics, bool) {
+	r.t.Helper()
+	rm := r.Collect()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return metricdata.Metrics{}, false
+}
+
+// matchLabels reports whether attrs contains every label with its value.
+func matchLabels(attrs attribute.Set, labels map[string]string) bool {
+	for key, want := range labels {
+		value, ok := attrs.Value(attribute.Key(key))
+		if !ok || value.Emit() != want {
+			return false
+		}
+	}
+	return true
+}
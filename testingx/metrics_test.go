@@ -0,0 +1,94 @@
+// Package testingx provides tests for the in-memory metrics recorder.
+package testingx
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestMetricsRecorder_Counter(t *testing.T) {
+	recorder := NewMetricsRecorder(t)
+	meter := recorder.MeterProvider().Meter("test")
+	ctx := context.Background()
+
+	counter, err := meter.Int64Counter("rpc_requests_total")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("method", "GetUser"), attribute.String("code", "ok")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("method", "GetUser"), attribute.String("code", "not_found")))
+	counter.Add(ctx, 4, metric.WithAttributes(attribute.String("method", "ListUsers"), attribute.String("code", "ok")))
+
+	recorder.AssertCounter("rpc_requests_total", nil, 7)
+	recorder.AssertCounter("rpc_requests_total", map[string]string{"method": "GetUser"}, 3)
+	recorder.AssertCounter("rpc_requests_total", map[string]string{"method": "GetUser", "code": "ok"}, 2)
+	recorder.AssertCounter("rpc_requests_total", map[string]string{"method": "DeleteUser"}, 0)
+
+	if !recorder.HasMetric("rpc_requests_total") {
+		t.Error("HasMetric(rpc_requests_total) = false, want true")
+	}
+	if recorder.HasMetric("missing_total") {
+		t.Error("HasMetric(missing_total) = true, want false")
+	}
+	if got := recorder.CounterValue("missing_total", nil); got != 0 {
+		t.Errorf("CounterValue(missing_total) = %v, want 0", got)
+	}
+}
+
+func TestMetricsRecorder_FloatCounter(t *testing.T) {
+	recorder := NewMetricsRecorder(t)
+	counter, err := recorder.MeterProvider().Meter("test").Float64UpDownCounter("queue_depth")
+	if err != nil {
+		t.Fatalf("Float64UpDownCounter() error = %v", err)
+	}
+	counter.Add(context.Background(), 2.5)
+	counter.Add(context.Background(), -1)
+
+	recorder.AssertCounter("queue_depth", nil, 1.5)
+}
+
+func TestMetricsRecorder_Histogram(t *testing.T) {
+	recorder := NewMetricsRecorder(t)
+	histogram, err := recorder.MeterProvider().Meter("test").Float64Histogram("rpc_request_duration_seconds")
+	if err != nil {
+		t.Fatalf("Float64Histogram() error = %v", err)
+	}
+	ctx := context.Background()
+	histogram.Record(ctx, 0.5, metric.WithAttributes(attribute.String("method", "GetUser")))
+	histogram.Record(ctx, 1.5, metric.WithAttributes(attribute.String("method", "GetUser")))
+	histogram.Record(ctx, 0.25, metric.WithAttributes(attribute.String("method", "ListUsers")))
+
+	recorder.AssertHistogramCount("rpc_request_duration_seconds", nil, 3)
+	recorder.AssertHistogramCount("rpc_request_duration_seconds", map[string]string{"method": "GetUser"}, 2)
+	if got := recorder.HistogramSum("rpc_request_duration_seconds", map[string]string{"method": "GetUser"}); got != 2 {
+		t.Errorf("HistogramSum() = %v, want 2", got)
+	}
+}
+
+func TestMetricsRecorder_Gauge(t *testing.T) {
+	recorder := NewMetricsRecorder(t)
+	gauge, err := recorder.MeterProvider().Meter("test").Int64Gauge("db_open_connections")
+	if err != nil {
+		t.Fatalf("Int64Gauge() error = %v", err)
+	}
+	ctx := context.Background()
+	gauge.Record(ctx, 5, metric.WithAttributes(attribute.String("db_name", "main")))
+	gauge.Record(ctx, 3, metric.WithAttributes(attribute.String("db_name", "main")))
+
+	if got := recorder.GaugeValue("db_open_connections", map[string]string{"db_name": "main"}); got != 3 {
+		t.Errorf("GaugeValue() = %v, want last value 3", got)
+	}
+}
+
+func TestMetricsRecorder_Reader(t *testing.T) {
+	recorder := NewMetricsRecorder(t)
+	if recorder.Reader() == nil {
+		t.Fatal("Reader() returned nil")
+	}
+	if recorder.MeterProvider() != recorder.MeterProvider() {
+		t.Error("MeterProvider() should return the same provider")
+	}
+}
@@ -0,0 +1,128 @@
+// Package testingx provides tests for the HTTP test server builder.
+package testingx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.eggybyte.com/egg/core/identity"
+)
+
+// whoamiService answers with the caller's user ID in a response header.
+type whoamiService struct{}
+
+func (whoamiService) Call(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+	resp := connect.NewResponse(&emptypb.Empty{})
+	if user, ok := identity.UserFrom(ctx); ok {
+		resp.Header().Set("X-Whoami", user.UserID)
+	}
+	return resp, nil
+}
+
+// newWhoamiServiceHandler mirrors a generated Connect handler constructor.
+func newWhoamiServiceHandler(svc whoamiService, opts ...connect.HandlerOption) (string, http.Handler) {
+	return DefaultTestProcedure, connect.NewUnaryHandler(DefaultTestProcedure, svc.Call, opts...)
+}
+
+// whoamiClient mirrors a generated Connect client.
+type whoamiClient struct {
+	call *connect.Client[emptypb.Empty, emptypb.Empty]
+}
+
+// newWhoamiClient mirrors a generated Connect client constructor.
+func newWhoamiClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) *whoamiClient {
+	return &whoamiClient{call: connect.NewClient[emptypb.Empty, emptypb.Empty](httpClient, baseURL+DefaultTestProcedure, opts...)}
+}
+
+// headerMiddleware sets a response header on every request.
+func headerMiddleware(key, value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add(key, value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestNewTestServer_Connect(t *testing.T) {
+	srv := NewTestServer(t, TestServerOptions{
+		Interceptors:    []connect.Interceptor{identityInterceptor()},
+		ConnectHandlers: []ConnectRegistration{ConnectHandler(whoamiService{}, newWhoamiServiceHandler)},
+		Middleware:      []func(http.Handler) http.Handler{headerMiddleware("X-Frame-Options", "DENY")},
+	})
+
+	client := NewServerClient(srv, newWhoamiClient)
+	req := connect.NewRequest(&emptypb.Empty{})
+	req.Header().Set("X-User-Id", "u-1")
+	resp, err := client.call.CallUnary(context.Background(), req)
+	AssertNoError(t, err)
+
+	if got := resp.Header().Get("X-Whoami"); got != "u-1" {
+		t.Errorf("Expected interceptors to set identity u-1, got %q", got)
+	}
+	if got := resp.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("Expected middleware header DENY, got %q", got)
+	}
+}
+
+func TestNewTestServer_Handlers(t *testing.T) {
+	srv := NewTestServer(t, TestServerOptions{
+		Handlers: map[string]http.Handler{
+			"GET /healthz": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "ok")
+			}),
+		},
+		Middleware: []func(http.Handler) http.Handler{
+			headerMiddleware("X-Order", "outer"),
+			headerMiddleware("X-Order", "inner"),
+		},
+	})
+
+	resp, err := srv.Client.Get(srv.URL + "/healthz")
+	AssertNoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("Expected 200 ok, got %d %q", resp.StatusCode, body)
+	}
+	if got := resp.Header.Values("X-Order"); len(got) != 2 || got[0] != "outer" || got[1] != "inner" {
+		t.Errorf("Expected middleware order [outer inner], got %v", got)
+	}
+}
+
+func TestNewTestServer_NoRetries(t *testing.T) {
+	calls := 0
+	srv := NewTestServer(t, TestServerOptions{
+		Handlers: map[string]http.Handler{
+			"/": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}),
+		},
+	})
+
+	resp, err := srv.Client.Get(srv.URL + "/")
+	AssertNoError(t, err)
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call without retries, got %d", calls)
+	}
+}
+
+func TestNewTestServer_Cleanup(t *testing.T) {
+	var srv *TestServer
+	t.Run("server", func(t *testing.T) {
+		srv = NewTestServer(t, TestServerOptions{})
+	})
+
+	if _, err := http.Get(srv.URL); err == nil {
+		t.Error("Expected server to be closed after the test ended")
+	}
+}
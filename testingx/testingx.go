@@ -16,6 +16,9 @@ package testingx
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
@@ -35,10 +38,49 @@ type MockLogger struct {
 type LogEntry struct {
 	Level   string
 	Message string
-	Fields  []any
+	Fields  []any // Key-value pairs as passed to the logger
 	Error   error
 }
 
+// Attrs returns the entry's fields keyed by name. Pairs created with
+// helpers such as log.Str are expanded; a trailing key without a value is
+// dropped, and a repeated key keeps its last value.
+func (e LogEntry) Attrs() map[string]any {
+	flat := make([]any, 0, len(e.Fields))
+	for _, item := range e.Fields {
+		if pair, ok := item.([]any); ok && len(pair) == 2 {
+			flat = append(flat, pair[0], pair[1])
+			continue
+		}
+		flat = append(flat, item)
+	}
+
+	attrs := make(map[string]any, len(flat)/2)
+	for i := 0; i+1 < len(flat); i += 2 {
+		attrs[fmt.Sprint(flat[i])] = flat[i+1]
+	}
+	return attrs
+}
+
+// matches reports whether the entry has the given level, a message
+// containing msg, and every field in fields with an equal value.
+func (e LogEntry) matches(level, msg string, fields map[string]any) bool {
+	if e.Level != level || !strings.Contains(e.Message, msg) {
+		return false
+	}
+	if len(fields) == 0 {
+		return true
+	}
+	attrs := e.Attrs()
+	for key, want := range fields {
+		got, ok := attrs[key]
+		if !ok || !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
 // NewMockLogger creates a new mock logger.
 func NewMockLogger(t *testing.T) *MockLogger {
 	return &MockLogger{
@@ -93,16 +135,35 @@ func (m *MockLogger) Entries() []LogEntry {
 	return entries
 }
 
-// AssertLogged asserts that a message was logged.
-func (m *MockLogger) AssertLogged(level, msg string) {
+// AssertLogged asserts that an entry was logged at level with a message
+// containing msgSubstring and at least the given fields. Field values are
+// compared with reflect.DeepEqual, so types must match (int vs int64).
+// A nil fields map matches any fields.
+//
+// Example:
+//
+//	logger.AssertLogged("ERROR", "failed to load user", map[string]any{"user_id": "u-1"})
+func (m *MockLogger) AssertLogged(level, msgSubstring string, fields map[string]any) {
+	m.t.Helper()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	for _, entry := range m.entries {
-		if entry.Level == level && entry.Message == msg {
+		if entry.matches(level, msgSubstring, fields) {
 			return
 		}
 	}
-	m.t.Errorf("Expected log message not found: level=%s msg=%q", level, msg)
+
+	var seen strings.Builder
+	for _, entry := range m.entries {
+		if entry.Level == level {
+			fmt.Fprintf(&seen, "\n\t%s %q %v", entry.Level, entry.Message, entry.Attrs())
+		}
+	}
+	if seen.Len() == 0 {
+		seen.WriteString(" none")
+	}
+	m.t.Errorf("Expected log message not found: level=%s msg=%q fields=%v; logged at %s:%s",
+		level, msgSubstring, fields, level, seen.String())
 }
 
 // Clear clears all log entries.
@@ -8,6 +8,7 @@ import (
 
 	"go.eggybyte.com/egg/core/errors"
 	"go.eggybyte.com/egg/core/identity"
+	"go.eggybyte.com/egg/core/log"
 )
 
 func TestNewMockLogger(t *testing.T) {
@@ -145,6 +146,7 @@ func TestMockLogger_AssertLogged(t *testing.T) {
 		logFunc    func(*MockLogger)
 		level      string
 		message    string
+		fields     map[string]any
 		shouldPass bool
 	}{
 		{
@@ -201,6 +203,55 @@ func TestMockLogger_AssertLogged(t *testing.T) {
 			message:    "test message",
 			shouldPass: false,
 		},
+		{
+			name: "message substring",
+			logFunc: func(l *MockLogger) {
+				l.Error(nil, "failed to load user profile")
+			},
+			level:      "ERROR",
+			message:    "load user",
+			shouldPass: true,
+		},
+		{
+			name: "fields match",
+			logFunc: func(l *MockLogger) {
+				l.Error(nil, "failed to load user", "user_id", "u-1", log.Int("attempt", 2))
+			},
+			level:      "ERROR",
+			message:    "failed",
+			fields:     map[string]any{"user_id": "u-1", "attempt": 2},
+			shouldPass: true,
+		},
+		{
+			name: "field value mismatch",
+			logFunc: func(l *MockLogger) {
+				l.Error(nil, "failed to load user", "user_id", "u-2")
+			},
+			level:      "ERROR",
+			message:    "failed",
+			fields:     map[string]any{"user_id": "u-1"},
+			shouldPass: false,
+		},
+		{
+			name: "field missing",
+			logFunc: func(l *MockLogger) {
+				l.Error(nil, "failed to load user")
+			},
+			level:      "ERROR",
+			message:    "failed",
+			fields:     map[string]any{"user_id": "u-1"},
+			shouldPass: false,
+		},
+		{
+			name: "field type mismatch",
+			logFunc: func(l *MockLogger) {
+				l.Info("retrying", log.Int64("attempt", 2))
+			},
+			level:      "INFO",
+			message:    "retrying",
+			fields:     map[string]any{"attempt": 2},
+			shouldPass: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -215,7 +266,7 @@ func TestMockLogger_AssertLogged(t *testing.T) {
 				entries: logger.entries,
 			}
 
-			mockLogger.AssertLogged(tt.level, tt.message)
+			mockLogger.AssertLogged(tt.level, tt.message, tt.fields)
 
 			if tt.shouldPass && mockT.Failed() {
 				t.Error("AssertLogged should pass but it failed")
@@ -227,6 +278,22 @@ func TestMockLogger_AssertLogged(t *testing.T) {
 	}
 }
 
+func TestLogEntry_Attrs(t *testing.T) {
+	logger := NewMockLogger(t)
+	logger.Info("request done", "method", "GET", log.Int("status", 200), "dangling")
+
+	attrs := logger.Entries()[0].Attrs()
+	if len(attrs) != 2 {
+		t.Fatalf("Expected 2 attrs, got %v", attrs)
+	}
+	if attrs["method"] != "GET" {
+		t.Errorf("Expected method GET, got %v", attrs["method"])
+	}
+	if attrs["status"] != 200 {
+		t.Errorf("Expected status 200, got %v", attrs["status"])
+	}
+}
+
 func TestMockLogger_Clear(t *testing.T) {
 	logger := NewMockLogger(t)
 